@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"admin_backend/internal/infra/config"
+	"admin_backend/internal/infra/db"
+	"admin_backend/internal/infra/repository/postgres"
+	"admin_backend/internal/infra/zipcode"
+)
+
+const importZipCodesUsage = `usage: admin_backend import-ceps <file.csv | ->
+
+Imports a CEP dataset so zip code lookups work when every online provider is
+down. The first line names the columns, separated by commas or semicolons:
+cep is required; logradouro, complemento, bairro, cidade (or localidade),
+uf, ibge, gia, ddd and siafi are read when present, as are their English
+names. Imported zip codes never expire and are replaced by a new import.
+Run migrate up first.
+`
+
+// importZipCodes runs the import-ceps subcommand; args are the words after
+// it. "-" reads the dataset from stdin.
+func importZipCodes(ctx context.Context, cfg config.Config, args []string, stdout io.Writer) error {
+	if len(args) != 1 || args[0] == "-h" || args[0] == "--help" {
+		fmt.Fprint(stdout, importZipCodesUsage)
+		return errors.New("invalid import-ceps command")
+	}
+
+	var input io.Reader = os.Stdin
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	database, err := db.Connect(ctx, cfg.DB)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	summary, err := zipcode.ImportDataset(ctx, postgres.NewZipCodeDirectoryRepository(database), input)
+	fmt.Fprintf(stdout, "imported %d zip codes, skipped %d rows with an invalid cep\n", summary.Imported, summary.Skipped)
+	return err
+}
@@ -1,36 +1,63 @@
 package main
 
 import (
-	"log"
-	"net/http"
+	"context"
+	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"admin_backend/internal/app"
+	"admin_backend/internal/dbmigrate"
+	"admin_backend/internal/infra/config"
+	"admin_backend/internal/infra/httpserver"
+	"admin_backend/internal/infra/logging"
 )
 
 func main() {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	cfg, err := config.Load("admin_backend")
+	logger := logging.New(cfg.Log, "admin_backend")
+	slog.SetDefault(logger)
+	if err != nil {
+		logger.Error("startup error", "error", err)
+		os.Exit(1)
 	}
 
-	application, err := app.New()
-	if err != nil {
-		log.Fatalf("startup error: %v", err)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := dbmigrate.Run(ctx, "admin_backend", cfg.DB, os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "migrate:", err)
+			stop()
+			os.Exit(1)
+		}
+		return
 	}
-	defer func() {
-		if err := application.Close(); err != nil {
-			log.Printf("shutdown error: %v", err)
+
+	if len(os.Args) > 1 && os.Args[1] == "import-ceps" {
+		if err := importZipCodes(ctx, cfg, os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "import-ceps:", err)
+			stop()
+			os.Exit(1)
 		}
-	}()
+		return
+	}
+
+	cfg.LogSummary(ctx, logger)
 
-	server := &http.Server{
-		Addr:    ":" + port,
-		Handler: application.Handler,
+	application, err := app.New(ctx, cfg, logger)
+	if err != nil {
+		logger.Error("startup error", "error", err)
+		os.Exit(1)
 	}
 
-	log.Printf("admin_backend listening on :%s", port)
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("server error: %v", err)
+	server := httpserver.New(":"+cfg.Port, application.Handler, cfg.Server, logger)
+
+	logger.Info("admin_backend listening", "port", cfg.Port)
+	if err := httpserver.Run(ctx, logger, server, cfg.Server, application); err != nil {
+		logger.Error("server error", "error", err)
+		os.Exit(1)
 	}
 }
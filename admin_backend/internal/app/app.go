@@ -2,18 +2,34 @@ package app
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
-	"time"
 
 	"admin_backend/internal/infra/auth"
 	"admin_backend/internal/infra/clock"
+	"admin_backend/internal/infra/cnpj"
+	"admin_backend/internal/infra/config"
 	"admin_backend/internal/infra/db"
+	"admin_backend/internal/infra/geocoding"
+	"admin_backend/internal/infra/health"
 	"admin_backend/internal/infra/id"
 	"admin_backend/internal/infra/localstack"
-	"admin_backend/internal/infra/repository/memory"
+	"admin_backend/internal/infra/mailer"
+	"admin_backend/internal/infra/metrics"
+	"admin_backend/internal/infra/payments"
+	"admin_backend/internal/infra/qrcode"
+	infraratelimit "admin_backend/internal/infra/ratelimit"
+	"admin_backend/internal/infra/realtime"
 	"admin_backend/internal/infra/repository/postgres"
+	"admin_backend/internal/infra/scheduler"
+	"admin_backend/internal/infra/tracing"
+	"admin_backend/internal/infra/webhooks"
 	"admin_backend/internal/infra/zipcode"
 	apphttp "admin_backend/internal/interfaces/http"
+	"admin_backend/internal/interfaces/http/probes"
+	"admin_backend/internal/interfaces/http/ratelimit"
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 	"github.com/jmoiron/sqlx"
 )
@@ -22,12 +38,32 @@ type App struct {
 	Handler    http.Handler
 	DB         *sqlx.DB
 	Localstack *localstack.Client
+	Scheduler  *scheduler.Scheduler
+	Realtime   *realtime.Hub
+	Tracer     *tracing.Tracer
+	Health     *health.Checker
+	RateLimits infraratelimit.Store
 }
 
-func New() (*App, error) {
-	ctx := context.Background()
+// New connects to the dependencies and wires the API. ctx only bounds
+// startup, e.g. the database connection retries. Settings only the use cases
+// can parse are checked first, so an invalid one fails before connecting.
+func New(ctx context.Context, cfg config.Config, logger *slog.Logger) (*App, error) {
+	schedulerConfig := cfg.Scheduler
+	reminderOffsets, err := usecase.ParsePaymentReminderOffsets(schedulerConfig.PaymentReminderOffsets)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PAYMENT_REMINDER_OFFSETS: %w", err)
+	}
+	businessCalendar, err := usecase.ParseBusinessCalendar(
+		schedulerConfig.SLABusinessHours,
+		schedulerConfig.SLABusinessDays,
+		schedulerConfig.SLATimezone,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SLA_BUSINESS_HOURS/SLA_BUSINESS_DAYS/SLA_TIMEZONE: %w", err)
+	}
 
-	dbConfig := db.FromEnv()
+	dbConfig := cfg.DB
 	if err := db.EnsureDatabase(ctx, dbConfig); err != nil {
 		return nil, err
 	}
@@ -35,27 +71,65 @@ func New() (*App, error) {
 	if err != nil {
 		return nil, err
 	}
-	if err := db.Migrate(ctx, database, db.Migrations); err != nil {
+	if dbConfig.AutoMigrate {
+		if err := db.Migrate(ctx, database, db.Migrations); err != nil {
+			_ = database.Close()
+			return nil, err
+		}
+	} else {
+		logger.InfoContext(ctx, "automatic migrations disabled; /readyz reports pending migrations")
+	}
+
+	localstackClient, err := localstack.New(cfg.Localstack)
+	if err != nil {
 		_ = database.Close()
 		return nil, err
 	}
 
-	localstackConfig := localstack.FromEnv()
-	localstackClient, err := localstack.New(localstackConfig)
+	paymentProvider, err := payments.New(cfg.Payments)
 	if err != nil {
 		_ = database.Close()
 		return nil, err
 	}
-	if err := localstackClient.HealthCheck(ctx); err != nil {
+
+	companyLookup, err := cnpj.New(cfg.CompanyLookup)
+	if err != nil {
+		_ = database.Close()
+		return nil, err
+	}
+
+	zipCodeLookup, err := zipcode.New(cfg.ZipCode, postgres.NewZipCodeDirectoryRepository(database))
+	if err != nil {
 		_ = database.Close()
 		return nil, err
 	}
 
-	userRepo := memory.NewUserRepository()
+	geocoder, err := geocoding.New(cfg.Geocoding)
+	if err != nil {
+		_ = database.Close()
+		return nil, err
+	}
+
+	emailSender, err := mailer.New(cfg.Mail)
+	if err != nil {
+		_ = database.Close()
+		return nil, err
+	}
+
+	realtimeHub, err := realtime.NewHub(dbConfig.DSN())
+	if err != nil {
+		_ = database.Close()
+		return nil, err
+	}
+
+	tracer := tracing.New(cfg.Tracing, "admin_backend")
+	tracing.SetDefault(tracer)
+	usecase.SetTracer(tracing.UsecaseTracer{})
+
 	ids := id.New()
 	clockProvider := clock.New()
-	tokenManager := auth.NewTokenManager(auth.FromEnv())
-	zipCodeLookup := zipcode.NewViaCEPClient(8 * time.Second)
+	tokenManager := auth.NewTokenManager(cfg.Auth)
+	userRepo := postgres.NewUserRepository(database)
 	clientRepo := postgres.NewClientRepository(database)
 	clientPortalRepo := postgres.NewClientPortalRepository(database)
 	authRepo := postgres.NewAuthRepository(database)
@@ -63,15 +137,53 @@ func New() (*App, error) {
 	userProfileRepo := postgres.NewUserProfileRepository(database)
 	securityRepo := postgres.NewSecurityRepository(database)
 	projectRepo := postgres.NewProjectRepository(database)
+	invoiceRepo := postgres.NewInvoiceRepository(database)
+	paymentRepo := postgres.NewPaymentRepository(database)
+	bankStatementRepo := postgres.NewBankStatementRepository(database)
+	reminderRepo := postgres.NewPaymentReminderRepository(database)
+	notificationRepo := postgres.NewNotificationRepository(database)
+	webhookRepo := postgres.NewWebhookRepository(database)
+	inboundMailRepo := postgres.NewInboundMailRepository(database)
+	slaRepo := postgres.NewSLARepository(database)
+	serviceRequestSettingsRepo := postgres.NewServiceRequestSettingsRepository(database)
+	surveyRepo := postgres.NewSurveyRepository(database)
+	businessMetricsRepo := postgres.NewBusinessMetricsRepository(database)
 
-	userService := usecase.NewUserService(userRepo, ids, clockProvider)
-	clientService := usecase.NewClientService(clientRepo, zipCodeLookup)
+	notificationService := usecase.NewNotificationService(notificationRepo)
+	userService := usecase.NewUserService(userRepo)
+	addressGeocodingService := usecase.NewAddressGeocodingService(clientRepo, geocoder, clockProvider)
+	clientService := usecase.NewClientService(clientRepo, zipCodeLookup, companyLookup, addressGeocodingService)
 	authService := usecase.NewAuthService(authRepo)
 	authorizationService := usecase.NewAuthorizationService(authorizationRepo)
 	userProfileService := usecase.NewUserProfileService(userProfileRepo)
 	securityService := usecase.NewSecurityService(securityRepo)
-	projectService := usecase.NewProjectService(projectRepo)
-	clientPortalService := usecase.NewClientPortalService(clientPortalRepo)
+	projectService := usecase.NewProjectService(projectRepo, notificationService)
+	slaService := usecase.NewSLAService(slaRepo, notificationService, clockProvider, businessCalendar)
+	clientPortalService := usecase.NewClientPortalService(clientPortalRepo, notificationService, slaService)
+	serviceRequestSettingsService := usecase.NewServiceRequestSettingsService(serviceRequestSettingsRepo)
+	serviceRequestConversionService := usecase.NewServiceRequestConversionService(
+		clientPortalRepo,
+		projectRepo,
+		notificationService,
+		slaService,
+	)
+	surveyService := usecase.NewSurveyService(surveyRepo, clockProvider)
+	businessMetricsService := usecase.NewBusinessMetricsService(businessMetricsRepo)
+	invoiceService := usecase.NewInvoiceService(invoiceRepo, projectRepo, clientRepo)
+	paymentService := usecase.NewPaymentService(paymentRepo, projectRepo, paymentProvider, qrcode.NewRenderer(0))
+	bankStatementService := usecase.NewBankStatementService(bankStatementRepo)
+	reminderService := usecase.NewPaymentReminderService(reminderRepo, emailSender, clockProvider, reminderOffsets)
+	webhookService := usecase.NewWebhookService(webhookRepo, webhooks.NewHTTPSender(cfg.WebhookTimeout), ids, clockProvider)
+	inboundMailService := usecase.NewInboundMailService(
+		inboundMailRepo,
+		clientPortalService,
+		emailSender,
+		localstackClient,
+		usecase.InboundMailConfig{
+			Address: cfg.Mail.InboundAddress,
+			Secret:  cfg.Mail.InboundSecret,
+		},
+	)
 	userHandler := apphttp.NewUserHandler(
 		userService,
 		clientService,
@@ -81,24 +193,180 @@ func New() (*App, error) {
 		securityService,
 		projectService,
 		clientPortalService,
-		database,
+		invoiceService,
+		paymentService,
+		bankStatementService,
+		reminderService,
+		notificationService,
+		webhookService,
+		inboundMailService,
+		slaService,
+		serviceRequestSettingsService,
+		serviceRequestConversionService,
+		surveyService,
+		realtimeHub,
 		tokenManager,
 	)
 
+	metrics.RegisterDBStats(metrics.Default, database.DB)
+	metrics.RegisterBusinessMetrics(metrics.Default, businessMetricsService)
+
+	healthChecker := health.NewChecker(
+		health.Database(database),
+		health.Migrations(database, db.Migrations),
+		health.Check{Name: "objectStorage", Run: localstackClient.Ping},
+	)
+
+	rateLimitStore, err := infraratelimit.New(cfg.RateLimit, database)
+	if err != nil {
+		_ = realtimeHub.Close()
+		_ = database.Close()
+		return nil, err
+	}
+
 	mux := http.NewServeMux()
 	userHandler.RegisterRoutes(mux)
-	handler := apphttp.WithCORS(mux)
+	probes.NewHandler(healthChecker).RegisterRoutes(mux)
+	mux.Handle("/metrics", metrics.Default.Handler())
+
+	// Limits sit inside CORS so browsers can read the 429 responses.
+	var routes http.Handler = mux
+	if cfg.RateLimit.Enabled {
+		routes = ratelimit.New(
+			rateLimitStore,
+			tokenManager,
+			cfg.RateLimit.TrustedProxies,
+			ratelimit.Policy{
+				Name:    "login",
+				Paths:   []string{"/auth/login"},
+				Methods: []string{http.MethodPost},
+				Key:     ratelimit.ByIP,
+				Limit:   cfg.RateLimit.Login,
+			},
+			ratelimit.Policy{
+				Name:    "lookups",
+				Paths:   []string{"/utils/cep/", "/utils/cnpj/"},
+				Methods: []string{http.MethodGet},
+				Key:     ratelimit.ByUser,
+				Limit:   cfg.RateLimit.Lookups,
+			},
+		).Middleware(mux)
+	}
+	handler := requestlog.Middleware(logger, mux, apphttp.WithCORS(cfg.CORSOrigins, routes))
+
+	var jobs []scheduler.Job
+	if schedulerConfig.PaymentRemindersEnabled {
+		jobs = append(jobs, scheduler.Job{
+			Name:     "payment-reminders",
+			Interval: schedulerConfig.PaymentReminderInterval,
+			Run: func(ctx context.Context) error {
+				result, err := reminderService.SendPaymentReminders(ctx)
+				if result.Sent > 0 || result.Failed > 0 {
+					logger.InfoContext(
+						ctx,
+						"payment reminders",
+						"sent", result.Sent,
+						"skipped", result.Skipped,
+						"failed", result.Failed,
+					)
+				}
+				return err
+			},
+		})
+	}
+	if schedulerConfig.WebhooksEnabled {
+		jobs = append(jobs, scheduler.Job{
+			Name:     "webhooks",
+			Interval: schedulerConfig.WebhookDispatchInterval,
+			Run: func(ctx context.Context) error {
+				result, err := webhookService.DispatchWebhooks(ctx)
+				if result.Delivered > 0 || result.Retrying > 0 || result.Failed > 0 {
+					logger.InfoContext(
+						ctx,
+						"webhooks",
+						"delivered", result.Delivered,
+						"retrying", result.Retrying,
+						"failed", result.Failed,
+					)
+				}
+				return err
+			},
+		})
+	}
+
+	if schedulerConfig.SLAEscalationEnabled {
+		jobs = append(jobs, scheduler.Job{
+			Name:     "sla-escalation",
+			Interval: schedulerConfig.SLAEscalationInterval,
+			Run: func(ctx context.Context) error {
+				result, err := slaService.EscalateServiceRequests(ctx)
+				if result.Warned > 0 || result.Breached > 0 {
+					logger.InfoContext(ctx, "sla escalation", "warned", result.Warned, "breached", result.Breached)
+				}
+				return err
+			},
+		})
+	}
+
+	if schedulerConfig.AddressGeocodingEnabled {
+		jobs = append(jobs, scheduler.Job{
+			Name:     "address-geocoding",
+			Interval: schedulerConfig.AddressGeocodingInterval,
+			Wake:     addressGeocodingService.Requested(),
+			Run: func(ctx context.Context) error {
+				result, err := addressGeocodingService.GeocodePendingAddresses(ctx)
+				if result.Located > 0 || result.NotFound > 0 || result.Retrying > 0 || result.Failed > 0 {
+					logger.InfoContext(
+						ctx,
+						"address geocoding",
+						"located", result.Located,
+						"not_found", result.NotFound,
+						"retrying", result.Retrying,
+						"failed", result.Failed,
+					)
+				}
+				return err
+			},
+		})
+	}
 
 	return &App{
 		Handler:    handler,
 		DB:         database,
 		Localstack: localstackClient,
+		Scheduler:  scheduler.Start(jobs...),
+		Realtime:   realtimeHub,
+		Tracer:     tracer,
+		Health:     healthChecker,
+		RateLimits: rateLimitStore,
 	}, nil
 }
 
-func (a *App) Close() error {
+// Drain marks the app not ready and ends the open event streams.
+func (a *App) Drain() {
+	a.Health.SetDraining()
+	if a.Realtime != nil {
+		_ = a.Realtime.Close()
+	}
+}
+
+// Shutdown waits for running scheduled jobs, flushes traces and closes the
+// database, giving up on the jobs when ctx expires.
+func (a *App) Shutdown(ctx context.Context) error {
+	err := a.Scheduler.Shutdown(ctx)
+	if a.Realtime != nil {
+		_ = a.Realtime.Close()
+	}
+	if a.Tracer != nil {
+		_ = a.Tracer.Close(ctx)
+	}
+	if a.RateLimits != nil {
+		_ = a.RateLimits.Close()
+	}
 	if a.DB != nil {
-		return a.DB.Close()
+		if closeErr := a.DB.Close(); err == nil {
+			err = closeErr
+		}
 	}
-	return nil
+	return err
 }
@@ -0,0 +1,216 @@
+// Package dbmigrate implements the "migrate" subcommand shared by
+// admin_backend and client_backend. Both binaries embed the same migrations,
+// owned by admin_backend, and connect with the same POSTGRES_* settings.
+package dbmigrate
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"admin_backend/internal/infra/db"
+)
+
+const usage = `usage: %s migrate <command>
+
+commands:
+  up                   apply every pending migration
+  down [N]             roll back the last N applied migrations (default 1)
+  redo                 roll back the last applied migration and apply it again
+  status               list migrations, marking pending, modified and missing ones
+  dry-run [up|down N|redo]
+                       print what the command would do without changing the schema
+
+Runs hold a Postgres advisory lock, so they never overlap with another
+replica migrating at boot. Applied files whose checksum changed stop up, down
+and redo. A migration can only be rolled back when NAME.down.sql exists next
+to NAME.sql.
+`
+
+var errUsage = errors.New("invalid migrate command")
+
+// Run executes the migrate subcommand against the database of config; args
+// are the words after "migrate".
+func Run(ctx context.Context, program string, config db.Config, args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	flags.Usage = func() { fmt.Fprintf(stdout, usage, program) }
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+	args = flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	command, dryRun := args[0], false
+	if command == "dry-run" {
+		dryRun = true
+		args = args[1:]
+		command = "up"
+		if len(args) > 0 {
+			command = args[0]
+		}
+	}
+
+	steps := 1
+	switch command {
+	case "up", "redo", "status":
+		if len(args) > 1 {
+			flags.Usage()
+			return errUsage
+		}
+	case "down":
+		if len(args) > 2 {
+			flags.Usage()
+			return errUsage
+		}
+		if len(args) == 2 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil || parsed < 1 {
+				return fmt.Errorf("down expects a positive number of steps, got %q", args[1])
+			}
+			steps = parsed
+		}
+	default:
+		flags.Usage()
+		return errUsage
+	}
+	if dryRun && command == "status" {
+		flags.Usage()
+		return errUsage
+	}
+
+	if command == "up" && !dryRun {
+		if err := db.EnsureDatabase(ctx, config); err != nil {
+			return err
+		}
+	}
+	database, err := db.Connect(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	migrator, err := db.NewMigrator(database, db.Migrations)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case command == "status":
+		return printStatus(ctx, migrator, stdout)
+	case dryRun:
+		return printPlan(ctx, migrator, command, steps, stdout)
+	case command == "up":
+		applied, err := migrator.Up(ctx)
+		printVersions(stdout, "applied", applied, err)
+		return err
+	case command == "down":
+		rolledBack, err := migrator.Down(ctx, steps)
+		printVersions(stdout, "rolled back", rolledBack, err)
+		return err
+	default:
+		version, err := migrator.Redo(ctx)
+		if version != "" {
+			fmt.Fprintf(stdout, "redone %s\n", version)
+		} else if err == nil {
+			fmt.Fprintln(stdout, "nothing to redo")
+		}
+		return err
+	}
+}
+
+func printStatus(ctx context.Context, migrator *db.Migrator, stdout io.Writer) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "VERSION\tSTATE\tAPPLIED AT\tDOWN")
+	pending := 0
+	for _, status := range statuses {
+		state := "pending"
+		switch {
+		case status.Missing:
+			state = "missing file"
+		case status.Modified:
+			state = "modified"
+		case status.Applied:
+			state = "applied"
+		default:
+			pending++
+		}
+
+		appliedAt := "-"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.UTC().Format("2006-01-02 15:04:05Z")
+		}
+		down := "no"
+		if status.HasDown {
+			down = "yes"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", status.Version, state, appliedAt, down)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%d migrations, %d pending\n", len(statuses), pending)
+	return nil
+}
+
+func printPlan(ctx context.Context, migrator *db.Migrator, command string, steps int, stdout io.Writer) error {
+	var (
+		planned []db.Migration
+		err     error
+	)
+	switch command {
+	case "up":
+		planned, err = migrator.PlanUp(ctx)
+	case "down":
+		planned, err = migrator.PlanDown(ctx, steps)
+	case "redo":
+		planned, err = migrator.PlanDown(ctx, 1)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(planned) == 0 {
+		fmt.Fprintf(stdout, "dry run: %s would change nothing\n", command)
+		return nil
+	}
+	for _, migration := range planned {
+		switch command {
+		case "up":
+			fmt.Fprintf(stdout, "would apply %s (sha256 %s)\n", migration.Version, migration.Checksum)
+		case "down":
+			fmt.Fprintf(stdout, "would roll back %s\n", migration.Version)
+		case "redo":
+			fmt.Fprintf(stdout, "would roll back and apply again %s\n", migration.Version)
+		}
+	}
+	return nil
+}
+
+// printVersions lists what a run completed, including the steps done before
+// it failed with err.
+func printVersions(stdout io.Writer, verb string, versions []string, err error) {
+	if len(versions) == 0 && err == nil {
+		fmt.Fprintf(stdout, "nothing %s\n", verb)
+		return
+	}
+	for _, version := range versions {
+		fmt.Fprintf(stdout, "%s %s\n", verb, version)
+	}
+}
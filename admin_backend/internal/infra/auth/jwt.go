@@ -15,19 +15,28 @@ var uuidPattern = regexp.MustCompile(
 	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`,
 )
 
+// Tokens carry the surface they were issued for, so a portal token is
+// refused by the admin API and vice versa even though both share a secret.
+const (
+	AudienceAdmin  = "admin"
+	AudienceClient = "client"
+)
+
 type TokenManager struct {
 	secret    []byte
 	issuer    string
+	audience  string
 	expiresIn time.Duration
 }
 
 type Claims struct {
-	Issuer string `json:"iss"`
-	Sub    string `json:"sub"`
-	Login  string `json:"login"`
-	Name   string `json:"name"`
-	Iat    int64  `json:"iat"`
-	Exp    int64  `json:"exp"`
+	Issuer   string `json:"iss"`
+	Audience string `json:"aud"`
+	Sub      string `json:"sub"`
+	Login    string `json:"login"`
+	Name     string `json:"name"`
+	Iat      int64  `json:"iat"`
+	Exp      int64  `json:"exp"`
 }
 
 func NewTokenManager(cfg Config) *TokenManager {
@@ -44,10 +53,19 @@ func NewTokenManager(cfg Config) *TokenManager {
 	return &TokenManager{
 		secret:    []byte(secret),
 		issuer:    cfg.Issuer,
+		audience:  AudienceAdmin,
 		expiresIn: expiresIn,
 	}
 }
 
+// WithAudience returns a manager sharing the same secret and issuer that
+// issues and accepts tokens for another audience.
+func (m *TokenManager) WithAudience(audience string) *TokenManager {
+	copied := *m
+	copied.audience = audience
+	return &copied
+}
+
 func (m *TokenManager) Generate(userID, login, name string, now time.Time) (string, time.Time, error) {
 	type header struct {
 		Alg string `json:"alg"`
@@ -66,12 +84,13 @@ func (m *TokenManager) Generate(userID, login, name string, now time.Time) (stri
 	}
 
 	claimsJSON, err := json.Marshal(Claims{
-		Issuer: m.issuer,
-		Sub:    userID,
-		Login:  login,
-		Name:   name,
-		Iat:    now.Unix(),
-		Exp:    expiresAt.Unix(),
+		Issuer:   m.issuer,
+		Audience: m.audience,
+		Sub:      userID,
+		Login:    login,
+		Name:     name,
+		Iat:      now.Unix(),
+		Exp:      expiresAt.Unix(),
 	})
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("marshal jwt claims: %w", err)
@@ -127,6 +146,9 @@ func (m *TokenManager) ParseAndValidate(token string, now time.Time) (Claims, er
 	if claims.Issuer != m.issuer {
 		return Claims{}, fmt.Errorf("invalid jwt issuer")
 	}
+	if claims.Audience != m.audience {
+		return Claims{}, fmt.Errorf("invalid jwt audience")
+	}
 	if claims.Exp <= 0 || now.Unix() >= claims.Exp {
 		return Claims{}, fmt.Errorf("jwt token expired")
 	}
@@ -0,0 +1,106 @@
+package cnpj
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"admin_backend/internal/usecase"
+)
+
+var nonDigitPattern = regexp.MustCompile(`\D`)
+
+type BrasilAPIClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewBrasilAPIClient(baseURL string, timeout time.Duration) *BrasilAPIClient {
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = "https://brasilapi.com.br/api/cnpj/v1"
+	}
+	if timeout <= 0 {
+		timeout = 8 * time.Second
+	}
+
+	return &BrasilAPIClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+func (c *BrasilAPIClient) LookupCompany(ctx context.Context, cnpj string) (usecase.CompanyLookupResult, error) {
+	requestURL := fmt.Sprintf("%s/%s", strings.TrimSuffix(c.baseURL, "/"), cnpj)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return usecase.CompanyLookupResult{}, usecase.ErrCompanyLookupUnavailable
+	}
+	req.Header.Set("Accept", "application/json")
+
+	response, err := c.httpClient.Do(req)
+	if err != nil {
+		return usecase.CompanyLookupResult{}, usecase.ErrCompanyLookupUnavailable
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return usecase.CompanyLookupResult{}, usecase.ErrCompanyNotFound
+	default:
+		return usecase.CompanyLookupResult{}, usecase.ErrCompanyLookupUnavailable
+	}
+
+	var payload struct {
+		CNPJ         string `json:"cnpj"`
+		LegalName    string `json:"razao_social"`
+		TradeName    string `json:"nome_fantasia"`
+		Status       string `json:"descricao_situacao_cadastral"`
+		Email        string `json:"email"`
+		Phone        string `json:"ddd_telefone_1"`
+		ZipCode      string `json:"cep"`
+		StreetType   string `json:"descricao_tipo_de_logradouro"`
+		StreetName   string `json:"logradouro"`
+		Number       string `json:"numero"`
+		Neighborhood string `json:"bairro"`
+		City         string `json:"municipio"`
+		State        string `json:"uf"`
+		Complement   string `json:"complemento"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&payload); err != nil {
+		return usecase.CompanyLookupResult{}, usecase.ErrCompanyLookupUnavailable
+	}
+
+	streetType := strings.TrimSpace(payload.StreetType)
+	streetName := strings.TrimSpace(payload.StreetName)
+
+	return usecase.CompanyLookupResult{
+		TaxID:     onlyDigits(payload.CNPJ),
+		LegalName: strings.TrimSpace(payload.LegalName),
+		TradeName: strings.TrimSpace(payload.TradeName),
+		Status:    strings.TrimSpace(payload.Status),
+		Email:     strings.ToLower(strings.TrimSpace(payload.Email)),
+		Phone:     onlyDigits(payload.Phone),
+		Address: usecase.CompanyAddress{
+			ZipCode:      onlyDigits(payload.ZipCode),
+			StreetType:   strings.ToLower(streetType),
+			StreetName:   streetName,
+			Street:       strings.TrimSpace(strings.Join([]string{streetType, streetName}, " ")),
+			Number:       strings.TrimSpace(payload.Number),
+			Neighborhood: strings.TrimSpace(payload.Neighborhood),
+			City:         strings.TrimSpace(payload.City),
+			State:        strings.TrimSpace(payload.State),
+			Complement:   strings.TrimSpace(payload.Complement),
+		},
+	}, nil
+}
+
+func onlyDigits(value string) string {
+	return nonDigitPattern.ReplaceAllString(strings.TrimSpace(value), "")
+}
@@ -0,0 +1,30 @@
+package cnpj
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"admin_backend/internal/usecase"
+)
+
+const BrasilAPIProviderName = "brasilapi"
+
+type Config struct {
+	Provider string
+	BaseURL  string
+	Timeout  time.Duration
+}
+
+// New builds the CNPJ lookup selected by COMPANY_LOOKUP_PROVIDER. The fake
+// provider is meant for local development and tests.
+func New(config Config) (usecase.CompanyLookup, error) {
+	switch strings.ToLower(strings.TrimSpace(config.Provider)) {
+	case "", BrasilAPIProviderName:
+		return NewBrasilAPIClient(config.BaseURL, config.Timeout), nil
+	case FakeProviderName:
+		return NewFakeClient(), nil
+	default:
+		return nil, fmt.Errorf("unsupported company lookup provider %q", config.Provider)
+	}
+}
@@ -0,0 +1,46 @@
+package cnpj
+
+import (
+	"context"
+	"fmt"
+
+	"admin_backend/internal/usecase"
+)
+
+const FakeProviderName = "fake"
+
+// FakeClient answers every CNPJ with a deterministic company so the client
+// form can be exercised without reaching the public registry. CNPJs listed
+// in NotFound are reported as unknown.
+type FakeClient struct {
+	NotFound map[string]struct{}
+}
+
+func NewFakeClient() *FakeClient {
+	return &FakeClient{NotFound: map[string]struct{}{}}
+}
+
+func (c *FakeClient) LookupCompany(_ context.Context, cnpj string) (usecase.CompanyLookupResult, error) {
+	if _, ok := c.NotFound[cnpj]; ok {
+		return usecase.CompanyLookupResult{}, usecase.ErrCompanyNotFound
+	}
+
+	return usecase.CompanyLookupResult{
+		TaxID:     cnpj,
+		LegalName: fmt.Sprintf("Empresa %s LTDA", cnpj[:8]),
+		TradeName: fmt.Sprintf("Empresa %s", cnpj[:8]),
+		Status:    "ATIVA",
+		Email:     fmt.Sprintf("contato+%s@example.com", cnpj[:8]),
+		Phone:     "1130000000",
+		Address: usecase.CompanyAddress{
+			ZipCode:      "01310100",
+			StreetType:   "avenida",
+			StreetName:   "Paulista",
+			Street:       "Avenida Paulista",
+			Number:       "1000",
+			Neighborhood: "Bela Vista",
+			City:         "São Paulo",
+			State:        "SP",
+		},
+	}, nil
+}
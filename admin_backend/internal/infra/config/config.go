@@ -0,0 +1,353 @@
+// Package config loads the settings of admin_backend and client_backend in
+// one place. Values come from the environment, from files named by KEY_FILE
+// (Docker secrets) and from the optional file named by CONFIG_FILE, in that
+// order of precedence. Load fails on any value that does not parse instead
+// of silently using the default.
+package config
+
+import (
+	"context"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"admin_backend/internal/infra/auth"
+	"admin_backend/internal/infra/cnpj"
+	"admin_backend/internal/infra/db"
+	"admin_backend/internal/infra/geocoding"
+	"admin_backend/internal/infra/httpserver"
+	"admin_backend/internal/infra/localstack"
+	"admin_backend/internal/infra/logging"
+	"admin_backend/internal/infra/mailer"
+	"admin_backend/internal/infra/payments"
+	"admin_backend/internal/infra/ratelimit"
+	"admin_backend/internal/infra/scheduler"
+	"admin_backend/internal/infra/tracing"
+	"admin_backend/internal/infra/zipcode"
+)
+
+// defaultCORSOrigins are the local frontends of each service: the site and
+// the admin frontend call admin_backend, the client frontend calls
+// client_backend.
+var defaultCORSOrigins = map[string][]string{
+	"admin_backend":  {"http://localhost:3000", "http://localhost:3001"},
+	"client_backend": {"http://localhost:3002"},
+}
+
+type Config struct {
+	Port          string
+	Log           logging.Config
+	Server        httpserver.Config
+	CORSOrigins   []string
+	DB            db.Config
+	Auth          auth.Config
+	Localstack    localstack.Config
+	Tracing       tracing.Config
+	Scheduler     scheduler.Config
+	Mail          mailer.Config
+	Payments      payments.Config
+	CompanyLookup cnpj.Config
+	ZipCode       zipcode.Config
+	Geocoding     geocoding.Config
+	RateLimit     ratelimit.Config
+	// WebhookTimeout bounds each webhook delivery attempt.
+	WebhookTimeout time.Duration
+
+	file    string
+	entries []Entry
+}
+
+// Load reads the configuration of service, which selects the defaults that
+// differ between admin_backend and client_backend. On error the returned
+// Config still holds defaults for every invalid value, enough to build a
+// logger that reports the problem.
+func Load(service string) (Config, error) {
+	source, err := NewSource(strings.TrimSpace(os.Getenv("CONFIG_FILE")))
+	if err != nil {
+		return Config{Log: logging.Config{Level: slog.LevelInfo}}, err
+	}
+	loader := NewLoader(source)
+
+	config := Config{
+		Port:        loader.Port("PORT", "8080"),
+		Log:         logging.Config{Level: logLevel(loader)},
+		Server:      serverConfig(loader),
+		CORSOrigins: corsOrigins(loader, defaultCORSOrigins[service]),
+		DB:          dbConfig(loader),
+		Auth: auth.Config{
+			Secret:    loader.Secret("JWT_SECRET", "change-me"),
+			Issuer:    loader.String("JWT_ISSUER", "shalosh"),
+			ExpiresIn: loader.PositiveDuration("JWT_EXPIRES_IN", 24*time.Hour),
+		},
+		Localstack: localstack.Config{
+			Endpoint:        loader.URL("LOCALSTACK_ENDPOINT", "http://localhost:4566"),
+			Region:          loader.String("LOCALSTACK_REGION", "us-east-1"),
+			AccessKeyID:     loader.String("AWS_ACCESS_KEY_ID", "test"),
+			SecretAccessKey: loader.Secret("AWS_SECRET_ACCESS_KEY", "test"),
+			Bucket:          loader.String("LOCALSTACK_BUCKET", "shalosh-attachments"),
+		},
+		Tracing:   tracingConfig(loader),
+		Scheduler: schedulerConfig(loader),
+		Mail:      mailConfig(loader),
+		Payments: payments.Config{
+			Provider:        loader.OneOf("PAYMENT_PROVIDER", payments.FakeProviderName, payments.FakeProviderName),
+			PixKey:          loader.String("PIX_KEY", "pagamentos@shalosh.local"),
+			PixMerchantName: loader.String("PIX_MERCHANT_NAME", "Shalosh"),
+			PixMerchantCity: loader.String("PIX_MERCHANT_CITY", "Sao Paulo"),
+			BoletoBankCode:  loader.String("BOLETO_BANK_CODE", "999"),
+			WebhookSecret:   loader.Secret("PAYMENT_WEBHOOK_SECRET", "local-payment-webhook-secret"),
+		},
+		CompanyLookup: cnpj.Config{
+			Provider: loader.OneOf(
+				"COMPANY_LOOKUP_PROVIDER",
+				cnpj.BrasilAPIProviderName,
+				cnpj.BrasilAPIProviderName,
+				cnpj.FakeProviderName,
+			),
+			BaseURL: loader.URL("COMPANY_LOOKUP_BASE_URL", ""),
+			Timeout: loader.PositiveDuration("COMPANY_LOOKUP_TIMEOUT", 8*time.Second),
+		},
+		ZipCode: zipCodeConfig(loader),
+		Geocoding: geocoding.Config{
+			Provider: loader.OneOf(
+				"GEOCODING_PROVIDER",
+				geocoding.NominatimProviderName,
+				geocoding.NominatimProviderName,
+				geocoding.FakeProviderName,
+			),
+			BaseURL:     loader.URL("GEOCODING_BASE_URL", geocoding.DefaultNominatimURL),
+			Timeout:     loader.PositiveDuration("GEOCODING_TIMEOUT", 10*time.Second),
+			UserAgent:   loader.String("GEOCODING_USER_AGENT", "shalosh-admin-backend"),
+			Email:       loader.String("GEOCODING_EMAIL", ""),
+			MinInterval: loader.Duration("GEOCODING_MIN_INTERVAL", time.Second),
+		},
+		WebhookTimeout: loader.PositiveDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		RateLimit:      rateLimitConfig(loader),
+
+		file: source.Path(),
+	}
+	config.entries = loader.Entries()
+	return config, loader.Err()
+}
+
+// LogSummary logs every setting and where it came from, with passwords,
+// keys and tokens redacted.
+func (c Config) LogSummary(ctx context.Context, logger *slog.Logger) {
+	values := make([]interface{}, 0, len(c.entries))
+	origins := make([]interface{}, 0, len(c.entries))
+	for _, entry := range c.entries {
+		values = append(values, slog.String(entry.Key, entry.Value))
+		if entry.Origin != OriginDefault {
+			origins = append(origins, slog.String(entry.Key, entry.Origin))
+		}
+	}
+	logger.InfoContext(
+		ctx,
+		"configuration loaded",
+		"config_file", c.file,
+		slog.Group("config", values...),
+		slog.Group("config_origin", origins...),
+	)
+}
+
+func logLevel(loader *Loader) slog.Level {
+	switch loader.OneOf("LOG_LEVEL", "info", "debug", "info", "warn", "warning", "error") {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// corsOrigins are compared exactly with the Origin header, so each must be a
+// bare scheme://host[:port]; a trailing slash or a wildcard would never
+// match.
+func corsOrigins(loader *Loader, fallback []string) []string {
+	origins := loader.List("CORS_ALLOWED_ORIGINS", fallback)
+	for _, origin := range origins {
+		parsed, err := url.Parse(origin)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+			parsed.Path != "" || parsed.RawQuery != "" || strings.Contains(origin, "*") {
+			loader.Problem("CORS_ALLOWED_ORIGINS", "%q is not an origin such as https://app.example.com", origin)
+		}
+	}
+	return origins
+}
+
+// serverConfig leaves SERVER_WRITE_TIMEOUT disabled by default because it
+// would cut the Server-Sent Event streams.
+func serverConfig(loader *Loader) httpserver.Config {
+	return httpserver.Config{
+		ReadHeaderTimeout: loader.PositiveDuration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+		ReadTimeout:       loader.Duration("SERVER_READ_TIMEOUT", time.Minute),
+		WriteTimeout:      loader.Duration("SERVER_WRITE_TIMEOUT", 0),
+		IdleTimeout:       loader.Duration("SERVER_IDLE_TIMEOUT", 2*time.Minute),
+		ShutdownDelay:     loader.Duration("SERVER_SHUTDOWN_DELAY", 0),
+		ShutdownTimeout:   loader.PositiveDuration("SERVER_SHUTDOWN_TIMEOUT", 25*time.Second),
+	}
+}
+
+// dbConfig needs no cross-check of the pool sizes: database/sql lowers
+// MaxIdleConns to MaxOpenConns by itself.
+func dbConfig(loader *Loader) db.Config {
+	return db.Config{
+		Host:     loader.String("POSTGRES_HOST", "localhost"),
+		Port:     loader.Port("POSTGRES_PORT", "5432"),
+		User:     loader.String("POSTGRES_USER", "postgres"),
+		Password: loader.Secret("POSTGRES_PASSWORD", "postgres"),
+		Name:     loader.String("POSTGRES_DB", "shalosh"),
+		SSLMode: loader.OneOf(
+			"POSTGRES_SSLMODE",
+			"disable",
+			"disable", "allow", "prefer", "require", "verify-ca", "verify-full",
+		),
+		AdminName:       loader.String("POSTGRES_ADMIN_DB", "postgres"),
+		MaxOpenConns:    loader.Int("DB_MAX_OPEN_CONNS", 20, 1),
+		MaxIdleConns:    loader.Int("DB_MAX_IDLE_CONNS", 10, 0),
+		ConnMaxLifetime: loader.Duration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+		ConnMaxIdleTime: loader.Duration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+
+		AutoMigrate: loader.Bool("DB_AUTO_MIGRATE", true),
+	}
+}
+
+// tracingConfig follows the OpenTelemetry variables: spans go to
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT, or to OTEL_EXPORTER_OTLP_ENDPOINT
+// followed by /v1/traces.
+func tracingConfig(loader *Loader) tracing.Config {
+	endpoint := loader.URL("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	base := loader.URL("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	if endpoint == "" {
+		endpoint = strings.TrimRight(base, "/") + "/v1/traces"
+	}
+
+	return tracing.Config{
+		Enabled:  loader.Bool("OTEL_TRACES_ENABLED", false),
+		Endpoint: endpoint,
+		// Headers usually carry the collector's API key.
+		Headers:     tracing.ParseHeaders(loader.Secret("OTEL_EXPORTER_OTLP_HEADERS", "")),
+		SampleRatio: loader.Ratio("OTEL_TRACES_SAMPLER_ARG", 1),
+		ServiceName: loader.String("OTEL_SERVICE_NAME", ""),
+	}
+}
+
+// schedulerConfig keeps the reminder offsets and the SLA calendar as text;
+// app.New parses them before connecting to anything, so a typo still stops
+// the process at boot rather than when a job first runs.
+func schedulerConfig(loader *Loader) scheduler.Config {
+	return scheduler.Config{
+		PaymentRemindersEnabled:  loader.Bool("PAYMENT_REMINDERS_ENABLED", true),
+		PaymentReminderInterval:  loader.PositiveDuration("PAYMENT_REMINDER_INTERVAL", time.Hour),
+		PaymentReminderOffsets:   loader.String("PAYMENT_REMINDER_OFFSETS", "-5,0,3,10"),
+		WebhooksEnabled:          loader.Bool("WEBHOOKS_ENABLED", true),
+		WebhookDispatchInterval:  loader.PositiveDuration("WEBHOOK_DISPATCH_INTERVAL", 10*time.Second),
+		SLAEscalationEnabled:     loader.Bool("SLA_ESCALATION_ENABLED", true),
+		SLAEscalationInterval:    loader.PositiveDuration("SLA_ESCALATION_INTERVAL", 5*time.Minute),
+		SLABusinessHours:         loader.String("SLA_BUSINESS_HOURS", "09:00-18:00"),
+		SLABusinessDays:          loader.String("SLA_BUSINESS_DAYS", "1-5"),
+		SLATimezone:              loader.String("SLA_TIMEZONE", "America/Sao_Paulo"),
+		AddressGeocodingEnabled:  loader.Bool("ADDRESS_GEOCODING_ENABLED", true),
+		AddressGeocodingInterval: loader.PositiveDuration("ADDRESS_GEOCODING_INTERVAL", time.Minute),
+	}
+}
+
+func mailConfig(loader *Loader) mailer.Config {
+	config := mailer.Config{
+		Provider:       loader.OneOf("MAIL_PROVIDER", mailer.ProviderLog, mailer.ProviderLog, mailer.ProviderSMTP),
+		SMTPHost:       loader.String("SMTP_HOST", ""),
+		SMTPPort:       loader.Port("SMTP_PORT", "587"),
+		SMTPUsername:   loader.String("SMTP_USERNAME", ""),
+		SMTPPassword:   loader.Secret("SMTP_PASSWORD", ""),
+		From:           loader.String("MAIL_FROM", "financeiro@shalosh.local"),
+		FromName:       loader.String("MAIL_FROM_NAME", "Shalosh"),
+		InboundAddress: loader.String("INBOUND_MAIL_ADDRESS", "solicitacoes@shalosh.local"),
+		InboundSecret:  loader.Secret("INBOUND_MAIL_SECRET", ""),
+		PortalURL:      loader.URL("CLIENT_PORTAL_URL", "http://localhost:3002"),
+	}
+	if config.Provider == mailer.ProviderSMTP && config.SMTPHost == "" {
+		loader.Problem("SMTP_HOST", "required when MAIL_PROVIDER is %s", mailer.ProviderSMTP)
+	}
+	return config
+}
+
+// zipCodeConfig reads the CEP providers, asked in the order of
+// ZIPCODE_PROVIDERS, and how long their answers are cached.
+func zipCodeConfig(loader *Loader) zipcode.Config {
+	config := zipcode.Config{
+		ViaCEPURL:        loader.URL("VIACEP_URL", zipcode.DefaultViaCEPURL),
+		BrasilAPIURL:     loader.URL("BRASILAPI_CEP_URL", zipcode.DefaultBrasilAPIURL),
+		OpenCEPURL:       loader.URL("OPENCEP_URL", zipcode.DefaultOpenCEPURL),
+		Timeout:          loader.PositiveDuration("ZIPCODE_LOOKUP_TIMEOUT", 3*time.Second),
+		CacheSize:        loader.Int("ZIPCODE_CACHE_SIZE", 10000, 0),
+		CacheTTL:         loader.PositiveDuration("ZIPCODE_CACHE_TTL", 30*24*time.Hour),
+		NotFoundTTL:      loader.PositiveDuration("ZIPCODE_NOT_FOUND_TTL", 24*time.Hour),
+		BreakerThreshold: loader.Int("ZIPCODE_BREAKER_THRESHOLD", 3, 1),
+		BreakerCooldown:  loader.PositiveDuration("ZIPCODE_BREAKER_COOLDOWN", 30*time.Second),
+	}
+	for _, provider := range loader.List("ZIPCODE_PROVIDERS", zipcode.ProviderNames) {
+		provider = strings.ToLower(provider)
+		if !slices.Contains(zipcode.ProviderNames, provider) {
+			loader.Problem("ZIPCODE_PROVIDERS", "%q is not one of %s", provider, strings.Join(zipcode.ProviderNames, ", "))
+			continue
+		}
+		config.Providers = append(config.Providers, provider)
+	}
+	if len(config.Providers) == 0 {
+		loader.Problem("ZIPCODE_PROVIDERS", "at least one of %s is required", strings.Join(zipcode.ProviderNames, ", "))
+	}
+	return config
+}
+
+// rateLimitConfig reads the limits as "requests/period", optionally with
+// ",burst=N". Buckets are per process unless RATE_LIMIT_STORE is postgres or
+// redis.
+func rateLimitConfig(loader *Loader) ratelimit.Config {
+	config := ratelimit.Config{
+		Enabled: loader.Bool("RATE_LIMIT_ENABLED", true),
+		Store: loader.OneOf(
+			"RATE_LIMIT_STORE",
+			ratelimit.StoreMemory,
+			ratelimit.StoreMemory, ratelimit.StorePostgres, ratelimit.StoreRedis,
+		),
+		RedisURL:     loader.Secret("RATE_LIMIT_REDIS_URL", ""),
+		Login:        rateLimit(loader, "RATE_LIMIT_LOGIN", "10/1m"),
+		Register:     rateLimit(loader, "RATE_LIMIT_REGISTER", "5/1h"),
+		Lookups:      rateLimit(loader, "RATE_LIMIT_LOOKUPS", "30/1m,burst=10"),
+		PortalWrites: rateLimit(loader, "RATE_LIMIT_PORTAL_WRITES", "60/1m"),
+	}
+	if config.Store == ratelimit.StoreRedis && config.RedisURL == "" {
+		loader.Problem("RATE_LIMIT_REDIS_URL", "required when RATE_LIMIT_STORE is %s", ratelimit.StoreRedis)
+	}
+
+	for _, value := range loader.List("RATE_LIMIT_TRUSTED_PROXIES", nil) {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(value)
+			if addrErr != nil {
+				loader.Problem("RATE_LIMIT_TRUSTED_PROXIES", "%q is not an IP address or CIDR range", value)
+				continue
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		config.TrustedProxies = append(config.TrustedProxies, prefix.Masked())
+	}
+	return config
+}
+
+func rateLimit(loader *Loader, key, fallback string) ratelimit.Limit {
+	defaultLimit, _ := ratelimit.ParseLimit(fallback)
+	limit, err := ratelimit.ParseLimit(loader.String(key, fallback))
+	if err != nil {
+		loader.Problem(key, "%v", err)
+		return defaultLimit
+	}
+	return limit
+}
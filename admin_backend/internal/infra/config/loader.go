@@ -0,0 +1,209 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const redacted = "[redacted]"
+
+// Entry is one setting as shown in the startup summary.
+type Entry struct {
+	Key    string
+	Value  string
+	Origin string
+}
+
+// Loader reads typed values from a Source. Instead of falling back to the
+// default, a value that does not parse is recorded as a problem, so every
+// mistake is reported at once by Err.
+type Loader struct {
+	source   *Source
+	entries  []Entry
+	problems []string
+}
+
+func NewLoader(source *Source) *Loader {
+	return &Loader{source: source}
+}
+
+// Entries lists every key read so far, with secrets redacted.
+func (l *Loader) Entries() []Entry {
+	return append([]Entry(nil), l.entries...)
+}
+
+// Problem records an error found while validating values together, such as
+// a provider that needs a setting left empty.
+func (l *Loader) Problem(key, format string, args ...interface{}) {
+	l.problems = append(l.problems, key+": "+fmt.Sprintf(format, args...))
+}
+
+// Err reports every invalid value, or nil when the configuration is usable.
+func (l *Loader) Err() error {
+	if len(l.problems) == 0 {
+		return nil
+	}
+	return errors.New("invalid configuration: " + strings.Join(l.problems, "; "))
+}
+
+func (l *Loader) lookup(key string, secret bool, fallback string) (string, bool) {
+	value, origin, ok, err := l.source.Lookup(key)
+	if err != nil {
+		l.problems = append(l.problems, err.Error())
+	}
+	if !ok {
+		value = fallback
+	}
+
+	shown := value
+	if secret && value != "" {
+		shown = redacted
+	}
+	l.entries = append(l.entries, Entry{Key: key, Value: shown, Origin: origin})
+	return value, ok
+}
+
+func (l *Loader) String(key, fallback string) string {
+	value, _ := l.lookup(key, false, fallback)
+	return value
+}
+
+// Secret is String for passwords and keys, which the summary never shows.
+func (l *Loader) Secret(key, fallback string) string {
+	value, _ := l.lookup(key, true, fallback)
+	return value
+}
+
+// OneOf accepts only the listed values, compared case-insensitively, and
+// returns the value lower-cased.
+func (l *Loader) OneOf(key, fallback string, allowed ...string) string {
+	value := strings.ToLower(l.String(key, fallback))
+	for _, candidate := range allowed {
+		if value == candidate {
+			return value
+		}
+	}
+	l.Problem(key, "%q is not one of %s", value, strings.Join(allowed, ", "))
+	return fallback
+}
+
+func (l *Loader) Bool(key string, fallback bool) bool {
+	value, ok := l.lookup(key, false, strconv.FormatBool(fallback))
+	if !ok {
+		return fallback
+	}
+	switch strings.ToLower(value) {
+	case "1", "true", "yes", "on":
+		return true
+	case "0", "false", "no", "off":
+		return false
+	default:
+		l.Problem(key, "%q is not a boolean", value)
+		return fallback
+	}
+}
+
+// Int accepts whole numbers of at least min.
+func (l *Loader) Int(key string, fallback, min int) int {
+	value, ok := l.lookup(key, false, strconv.Itoa(fallback))
+	if !ok {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		l.Problem(key, "%q is not a whole number", value)
+		return fallback
+	}
+	if parsed < min {
+		l.Problem(key, "%d is below the minimum of %d", parsed, min)
+		return fallback
+	}
+	return parsed
+}
+
+// Port accepts a TCP port number, kept as a string for address joining.
+func (l *Loader) Port(key, fallback string) string {
+	value := l.String(key, fallback)
+	if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+		l.Problem(key, "%q is not a TCP port", value)
+		return fallback
+	}
+	return value
+}
+
+// Ratio accepts a number between 0 and 1.
+func (l *Loader) Ratio(key string, fallback float64) float64 {
+	value, ok := l.lookup(key, false, strconv.FormatFloat(fallback, 'f', -1, 64))
+	if !ok {
+		return fallback
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil || parsed < 0 || parsed > 1 {
+		l.Problem(key, "%q is not a number between 0 and 1", value)
+		return fallback
+	}
+	return parsed
+}
+
+// Duration accepts Go durations such as "90s" or "24h"; zero is allowed and
+// usually means disabled.
+func (l *Loader) Duration(key string, fallback time.Duration) time.Duration {
+	return l.duration(key, fallback, false)
+}
+
+// PositiveDuration is Duration for intervals and timeouts that cannot be
+// zero.
+func (l *Loader) PositiveDuration(key string, fallback time.Duration) time.Duration {
+	return l.duration(key, fallback, true)
+}
+
+func (l *Loader) duration(key string, fallback time.Duration, positive bool) time.Duration {
+	value, ok := l.lookup(key, false, fallback.String())
+	if !ok {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	switch {
+	case err != nil:
+		l.Problem(key, "%q is not a duration such as 30s or 24h", value)
+		return fallback
+	case parsed < 0, positive && parsed == 0:
+		l.Problem(key, "%s must be positive", value)
+		return fallback
+	}
+	return parsed
+}
+
+// URL accepts an absolute http or https URL; an empty fallback makes the
+// setting optional.
+func (l *Loader) URL(key, fallback string) string {
+	value := l.String(key, fallback)
+	if value == "" {
+		return ""
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		l.Problem(key, "%q is not an http(s) URL", value)
+		return fallback
+	}
+	return value
+}
+
+// List splits a comma-separated value, dropping empty items.
+func (l *Loader) List(key string, fallback []string) []string {
+	value, ok := l.lookup(key, false, strings.Join(fallback, ","))
+	if !ok {
+		return fallback
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
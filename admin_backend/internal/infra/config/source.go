@@ -0,0 +1,153 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Where a value came from, as shown in the startup summary.
+const (
+	OriginDefault    = "default"
+	OriginEnv        = "env"
+	OriginConfig     = "config file"
+	OriginSecretFile = "secret file"
+)
+
+// fileSuffix points a key at a file holding its value, the way Docker and
+// Kubernetes mount secrets: JWT_SECRET_FILE=/run/secrets/jwt_secret.
+const fileSuffix = "_FILE"
+
+// Source resolves raw values by key. The environment wins over the config
+// file, and KEY_FILE is read when KEY itself is not set at that level.
+type Source struct {
+	path   string
+	file   map[string]string
+	getenv func(string) string
+}
+
+// NewSource reads the optional config file at path; an empty path means
+// environment only.
+func NewSource(path string) (*Source, error) {
+	source := &Source{path: path, file: map[string]string{}, getenv: os.Getenv}
+	if path == "" {
+		return source, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		source.file, err = parseJSONFile(content)
+	} else {
+		source.file, err = parseEnvFile(content)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return source, nil
+}
+
+// Path is the config file in use, empty when there is none.
+func (s *Source) Path() string {
+	return s.path
+}
+
+// Lookup returns the value of key and its origin; ok is false when the key
+// is set nowhere. Setting both KEY and KEY_FILE at the same level is an
+// error, since it is never clear which one was meant.
+func (s *Source) Lookup(key string) (value, origin string, ok bool, err error) {
+	levels := []struct {
+		origin string
+		get    func(string) string
+	}{
+		{OriginEnv, s.getenv},
+		{OriginConfig, func(key string) string { return s.file[key] }},
+	}
+	for _, level := range levels {
+		value := strings.TrimSpace(level.get(key))
+		path := strings.TrimSpace(level.get(key + fileSuffix))
+		switch {
+		case value != "" && path != "":
+			return "", level.origin, false, fmt.Errorf("set either %s or %s%s, not both", key, key, fileSuffix)
+		case value != "":
+			return value, level.origin, true, nil
+		case path != "":
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return "", OriginSecretFile, false, fmt.Errorf("read %s%s: %w", key, fileSuffix, err)
+			}
+			// Secret files usually end with a newline that is not part of
+			// the value.
+			return strings.TrimRight(string(content), "\r\n"), OriginSecretFile, true, nil
+		}
+	}
+	return "", OriginDefault, false, nil
+}
+
+// parseEnvFile reads KEY=VALUE lines. Blank lines, # comments and a leading
+// "export " are ignored, and values may be wrapped in single or double
+// quotes.
+func parseEnvFile(content []byte) (map[string]string, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			if value[0] == '"' {
+				unquoted, err := strconv.Unquote(value)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", line, err)
+				}
+				value = unquoted
+			} else {
+				value = value[1 : len(value)-1]
+			}
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+// parseJSONFile reads a flat object keyed by the same names as the
+// environment variables; numbers and booleans are accepted as well as
+// strings.
+func parseJSONFile(content []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch typed := value.(type) {
+		case string:
+			values[key] = typed
+		case float64:
+			values[key] = strconv.FormatFloat(typed, 'f', -1, 64)
+		case bool:
+			values[key] = strconv.FormatBool(typed)
+		case nil:
+		default:
+			return nil, fmt.Errorf("%s: expected a string, number or boolean", key)
+		}
+	}
+	return values, nil
+}
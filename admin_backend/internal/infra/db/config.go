@@ -3,7 +3,7 @@ package db
 import (
 	"net"
 	"net/url"
-	"os"
+	"time"
 )
 
 type Config struct {
@@ -13,17 +13,18 @@ type Config struct {
 	Password string
 	Name     string
 	SSLMode  string
-}
+	// AdminName is the database EnsureDatabase connects to in order to
+	// create Name.
+	AdminName string
 
-func FromEnv() Config {
-	return Config{
-		Host:     getenv("POSTGRES_HOST", "localhost"),
-		Port:     getenv("POSTGRES_PORT", "5432"),
-		User:     getenv("POSTGRES_USER", "postgres"),
-		Password: getenv("POSTGRES_PASSWORD", "postgres"),
-		Name:     getenv("POSTGRES_DB", "shalosh"),
-		SSLMode:  getenv("POSTGRES_SSLMODE", "disable"),
-	}
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// AutoMigrate applies pending migrations when admin_backend boots. Turn
+	// it off to run "admin_backend migrate up" as a separate deploy step.
+	AutoMigrate bool
 }
 
 func (c Config) DSN() string {
@@ -44,10 +45,3 @@ func (c Config) WithDatabase(name string) Config {
 	c.Name = name
 	return c
 }
-
-func getenv(key, fallback string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return fallback
-}
@@ -13,6 +13,10 @@ func Connect(ctx context.Context, cfg Config) (*sqlx.DB, error) {
 	if err != nil {
 		return nil, err
 	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
 	var lastErr error
 	for attempt := 0; attempt < 10; attempt++ {
@@ -22,7 +26,13 @@ func Connect(ctx context.Context, cfg Config) (*sqlx.DB, error) {
 		if lastErr == nil {
 			return db, nil
 		}
-		time.Sleep(1 * time.Second)
+
+		select {
+		case <-ctx.Done():
+			_ = db.Close()
+			return nil, ctx.Err()
+		case <-time.After(1 * time.Second):
+		}
 	}
 
 	if lastErr != nil {
@@ -11,8 +11,7 @@ import (
 )
 
 func EnsureDatabase(ctx context.Context, cfg Config) error {
-	adminDB := getenv("POSTGRES_ADMIN_DB", "postgres")
-	adminCfg := cfg.WithDatabase(adminDB)
+	adminCfg := cfg.WithDatabase(cfg.AdminName)
 
 	var db *sqlx.DB
 	var err error
@@ -2,142 +2,496 @@ package db
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io/fs"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
-func Migrate(ctx context.Context, db *sqlx.DB, migrations fs.FS) error {
-	if _, err := db.ExecContext(ctx, `
-		CREATE EXTENSION IF NOT EXISTS pgcrypto;
-		CREATE TABLE IF NOT EXISTS schema_migrations (
-			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-			version TEXT NOT NULL UNIQUE,
-			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
-		)
-	`); err != nil {
-		return fmt.Errorf("create schema_migrations: %w", err)
+// migrationLockKey identifies the pg_advisory_lock taken around every run, so
+// replicas starting together apply each migration once.
+const migrationLockKey int64 = 0x5348414c4f534801
+
+const downSuffix = ".down.sql"
+
+var (
+	ErrMigrationChecksumMismatch = errors.New("applied migration was modified")
+	ErrMigrationNoDown           = errors.New("migration has no down file")
+)
+
+// Migration is one NAME.sql file and its optional NAME.down.sql pair. The
+// version recorded in schema_migrations is the up file name.
+type Migration struct {
+	Version  string
+	Checksum string
+	Up       string
+	Down     string
+	HasDown  bool
+}
+
+// MigrationStatus describes a migration as found in the files and in
+// schema_migrations. Missing marks a version applied to the database whose
+// file no longer exists; Modified, an applied file edited since.
+type MigrationStatus struct {
+	Version   string
+	Applied   bool
+	AppliedAt *time.Time
+	HasDown   bool
+	Modified  bool
+	Missing   bool
+}
+
+type appliedMigration struct {
+	Version   string    `db:"version"`
+	Checksum  *string   `db:"checksum"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+// NewMigrator reads the migrations/ directory of migrations.
+func NewMigrator(db *sqlx.DB, migrations fs.FS) (*Migrator, error) {
+	loaded, err := loadMigrations(migrations)
+	if err != nil {
+		return nil, err
 	}
+	return &Migrator{db: db, migrations: loaded}, nil
+}
 
-	applied, err := loadApplied(ctx, db)
+// Migrate applies every pending migration; it is what admin_backend runs at
+// boot unless DB_AUTO_MIGRATE is off.
+func Migrate(ctx context.Context, db *sqlx.DB, migrations fs.FS) error {
+	migrator, err := NewMigrator(db, migrations)
 	if err != nil {
 		return err
 	}
+	_, err = migrator.Up(ctx)
+	return err
+}
 
-	entries, err := fs.ReadDir(migrations, "migrations")
+// PendingMigrations lists the migrations not yet recorded in
+// schema_migrations, in the order Up would apply them. It takes no lock and
+// never writes, so it is safe for readiness checks.
+func PendingMigrations(ctx context.Context, db *sqlx.DB, migrations fs.FS) ([]string, error) {
+	migrator, err := NewMigrator(db, migrations)
 	if err != nil {
-		return fmt.Errorf("read migrations dir: %w", err)
+		return nil, err
+	}
+	var versions []string
+	if err := db.SelectContext(ctx, &versions, "SELECT version FROM schema_migrations"); err != nil {
+		return nil, fmt.Errorf("list schema_migrations: %w", err)
+	}
+	applied := make(map[string]struct{}, len(versions))
+	for _, version := range versions {
+		applied[version] = struct{}{}
 	}
 
-	files := make([]string, 0, len(entries))
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		name := entry.Name()
-		if !strings.HasSuffix(name, ".sql") {
-			continue
+	var pending []string
+	for _, migration := range migrator.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration.Version)
 		}
-		files = append(files, name)
 	}
+	return pending, nil
+}
 
-	sort.Strings(files)
+// Up applies the pending migrations and returns their versions.
+func (m *Migrator) Up(ctx context.Context) ([]string, error) {
+	var done []string
+	err := m.locked(ctx, true, func(conn *sqlx.Conn) error {
+		pending, err := m.planUp(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range pending {
+			if err := applyUp(ctx, conn, migration); err != nil {
+				return err
+			}
+			done = append(done, migration.Version)
+		}
+		return nil
+	})
+	return done, err
+}
 
-	for _, name := range files {
-		if applied[name] {
-			continue
+// Down rolls back the last steps applied migrations, newest first. Nothing
+// is rolled back unless every one of them has a down file.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]string, error) {
+	var done []string
+	err := m.locked(ctx, true, func(conn *sqlx.Conn) error {
+		targets, err := m.planDown(ctx, conn, steps)
+		if err != nil {
+			return err
 		}
-		contents, err := fs.ReadFile(migrations, "migrations/"+name)
+		for _, migration := range targets {
+			if err := applyDown(ctx, conn, migration); err != nil {
+				return err
+			}
+			done = append(done, migration.Version)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Redo rolls back the last applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) (string, error) {
+	var version string
+	err := m.locked(ctx, true, func(conn *sqlx.Conn) error {
+		targets, err := m.planDown(ctx, conn, 1)
 		if err != nil {
-			return fmt.Errorf("read migration %s: %w", name, err)
+			return err
+		}
+		if len(targets) == 0 {
+			return nil
+		}
+		if err := applyDown(ctx, conn, targets[0]); err != nil {
+			return err
+		}
+		if err := applyUp(ctx, conn, targets[0]); err != nil {
+			return err
 		}
-		if err := runMigration(ctx, db, name, string(contents)); err != nil {
+		version = targets[0].Version
+		return nil
+	})
+	return version, err
+}
+
+// PlanUp returns what Up would apply, after the same checksum verification.
+func (m *Migrator) PlanUp(ctx context.Context) ([]Migration, error) {
+	var planned []Migration
+	err := m.locked(ctx, false, func(conn *sqlx.Conn) error {
+		var err error
+		planned, err = m.planUp(ctx, conn)
+		return err
+	})
+	return planned, err
+}
+
+// PlanDown returns what Down would roll back, newest first.
+func (m *Migrator) PlanDown(ctx context.Context, steps int) ([]Migration, error) {
+	var planned []Migration
+	err := m.locked(ctx, false, func(conn *sqlx.Conn) error {
+		var err error
+		planned, err = m.planDown(ctx, conn, steps)
+		return err
+	})
+	return planned, err
+}
+
+// Status lists every migration file and every applied version without a
+// file, ordered by version.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.locked(ctx, false, func(conn *sqlx.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
 			return err
 		}
+
+		known := make(map[string]struct{}, len(m.migrations))
+		for _, migration := range m.migrations {
+			known[migration.Version] = struct{}{}
+			status := MigrationStatus{Version: migration.Version, HasDown: migration.HasDown}
+			if record, ok := applied[migration.Version]; ok {
+				appliedAt := record.AppliedAt
+				status.Applied = true
+				status.AppliedAt = &appliedAt
+				status.Modified = record.Checksum != nil && *record.Checksum != migration.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+		for version, record := range applied {
+			if _, ok := known[version]; ok {
+				continue
+			}
+			appliedAt := record.AppliedAt
+			statuses = append(statuses, MigrationStatus{
+				Version:   version,
+				Applied:   true,
+				AppliedAt: &appliedAt,
+				Missing:   true,
+			})
+		}
+		return nil
+	})
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, err
+}
+
+// locked runs fn on one connection holding the migration advisory lock. Runs
+// that change the schema first make sure schema_migrations exists and has
+// checksums; read-only runs (status, dry runs) leave it untouched.
+func (m *Migrator) locked(ctx context.Context, writes bool, fn func(conn *sqlx.Conn) error) error {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire migration connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		// The lock must be released even when ctx was cancelled mid-run.
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, _ = conn.ExecContext(unlockCtx, "SELECT pg_advisory_unlock($1)", migrationLockKey)
+	}()
+
+	if writes {
+		if err := m.prepare(ctx, conn); err != nil {
+			return err
+		}
+	}
+	return fn(conn)
+}
+
+// prepare creates schema_migrations and records the checksum of versions
+// applied before checksums were kept, trusting the files as they are now.
+func (m *Migrator) prepare(ctx context.Context, conn *sqlx.Conn) error {
+	if _, err := conn.ExecContext(ctx, `
+		CREATE EXTENSION IF NOT EXISTS pgcrypto;
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			version TEXT NOT NULL UNIQUE,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS checksum TEXT;
+	`); err != nil {
+		return fmt.Errorf("prepare schema_migrations: %w", err)
+	}
+
+	for _, migration := range m.migrations {
+		if _, err := conn.ExecContext(
+			ctx,
+			"UPDATE schema_migrations SET checksum = $2 WHERE version = $1 AND checksum IS NULL",
+			migration.Version,
+			migration.Checksum,
+		); err != nil {
+			return fmt.Errorf("backfill checksum of %s: %w", migration.Version, err)
+		}
 	}
 
 	return nil
 }
 
-func loadApplied(ctx context.Context, db *sqlx.DB) (map[string]bool, error) {
-	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+func (m *Migrator) planUp(ctx context.Context, conn *sqlx.Conn) ([]Migration, error) {
+	applied, err := loadApplied(ctx, conn)
 	if err != nil {
-		return nil, fmt.Errorf("list schema_migrations: %w", err)
+		return nil, err
+	}
+	if err := m.verify(applied); err != nil {
+		return nil, err
 	}
-	defer rows.Close()
 
-	applied := make(map[string]bool)
-	for rows.Next() {
-		var version string
-		if err := rows.Scan(&version); err != nil {
-			return nil, fmt.Errorf("scan schema_migrations: %w", err)
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
 		}
-		applied[version] = true
 	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate schema_migrations: %w", err)
+	return pending, nil
+}
+
+func (m *Migrator) planDown(ctx context.Context, conn *sqlx.Conn, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("down needs at least 1 step, got %d", steps)
+	}
+
+	applied, err := loadApplied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.verify(applied); err != nil {
+		return nil, err
 	}
 
-	return applied, nil
+	versions := make([]string, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(versions)))
+	if steps > len(versions) {
+		steps = len(versions)
+	}
+
+	byVersion := make(map[string]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	targets := make([]Migration, 0, steps)
+	for _, version := range versions[:steps] {
+		migration, ok := byVersion[version]
+		if !ok || !migration.HasDown {
+			return nil, fmt.Errorf("%w: %s", ErrMigrationNoDown, version)
+		}
+		targets = append(targets, migration)
+	}
+	return targets, nil
+}
+
+// verify refuses to run when an applied file was edited: the database would
+// silently differ from what a fresh install builds.
+func (m *Migrator) verify(applied map[string]appliedMigration) error {
+	var modified []string
+	for _, migration := range m.migrations {
+		record, ok := applied[migration.Version]
+		if ok && record.Checksum != nil && *record.Checksum != migration.Checksum {
+			modified = append(modified, migration.Version)
+		}
+	}
+	if len(modified) > 0 {
+		return fmt.Errorf("%w: %s", ErrMigrationChecksumMismatch, strings.Join(modified, ", "))
+	}
+	return nil
 }
 
-func runMigration(ctx context.Context, db *sqlx.DB, name, sqlText string) error {
-	tx, err := db.BeginTxx(ctx, nil)
+func applyUp(ctx context.Context, conn *sqlx.Conn, migration Migration) error {
+	tx, err := conn.BeginTxx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("start migration %s: %w", name, err)
-	}
-
-	if name == "admin_0014_convert_ids_to_uuid.sql" {
-		// Legacy safety: drop FK constraints that can block PK ID rewrites in admin_0014.
-		if _, err := tx.ExecContext(ctx, `
-			DO $$
-			DECLARE
-			  fk_record RECORD;
-			BEGIN
-			  FOR fk_record IN
-			    SELECT
-			      con.conrelid::regclass AS table_name,
-			      con.conname
-			    FROM pg_constraint con
-			    INNER JOIN pg_class rel
-			      ON rel.oid = con.conrelid
-			    WHERE con.contype = 'f'
-			      AND rel.relname IN (
-			        'user_profiles',
-			        'profile_permissions',
-			        'client_addresses',
-			        'client_phones'
-			      )
-			  LOOP
-			    EXECUTE format(
-			      'ALTER TABLE %s DROP CONSTRAINT IF EXISTS %I',
-			      fk_record.table_name,
-			      fk_record.conname
-			    );
-			  END LOOP;
-			END $$;
-		`); err != nil {
-			_ = tx.Rollback()
-			return fmt.Errorf("prepare migration %s: %w", name, err)
-		}
-	}
-
-	if _, err := tx.ExecContext(ctx, sqlText); err != nil {
-		_ = tx.Rollback()
-		return fmt.Errorf("apply migration %s: %w", name, err)
-	}
-
-	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", name); err != nil {
-		_ = tx.Rollback()
-		return fmt.Errorf("record migration %s: %w", name, err)
+		return fmt.Errorf("start migration %s: %w", migration.Version, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+		return fmt.Errorf("apply migration %s: %w", migration.Version, err)
+	}
+	if _, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO schema_migrations (version, checksum) VALUES ($1, $2)",
+		migration.Version,
+		migration.Checksum,
+	); err != nil {
+		return fmt.Errorf("record migration %s: %w", migration.Version, err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit migration %s: %w", name, err)
+		return fmt.Errorf("commit migration %s: %w", migration.Version, err)
+	}
+	return nil
+}
+
+func applyDown(ctx context.Context, conn *sqlx.Conn, migration Migration) error {
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("start rollback %s: %w", migration.Version, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+		return fmt.Errorf("roll back migration %s: %w", migration.Version, err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version); err != nil {
+		return fmt.Errorf("unrecord migration %s: %w", migration.Version, err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit rollback %s: %w", migration.Version, err)
+	}
 	return nil
 }
+
+func loadMigrations(migrations fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(migrations, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir: %w", err)
+	}
+
+	downs := map[string]string{}
+	var loaded []Migration
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+
+		contents, err := fs.ReadFile(migrations, "migrations/"+name)
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", name, err)
+		}
+
+		if strings.HasSuffix(name, downSuffix) {
+			downs[strings.TrimSuffix(name, downSuffix)+".sql"] = string(contents)
+			continue
+		}
+
+		sum := sha256.Sum256(contents)
+		loaded = append(loaded, Migration{
+			Version:  name,
+			Checksum: hex.EncodeToString(sum[:]),
+			Up:       string(contents),
+		})
+	}
+
+	for index := range loaded {
+		if down, ok := downs[loaded[index].Version]; ok {
+			loaded[index].Down = down
+			loaded[index].HasDown = true
+			delete(downs, loaded[index].Version)
+		}
+	}
+	for version := range downs {
+		return nil, fmt.Errorf("down migration without up file: %s", strings.TrimSuffix(version, ".sql")+downSuffix)
+	}
+
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].Version < loaded[j].Version })
+	return loaded, nil
+}
+
+// loadApplied reads schema_migrations, which may not exist yet or may predate
+// the checksum column when called from a read-only run.
+func loadApplied(ctx context.Context, queryer sqlx.QueryerContext) (map[string]appliedMigration, error) {
+	var table struct {
+		Exists      bool `db:"table_exists"`
+		HasChecksum bool `db:"has_checksum"`
+	}
+	if err := sqlx.GetContext(
+		ctx,
+		queryer,
+		&table,
+		`
+		SELECT
+		  to_regclass('schema_migrations') IS NOT NULL AS table_exists,
+		  EXISTS (
+		    SELECT 1
+		    FROM information_schema.columns
+		    WHERE table_schema = current_schema()
+		      AND table_name = 'schema_migrations'
+		      AND column_name = 'checksum'
+		  ) AS has_checksum
+		`,
+	); err != nil {
+		return nil, fmt.Errorf("inspect schema_migrations: %w", err)
+	}
+	if !table.Exists {
+		return map[string]appliedMigration{}, nil
+	}
+
+	query := "SELECT version, checksum, applied_at FROM schema_migrations"
+	if !table.HasChecksum {
+		query = "SELECT version, NULL::text AS checksum, applied_at FROM schema_migrations"
+	}
+
+	var records []appliedMigration
+	if err := sqlx.SelectContext(ctx, queryer, &records, query); err != nil {
+		return nil, fmt.Errorf("list schema_migrations: %w", err)
+	}
+
+	applied := make(map[string]appliedMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
@@ -0,0 +1,105 @@
+CREATE TABLE IF NOT EXISTS project_expense_categories (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  code TEXT NOT NULL,
+  name TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS project_expense_categories_code_lower_key
+  ON project_expense_categories ((LOWER(code)));
+
+CREATE UNIQUE INDEX IF NOT EXISTS project_expense_categories_name_lower_key
+  ON project_expense_categories ((LOWER(name)));
+
+CREATE TABLE IF NOT EXISTS project_expenses (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  category_id UUID NOT NULL REFERENCES project_expense_categories(id) ON DELETE RESTRICT,
+  title TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  supplier TEXT NOT NULL DEFAULT '',
+  amount NUMERIC(12, 2) NOT NULL DEFAULT 0,
+  incurred_on DATE NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT project_expenses_amount_check CHECK (amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS project_expenses_project_id_idx
+  ON project_expenses (project_id);
+
+CREATE INDEX IF NOT EXISTS project_expenses_category_id_idx
+  ON project_expenses (category_id);
+
+CREATE INDEX IF NOT EXISTS project_expenses_incurred_on_idx
+  ON project_expenses (incurred_on);
+
+CREATE TABLE IF NOT EXISTS project_expense_receipts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_expense_id UUID NOT NULL REFERENCES project_expenses(id) ON DELETE CASCADE,
+  file_name TEXT NOT NULL DEFAULT '',
+  file_key TEXT NOT NULL DEFAULT '',
+  content_type TEXT NOT NULL DEFAULT '',
+  issued_on DATE,
+  notes TEXT NOT NULL DEFAULT '',
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS project_expense_receipts_expense_id_idx
+  ON project_expense_receipts (project_expense_id);
+
+CREATE TABLE IF NOT EXISTS user_hourly_rates (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  hourly_cost NUMERIC(12, 2) NOT NULL DEFAULT 0,
+  valid_from DATE NOT NULL,
+  notes TEXT NOT NULL DEFAULT '',
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT user_hourly_rates_hourly_cost_check CHECK (hourly_cost >= 0),
+  CONSTRAINT user_hourly_rates_user_valid_from_key UNIQUE (user_id, valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS user_hourly_rates_user_id_idx
+  ON user_hourly_rates (user_id);
+
+CREATE TABLE IF NOT EXISTS project_time_entries (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  project_task_id UUID REFERENCES project_tasks(id) ON DELETE SET NULL,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+  worked_on DATE NOT NULL,
+  hours NUMERIC(5, 2) NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT project_time_entries_hours_check CHECK (hours > 0 AND hours <= 24)
+);
+
+CREATE INDEX IF NOT EXISTS project_time_entries_project_id_idx
+  ON project_time_entries (project_id);
+
+CREATE INDEX IF NOT EXISTS project_time_entries_user_id_idx
+  ON project_time_entries (user_id);
+
+CREATE INDEX IF NOT EXISTS project_time_entries_worked_on_idx
+  ON project_time_entries (worked_on);
+
+INSERT INTO project_expense_categories (code, name, description, active, created, updated)
+VALUES
+  ('infraestrutura', 'Infraestrutura', 'Servidores, hospedagem, domínios e serviços em nuvem', TRUE, NOW(), NOW()),
+  ('licencas', 'Licenças', 'Licenças de software, plugins e assinaturas', TRUE, NOW(), NOW()),
+  ('terceiros', 'Terceiros', 'Serviços prestados por fornecedores e freelancers', TRUE, NOW(), NOW()),
+  ('deslocamento', 'Deslocamento', 'Viagens, transporte e hospedagem da equipe', TRUE, NOW(), NOW()),
+  ('outros', 'Outros', 'Demais custos do projeto', TRUE, NOW(), NOW())
+ON CONFLICT ((LOWER(code))) DO UPDATE
+SET
+  name = EXCLUDED.name,
+  description = EXCLUDED.description,
+  active = TRUE,
+  updated = NOW();
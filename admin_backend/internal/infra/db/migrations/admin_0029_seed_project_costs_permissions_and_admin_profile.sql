@@ -0,0 +1,64 @@
+INSERT INTO permissions (code, name, description, active, created, updated)
+VALUES
+  ('project_expense_categories.read', 'project_expense_categories.read', 'Permite visualizar categorias de despesas de projetos', TRUE, NOW(), NOW()),
+  ('project_expense_categories.create', 'project_expense_categories.create', 'Permite cadastrar categorias de despesas de projetos', TRUE, NOW(), NOW()),
+  ('project_expenses.create', 'project_expenses.create', 'Permite cadastrar despesas de projetos', TRUE, NOW(), NOW()),
+  ('project_expenses.read', 'project_expenses.read', 'Permite visualizar despesas de projetos', TRUE, NOW(), NOW()),
+  ('project_expenses.update', 'project_expenses.update', 'Permite editar despesas de projetos', TRUE, NOW(), NOW()),
+  ('project_time_entries.create', 'project_time_entries.create', 'Permite lançar horas trabalhadas em projetos', TRUE, NOW(), NOW()),
+  ('project_time_entries.read', 'project_time_entries.read', 'Permite visualizar horas trabalhadas em projetos', TRUE, NOW(), NOW()),
+  ('project_time_entries.update', 'project_time_entries.update', 'Permite editar horas trabalhadas em projetos', TRUE, NOW(), NOW()),
+  ('user_hourly_rates.read', 'user_hourly_rates.read', 'Permite visualizar o custo por hora dos usuários', TRUE, NOW(), NOW()),
+  ('user_hourly_rates.update', 'user_hourly_rates.update', 'Permite editar o custo por hora dos usuários', TRUE, NOW(), NOW()),
+  ('project_financials.read', 'project_financials.read', 'Permite visualizar a rentabilidade dos projetos', TRUE, NOW(), NOW())
+ON CONFLICT ((LOWER(code))) DO UPDATE
+SET
+  name = EXCLUDED.name,
+  description = EXCLUDED.description,
+  active = TRUE,
+  updated = NOW();
+
+DO $$
+DECLARE
+  admin_profile_id UUID;
+  admin_user_id UUID;
+BEGIN
+  INSERT INTO profiles (name, description, active, created, updated)
+  VALUES (
+    'Administrator',
+    'Administrador do sistema com acesso total a todas as permissões',
+    TRUE,
+    NOW(),
+    NOW()
+  )
+  ON CONFLICT ((LOWER(name))) DO UPDATE
+  SET
+    description = EXCLUDED.description,
+    active = TRUE,
+    updated = NOW()
+  RETURNING id INTO admin_profile_id;
+
+  INSERT INTO profile_permissions (profile_id, permission_id, created)
+  SELECT admin_profile_id, permission.id, NOW()
+  FROM permissions permission
+  ON CONFLICT (profile_id, permission_id) DO NOTHING;
+
+  SELECT user_record.id
+    INTO admin_user_id
+  FROM users user_record
+  WHERE LOWER(user_record.login) IN (LOWER('admin'), LOWER('admi'))
+  ORDER BY
+    CASE
+      WHEN LOWER(user_record.login) = LOWER('admin') THEN 0
+      ELSE 1
+    END,
+    user_record.created,
+    user_record.id
+  LIMIT 1;
+
+  IF admin_user_id IS NOT NULL THEN
+    INSERT INTO user_profiles (user_id, profile_id, created)
+    VALUES (admin_user_id, admin_profile_id, NOW())
+    ON CONFLICT (user_id, profile_id) DO NOTHING;
+  END IF;
+END $$;
@@ -0,0 +1,168 @@
+ALTER TABLE project_types
+  ADD COLUMN IF NOT EXISTS iss_rate NUMERIC(5, 2) NOT NULL DEFAULT 0;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1
+    FROM pg_constraint
+    WHERE conname = 'project_types_iss_rate_check'
+  ) THEN
+    ALTER TABLE project_types
+      ADD CONSTRAINT project_types_iss_rate_check CHECK (iss_rate >= 0 AND iss_rate <= 100);
+  END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS invoice_series (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  code TEXT NOT NULL,
+  name TEXT NOT NULL,
+  kind TEXT NOT NULL DEFAULT 'invoice',
+  next_number INTEGER NOT NULL DEFAULT 1,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT invoice_series_kind_check CHECK (kind IN ('invoice', 'credit_note')),
+  CONSTRAINT invoice_series_next_number_check CHECK (next_number >= 1)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS invoice_series_code_lower_key
+  ON invoice_series ((LOWER(code)));
+
+CREATE TABLE IF NOT EXISTS invoices (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  series_id UUID NOT NULL REFERENCES invoice_series(id) ON DELETE RESTRICT,
+  number INTEGER NOT NULL,
+  kind TEXT NOT NULL DEFAULT 'invoice',
+  status TEXT NOT NULL DEFAULT 'emitida',
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE RESTRICT,
+  client_id UUID NOT NULL REFERENCES clients(id) ON DELETE RESTRICT,
+  project_revenue_id UUID REFERENCES project_revenues(id) ON DELETE RESTRICT,
+  project_monthly_charge_id UUID REFERENCES project_monthly_charges(id) ON DELETE RESTRICT,
+  original_invoice_id UUID REFERENCES invoices(id) ON DELETE RESTRICT,
+  client_name TEXT NOT NULL DEFAULT '',
+  client_email TEXT NOT NULL DEFAULT '',
+  billing_address TEXT NOT NULL DEFAULT '',
+  billing_zip_code TEXT NOT NULL DEFAULT '',
+  billing_city TEXT NOT NULL DEFAULT '',
+  billing_state TEXT NOT NULL DEFAULT '',
+  project_name TEXT NOT NULL DEFAULT '',
+  project_type_name TEXT NOT NULL DEFAULT '',
+  issued_on DATE NOT NULL,
+  due_on DATE,
+  services_amount NUMERIC(12, 2) NOT NULL DEFAULT 0,
+  iss_rate NUMERIC(5, 2) NOT NULL DEFAULT 0,
+  iss_amount NUMERIC(12, 2) NOT NULL DEFAULT 0,
+  total_amount NUMERIC(12, 2) NOT NULL DEFAULT 0,
+  notes TEXT NOT NULL DEFAULT '',
+  cancelled_at TIMESTAMPTZ,
+  cancellation_reason TEXT NOT NULL DEFAULT '',
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT invoices_series_number_key UNIQUE (series_id, number),
+  CONSTRAINT invoices_kind_check CHECK (kind IN ('invoice', 'credit_note')),
+  CONSTRAINT invoices_status_check CHECK (status IN ('emitida', 'cancelada')),
+  CONSTRAINT invoices_source_check CHECK (
+    (kind = 'credit_note' AND original_invoice_id IS NOT NULL)
+    OR (
+      kind = 'invoice'
+      AND original_invoice_id IS NULL
+      AND (project_revenue_id IS NULL) <> (project_monthly_charge_id IS NULL)
+    )
+  ),
+  CONSTRAINT invoices_amounts_check CHECK (
+    services_amount >= 0
+    AND iss_rate >= 0
+    AND iss_rate <= 100
+    AND iss_amount >= 0
+    AND total_amount >= 0
+  )
+);
+
+CREATE INDEX IF NOT EXISTS invoices_project_id_idx
+  ON invoices (project_id);
+
+CREATE INDEX IF NOT EXISTS invoices_client_id_idx
+  ON invoices (client_id);
+
+CREATE INDEX IF NOT EXISTS invoices_issued_on_idx
+  ON invoices (issued_on);
+
+CREATE UNIQUE INDEX IF NOT EXISTS invoices_active_revenue_key
+  ON invoices (project_revenue_id)
+  WHERE kind = 'invoice' AND status = 'emitida' AND project_revenue_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS invoices_active_monthly_charge_key
+  ON invoices (project_monthly_charge_id)
+  WHERE kind = 'invoice' AND status = 'emitida' AND project_monthly_charge_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS invoices_credit_note_original_key
+  ON invoices (original_invoice_id)
+  WHERE kind = 'credit_note';
+
+CREATE TABLE IF NOT EXISTS invoice_items (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE RESTRICT,
+  position INTEGER NOT NULL DEFAULT 0,
+  description TEXT NOT NULL,
+  quantity NUMERIC(12, 2) NOT NULL DEFAULT 1,
+  unit_price NUMERIC(12, 2) NOT NULL DEFAULT 0,
+  amount NUMERIC(12, 2) NOT NULL DEFAULT 0,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT invoice_items_quantity_check CHECK (quantity > 0),
+  CONSTRAINT invoice_items_amount_check CHECK (unit_price >= 0 AND amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS invoice_items_invoice_id_idx
+  ON invoice_items (invoice_id);
+
+-- Issued documents are fiscal records: only the emitida -> cancelada
+-- transition (with its cancellation metadata) may touch an existing row.
+CREATE OR REPLACE FUNCTION invoices_prevent_mutation()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    RAISE EXCEPTION 'invoices are immutable once issued'
+      USING ERRCODE = '23514', CONSTRAINT = 'invoices_immutable_check';
+  END IF;
+
+  IF OLD.status <> 'emitida'
+    OR NEW.status <> 'cancelada'
+    OR (
+      to_jsonb(NEW) - ARRAY['status', 'cancelled_at', 'cancellation_reason', 'updated']
+    ) IS DISTINCT FROM (
+      to_jsonb(OLD) - ARRAY['status', 'cancelled_at', 'cancellation_reason', 'updated']
+    ) THEN
+    RAISE EXCEPTION 'invoices are immutable once issued'
+      USING ERRCODE = '23514', CONSTRAINT = 'invoices_immutable_check';
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS invoices_prevent_mutation_trigger ON invoices;
+CREATE TRIGGER invoices_prevent_mutation_trigger
+  BEFORE UPDATE OR DELETE ON invoices
+  FOR EACH ROW
+  EXECUTE FUNCTION invoices_prevent_mutation();
+
+CREATE OR REPLACE FUNCTION invoice_items_prevent_mutation()
+RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'invoice items are immutable once issued'
+    USING ERRCODE = '23514', CONSTRAINT = 'invoices_immutable_check';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS invoice_items_prevent_mutation_trigger ON invoice_items;
+CREATE TRIGGER invoice_items_prevent_mutation_trigger
+  BEFORE UPDATE OR DELETE ON invoice_items
+  FOR EACH ROW
+  EXECUTE FUNCTION invoice_items_prevent_mutation();
+
+INSERT INTO invoice_series (code, name, kind, next_number, active, created, updated)
+VALUES
+  ('NFS', 'Nota fiscal de serviço', 'invoice', 1, TRUE, NOW(), NOW()),
+  ('NC', 'Nota de crédito', 'credit_note', 1, TRUE, NOW(), NOW())
+ON CONFLICT ((LOWER(code))) DO NOTHING;
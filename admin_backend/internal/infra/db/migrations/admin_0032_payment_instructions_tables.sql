@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS payment_instructions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  project_monthly_charge_id UUID NOT NULL REFERENCES project_monthly_charges(id) ON DELETE CASCADE,
+  provider TEXT NOT NULL,
+  provider_reference TEXT NOT NULL,
+  method TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pendente',
+  amount NUMERIC(12, 2) NOT NULL,
+  due_on DATE NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  pix_copy_paste TEXT NOT NULL DEFAULT '',
+  boleto_barcode TEXT NOT NULL DEFAULT '',
+  boleto_digitable_line TEXT NOT NULL DEFAULT '',
+  paid_at TIMESTAMPTZ,
+  paid_amount NUMERIC(12, 2) NOT NULL DEFAULT 0,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT payment_instructions_provider_reference_key UNIQUE (provider, provider_reference),
+  CONSTRAINT payment_instructions_method_check CHECK (method IN ('pix', 'boleto')),
+  CONSTRAINT payment_instructions_status_check CHECK (status IN ('pendente', 'pago')),
+  CONSTRAINT payment_instructions_amount_check CHECK (amount > 0 AND paid_amount >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS payment_instructions_project_id_idx
+  ON payment_instructions (project_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS payment_instructions_pending_charge_method_key
+  ON payment_instructions (project_monthly_charge_id, method)
+  WHERE status = 'pendente';
+
+CREATE TABLE IF NOT EXISTS payment_webhook_events (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  provider TEXT NOT NULL,
+  event_id TEXT NOT NULL,
+  event_type TEXT NOT NULL DEFAULT '',
+  payment_instruction_id UUID REFERENCES payment_instructions(id) ON DELETE SET NULL,
+  outcome TEXT NOT NULL,
+  amount_paid NUMERIC(12, 2) NOT NULL DEFAULT 0,
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  received TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT payment_webhook_events_provider_event_key UNIQUE (provider, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS payment_webhook_events_instruction_id_idx
+  ON payment_webhook_events (payment_instruction_id);
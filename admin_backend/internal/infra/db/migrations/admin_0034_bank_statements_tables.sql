@@ -0,0 +1,58 @@
+ALTER TABLE project_monthly_charges
+  ADD COLUMN IF NOT EXISTS received_on DATE;
+
+UPDATE project_monthly_charges charge
+SET received_on = instruction.paid_at::date
+FROM payment_instructions instruction
+WHERE instruction.project_monthly_charge_id = charge.id
+  AND instruction.status = 'pago'
+  AND charge.status = 'pago'
+  AND charge.received_on IS NULL;
+
+CREATE TABLE IF NOT EXISTS bank_statement_imports (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  format TEXT NOT NULL,
+  file_name TEXT NOT NULL DEFAULT '',
+  account TEXT NOT NULL DEFAULT '',
+  imported_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  total_lines INTEGER NOT NULL DEFAULT 0,
+  imported_lines INTEGER NOT NULL DEFAULT 0,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT bank_statement_imports_format_check CHECK (format IN ('ofx', 'csv'))
+);
+
+CREATE TABLE IF NOT EXISTS bank_statement_lines (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  import_id UUID NOT NULL REFERENCES bank_statement_imports(id) ON DELETE CASCADE,
+  account TEXT NOT NULL DEFAULT '',
+  fit_id TEXT NOT NULL,
+  posted_on DATE NOT NULL,
+  amount NUMERIC(12, 2) NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  payer_name TEXT NOT NULL DEFAULT '',
+  payer_document TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL DEFAULT 'pendente',
+  matched_source_type TEXT NOT NULL DEFAULT '',
+  project_revenue_id UUID REFERENCES project_revenues(id) ON DELETE SET NULL,
+  project_monthly_charge_id UUID REFERENCES project_monthly_charges(id) ON DELETE SET NULL,
+  matched_project_id UUID REFERENCES projects(id) ON DELETE SET NULL,
+  resolved_at TIMESTAMPTZ,
+  resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT bank_statement_lines_account_fit_id_key UNIQUE (account, fit_id),
+  CONSTRAINT bank_statement_lines_status_check CHECK (status IN ('pendente', 'conciliado', 'ignorado')),
+  CONSTRAINT bank_statement_lines_source_type_check CHECK (
+    matched_source_type IN ('', 'revenue', 'monthly_charge')
+  )
+);
+
+CREATE INDEX IF NOT EXISTS bank_statement_lines_import_id_idx
+  ON bank_statement_lines (import_id);
+
+CREATE INDEX IF NOT EXISTS bank_statement_lines_status_idx
+  ON bank_statement_lines (status);
+
+CREATE INDEX IF NOT EXISTS bank_statement_lines_payer_document_idx
+  ON bank_statement_lines (payer_document)
+  WHERE payer_document <> '';
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS payment_reminders (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  source_type TEXT NOT NULL,
+  source_id UUID NOT NULL,
+  client_id UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+  due_on DATE NOT NULL,
+  offset_days INTEGER NOT NULL,
+  email TEXT NOT NULL DEFAULT '',
+  sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT payment_reminders_source_type_check CHECK (
+    source_type IN ('revenue', 'monthly_charge')
+  ),
+  CONSTRAINT payment_reminders_unique_key UNIQUE (
+    source_type,
+    source_id,
+    client_id,
+    due_on,
+    offset_days
+  )
+);
+
+CREATE INDEX IF NOT EXISTS payment_reminders_client_id_idx
+  ON payment_reminders (client_id);
+
+CREATE TABLE IF NOT EXISTS client_payment_reminder_opt_outs (
+  client_id UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+  project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (client_id, project_id)
+);
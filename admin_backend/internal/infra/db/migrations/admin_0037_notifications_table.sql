@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS notifications (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  recipient_user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+  recipient_client_id UUID REFERENCES clients(id) ON DELETE CASCADE,
+  type TEXT NOT NULL,
+  title TEXT NOT NULL,
+  message TEXT NOT NULL DEFAULT '',
+  project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+  service_request_id UUID REFERENCES client_service_requests(id) ON DELETE CASCADE,
+  project_task_id UUID REFERENCES project_tasks(id) ON DELETE CASCADE,
+  read_at TIMESTAMPTZ,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT notifications_single_recipient_check CHECK (
+    (recipient_user_id IS NOT NULL AND recipient_client_id IS NULL)
+    OR (recipient_user_id IS NULL AND recipient_client_id IS NOT NULL)
+  )
+);
+
+CREATE INDEX IF NOT EXISTS notifications_recipient_user_idx
+  ON notifications (recipient_user_id, created DESC)
+  WHERE recipient_user_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS notifications_recipient_client_idx
+  ON notifications (recipient_client_id, created DESC)
+  WHERE recipient_client_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS notifications_unread_idx
+  ON notifications (recipient_user_id, recipient_client_id)
+  WHERE read_at IS NULL;
@@ -0,0 +1,101 @@
+-- Publishes a small JSON payload on the realtime_events channel whenever a
+-- row that the frontends display changes. Every admin_backend instance
+-- LISTENs on the channel and forwards the events to its own SSE sessions.
+CREATE OR REPLACE FUNCTION notify_realtime_event() RETURNS trigger AS $$
+DECLARE
+  rec RECORD;
+  payload JSONB;
+  project_ref UUID;
+  client_ref UUID;
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    rec := OLD;
+  ELSE
+    rec := NEW;
+  END IF;
+
+  payload := jsonb_build_object(
+    'type', TG_ARGV[0] || '.' || CASE TG_OP
+      WHEN 'INSERT' THEN 'created'
+      WHEN 'UPDATE' THEN 'updated'
+      ELSE 'deleted'
+    END,
+    'id', rec.id
+  );
+
+  IF TG_TABLE_NAME = 'projects' THEN
+    payload := payload || jsonb_build_object('projectId', rec.id);
+  ELSIF TG_TABLE_NAME = 'project_tasks' THEN
+    payload := payload || jsonb_build_object(
+      'projectId', rec.project_id,
+      'projectTaskId', rec.id
+    );
+  ELSIF TG_TABLE_NAME = 'project_task_comments' THEN
+    SELECT task.project_id INTO project_ref
+    FROM project_tasks task
+    WHERE task.id = rec.project_task_id;
+
+    payload := payload || jsonb_build_object(
+      'projectId', project_ref,
+      'projectTaskId', rec.project_task_id
+    );
+  ELSIF TG_TABLE_NAME = 'client_service_requests' THEN
+    payload := payload || jsonb_build_object(
+      'projectId', rec.project_id,
+      'serviceRequestId', rec.id,
+      'clientId', rec.client_id
+    );
+  ELSIF TG_TABLE_NAME = 'client_service_request_comments' THEN
+    SELECT request.project_id, request.client_id INTO project_ref, client_ref
+    FROM client_service_requests request
+    WHERE request.id = rec.service_request_id;
+
+    payload := payload || jsonb_build_object(
+      'projectId', project_ref,
+      'serviceRequestId', rec.service_request_id,
+      'clientId', client_ref
+    );
+  ELSIF TG_TABLE_NAME = 'notifications' THEN
+    payload := payload || jsonb_build_object(
+      'projectId', rec.project_id,
+      'projectTaskId', rec.project_task_id,
+      'serviceRequestId', rec.service_request_id,
+      'recipientUserId', rec.recipient_user_id,
+      'recipientClientId', rec.recipient_client_id
+    );
+  END IF;
+
+  PERFORM pg_notify('realtime_events', jsonb_strip_nulls(payload)::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS projects_realtime_event ON projects;
+CREATE TRIGGER projects_realtime_event
+  AFTER INSERT OR UPDATE OR DELETE ON projects
+  FOR EACH ROW EXECUTE FUNCTION notify_realtime_event('project');
+
+DROP TRIGGER IF EXISTS project_tasks_realtime_event ON project_tasks;
+CREATE TRIGGER project_tasks_realtime_event
+  AFTER INSERT OR UPDATE OR DELETE ON project_tasks
+  FOR EACH ROW EXECUTE FUNCTION notify_realtime_event('project_task');
+
+DROP TRIGGER IF EXISTS project_task_comments_realtime_event ON project_task_comments;
+CREATE TRIGGER project_task_comments_realtime_event
+  AFTER INSERT OR UPDATE OR DELETE ON project_task_comments
+  FOR EACH ROW EXECUTE FUNCTION notify_realtime_event('project_task_comment');
+
+DROP TRIGGER IF EXISTS client_service_requests_realtime_event ON client_service_requests;
+CREATE TRIGGER client_service_requests_realtime_event
+  AFTER INSERT OR UPDATE OR DELETE ON client_service_requests
+  FOR EACH ROW EXECUTE FUNCTION notify_realtime_event('service_request');
+
+DROP TRIGGER IF EXISTS client_service_request_comments_realtime_event ON client_service_request_comments;
+CREATE TRIGGER client_service_request_comments_realtime_event
+  AFTER INSERT OR UPDATE OR DELETE ON client_service_request_comments
+  FOR EACH ROW EXECUTE FUNCTION notify_realtime_event('service_request_comment');
+
+DROP TRIGGER IF EXISTS notifications_realtime_event ON notifications;
+CREATE TRIGGER notifications_realtime_event
+  AFTER INSERT OR UPDATE ON notifications
+  FOR EACH ROW EXECUTE FUNCTION notify_realtime_event('notification');
@@ -0,0 +1,198 @@
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  name TEXT NOT NULL,
+  url TEXT NOT NULL,
+  secret TEXT NOT NULL,
+  event_types TEXT[] NOT NULL DEFAULT '{}',
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Transactional outbox: rows are written by the triggers below in the same
+-- transaction as the change they describe and fanned out to subscriptions
+-- by the webhook dispatcher.
+CREATE TABLE IF NOT EXISTS webhook_outbox (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  event_type TEXT NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  dispatched_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS webhook_outbox_pending_idx
+  ON webhook_outbox (created)
+  WHERE dispatched_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  event_id UUID NOT NULL REFERENCES webhook_outbox(id) ON DELETE CASCADE,
+  subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+  status TEXT NOT NULL DEFAULT 'pendente',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  last_status_code INTEGER,
+  last_error TEXT NOT NULL DEFAULT '',
+  delivered_at TIMESTAMPTZ,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT webhook_deliveries_event_subscription_key UNIQUE (event_id, subscription_id),
+  CONSTRAINT webhook_deliveries_status_check CHECK (
+    status IN ('pendente', 'entregue', 'falhou')
+  )
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx
+  ON webhook_deliveries (next_attempt_at)
+  WHERE status = 'pendente';
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_subscription_id_idx
+  ON webhook_deliveries (subscription_id, created DESC);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  delivery_id UUID NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+  status_code INTEGER,
+  error TEXT NOT NULL DEFAULT '',
+  response_body TEXT NOT NULL DEFAULT '',
+  duration_ms INTEGER NOT NULL DEFAULT 0,
+  attempted_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_delivery_attempts_delivery_id_idx
+  ON webhook_delivery_attempts (delivery_id, attempted_at);
+
+-- Only events that some active subscription listens to are stored.
+CREATE OR REPLACE FUNCTION enqueue_webhook_event(event_type TEXT, payload JSONB) RETURNS VOID AS $$
+BEGIN
+  IF EXISTS (
+    SELECT 1
+    FROM webhook_subscriptions subscription
+    WHERE subscription.active = TRUE
+      AND (event_type = ANY(subscription.event_types) OR '*' = ANY(subscription.event_types))
+  ) THEN
+    INSERT INTO webhook_outbox (event_type, payload)
+    VALUES (event_type, payload);
+  END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION webhook_service_request_events() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    PERFORM enqueue_webhook_event('service_request.created', jsonb_build_object(
+      'id', NEW.id,
+      'clientId', NEW.client_id,
+      'projectId', NEW.project_id,
+      'title', NEW.title,
+      'description', NEW.description,
+      'status', NEW.status,
+      'created', NEW.created
+    ));
+  ELSIF NEW.status IS DISTINCT FROM OLD.status THEN
+    PERFORM enqueue_webhook_event('service_request.status_changed', jsonb_build_object(
+      'id', NEW.id,
+      'clientId', NEW.client_id,
+      'projectId', NEW.project_id,
+      'title', NEW.title,
+      'previousStatus', OLD.status,
+      'status', NEW.status,
+      'updated', NEW.updated
+    ));
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION webhook_service_request_comment_events() RETURNS trigger AS $$
+BEGIN
+  PERFORM enqueue_webhook_event('service_request.comment_created', jsonb_build_object(
+    'id', NEW.id,
+    'serviceRequestId', NEW.service_request_id,
+    'userId', NEW.user_id,
+    'clientId', NEW.client_id,
+    'comment', NEW.comment,
+    'created', NEW.created
+  ));
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION webhook_project_events() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'INSERT' THEN
+    PERFORM enqueue_webhook_event('project.created', jsonb_build_object(
+      'id', NEW.id,
+      'name', NEW.name,
+      'status', NEW.status,
+      'created', NEW.created
+    ));
+  ELSIF NEW.status IS DISTINCT FROM OLD.status THEN
+    PERFORM enqueue_webhook_event('project.status_changed', jsonb_build_object(
+      'id', NEW.id,
+      'name', NEW.name,
+      'previousStatus', OLD.status,
+      'status', NEW.status,
+      'updated', NEW.updated
+    ));
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION webhook_monthly_charge_events() RETURNS trigger AS $$
+BEGIN
+  IF NEW.status = 'pago' AND (TG_OP = 'INSERT' OR OLD.status IS DISTINCT FROM NEW.status) THEN
+    PERFORM enqueue_webhook_event('monthly_charge.paid', jsonb_build_object(
+      'id', NEW.id,
+      'projectId', NEW.project_id,
+      'title', NEW.title,
+      'installment', NEW.installment,
+      'amount', NEW.amount,
+      'receivedOn', NEW.received_on
+    ));
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION webhook_revenue_events() RETURNS trigger AS $$
+BEGIN
+  IF NEW.status = 'recebido' AND (TG_OP = 'INSERT' OR OLD.status IS DISTINCT FROM NEW.status) THEN
+    PERFORM enqueue_webhook_event('revenue.received', jsonb_build_object(
+      'id', NEW.id,
+      'projectId', NEW.project_id,
+      'title', NEW.title,
+      'amount', NEW.amount,
+      'receivedOn', NEW.received_on
+    ));
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS client_service_requests_webhook_events ON client_service_requests;
+CREATE TRIGGER client_service_requests_webhook_events
+  AFTER INSERT OR UPDATE ON client_service_requests
+  FOR EACH ROW EXECUTE FUNCTION webhook_service_request_events();
+
+DROP TRIGGER IF EXISTS client_service_request_comments_webhook_events ON client_service_request_comments;
+CREATE TRIGGER client_service_request_comments_webhook_events
+  AFTER INSERT ON client_service_request_comments
+  FOR EACH ROW EXECUTE FUNCTION webhook_service_request_comment_events();
+
+DROP TRIGGER IF EXISTS projects_webhook_events ON projects;
+CREATE TRIGGER projects_webhook_events
+  AFTER INSERT OR UPDATE ON projects
+  FOR EACH ROW EXECUTE FUNCTION webhook_project_events();
+
+DROP TRIGGER IF EXISTS project_monthly_charges_webhook_events ON project_monthly_charges;
+CREATE TRIGGER project_monthly_charges_webhook_events
+  AFTER INSERT OR UPDATE ON project_monthly_charges
+  FOR EACH ROW EXECUTE FUNCTION webhook_monthly_charge_events();
+
+DROP TRIGGER IF EXISTS project_revenues_webhook_events ON project_revenues;
+CREATE TRIGGER project_revenues_webhook_events
+  AFTER INSERT OR UPDATE ON project_revenues
+  FOR EACH ROW EXECUTE FUNCTION webhook_revenue_events();
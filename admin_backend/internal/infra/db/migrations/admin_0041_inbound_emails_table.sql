@@ -0,0 +1,22 @@
+-- Log of the messages received by the inbound mail endpoint. The unique
+-- message id makes MTA retries idempotent.
+CREATE TABLE IF NOT EXISTS inbound_emails (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  message_id TEXT NOT NULL,
+  from_address TEXT NOT NULL DEFAULT '',
+  subject TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL DEFAULT 'processando',
+  client_id UUID REFERENCES clients(id) ON DELETE SET NULL,
+  service_request_id UUID REFERENCES client_service_requests(id) ON DELETE SET NULL,
+  comment_id UUID REFERENCES client_service_request_comments(id) ON DELETE SET NULL,
+  error TEXT NOT NULL DEFAULT '',
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT inbound_emails_message_id_key UNIQUE (message_id),
+  CONSTRAINT inbound_emails_status_check CHECK (
+    status IN ('processando', 'solicitacao_criada', 'comentario_criado', 'ignorado', 'rejeitado')
+  )
+);
+
+CREATE INDEX IF NOT EXISTS inbound_emails_created_idx
+  ON inbound_emails (created DESC);
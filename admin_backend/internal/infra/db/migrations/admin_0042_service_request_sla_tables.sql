@@ -0,0 +1,120 @@
+-- SLA policies apply to a client, to a project type or, with neither set,
+-- to every service request. Targets are business minutes.
+CREATE TABLE IF NOT EXISTS sla_policies (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  name TEXT NOT NULL,
+  client_id UUID REFERENCES clients(id) ON DELETE CASCADE,
+  project_type_id UUID REFERENCES project_types(id) ON DELETE CASCADE,
+  first_response_minutes INTEGER NOT NULL,
+  resolution_minutes INTEGER NOT NULL,
+  warning_minutes INTEGER NOT NULL DEFAULT 60,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT sla_policies_targets_check CHECK (
+    first_response_minutes > 0
+    AND resolution_minutes >= first_response_minutes
+    AND warning_minutes >= 0
+  )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS sla_policies_scope_key
+  ON sla_policies (
+    COALESCE(client_id, '00000000-0000-0000-0000-000000000000'::uuid),
+    COALESCE(project_type_id, '00000000-0000-0000-0000-000000000000'::uuid)
+  )
+  WHERE active = TRUE;
+
+-- Deadlines are computed by the application when the request is created;
+-- the triggers below record when they are met.
+CREATE TABLE IF NOT EXISTS client_service_request_slas (
+  service_request_id UUID PRIMARY KEY REFERENCES client_service_requests(id) ON DELETE CASCADE,
+  policy_id UUID REFERENCES sla_policies(id) ON DELETE SET NULL,
+  policy_name TEXT NOT NULL DEFAULT '',
+  first_response_due_at TIMESTAMPTZ NOT NULL,
+  first_response_warn_at TIMESTAMPTZ NOT NULL,
+  first_responded_at TIMESTAMPTZ,
+  first_response_warned_at TIMESTAMPTZ,
+  first_response_breached BOOLEAN NOT NULL DEFAULT FALSE,
+  resolution_due_at TIMESTAMPTZ NOT NULL,
+  resolution_warn_at TIMESTAMPTZ NOT NULL,
+  resolved_at TIMESTAMPTZ,
+  resolution_warned_at TIMESTAMPTZ,
+  resolution_breached BOOLEAN NOT NULL DEFAULT FALSE,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS client_service_request_slas_first_response_idx
+  ON client_service_request_slas (first_response_due_at)
+  WHERE first_responded_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS client_service_request_slas_resolution_idx
+  ON client_service_request_slas (resolution_due_at)
+  WHERE resolved_at IS NULL;
+
+-- The first reply from the team, or the first status change away from
+-- "aberta", counts as the first response. Closing or cancelling the request
+-- resolves it; reopening restarts the resolution clock without clearing a
+-- recorded breach.
+CREATE OR REPLACE FUNCTION track_service_request_sla_status() RETURNS trigger AS $$
+BEGIN
+  IF NEW.status IS NOT DISTINCT FROM OLD.status THEN
+    RETURN NULL;
+  END IF;
+
+  IF NEW.status <> 'aberta' THEN
+    UPDATE client_service_request_slas
+    SET
+      first_response_breached = first_response_breached OR NOW() > first_response_due_at,
+      first_responded_at = NOW(),
+      updated = NOW()
+    WHERE service_request_id = NEW.id
+      AND first_responded_at IS NULL;
+  END IF;
+
+  IF NEW.status IN ('concluida', 'cancelada') THEN
+    UPDATE client_service_request_slas
+    SET
+      resolution_breached = resolution_breached OR NOW() > resolution_due_at,
+      resolved_at = NOW(),
+      updated = NOW()
+    WHERE service_request_id = NEW.id
+      AND resolved_at IS NULL;
+  ELSIF OLD.status IN ('concluida', 'cancelada') THEN
+    UPDATE client_service_request_slas
+    SET
+      resolved_at = NULL,
+      updated = NOW()
+    WHERE service_request_id = NEW.id;
+  END IF;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION track_service_request_sla_comment() RETURNS trigger AS $$
+BEGIN
+  UPDATE client_service_request_slas
+  SET
+    first_response_breached = first_response_breached OR NEW.created > first_response_due_at,
+    first_responded_at = NEW.created,
+    updated = NOW()
+  WHERE service_request_id = NEW.service_request_id
+    AND first_responded_at IS NULL;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS client_service_requests_sla_status ON client_service_requests;
+CREATE TRIGGER client_service_requests_sla_status
+  AFTER UPDATE OF status ON client_service_requests
+  FOR EACH ROW EXECUTE FUNCTION track_service_request_sla_status();
+
+DROP TRIGGER IF EXISTS client_service_request_comments_sla ON client_service_request_comments;
+CREATE TRIGGER client_service_request_comments_sla
+  AFTER INSERT ON client_service_request_comments
+  FOR EACH ROW
+  WHEN (NEW.user_id IS NOT NULL)
+  EXECUTE FUNCTION track_service_request_sla_comment();
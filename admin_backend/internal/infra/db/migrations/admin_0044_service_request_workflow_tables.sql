@@ -0,0 +1,211 @@
+-- Statuses and the transitions between them are configured by the admins.
+-- System statuses are referenced by the application and cannot be removed.
+CREATE TABLE IF NOT EXISTS service_request_statuses (
+  code TEXT PRIMARY KEY,
+  name TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  closed BOOLEAN NOT NULL DEFAULT FALSE,
+  -- Clients may reopen a request in this status for this many days after it
+  -- entered the status; NULL disables reopening by the client.
+  reopen_window_days INTEGER,
+  position INTEGER NOT NULL DEFAULT 0,
+  system BOOLEAN NOT NULL DEFAULT FALSE,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT service_request_statuses_code_check CHECK (code ~ '^[a-z][a-z0-9_]*$'),
+  CONSTRAINT service_request_statuses_reopen_window_check CHECK (
+    reopen_window_days IS NULL OR reopen_window_days > 0
+  )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS service_request_statuses_name_lower_key
+  ON service_request_statuses ((LOWER(name)));
+
+CREATE TABLE IF NOT EXISTS service_request_status_transitions (
+  from_status TEXT NOT NULL REFERENCES service_request_statuses(code) ON DELETE CASCADE,
+  to_status TEXT NOT NULL REFERENCES service_request_statuses(code) ON DELETE CASCADE,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (from_status, to_status),
+  CONSTRAINT service_request_status_transitions_self_check CHECK (from_status <> to_status)
+);
+
+INSERT INTO service_request_statuses (code, name, description, closed, reopen_window_days, position, system)
+VALUES
+  ('aberta', 'Aberta', 'Solicitação recebida, aguardando atendimento', FALSE, NULL, 10, TRUE),
+  ('em_andamento', 'Em andamento', 'A equipe está trabalhando na solicitação', FALSE, NULL, 20, TRUE),
+  ('aguardando_cliente', 'Aguardando cliente', 'A equipe aguarda informações do cliente', FALSE, NULL, 30, TRUE),
+  ('reaberta', 'Reaberta', 'O cliente reabriu a solicitação concluída', FALSE, NULL, 40, TRUE),
+  ('concluida', 'Concluída', 'Solicitação atendida', TRUE, 7, 50, TRUE),
+  ('cancelada', 'Cancelada', 'Solicitação cancelada', TRUE, NULL, 60, TRUE)
+ON CONFLICT (code) DO UPDATE
+SET
+  system = TRUE,
+  closed = EXCLUDED.closed,
+  updated = NOW();
+
+INSERT INTO service_request_status_transitions (from_status, to_status)
+VALUES
+  ('aberta', 'em_andamento'),
+  ('aberta', 'aguardando_cliente'),
+  ('aberta', 'concluida'),
+  ('aberta', 'cancelada'),
+  ('em_andamento', 'aguardando_cliente'),
+  ('em_andamento', 'concluida'),
+  ('em_andamento', 'cancelada'),
+  ('aguardando_cliente', 'em_andamento'),
+  ('aguardando_cliente', 'concluida'),
+  ('aguardando_cliente', 'cancelada'),
+  ('reaberta', 'em_andamento'),
+  ('reaberta', 'aguardando_cliente'),
+  ('reaberta', 'concluida'),
+  ('reaberta', 'cancelada'),
+  ('concluida', 'reaberta'),
+  ('cancelada', 'reaberta')
+ON CONFLICT (from_status, to_status) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS service_request_categories (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  name TEXT NOT NULL,
+  description TEXT NOT NULL DEFAULT '',
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS service_request_categories_name_lower_key
+  ON service_request_categories ((LOWER(name)));
+
+ALTER TABLE client_service_requests
+  DROP CONSTRAINT IF EXISTS client_service_requests_status_check;
+
+ALTER TABLE client_service_requests
+  ADD COLUMN IF NOT EXISTS assigned_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  ADD COLUMN IF NOT EXISTS priority TEXT NOT NULL DEFAULT 'media',
+  ADD COLUMN IF NOT EXISTS category_id UUID REFERENCES service_request_categories(id) ON DELETE SET NULL;
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1 FROM pg_constraint WHERE conname = 'client_service_requests_status_fkey'
+  ) THEN
+    ALTER TABLE client_service_requests
+      ADD CONSTRAINT client_service_requests_status_fkey
+      FOREIGN KEY (status) REFERENCES service_request_statuses(code);
+  END IF;
+
+  IF NOT EXISTS (
+    SELECT 1 FROM pg_constraint WHERE conname = 'client_service_requests_priority_check'
+  ) THEN
+    ALTER TABLE client_service_requests
+      ADD CONSTRAINT client_service_requests_priority_check
+      CHECK (priority IN ('baixa', 'media', 'alta', 'urgente'));
+  END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS client_service_requests_assigned_user_id_idx
+  ON client_service_requests (assigned_user_id);
+
+CREATE INDEX IF NOT EXISTS client_service_requests_category_id_idx
+  ON client_service_requests (category_id);
+
+CREATE TABLE IF NOT EXISTS client_service_request_status_history (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  service_request_id UUID NOT NULL REFERENCES client_service_requests(id) ON DELETE CASCADE,
+  from_status TEXT,
+  to_status TEXT NOT NULL,
+  changed_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  changed_by_client_id UUID REFERENCES clients(id) ON DELETE SET NULL,
+  note TEXT NOT NULL DEFAULT '',
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS client_service_request_status_history_request_idx
+  ON client_service_request_status_history (service_request_id, created);
+
+INSERT INTO client_service_request_status_history (service_request_id, from_status, to_status, created)
+SELECT request.id, NULL, request.status, request.created
+FROM client_service_requests request
+WHERE NOT EXISTS (
+  SELECT 1
+  FROM client_service_request_status_history history
+  WHERE history.service_request_id = request.id
+);
+
+-- The repository identifies who changed the status through transaction-local
+-- settings (app.actor_user_id, app.actor_client_id and app.status_note), so
+-- changes made by the application and by triggers are all recorded.
+CREATE OR REPLACE FUNCTION record_service_request_status_history() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'UPDATE' AND NEW.status IS NOT DISTINCT FROM OLD.status THEN
+    RETURN NULL;
+  END IF;
+
+  INSERT INTO client_service_request_status_history (
+    service_request_id,
+    from_status,
+    to_status,
+    changed_by_user_id,
+    changed_by_client_id,
+    note
+  )
+  VALUES (
+    NEW.id,
+    CASE WHEN TG_OP = 'UPDATE' THEN OLD.status END,
+    NEW.status,
+    NULLIF(current_setting('app.actor_user_id', TRUE), '')::uuid,
+    NULLIF(current_setting('app.actor_client_id', TRUE), '')::uuid,
+    COALESCE(current_setting('app.status_note', TRUE), '')
+  );
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS client_service_requests_status_history ON client_service_requests;
+CREATE TRIGGER client_service_requests_status_history
+  AFTER INSERT OR UPDATE OF status ON client_service_requests
+  FOR EACH ROW EXECUTE FUNCTION record_service_request_status_history();
+
+-- SLA resolution now follows the configurable closed flag.
+CREATE OR REPLACE FUNCTION track_service_request_sla_status() RETURNS trigger AS $$
+DECLARE
+  new_closed BOOLEAN;
+  old_closed BOOLEAN;
+BEGIN
+  IF NEW.status IS NOT DISTINCT FROM OLD.status THEN
+    RETURN NULL;
+  END IF;
+
+  SELECT closed INTO new_closed FROM service_request_statuses WHERE code = NEW.status;
+  SELECT closed INTO old_closed FROM service_request_statuses WHERE code = OLD.status;
+
+  IF NEW.status <> 'aberta' THEN
+    UPDATE client_service_request_slas
+    SET
+      first_response_breached = first_response_breached OR NOW() > first_response_due_at,
+      first_responded_at = NOW(),
+      updated = NOW()
+    WHERE service_request_id = NEW.id
+      AND first_responded_at IS NULL;
+  END IF;
+
+  IF COALESCE(new_closed, FALSE) THEN
+    UPDATE client_service_request_slas
+    SET
+      resolution_breached = resolution_breached OR NOW() > resolution_due_at,
+      resolved_at = NOW(),
+      updated = NOW()
+    WHERE service_request_id = NEW.id
+      AND resolved_at IS NULL;
+  ELSIF COALESCE(old_closed, FALSE) THEN
+    UPDATE client_service_request_slas
+    SET
+      resolved_at = NULL,
+      updated = NOW()
+    WHERE service_request_id = NEW.id;
+  END IF;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
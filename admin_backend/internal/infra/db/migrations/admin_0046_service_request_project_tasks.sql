@@ -0,0 +1,45 @@
+-- A service request converted into work points at the project task created
+-- for it; the task side is looked up through the same column.
+ALTER TABLE project_tasks
+  ADD COLUMN IF NOT EXISTS service_request_id UUID REFERENCES client_service_requests(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS project_tasks_service_request_id_key
+  ON project_tasks (service_request_id)
+  WHERE service_request_id IS NOT NULL;
+
+-- Completing the task completes the request, when the workflow allows it
+-- from the request's current status.
+CREATE OR REPLACE FUNCTION sync_service_request_from_project_task() RETURNS trigger AS $$
+BEGIN
+  IF NEW.service_request_id IS NULL
+     OR NEW.status <> 'concluida'
+     OR NEW.status IS NOT DISTINCT FROM OLD.status THEN
+    RETURN NULL;
+  END IF;
+
+  PERFORM set_config('app.actor_user_id', '', TRUE);
+  PERFORM set_config('app.actor_client_id', '', TRUE);
+  PERFORM set_config('app.status_note', 'Tarefa "' || NEW.name || '" concluída', TRUE);
+
+  UPDATE client_service_requests request
+  SET status = 'concluida',
+      updated = NOW()
+  WHERE request.id = NEW.service_request_id
+    AND request.status <> 'concluida'
+    AND EXISTS (
+      SELECT 1
+      FROM service_request_status_transitions transition
+      WHERE transition.from_status = request.status
+        AND transition.to_status = 'concluida'
+    );
+
+  PERFORM set_config('app.status_note', '', TRUE);
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS project_tasks_service_request_sync ON project_tasks;
+CREATE TRIGGER project_tasks_service_request_sync
+  AFTER UPDATE OF status ON project_tasks
+  FOR EACH ROW EXECUTE FUNCTION sync_service_request_from_project_task();
@@ -0,0 +1,114 @@
+-- Satisfaction surveys are created when a service request is completed or a
+-- project is concluded. Service request surveys collect a 1-5 rating (CSAT);
+-- project surveys also collect a 0-10 NPS score.
+CREATE TABLE IF NOT EXISTS client_surveys (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  kind TEXT NOT NULL,
+  client_id UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+  service_request_id UUID REFERENCES client_service_requests(id) ON DELETE CASCADE,
+  project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+  status TEXT NOT NULL DEFAULT 'pendente',
+  rating INTEGER,
+  nps INTEGER,
+  comment TEXT NOT NULL DEFAULT '',
+  answered_at TIMESTAMPTZ,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT client_surveys_kind_check CHECK (kind IN ('service_request', 'project')),
+  CONSTRAINT client_surveys_status_check CHECK (status IN ('pendente', 'respondida')),
+  CONSTRAINT client_surveys_rating_check CHECK (rating IS NULL OR rating BETWEEN 1 AND 5),
+  CONSTRAINT client_surveys_nps_check CHECK (nps IS NULL OR nps BETWEEN 0 AND 10),
+  CONSTRAINT client_surveys_subject_check CHECK (
+    (kind = 'service_request' AND service_request_id IS NOT NULL)
+    OR (kind = 'project' AND project_id IS NOT NULL AND service_request_id IS NULL)
+  ),
+  CONSTRAINT client_surveys_answer_check CHECK (
+    status = 'pendente'
+    OR (rating IS NOT NULL AND answered_at IS NOT NULL AND (kind <> 'project' OR nps IS NOT NULL))
+  )
+);
+
+-- A request or project only asks each client once, even if it is reopened
+-- and closed again.
+CREATE UNIQUE INDEX IF NOT EXISTS client_surveys_service_request_key
+  ON client_surveys (service_request_id, client_id)
+  WHERE kind = 'service_request';
+
+CREATE UNIQUE INDEX IF NOT EXISTS client_surveys_project_key
+  ON client_surveys (project_id, client_id)
+  WHERE kind = 'project';
+
+CREATE INDEX IF NOT EXISTS client_surveys_client_status_idx
+  ON client_surveys (client_id, status, created DESC);
+
+CREATE INDEX IF NOT EXISTS client_surveys_answered_at_idx
+  ON client_surveys (answered_at)
+  WHERE status = 'respondida';
+
+CREATE OR REPLACE FUNCTION create_service_request_survey() RETURNS trigger AS $$
+DECLARE
+  survey_id UUID;
+BEGIN
+  IF NEW.status <> 'concluida' OR NEW.status IS NOT DISTINCT FROM OLD.status THEN
+    RETURN NULL;
+  END IF;
+
+  INSERT INTO client_surveys (kind, client_id, service_request_id, project_id)
+  VALUES ('service_request', NEW.client_id, NEW.id, NEW.project_id)
+  ON CONFLICT (service_request_id, client_id) WHERE kind = 'service_request' DO NOTHING
+  RETURNING id INTO survey_id;
+
+  IF survey_id IS NOT NULL THEN
+    INSERT INTO notifications (recipient_client_id, type, title, message, project_id, service_request_id)
+    VALUES (
+      NEW.client_id,
+      'survey.requested',
+      'Como foi o atendimento da solicitação ' || NEW.title || '?',
+      'Avalie o atendimento de 1 a 5 em Pesquisas.',
+      NEW.project_id,
+      NEW.id
+    );
+  END IF;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS client_service_requests_survey ON client_service_requests;
+CREATE TRIGGER client_service_requests_survey
+  AFTER UPDATE OF status ON client_service_requests
+  FOR EACH ROW EXECUTE FUNCTION create_service_request_survey();
+
+CREATE OR REPLACE FUNCTION create_project_surveys() RETURNS trigger AS $$
+BEGIN
+  IF NEW.status <> 'concluido' OR NEW.status IS NOT DISTINCT FROM OLD.status THEN
+    RETURN NULL;
+  END IF;
+
+  WITH created_surveys AS (
+    INSERT INTO client_surveys (kind, client_id, project_id)
+    SELECT 'project', project_client.client_id, NEW.id
+    FROM project_clients project_client
+    INNER JOIN clients client_record ON client_record.id = project_client.client_id
+    WHERE project_client.project_id = NEW.id
+      AND client_record.active = TRUE
+    ON CONFLICT (project_id, client_id) WHERE kind = 'project' DO NOTHING
+    RETURNING client_id
+  )
+  INSERT INTO notifications (recipient_client_id, type, title, message, project_id)
+  SELECT
+    created_surveys.client_id,
+    'survey.requested',
+    'Como foi o projeto ' || NEW.name || '?',
+    'Avalie o projeto e conte se nos recomendaria em Pesquisas.',
+    NEW.id
+  FROM created_surveys;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS projects_survey ON projects;
+CREATE TRIGGER projects_survey
+  AFTER UPDATE OF status ON projects
+  FOR EACH ROW EXECUTE FUNCTION create_project_surveys();
@@ -0,0 +1,105 @@
+-- A client organization is the customer company; each row of clients is one
+-- of its portal users. Members see the projects of every member and their
+-- role limits what they can do in the portal:
+--   owner   - everything, including inviting and managing colleagues
+--   finance - projects and financial data
+--   viewer  - projects only
+CREATE TABLE IF NOT EXISTS client_organizations (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  name TEXT NOT NULL,
+  -- CNPJ digits, empty while unknown.
+  tax_id TEXT NOT NULL DEFAULT '',
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT client_organizations_tax_id_check CHECK (tax_id = '' OR tax_id ~ '^[0-9]{14}$')
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS client_organizations_tax_id_key
+  ON client_organizations (tax_id)
+  WHERE tax_id <> '';
+
+ALTER TABLE clients
+  ADD COLUMN IF NOT EXISTS organization_id UUID REFERENCES client_organizations(id) ON DELETE RESTRICT,
+  ADD COLUMN IF NOT EXISTS organization_role TEXT NOT NULL DEFAULT 'owner';
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1 FROM pg_constraint WHERE conname = 'clients_organization_role_check'
+  ) THEN
+    ALTER TABLE clients
+      ADD CONSTRAINT clients_organization_role_check
+      CHECK (organization_role IN ('owner', 'finance', 'viewer'));
+  END IF;
+END $$;
+
+-- Existing clients become the owner of an organization of their own.
+DO $$
+DECLARE
+  client_record RECORD;
+  new_organization_id UUID;
+BEGIN
+  FOR client_record IN
+    SELECT id, name FROM clients WHERE organization_id IS NULL
+  LOOP
+    INSERT INTO client_organizations (name)
+    VALUES (client_record.name)
+    RETURNING id INTO new_organization_id;
+
+    UPDATE clients
+    SET organization_id = new_organization_id, organization_role = 'owner'
+    WHERE id = client_record.id;
+  END LOOP;
+END $$;
+
+-- Clients created without an organization (self-registration and the admin
+-- client form) found a new one as its owner.
+CREATE OR REPLACE FUNCTION assign_client_organization() RETURNS trigger AS $$
+BEGIN
+  IF NEW.organization_id IS NULL THEN
+    INSERT INTO client_organizations (name)
+    VALUES (NEW.name)
+    RETURNING id INTO NEW.organization_id;
+    NEW.organization_role := 'owner';
+  END IF;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS clients_assign_organization ON clients;
+CREATE TRIGGER clients_assign_organization
+  BEFORE INSERT ON clients
+  FOR EACH ROW EXECUTE FUNCTION assign_client_organization();
+
+ALTER TABLE clients
+  ALTER COLUMN organization_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS clients_organization_id_idx
+  ON clients (organization_id);
+
+-- Invites are sent by e-mail with a one-time token; only its SHA-256 hash is
+-- stored. Accepting creates the portal user in the organization.
+CREATE TABLE IF NOT EXISTS client_organization_invites (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  organization_id UUID NOT NULL REFERENCES client_organizations(id) ON DELETE CASCADE,
+  email TEXT NOT NULL,
+  role TEXT NOT NULL,
+  token_hash TEXT NOT NULL,
+  invited_by_client_id UUID REFERENCES clients(id) ON DELETE SET NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  accepted_at TIMESTAMPTZ,
+  accepted_client_id UUID REFERENCES clients(id) ON DELETE SET NULL,
+  revoked_at TIMESTAMPTZ,
+  created TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT client_organization_invites_role_check CHECK (role IN ('owner', 'finance', 'viewer'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS client_organization_invites_token_hash_key
+  ON client_organization_invites (token_hash);
+
+CREATE UNIQUE INDEX IF NOT EXISTS client_organization_invites_pending_email_key
+  ON client_organization_invites (organization_id, (LOWER(email)))
+  WHERE accepted_at IS NULL AND revoked_at IS NULL;
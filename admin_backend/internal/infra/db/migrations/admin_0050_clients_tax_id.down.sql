@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS clients_tax_id_key;
+
+ALTER TABLE clients
+  DROP CONSTRAINT IF EXISTS clients_tax_id_check;
+
+ALTER TABLE clients
+  DROP COLUMN IF EXISTS tax_id;
@@ -0,0 +1,20 @@
+-- CPF (11 digits) or CNPJ (14 digits) of the client, stored without
+-- punctuation; empty while unknown. Check digits are validated by the
+-- application.
+ALTER TABLE clients
+  ADD COLUMN IF NOT EXISTS tax_id TEXT NOT NULL DEFAULT '';
+
+DO $$
+BEGIN
+  IF NOT EXISTS (
+    SELECT 1 FROM pg_constraint WHERE conname = 'clients_tax_id_check'
+  ) THEN
+    ALTER TABLE clients
+      ADD CONSTRAINT clients_tax_id_check
+      CHECK (tax_id = '' OR tax_id ~ '^([0-9]{11}|[0-9]{14})$');
+  END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS clients_tax_id_key
+  ON clients (tax_id)
+  WHERE tax_id <> '';
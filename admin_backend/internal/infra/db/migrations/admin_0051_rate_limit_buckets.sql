@@ -0,0 +1,51 @@
+-- Token buckets of the API rate limits when RATE_LIMIT_STORE=postgres. The
+-- table is unlogged: losing it in a crash only resets the limits.
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets (
+  key TEXT PRIMARY KEY,
+  tokens DOUBLE PRECISION NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS rate_limit_buckets_updated_at_idx
+  ON rate_limit_buckets (updated_at);
+
+-- rate_limit_take refills the bucket for the time elapsed since its last
+-- use, up to burst, and takes one token when available. The row lock
+-- serializes concurrent requests on the same key across replicas.
+CREATE OR REPLACE FUNCTION rate_limit_take(
+  bucket_key TEXT,
+  burst DOUBLE PRECISION,
+  refill_per_second DOUBLE PRECISION,
+  OUT remaining_tokens DOUBLE PRECISION,
+  OUT allowed BOOLEAN
+)
+LANGUAGE plpgsql
+AS $$
+DECLARE
+  stored_tokens DOUBLE PRECISION;
+  stored_at TIMESTAMPTZ;
+BEGIN
+  INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+  VALUES (bucket_key, burst, NOW())
+  ON CONFLICT (key) DO NOTHING;
+
+  SELECT bucket.tokens, bucket.updated_at
+  INTO stored_tokens, stored_at
+  FROM rate_limit_buckets AS bucket
+  WHERE bucket.key = bucket_key
+  FOR UPDATE;
+
+  remaining_tokens := LEAST(
+    burst,
+    stored_tokens + GREATEST(EXTRACT(EPOCH FROM NOW() - stored_at), 0) * refill_per_second
+  );
+  allowed := remaining_tokens >= 1;
+  IF allowed THEN
+    remaining_tokens := remaining_tokens - 1;
+  END IF;
+
+  UPDATE rate_limit_buckets
+  SET tokens = remaining_tokens, updated_at = NOW()
+  WHERE key = bucket_key;
+END
+$$;
@@ -0,0 +1,19 @@
+-- Local directory of zip codes: answers of the online providers, kept until
+-- expires_at, and rows imported from a CEP dataset, which have no expiry.
+-- Expired rows are still served when every provider is unavailable.
+CREATE TABLE IF NOT EXISTS zip_codes (
+  zip_code TEXT PRIMARY KEY CHECK (zip_code ~ '^[0-9]{8}$'),
+  street TEXT NOT NULL DEFAULT '',
+  complement TEXT NOT NULL DEFAULT '',
+  neighborhood TEXT NOT NULL DEFAULT '',
+  city TEXT NOT NULL DEFAULT '',
+  state TEXT NOT NULL DEFAULT '',
+  ibge TEXT NOT NULL DEFAULT '',
+  gia TEXT NOT NULL DEFAULT '',
+  ddd TEXT NOT NULL DEFAULT '',
+  siafi TEXT NOT NULL DEFAULT '',
+  not_found BOOLEAN NOT NULL DEFAULT FALSE,
+  source TEXT NOT NULL,
+  fetched_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  expires_at TIMESTAMPTZ
+);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS client_addresses_location_idx;
+
+DROP INDEX IF EXISTS client_addresses_geocode_pending_idx;
+
+ALTER TABLE client_addresses
+  DROP CONSTRAINT IF EXISTS client_addresses_geocode_status_check,
+  DROP COLUMN IF EXISTS geocoded_at,
+  DROP COLUMN IF EXISTS geocode_next_attempt_at,
+  DROP COLUMN IF EXISTS geocode_attempts,
+  DROP COLUMN IF EXISTS geocode_status;
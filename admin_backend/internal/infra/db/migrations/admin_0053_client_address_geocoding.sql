@@ -0,0 +1,28 @@
+-- Geocoding state of client addresses. Addresses saved without coordinates
+-- are pending until the address-geocoding job places them; coordinates
+-- already stored were entered by hand.
+ALTER TABLE client_addresses
+  ADD COLUMN IF NOT EXISTS geocode_status TEXT NOT NULL DEFAULT 'pending',
+  ADD COLUMN IF NOT EXISTS geocode_attempts INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS geocode_next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  ADD COLUMN IF NOT EXISTS geocoded_at TIMESTAMPTZ;
+
+ALTER TABLE client_addresses
+  DROP CONSTRAINT IF EXISTS client_addresses_geocode_status_check;
+
+ALTER TABLE client_addresses
+  ADD CONSTRAINT client_addresses_geocode_status_check CHECK (
+    geocode_status IN ('pending', 'located', 'not_found', 'failed', 'manual')
+  );
+
+UPDATE client_addresses
+SET geocode_status = 'manual'
+WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS client_addresses_geocode_pending_idx
+  ON client_addresses (geocode_next_attempt_at)
+  WHERE geocode_status = 'pending';
+
+CREATE INDEX IF NOT EXISTS client_addresses_location_idx
+  ON client_addresses (latitude, longitude)
+  WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
@@ -0,0 +1,37 @@
+package geocoding
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"admin_backend/internal/usecase"
+)
+
+const NominatimProviderName = "nominatim"
+
+type Config struct {
+	Provider string
+	BaseURL  string
+	Timeout  time.Duration
+	// UserAgent and Email identify the application, as the public
+	// Nominatim usage policy requires.
+	UserAgent string
+	Email     string
+	// MinInterval spaces requests; the public Nominatim allows one per
+	// second.
+	MinInterval time.Duration
+}
+
+// New builds the geocoder selected by GEOCODING_PROVIDER. The fake provider
+// is meant for local development and tests.
+func New(config Config) (usecase.Geocoder, error) {
+	switch strings.ToLower(strings.TrimSpace(config.Provider)) {
+	case "", NominatimProviderName:
+		return NewNominatimClient(config), nil
+	case FakeProviderName:
+		return NewFakeClient(), nil
+	default:
+		return nil, fmt.Errorf("unsupported geocoding provider %q", config.Provider)
+	}
+}
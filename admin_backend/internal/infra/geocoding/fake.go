@@ -0,0 +1,43 @@
+package geocoding
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+
+	"admin_backend/internal/usecase"
+)
+
+const FakeProviderName = "fake"
+
+// FakeClient places every address at a deterministic point inside Brazil,
+// derived from its zip code, street and number, so the client map can be
+// exercised without a geocoding service. Addresses without a street or a
+// city are reported as not found, as are zip codes listed in NotFound.
+type FakeClient struct {
+	NotFound map[string]struct{}
+}
+
+func NewFakeClient() *FakeClient {
+	return &FakeClient{NotFound: map[string]struct{}{}}
+}
+
+func (c *FakeClient) Geocode(_ context.Context, query usecase.GeocodeQuery) (usecase.Coordinates, error) {
+	if _, ok := c.NotFound[query.ZipCode]; ok {
+		return usecase.Coordinates{}, usecase.ErrGeocodeNotFound
+	}
+	if strings.TrimSpace(query.Street) == "" || strings.TrimSpace(query.City) == "" {
+		return usecase.Coordinates{}, usecase.ErrGeocodeNotFound
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(query.ZipCode + "|" + query.Street + "|" + query.Number))
+	sum := hash.Sum64()
+
+	// Spread over eastern Brazil, from the latitude of Porto Alegre to that
+	// of Fortaleza.
+	return usecase.Coordinates{
+		Latitude:  -30 + float64(sum%26000)/1000,
+		Longitude: -48 + float64((sum/26000)%10000)/1000,
+	}, nil
+}
@@ -0,0 +1,200 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"admin_backend/internal/usecase"
+)
+
+// DefaultNominatimURL is the public OpenStreetMap Nominatim instance.
+const DefaultNominatimURL = "https://nominatim.openstreetmap.org"
+
+// brazilianStates names the states by their UF, as Nominatim matches state
+// names rather than abbreviations.
+var brazilianStates = map[string]string{
+	"AC": "Acre",
+	"AL": "Alagoas",
+	"AP": "Amapá",
+	"AM": "Amazonas",
+	"BA": "Bahia",
+	"CE": "Ceará",
+	"DF": "Distrito Federal",
+	"ES": "Espírito Santo",
+	"GO": "Goiás",
+	"MA": "Maranhão",
+	"MT": "Mato Grosso",
+	"MS": "Mato Grosso do Sul",
+	"MG": "Minas Gerais",
+	"PA": "Pará",
+	"PB": "Paraíba",
+	"PR": "Paraná",
+	"PE": "Pernambuco",
+	"PI": "Piauí",
+	"RJ": "Rio de Janeiro",
+	"RN": "Rio Grande do Norte",
+	"RS": "Rio Grande do Sul",
+	"RO": "Rondônia",
+	"RR": "Roraima",
+	"SC": "Santa Catarina",
+	"SP": "São Paulo",
+	"SE": "Sergipe",
+	"TO": "Tocantins",
+}
+
+// NominatimClient geocodes with the structured search of Nominatim-compatible
+// APIs: the public instance, a self-hosted one, or services such as
+// LocationIQ that mirror its API.
+type NominatimClient struct {
+	baseURL     string
+	userAgent   string
+	email       string
+	minInterval time.Duration
+	httpClient  *http.Client
+
+	mu          sync.Mutex
+	lastRequest time.Time
+}
+
+func NewNominatimClient(config Config) *NominatimClient {
+	baseURL := config.BaseURL
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = DefaultNominatimURL
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	userAgent := config.UserAgent
+	if strings.TrimSpace(userAgent) == "" {
+		userAgent = "shalosh-admin-backend"
+	}
+
+	return &NominatimClient{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		userAgent:   userAgent,
+		email:       config.Email,
+		minInterval: config.MinInterval,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Geocode searches the street address first and falls back to the zip code
+// alone; it never settles for the city center, which would put every
+// unknown street of a city on the same spot.
+func (c *NominatimClient) Geocode(ctx context.Context, query usecase.GeocodeQuery) (usecase.Coordinates, error) {
+	country := url.Values{}
+	switch strings.ToLower(strings.TrimSpace(query.Country)) {
+	case "", "brasil", "brazil", "br":
+		country.Set("countrycodes", "br")
+	default:
+		country.Set("country", query.Country)
+	}
+
+	var searches []url.Values
+	if query.Street != "" && query.City != "" {
+		search := cloneValues(country)
+		search.Set("street", strings.TrimSpace(query.Number+" "+query.Street))
+		search.Set("city", query.City)
+		if state, ok := brazilianStates[strings.ToUpper(query.State)]; ok {
+			search.Set("state", state)
+		} else if query.State != "" {
+			search.Set("state", query.State)
+		}
+		searches = append(searches, search)
+	}
+	if len(query.ZipCode) == 8 {
+		search := cloneValues(country)
+		search.Set("postalcode", query.ZipCode[:5]+"-"+query.ZipCode[5:])
+		searches = append(searches, search)
+	}
+
+	for _, search := range searches {
+		coordinates, err := c.search(ctx, search)
+		if !errors.Is(err, usecase.ErrGeocodeNotFound) {
+			return coordinates, err
+		}
+	}
+	return usecase.Coordinates{}, usecase.ErrGeocodeNotFound
+}
+
+func (c *NominatimClient) search(ctx context.Context, params url.Values) (usecase.Coordinates, error) {
+	params.Set("format", "jsonv2")
+	params.Set("limit", "1")
+	if c.email != "" {
+		params.Set("email", c.email)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return usecase.Coordinates{}, usecase.ErrGeocoderUnavailable
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	if err := c.wait(ctx); err != nil {
+		return usecase.Coordinates{}, usecase.ErrGeocoderUnavailable
+	}
+	response, err := c.httpClient.Do(req)
+	if err != nil {
+		return usecase.Coordinates{}, usecase.ErrGeocoderUnavailable
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return usecase.Coordinates{}, usecase.ErrGeocoderUnavailable
+	}
+
+	var payload []struct {
+		Latitude  string `json:"lat"`
+		Longitude string `json:"lon"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&payload); err != nil {
+		return usecase.Coordinates{}, usecase.ErrGeocoderUnavailable
+	}
+	if len(payload) == 0 {
+		return usecase.Coordinates{}, usecase.ErrGeocodeNotFound
+	}
+
+	latitude, latErr := strconv.ParseFloat(payload[0].Latitude, 64)
+	longitude, lonErr := strconv.ParseFloat(payload[0].Longitude, 64)
+	if latErr != nil || lonErr != nil {
+		return usecase.Coordinates{}, usecase.ErrGeocoderUnavailable
+	}
+	return usecase.Coordinates{Latitude: latitude, Longitude: longitude}, nil
+}
+
+// wait spaces requests by minInterval across callers.
+func (c *NominatimClient) wait(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if delay := time.Until(c.lastRequest.Add(c.minInterval)); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	c.lastRequest = time.Now()
+	return nil
+}
+
+func cloneValues(values url.Values) url.Values {
+	clone := make(url.Values, len(values))
+	for key, value := range values {
+		clone[key] = append([]string(nil), value...)
+	}
+	return clone
+}
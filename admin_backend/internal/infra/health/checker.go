@@ -0,0 +1,100 @@
+// Package health runs the dependency checks behind /readyz.
+package health
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"admin_backend/internal/infra/db"
+	"github.com/jmoiron/sqlx"
+)
+
+const checkTimeout = 2 * time.Second
+
+// Check probes one dependency; a nil error means it is usable.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Result is the outcome of one Check. Err is nil when the check passed.
+type Result struct {
+	Name string
+	Err  error
+}
+
+type Checker struct {
+	checks   []Check
+	draining atomic.Bool
+}
+
+func NewChecker(checks ...Check) *Checker {
+	return &Checker{checks: checks}
+}
+
+// SetDraining makes the process report not ready for the rest of its life.
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+// Ready runs every check concurrently, each bounded by a short timeout, and
+// returns the results in registration order.
+func (c *Checker) Ready(ctx context.Context) ([]Result, bool) {
+	results := make([]Result, len(c.checks))
+
+	var wg sync.WaitGroup
+	for index, check := range c.checks {
+		wg.Add(1)
+		go func(index int, check Check) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+			results[index] = Result{Name: check.Name, Err: check.Run(checkCtx)}
+		}(index, check)
+	}
+	wg.Wait()
+
+	ready := !c.Draining()
+	for _, result := range results {
+		if result.Err != nil {
+			ready = false
+		}
+	}
+	return results, ready
+}
+
+// Database pings the connection pool.
+func Database(database *sqlx.DB) Check {
+	return Check{
+		Name: "database",
+		Run: func(ctx context.Context) error {
+			return database.PingContext(ctx)
+		},
+	}
+}
+
+// Migrations fails while the schema is behind the migrations embedded in
+// this binary, e.g. when client_backend was deployed ahead of admin_backend.
+func Migrations(database *sqlx.DB, migrations fs.FS) Check {
+	return Check{
+		Name: "migrations",
+		Run: func(ctx context.Context) error {
+			pending, err := db.PendingMigrations(ctx, database, migrations)
+			if err != nil {
+				return err
+			}
+			if len(pending) > 0 {
+				return fmt.Errorf("%d pending migrations, first %s", len(pending), pending[0])
+			}
+			return nil
+		},
+	}
+}
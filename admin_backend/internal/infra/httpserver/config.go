@@ -0,0 +1,19 @@
+package httpserver
+
+import "time"
+
+// Config holds the HTTP server timeouts. WriteTimeout is disabled by default
+// because it would cut the Server-Sent Event streams; handlers that need a
+// deadline set their own.
+type Config struct {
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownDelay keeps serving, with /readyz failing, after SIGTERM so load
+	// balancers stop routing new requests before the listener closes.
+	ShutdownDelay time.Duration
+	// ShutdownTimeout bounds draining in-flight requests and stopping the
+	// background jobs.
+	ShutdownTimeout time.Duration
+}
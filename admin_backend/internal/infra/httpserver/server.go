@@ -0,0 +1,73 @@
+// Package httpserver runs an HTTP server until the process is asked to stop
+// and then shuts it down gracefully.
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Application is what Run coordinates with during shutdown.
+type Application interface {
+	// Drain is called as soon as shutdown starts: the application stops
+	// reporting ready and ends long-lived streams so they do not hold the
+	// drain open.
+	Drain()
+	// Shutdown stops background work and releases resources once the server
+	// has drained, within the deadline of ctx.
+	Shutdown(ctx context.Context) error
+}
+
+func New(addr string, handler http.Handler, config Config, logger *slog.Logger) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
+		ReadTimeout:       config.ReadTimeout,
+		WriteTimeout:      config.WriteTimeout,
+		IdleTimeout:       config.IdleTimeout,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelError),
+	}
+}
+
+// Run serves until ctx is done, typically on SIGTERM, then drains in-flight
+// requests and shuts app down, both within config.ShutdownTimeout. It
+// returns the error that stopped the server, if it was not a shutdown.
+func Run(ctx context.Context, logger *slog.Logger, server *http.Server, config Config, app Application) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	var runErr error
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			runErr = err
+		}
+	case <-ctx.Done():
+		logger.Info("shutdown requested", "delay", config.ShutdownDelay.String(), "timeout", config.ShutdownTimeout.String())
+	}
+
+	app.Drain()
+	if runErr == nil && config.ShutdownDelay > 0 {
+		time.Sleep(config.ShutdownDelay)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("http server shutdown incomplete", "error", err)
+		_ = server.Close()
+	}
+	if err := app.Shutdown(shutdownCtx); err != nil {
+		logger.Error("application shutdown incomplete", "error", err)
+	}
+
+	logger.Info("shutdown complete")
+	return runErr
+}
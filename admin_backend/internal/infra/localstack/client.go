@@ -11,6 +11,8 @@ import (
 type Client struct {
 	config     Config
 	httpClient *http.Client
+	// uploadClient allows for attachments of several megabytes.
+	uploadClient *http.Client
 }
 
 func New(cfg Config) (*Client, error) {
@@ -29,35 +31,36 @@ func New(cfg Config) (*Client, error) {
 		httpClient: &http.Client{
 			Timeout: 3 * time.Second,
 		},
+		uploadClient: &http.Client{
+			Timeout: uploadTimeout,
+		},
 	}, nil
 }
 
-func (c *Client) HealthCheck(ctx context.Context) error {
+// Ping checks once that the object storage endpoint answers. Startup no
+// longer waits for it; /readyz reports it instead.
+func (c *Client) Ping(ctx context.Context) error {
 	endpoint := strings.TrimRight(c.config.Endpoint, "/")
 	healthPaths := []string{"/_localstack/health", "/health"}
 
 	var lastErr error
-	for attempt := 0; attempt < 10; attempt++ {
-		for _, path := range healthPaths {
-			url := endpoint + path
-			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-			if err != nil {
-				return fmt.Errorf("localstack health request: %w", err)
-			}
+	for _, path := range healthPaths {
+		url := endpoint + path
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return fmt.Errorf("localstack health request: %w", err)
+		}
 
-			resp, err := c.httpClient.Do(req)
-			if err == nil {
-				resp.Body.Close()
-				if resp.StatusCode < http.StatusBadRequest {
-					return nil
-				}
-				lastErr = fmt.Errorf("localstack health status (%s): %s", path, resp.Status)
-			} else {
-				lastErr = fmt.Errorf("localstack health check (%s): %w", path, err)
+		resp, err := c.httpClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < http.StatusBadRequest {
+				return nil
 			}
+			lastErr = fmt.Errorf("localstack health status (%s): %s", path, resp.Status)
+		} else {
+			lastErr = fmt.Errorf("localstack health check (%s): %w", path, err)
 		}
-
-		time.Sleep(1 * time.Second)
 	}
 
 	return lastErr
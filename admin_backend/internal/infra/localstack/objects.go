@@ -0,0 +1,191 @@
+package localstack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	signingService   = "s3"
+	amzDateLayout    = "20060102T150405Z"
+	uploadTimeout    = 30 * time.Second
+)
+
+// PutObject stores content under key in the configured bucket with a
+// path-style S3 request signed with Signature Version 4, which LocalStack
+// and S3 both accept. The bucket is created the first time it is missing.
+func (c *Client) PutObject(ctx context.Context, key string, contentType string, content []byte) error {
+	if strings.TrimSpace(c.config.Bucket) == "" {
+		return fmt.Errorf("localstack bucket is required")
+	}
+
+	status, err := c.putObject(ctx, key, contentType, content)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		if err := c.createBucket(ctx); err != nil {
+			return err
+		}
+		status, err = c.putObject(ctx, key, contentType, content)
+		if err != nil {
+			return err
+		}
+	}
+	if status >= http.StatusMultipleChoices {
+		return fmt.Errorf("localstack put object status: %d", status)
+	}
+	return nil
+}
+
+func (c *Client) putObject(ctx context.Context, key string, contentType string, content []byte) (int, error) {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return c.do(ctx, http.MethodPut, "/"+escapePath(c.config.Bucket)+"/"+escapePath(key), header, content)
+}
+
+func (c *Client) createBucket(ctx context.Context) error {
+	var body []byte
+	if c.config.Region != "us-east-1" {
+		body = []byte(`<CreateBucketConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">` +
+			`<LocationConstraint>` + c.config.Region + `</LocationConstraint></CreateBucketConfiguration>`)
+	}
+
+	status, err := c.do(ctx, http.MethodPut, "/"+escapePath(c.config.Bucket), http.Header{}, body)
+	if err != nil {
+		return err
+	}
+	// 409 means another replica created it meanwhile.
+	if status >= http.StatusMultipleChoices && status != http.StatusConflict {
+		return fmt.Errorf("localstack create bucket status: %d", status)
+	}
+	return nil
+}
+
+// do sends a signed request to an already escaped path and returns the
+// response status.
+func (c *Client) do(ctx context.Context, method string, escapedPath string, header http.Header, body []byte) (int, error) {
+	target, err := url.Parse(strings.TrimRight(c.config.Endpoint, "/") + escapedPath)
+	if err != nil {
+		return 0, fmt.Errorf("localstack request url: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("localstack request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	c.sign(req, body, time.Now().UTC())
+
+	resp, err := c.uploadClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("localstack %s %s: %w", method, escapedPath, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return resp.StatusCode, nil
+}
+
+// sign adds the Signature Version 4 headers for the s3 service.
+func (c *Client) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.Format(amzDateLayout)
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + c.config.Region + "/" + signingService + "/aws4_request"
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.config.SecretAccessKey), date)
+	key = hmacSHA256(key, c.config.Region)
+	key = hmacSHA256(key, signingService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm,
+		c.config.AccessKeyID,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+// escapePath encodes every segment of an object key the way S3 expects in
+// the canonical request: everything but unreserved characters.
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		var escaped strings.Builder
+		for _, b := range []byte(segment) {
+			if ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z') || ('0' <= b && b <= '9') ||
+				b == '-' || b == '.' || b == '_' || b == '~' {
+				escaped.WriteByte(b)
+				continue
+			}
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+		segments[i] = escaped.String()
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,7 @@
+package logging
+
+import "log/slog"
+
+type Config struct {
+	Level slog.Level
+}
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"context"
+	"sync"
+)
+
+type requestKey struct{}
+
+// RequestInfo is what the access log learns about a request while it is
+// handled: its ID, the route pattern it matched, who made it and the error a
+// handler hid behind a generic response.
+type RequestInfo struct {
+	ID    string
+	Route string
+
+	mu       sync.Mutex
+	userID   string
+	clientID string
+	err      error
+}
+
+func WithRequestInfo(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestKey{}, info)
+}
+
+func requestInfo(ctx context.Context) *RequestInfo {
+	if ctx == nil {
+		return nil
+	}
+	info, _ := ctx.Value(requestKey{}).(*RequestInfo)
+	return info
+}
+
+func RequestID(ctx context.Context) string {
+	if info := requestInfo(ctx); info != nil {
+		return info.ID
+	}
+	return ""
+}
+
+// SetUserID records the admin user authenticated for the request.
+func SetUserID(ctx context.Context, userID string) {
+	if info := requestInfo(ctx); info != nil {
+		info.mu.Lock()
+		info.userID = userID
+		info.mu.Unlock()
+	}
+}
+
+// SetClientID records the portal client authenticated for the request.
+func SetClientID(ctx context.Context, clientID string) {
+	if info := requestInfo(ctx); info != nil {
+		info.mu.Lock()
+		info.clientID = clientID
+		info.mu.Unlock()
+	}
+}
+
+// RecordError keeps the first error reported for the request.
+func (i *RequestInfo) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	i.mu.Lock()
+	if i.err == nil {
+		i.err = err
+	}
+	i.mu.Unlock()
+}
+
+func (i *RequestInfo) Snapshot() (userID, clientID string, err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.userID, i.clientID, i.err
+}
@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"fmt"
+	"strings"
+
+	"admin_backend/internal/usecase"
+)
+
+const (
+	ProviderLog  = "log"
+	ProviderSMTP = "smtp"
+)
+
+type Config struct {
+	Provider     string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+	FromName     string
+	// InboundAddress receives client replies; threads are told apart by a
+	// "+token" suffix, so the mailbox must accept plus addressing.
+	InboundAddress string
+	InboundSecret  string
+	// PortalURL is the client portal address used in links sent by e-mail.
+	PortalURL string
+}
+
+// New builds the mailer selected by MAIL_PROVIDER. The log mailer only
+// prints messages and is the default for local environments.
+func New(config Config) (usecase.Mailer, error) {
+	switch strings.ToLower(strings.TrimSpace(config.Provider)) {
+	case "", ProviderLog:
+		return NewLogMailer(config), nil
+	case ProviderSMTP:
+		if strings.TrimSpace(config.SMTPHost) == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required for the smtp mail provider")
+		}
+		return NewSMTPMailer(config), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail provider %q", config.Provider)
+	}
+}
@@ -0,0 +1,28 @@
+package mailer
+
+import (
+	"context"
+	"log/slog"
+
+	"admin_backend/internal/usecase"
+)
+
+type LogMailer struct {
+	from string
+}
+
+func NewLogMailer(config Config) *LogMailer {
+	return &LogMailer{from: config.From}
+}
+
+func (m *LogMailer) Send(_ context.Context, message usecase.EmailMessage) error {
+	slog.Info(
+		"mail",
+		"from", m.from,
+		"to", message.To,
+		"reply_to", message.ReplyTo,
+		"subject", message.Subject,
+		"body", message.Body,
+	)
+	return nil
+}
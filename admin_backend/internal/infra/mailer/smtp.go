@@ -0,0 +1,84 @@
+package mailer
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"admin_backend/internal/usecase"
+)
+
+type SMTPMailer struct {
+	address  string
+	host     string
+	auth     smtp.Auth
+	from     string
+	fromName string
+}
+
+func NewSMTPMailer(config Config) *SMTPMailer {
+	host := strings.TrimSpace(config.SMTPHost)
+
+	var auth smtp.Auth
+	if config.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", config.SMTPUsername, config.SMTPPassword, host)
+	}
+
+	return &SMTPMailer{
+		address:  net.JoinHostPort(host, strings.TrimSpace(config.SMTPPort)),
+		host:     host,
+		auth:     auth,
+		from:     strings.TrimSpace(config.From),
+		fromName: strings.TrimSpace(config.FromName),
+	}
+}
+
+// Send delivers a plain text UTF-8 message. net/smtp upgrades the connection
+// with STARTTLS whenever the server offers it.
+func (m *SMTPMailer) Send(ctx context.Context, message usecase.EmailMessage) error {
+	to := strings.TrimSpace(message.To)
+	if to == "" {
+		return usecase.ErrInvalidInput
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return smtp.SendMail(m.address, m.auth, m.from, []string{to}, m.buildMessage(message))
+}
+
+func (m *SMTPMailer) buildMessage(message usecase.EmailMessage) []byte {
+	var builder strings.Builder
+	builder.WriteString("From: " + formatAddress(m.fromName, m.from) + "\r\n")
+	builder.WriteString("To: " + formatAddress(message.ToName, message.To) + "\r\n")
+	if replyTo := strings.TrimSpace(message.ReplyTo); replyTo != "" {
+		builder.WriteString("Reply-To: <" + replyTo + ">\r\n")
+	}
+	builder.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", message.Subject) + "\r\n")
+	builder.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	builder.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(message.Body))
+	for len(encoded) > 76 {
+		builder.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	builder.WriteString(encoded + "\r\n")
+
+	return []byte(builder.String())
+}
+
+func formatAddress(name, address string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "<" + address + ">"
+	}
+	return fmt.Sprintf("%s <%s>", mime.QEncoding.Encode("utf-8", name), address)
+}
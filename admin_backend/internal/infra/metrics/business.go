@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"context"
+
+	"admin_backend/internal/usecase"
+)
+
+// RegisterBusinessMetrics exposes the operational gauges computed by
+// service. They are queried on every scrape, so only one service should
+// register them.
+func RegisterBusinessMetrics(registry *Registry, service *usecase.BusinessMetricsService) {
+	registry.NewGaugeFunc(
+		"shalosh_open_service_requests",
+		"Service requests in a status not flagged as closed, by status.",
+		[]string{"status"},
+		func(ctx context.Context) ([]Sample, error) {
+			counts, err := service.OpenServiceRequests(ctx)
+			if err != nil {
+				return nil, err
+			}
+			samples := make([]Sample, 0, len(counts))
+			for _, count := range counts {
+				samples = append(samples, Sample{LabelValues: []string{count.Status}, Value: float64(count.Count)})
+			}
+			return samples, nil
+		},
+	)
+
+	overdue := func(value func(usecase.OverdueReceivableSummary) float64) func(context.Context) ([]Sample, error) {
+		return func(ctx context.Context) ([]Sample, error) {
+			summaries, err := service.OverdueReceivables(ctx)
+			if err != nil {
+				return nil, err
+			}
+			samples := make([]Sample, 0, len(summaries))
+			for _, summary := range summaries {
+				samples = append(samples, Sample{LabelValues: []string{summary.Source}, Value: value(summary)})
+			}
+			return samples, nil
+		}
+	}
+	registry.NewGaugeFunc(
+		"shalosh_overdue_charges",
+		"Pending revenues and monthly charges past their due date, by source.",
+		[]string{"source"},
+		overdue(func(summary usecase.OverdueReceivableSummary) float64 { return float64(summary.Count) }),
+	)
+	registry.NewGaugeFunc(
+		"shalosh_overdue_charges_amount",
+		"Amount of the pending revenues and monthly charges past their due date, by source.",
+		[]string{"source"},
+		overdue(func(summary usecase.OverdueReceivableSummary) float64 { return summary.Amount }),
+	)
+}
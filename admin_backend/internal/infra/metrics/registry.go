@@ -0,0 +1,347 @@
+// Package metrics keeps process metrics and renders them in the Prometheus
+// text exposition format (version 0.0.4) for GET /metrics.
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets suit HTTP and database latencies, in seconds.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Sample is one value reported by a function collector. LabelValues follow
+// the label names the collector was registered with.
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+type collector interface {
+	describe() (name, help, kind string)
+	write(ctx context.Context, w *bufio.Writer) error
+}
+
+// Registry holds the metrics exposed by one process.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+	names      map[string]struct{}
+}
+
+func NewRegistry() *Registry {
+	return &Registry{names: map[string]struct{}{}}
+}
+
+func (r *Registry) register(c collector) {
+	name, _, _ := c.describe()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.names[name]; exists {
+		panic("metrics: duplicate metric " + name)
+	}
+	r.names[name] = struct{}{}
+	r.collectors = append(r.collectors, c)
+}
+
+// NewCounter registers a monotonically increasing counter.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	counter := &Counter{family: newFamily(name, help, labels)}
+	r.register(counter)
+	return counter
+}
+
+// NewHistogram registers a histogram with the given upper bounds, which must
+// be sorted in increasing order.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	histogram := &Histogram{family: newFamily(name, help, labels), buckets: buckets}
+	r.register(histogram)
+	return histogram
+}
+
+// NewGaugeFunc registers a gauge whose samples are read by collect on every
+// scrape.
+func (r *Registry) NewGaugeFunc(
+	name string,
+	help string,
+	labels []string,
+	collect func(ctx context.Context) ([]Sample, error),
+) {
+	r.register(&funcCollector{family: newFamily(name, help, labels), kind: "gauge", collect: collect})
+}
+
+// NewCounterFunc is NewGaugeFunc for totals kept elsewhere, such as the
+// database/sql pool statistics.
+func (r *Registry) NewCounterFunc(
+	name string,
+	help string,
+	labels []string,
+	collect func(ctx context.Context) ([]Sample, error),
+) {
+	r.register(&funcCollector{family: newFamily(name, help, labels), kind: "counter", collect: collect})
+}
+
+// Handler serves the registry. A collector that fails is skipped and logged
+// so one slow query does not hide every other metric.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(req.Context(), 10*time.Second)
+		defer cancel()
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+
+		r.mu.Lock()
+		collectors := append([]collector(nil), r.collectors...)
+		r.mu.Unlock()
+
+		writer := bufio.NewWriter(w)
+		for _, c := range collectors {
+			if err := c.write(ctx, writer); err != nil {
+				name, _, _ := c.describe()
+				slog.WarnContext(ctx, "metric collection failed", "metric", name, "error", err)
+			}
+		}
+		_ = writer.Flush()
+	})
+}
+
+type family struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func newFamily(name, help string, labels []string) family {
+	return family{name: name, help: help, labels: labels}
+}
+
+func (f family) header(w *bufio.Writer, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, kind)
+}
+
+func (f family) key(labelValues []string) string {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labels), len(labelValues)))
+	}
+	return strings.Join(labelValues, "\xff")
+}
+
+// Counter is a labeled counter.
+type Counter struct {
+	family
+	mu     sync.Mutex
+	values map[string]*counterValue
+}
+
+type counterValue struct {
+	labelValues []string
+	value       float64
+}
+
+func (c *Counter) describe() (string, string, string) { return c.name, c.help, "counter" }
+
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	key := c.key(labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.values == nil {
+		c.values = map[string]*counterValue{}
+	}
+	value, ok := c.values[key]
+	if !ok {
+		value = &counterValue{labelValues: append([]string(nil), labelValues...)}
+		c.values[key] = value
+	}
+	value.value += delta
+}
+
+func (c *Counter) write(_ context.Context, w *bufio.Writer) error {
+	c.mu.Lock()
+	samples := make([]Sample, 0, len(c.values))
+	for _, value := range c.values {
+		samples = append(samples, Sample{LabelValues: value.labelValues, Value: value.value})
+	}
+	c.mu.Unlock()
+
+	c.header(w, "counter")
+	writeSamples(w, c.name, c.labels, samples)
+	return nil
+}
+
+// Histogram is a labeled cumulative histogram.
+type Histogram struct {
+	family
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*histogramValue
+}
+
+type histogramValue struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+func (h *Histogram) describe() (string, string, string) { return h.name, h.help, "histogram" }
+
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	key := h.key(labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.values == nil {
+		h.values = map[string]*histogramValue{}
+	}
+	entry, ok := h.values[key]
+	if !ok {
+		entry = &histogramValue{
+			labelValues: append([]string(nil), labelValues...),
+			counts:      make([]uint64, len(h.buckets)),
+		}
+		h.values[key] = entry
+	}
+	for index, upperBound := range h.buckets {
+		if value <= upperBound {
+			entry.counts[index]++
+		}
+	}
+	entry.count++
+	entry.sum += value
+}
+
+func (h *Histogram) write(_ context.Context, w *bufio.Writer) error {
+	h.mu.Lock()
+	entries := make([]histogramValue, 0, len(h.values))
+	for _, entry := range h.values {
+		entries = append(entries, histogramValue{
+			labelValues: entry.labelValues,
+			counts:      append([]uint64(nil), entry.counts...),
+			count:       entry.count,
+			sum:         entry.sum,
+		})
+	}
+	h.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return strings.Join(entries[i].labelValues, "\xff") < strings.Join(entries[j].labelValues, "\xff")
+	})
+
+	h.header(w, "histogram")
+	bucketLabels := append(append([]string(nil), h.labels...), "le")
+	for _, entry := range entries {
+		for index, upperBound := range h.buckets {
+			labelValues := append(append([]string(nil), entry.labelValues...), formatValue(upperBound))
+			writeSample(w, h.name+"_bucket", bucketLabels, labelValues, float64(entry.counts[index]))
+		}
+		labelValues := append(append([]string(nil), entry.labelValues...), "+Inf")
+		writeSample(w, h.name+"_bucket", bucketLabels, labelValues, float64(entry.count))
+		writeSample(w, h.name+"_sum", h.labels, entry.labelValues, entry.sum)
+		writeSample(w, h.name+"_count", h.labels, entry.labelValues, float64(entry.count))
+	}
+	return nil
+}
+
+type funcCollector struct {
+	family
+	kind    string
+	collect func(ctx context.Context) ([]Sample, error)
+}
+
+func (f *funcCollector) describe() (string, string, string) { return f.name, f.help, f.kind }
+
+func (f *funcCollector) write(ctx context.Context, w *bufio.Writer) error {
+	samples, err := f.collect(ctx)
+	if err != nil {
+		return err
+	}
+	for _, sample := range samples {
+		if len(sample.LabelValues) != len(f.labels) {
+			return fmt.Errorf("expected %d label values, got %d", len(f.labels), len(sample.LabelValues))
+		}
+	}
+
+	f.header(w, f.kind)
+	writeSamples(w, f.name, f.labels, samples)
+	return nil
+}
+
+func writeSamples(w *bufio.Writer, name string, labels []string, samples []Sample) {
+	sort.Slice(samples, func(i, j int) bool {
+		return strings.Join(samples[i].LabelValues, "\xff") < strings.Join(samples[j].LabelValues, "\xff")
+	})
+	for _, sample := range samples {
+		writeSample(w, name, labels, sample.LabelValues, sample.Value)
+	}
+}
+
+func writeSample(w *bufio.Writer, name string, labels []string, labelValues []string, value float64) {
+	w.WriteString(name)
+	if len(labels) > 0 {
+		w.WriteByte('{')
+		for index, label := range labels {
+			if index > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(label)
+			w.WriteString(`="`)
+			w.WriteString(escapeLabelValue(labelValues[index]))
+			w.WriteByte('"')
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatValue(value))
+	w.WriteByte('\n')
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelEscaper.Replace(value)
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Default is the registry served on /metrics by both backends.
+var Default = NewRegistry()
+
+var (
+	HTTPRequests = Default.NewCounter(
+		"http_requests_total",
+		"HTTP requests served, by method, route and status code.",
+		"method", "route", "status",
+	)
+	HTTPRequestDuration = Default.NewHistogram(
+		"http_request_duration_seconds",
+		"HTTP request latency in seconds, by method and route.",
+		DefaultBuckets,
+		"method", "route",
+	)
+	LoginAttempts = Default.NewCounter(
+		"auth_login_attempts_total",
+		"Login attempts by audience (admin or client) and outcome (success or failure).",
+		"audience", "outcome",
+	)
+	RateLimited = Default.NewCounter(
+		"http_rate_limited_total",
+		"Requests refused with 429 by the rate limits, by policy.",
+		"policy",
+	)
+	ZipCodeLookups = Default.NewCounter(
+		"zipcode_lookups_total",
+		"CEP lookups by where the answer came from: memory, database, stale, a provider name, or unavailable.",
+		"source",
+	)
+)
+
+const (
+	LoginSuccess = "success"
+	LoginFailure = "failure"
+)
+
+// RegisterDBStats exposes the database/sql pool statistics of db.
+func RegisterDBStats(registry *Registry, db *sql.DB) {
+	gauge := func(name, help string, value func(sql.DBStats) float64) {
+		registry.NewGaugeFunc(name, help, nil, func(context.Context) ([]Sample, error) {
+			return []Sample{{Value: value(db.Stats())}}, nil
+		})
+	}
+	counter := func(name, help string, value func(sql.DBStats) float64) {
+		registry.NewCounterFunc(name, help, nil, func(context.Context) ([]Sample, error) {
+			return []Sample{{Value: value(db.Stats())}}, nil
+		})
+	}
+
+	gauge("db_pool_max_open_connections", "Maximum number of open connections to the database.",
+		func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) })
+	gauge("db_pool_open_connections", "Established connections, both in use and idle.",
+		func(s sql.DBStats) float64 { return float64(s.OpenConnections) })
+	gauge("db_pool_in_use_connections", "Connections currently in use.",
+		func(s sql.DBStats) float64 { return float64(s.InUse) })
+	gauge("db_pool_idle_connections", "Idle connections.",
+		func(s sql.DBStats) float64 { return float64(s.Idle) })
+	counter("db_pool_wait_count_total", "Connections waited for.",
+		func(s sql.DBStats) float64 { return float64(s.WaitCount) })
+	counter("db_pool_wait_duration_seconds_total", "Time blocked waiting for a new connection.",
+		func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() })
+	counter("db_pool_max_idle_closed_total", "Connections closed due to SetMaxIdleConns.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) })
+	counter("db_pool_max_idle_time_closed_total", "Connections closed due to SetConnMaxIdleTime.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) })
+	counter("db_pool_max_lifetime_closed_total", "Connections closed due to SetConnMaxLifetime.",
+		func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) })
+}
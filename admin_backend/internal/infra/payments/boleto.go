@@ -0,0 +1,117 @@
+package payments
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+var boletoFactorBaseDate = time.Date(1997, time.October, 7, 0, 0, 0, 0, time.UTC)
+
+// buildBoleto lays out a FEBRABAN barcode (44 digits) and its digitable line
+// (47 digits). The 25-digit free field is derived from the provider reference
+// since stub boletos are not registered with any bank.
+func buildBoleto(bankCode string, amount float64, dueOn time.Time, reference string) (string, string, error) {
+	cents := int64(math.Round(amount * 100))
+	if len(bankCode) != 3 || !isDigits(bankCode) || cents <= 0 || cents > 9999999999 {
+		return "", "", fmt.Errorf("invalid boleto data")
+	}
+
+	factor := boletoDueFactor(dueOn)
+	amountField := fmt.Sprintf("%010d", cents)
+	freeField := boletoFreeField(reference)
+
+	withoutDV := bankCode + "9" + fmt.Sprintf("%04d", factor) + amountField + freeField
+	generalDV := boletoBarcodeDV(withoutDV)
+	barcode := withoutDV[:4] + generalDV + withoutDV[4:]
+
+	field1 := bankCode + "9" + freeField[:5]
+	field1 += boletoMod10(field1)
+	field2 := freeField[5:15]
+	field2 += boletoMod10(field2)
+	field3 := freeField[15:25]
+	field3 += boletoMod10(field3)
+	field5 := barcode[5:19]
+
+	digitableLine := fmt.Sprintf(
+		"%s.%s %s.%s %s.%s %s %s",
+		field1[:5], field1[5:],
+		field2[:5], field2[5:],
+		field3[:5], field3[5:],
+		generalDV,
+		field5,
+	)
+
+	return barcode, digitableLine, nil
+}
+
+// boletoDueFactor counts days from 1997-10-07. The factor wrapped after 9999
+// on 2025-02-21 and restarted at 1000, per FEBRABAN's rollover rule.
+func boletoDueFactor(dueOn time.Time) int {
+	day := time.Date(dueOn.Year(), dueOn.Month(), dueOn.Day(), 0, 0, 0, 0, time.UTC)
+	factor := int(day.Sub(boletoFactorBaseDate).Hours() / 24)
+	if factor > 9999 {
+		factor = (factor-10000)%9000 + 1000
+	}
+	if factor < 1000 {
+		factor = 1000
+	}
+	return factor
+}
+
+func boletoFreeField(reference string) string {
+	sum := sha256.Sum256([]byte(reference))
+	var builder strings.Builder
+	for _, b := range sum {
+		builder.WriteString(fmt.Sprintf("%d", b%10))
+		if builder.Len() == 25 {
+			break
+		}
+	}
+	return builder.String()
+}
+
+func boletoBarcodeDV(digits string) string {
+	sum := 0
+	weight := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+
+	dv := 11 - sum%11
+	if dv == 0 || dv == 10 || dv == 11 {
+		dv = 1
+	}
+	return fmt.Sprintf("%d", dv)
+}
+
+func boletoMod10(digits string) string {
+	sum := 0
+	weight := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		product := int(digits[i]-'0') * weight
+		sum += product/10 + product%10
+		if weight == 2 {
+			weight = 1
+		} else {
+			weight = 2
+		}
+	}
+
+	return fmt.Sprintf("%d", (10-sum%10)%10)
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
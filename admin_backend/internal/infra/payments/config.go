@@ -0,0 +1,28 @@
+package payments
+
+import (
+	"fmt"
+	"strings"
+
+	"admin_backend/internal/usecase"
+)
+
+type Config struct {
+	Provider        string
+	PixKey          string
+	PixMerchantName string
+	PixMerchantCity string
+	BoletoBankCode  string
+	WebhookSecret   string
+}
+
+// New builds the payment provider selected by PAYMENT_PROVIDER. Only the
+// fake provider ships today; real gateways plug in here.
+func New(config Config) (usecase.PaymentProvider, error) {
+	switch strings.ToLower(strings.TrimSpace(config.Provider)) {
+	case "", FakeProviderName:
+		return NewFakeProvider(config), nil
+	default:
+		return nil, fmt.Errorf("unsupported payment provider %q", config.Provider)
+	}
+}
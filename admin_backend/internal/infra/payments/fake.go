@@ -0,0 +1,135 @@
+package payments
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"admin_backend/internal/infra/signature"
+	"admin_backend/internal/usecase"
+)
+
+const FakeProviderName = "fake"
+
+// FakeProvider issues real static PIX BR Codes against the configured key
+// and unregistered boleto stubs. Webhooks use the same HMAC scheme a real
+// gateway adapter is expected to follow, so the reconciliation path can be
+// exercised end to end without external services.
+type FakeProvider struct {
+	config Config
+	now    func() time.Time
+}
+
+func NewFakeProvider(config Config) *FakeProvider {
+	return &FakeProvider{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+type fakeWebhookPayload struct {
+	ID   string                 `json:"id"`
+	Type string                 `json:"type"`
+	Data fakeWebhookPaymentData `json:"data"`
+}
+
+type fakeWebhookPaymentData struct {
+	Reference string    `json:"reference"`
+	Amount    float64   `json:"amount"`
+	PaidAt    time.Time `json:"paidAt"`
+}
+
+func (p *FakeProvider) Name() string {
+	return FakeProviderName
+}
+
+func (p *FakeProvider) CreatePaymentInstruction(
+	_ context.Context,
+	request usecase.PaymentInstructionRequest,
+) (usecase.PaymentProviderInstruction, error) {
+	reference, err := randomReference("FK", 11)
+	if err != nil {
+		return usecase.PaymentProviderInstruction{}, err
+	}
+
+	result := usecase.PaymentProviderInstruction{ProviderReference: reference}
+	switch request.Method {
+	case usecase.PaymentMethodPix:
+		copyPaste, err := usecase.BuildPixBRCode(usecase.PixBRCodeInput{
+			Key:          p.config.PixKey,
+			MerchantName: p.config.PixMerchantName,
+			MerchantCity: p.config.PixMerchantCity,
+			Amount:       request.Amount,
+			TxID:         reference,
+		})
+		if err != nil {
+			return usecase.PaymentProviderInstruction{}, err
+		}
+		result.PixCopyPaste = copyPaste
+	case usecase.PaymentMethodBoleto:
+		barcode, digitableLine, err := buildBoleto(p.config.BoletoBankCode, request.Amount, request.DueOn, reference)
+		if err != nil {
+			return usecase.PaymentProviderInstruction{}, usecase.ErrInvalidInput
+		}
+		result.BoletoBarcode = barcode
+		result.BoletoDigitableLine = digitableLine
+	default:
+		return usecase.PaymentProviderInstruction{}, usecase.ErrInvalidInput
+	}
+
+	return result, nil
+}
+
+func (p *FakeProvider) ParseWebhook(payload []byte, payloadSignature string) (usecase.PaymentWebhookEvent, error) {
+	if !signature.Verify(p.config.WebhookSecret, payload, payloadSignature) {
+		return usecase.PaymentWebhookEvent{}, fmt.Errorf("invalid webhook signature")
+	}
+
+	var body fakeWebhookPayload
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return usecase.PaymentWebhookEvent{}, err
+	}
+
+	return usecase.PaymentWebhookEvent{
+		EventID:           strings.TrimSpace(body.ID),
+		Type:              strings.TrimSpace(body.Type),
+		ProviderReference: strings.TrimSpace(body.Data.Reference),
+		AmountPaid:        body.Data.Amount,
+		PaidAt:            body.Data.PaidAt,
+	}, nil
+}
+
+func (p *FakeProvider) SimulatePayment(instruction usecase.PaymentInstruction) ([]byte, string, error) {
+	eventID, err := randomReference("evt_", 12)
+	if err != nil {
+		return nil, "", err
+	}
+
+	payload, err := json.Marshal(fakeWebhookPayload{
+		ID:   eventID,
+		Type: usecase.PaymentWebhookEventPaymentConfirmed,
+		Data: fakeWebhookPaymentData{
+			Reference: instruction.ProviderReference,
+			Amount:    instruction.Amount,
+			PaidAt:    p.now().UTC(),
+		},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return payload, signature.Sign(p.config.WebhookSecret, payload), nil
+}
+
+func randomReference(prefix string, size int) (string, error) {
+	buffer := make([]byte, size)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+
+	return prefix + strings.ToUpper(hex.EncodeToString(buffer)), nil
+}
@@ -0,0 +1,570 @@
+// Package qrcode renders QR Code symbols (ISO/IEC 18004) in byte mode with
+// error correction level M, which is what PIX BR Codes require. It supports
+// versions 1 to 20, enough for any static or dynamic BR Code payload.
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+var ErrContentTooLong = errors.New("qrcode content too long")
+
+const (
+	maxVersion   = 20
+	quietZone    = 4
+	defaultScale = 6
+)
+
+type versionInfo struct {
+	ecPerBlock   int
+	group1Blocks int
+	group1Data   int
+	group2Blocks int
+	group2Data   int
+	alignments   []int
+}
+
+// versionsLevelM holds the block structure for error correction level M.
+var versionsLevelM = [maxVersion + 1]versionInfo{
+	1:  {10, 1, 16, 0, 0, nil},
+	2:  {16, 1, 28, 0, 0, []int{6, 18}},
+	3:  {26, 1, 44, 0, 0, []int{6, 22}},
+	4:  {18, 2, 32, 0, 0, []int{6, 26}},
+	5:  {24, 2, 43, 0, 0, []int{6, 30}},
+	6:  {16, 4, 27, 0, 0, []int{6, 34}},
+	7:  {18, 4, 31, 0, 0, []int{6, 22, 38}},
+	8:  {22, 2, 38, 2, 39, []int{6, 24, 42}},
+	9:  {22, 3, 36, 2, 37, []int{6, 26, 46}},
+	10: {26, 4, 43, 1, 44, []int{6, 28, 50}},
+	11: {30, 1, 50, 4, 51, []int{6, 30, 54}},
+	12: {22, 6, 36, 2, 37, []int{6, 32, 58}},
+	13: {22, 8, 37, 1, 38, []int{6, 34, 62}},
+	14: {24, 4, 40, 5, 41, []int{6, 26, 46, 66}},
+	15: {24, 5, 41, 5, 42, []int{6, 26, 48, 70}},
+	16: {28, 7, 45, 3, 46, []int{6, 26, 50, 74}},
+	17: {28, 10, 46, 1, 47, []int{6, 30, 54, 78}},
+	18: {26, 9, 43, 4, 44, []int{6, 30, 56, 82}},
+	19: {26, 3, 44, 11, 45, []int{6, 30, 58, 86}},
+	20: {26, 3, 41, 13, 42, []int{6, 34, 62, 90}},
+}
+
+func (v versionInfo) dataCodewords() int {
+	return v.group1Blocks*v.group1Data + v.group2Blocks*v.group2Data
+}
+
+// Symbol is an encoded QR Code; Modules[y][x] is true for dark modules.
+type Symbol struct {
+	Version int
+	Size    int
+	Modules [][]bool
+}
+
+type Renderer struct {
+	scale int
+}
+
+func NewRenderer(scale int) *Renderer {
+	if scale <= 0 {
+		scale = defaultScale
+	}
+	return &Renderer{scale: scale}
+}
+
+func (r *Renderer) RenderPNG(content string) ([]byte, error) {
+	symbol, err := Encode([]byte(content))
+	if err != nil {
+		return nil, err
+	}
+
+	return symbol.PNG(r.scale)
+}
+
+func Encode(content []byte) (Symbol, error) {
+	version := 0
+	for candidate := 1; candidate <= maxVersion; candidate++ {
+		if dataBitsNeeded(content, candidate) <= versionsLevelM[candidate].dataCodewords()*8 {
+			version = candidate
+			break
+		}
+	}
+	if version == 0 {
+		return Symbol{}, ErrContentTooLong
+	}
+
+	codewords := addErrorCorrection(encodeData(content, version), versionsLevelM[version])
+
+	builder := newMatrixBuilder(version)
+	builder.drawFunctionPatterns()
+	builder.drawCodewords(codewords)
+
+	bestMask := 0
+	bestPenalty := -1
+	for mask := 0; mask < 8; mask++ {
+		builder.applyMask(mask)
+		builder.drawFormatBits(mask)
+		penalty := builder.penalty()
+		if bestPenalty < 0 || penalty < bestPenalty {
+			bestMask = mask
+			bestPenalty = penalty
+		}
+		builder.applyMask(mask)
+	}
+	builder.applyMask(bestMask)
+	builder.drawFormatBits(bestMask)
+
+	return Symbol{
+		Version: version,
+		Size:    builder.size,
+		Modules: builder.modules,
+	}, nil
+}
+
+func (s Symbol) PNG(scale int) ([]byte, error) {
+	if scale <= 0 {
+		scale = defaultScale
+	}
+
+	side := (s.Size + quietZone*2) * scale
+	img := image.NewPaletted(
+		image.Rect(0, 0, side, side),
+		color.Palette{color.White, color.Black},
+	)
+	for y := 0; y < s.Size; y++ {
+		for x := 0; x < s.Size; x++ {
+			if !s.Modules[y][x] {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex((x+quietZone)*scale+dx, (y+quietZone)*scale+dy, 1)
+				}
+			}
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func characterCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+func dataBitsNeeded(content []byte, version int) int {
+	return 4 + characterCountBits(version) + len(content)*8
+}
+
+func encodeData(content []byte, version int) []byte {
+	capacityBits := versionsLevelM[version].dataCodewords() * 8
+	bits := bitBuffer{}
+	bits.append(0x4, 4)
+	bits.append(len(content), characterCountBits(version))
+	for _, b := range content {
+		bits.append(int(b), 8)
+	}
+
+	terminator := capacityBits - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	if remainder := len(bits) % 8; remainder != 0 {
+		bits.append(0, 8-remainder)
+	}
+	for pad := 0xEC; len(bits) < capacityBits; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	data := make([]byte, len(bits)/8)
+	for index, bit := range bits {
+		if bit {
+			data[index>>3] |= 1 << (7 - uint(index&7))
+		}
+	}
+
+	return data
+}
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value int, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, (value>>uint(i))&1 == 1)
+	}
+}
+
+// addErrorCorrection splits data into blocks, computes Reed-Solomon codewords
+// for each one and interleaves the result as the standard requires.
+func addErrorCorrection(data []byte, info versionInfo) []byte {
+	divisor := reedSolomonDivisor(info.ecPerBlock)
+
+	blocks := make([][]byte, 0, info.group1Blocks+info.group2Blocks)
+	eccBlocks := make([][]byte, 0, cap(blocks))
+	offset := 0
+	for _, group := range [][2]int{
+		{info.group1Blocks, info.group1Data},
+		{info.group2Blocks, info.group2Data},
+	} {
+		for i := 0; i < group[0]; i++ {
+			block := data[offset : offset+group[1]]
+			offset += group[1]
+			blocks = append(blocks, block)
+			eccBlocks = append(eccBlocks, reedSolomonRemainder(block, divisor))
+		}
+	}
+
+	result := make([]byte, 0, offset+len(blocks)*info.ecPerBlock)
+	longest := info.group1Data
+	if info.group2Data > longest {
+		longest = info.group2Data
+	}
+	for i := 0; i < longest; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < info.ecPerBlock; i++ {
+		for _, block := range eccBlocks {
+			result = append(result, block[i])
+		}
+	}
+
+	return result
+}
+
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := 0; j < degree; j++ {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < degree {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+
+	return result
+}
+
+func reedSolomonRemainder(data []byte, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= gfMultiply(divisor[i], factor)
+		}
+	}
+
+	return result
+}
+
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+
+	return byte(z)
+}
+
+type matrixBuilder struct {
+	version    int
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+func newMatrixBuilder(version int) *matrixBuilder {
+	size := version*4 + 17
+	modules := make([][]bool, size)
+	isFunction := make([][]bool, size)
+	for i := range modules {
+		modules[i] = make([]bool, size)
+		isFunction[i] = make([]bool, size)
+	}
+
+	return &matrixBuilder{
+		version:    version,
+		size:       size,
+		modules:    modules,
+		isFunction: isFunction,
+	}
+}
+
+func (m *matrixBuilder) setFunction(x, y int, dark bool) {
+	m.modules[y][x] = dark
+	m.isFunction[y][x] = true
+}
+
+func (m *matrixBuilder) drawFunctionPatterns() {
+	for i := 0; i < m.size; i++ {
+		m.setFunction(6, i, i%2 == 0)
+		m.setFunction(i, 6, i%2 == 0)
+	}
+
+	m.drawFinderPattern(3, 3)
+	m.drawFinderPattern(m.size-4, 3)
+	m.drawFinderPattern(3, m.size-4)
+
+	alignments := versionsLevelM[m.version].alignments
+	last := len(alignments) - 1
+	for i, x := range alignments {
+		for j, y := range alignments {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			m.drawAlignmentPattern(x, y)
+		}
+	}
+
+	m.drawFormatBits(0)
+	m.drawVersion()
+}
+
+func (m *matrixBuilder) drawFinderPattern(centerX, centerY int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x := centerX + dx
+			y := centerY + dy
+			if x < 0 || x >= m.size || y < 0 || y >= m.size {
+				continue
+			}
+			distance := maxInt(absInt(dx), absInt(dy))
+			m.setFunction(x, y, distance != 2 && distance != 4)
+		}
+	}
+}
+
+func (m *matrixBuilder) drawAlignmentPattern(centerX, centerY int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			m.setFunction(centerX+dx, centerY+dy, maxInt(absInt(dx), absInt(dy)) != 1)
+		}
+	}
+}
+
+func (m *matrixBuilder) drawFormatBits(mask int) {
+	// Level M is encoded as 00, so the data bits are just the mask.
+	data := mask
+	remainder := data
+	for i := 0; i < 10; i++ {
+		remainder = (remainder << 1) ^ ((remainder >> 9) * 0x537)
+	}
+	bits := (data<<10 | remainder) ^ 0x5412
+
+	bit := func(index int) bool {
+		return (bits>>uint(index))&1 == 1
+	}
+
+	for i := 0; i <= 5; i++ {
+		m.setFunction(8, i, bit(i))
+	}
+	m.setFunction(8, 7, bit(6))
+	m.setFunction(8, 8, bit(7))
+	m.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		m.setFunction(m.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.setFunction(8, m.size-15+i, bit(i))
+	}
+	m.setFunction(8, m.size-8, true)
+}
+
+func (m *matrixBuilder) drawVersion() {
+	if m.version < 7 {
+		return
+	}
+
+	remainder := m.version
+	for i := 0; i < 12; i++ {
+		remainder = (remainder << 1) ^ ((remainder >> 11) * 0x1F25)
+	}
+	bits := m.version<<12 | remainder
+
+	for i := 0; i < 18; i++ {
+		dark := (bits>>uint(i))&1 == 1
+		a := m.size - 11 + i%3
+		b := i / 3
+		m.setFunction(a, b, dark)
+		m.setFunction(b, a, dark)
+	}
+}
+
+func (m *matrixBuilder) drawCodewords(codewords []byte) {
+	index := 0
+	totalBits := len(codewords) * 8
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vertical := 0; vertical < m.size; vertical++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				upward := ((right + 1) & 2) == 0
+				y := vertical
+				if upward {
+					y = m.size - 1 - vertical
+				}
+				if m.isFunction[y][x] || index >= totalBits {
+					continue
+				}
+				m.modules[y][x] = (codewords[index>>3]>>(7-uint(index&7)))&1 == 1
+				index++
+			}
+		}
+	}
+}
+
+// applyMask XORs the data modules with a mask pattern; applying the same mask
+// twice restores the original matrix.
+func (m *matrixBuilder) applyMask(mask int) {
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if m.isFunction[y][x] {
+				continue
+			}
+
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				m.modules[y][x] = !m.modules[y][x]
+			}
+		}
+	}
+}
+
+func (m *matrixBuilder) penalty() int {
+	penalty := 0
+	at := func(x, y int, vertical bool) bool {
+		if vertical {
+			return m.modules[x][y]
+		}
+		return m.modules[y][x]
+	}
+
+	for _, vertical := range []bool{false, true} {
+		for line := 0; line < m.size; line++ {
+			run := 1
+			for i := 1; i < m.size; i++ {
+				if at(i, line, vertical) == at(i-1, line, vertical) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					penalty += 3 + run - 5
+				}
+				run = 1
+			}
+			if run >= 5 {
+				penalty += 3 + run - 5
+			}
+
+			for i := 0; i+10 < m.size; i++ {
+				if matchesFinderLike(func(offset int) bool { return at(i+offset, line, vertical) }) {
+					penalty += 40
+				}
+			}
+		}
+	}
+
+	dark := 0
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if m.modules[y][x] {
+				dark++
+			}
+			if x+1 < m.size && y+1 < m.size {
+				color := m.modules[y][x]
+				if color == m.modules[y][x+1] &&
+					color == m.modules[y+1][x] &&
+					color == m.modules[y+1][x+1] {
+					penalty += 3
+				}
+			}
+		}
+	}
+
+	total := m.size * m.size
+	deviation := absInt(dark*20-total*10) / total
+	penalty += deviation * 10
+
+	return penalty
+}
+
+var finderLikePatterns = [2][11]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+func matchesFinderLike(module func(offset int) bool) bool {
+	for _, pattern := range finderLikePatterns {
+		matched := true
+		for offset, expected := range pattern {
+			if module(offset) != expected {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func absInt(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+func maxInt(left, right int) int {
+	if left > right {
+		return left
+	}
+	return right
+}
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	StoreMemory   = "memory"
+	StorePostgres = "postgres"
+	StoreRedis    = "redis"
+)
+
+type Config struct {
+	Enabled bool
+	// Store is memory, postgres or redis. Only the last two share limits
+	// across replicas.
+	Store    string
+	RedisURL string
+	// TrustedProxies are the load balancers whose X-Forwarded-For is
+	// believed when finding the client IP.
+	TrustedProxies []netip.Prefix
+
+	// Limits of the route policies.
+	Login        Limit
+	Register     Limit
+	Lookups      Limit
+	PortalWrites Limit
+}
+
+// New builds the store selected by config.Store. database backs the
+// postgres store.
+func New(config Config, database *sqlx.DB) (Store, error) {
+	switch config.Store {
+	case "", StoreMemory:
+		return NewMemoryStore(), nil
+	case StorePostgres:
+		return NewPostgresStore(database), nil
+	case StoreRedis:
+		return NewRedisStore(config.RedisURL)
+	default:
+		return nil, fmt.Errorf("unsupported rate limit store %q", config.Store)
+	}
+}
@@ -0,0 +1,115 @@
+// Package ratelimit implements token buckets kept in memory, in Postgres or
+// in a Redis-compatible server, so limits can hold across replicas.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests per Per on average, with bursts of up to Burst
+// requests after a quiet period.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+// ParseLimit reads "N/duration", e.g. "10/1m", optionally followed by
+// ",burst=M"; the burst defaults to N.
+func ParseLimit(value string) (Limit, error) {
+	rate, burst, hasBurst := strings.Cut(strings.TrimSpace(value), ",")
+	requests, per, ok := strings.Cut(rate, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("%q is not a rate such as 10/1m", value)
+	}
+
+	limit := Limit{}
+	var err error
+	if limit.Requests, err = strconv.Atoi(strings.TrimSpace(requests)); err != nil || limit.Requests < 1 {
+		return Limit{}, fmt.Errorf("%q: the request count must be a positive number", value)
+	}
+	if limit.Per, err = time.ParseDuration(strings.TrimSpace(per)); err != nil || limit.Per <= 0 {
+		return Limit{}, fmt.Errorf("%q: the period must be a positive duration", value)
+	}
+
+	limit.Burst = limit.Requests
+	if hasBurst {
+		name, count, _ := strings.Cut(strings.TrimSpace(burst), "=")
+		if strings.TrimSpace(name) != "burst" {
+			return Limit{}, fmt.Errorf("%q: expected burst=N after the comma", value)
+		}
+		if limit.Burst, err = strconv.Atoi(strings.TrimSpace(count)); err != nil || limit.Burst < 1 {
+			return Limit{}, fmt.Errorf("%q: the burst must be a positive number", value)
+		}
+	}
+	return limit, nil
+}
+
+func (l Limit) String() string {
+	value := strconv.Itoa(l.Requests) + "/" + l.Per.String()
+	if l.Burst != l.Requests {
+		value += ",burst=" + strconv.Itoa(l.Burst)
+	}
+	return value
+}
+
+// ratePerSecond is how fast an emptied bucket refills.
+func (l Limit) ratePerSecond() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// Decision is the outcome of taking one token.
+type Decision struct {
+	Allowed bool
+	// Remaining is the number of requests allowed right now after this one.
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed; zero while
+	// tokens remain.
+	RetryAfter time.Duration
+}
+
+// Store takes tokens from the bucket named key.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Decision, error)
+	Close() error
+}
+
+// decide turns the tokens left in a bucket into a Decision.
+func decide(limit Limit, allowed bool, tokens float64) Decision {
+	rate := limit.ratePerSecond()
+	decision := Decision{
+		Allowed:   allowed,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((float64(limit.Burst) - tokens) / rate),
+	}
+	if tokens < 1 {
+		decision.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+	return decision
+}
+
+// refill adds the tokens earned over elapsed, up to the burst, and takes one
+// if available.
+func refill(limit Limit, tokens float64, elapsed time.Duration) (float64, bool) {
+	if elapsed > 0 {
+		tokens = math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.ratePerSecond())
+	}
+	if tokens >= 1 {
+		return tokens - 1, true
+	}
+	return tokens, false
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
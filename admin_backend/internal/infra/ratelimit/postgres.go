@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	postgresPurgeInterval = 10 * time.Minute
+	// postgresRetention must exceed the longest refill time of any policy;
+	// older rows belong to full buckets.
+	postgresRetention = 24 * time.Hour
+)
+
+// PostgresStore keeps buckets in the rate_limit_buckets table, shared by
+// every replica, through the rate_limit_take function. Refills use the
+// database clock so replicas with skewed clocks agree.
+type PostgresStore struct {
+	db *sqlx.DB
+
+	mu        sync.Mutex
+	lastPurge time.Time
+}
+
+func NewPostgresStore(db *sqlx.DB) *PostgresStore {
+	return &PostgresStore{db: db, lastPurge: time.Now()}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Decision, error) {
+	var row struct {
+		Tokens  float64 `db:"remaining_tokens"`
+		Allowed bool    `db:"allowed"`
+	}
+	if err := s.db.GetContext(
+		ctx,
+		&row,
+		"SELECT remaining_tokens, allowed FROM rate_limit_take($1, $2, $3)",
+		key,
+		float64(limit.Burst),
+		limit.ratePerSecond(),
+	); err != nil {
+		return Decision{}, err
+	}
+
+	s.maybePurge()
+	return decide(limit, row.Allowed, row.Tokens), nil
+}
+
+// maybePurge deletes long-idle buckets in the background every few minutes.
+func (s *PostgresStore) maybePurge() {
+	s.mu.Lock()
+	if time.Since(s.lastPurge) < postgresPurgeInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPurge = time.Now()
+	s.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if _, err := s.db.ExecContext(
+			ctx,
+			"DELETE FROM rate_limit_buckets WHERE updated_at < now() - make_interval(secs => $1)",
+			postgresRetention.Seconds(),
+		); err != nil {
+			slog.WarnContext(ctx, "purge rate limit buckets", "error", err)
+		}
+	}()
+}
+
+// Close leaves the pool to its owner.
+func (s *PostgresStore) Close() error {
+	return nil
+}
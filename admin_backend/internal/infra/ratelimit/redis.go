@@ -0,0 +1,296 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	redisDialTimeout    = 2 * time.Second
+	redisCommandTimeout = time.Second
+	redisMaxIdleConns   = 8
+)
+
+// takeScript is the token bucket run atomically on the server, using the
+// server clock. Buckets expire once they would be full again.
+const takeScript = `
+local burst = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2]) / 1000
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) * 1000 + math.floor(tonumber(clock[2]) / 1000)
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+if now > ts then
+  tokens = math.min(burst, tokens + (now - ts) * rate)
+end
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate) + 1000)
+return {allowed, tostring(tokens)}
+`
+
+// RedisStore keeps buckets in a Redis-compatible server (Redis, Valkey,
+// KeyDB, ...) shared by every replica. It speaks RESP directly.
+type RedisStore struct {
+	address   string
+	useTLS    bool
+	username  string
+	password  string
+	database  int
+	keyPrefix string
+
+	mu     sync.Mutex
+	idle   []*redisConn
+	closed bool
+}
+
+type redisConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedisStore parses rawURL, redis://[user:password@]host:port[/db] or
+// rediss:// for TLS. Connections are opened on demand, so the server being
+// down at boot only lets requests through until it is back.
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "redis" && parsed.Scheme != "rediss") || parsed.Host == "" {
+		return nil, errors.New("rate limit redis URL must look like redis://host:6379/0")
+	}
+
+	store := &RedisStore{
+		address:   parsed.Host,
+		useTLS:    parsed.Scheme == "rediss",
+		keyPrefix: "ratelimit:",
+	}
+	if parsed.Port() == "" {
+		store.address = net.JoinHostPort(parsed.Hostname(), "6379")
+	}
+	if parsed.User != nil {
+		store.username = parsed.User.Username()
+		store.password, _ = parsed.User.Password()
+	}
+	if path := strings.Trim(parsed.Path, "/"); path != "" {
+		if store.database, err = strconv.Atoi(path); err != nil || store.database < 0 {
+			return nil, fmt.Errorf("invalid redis database %q", path)
+		}
+	}
+	return store, nil
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit) (Decision, error) {
+	reply, err := s.do(
+		ctx,
+		"EVAL", takeScript, "1", s.keyPrefix+key,
+		strconv.Itoa(limit.Burst),
+		strconv.FormatFloat(limit.ratePerSecond(), 'g', -1, 64),
+	)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return Decision{}, fmt.Errorf("unexpected rate limit script reply %v", reply)
+	}
+	allowed, _ := values[0].(int64)
+	remaining, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(remaining, 64)
+	if err != nil {
+		return Decision{}, fmt.Errorf("unexpected rate limit script reply %v", reply)
+	}
+	return decide(limit, allowed == 1, tokens), nil
+}
+
+func (s *RedisStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for _, conn := range s.idle {
+		_ = conn.Close()
+	}
+	s.idle = nil
+	return nil
+}
+
+// do sends one command and reads its reply. A connection is only reused
+// after a complete exchange, so a timeout never leaves a stale reply behind.
+func (s *RedisStore) do(ctx context.Context, args ...string) (interface{}, error) {
+	conn, err := s.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(redisCommandTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	_ = conn.SetDeadline(deadline)
+
+	reply, err := conn.command(args...)
+	var serverErr redisError
+	if err != nil && !errors.As(err, &serverErr) {
+		_ = conn.Close()
+		return nil, err
+	}
+	s.put(conn)
+	return reply, err
+}
+
+func (s *RedisStore) get(ctx context.Context) (*redisConn, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, errors.New("rate limit redis store closed")
+	}
+	if count := len(s.idle); count > 0 {
+		conn := s.idle[count-1]
+		s.idle = s.idle[:count-1]
+		s.mu.Unlock()
+		return conn, nil
+	}
+	s.mu.Unlock()
+
+	return s.dial(ctx)
+}
+
+func (s *RedisStore) put(conn *redisConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || len(s.idle) >= redisMaxIdleConns {
+		_ = conn.Close()
+		return
+	}
+	s.idle = append(s.idle, conn)
+}
+
+func (s *RedisStore) dial(ctx context.Context) (*redisConn, error) {
+	dialer := &net.Dialer{Timeout: redisDialTimeout}
+	var (
+		raw net.Conn
+		err error
+	)
+	if s.useTLS {
+		host, _, _ := net.SplitHostPort(s.address)
+		raw, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host}}).DialContext(ctx, "tcp", s.address)
+	} else {
+		raw, err = dialer.DialContext(ctx, "tcp", s.address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &redisConn{Conn: raw, reader: bufio.NewReader(raw)}
+	_ = conn.SetDeadline(time.Now().Add(redisDialTimeout))
+	if s.password != "" {
+		args := []string{"AUTH", s.password}
+		if s.username != "" {
+			args = []string{"AUTH", s.username, s.password}
+		}
+		if _, err := conn.command(args...); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis auth: %w", err)
+		}
+	}
+	if s.database != 0 {
+		if _, err := conn.command("SELECT", strconv.Itoa(s.database)); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("redis select: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// redisError is an error reply from the server; the connection stays
+// usable after one.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+func (c *redisConn) command(args ...string) (interface{}, error) {
+	var request strings.Builder
+	fmt.Fprintf(&request, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&request, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.Conn, request.String()); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+func (c *redisConn) readReply() (interface{}, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		buffer := make([]byte, size+2)
+		if _, err := io.ReadFull(c.reader, buffer); err != nil {
+			return nil, err
+		}
+		return string(buffer[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		values := make([]interface{}, count)
+		for index := range values {
+			// Errors nested in arrays are returned as values.
+			value, err := c.readReply()
+			var serverErr redisError
+			if err != nil && !errors.As(err, &serverErr) {
+				return nil, err
+			}
+			if err != nil {
+				value = serverErr
+			}
+			values[index] = value
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
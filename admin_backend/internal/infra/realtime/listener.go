@@ -0,0 +1,153 @@
+package realtime
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"admin_backend/internal/usecase"
+	"github.com/lib/pq"
+)
+
+// Channel is the Postgres NOTIFY channel written by the realtime triggers.
+const Channel = "realtime_events"
+
+const subscriberBuffer = 64
+
+// Hub keeps one LISTEN connection per process and fans the received events
+// out to the in-process subscribers (one per open SSE stream).
+type Hub struct {
+	listener *pq.Listener
+
+	mu          sync.Mutex
+	subscribers map[chan usecase.RealtimeEvent]struct{}
+	closed      bool
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func NewHub(dsn string) (*Hub, error) {
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Error("realtime listener event", "event", int(event), "error", err)
+		}
+	})
+	if err := listener.Listen(Channel); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	hub := &Hub{
+		listener:    listener,
+		subscribers: make(map[chan usecase.RealtimeEvent]struct{}),
+		done:        make(chan struct{}),
+	}
+	hub.wg.Add(1)
+	go hub.run()
+
+	return hub, nil
+}
+
+// Subscribe returns a channel of events, closed when the hub closes, and
+// the function that unsubscribes it.
+func (h *Hub) Subscribe() (<-chan usecase.RealtimeEvent, func()) {
+	events := make(chan usecase.RealtimeEvent, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		close(events)
+		return events, func() {}
+	}
+	h.subscribers[events] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers, events)
+			h.mu.Unlock()
+		})
+	}
+}
+
+// Close stops listening and closes every subscriber channel, which ends the
+// open SSE streams so a graceful shutdown is not held up by them. It is safe
+// to call more than once.
+func (h *Hub) Close() error {
+	h.closeOnce.Do(func() {
+		close(h.done)
+		h.closeErr = h.listener.Close()
+		h.wg.Wait()
+
+		h.mu.Lock()
+		h.closed = true
+		for subscriber := range h.subscribers {
+			close(subscriber)
+			delete(h.subscribers, subscriber)
+		}
+		h.mu.Unlock()
+	})
+	return h.closeErr
+}
+
+func (h *Hub) run() {
+	defer h.wg.Done()
+
+	for {
+		select {
+		case <-h.done:
+			return
+		case notification, ok := <-h.listener.Notify:
+			if !ok {
+				return
+			}
+			// pq sends nil after reconnecting; anything published meanwhile
+			// was lost, so ask the streams to reload.
+			if notification == nil {
+				h.broadcast(usecase.RealtimeEvent{Type: usecase.RealtimeEventResync})
+				continue
+			}
+
+			var event usecase.RealtimeEvent
+			if err := json.Unmarshal([]byte(notification.Extra), &event); err != nil || event.Type == "" {
+				slog.Warn("realtime listener invalid payload", "payload", notification.Extra)
+				continue
+			}
+			h.broadcast(event)
+		case <-time.After(90 * time.Second):
+			go func() {
+				if err := h.listener.Ping(); err != nil {
+					slog.Error("realtime listener ping failed", "error", err)
+				}
+			}()
+		}
+	}
+}
+
+// broadcast never blocks: a subscriber whose buffer is full gets a resync
+// event in place of the ones it could not take.
+func (h *Hub) broadcast(event usecase.RealtimeEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for subscriber := range h.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			select {
+			case <-subscriber:
+			default:
+			}
+			select {
+			case subscriber <- usecase.RealtimeEvent{Type: usecase.RealtimeEventResync}:
+			default:
+			}
+		}
+	}
+}
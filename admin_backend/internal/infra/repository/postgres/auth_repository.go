@@ -19,6 +19,9 @@ func NewAuthRepository(db *sqlx.DB) *AuthRepository {
 }
 
 func (r *AuthRepository) FindByLoginOrEmail(ctx context.Context, login string) (usecase.AuthUser, error) {
+	ctx, span := startSpan(ctx, "AuthRepository.FindByLoginOrEmail")
+	defer span.End()
+
 	var user struct {
 		ID       string `db:"id"`
 		Name     string `db:"name"`
@@ -64,6 +67,9 @@ func (r *AuthRepository) IsLoginInUseByAnotherUser(
 	ctx context.Context,
 	login, userID string,
 ) (bool, error) {
+	ctx, span := startSpan(ctx, "AuthRepository.IsLoginInUseByAnotherUser")
+	defer span.End()
+
 	var loginInUse bool
 	if err := r.db.GetContext(
 		ctx,
@@ -89,6 +95,9 @@ func (r *AuthRepository) UpdateAccount(
 	ctx context.Context,
 	input usecase.UpdateAccountInput,
 ) (usecase.AuthUser, error) {
+	ctx, span := startSpan(ctx, "AuthRepository.UpdateAccount")
+	defer span.End()
+
 	var user struct {
 		ID      string `db:"id"`
 		Name    string `db:"name"`
@@ -15,6 +15,9 @@ func NewAuthorizationRepository(db *sqlx.DB) *AuthorizationRepository {
 }
 
 func (r *AuthorizationRepository) IsUserAdministrator(ctx context.Context, userID string) (bool, error) {
+	ctx, span := startSpan(ctx, "AuthorizationRepository.IsUserAdministrator")
+	defer span.End()
+
 	var isAdministrator bool
 	if err := r.db.GetContext(
 		ctx,
@@ -45,6 +48,9 @@ func (r *AuthorizationRepository) HasUserPermission(
 	userID,
 	permissionCode string,
 ) (bool, error) {
+	ctx, span := startSpan(ctx, "AuthorizationRepository.HasUserPermission")
+	defer span.End()
+
 	isAdministrator, err := r.IsUserAdministrator(ctx, userID)
 	if err != nil {
 		return false, err
@@ -0,0 +1,643 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type BankStatementRepository struct {
+	db *sqlx.DB
+}
+
+func NewBankStatementRepository(db *sqlx.DB) *BankStatementRepository {
+	return &BankStatementRepository{db: db}
+}
+
+type bankStatementImportRecord struct {
+	ID            string    `db:"id"`
+	Format        string    `db:"format"`
+	FileName      string    `db:"file_name"`
+	Account       string    `db:"account"`
+	ImportedBy    string    `db:"imported_by"`
+	TotalLines    int       `db:"total_lines"`
+	ImportedLines int       `db:"imported_lines"`
+	PendingLines  int       `db:"pending_lines"`
+	Created       time.Time `db:"created"`
+}
+
+type bankStatementLineRecord struct {
+	ID                string     `db:"id"`
+	ImportID          string     `db:"import_id"`
+	Account           string     `db:"account"`
+	FitID             string     `db:"fit_id"`
+	PostedOn          time.Time  `db:"posted_on"`
+	Amount            float64    `db:"amount"`
+	Description       string     `db:"description"`
+	PayerName         string     `db:"payer_name"`
+	PayerDocument     string     `db:"payer_document"`
+	Status            string     `db:"status"`
+	MatchedSourceType string     `db:"matched_source_type"`
+	MatchedSourceID   string     `db:"matched_source_id"`
+	MatchedProjectID  string     `db:"matched_project_id"`
+	ResolvedAt        *time.Time `db:"resolved_at"`
+	ResolvedBy        string     `db:"resolved_by"`
+	Created           time.Time  `db:"created"`
+}
+
+type bankReconciliationRevenueRecord struct {
+	ID          string     `db:"id"`
+	ProjectID   string     `db:"project_id"`
+	ProjectName string     `db:"project_name"`
+	Title       string     `db:"title"`
+	Amount      float64    `db:"amount"`
+	ExpectedOn  *time.Time `db:"expected_on"`
+}
+
+type bankReconciliationProjectValueRecord struct {
+	ProjectID string `db:"project_id"`
+	Value     string `db:"value"`
+}
+
+const bankStatementImportSelectSQL = `
+SELECT
+  statement_import.id,
+  statement_import.format,
+  statement_import.file_name,
+  statement_import.account,
+  COALESCE(statement_import.imported_by::text, '') AS imported_by,
+  statement_import.total_lines,
+  statement_import.imported_lines,
+  (
+    SELECT COUNT(*)::int
+    FROM bank_statement_lines line
+    WHERE line.import_id = statement_import.id
+      AND line.status = 'pendente'
+  ) AS pending_lines,
+  statement_import.created
+FROM bank_statement_imports statement_import
+`
+
+const bankStatementLineSelectSQL = `
+SELECT
+  line.id,
+  line.import_id,
+  line.account,
+  line.fit_id,
+  line.posted_on,
+  line.amount,
+  line.description,
+  line.payer_name,
+  line.payer_document,
+  line.status,
+  line.matched_source_type,
+  COALESCE(line.project_revenue_id::text, line.project_monthly_charge_id::text, '') AS matched_source_id,
+  COALESCE(line.matched_project_id::text, '') AS matched_project_id,
+  line.resolved_at,
+  COALESCE(line.resolved_by::text, '') AS resolved_by,
+  line.created
+FROM bank_statement_lines line
+`
+
+// CreateBankStatementImport stores the import and its credits. Lines already
+// imported for the same account and bank identifier are skipped, so the
+// same statement (or overlapping periods) can be uploaded safely.
+func (r *BankStatementRepository) CreateBankStatementImport(
+	ctx context.Context,
+	input usecase.NewBankStatementImport,
+) (usecase.BankStatementImport, error) {
+	ctx, span := startSpan(ctx, "BankStatementRepository.CreateBankStatementImport")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.BankStatementImport{}, err
+	}
+	defer tx.Rollback()
+
+	var importID string
+	if err := tx.GetContext(
+		ctx,
+		&importID,
+		`
+		INSERT INTO bank_statement_imports (
+		  format,
+		  file_name,
+		  account,
+		  imported_by,
+		  total_lines,
+		  created
+		)
+		VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5, NOW())
+		RETURNING id
+		`,
+		input.Format,
+		input.FileName,
+		input.Account,
+		input.ImportedBy,
+		input.TotalLines,
+	); err != nil {
+		return usecase.BankStatementImport{}, mapBankStatementPersistenceError(err)
+	}
+
+	importedLines := 0
+	for _, line := range input.Lines {
+		result, err := tx.ExecContext(
+			ctx,
+			`
+			INSERT INTO bank_statement_lines (
+			  import_id,
+			  account,
+			  fit_id,
+			  posted_on,
+			  amount,
+			  description,
+			  payer_name,
+			  payer_document,
+			  status,
+			  created,
+			  updated
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 'pendente', NOW(), NOW())
+			ON CONFLICT (account, fit_id) DO NOTHING
+			`,
+			importID,
+			input.Account,
+			line.FitID,
+			line.PostedOn,
+			line.Amount,
+			line.Description,
+			line.PayerName,
+			line.PayerDocument,
+		)
+		if err != nil {
+			return usecase.BankStatementImport{}, mapBankStatementPersistenceError(err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return usecase.BankStatementImport{}, err
+		}
+		importedLines += int(rowsAffected)
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		"UPDATE bank_statement_imports SET imported_lines = $1 WHERE id = $2",
+		importedLines,
+		importID,
+	); err != nil {
+		return usecase.BankStatementImport{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.BankStatementImport{}, err
+	}
+
+	return r.GetBankStatementImport(ctx, importID)
+}
+
+func (r *BankStatementRepository) ListBankStatementImports(
+	ctx context.Context,
+) ([]usecase.BankStatementImport, error) {
+	ctx, span := startSpan(ctx, "BankStatementRepository.ListBankStatementImports")
+	defer span.End()
+
+	var records []bankStatementImportRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		bankStatementImportSelectSQL+"ORDER BY statement_import.created DESC, statement_import.id ASC",
+	); err != nil {
+		return nil, err
+	}
+
+	imports := make([]usecase.BankStatementImport, 0, len(records))
+	for _, record := range records {
+		imports = append(imports, mapBankStatementImportRecord(record))
+	}
+
+	return imports, nil
+}
+
+func (r *BankStatementRepository) GetBankStatementImport(
+	ctx context.Context,
+	importID string,
+) (usecase.BankStatementImport, error) {
+	ctx, span := startSpan(ctx, "BankStatementRepository.GetBankStatementImport")
+	defer span.End()
+
+	var record bankStatementImportRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		bankStatementImportSelectSQL+"WHERE statement_import.id::text = $1",
+		importID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.BankStatementImport{}, usecase.ErrNotFound
+		}
+		return usecase.BankStatementImport{}, err
+	}
+
+	return mapBankStatementImportRecord(record), nil
+}
+
+func (r *BankStatementRepository) ListBankStatementLines(
+	ctx context.Context,
+	filter usecase.BankStatementLineFilter,
+) ([]usecase.BankStatementLine, error) {
+	ctx, span := startSpan(ctx, "BankStatementRepository.ListBankStatementLines")
+	defer span.End()
+
+	var records []bankStatementLineRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		bankStatementLineSelectSQL+`
+		WHERE ($1 = '' OR line.import_id::text = $1)
+		  AND ($2 = '' OR line.status = $2)
+		ORDER BY line.posted_on ASC, line.created ASC, line.id ASC
+		`,
+		filter.ImportID,
+		filter.Status,
+	); err != nil {
+		return nil, err
+	}
+
+	lines := make([]usecase.BankStatementLine, 0, len(records))
+	for _, record := range records {
+		lines = append(lines, mapBankStatementLineRecord(record))
+	}
+
+	return lines, nil
+}
+
+// ListBankReconciliationCandidates loads every active pending revenue and
+// monthly charge, together with the names of the project's clients and the
+// payer documents already reconciled against each project.
+func (r *BankStatementRepository) ListBankReconciliationCandidates(
+	ctx context.Context,
+) ([]usecase.BankReconciliationCandidate, error) {
+	ctx, span := startSpan(ctx, "BankStatementRepository.ListBankReconciliationCandidates")
+	defer span.End()
+
+	var revenueRecords []bankReconciliationRevenueRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&revenueRecords,
+		`
+		SELECT
+		  revenue.id,
+		  revenue.project_id,
+		  project.name AS project_name,
+		  revenue.title,
+		  revenue.amount,
+		  revenue.expected_on
+		FROM project_revenues revenue
+		INNER JOIN projects project ON project.id = revenue.project_id
+		WHERE revenue.status = 'pendente'
+		  AND revenue.active = TRUE
+		`,
+	); err != nil {
+		return nil, err
+	}
+
+	var chargeRecords []projectFinancialChargeRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&chargeRecords,
+		`
+		SELECT
+		  charge.id,
+		  charge.project_id,
+		  project.name AS project_name,
+		  charge.title,
+		  charge.description,
+		  charge.installment,
+		  charge.status,
+		  charge.amount,
+		  charge.due_day,
+		  charge.starts_on,
+		  charge.ends_on,
+		  charge.received_on,
+		  charge.active,
+		  charge.created,
+		  charge.updated
+		FROM project_monthly_charges charge
+		INNER JOIN projects project ON project.id = charge.project_id
+		WHERE charge.status = 'pendente'
+		  AND charge.active = TRUE
+		`,
+	); err != nil {
+		return nil, err
+	}
+
+	var clientNameRecords []bankReconciliationProjectValueRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&clientNameRecords,
+		`
+		SELECT project_client.project_id, client.name AS value
+		FROM project_clients project_client
+		INNER JOIN clients client ON client.id = project_client.client_id
+		`,
+	); err != nil {
+		return nil, err
+	}
+
+	var documentRecords []bankReconciliationProjectValueRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&documentRecords,
+		`
+		SELECT DISTINCT line.matched_project_id AS project_id, line.payer_document AS value
+		FROM bank_statement_lines line
+		WHERE line.status = 'conciliado'
+		  AND line.payer_document <> ''
+		  AND line.matched_project_id IS NOT NULL
+		`,
+	); err != nil {
+		return nil, err
+	}
+
+	clientNames := groupBankReconciliationValues(clientNameRecords)
+	documents := groupBankReconciliationValues(documentRecords)
+
+	candidates := make([]usecase.BankReconciliationCandidate, 0, len(revenueRecords)+len(chargeRecords))
+	for _, record := range revenueRecords {
+		candidates = append(candidates, usecase.BankReconciliationCandidate{
+			SourceType:          usecase.BankMatchSourceRevenue,
+			SourceID:            record.ID,
+			ProjectID:           record.ProjectID,
+			ProjectName:         record.ProjectName,
+			Title:               record.Title,
+			Amount:              record.Amount,
+			ExpectedOn:          record.ExpectedOn,
+			ClientNames:         clientNames[record.ProjectID],
+			KnownPayerDocuments: documents[record.ProjectID],
+		})
+	}
+	for _, record := range chargeRecords {
+		dueDate := usecase.ProjectMonthlyChargeDueDate(usecase.ProjectMonthlyCharge{
+			DueDay:   record.DueDay,
+			StartsOn: record.StartsOn,
+			Created:  record.Created,
+		})
+		title := record.Title
+		if record.Installment != "" {
+			title = title + " " + record.Installment
+		}
+
+		candidates = append(candidates, usecase.BankReconciliationCandidate{
+			SourceType:          usecase.BankMatchSourceMonthlyCharge,
+			SourceID:            record.ID,
+			ProjectID:           record.ProjectID,
+			ProjectName:         record.ProjectName,
+			Title:               title,
+			Amount:              record.Amount,
+			ExpectedOn:          &dueDate,
+			ClientNames:         clientNames[record.ProjectID],
+			KnownPayerDocuments: documents[record.ProjectID],
+		})
+	}
+
+	return candidates, nil
+}
+
+func (r *BankStatementRepository) ConfirmBankStatementMatch(
+	ctx context.Context,
+	input usecase.BankStatementMatchRecordInput,
+) (usecase.BankStatementLine, error) {
+	ctx, span := startSpan(ctx, "BankStatementRepository.ConfirmBankStatementMatch")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.BankStatementLine{}, err
+	}
+	defer tx.Rollback()
+
+	line, err := lockPendingBankStatementLine(ctx, tx, input.LineID)
+	if err != nil {
+		return usecase.BankStatementLine{}, err
+	}
+
+	settleSQL := `
+		UPDATE project_revenues
+		SET status = 'recebido',
+		    received_on = $2,
+		    updated = NOW()
+		WHERE id::text = $1
+		  AND status = 'pendente'
+		  AND active = TRUE
+		RETURNING project_id
+		`
+	revenueID := input.SourceID
+	chargeID := ""
+	if input.SourceType == usecase.BankMatchSourceMonthlyCharge {
+		settleSQL = `
+		UPDATE project_monthly_charges
+		SET status = 'pago',
+		    received_on = $2,
+		    updated = NOW()
+		WHERE id::text = $1
+		  AND status = 'pendente'
+		  AND active = TRUE
+		RETURNING project_id
+		`
+		revenueID = ""
+		chargeID = input.SourceID
+	}
+
+	var projectID string
+	if err := tx.GetContext(ctx, &projectID, settleSQL, input.SourceID, line.PostedOn); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.BankStatementLine{}, usecase.ErrBankStatementSourceNotPending
+		}
+		return usecase.BankStatementLine{}, mapBankStatementPersistenceError(err)
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`
+		UPDATE bank_statement_lines
+		SET status = 'conciliado',
+		    matched_source_type = $2,
+		    project_revenue_id = NULLIF($3, '')::uuid,
+		    project_monthly_charge_id = NULLIF($4, '')::uuid,
+		    matched_project_id = $5,
+		    resolved_at = $6,
+		    resolved_by = NULLIF($7, '')::uuid,
+		    updated = NOW()
+		WHERE id = $1
+		`,
+		line.ID,
+		input.SourceType,
+		revenueID,
+		chargeID,
+		projectID,
+		input.ConfirmedAt,
+		input.ConfirmedBy,
+	); err != nil {
+		return usecase.BankStatementLine{}, mapBankStatementPersistenceError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.BankStatementLine{}, err
+	}
+
+	return r.getBankStatementLine(ctx, line.ID)
+}
+
+func (r *BankStatementRepository) IgnoreBankStatementLine(
+	ctx context.Context,
+	input usecase.BankStatementLineResolutionInput,
+) (usecase.BankStatementLine, error) {
+	ctx, span := startSpan(ctx, "BankStatementRepository.IgnoreBankStatementLine")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.BankStatementLine{}, err
+	}
+	defer tx.Rollback()
+
+	line, err := lockPendingBankStatementLine(ctx, tx, input.LineID)
+	if err != nil {
+		return usecase.BankStatementLine{}, err
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`
+		UPDATE bank_statement_lines
+		SET status = 'ignorado',
+		    resolved_at = $2,
+		    resolved_by = NULLIF($3, '')::uuid,
+		    updated = NOW()
+		WHERE id = $1
+		`,
+		line.ID,
+		input.ResolvedAt,
+		input.ResolvedBy,
+	); err != nil {
+		return usecase.BankStatementLine{}, mapBankStatementPersistenceError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.BankStatementLine{}, err
+	}
+
+	return r.getBankStatementLine(ctx, line.ID)
+}
+
+func (r *BankStatementRepository) getBankStatementLine(
+	ctx context.Context,
+	lineID string,
+) (usecase.BankStatementLine, error) {
+	var record bankStatementLineRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		bankStatementLineSelectSQL+"WHERE line.id::text = $1",
+		lineID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.BankStatementLine{}, usecase.ErrNotFound
+		}
+		return usecase.BankStatementLine{}, err
+	}
+
+	return mapBankStatementLineRecord(record), nil
+}
+
+func lockPendingBankStatementLine(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	lineID string,
+) (bankStatementLineRecord, error) {
+	var record bankStatementLineRecord
+	if err := tx.GetContext(
+		ctx,
+		&record,
+		bankStatementLineSelectSQL+"WHERE line.id::text = $1 FOR UPDATE OF line",
+		lineID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return bankStatementLineRecord{}, usecase.ErrNotFound
+		}
+		return bankStatementLineRecord{}, err
+	}
+	if record.Status != usecase.BankStatementLineStatusPending {
+		return bankStatementLineRecord{}, usecase.ErrBankStatementLineResolved
+	}
+
+	return record, nil
+}
+
+func groupBankReconciliationValues(records []bankReconciliationProjectValueRecord) map[string][]string {
+	grouped := make(map[string][]string)
+	for _, record := range records {
+		grouped[record.ProjectID] = append(grouped[record.ProjectID], record.Value)
+	}
+	return grouped
+}
+
+func mapBankStatementImportRecord(record bankStatementImportRecord) usecase.BankStatementImport {
+	return usecase.BankStatementImport{
+		ID:            record.ID,
+		Format:        record.Format,
+		FileName:      record.FileName,
+		Account:       record.Account,
+		ImportedBy:    record.ImportedBy,
+		TotalLines:    record.TotalLines,
+		ImportedLines: record.ImportedLines,
+		PendingLines:  record.PendingLines,
+		Created:       record.Created,
+	}
+}
+
+func mapBankStatementLineRecord(record bankStatementLineRecord) usecase.BankStatementLine {
+	return usecase.BankStatementLine{
+		ID:                record.ID,
+		ImportID:          record.ImportID,
+		Account:           record.Account,
+		FitID:             record.FitID,
+		PostedOn:          record.PostedOn,
+		Amount:            record.Amount,
+		Description:       record.Description,
+		PayerName:         record.PayerName,
+		PayerDocument:     record.PayerDocument,
+		Status:            record.Status,
+		MatchedSourceType: record.MatchedSourceType,
+		MatchedSourceID:   record.MatchedSourceID,
+		MatchedProjectID:  record.MatchedProjectID,
+		ResolvedAt:        record.ResolvedAt,
+		ResolvedBy:        record.ResolvedBy,
+		Created:           record.Created,
+	}
+}
+
+func mapBankStatementPersistenceError(err error) error {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "22P02", "23514":
+			return usecase.ErrInvalidInput
+		case "23503":
+			if pgErr.Constraint == "bank_statement_imports_imported_by_fkey" ||
+				pgErr.Constraint == "bank_statement_lines_resolved_by_fkey" {
+				return usecase.ErrUserNotFound
+			}
+			return usecase.ErrNotFound
+		}
+	}
+
+	return err
+}
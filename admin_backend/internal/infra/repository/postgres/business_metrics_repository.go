@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+)
+
+type BusinessMetricsRepository struct {
+	db *sqlx.DB
+}
+
+func NewBusinessMetricsRepository(db *sqlx.DB) *BusinessMetricsRepository {
+	return &BusinessMetricsRepository{db: db}
+}
+
+func (r *BusinessMetricsRepository) CountOpenServiceRequests(
+	ctx context.Context,
+) ([]usecase.ServiceRequestStatusCount, error) {
+	ctx, span := startSpan(ctx, "BusinessMetricsRepository.CountOpenServiceRequests")
+	defer span.End()
+
+	var records []struct {
+		Status string `db:"status"`
+		Count  int    `db:"count"`
+	}
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		`
+		SELECT request.status, COUNT(*) AS count
+		FROM client_service_requests request
+		INNER JOIN service_request_statuses status ON status.code = request.status
+		WHERE status.closed = FALSE
+		GROUP BY request.status
+		`,
+	); err != nil {
+		return nil, err
+	}
+
+	counts := make([]usecase.ServiceRequestStatusCount, 0, len(records))
+	for _, record := range records {
+		counts = append(counts, usecase.ServiceRequestStatusCount{Status: record.Status, Count: record.Count})
+	}
+	return counts, nil
+}
+
+// SummarizeOverdueReceivables mirrors the receivables the payment reminders
+// consider. The monthly charge due date is the SQL form of
+// usecase.ProjectMonthlyChargeDueDate: due_day in the month of starts_on (or
+// of the creation date), clamped to that month's last day.
+func (r *BusinessMetricsRepository) SummarizeOverdueReceivables(
+	ctx context.Context,
+) ([]usecase.OverdueReceivableSummary, error) {
+	ctx, span := startSpan(ctx, "BusinessMetricsRepository.SummarizeOverdueReceivables")
+	defer span.End()
+
+	var records []struct {
+		Source string  `db:"source"`
+		Count  int     `db:"count"`
+		Amount float64 `db:"amount"`
+	}
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		`
+		SELECT $1::text AS source, COUNT(*) AS count, COALESCE(SUM(revenue.amount), 0)::float8 AS amount
+		FROM project_revenues revenue
+		INNER JOIN projects project ON project.id = revenue.project_id
+		WHERE revenue.status = 'pendente'
+		  AND revenue.active = TRUE
+		  AND project.active = TRUE
+		  AND revenue.expected_on < CURRENT_DATE
+		UNION ALL
+		SELECT $2::text AS source, COUNT(*) AS count, COALESCE(SUM(charge.amount), 0)::float8 AS amount
+		FROM (
+		  SELECT
+		    charge.amount,
+		    charge.due_day,
+		    COALESCE(charge.starts_on, (charge.created AT TIME ZONE 'UTC')::date) AS reference
+		  FROM project_monthly_charges charge
+		  INNER JOIN projects project ON project.id = charge.project_id
+		  WHERE charge.status = 'pendente'
+		    AND charge.active = TRUE
+		    AND project.active = TRUE
+		) charge
+		WHERE make_date(
+		  EXTRACT(YEAR FROM charge.reference)::int,
+		  EXTRACT(MONTH FROM charge.reference)::int,
+		  LEAST(
+		    GREATEST(charge.due_day, 1),
+		    EXTRACT(DAY FROM date_trunc('month', charge.reference) + INTERVAL '1 month - 1 day')::int
+		  )
+		) < CURRENT_DATE
+		`,
+		usecase.PaymentReminderSourceRevenue,
+		usecase.PaymentReminderSourceMonthlyCharge,
+	); err != nil {
+		return nil, err
+	}
+
+	summaries := make([]usecase.OverdueReceivableSummary, 0, len(records))
+	for _, record := range records {
+		summaries = append(summaries, usecase.OverdueReceivableSummary{
+			Source: record.Source,
+			Count:  record.Count,
+			Amount: record.Amount,
+		})
+	}
+	return summaries, nil
+}
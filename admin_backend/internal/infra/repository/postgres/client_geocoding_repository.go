@@ -0,0 +1,301 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+)
+
+type addressGeocodeRecord struct {
+	Country      string     `db:"country"`
+	ZipCode      string     `db:"zipcode"`
+	Street       string     `db:"street"`
+	Number       string     `db:"number"`
+	Neighborhood string     `db:"neighborhood"`
+	City         string     `db:"city"`
+	State        string     `db:"state"`
+	Latitude     *float64   `db:"latitude"`
+	Longitude    *float64   `db:"longitude"`
+	Status       string     `db:"geocode_status"`
+	GeocodedAt   *time.Time `db:"geocoded_at"`
+}
+
+func (r addressGeocodeRecord) query() usecase.GeocodeQuery {
+	return usecase.GeocodeQuery{
+		Country:      r.Country,
+		ZipCode:      r.ZipCode,
+		Street:       r.Street,
+		Number:       r.Number,
+		Neighborhood: r.Neighborhood,
+		City:         r.City,
+		State:        r.State,
+	}
+}
+
+type pendingAddressGeocodeRecord struct {
+	ID       string `db:"id"`
+	Attempts int    `db:"geocode_attempts"`
+	addressGeocodeRecord
+}
+
+type clientLocationRecord struct {
+	AddressID    string  `db:"address_id"`
+	ClientID     string  `db:"client_id"`
+	ClientName   string  `db:"client_name"`
+	Label        string  `db:"label"`
+	Street       string  `db:"street"`
+	Number       string  `db:"number"`
+	Neighborhood string  `db:"neighborhood"`
+	City         string  `db:"city"`
+	State        string  `db:"state"`
+	ZipCode      string  `db:"zipcode"`
+	Latitude     float64 `db:"latitude"`
+	Longitude    float64 `db:"longitude"`
+}
+
+// loadAddressGeocodes indexes the saved addresses of a client by what
+// decides where they are, so replacing them keeps their coordinates.
+func loadAddressGeocodes(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	clientID string,
+) (map[usecase.GeocodeQuery]addressGeocodeRecord, error) {
+	var records []addressGeocodeRecord
+	if err := tx.SelectContext(
+		ctx,
+		&records,
+		`
+		SELECT
+		  country,
+		  zipcode,
+		  street,
+		  number,
+		  neighborhood,
+		  city,
+		  state,
+		  latitude,
+		  longitude,
+		  geocode_status,
+		  geocoded_at
+		FROM client_addresses
+		WHERE client_id = $1
+		`,
+		clientID,
+	); err != nil {
+		return nil, err
+	}
+
+	geocodes := make(map[usecase.GeocodeQuery]addressGeocodeRecord, len(records))
+	for _, record := range records {
+		geocodes[record.query()] = record
+	}
+	return geocodes, nil
+}
+
+// nextAddressGeocode decides the coordinates of a saved address. Coordinates
+// sent with the address are manual unless they are the ones already saved;
+// without them, the saved outcome of the same address is kept and anything
+// else is geocoded again.
+func nextAddressGeocode(
+	address usecase.ClientAddressInput,
+	previous map[usecase.GeocodeQuery]addressGeocodeRecord,
+) addressGeocodeRecord {
+	saved, hasSaved := previous[address.GeocodeQuery()]
+
+	if address.Latitude != nil && address.Longitude != nil {
+		if hasSaved &&
+			saved.Latitude != nil && saved.Longitude != nil &&
+			*saved.Latitude == *address.Latitude && *saved.Longitude == *address.Longitude {
+			return saved
+		}
+		return addressGeocodeRecord{
+			Latitude:  address.Latitude,
+			Longitude: address.Longitude,
+			Status:    usecase.GeocodeStatusManual,
+		}
+	}
+
+	if hasSaved {
+		switch saved.Status {
+		case usecase.GeocodeStatusLocated, usecase.GeocodeStatusManual, usecase.GeocodeStatusNotFound:
+			return saved
+		}
+	}
+	return addressGeocodeRecord{Status: usecase.GeocodeStatusPending}
+}
+
+func (r *ClientRepository) ClaimPendingAddressGeocodes(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]usecase.PendingAddressGeocode, error) {
+	ctx, span := startSpan(ctx, "ClientRepository.ClaimPendingAddressGeocodes")
+	defer span.End()
+
+	var records []pendingAddressGeocodeRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		`
+		WITH due AS (
+		  SELECT address.id
+		  FROM client_addresses address
+		  JOIN clients client ON client.id = address.client_id
+		  WHERE address.geocode_status = 'pending'
+		    AND address.geocode_next_attempt_at <= NOW()
+		    AND address.active = TRUE
+		    AND client.active = TRUE
+		  ORDER BY address.geocode_next_attempt_at
+		  LIMIT $1
+		  FOR UPDATE OF address SKIP LOCKED
+		)
+		UPDATE client_addresses address
+		SET geocode_next_attempt_at = NOW() + ($2::int * INTERVAL '1 second')
+		FROM due
+		WHERE address.id = due.id
+		RETURNING
+		  address.id,
+		  address.geocode_attempts,
+		  address.country,
+		  address.zipcode,
+		  address.street,
+		  address.number,
+		  address.neighborhood,
+		  address.city,
+		  address.state,
+		  address.latitude,
+		  address.longitude,
+		  address.geocode_status,
+		  address.geocoded_at
+		`,
+		limit,
+		int(lease/time.Second),
+	); err != nil {
+		return nil, err
+	}
+
+	pending := make([]usecase.PendingAddressGeocode, 0, len(records))
+	for _, record := range records {
+		pending = append(pending, usecase.PendingAddressGeocode{
+			AddressID: record.ID,
+			Attempts:  record.Attempts,
+			Query:     record.query(),
+		})
+	}
+	return pending, nil
+}
+
+func (r *ClientRepository) RecordAddressGeocode(ctx context.Context, update usecase.AddressGeocodeUpdate) error {
+	ctx, span := startSpan(ctx, "ClientRepository.RecordAddressGeocode")
+	defer span.End()
+
+	var latitude, longitude *float64
+	if update.Coordinates != nil {
+		latitude = &update.Coordinates.Latitude
+		longitude = &update.Coordinates.Longitude
+	}
+	var nextAttemptAt *time.Time
+	if !update.NextAttemptAt.IsZero() {
+		nextAttemptAt = &update.NextAttemptAt
+	}
+
+	// Only still-pending rows are updated: a save meanwhile replaced the
+	// address or gave it coordinates by hand.
+	_, err := r.db.ExecContext(
+		ctx,
+		`
+		UPDATE client_addresses
+		SET
+		  geocode_status = $2,
+		  geocode_attempts = $3,
+		  latitude = $4,
+		  longitude = $5,
+		  geocode_next_attempt_at = COALESCE($6, geocode_next_attempt_at),
+		  geocoded_at = CASE WHEN $2 = 'pending' THEN geocoded_at ELSE NOW() END
+		WHERE id = $1
+		  AND geocode_status = 'pending'
+		`,
+		update.AddressID,
+		update.Status,
+		update.Attempts,
+		latitude,
+		longitude,
+		nextAttemptAt,
+	)
+	return err
+}
+
+func (r *ClientRepository) ListClientLocations(
+	ctx context.Context,
+	filter usecase.ClientLocationFilter,
+) ([]usecase.ClientLocation, error) {
+	ctx, span := startSpan(ctx, "ClientRepository.ListClientLocations")
+	defer span.End()
+
+	var minLongitude, minLatitude, maxLongitude, maxLatitude *float64
+	if box := filter.BoundingBox; box != nil {
+		minLongitude, minLatitude = &box.MinLongitude, &box.MinLatitude
+		maxLongitude, maxLatitude = &box.MaxLongitude, &box.MaxLatitude
+	}
+
+	var records []clientLocationRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		`
+		SELECT
+		  address.id AS address_id,
+		  client.id AS client_id,
+		  client.name AS client_name,
+		  address.label,
+		  address.street,
+		  address.number,
+		  address.neighborhood,
+		  address.city,
+		  address.state,
+		  address.zipcode,
+		  address.latitude,
+		  address.longitude
+		FROM client_addresses address
+		JOIN clients client ON client.id = address.client_id
+		WHERE client.active = TRUE
+		  AND address.active = TRUE
+		  AND address.latitude IS NOT NULL
+		  AND address.longitude IS NOT NULL
+		  AND ($1::double precision IS NULL OR address.longitude >= $1)
+		  AND ($2::double precision IS NULL OR address.latitude >= $2)
+		  AND ($3::double precision IS NULL OR address.longitude <= $3)
+		  AND ($4::double precision IS NULL OR address.latitude <= $4)
+		ORDER BY client.name ASC, address.position ASC, address.id ASC
+		`,
+		minLongitude,
+		minLatitude,
+		maxLongitude,
+		maxLatitude,
+	); err != nil {
+		return nil, err
+	}
+
+	locations := make([]usecase.ClientLocation, 0, len(records))
+	for _, record := range records {
+		locations = append(locations, usecase.ClientLocation{
+			AddressID:    record.AddressID,
+			ClientID:     record.ClientID,
+			ClientName:   record.ClientName,
+			Label:        record.Label,
+			Street:       record.Street,
+			Number:       record.Number,
+			Neighborhood: record.Neighborhood,
+			City:         record.City,
+			State:        record.State,
+			ZipCode:      record.ZipCode,
+			Coordinates: usecase.Coordinates{
+				Latitude:  record.Latitude,
+				Longitude: record.Longitude,
+			},
+		})
+	}
+	return locations, nil
+}
@@ -0,0 +1,567 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ClientOrganizationRepository struct {
+	db *sqlx.DB
+}
+
+func NewClientOrganizationRepository(db *sqlx.DB) *ClientOrganizationRepository {
+	return &ClientOrganizationRepository{db: db}
+}
+
+type clientOrganizationRecord struct {
+	ID      string    `db:"id"`
+	Name    string    `db:"name"`
+	TaxID   string    `db:"tax_id"`
+	Active  bool      `db:"active"`
+	Created time.Time `db:"created"`
+	Updated time.Time `db:"updated"`
+}
+
+type clientOrganizationMemberRecord struct {
+	ID      string    `db:"id"`
+	Name    string    `db:"name"`
+	Email   string    `db:"email"`
+	Login   string    `db:"login"`
+	Avatar  string    `db:"avatar"`
+	Role    string    `db:"organization_role"`
+	Active  bool      `db:"active"`
+	Created time.Time `db:"created"`
+}
+
+type clientOrganizationInviteRecord struct {
+	ID                string    `db:"id"`
+	OrganizationID    string    `db:"organization_id"`
+	Email             string    `db:"email"`
+	Role              string    `db:"role"`
+	InvitedByClientID string    `db:"invited_by_client_id"`
+	InvitedByName     string    `db:"invited_by_name"`
+	ExpiresAt         time.Time `db:"expires_at"`
+	Created           time.Time `db:"created"`
+}
+
+const clientOrganizationMemberSelectSQL = `
+SELECT
+  id,
+  name,
+  email,
+  login,
+  COALESCE(avatar, '') AS avatar,
+  organization_role,
+  active,
+  created
+FROM clients
+`
+
+const clientOrganizationInviteSelectSQL = `
+SELECT
+  invite.id,
+  invite.organization_id,
+  invite.email,
+  invite.role,
+  COALESCE(invite.invited_by_client_id::text, '') AS invited_by_client_id,
+  COALESCE(inviter.name, '') AS invited_by_name,
+  invite.expires_at,
+  invite.created
+FROM client_organization_invites invite
+LEFT JOIN clients inviter ON inviter.id = invite.invited_by_client_id
+`
+
+func (r *ClientOrganizationRepository) GetClientOrganization(
+	ctx context.Context,
+	organizationID string,
+) (usecase.ClientOrganization, error) {
+	ctx, span := startSpan(ctx, "ClientOrganizationRepository.GetClientOrganization")
+	defer span.End()
+
+	var record clientOrganizationRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		`
+		SELECT id, name, tax_id, active, created, updated
+		FROM client_organizations
+		WHERE id::text = $1
+		`,
+		organizationID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.ClientOrganization{}, usecase.ErrNotFound
+		}
+		return usecase.ClientOrganization{}, err
+	}
+
+	return mapClientOrganizationRecord(record), nil
+}
+
+func (r *ClientOrganizationRepository) UpdateClientOrganization(
+	ctx context.Context,
+	input usecase.UpdateClientOrganizationInput,
+) (usecase.ClientOrganization, error) {
+	ctx, span := startSpan(ctx, "ClientOrganizationRepository.UpdateClientOrganization")
+	defer span.End()
+
+	var record clientOrganizationRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		`
+		UPDATE client_organizations
+		SET
+		  name = $2,
+		  tax_id = $3,
+		  updated = NOW()
+		WHERE id::text = $1
+		RETURNING id, name, tax_id, active, created, updated
+		`,
+		input.OrganizationID,
+		input.Name,
+		input.TaxID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.ClientOrganization{}, usecase.ErrNotFound
+		}
+		return usecase.ClientOrganization{}, mapClientOrganizationPersistenceError(err)
+	}
+
+	return mapClientOrganizationRecord(record), nil
+}
+
+func (r *ClientOrganizationRepository) ListClientOrganizationMembers(
+	ctx context.Context,
+	organizationID string,
+) ([]usecase.ClientOrganizationMember, error) {
+	ctx, span := startSpan(ctx, "ClientOrganizationRepository.ListClientOrganizationMembers")
+	defer span.End()
+
+	var records []clientOrganizationMemberRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		clientOrganizationMemberSelectSQL+`
+		WHERE organization_id::text = $1
+		ORDER BY
+		  CASE organization_role WHEN 'owner' THEN 0 WHEN 'finance' THEN 1 ELSE 2 END,
+		  LOWER(name),
+		  id
+		`,
+		organizationID,
+	); err != nil {
+		return nil, err
+	}
+
+	members := make([]usecase.ClientOrganizationMember, 0, len(records))
+	for _, record := range records {
+		members = append(members, mapClientOrganizationMemberRecord(record))
+	}
+	return members, nil
+}
+
+// UpdateClientOrganizationMember locks the members of the organization so
+// two owners cannot demote each other at the same time.
+func (r *ClientOrganizationRepository) UpdateClientOrganizationMember(
+	ctx context.Context,
+	input usecase.UpdateClientOrganizationMemberInput,
+) (usecase.ClientOrganizationMember, error) {
+	ctx, span := startSpan(ctx, "ClientOrganizationRepository.UpdateClientOrganizationMember")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.ClientOrganizationMember{}, err
+	}
+	defer tx.Rollback()
+
+	var members []struct {
+		ID     string `db:"id"`
+		Role   string `db:"organization_role"`
+		Active bool   `db:"active"`
+	}
+	if err := tx.SelectContext(
+		ctx,
+		&members,
+		`
+		SELECT id, organization_role, active
+		FROM clients
+		WHERE organization_id::text = $1
+		FOR UPDATE
+		`,
+		input.OrganizationID,
+	); err != nil {
+		return usecase.ClientOrganizationMember{}, err
+	}
+
+	found := false
+	activeOwners := 0
+	for _, member := range members {
+		role, active := member.Role, member.Active
+		if member.ID == input.ClientID {
+			found = true
+			role = input.Role
+			if input.Active != nil {
+				active = *input.Active
+			}
+		}
+		if role == usecase.ClientOrganizationRoleOwner && active {
+			activeOwners++
+		}
+	}
+	if !found {
+		return usecase.ClientOrganizationMember{}, usecase.ErrNotFound
+	}
+	if activeOwners == 0 {
+		return usecase.ClientOrganizationMember{}, usecase.ErrClientOrganizationOwnerRequired
+	}
+
+	var record clientOrganizationMemberRecord
+	if err := tx.GetContext(
+		ctx,
+		&record,
+		`
+		UPDATE clients
+		SET
+		  organization_role = $2,
+		  active = COALESCE($3, active),
+		  updated = NOW()
+		WHERE id = $1
+		RETURNING
+		  id,
+		  name,
+		  email,
+		  login,
+		  COALESCE(avatar, '') AS avatar,
+		  organization_role,
+		  active,
+		  created
+		`,
+		input.ClientID,
+		input.Role,
+		input.Active,
+	); err != nil {
+		return usecase.ClientOrganizationMember{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.ClientOrganizationMember{}, err
+	}
+
+	return mapClientOrganizationMemberRecord(record), nil
+}
+
+func (r *ClientOrganizationRepository) ListClientOrganizationInvites(
+	ctx context.Context,
+	organizationID string,
+) ([]usecase.ClientOrganizationInvite, error) {
+	ctx, span := startSpan(ctx, "ClientOrganizationRepository.ListClientOrganizationInvites")
+	defer span.End()
+
+	var records []clientOrganizationInviteRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		clientOrganizationInviteSelectSQL+`
+		WHERE invite.organization_id::text = $1
+		  AND invite.accepted_at IS NULL
+		  AND invite.revoked_at IS NULL
+		  AND invite.expires_at > NOW()
+		ORDER BY invite.created DESC, invite.id DESC
+		`,
+		organizationID,
+	); err != nil {
+		return nil, err
+	}
+
+	invites := make([]usecase.ClientOrganizationInvite, 0, len(records))
+	for _, record := range records {
+		invites = append(invites, mapClientOrganizationInviteRecord(record))
+	}
+	return invites, nil
+}
+
+func (r *ClientOrganizationRepository) CreateClientOrganizationInvite(
+	ctx context.Context,
+	input usecase.CreateClientOrganizationInviteInput,
+) (usecase.ClientOrganizationInvite, error) {
+	ctx, span := startSpan(ctx, "ClientOrganizationRepository.CreateClientOrganizationInvite")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.ClientOrganizationInvite{}, err
+	}
+	defer tx.Rollback()
+
+	var emailInUse bool
+	if err := tx.GetContext(
+		ctx,
+		&emailInUse,
+		"SELECT EXISTS (SELECT 1 FROM clients WHERE LOWER(email) = LOWER($1))",
+		input.Email,
+	); err != nil {
+		return usecase.ClientOrganizationInvite{}, err
+	}
+	if emailInUse {
+		return usecase.ClientOrganizationInvite{}, usecase.ErrClientEmailInUse
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`
+		UPDATE client_organization_invites
+		SET revoked_at = NOW(), updated = NOW()
+		WHERE organization_id::text = $1
+		  AND LOWER(email) = LOWER($2)
+		  AND accepted_at IS NULL
+		  AND revoked_at IS NULL
+		`,
+		input.OrganizationID,
+		input.Email,
+	); err != nil {
+		return usecase.ClientOrganizationInvite{}, err
+	}
+
+	var inviteID string
+	if err := tx.GetContext(
+		ctx,
+		&inviteID,
+		`
+		INSERT INTO client_organization_invites (
+		  organization_id,
+		  email,
+		  role,
+		  token_hash,
+		  invited_by_client_id,
+		  expires_at
+		)
+		VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid, $6)
+		RETURNING id
+		`,
+		input.OrganizationID,
+		input.Email,
+		input.Role,
+		input.TokenHash,
+		input.InvitedByClientID,
+		input.ExpiresAt,
+	); err != nil {
+		return usecase.ClientOrganizationInvite{}, mapClientOrganizationPersistenceError(err)
+	}
+
+	var record clientOrganizationInviteRecord
+	if err := tx.GetContext(
+		ctx,
+		&record,
+		clientOrganizationInviteSelectSQL+"WHERE invite.id = $1",
+		inviteID,
+	); err != nil {
+		return usecase.ClientOrganizationInvite{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.ClientOrganizationInvite{}, err
+	}
+
+	return mapClientOrganizationInviteRecord(record), nil
+}
+
+func (r *ClientOrganizationRepository) RevokeClientOrganizationInvite(
+	ctx context.Context,
+	organizationID string,
+	inviteID string,
+) error {
+	ctx, span := startSpan(ctx, "ClientOrganizationRepository.RevokeClientOrganizationInvite")
+	defer span.End()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		`
+		UPDATE client_organization_invites
+		SET revoked_at = NOW(), updated = NOW()
+		WHERE id::text = $1
+		  AND organization_id::text = $2
+		  AND accepted_at IS NULL
+		  AND revoked_at IS NULL
+		`,
+		inviteID,
+		organizationID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return usecase.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ClientOrganizationRepository) AcceptClientOrganizationInvite(
+	ctx context.Context,
+	input usecase.AcceptClientOrganizationInviteInput,
+) (usecase.ClientPortalAccount, error) {
+	ctx, span := startSpan(ctx, "ClientOrganizationRepository.AcceptClientOrganizationInvite")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.ClientPortalAccount{}, err
+	}
+	defer tx.Rollback()
+
+	var invite struct {
+		ID             string `db:"id"`
+		OrganizationID string `db:"organization_id"`
+		Email          string `db:"email"`
+		Role           string `db:"role"`
+	}
+	if err := tx.GetContext(
+		ctx,
+		&invite,
+		`
+		SELECT invite.id, invite.organization_id, invite.email, invite.role
+		FROM client_organization_invites invite
+		INNER JOIN client_organizations organization ON organization.id = invite.organization_id
+		WHERE invite.token_hash = $1
+		  AND invite.accepted_at IS NULL
+		  AND invite.revoked_at IS NULL
+		  AND invite.expires_at > NOW()
+		  AND organization.active
+		FOR UPDATE OF invite
+		`,
+		input.TokenHash,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.ClientPortalAccount{}, usecase.ErrNotFound
+		}
+		return usecase.ClientPortalAccount{}, err
+	}
+
+	var clientID string
+	if err := tx.GetContext(
+		ctx,
+		&clientID,
+		`
+		INSERT INTO clients (
+		  name,
+		  email,
+		  login,
+		  password,
+		  active,
+		  organization_id,
+		  organization_role,
+		  created,
+		  updated
+		)
+		VALUES ($1, $2, $3, $4, TRUE, $5, $6, NOW(), NOW())
+		RETURNING id
+		`,
+		input.Name,
+		invite.Email,
+		input.Login,
+		input.Password,
+		invite.OrganizationID,
+		invite.Role,
+	); err != nil {
+		return usecase.ClientPortalAccount{}, mapClientPortalPersistenceError(err)
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`
+		UPDATE client_organization_invites
+		SET accepted_at = NOW(), accepted_client_id = $2, updated = NOW()
+		WHERE id = $1
+		`,
+		invite.ID,
+		clientID,
+	); err != nil {
+		return usecase.ClientPortalAccount{}, err
+	}
+
+	var account clientPortalAccountRecord
+	if err := tx.GetContext(
+		ctx,
+		&account,
+		clientPortalAccountSelectSQL+"WHERE client.id = $1",
+		clientID,
+	); err != nil {
+		return usecase.ClientPortalAccount{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.ClientPortalAccount{}, err
+	}
+
+	return mapClientPortalAccountRecord(account), nil
+}
+
+func mapClientOrganizationRecord(record clientOrganizationRecord) usecase.ClientOrganization {
+	return usecase.ClientOrganization{
+		ID:      record.ID,
+		Name:    record.Name,
+		TaxID:   record.TaxID,
+		Active:  record.Active,
+		Created: record.Created,
+		Updated: record.Updated,
+	}
+}
+
+func mapClientOrganizationMemberRecord(record clientOrganizationMemberRecord) usecase.ClientOrganizationMember {
+	return usecase.ClientOrganizationMember{
+		ID:      record.ID,
+		Name:    record.Name,
+		Email:   record.Email,
+		Login:   record.Login,
+		Avatar:  record.Avatar,
+		Role:    record.Role,
+		Active:  record.Active,
+		Created: record.Created,
+	}
+}
+
+func mapClientOrganizationInviteRecord(record clientOrganizationInviteRecord) usecase.ClientOrganizationInvite {
+	return usecase.ClientOrganizationInvite{
+		ID:                record.ID,
+		OrganizationID:    record.OrganizationID,
+		Email:             record.Email,
+		Role:              record.Role,
+		InvitedByClientID: record.InvitedByClientID,
+		InvitedByName:     record.InvitedByName,
+		ExpiresAt:         record.ExpiresAt,
+		Created:           record.Created,
+	}
+}
+
+func mapClientOrganizationPersistenceError(err error) error {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			switch pgErr.Constraint {
+			case "client_organizations_tax_id_key":
+				return usecase.ErrClientOrganizationTaxIDInUse
+			case "client_organization_invites_pending_email_key":
+				return usecase.ErrConflict
+			}
+		case "22P02", "23514":
+			return usecase.ErrInvalidInput
+		case "23503":
+			return usecase.ErrNotFound
+		}
+	}
+
+	return err
+}
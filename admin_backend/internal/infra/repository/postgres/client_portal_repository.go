@@ -28,19 +28,58 @@ type clientPortalAuthRecord struct {
 	Password string `db:"password"`
 	Avatar   string `db:"avatar"`
 	Active   bool   `db:"active"`
+
+	OrganizationID string `db:"organization_id"`
+	Role           string `db:"organization_role"`
 }
 
 type clientPortalAccountRecord struct {
-	ID      string    `db:"id"`
-	Name    string    `db:"name"`
-	Email   string    `db:"email"`
-	Login   string    `db:"login"`
-	Avatar  string    `db:"avatar"`
-	Active  bool      `db:"active"`
-	Created time.Time `db:"created"`
-	Updated time.Time `db:"updated"`
+	ID               string    `db:"id"`
+	Name             string    `db:"name"`
+	Email            string    `db:"email"`
+	Login            string    `db:"login"`
+	Avatar           string    `db:"avatar"`
+	Active           bool      `db:"active"`
+	OrganizationID   string    `db:"organization_id"`
+	OrganizationName string    `db:"organization_name"`
+	Role             string    `db:"organization_role"`
+	Created          time.Time `db:"created"`
+	Updated          time.Time `db:"updated"`
 }
 
+// Portal users of an inactive organization cannot sign in.
+const clientPortalAuthSelectSQL = `
+SELECT
+  client.id,
+  client.name,
+  client.email,
+  client.login,
+  client.password,
+  COALESCE(client.avatar, '') AS avatar,
+  client.active AND organization.active AS active,
+  client.organization_id,
+  client.organization_role
+FROM clients client
+INNER JOIN client_organizations organization ON organization.id = client.organization_id
+`
+
+const clientPortalAccountSelectSQL = `
+SELECT
+  client.id,
+  client.name,
+  client.email,
+  client.login,
+  COALESCE(client.avatar, '') AS avatar,
+  client.active,
+  client.organization_id,
+  organization.name AS organization_name,
+  client.organization_role,
+  client.created,
+  client.updated
+FROM clients client
+INNER JOIN client_organizations organization ON organization.id = client.organization_id
+`
+
 type clientPortalProjectRecord struct {
 	ID        string     `db:"id"`
 	Name      string     `db:"name"`
@@ -54,15 +93,17 @@ type clientPortalProjectRecord struct {
 }
 
 type clientServiceRequestRecord struct {
-	ID          string    `db:"id"`
-	ClientID    string    `db:"client_id"`
-	ProjectID   string    `db:"project_id"`
-	ProjectName string    `db:"project_name"`
-	Title       string    `db:"title"`
-	Description string    `db:"description"`
-	Status      string    `db:"status"`
-	Created     time.Time `db:"created"`
-	Updated     time.Time `db:"updated"`
+	ID              string     `db:"id"`
+	ClientID        string     `db:"client_id"`
+	ProjectID       string     `db:"project_id"`
+	ProjectName     string     `db:"project_name"`
+	Title           string     `db:"title"`
+	Description     string     `db:"description"`
+	Status          string     `db:"status"`
+	StatusName      string     `db:"status_name"`
+	ReopenableUntil *time.Time `db:"reopenable_until"`
+	Created         time.Time  `db:"created"`
+	Updated         time.Time  `db:"updated"`
 }
 
 type clientServiceRequestFileRecord struct {
@@ -91,8 +132,125 @@ type adminServiceRequestRecord struct {
 	OpenComments int       `db:"open_comments"`
 	Created      time.Time `db:"created"`
 	Updated      time.Time `db:"updated"`
+
+	StatusName       string `db:"status_name"`
+	StatusClosed     bool   `db:"status_closed"`
+	Priority         string `db:"priority"`
+	CategoryID       string `db:"category_id"`
+	CategoryName     string `db:"category_name"`
+	AssignedUserID   string `db:"assigned_user_id"`
+	AssignedUserName string `db:"assigned_user_name"`
+
+	ProjectTaskID     string `db:"project_task_id"`
+	ProjectTaskName   string `db:"project_task_name"`
+	ProjectTaskStatus string `db:"project_task_status"`
+
+	SLAPolicyID              sql.NullString `db:"sla_policy_id"`
+	SLAPolicyName            sql.NullString `db:"sla_policy_name"`
+	SLAFirstResponseDueAt    *time.Time     `db:"sla_first_response_due_at"`
+	SLAFirstResponseWarnAt   *time.Time     `db:"sla_first_response_warn_at"`
+	SLAFirstRespondedAt      *time.Time     `db:"sla_first_responded_at"`
+	SLAFirstResponseBreached sql.NullBool   `db:"sla_first_response_breached"`
+	SLAResolutionDueAt       *time.Time     `db:"sla_resolution_due_at"`
+	SLAResolutionWarnAt      *time.Time     `db:"sla_resolution_warn_at"`
+	SLAResolvedAt            *time.Time     `db:"sla_resolved_at"`
+	SLAResolutionBreached    sql.NullBool   `db:"sla_resolution_breached"`
+}
+
+type serviceRequestStatusChangeRecord struct {
+	ID                  string    `db:"id"`
+	FromStatus          string    `db:"from_status"`
+	FromStatusName      string    `db:"from_status_name"`
+	ToStatus            string    `db:"to_status"`
+	ToStatusName        string    `db:"to_status_name"`
+	ChangedByUserID     string    `db:"changed_by_user_id"`
+	ChangedByUserName   string    `db:"changed_by_user_name"`
+	ChangedByClientID   string    `db:"changed_by_client_id"`
+	ChangedByClientName string    `db:"changed_by_client_name"`
+	Note                string    `db:"note"`
+	Created             time.Time `db:"created"`
 }
 
+// adminServiceRequestSelectSQL is shared by the admin list and detail
+// queries, which only add their own WHERE and ORDER BY clauses.
+const adminServiceRequestSelectSQL = `
+	SELECT
+	  request.id,
+	  request.client_id,
+	  COALESCE(client_record.name, '') AS client_name,
+	  COALESCE(client_record.email, '') AS client_email,
+	  COALESCE(client_record.login, '') AS client_login,
+	  COALESCE(request.project_id::text, '') AS project_id,
+	  COALESCE(project.name, '') AS project_name,
+	  request.title,
+	  request.description,
+	  request.status,
+	  COALESCE(status_record.name, request.status) AS status_name,
+	  COALESCE(status_record.closed, FALSE) AS status_closed,
+	  request.priority,
+	  COALESCE(request.category_id::text, '') AS category_id,
+	  COALESCE(category.name, '') AS category_name,
+	  COALESCE(request.assigned_user_id::text, '') AS assigned_user_id,
+	  COALESCE(assignee.name, '') AS assigned_user_name,
+	  COALESCE(linked_task.id::text, '') AS project_task_id,
+	  COALESCE(linked_task.name, '') AS project_task_name,
+	  COALESCE(linked_task.status, '') AS project_task_status,
+	  COALESCE(comment_totals.total_comments, 0)::int AS comments,
+	  COALESCE(comment_totals.client_comments, 0)::int AS open_comments,
+	  request.created,
+	  request.updated,
+	  COALESCE(sla.policy_id::text, '') AS sla_policy_id,
+	  sla.policy_name AS sla_policy_name,
+	  sla.first_response_due_at AS sla_first_response_due_at,
+	  sla.first_response_warn_at AS sla_first_response_warn_at,
+	  sla.first_responded_at AS sla_first_responded_at,
+	  sla.first_response_breached AS sla_first_response_breached,
+	  sla.resolution_due_at AS sla_resolution_due_at,
+	  sla.resolution_warn_at AS sla_resolution_warn_at,
+	  sla.resolved_at AS sla_resolved_at,
+	  sla.resolution_breached AS sla_resolution_breached
+	FROM client_service_requests request
+	INNER JOIN clients client_record ON client_record.id = request.client_id
+	LEFT JOIN projects project ON project.id = request.project_id
+	LEFT JOIN service_request_statuses status_record ON status_record.code = request.status
+	LEFT JOIN service_request_categories category ON category.id = request.category_id
+	LEFT JOIN users assignee ON assignee.id = request.assigned_user_id
+	LEFT JOIN project_tasks linked_task ON linked_task.service_request_id = request.id
+	LEFT JOIN client_service_request_slas sla ON sla.service_request_id = request.id
+	LEFT JOIN (
+	  SELECT
+	    comment.service_request_id,
+	    COUNT(*)::int AS total_comments,
+	    COUNT(*) FILTER (WHERE comment.client_id IS NOT NULL)::int AS client_comments
+	  FROM client_service_request_comments comment
+	  GROUP BY comment.service_request_id
+	) comment_totals ON comment_totals.service_request_id = request.id
+`
+
+// serviceRequestReopenableUntilSQL is the end of the reopen window of the
+// request in the current status, or NULL when the client cannot reopen it.
+// The window starts when the request last entered the status.
+const serviceRequestReopenableUntilSQL = `
+	CASE
+	  WHEN status_record.reopen_window_days IS NOT NULL
+	   AND EXISTS (
+	     SELECT 1
+	     FROM service_request_status_transitions transition
+	     WHERE transition.from_status = request.status
+	       AND transition.to_status = 'reaberta'
+	   )
+	  THEN COALESCE(
+	    (
+	      SELECT MAX(history.created)
+	      FROM client_service_request_status_history history
+	      WHERE history.service_request_id = request.id
+	        AND history.to_status = request.status
+	    ),
+	    request.updated
+	  ) + MAKE_INTERVAL(days => status_record.reopen_window_days)
+	END
+`
+
 type serviceRequestCommentRecord struct {
 	ID               string    `db:"id"`
 	ServiceRequestID string    `db:"service_request_id"`
@@ -122,22 +280,16 @@ func (r *ClientPortalRepository) FindClientByLoginOrEmail(
 	ctx context.Context,
 	login string,
 ) (usecase.ClientPortalAuthUser, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.FindClientByLoginOrEmail")
+	defer span.End()
+
 	var record clientPortalAuthRecord
 	if err := r.db.GetContext(
 		ctx,
 		&record,
-		`
-		SELECT
-		  id,
-		  name,
-		  email,
-		  login,
-		  password,
-		  COALESCE(avatar, '') AS avatar,
-		  active
-		FROM clients
-		WHERE LOWER(login) = LOWER($1)
-		   OR LOWER(email) = LOWER($1)
+		clientPortalAuthSelectSQL+`
+		WHERE LOWER(client.login) = LOWER($1)
+		   OR LOWER(client.email) = LOWER($1)
 		LIMIT 1
 		`,
 		login,
@@ -155,21 +307,15 @@ func (r *ClientPortalRepository) GetClientAuthByID(
 	ctx context.Context,
 	clientID string,
 ) (usecase.ClientPortalAuthUser, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.GetClientAuthByID")
+	defer span.End()
+
 	var record clientPortalAuthRecord
 	if err := r.db.GetContext(
 		ctx,
 		&record,
-		`
-		SELECT
-		  id,
-		  name,
-		  email,
-		  login,
-		  password,
-		  COALESCE(avatar, '') AS avatar,
-		  active
-		FROM clients
-		WHERE id = $1
+		clientPortalAuthSelectSQL+`
+		WHERE client.id = $1
 		LIMIT 1
 		`,
 		clientID,
@@ -183,14 +329,19 @@ func (r *ClientPortalRepository) GetClientAuthByID(
 	return mapClientPortalAuthRecord(record), nil
 }
 
+// CreateBasicClient registers a portal user; the database founds a new
+// organization with the client as its owner.
 func (r *ClientPortalRepository) CreateBasicClient(
 	ctx context.Context,
 	input usecase.CreateClientPortalAccountInput,
 ) (usecase.ClientPortalAccount, error) {
-	var account clientPortalAccountRecord
+	ctx, span := startSpan(ctx, "ClientPortalRepository.CreateBasicClient")
+	defer span.End()
+
+	var clientID string
 	if err := r.db.GetContext(
 		ctx,
-		&account,
+		&clientID,
 		`
 		INSERT INTO clients (
 		  name,
@@ -212,15 +363,7 @@ func (r *ClientPortalRepository) CreateBasicClient(
 		  NOW(),
 		  NOW()
 		)
-		RETURNING
-		  id,
-		  name,
-		  email,
-		  login,
-		  COALESCE(avatar, '') AS avatar,
-		  active,
-		  created,
-		  updated
+		RETURNING id
 		`,
 		input.Name,
 		input.Email,
@@ -231,29 +374,22 @@ func (r *ClientPortalRepository) CreateBasicClient(
 		return usecase.ClientPortalAccount{}, mapClientPortalPersistenceError(err)
 	}
 
-	return mapClientPortalAccountRecord(account), nil
+	return r.GetClientAccount(ctx, clientID)
 }
 
 func (r *ClientPortalRepository) GetClientAccount(
 	ctx context.Context,
 	clientID string,
 ) (usecase.ClientPortalAccount, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.GetClientAccount")
+	defer span.End()
+
 	var account clientPortalAccountRecord
 	if err := r.db.GetContext(
 		ctx,
 		&account,
-		`
-		SELECT
-		  id,
-		  name,
-		  email,
-		  login,
-		  COALESCE(avatar, '') AS avatar,
-		  active,
-		  created,
-		  updated
-		FROM clients
-		WHERE id = $1
+		clientPortalAccountSelectSQL+`
+		WHERE client.id = $1
 		LIMIT 1
 		`,
 		clientID,
@@ -271,10 +407,13 @@ func (r *ClientPortalRepository) UpdateClientAccount(
 	ctx context.Context,
 	input usecase.UpdateClientPortalAccountInput,
 ) (usecase.ClientPortalAccount, error) {
-	var account clientPortalAccountRecord
+	ctx, span := startSpan(ctx, "ClientPortalRepository.UpdateClientAccount")
+	defer span.End()
+
+	var clientID string
 	if err := r.db.GetContext(
 		ctx,
-		&account,
+		&clientID,
 		`
 		UPDATE clients
 		SET name = $1,
@@ -284,15 +423,7 @@ func (r *ClientPortalRepository) UpdateClientAccount(
 		    avatar = NULLIF($5, ''),
 		    updated = NOW()
 		WHERE id = $6
-		RETURNING
-		  id,
-		  name,
-		  email,
-		  login,
-		  COALESCE(avatar, '') AS avatar,
-		  active,
-		  created,
-		  updated
+		RETURNING id
 		`,
 		input.Name,
 		input.Email,
@@ -307,13 +438,18 @@ func (r *ClientPortalRepository) UpdateClientAccount(
 		return usecase.ClientPortalAccount{}, mapClientPortalPersistenceError(err)
 	}
 
-	return mapClientPortalAccountRecord(account), nil
+	return r.GetClientAccount(ctx, clientID)
 }
 
+// ListClientProjects and ClientHasProjectAccess resolve through the client
+// organization: a project linked to any member is visible to all of them.
 func (r *ClientPortalRepository) ListClientProjects(
 	ctx context.Context,
 	clientID string,
 ) ([]usecase.ClientPortalProject, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.ListClientProjects")
+	defer span.End()
+
 	var records []clientPortalProjectRecord
 	if err := r.db.SelectContext(
 		ctx,
@@ -329,9 +465,15 @@ func (r *ClientPortalRepository) ListClientProjects(
 		  project.end_date,
 		  project.created,
 		  project.updated
-		FROM project_clients project_client
-		INNER JOIN projects project ON project.id = project_client.project_id
-		WHERE project_client.client_id = $1
+		FROM projects project
+		WHERE EXISTS (
+		  SELECT 1
+		  FROM project_clients project_client
+		  INNER JOIN clients member ON member.id = project_client.client_id
+		  INNER JOIN clients client ON client.organization_id = member.organization_id
+		  WHERE project_client.project_id = project.id
+		    AND client.id = $1
+		)
 		ORDER BY project.created DESC, project.id DESC
 		`,
 		clientID,
@@ -361,6 +503,9 @@ func (r *ClientPortalRepository) ClientHasProjectAccess(
 	ctx context.Context,
 	clientID, projectID string,
 ) (bool, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.ClientHasProjectAccess")
+	defer span.End()
+
 	var hasAccess bool
 	if err := r.db.GetContext(
 		ctx,
@@ -369,7 +514,9 @@ func (r *ClientPortalRepository) ClientHasProjectAccess(
 		SELECT EXISTS (
 		  SELECT 1
 		  FROM project_clients project_client
-		  WHERE project_client.client_id = $1
+		  INNER JOIN clients member ON member.id = project_client.client_id
+		  INNER JOIN clients client ON client.organization_id = member.organization_id
+		  WHERE client.id = $1
 		    AND project_client.project_id = $2
 		)
 		`,
@@ -386,15 +533,19 @@ func (r *ClientPortalRepository) CountOpenServiceRequests(
 	ctx context.Context,
 	clientID string,
 ) (int, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.CountOpenServiceRequests")
+	defer span.End()
+
 	var total int
 	if err := r.db.GetContext(
 		ctx,
 		&total,
 		`
 		SELECT COUNT(*)::int
-		FROM client_service_requests
-		WHERE client_id = $1
-		  AND status IN ('aberta', 'em_andamento')
+		FROM client_service_requests request
+		INNER JOIN service_request_statuses status_record ON status_record.code = request.status
+		WHERE request.client_id = $1
+		  AND NOT status_record.closed
 		`,
 		clientID,
 	); err != nil {
@@ -411,6 +562,9 @@ func (r *ClientPortalRepository) ListClientServiceRequests(
 	ctx context.Context,
 	clientID string,
 ) ([]usecase.ClientServiceRequest, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.ListClientServiceRequests")
+	defer span.End()
+
 	var requestRecords []clientServiceRequestRecord
 	if err := r.db.SelectContext(
 		ctx,
@@ -424,10 +578,13 @@ func (r *ClientPortalRepository) ListClientServiceRequests(
 		  request.title,
 		  request.description,
 		  request.status,
+		  COALESCE(status_record.name, request.status) AS status_name,
+		  `+serviceRequestReopenableUntilSQL+` AS reopenable_until,
 		  request.created,
 		  request.updated
 		FROM client_service_requests request
 		LEFT JOIN projects project ON project.id = request.project_id
+		LEFT JOIN service_request_statuses status_record ON status_record.code = request.status
 		WHERE request.client_id = $1
 		ORDER BY request.created DESC, request.id DESC
 		`,
@@ -443,20 +600,28 @@ func (r *ClientPortalRepository) ListClientServiceRequests(
 	requestIDs := make([]string, 0, len(requestRecords))
 	requestIndexByID := make(map[string]int, len(requestRecords))
 
+	now := time.Now()
 	for _, record := range requestRecords {
+		reopenableUntil := record.ReopenableUntil
+		if reopenableUntil != nil && !reopenableUntil.After(now) {
+			reopenableUntil = nil
+		}
+
 		requestIndexByID[record.ID] = len(requests)
 		requestIDs = append(requestIDs, record.ID)
 		requests = append(requests, usecase.ClientServiceRequest{
-			ID:          record.ID,
-			ClientID:    record.ClientID,
-			ProjectID:   strings.TrimSpace(record.ProjectID),
-			ProjectName: record.ProjectName,
-			Title:       record.Title,
-			Description: record.Description,
-			Status:      normalizeServiceRequestStatus(record.Status),
-			Files:       []usecase.ClientServiceRequestFile{},
-			Created:     record.Created,
-			Updated:     record.Updated,
+			ID:              record.ID,
+			ClientID:        record.ClientID,
+			ProjectID:       strings.TrimSpace(record.ProjectID),
+			ProjectName:     record.ProjectName,
+			Title:           record.Title,
+			Description:     record.Description,
+			Status:          normalizeServiceRequestStatus(record.Status),
+			StatusName:      record.StatusName,
+			ReopenableUntil: reopenableUntil,
+			Files:           []usecase.ClientServiceRequestFile{},
+			Created:         record.Created,
+			Updated:         record.Updated,
 		})
 	}
 
@@ -513,12 +678,19 @@ func (r *ClientPortalRepository) CreateClientServiceRequest(
 	ctx context.Context,
 	input usecase.CreateClientServiceRequestInput,
 ) (usecase.ClientServiceRequest, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.CreateClientServiceRequest")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return usecase.ClientServiceRequest{}, err
 	}
 	defer tx.Rollback()
 
+	if err := setServiceRequestActor(ctx, tx, "", input.ClientID, ""); err != nil {
+		return usecase.ClientServiceRequest{}, err
+	}
+
 	var requestID string
 	if err := tx.GetContext(
 		ctx,
@@ -638,15 +810,30 @@ func (r *ClientPortalRepository) CancelClientServiceRequest(
 	clientID string,
 	requestID string,
 ) (usecase.ClientServiceRequest, error) {
-	result, err := r.db.ExecContext(
+	ctx, span := startSpan(ctx, "ClientPortalRepository.CancelClientServiceRequest")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.ClientServiceRequest{}, err
+	}
+	defer tx.Rollback()
+
+	if err := setServiceRequestActor(ctx, tx, "", clientID, ""); err != nil {
+		return usecase.ClientServiceRequest{}, err
+	}
+
+	result, err := tx.ExecContext(
 		ctx,
 		`
-		UPDATE client_service_requests
+		UPDATE client_service_requests request
 		SET status = 'cancelada',
 		    updated = NOW()
-		WHERE id = $1
-		  AND client_id = $2
-		  AND status IN ('aberta', 'em_andamento')
+		FROM service_request_statuses status_record
+		WHERE request.id = $1
+		  AND request.client_id = $2
+		  AND status_record.code = request.status
+		  AND NOT status_record.closed
 		`,
 		requestID,
 		clientID,
@@ -662,7 +849,7 @@ func (r *ClientPortalRepository) CancelClientServiceRequest(
 
 	if affectedRows == 0 {
 		var currentStatus string
-		statusErr := r.db.GetContext(
+		statusErr := tx.GetContext(
 			ctx,
 			&currentStatus,
 			`
@@ -682,14 +869,117 @@ func (r *ClientPortalRepository) CancelClientServiceRequest(
 			return usecase.ClientServiceRequest{}, statusErr
 		}
 
-		normalizedStatus := normalizeServiceRequestStatus(currentStatus)
-		if normalizedStatus == "cancelada" || normalizedStatus == "concluida" {
-			return usecase.ClientServiceRequest{}, usecase.ErrConflict
+		return usecase.ClientServiceRequest{}, usecase.ErrConflict
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.ClientServiceRequest{}, err
+	}
+
+	return r.findClientServiceRequest(ctx, clientID, requestID)
+}
+
+// ReopenClientServiceRequest moves the request to "reaberta" when its current
+// status has a reopen window, the window has not passed and the workflow
+// allows the transition.
+func (r *ClientPortalRepository) ReopenClientServiceRequest(
+	ctx context.Context,
+	input usecase.ReopenClientServiceRequestInput,
+) (usecase.ClientServiceRequest, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.ReopenClientServiceRequest")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.ClientServiceRequest{}, err
+	}
+	defer tx.Rollback()
+
+	var state struct {
+		Reopenable      bool       `db:"reopenable"`
+		ReopenableUntil *time.Time `db:"reopenable_until"`
+	}
+	if err := tx.GetContext(
+		ctx,
+		&state,
+		`
+		SELECT
+		  status_record.reopen_window_days IS NOT NULL AS reopenable,
+		  `+serviceRequestReopenableUntilSQL+` AS reopenable_until
+		FROM client_service_requests request
+		LEFT JOIN service_request_statuses status_record ON status_record.code = request.status
+		WHERE request.id = $1
+		  AND request.client_id = $2
+		FOR UPDATE OF request
+		`,
+		input.RequestID,
+		input.ClientID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.ClientServiceRequest{}, usecase.ErrNotFound
 		}
+		return usecase.ClientServiceRequest{}, mapClientPortalPersistenceError(err)
+	}
 
+	if !state.Reopenable || state.ReopenableUntil == nil {
 		return usecase.ClientServiceRequest{}, usecase.ErrConflict
 	}
+	if !state.ReopenableUntil.After(time.Now()) {
+		return usecase.ClientServiceRequest{}, usecase.ErrServiceRequestReopenExpired
+	}
+
+	if input.Comment != "" {
+		if _, err := tx.ExecContext(
+			ctx,
+			`
+			INSERT INTO client_service_request_comments (
+			  service_request_id,
+			  parent_comment_id,
+			  user_id,
+			  client_id,
+			  comment,
+			  created,
+			  updated
+			)
+			VALUES ($1, NULL, NULL, $2, $3, NOW(), NOW())
+			`,
+			input.RequestID,
+			input.ClientID,
+			input.Comment,
+		); err != nil {
+			return usecase.ClientServiceRequest{}, mapClientPortalPersistenceError(err)
+		}
+	}
+
+	if err := setServiceRequestActor(ctx, tx, "", input.ClientID, input.Comment); err != nil {
+		return usecase.ClientServiceRequest{}, err
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`
+		UPDATE client_service_requests
+		SET status = 'reaberta',
+		    updated = NOW()
+		WHERE id = $1
+		`,
+		input.RequestID,
+	); err != nil {
+		return usecase.ClientServiceRequest{}, mapClientPortalPersistenceError(err)
+	}
 
+	if err := tx.Commit(); err != nil {
+		return usecase.ClientServiceRequest{}, err
+	}
+
+	return r.findClientServiceRequest(ctx, input.ClientID, input.RequestID)
+}
+
+func (r *ClientPortalRepository) findClientServiceRequest(
+	ctx context.Context,
+	clientID string,
+	requestID string,
+) (usecase.ClientServiceRequest, error) {
 	requests, err := r.ListClientServiceRequests(ctx, clientID)
 	if err != nil {
 		return usecase.ClientServiceRequest{}, err
@@ -706,43 +996,33 @@ func (r *ClientPortalRepository) CancelClientServiceRequest(
 
 func (r *ClientPortalRepository) ListAdminServiceRequests(
 	ctx context.Context,
+	filter usecase.AdminServiceRequestFilter,
 ) ([]usecase.AdminServiceRequest, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.ListAdminServiceRequests")
+	defer span.End()
+
 	var records []adminServiceRequestRecord
 	if err := r.db.SelectContext(
 		ctx,
 		&records,
-		`
-		SELECT
-		  request.id,
-		  request.client_id,
-		  COALESCE(client_record.name, '') AS client_name,
-		  COALESCE(client_record.email, '') AS client_email,
-		  COALESCE(client_record.login, '') AS client_login,
-		  COALESCE(request.project_id::text, '') AS project_id,
-		  COALESCE(project.name, '') AS project_name,
-		  request.title,
-		  request.description,
-		  request.status,
-		  COALESCE(comment_totals.total_comments, 0)::int AS comments,
-		  COALESCE(comment_totals.client_comments, 0)::int AS open_comments,
-		  request.created,
-		  request.updated
-		FROM client_service_requests request
-		INNER JOIN clients client_record ON client_record.id = request.client_id
-		LEFT JOIN projects project ON project.id = request.project_id
-		LEFT JOIN (
-		  SELECT
-		    comment.service_request_id,
-		    COUNT(*)::int AS total_comments,
-		    COUNT(*) FILTER (WHERE comment.client_id IS NOT NULL)::int AS client_comments
-		  FROM client_service_request_comments comment
-		  GROUP BY comment.service_request_id
-		) comment_totals ON comment_totals.service_request_id = request.id
+		adminServiceRequestSelectSQL+`
+		WHERE ($1 = '' OR request.status = $1)
+		  AND ($2 = '' OR request.priority = $2)
+		  AND ($3 = '' OR request.category_id::text = $3)
+		  AND (
+		    $4 = ''
+		    OR ($4 = 'none' AND request.assigned_user_id IS NULL)
+		    OR request.assigned_user_id::text = $4
+		  )
 		ORDER BY
-		  CASE WHEN request.status IN ('aberta', 'em_andamento') THEN 0 ELSE 1 END ASC,
+		  CASE WHEN COALESCE(status_record.closed, FALSE) THEN 1 ELSE 0 END ASC,
 		  request.created DESC,
 		  request.id DESC
 		`,
+		filter.Status,
+		filter.Priority,
+		filter.CategoryID,
+		filter.AssignedUserID,
 	); err != nil {
 		if isUndefinedRelationOrColumn(err) {
 			return []usecase.AdminServiceRequest{}, nil
@@ -762,37 +1042,14 @@ func (r *ClientPortalRepository) GetAdminServiceRequest(
 	ctx context.Context,
 	requestID string,
 ) (usecase.AdminServiceRequest, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.GetAdminServiceRequest")
+	defer span.End()
+
 	var record adminServiceRequestRecord
 	if err := r.db.GetContext(
 		ctx,
 		&record,
-		`
-		SELECT
-		  request.id,
-		  request.client_id,
-		  COALESCE(client_record.name, '') AS client_name,
-		  COALESCE(client_record.email, '') AS client_email,
-		  COALESCE(client_record.login, '') AS client_login,
-		  COALESCE(request.project_id::text, '') AS project_id,
-		  COALESCE(project.name, '') AS project_name,
-		  request.title,
-		  request.description,
-		  request.status,
-		  COALESCE(comment_totals.total_comments, 0)::int AS comments,
-		  COALESCE(comment_totals.client_comments, 0)::int AS open_comments,
-		  request.created,
-		  request.updated
-		FROM client_service_requests request
-		INNER JOIN clients client_record ON client_record.id = request.client_id
-		LEFT JOIN projects project ON project.id = request.project_id
-		LEFT JOIN (
-		  SELECT
-		    comment.service_request_id,
-		    COUNT(*)::int AS total_comments,
-		    COUNT(*) FILTER (WHERE comment.client_id IS NOT NULL)::int AS client_comments
-		  FROM client_service_request_comments comment
-		  GROUP BY comment.service_request_id
-		) comment_totals ON comment_totals.service_request_id = request.id
+		adminServiceRequestSelectSQL+`
 		WHERE request.id = $1
 		LIMIT 1
 		`,
@@ -814,37 +1071,336 @@ func (r *ClientPortalRepository) GetAdminServiceRequest(
 	return request, nil
 }
 
+// UpdateAdminServiceRequestStatus only accepts active statuses reachable from
+// the current one through a configured transition.
 func (r *ClientPortalRepository) UpdateAdminServiceRequestStatus(
 	ctx context.Context,
 	input usecase.UpdateAdminServiceRequestStatusInput,
 ) (usecase.AdminServiceRequest, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.UpdateAdminServiceRequestStatus")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.AdminServiceRequest{}, err
+	}
+	defer tx.Rollback()
+
+	var currentStatus string
+	if err := tx.GetContext(
+		ctx,
+		&currentStatus,
+		`
+		SELECT status
+		FROM client_service_requests
+		WHERE id = $1
+		FOR UPDATE
+		`,
+		input.RequestID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.AdminServiceRequest{}, usecase.ErrNotFound
+		}
+		return usecase.AdminServiceRequest{}, mapClientPortalPersistenceError(err)
+	}
+
+	if currentStatus != input.Status {
+		var state struct {
+			Active  bool `db:"active"`
+			Allowed bool `db:"allowed"`
+		}
+		if err := tx.GetContext(
+			ctx,
+			&state,
+			`
+			SELECT
+			  status_record.active,
+			  EXISTS (
+			    SELECT 1
+			    FROM service_request_status_transitions transition
+			    WHERE transition.from_status = $1
+			      AND transition.to_status = status_record.code
+			  ) AS allowed
+			FROM service_request_statuses status_record
+			WHERE status_record.code = $2
+			`,
+			currentStatus,
+			input.Status,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return usecase.AdminServiceRequest{}, usecase.ErrServiceRequestStatusNotFound
+			}
+			return usecase.AdminServiceRequest{}, err
+		}
+		if !state.Active {
+			return usecase.AdminServiceRequest{}, usecase.ErrServiceRequestStatusNotFound
+		}
+		if !state.Allowed {
+			return usecase.AdminServiceRequest{}, usecase.ErrServiceRequestTransitionNotAllowed
+		}
+
+		if err := setServiceRequestActor(ctx, tx, input.ChangedByUserID, "", input.Note); err != nil {
+			return usecase.AdminServiceRequest{}, err
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			`
+			UPDATE client_service_requests
+			SET status = $1,
+			    updated = NOW()
+			WHERE id = $2
+			`,
+			input.Status,
+			input.RequestID,
+		); err != nil {
+			return usecase.AdminServiceRequest{}, mapClientPortalPersistenceError(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.AdminServiceRequest{}, err
+	}
+
+	return r.GetAdminServiceRequest(ctx, input.RequestID)
+}
+
+// UpdateAdminServiceRequest only assigns active users and active categories;
+// clearing either one is always allowed.
+func (r *ClientPortalRepository) UpdateAdminServiceRequest(
+	ctx context.Context,
+	input usecase.UpdateAdminServiceRequestInput,
+) (usecase.AdminServiceRequest, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.UpdateAdminServiceRequest")
+	defer span.End()
+
+	assignedUserID, updateAssignee := optionalStringArg(input.AssignedUserID)
+	priority, updatePriority := optionalStringArg(input.Priority)
+	categoryID, updateCategory := optionalStringArg(input.CategoryID)
+
+	if updateAssignee && assignedUserID != "" {
+		var active bool
+		if err := r.db.GetContext(
+			ctx,
+			&active,
+			`SELECT ativo FROM users WHERE id::text = $1`,
+			assignedUserID,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return usecase.AdminServiceRequest{}, usecase.ErrUserNotFound
+			}
+			return usecase.AdminServiceRequest{}, mapClientPortalPersistenceError(err)
+		}
+		if !active {
+			return usecase.AdminServiceRequest{}, usecase.ErrUserNotFound
+		}
+	}
+
+	if updateCategory && categoryID != "" {
+		var active bool
+		if err := r.db.GetContext(
+			ctx,
+			&active,
+			`SELECT active FROM service_request_categories WHERE id::text = $1`,
+			categoryID,
+		); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return usecase.AdminServiceRequest{}, usecase.ErrServiceRequestCategoryNotFound
+			}
+			return usecase.AdminServiceRequest{}, mapClientPortalPersistenceError(err)
+		}
+		if !active {
+			return usecase.AdminServiceRequest{}, usecase.ErrServiceRequestCategoryNotFound
+		}
+	}
+
 	var requestID string
 	if err := r.db.GetContext(
 		ctx,
 		&requestID,
 		`
 		UPDATE client_service_requests
-		SET status = $1,
-		    updated = NOW()
-		WHERE id = $2
+		SET
+		  assigned_user_id = CASE WHEN $2 THEN NULLIF($3, '')::uuid ELSE assigned_user_id END,
+		  priority = CASE WHEN $4 THEN $5 ELSE priority END,
+		  category_id = CASE WHEN $6 THEN NULLIF($7, '')::uuid ELSE category_id END,
+		  updated = NOW()
+		WHERE id::text = $1
 		RETURNING id
 		`,
-		input.Status,
 		input.RequestID,
+		updateAssignee,
+		assignedUserID,
+		updatePriority,
+		priority,
+		updateCategory,
+		categoryID,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return usecase.AdminServiceRequest{}, usecase.ErrNotFound
 		}
-		return usecase.AdminServiceRequest{}, mapClientPortalPersistenceError(err)
+		return usecase.AdminServiceRequest{}, mapServiceRequestAssignmentError(err)
 	}
 
 	return r.GetAdminServiceRequest(ctx, requestID)
 }
 
+// LinkServiceRequestTask links a converted request to its task. The request
+// joins the task project when it had none, and moves to "em_andamento" when
+// the workflow allows it from the current status.
+func (r *ClientPortalRepository) LinkServiceRequestTask(
+	ctx context.Context,
+	input usecase.LinkServiceRequestTaskInput,
+) (usecase.AdminServiceRequest, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.LinkServiceRequestTask")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.AdminServiceRequest{}, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(
+		ctx,
+		`
+		UPDATE project_tasks
+		SET service_request_id = $1,
+		    updated = NOW()
+		WHERE id::text = $2
+		  AND service_request_id IS NULL
+		`,
+		input.RequestID,
+		input.TaskID,
+	)
+	if err != nil {
+		return usecase.AdminServiceRequest{}, mapServiceRequestLinkError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return usecase.AdminServiceRequest{}, err
+	}
+	if affected == 0 {
+		return usecase.AdminServiceRequest{}, usecase.ErrNotFound
+	}
+
+	if err := setServiceRequestActor(
+		ctx,
+		tx,
+		input.LinkedByUserID,
+		"",
+		"Convertida em tarefa de projeto",
+	); err != nil {
+		return usecase.AdminServiceRequest{}, err
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`
+		UPDATE client_service_requests request
+		SET
+		  project_id = COALESCE(request.project_id, $2::uuid),
+		  status = CASE
+		    WHEN EXISTS (
+		      SELECT 1
+		      FROM service_request_status_transitions transition
+		      WHERE transition.from_status = request.status
+		        AND transition.to_status = 'em_andamento'
+		    ) THEN 'em_andamento'
+		    ELSE request.status
+		  END,
+		  updated = NOW()
+		WHERE request.id::text = $1
+		`,
+		input.RequestID,
+		input.ProjectID,
+	); err != nil {
+		return usecase.AdminServiceRequest{}, mapServiceRequestLinkError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.AdminServiceRequest{}, err
+	}
+
+	return r.GetAdminServiceRequest(ctx, input.RequestID)
+}
+
+func (r *ClientPortalRepository) ListServiceRequestStatusHistory(
+	ctx context.Context,
+	requestID string,
+) ([]usecase.ServiceRequestStatusChange, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.ListServiceRequestStatusHistory")
+	defer span.End()
+
+	exists, err := r.serviceRequestExists(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, usecase.ErrNotFound
+	}
+
+	var records []serviceRequestStatusChangeRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		`
+		SELECT
+		  history.id,
+		  COALESCE(history.from_status, '') AS from_status,
+		  COALESCE(from_record.name, history.from_status, '') AS from_status_name,
+		  history.to_status,
+		  COALESCE(to_record.name, history.to_status) AS to_status_name,
+		  COALESCE(history.changed_by_user_id::text, '') AS changed_by_user_id,
+		  COALESCE(user_record.name, '') AS changed_by_user_name,
+		  COALESCE(history.changed_by_client_id::text, '') AS changed_by_client_id,
+		  COALESCE(client_record.name, '') AS changed_by_client_name,
+		  history.note,
+		  history.created
+		FROM client_service_request_status_history history
+		LEFT JOIN service_request_statuses from_record ON from_record.code = history.from_status
+		LEFT JOIN service_request_statuses to_record ON to_record.code = history.to_status
+		LEFT JOIN users user_record ON user_record.id = history.changed_by_user_id
+		LEFT JOIN clients client_record ON client_record.id = history.changed_by_client_id
+		WHERE history.service_request_id = $1
+		ORDER BY history.created ASC, history.id ASC
+		`,
+		requestID,
+	); err != nil {
+		if isUndefinedRelationOrColumn(err) {
+			return []usecase.ServiceRequestStatusChange{}, nil
+		}
+		return nil, err
+	}
+
+	changes := make([]usecase.ServiceRequestStatusChange, 0, len(records))
+	for _, record := range records {
+		changes = append(changes, usecase.ServiceRequestStatusChange{
+			ID:                  record.ID,
+			FromStatus:          record.FromStatus,
+			FromStatusName:      record.FromStatusName,
+			ToStatus:            record.ToStatus,
+			ToStatusName:        record.ToStatusName,
+			ChangedByUserID:     record.ChangedByUserID,
+			ChangedByUserName:   record.ChangedByUserName,
+			ChangedByClientID:   record.ChangedByClientID,
+			ChangedByClientName: record.ChangedByClientName,
+			Note:                record.Note,
+			Created:             record.Created,
+		})
+	}
+
+	return changes, nil
+}
+
 func (r *ClientPortalRepository) ListServiceRequestComments(
 	ctx context.Context,
 	requestID string,
 ) ([]usecase.ServiceRequestComment, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.ListServiceRequestComments")
+	defer span.End()
+
 	exists, err := r.serviceRequestExists(ctx, requestID)
 	if err != nil {
 		return nil, err
@@ -963,6 +1519,9 @@ func (r *ClientPortalRepository) DeleteServiceRequestCommentFile(
 	ctx context.Context,
 	input usecase.DeleteServiceRequestCommentFileInput,
 ) error {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.DeleteServiceRequestCommentFile")
+	defer span.End()
+
 	var owner struct {
 		UserID   string `db:"user_id"`
 		ClientID string `db:"client_id"`
@@ -1031,6 +1590,9 @@ func (r *ClientPortalRepository) DeleteServiceRequestComment(
 	ctx context.Context,
 	input usecase.DeleteServiceRequestCommentInput,
 ) error {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.DeleteServiceRequestComment")
+	defer span.End()
+
 	var owner struct {
 		UserID   string `db:"user_id"`
 		ClientID string `db:"client_id"`
@@ -1095,6 +1657,9 @@ func (r *ClientPortalRepository) UpdateServiceRequestComment(
 	ctx context.Context,
 	input usecase.UpdateServiceRequestCommentInput,
 ) error {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.UpdateServiceRequestComment")
+	defer span.End()
+
 	var owner struct {
 		UserID   string `db:"user_id"`
 		ClientID string `db:"client_id"`
@@ -1205,6 +1770,9 @@ func (r *ClientPortalRepository) CreateServiceRequestComment(
 	ctx context.Context,
 	input usecase.CreateServiceRequestCommentInput,
 ) (usecase.ServiceRequestComment, error) {
+	ctx, span := startSpan(ctx, "ClientPortalRepository.CreateServiceRequestComment")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return usecase.ServiceRequestComment{}, err
@@ -1320,18 +1888,28 @@ func (r *ClientPortalRepository) CreateServiceRequestComment(
 		}
 	}
 
+	if err := setServiceRequestActor(ctx, tx, input.AuthorUserID, input.AuthorClientID, ""); err != nil {
+		return usecase.ServiceRequestComment{}, err
+	}
+
+	// A team reply picks reopened requests back up and a client reply
+	// answers a request that was waiting for them.
 	if _, err := tx.ExecContext(
 		ctx,
 		`
 		UPDATE client_service_requests
 		SET status = CASE
 		      WHEN status = 'aberta' THEN 'em_andamento'
+		      WHEN status = 'reaberta' AND $2 <> '' THEN 'em_andamento'
+		      WHEN status = 'aguardando_cliente' AND $3 <> '' THEN 'em_andamento'
 		      ELSE status
 		    END,
 		    updated = NOW()
 		WHERE id = $1
 		`,
 		input.ServiceRequestID,
+		input.AuthorUserID,
+		input.AuthorClientID,
 	); err != nil {
 		return usecase.ServiceRequestComment{}, mapClientPortalPersistenceError(err)
 	}
@@ -1434,42 +2012,86 @@ func mapClientPortalAuthRecord(record clientPortalAuthRecord) usecase.ClientPort
 		Password: record.Password,
 		Avatar:   record.Avatar,
 		Active:   record.Active,
+
+		OrganizationID: record.OrganizationID,
+		Role:           record.Role,
 	}
 }
 
 func mapClientPortalAccountRecord(record clientPortalAccountRecord) usecase.ClientPortalAccount {
 	return usecase.ClientPortalAccount{
-		ID:      record.ID,
-		Name:    record.Name,
-		Email:   record.Email,
-		Login:   record.Login,
-		Avatar:  record.Avatar,
-		Active:  record.Active,
-		Created: record.Created,
-		Updated: record.Updated,
+		ID:               record.ID,
+		Name:             record.Name,
+		Email:            record.Email,
+		Login:            record.Login,
+		Avatar:           record.Avatar,
+		Active:           record.Active,
+		OrganizationID:   record.OrganizationID,
+		OrganizationName: record.OrganizationName,
+		Role:             record.Role,
+		Created:          record.Created,
+		Updated:          record.Updated,
 	}
 }
 
 func mapAdminServiceRequestRecord(record adminServiceRequestRecord) usecase.AdminServiceRequest {
 	return usecase.AdminServiceRequest{
-		ID:           record.ID,
-		ClientID:     record.ClientID,
-		ClientName:   record.ClientName,
-		ClientEmail:  record.ClientEmail,
-		ClientLogin:  record.ClientLogin,
-		ProjectID:    strings.TrimSpace(record.ProjectID),
-		ProjectName:  record.ProjectName,
-		Title:        record.Title,
-		Description:  record.Description,
-		Status:       normalizeServiceRequestStatus(record.Status),
-		Files:        []usecase.ClientServiceRequestFile{},
-		Comments:     record.Comments,
-		OpenComments: record.OpenComments,
-		Created:      record.Created,
-		Updated:      record.Updated,
+		ID:                record.ID,
+		ClientID:          record.ClientID,
+		ClientName:        record.ClientName,
+		ClientEmail:       record.ClientEmail,
+		ClientLogin:       record.ClientLogin,
+		ProjectID:         strings.TrimSpace(record.ProjectID),
+		ProjectName:       record.ProjectName,
+		Title:             record.Title,
+		Description:       record.Description,
+		Status:            normalizeServiceRequestStatus(record.Status),
+		StatusName:        record.StatusName,
+		StatusClosed:      record.StatusClosed,
+		Priority:          record.Priority,
+		CategoryID:        record.CategoryID,
+		CategoryName:      record.CategoryName,
+		AssignedUserID:    record.AssignedUserID,
+		AssignedUserName:  record.AssignedUserName,
+		ProjectTaskID:     record.ProjectTaskID,
+		ProjectTaskName:   record.ProjectTaskName,
+		ProjectTaskStatus: record.ProjectTaskStatus,
+		Files:             []usecase.ClientServiceRequestFile{},
+		Comments:          record.Comments,
+		OpenComments:      record.OpenComments,
+		SLATracking:       mapServiceRequestSLATracking(record),
+		Created:           record.Created,
+		Updated:           record.Updated,
 	}
 }
 
+// mapServiceRequestSLATracking returns nil when the request has no SLA row,
+// i.e. no policy applied when it was created.
+func mapServiceRequestSLATracking(record adminServiceRequestRecord) *usecase.ServiceRequestSLATracking {
+	if record.SLAFirstResponseDueAt == nil || record.SLAResolutionDueAt == nil {
+		return nil
+	}
+
+	tracking := &usecase.ServiceRequestSLATracking{
+		PolicyID:              record.SLAPolicyID.String,
+		PolicyName:            record.SLAPolicyName.String,
+		FirstResponseDueAt:    *record.SLAFirstResponseDueAt,
+		FirstRespondedAt:      record.SLAFirstRespondedAt,
+		FirstResponseBreached: record.SLAFirstResponseBreached.Bool,
+		ResolutionDueAt:       *record.SLAResolutionDueAt,
+		ResolvedAt:            record.SLAResolvedAt,
+		ResolutionBreached:    record.SLAResolutionBreached.Bool,
+	}
+	if record.SLAFirstResponseWarnAt != nil {
+		tracking.FirstResponseWarnAt = *record.SLAFirstResponseWarnAt
+	}
+	if record.SLAResolutionWarnAt != nil {
+		tracking.ResolutionWarnAt = *record.SLAResolutionWarnAt
+	}
+
+	return tracking
+}
+
 func mapClientPortalPersistenceError(err error) error {
 	mappedClientError := mapClientPersistenceError(err)
 	if mappedClientError != err {
@@ -1501,10 +2123,59 @@ func normalizeProjectStatus(value string) string {
 
 func normalizeServiceRequestStatus(value string) string {
 	normalized := strings.ToLower(strings.TrimSpace(value))
-	switch normalized {
-	case "aberta", "em_andamento", "concluida", "cancelada":
-		return normalized
-	default:
+	if normalized == "" {
 		return "aberta"
 	}
+	return normalized
+}
+
+// setServiceRequestActor tells the status history trigger who is changing the
+// request in this transaction and why.
+func setServiceRequestActor(ctx context.Context, tx *sqlx.Tx, userID, clientID, note string) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`
+		SELECT
+		  set_config('app.actor_user_id', $1, TRUE),
+		  set_config('app.actor_client_id', $2, TRUE),
+		  set_config('app.status_note', $3, TRUE)
+		`,
+		userID,
+		clientID,
+		note,
+	)
+	return err
+}
+
+func mapServiceRequestAssignmentError(err error) error {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "22P02", "23514":
+			return usecase.ErrInvalidInput
+		case "23503":
+			if strings.Contains(pgErr.Constraint, "category") {
+				return usecase.ErrServiceRequestCategoryNotFound
+			}
+			return usecase.ErrUserNotFound
+		}
+	}
+
+	return err
+}
+
+func mapServiceRequestLinkError(err error) error {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return usecase.ErrServiceRequestAlreadyConverted
+	}
+
+	return mapClientPortalPersistenceError(err)
+}
+
+func optionalStringArg(value *string) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+	return *value, true
 }
@@ -28,6 +28,8 @@ type clientListRecord struct {
 	Email          string    `db:"email"`
 	Login          string    `db:"login"`
 	Avatar         string    `db:"avatar"`
+	TaxID          string    `db:"tax_id"`
+	TaxIDType      string    `db:"tax_id_type"`
 	Phone          string    `db:"phone"`
 	Address        string    `db:"address"`
 	Active         bool      `db:"active"`
@@ -38,36 +40,39 @@ type clientListRecord struct {
 }
 
 type clientRecord struct {
-	ID      string    `db:"id"`
-	Name    string    `db:"name"`
-	Email   string    `db:"email"`
-	Login   string    `db:"login"`
-	Avatar  string    `db:"avatar"`
-	Active  bool      `db:"active"`
-	Created time.Time `db:"created"`
-	Updated time.Time `db:"updated"`
+	ID        string    `db:"id"`
+	Name      string    `db:"name"`
+	Email     string    `db:"email"`
+	Login     string    `db:"login"`
+	Avatar    string    `db:"avatar"`
+	TaxID     string    `db:"tax_id"`
+	TaxIDType string    `db:"tax_id_type"`
+	Active    bool      `db:"active"`
+	Created   time.Time `db:"created"`
+	Updated   time.Time `db:"updated"`
 }
 
 type clientAddressRecord struct {
-	ID           string    `db:"id"`
-	ClientID     string    `db:"client_id"`
-	Label        string    `db:"label"`
-	Country      string    `db:"country"`
-	ZipCode      string    `db:"zipcode"`
-	StreetType   string    `db:"street_type"`
-	StreetName   string    `db:"street_name"`
-	Street       string    `db:"street"`
-	Number       string    `db:"number"`
-	Neighborhood string    `db:"neighborhood"`
-	City         string    `db:"city"`
-	State        string    `db:"state"`
-	Complement   string    `db:"complement"`
-	Latitude     *float64  `db:"latitude"`
-	Longitude    *float64  `db:"longitude"`
-	Position     int       `db:"position"`
-	Active       bool      `db:"active"`
-	Created      time.Time `db:"created"`
-	Updated      time.Time `db:"updated"`
+	ID            string    `db:"id"`
+	ClientID      string    `db:"client_id"`
+	Label         string    `db:"label"`
+	Country       string    `db:"country"`
+	ZipCode       string    `db:"zipcode"`
+	StreetType    string    `db:"street_type"`
+	StreetName    string    `db:"street_name"`
+	Street        string    `db:"street"`
+	Number        string    `db:"number"`
+	Neighborhood  string    `db:"neighborhood"`
+	City          string    `db:"city"`
+	State         string    `db:"state"`
+	Complement    string    `db:"complement"`
+	Latitude      *float64  `db:"latitude"`
+	Longitude     *float64  `db:"longitude"`
+	GeocodeStatus string    `db:"geocode_status"`
+	Position      int       `db:"position"`
+	Active        bool      `db:"active"`
+	Created       time.Time `db:"created"`
+	Updated       time.Time `db:"updated"`
 }
 
 type clientPhoneRecord struct {
@@ -83,6 +88,9 @@ type clientPhoneRecord struct {
 }
 
 func (r *ClientRepository) List(ctx context.Context, onlyActive bool) ([]usecase.ClientListItem, error) {
+	ctx, span := startSpan(ctx, "ClientRepository.List")
+	defer span.End()
+
 	query := `
 		SELECT
 		  client.id,
@@ -90,6 +98,8 @@ func (r *ClientRepository) List(ctx context.Context, onlyActive bool) ([]usecase
 		  client.email,
 		  client.login,
 		  COALESCE(client.avatar, '') AS avatar,
+		  client.tax_id,
+		  CASE LENGTH(client.tax_id) WHEN 11 THEN 'cpf' WHEN 14 THEN 'cnpj' ELSE '' END AS tax_id_type,
 		  COALESCE((
 		    SELECT phone.phone_number
 		    FROM client_phones phone
@@ -137,6 +147,8 @@ func (r *ClientRepository) List(ctx context.Context, onlyActive bool) ([]usecase
 			Email:          record.Email,
 			Login:          record.Login,
 			Avatar:         record.Avatar,
+			TaxID:          record.TaxID,
+			TaxIDType:      record.TaxIDType,
 			Phone:          record.Phone,
 			Address:        record.Address,
 			Active:         record.Active,
@@ -151,12 +163,25 @@ func (r *ClientRepository) List(ctx context.Context, onlyActive bool) ([]usecase
 }
 
 func (r *ClientRepository) GetDetail(ctx context.Context, clientID string) (usecase.ClientDetail, error) {
+	ctx, span := startSpan(ctx, "ClientRepository.GetDetail")
+	defer span.End()
+
 	var client clientRecord
 	if err := r.db.GetContext(
 		ctx,
 		&client,
 		`
-		SELECT id, name, email, login, COALESCE(avatar, '') AS avatar, active, created, updated
+		SELECT
+		  id,
+		  name,
+		  email,
+		  login,
+		  COALESCE(avatar, '') AS avatar,
+		  tax_id,
+		  CASE LENGTH(tax_id) WHEN 11 THEN 'cpf' WHEN 14 THEN 'cnpj' ELSE '' END AS tax_id_type,
+		  active,
+		  created,
+		  updated
 		FROM clients
 		WHERE id = $1
 		LIMIT 1
@@ -190,6 +215,7 @@ func (r *ClientRepository) GetDetail(ctx context.Context, clientID string) (usec
 		  complement,
 		  latitude,
 		  longitude,
+		  geocode_status,
 		  position,
 		  active,
 		  created,
@@ -230,25 +256,26 @@ func (r *ClientRepository) GetDetail(ctx context.Context, clientID string) (usec
 	addresses := make([]usecase.ClientAddress, 0, len(addressRecords))
 	for _, address := range addressRecords {
 		addresses = append(addresses, usecase.ClientAddress{
-			ID:           address.ID,
-			ClientID:     address.ClientID,
-			Label:        address.Label,
-			Country:      address.Country,
-			ZipCode:      address.ZipCode,
-			StreetType:   address.StreetType,
-			StreetName:   address.StreetName,
-			Street:       address.Street,
-			Number:       address.Number,
-			Neighborhood: address.Neighborhood,
-			City:         address.City,
-			State:        address.State,
-			Complement:   address.Complement,
-			Latitude:     address.Latitude,
-			Longitude:    address.Longitude,
-			Position:     address.Position,
-			Active:       address.Active,
-			Created:      address.Created,
-			Updated:      address.Updated,
+			ID:            address.ID,
+			ClientID:      address.ClientID,
+			Label:         address.Label,
+			Country:       address.Country,
+			ZipCode:       address.ZipCode,
+			StreetType:    address.StreetType,
+			StreetName:    address.StreetName,
+			Street:        address.Street,
+			Number:        address.Number,
+			Neighborhood:  address.Neighborhood,
+			City:          address.City,
+			State:         address.State,
+			Complement:    address.Complement,
+			Latitude:      address.Latitude,
+			Longitude:     address.Longitude,
+			GeocodeStatus: address.GeocodeStatus,
+			Position:      address.Position,
+			Active:        address.Active,
+			Created:       address.Created,
+			Updated:       address.Updated,
 		})
 	}
 
@@ -273,6 +300,8 @@ func (r *ClientRepository) GetDetail(ctx context.Context, clientID string) (usec
 		Email:     client.Email,
 		Login:     client.Login,
 		Avatar:    client.Avatar,
+		TaxID:     client.TaxID,
+		TaxIDType: client.TaxIDType,
 		Active:    client.Active,
 		Created:   client.Created,
 		Updated:   client.Updated,
@@ -282,6 +311,9 @@ func (r *ClientRepository) GetDetail(ctx context.Context, clientID string) (usec
 }
 
 func (r *ClientRepository) Create(ctx context.Context, input usecase.CreateClientInput) (usecase.ClientDetail, error) {
+	ctx, span := startSpan(ctx, "ClientRepository.Create")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return usecase.ClientDetail{}, err
@@ -293,8 +325,8 @@ func (r *ClientRepository) Create(ctx context.Context, input usecase.CreateClien
 		ctx,
 		&clientID,
 		`
-		INSERT INTO clients (name, email, login, password, avatar, active, created, updated)
-		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, NOW(), NOW())
+		INSERT INTO clients (name, email, login, password, avatar, tax_id, active, created, updated)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, NOW(), NOW())
 		RETURNING id
 		`,
 		input.Name,
@@ -302,6 +334,7 @@ func (r *ClientRepository) Create(ctx context.Context, input usecase.CreateClien
 		input.Login,
 		input.Password,
 		input.Avatar,
+		input.TaxID,
 		input.Active,
 	); err != nil {
 		return usecase.ClientDetail{}, mapClientPersistenceError(err)
@@ -322,6 +355,9 @@ func (r *ClientRepository) Create(ctx context.Context, input usecase.CreateClien
 }
 
 func (r *ClientRepository) Update(ctx context.Context, input usecase.UpdateClientInput) (usecase.ClientDetail, error) {
+	ctx, span := startSpan(ctx, "ClientRepository.Update")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return usecase.ClientDetail{}, err
@@ -350,15 +386,17 @@ func (r *ClientRepository) Update(ctx context.Context, input usecase.UpdateClien
 		    login = $3,
 		    password = COALESCE(NULLIF($4, ''), password),
 		    avatar = NULLIF($5, ''),
-		    active = COALESCE($6::boolean, active),
+		    tax_id = COALESCE($6::text, tax_id),
+		    active = COALESCE($7::boolean, active),
 		    updated = NOW()
-		WHERE id = $7
+		WHERE id = $8
 		`,
 		input.Name,
 		input.Email,
 		input.Login,
 		input.Password,
 		input.Avatar,
+		input.TaxID,
 		input.Active,
 		input.ID,
 	); err != nil {
@@ -385,6 +423,9 @@ func (r *ClientRepository) Update(ctx context.Context, input usecase.UpdateClien
 }
 
 func (r *ClientRepository) Deactivate(ctx context.Context, clientID string) (usecase.ClientDetail, error) {
+	ctx, span := startSpan(ctx, "ClientRepository.Deactivate")
+	defer span.End()
+
 	result, err := r.db.ExecContext(
 		ctx,
 		`
@@ -416,6 +457,11 @@ func (r *ClientRepository) replaceClientAddresses(
 	clientID string,
 	addresses []usecase.ClientAddressInput,
 ) error {
+	previousGeocodes, err := loadAddressGeocodes(ctx, tx, clientID)
+	if err != nil {
+		return err
+	}
+
 	if _, err := tx.ExecContext(ctx, "DELETE FROM client_addresses WHERE client_id = $1", clientID); err != nil {
 		return err
 	}
@@ -441,6 +487,7 @@ func (r *ClientRepository) replaceClientAddresses(
 			return usecase.ErrInvalidInput
 		}
 
+		geocode := nextAddressGeocode(address, previousGeocodes)
 		if _, err := tx.ExecContext(
 			ctx,
 			`
@@ -459,13 +506,15 @@ func (r *ClientRepository) replaceClientAddresses(
 			  complement,
 			  latitude,
 			  longitude,
+			  geocode_status,
+			  geocoded_at,
 			  position,
 			  active,
 				  created,
 				  updated
 				)
 					VALUES (
-					  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW(), NOW()
+					  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, NOW(), NOW()
 					)
 					`,
 			clientID,
@@ -480,8 +529,10 @@ func (r *ClientRepository) replaceClientAddresses(
 			address.City,
 			address.State,
 			address.Complement,
-			address.Latitude,
-			address.Longitude,
+			geocode.Latitude,
+			geocode.Longitude,
+			geocode.Status,
+			geocode.GeocodedAt,
 			position,
 			active,
 		); err != nil {
@@ -557,6 +608,8 @@ func mapClientPersistenceError(err error) error {
 			return usecase.ErrClientLoginInUse
 		case "clients_email_lower_key":
 			return usecase.ErrClientEmailInUse
+		case "clients_tax_id_key":
+			return usecase.ErrClientTaxIDInUse
 		}
 	}
 
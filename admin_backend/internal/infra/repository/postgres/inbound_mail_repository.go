@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+)
+
+type InboundMailRepository struct {
+	db *sqlx.DB
+}
+
+func NewInboundMailRepository(db *sqlx.DB) *InboundMailRepository {
+	return &InboundMailRepository{db: db}
+}
+
+type inboundMailClientRecord struct {
+	ID    string `db:"id"`
+	Name  string `db:"name"`
+	Email string `db:"email"`
+}
+
+func (r *InboundMailRepository) FindClientByEmail(
+	ctx context.Context,
+	email string,
+) (usecase.InboundMailClient, error) {
+	ctx, span := startSpan(ctx, "InboundMailRepository.FindClientByEmail")
+	defer span.End()
+
+	var record inboundMailClientRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		`
+		SELECT id, name, email
+		FROM clients
+		WHERE LOWER(email) = LOWER($1)
+		  AND active = TRUE
+		`,
+		email,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.InboundMailClient{}, usecase.ErrNotFound
+		}
+		return usecase.InboundMailClient{}, err
+	}
+
+	return usecase.InboundMailClient{
+		ID:    record.ID,
+		Name:  record.Name,
+		Email: record.Email,
+	}, nil
+}
+
+func (r *InboundMailRepository) ReserveInboundEmail(
+	ctx context.Context,
+	input usecase.ReserveInboundEmailInput,
+) (string, bool, error) {
+	ctx, span := startSpan(ctx, "InboundMailRepository.ReserveInboundEmail")
+	defer span.End()
+
+	var id string
+	err := r.db.GetContext(
+		ctx,
+		&id,
+		`
+		INSERT INTO inbound_emails (message_id, from_address, subject)
+		VALUES ($1, $2, $3)
+		ON CONFLICT ON CONSTRAINT inbound_emails_message_id_key DO NOTHING
+		RETURNING id
+		`,
+		input.MessageID,
+		input.FromAddress,
+		input.Subject,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	return id, true, nil
+}
+
+func (r *InboundMailRepository) CompleteInboundEmail(
+	ctx context.Context,
+	input usecase.CompleteInboundEmailInput,
+) error {
+	ctx, span := startSpan(ctx, "InboundMailRepository.CompleteInboundEmail")
+	defer span.End()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`
+		UPDATE inbound_emails
+		SET
+		  status = $2,
+		  client_id = NULLIF($3, '')::uuid,
+		  service_request_id = NULLIF($4, '')::uuid,
+		  comment_id = NULLIF($5, '')::uuid,
+		  error = $6,
+		  updated = NOW()
+		WHERE id = $1
+		`,
+		input.ID,
+		input.Status,
+		input.ClientID,
+		input.ServiceRequestID,
+		input.CommentID,
+		input.Error,
+	)
+	return err
+}
+
+// ReleaseInboundEmail forgets a message whose processing failed, so the
+// MTA retry is processed again instead of being reported as a duplicate.
+func (r *InboundMailRepository) ReleaseInboundEmail(ctx context.Context, inboundEmailID string) error {
+	ctx, span := startSpan(ctx, "InboundMailRepository.ReleaseInboundEmail")
+	defer span.End()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		"DELETE FROM inbound_emails WHERE id = $1 AND status = 'processando'",
+		inboundEmailID,
+	)
+	return err
+}
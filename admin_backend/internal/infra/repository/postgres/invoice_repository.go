@@ -0,0 +1,682 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type InvoiceRepository struct {
+	db *sqlx.DB
+}
+
+func NewInvoiceRepository(db *sqlx.DB) *InvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+type invoiceSeriesRecord struct {
+	ID         string    `db:"id"`
+	Code       string    `db:"code"`
+	Name       string    `db:"name"`
+	Kind       string    `db:"kind"`
+	NextNumber int       `db:"next_number"`
+	Active     bool      `db:"active"`
+	Created    time.Time `db:"created"`
+	Updated    time.Time `db:"updated"`
+}
+
+type invoiceRecord struct {
+	ID                     string     `db:"id"`
+	SeriesID               string     `db:"series_id"`
+	SeriesCode             string     `db:"series_code"`
+	Number                 int        `db:"number"`
+	Kind                   string     `db:"kind"`
+	Status                 string     `db:"status"`
+	ProjectID              string     `db:"project_id"`
+	ClientID               string     `db:"client_id"`
+	ProjectRevenueID       string     `db:"project_revenue_id"`
+	ProjectMonthlyChargeID string     `db:"project_monthly_charge_id"`
+	OriginalInvoiceID      string     `db:"original_invoice_id"`
+	CreditNoteID           string     `db:"credit_note_id"`
+	ClientName             string     `db:"client_name"`
+	ClientEmail            string     `db:"client_email"`
+	BillingAddress         string     `db:"billing_address"`
+	BillingZipCode         string     `db:"billing_zip_code"`
+	BillingCity            string     `db:"billing_city"`
+	BillingState           string     `db:"billing_state"`
+	ProjectName            string     `db:"project_name"`
+	ProjectTypeName        string     `db:"project_type_name"`
+	IssuedOn               time.Time  `db:"issued_on"`
+	DueOn                  *time.Time `db:"due_on"`
+	ServicesAmount         float64    `db:"services_amount"`
+	IssRate                float64    `db:"iss_rate"`
+	IssAmount              float64    `db:"iss_amount"`
+	TotalAmount            float64    `db:"total_amount"`
+	Notes                  string     `db:"notes"`
+	CancelledAt            *time.Time `db:"cancelled_at"`
+	CancellationReason     string     `db:"cancellation_reason"`
+	Created                time.Time  `db:"created"`
+	Updated                time.Time  `db:"updated"`
+}
+
+type invoiceItemRecord struct {
+	ID          string  `db:"id"`
+	InvoiceID   string  `db:"invoice_id"`
+	Position    int     `db:"position"`
+	Description string  `db:"description"`
+	Quantity    float64 `db:"quantity"`
+	UnitPrice   float64 `db:"unit_price"`
+	Amount      float64 `db:"amount"`
+}
+
+const invoiceSelectSQL = `
+SELECT
+  invoice.id,
+  invoice.series_id,
+  series.code AS series_code,
+  invoice.number,
+  invoice.kind,
+  invoice.status,
+  invoice.project_id,
+  invoice.client_id,
+  COALESCE(invoice.project_revenue_id::text, '') AS project_revenue_id,
+  COALESCE(invoice.project_monthly_charge_id::text, '') AS project_monthly_charge_id,
+  COALESCE(invoice.original_invoice_id::text, '') AS original_invoice_id,
+  COALESCE((
+    SELECT credit_note.id::text
+    FROM invoices credit_note
+    WHERE credit_note.original_invoice_id = invoice.id
+      AND credit_note.kind = 'credit_note'
+    LIMIT 1
+  ), '') AS credit_note_id,
+  invoice.client_name,
+  invoice.client_email,
+  invoice.billing_address,
+  invoice.billing_zip_code,
+  invoice.billing_city,
+  invoice.billing_state,
+  invoice.project_name,
+  invoice.project_type_name,
+  invoice.issued_on,
+  invoice.due_on,
+  invoice.services_amount,
+  invoice.iss_rate,
+  invoice.iss_amount,
+  invoice.total_amount,
+  invoice.notes,
+  invoice.cancelled_at,
+  invoice.cancellation_reason,
+  invoice.created,
+  invoice.updated
+FROM invoices invoice
+INNER JOIN invoice_series series ON series.id = invoice.series_id
+`
+
+func (r *InvoiceRepository) ListInvoiceSeries(
+	ctx context.Context,
+) ([]usecase.InvoiceSeries, error) {
+	ctx, span := startSpan(ctx, "InvoiceRepository.ListInvoiceSeries")
+	defer span.End()
+
+	var records []invoiceSeriesRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		`
+		SELECT id, code, name, kind, next_number, active, created, updated
+		FROM invoice_series
+		ORDER BY kind ASC, code ASC, id ASC
+		`,
+	); err != nil {
+		return nil, err
+	}
+
+	series := make([]usecase.InvoiceSeries, 0, len(records))
+	for _, record := range records {
+		series = append(series, usecase.InvoiceSeries{
+			ID:         record.ID,
+			Code:       record.Code,
+			Name:       record.Name,
+			Kind:       record.Kind,
+			NextNumber: record.NextNumber,
+			Active:     record.Active,
+			Created:    record.Created,
+			Updated:    record.Updated,
+		})
+	}
+
+	return series, nil
+}
+
+func (r *InvoiceRepository) ListInvoices(
+	ctx context.Context,
+	filter usecase.InvoiceListFilter,
+) ([]usecase.Invoice, error) {
+	ctx, span := startSpan(ctx, "InvoiceRepository.ListInvoices")
+	defer span.End()
+
+	var records []invoiceRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		invoiceSelectSQL+`
+		WHERE ($1 = '' OR invoice.project_id::text = $1)
+		  AND ($2 = '' OR invoice.client_id::text = $2)
+		  AND ($3 = '' OR invoice.kind = $3)
+		  AND ($4 = '' OR invoice.status = $4)
+		ORDER BY invoice.issued_on DESC, series.code ASC, invoice.number DESC
+		`,
+		filter.ProjectID,
+		filter.ClientID,
+		filter.Kind,
+		filter.Status,
+	); err != nil {
+		return nil, err
+	}
+
+	return r.withInvoiceItems(ctx, records)
+}
+
+func (r *InvoiceRepository) GetInvoice(
+	ctx context.Context,
+	invoiceID string,
+) (usecase.Invoice, error) {
+	ctx, span := startSpan(ctx, "InvoiceRepository.GetInvoice")
+	defer span.End()
+
+	var records []invoiceRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		invoiceSelectSQL+"WHERE invoice.id::text = $1",
+		invoiceID,
+	); err != nil {
+		return usecase.Invoice{}, err
+	}
+	if len(records) == 0 {
+		return usecase.Invoice{}, usecase.ErrNotFound
+	}
+
+	invoices, err := r.withInvoiceItems(ctx, records)
+	if err != nil {
+		return usecase.Invoice{}, err
+	}
+
+	return invoices[0], nil
+}
+
+func (r *InvoiceRepository) GetProjectTypeIssRate(
+	ctx context.Context,
+	projectTypeID string,
+) (float64, error) {
+	ctx, span := startSpan(ctx, "InvoiceRepository.GetProjectTypeIssRate")
+	defer span.End()
+
+	var issRate float64
+	if err := r.db.GetContext(
+		ctx,
+		&issRate,
+		"SELECT iss_rate FROM project_types WHERE id::text = $1",
+		projectTypeID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, usecase.ErrProjectTypeNotFound
+		}
+		return 0, err
+	}
+
+	return issRate, nil
+}
+
+func (r *InvoiceRepository) IssueInvoice(
+	ctx context.Context,
+	input usecase.NewInvoice,
+) (usecase.Invoice, error) {
+	ctx, span := startSpan(ctx, "InvoiceRepository.IssueInvoice")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.Invoice{}, err
+	}
+	defer tx.Rollback()
+
+	seriesID, number, err := allocateInvoiceNumber(ctx, tx, input.SeriesCode, usecase.InvoiceKindInvoice)
+	if err != nil {
+		return usecase.Invoice{}, err
+	}
+
+	var invoiceID string
+	if err := tx.GetContext(
+		ctx,
+		&invoiceID,
+		`
+		INSERT INTO invoices (
+		  series_id,
+		  number,
+		  kind,
+		  status,
+		  project_id,
+		  client_id,
+		  project_revenue_id,
+		  project_monthly_charge_id,
+		  client_name,
+		  client_email,
+		  billing_address,
+		  billing_zip_code,
+		  billing_city,
+		  billing_state,
+		  project_name,
+		  project_type_name,
+		  issued_on,
+		  due_on,
+		  services_amount,
+		  iss_rate,
+		  iss_amount,
+		  total_amount,
+		  notes,
+		  created,
+		  updated
+		)
+		VALUES (
+		  $1, $2, 'invoice', 'emitida', $3, $4, NULLIF($5, '')::uuid, NULLIF($6, '')::uuid,
+		  $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, NOW(), NOW()
+		)
+		RETURNING id
+		`,
+		seriesID,
+		number,
+		input.ProjectID,
+		input.ClientID,
+		input.ProjectRevenueID,
+		input.ProjectMonthlyChargeID,
+		input.ClientName,
+		input.ClientEmail,
+		input.BillingAddress,
+		input.BillingZipCode,
+		input.BillingCity,
+		input.BillingState,
+		input.ProjectName,
+		input.ProjectTypeName,
+		input.IssuedOn,
+		input.DueOn,
+		input.ServicesAmount,
+		input.IssRate,
+		input.IssAmount,
+		input.TotalAmount,
+		input.Notes,
+	); err != nil {
+		return usecase.Invoice{}, mapInvoicePersistenceError(err)
+	}
+
+	if err := insertInvoiceItems(ctx, tx, invoiceID, input.Items); err != nil {
+		return usecase.Invoice{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.Invoice{}, mapInvoicePersistenceError(err)
+	}
+
+	return r.GetInvoice(ctx, invoiceID)
+}
+
+func (r *InvoiceRepository) CancelInvoice(
+	ctx context.Context,
+	input usecase.CancelInvoiceRecordInput,
+) (usecase.Invoice, error) {
+	ctx, span := startSpan(ctx, "InvoiceRepository.CancelInvoice")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.Invoice{}, err
+	}
+	defer tx.Rollback()
+
+	var original invoiceRecord
+	if err := tx.GetContext(
+		ctx,
+		&original,
+		`
+		SELECT
+		  id,
+		  kind,
+		  status,
+		  project_id,
+		  client_id,
+		  client_name,
+		  client_email,
+		  billing_address,
+		  billing_zip_code,
+		  billing_city,
+		  billing_state,
+		  project_name,
+		  project_type_name,
+		  services_amount,
+		  iss_rate,
+		  iss_amount,
+		  total_amount,
+		  number,
+		  series_id
+		FROM invoices
+		WHERE id::text = $1
+		FOR UPDATE
+		`,
+		input.ID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.Invoice{}, usecase.ErrNotFound
+		}
+		return usecase.Invoice{}, err
+	}
+	if original.Kind != usecase.InvoiceKindInvoice || original.Status != usecase.InvoiceStatusIssued {
+		return usecase.Invoice{}, usecase.ErrInvoiceNotCancellable
+	}
+
+	var originalSeriesCode string
+	if err := tx.GetContext(
+		ctx,
+		&originalSeriesCode,
+		"SELECT code FROM invoice_series WHERE id = $1",
+		original.SeriesID,
+	); err != nil {
+		return usecase.Invoice{}, err
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`
+		UPDATE invoices
+		SET status = 'cancelada',
+		    cancelled_at = $1,
+		    cancellation_reason = $2,
+		    updated = NOW()
+		WHERE id = $3
+		`,
+		input.CancelledAt,
+		input.Reason,
+		original.ID,
+	); err != nil {
+		return usecase.Invoice{}, mapInvoicePersistenceError(err)
+	}
+
+	seriesID, number, err := allocateInvoiceNumber(ctx, tx, input.CreditNoteSeriesCode, usecase.InvoiceKindCreditNote)
+	if err != nil {
+		return usecase.Invoice{}, err
+	}
+
+	var creditNoteID string
+	if err := tx.GetContext(
+		ctx,
+		&creditNoteID,
+		`
+		INSERT INTO invoices (
+		  series_id,
+		  number,
+		  kind,
+		  status,
+		  project_id,
+		  client_id,
+		  original_invoice_id,
+		  client_name,
+		  client_email,
+		  billing_address,
+		  billing_zip_code,
+		  billing_city,
+		  billing_state,
+		  project_name,
+		  project_type_name,
+		  issued_on,
+		  services_amount,
+		  iss_rate,
+		  iss_amount,
+		  total_amount,
+		  notes,
+		  created,
+		  updated
+		)
+		VALUES (
+		  $1, $2, 'credit_note', 'emitida', $3, $4, $5,
+		  $6, $7, $8, $9, $10, $11, $12, $13, $14::date, $15, $16, $17, $18, $19, NOW(), NOW()
+		)
+		RETURNING id
+		`,
+		seriesID,
+		number,
+		original.ProjectID,
+		original.ClientID,
+		original.ID,
+		original.ClientName,
+		original.ClientEmail,
+		original.BillingAddress,
+		original.BillingZipCode,
+		original.BillingCity,
+		original.BillingState,
+		original.ProjectName,
+		original.ProjectTypeName,
+		input.CancelledAt,
+		original.ServicesAmount,
+		original.IssRate,
+		original.IssAmount,
+		original.TotalAmount,
+		fmt.Sprintf(
+			"Estorno da nota %s. Motivo: %s",
+			usecase.FormatInvoiceNumber(originalSeriesCode, original.Number),
+			input.Reason,
+		),
+	); err != nil {
+		return usecase.Invoice{}, mapInvoicePersistenceError(err)
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`
+		INSERT INTO invoice_items (invoice_id, position, description, quantity, unit_price, amount, created)
+		SELECT $1, position, description, quantity, unit_price, amount, NOW()
+		FROM invoice_items
+		WHERE invoice_id = $2
+		`,
+		creditNoteID,
+		original.ID,
+	); err != nil {
+		return usecase.Invoice{}, mapInvoicePersistenceError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.Invoice{}, mapInvoicePersistenceError(err)
+	}
+
+	return r.GetInvoice(ctx, creditNoteID)
+}
+
+// allocateInvoiceNumber takes the next number of a series inside the caller's
+// transaction. The row lock held until commit serialises concurrent issuers,
+// and a rollback returns the number, so the sequence never has gaps.
+func allocateInvoiceNumber(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	seriesCode string,
+	kind string,
+) (string, int, error) {
+	var allocation struct {
+		ID     string `db:"id"`
+		Number int    `db:"number"`
+	}
+	if err := tx.GetContext(
+		ctx,
+		&allocation,
+		`
+		UPDATE invoice_series
+		SET next_number = next_number + 1,
+		    updated = NOW()
+		WHERE LOWER(code) = LOWER($1)
+		  AND kind = $2
+		  AND active = TRUE
+		RETURNING id, next_number - 1 AS number
+		`,
+		seriesCode,
+		kind,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", 0, usecase.ErrInvoiceSeriesNotFound
+		}
+		return "", 0, err
+	}
+
+	return allocation.ID, allocation.Number, nil
+}
+
+func insertInvoiceItems(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	invoiceID string,
+	items []usecase.InvoiceItem,
+) error {
+	for _, item := range items {
+		if _, err := tx.ExecContext(
+			ctx,
+			`
+			INSERT INTO invoice_items (
+			  invoice_id,
+			  position,
+			  description,
+			  quantity,
+			  unit_price,
+			  amount,
+			  created
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, NOW())
+			`,
+			invoiceID,
+			item.Position,
+			item.Description,
+			item.Quantity,
+			item.UnitPrice,
+			item.Amount,
+		); err != nil {
+			return mapInvoicePersistenceError(err)
+		}
+	}
+
+	return nil
+}
+
+func (r *InvoiceRepository) withInvoiceItems(
+	ctx context.Context,
+	records []invoiceRecord,
+) ([]usecase.Invoice, error) {
+	invoices := make([]usecase.Invoice, 0, len(records))
+	invoiceIDs := make([]string, 0, len(records))
+	invoiceIndexByID := make(map[string]int, len(records))
+
+	for _, record := range records {
+		invoiceIndexByID[record.ID] = len(invoices)
+		invoiceIDs = append(invoiceIDs, record.ID)
+		invoices = append(invoices, usecase.Invoice{
+			ID:                     record.ID,
+			SeriesID:               record.SeriesID,
+			SeriesCode:             record.SeriesCode,
+			Number:                 record.Number,
+			DisplayNumber:          usecase.FormatInvoiceNumber(record.SeriesCode, record.Number),
+			Kind:                   record.Kind,
+			Status:                 record.Status,
+			ProjectID:              record.ProjectID,
+			ClientID:               record.ClientID,
+			ProjectRevenueID:       record.ProjectRevenueID,
+			ProjectMonthlyChargeID: record.ProjectMonthlyChargeID,
+			OriginalInvoiceID:      record.OriginalInvoiceID,
+			CreditNoteID:           record.CreditNoteID,
+			ClientName:             record.ClientName,
+			ClientEmail:            record.ClientEmail,
+			BillingAddress:         record.BillingAddress,
+			BillingZipCode:         record.BillingZipCode,
+			BillingCity:            record.BillingCity,
+			BillingState:           record.BillingState,
+			ProjectName:            record.ProjectName,
+			ProjectTypeName:        record.ProjectTypeName,
+			IssuedOn:               record.IssuedOn,
+			DueOn:                  record.DueOn,
+			ServicesAmount:         record.ServicesAmount,
+			IssRate:                record.IssRate,
+			IssAmount:              record.IssAmount,
+			TotalAmount:            record.TotalAmount,
+			Notes:                  record.Notes,
+			CancelledAt:            record.CancelledAt,
+			CancellationReason:     record.CancellationReason,
+			Items:                  []usecase.InvoiceItem{},
+			Created:                record.Created,
+			Updated:                record.Updated,
+		})
+	}
+
+	if len(invoiceIDs) == 0 {
+		return invoices, nil
+	}
+
+	var itemRecords []invoiceItemRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&itemRecords,
+		`
+		SELECT id, invoice_id, position, description, quantity, unit_price, amount
+		FROM invoice_items
+		WHERE invoice_id = ANY($1::uuid[])
+		ORDER BY position ASC, id ASC
+		`,
+		pq.Array(invoiceIDs),
+	); err != nil {
+		return nil, err
+	}
+
+	for _, itemRecord := range itemRecords {
+		index, exists := invoiceIndexByID[itemRecord.InvoiceID]
+		if !exists {
+			continue
+		}
+		invoices[index].Items = append(invoices[index].Items, usecase.InvoiceItem{
+			ID:          itemRecord.ID,
+			Position:    itemRecord.Position,
+			Description: itemRecord.Description,
+			Quantity:    itemRecord.Quantity,
+			UnitPrice:   itemRecord.UnitPrice,
+			Amount:      itemRecord.Amount,
+		})
+	}
+
+	return invoices, nil
+}
+
+func mapInvoicePersistenceError(err error) error {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			switch pgErr.Constraint {
+			case "invoices_active_revenue_key",
+				"invoices_active_monthly_charge_key",
+				"invoices_credit_note_original_key":
+				return usecase.ErrInvoiceAlreadyIssued
+			}
+			return usecase.ErrConflict
+		case "23503":
+			if strings.HasPrefix(pgErr.Constraint, "invoices_client_id") {
+				return usecase.ErrInvoiceClientRequired
+			}
+			return usecase.ErrNotFound
+		case "23514":
+			if pgErr.Constraint == "invoices_immutable_check" {
+				return usecase.ErrInvoiceImmutable
+			}
+			return usecase.ErrInvalidInput
+		}
+	}
+
+	return err
+}
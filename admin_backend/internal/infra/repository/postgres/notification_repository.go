@@ -0,0 +1,353 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type NotificationRepository struct {
+	db *sqlx.DB
+}
+
+func NewNotificationRepository(db *sqlx.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+type notificationRecord struct {
+	ID               string     `db:"id"`
+	Type             string     `db:"type"`
+	Title            string     `db:"title"`
+	Message          string     `db:"message"`
+	ProjectID        string     `db:"project_id"`
+	ServiceRequestID string     `db:"service_request_id"`
+	ProjectTaskID    string     `db:"project_task_id"`
+	ReadAt           *time.Time `db:"read_at"`
+	Created          time.Time  `db:"created"`
+}
+
+const notificationSelectSQL = `
+SELECT
+  id,
+  type,
+  title,
+  message,
+  COALESCE(project_id::text, '') AS project_id,
+  COALESCE(service_request_id::text, '') AS service_request_id,
+  COALESCE(project_task_id::text, '') AS project_task_id,
+  read_at,
+  created
+FROM notifications
+`
+
+// notificationRecipientSQL matches the rows of one recipient; $1 is the user
+// id and $2 the client id, exactly one of them being non-empty.
+const notificationRecipientSQL = `
+(
+  ($1 <> '' AND recipient_user_id::text = $1)
+  OR ($2 <> '' AND recipient_client_id::text = $2)
+)
+`
+
+// CreateNotifications expands the event audience into recipients and stores
+// one notification for each of them. It returns how many were created.
+func (r *NotificationRepository) CreateNotifications(
+	ctx context.Context,
+	event usecase.NotificationEvent,
+) (int, error) {
+	ctx, span := startSpan(ctx, "NotificationRepository.CreateNotifications")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var userIDs []string
+	if err := tx.SelectContext(
+		ctx,
+		&userIDs,
+		`
+		SELECT DISTINCT app_user.id::text
+		FROM users app_user
+		WHERE app_user.ativo = TRUE
+		  AND (
+		    EXISTS (
+		      SELECT 1
+		      FROM project_managers manager
+		      WHERE $1 <> ''
+		        AND manager.project_id::text = $1
+		        AND manager.user_id = app_user.id
+		    )
+		    OR EXISTS (
+		      SELECT 1
+		      FROM project_tasks task
+		      WHERE $2 <> ''
+		        AND task.id::text = $2
+		        AND task.responsible_user_id = app_user.id
+		    )
+		    OR app_user.id::text = ANY($3)
+		  )
+		`,
+		event.Audience.ProjectManagersOf,
+		event.Audience.TaskResponsibleOf,
+		pq.Array(event.Audience.UserIDs),
+	); err != nil {
+		return 0, err
+	}
+
+	// The fallback looks at the resolved groups before the actor is removed,
+	// so a manager commenting on their own project does not notify everyone.
+	if len(userIDs) == 0 && event.Audience.AllUsersWhenEmpty {
+		if err := tx.SelectContext(
+			ctx,
+			&userIDs,
+			"SELECT id::text FROM users WHERE ativo = TRUE",
+		); err != nil {
+			return 0, err
+		}
+	}
+
+	clientIDs := make([]string, 0)
+	if err := tx.SelectContext(
+		ctx,
+		&clientIDs,
+		`
+		SELECT DISTINCT client.id::text
+		FROM clients client
+		WHERE client.active = TRUE
+		  AND (
+		    client.id::text = ANY($1)
+		    OR EXISTS (
+		      SELECT 1
+		      FROM project_clients project_client
+		      WHERE $2 <> ''
+		        AND project_client.project_id::text = $2
+		        AND project_client.client_id = client.id
+		    )
+		  )
+		`,
+		pq.Array(event.Audience.ClientIDs),
+		event.Audience.ProjectClientsOf,
+	); err != nil {
+		return 0, err
+	}
+
+	insertSQL := `
+		INSERT INTO notifications (
+		  recipient_user_id,
+		  recipient_client_id,
+		  type,
+		  title,
+		  message,
+		  project_id,
+		  service_request_id,
+		  project_task_id
+		)
+		VALUES (
+		  NULLIF($1, '')::uuid,
+		  NULLIF($2, '')::uuid,
+		  $3,
+		  $4,
+		  $5,
+		  NULLIF($6, '')::uuid,
+		  NULLIF($7, '')::uuid,
+		  NULLIF($8, '')::uuid
+		)
+		`
+
+	created := 0
+	insert := func(userID, clientID string) error {
+		if _, err := tx.ExecContext(
+			ctx,
+			insertSQL,
+			userID,
+			clientID,
+			event.Type,
+			event.Title,
+			event.Message,
+			event.ProjectID,
+			event.ServiceRequestID,
+			event.ProjectTaskID,
+		); err != nil {
+			return err
+		}
+		created++
+		return nil
+	}
+
+	for _, userID := range userIDs {
+		if userID == event.ActorUserID {
+			continue
+		}
+		if err := insert(userID, ""); err != nil {
+			return 0, err
+		}
+	}
+	for _, clientID := range clientIDs {
+		if clientID == event.ActorClientID {
+			continue
+		}
+		if err := insert("", clientID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return created, nil
+}
+
+func (r *NotificationRepository) ListNotifications(
+	ctx context.Context,
+	filter usecase.NotificationListFilter,
+) ([]usecase.Notification, error) {
+	ctx, span := startSpan(ctx, "NotificationRepository.ListNotifications")
+	defer span.End()
+
+	var records []notificationRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		notificationSelectSQL+`
+		WHERE `+notificationRecipientSQL+`
+		  AND ($3 = FALSE OR read_at IS NULL)
+		ORDER BY created DESC
+		LIMIT $4
+		`,
+		filter.Recipient.UserID,
+		filter.Recipient.ClientID,
+		filter.UnreadOnly,
+		filter.Limit,
+	); err != nil {
+		return nil, err
+	}
+
+	notifications := make([]usecase.Notification, 0, len(records))
+	for _, record := range records {
+		notifications = append(notifications, mapNotificationRecord(record))
+	}
+
+	return notifications, nil
+}
+
+func (r *NotificationRepository) CountUnreadNotifications(
+	ctx context.Context,
+	recipient usecase.NotificationRecipient,
+) (int, error) {
+	ctx, span := startSpan(ctx, "NotificationRepository.CountUnreadNotifications")
+	defer span.End()
+
+	var count int
+	if err := r.db.GetContext(
+		ctx,
+		&count,
+		`
+		SELECT COUNT(*)::int
+		FROM notifications
+		WHERE `+notificationRecipientSQL+`
+		  AND read_at IS NULL
+		`,
+		recipient.UserID,
+		recipient.ClientID,
+	); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *NotificationRepository) MarkNotificationRead(
+	ctx context.Context,
+	recipient usecase.NotificationRecipient,
+	notificationID string,
+) (usecase.Notification, error) {
+	ctx, span := startSpan(ctx, "NotificationRepository.MarkNotificationRead")
+	defer span.End()
+
+	var record notificationRecord
+	err := r.db.GetContext(
+		ctx,
+		&record,
+		`
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, NOW())
+		WHERE `+notificationRecipientSQL+`
+		  AND id::text = $3
+		RETURNING
+		  id,
+		  type,
+		  title,
+		  message,
+		  COALESCE(project_id::text, '') AS project_id,
+		  COALESCE(service_request_id::text, '') AS service_request_id,
+		  COALESCE(project_task_id::text, '') AS project_task_id,
+		  read_at,
+		  created
+		`,
+		recipient.UserID,
+		recipient.ClientID,
+		notificationID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.Notification{}, usecase.ErrNotFound
+		}
+		return usecase.Notification{}, err
+	}
+
+	return mapNotificationRecord(record), nil
+}
+
+func (r *NotificationRepository) MarkAllNotificationsRead(
+	ctx context.Context,
+	recipient usecase.NotificationRecipient,
+) (int, error) {
+	ctx, span := startSpan(ctx, "NotificationRepository.MarkAllNotificationsRead")
+	defer span.End()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		`
+		UPDATE notifications
+		SET read_at = NOW()
+		WHERE `+notificationRecipientSQL+`
+		  AND read_at IS NULL
+		`,
+		recipient.UserID,
+		recipient.ClientID,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(affected), nil
+}
+
+func mapNotificationRecord(record notificationRecord) usecase.Notification {
+	return usecase.Notification{
+		ID:               record.ID,
+		Type:             record.Type,
+		Title:            record.Title,
+		Message:          record.Message,
+		ProjectID:        record.ProjectID,
+		ServiceRequestID: record.ServiceRequestID,
+		ProjectTaskID:    record.ProjectTaskID,
+		Read:             record.ReadAt != nil,
+		ReadAt:           record.ReadAt,
+		Created:          record.Created,
+	}
+}
@@ -0,0 +1,337 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+)
+
+type PaymentReminderRepository struct {
+	db *sqlx.DB
+}
+
+func NewPaymentReminderRepository(db *sqlx.DB) *PaymentReminderRepository {
+	return &PaymentReminderRepository{db: db}
+}
+
+type paymentReminderRevenueRecord struct {
+	ID          string    `db:"id"`
+	ProjectID   string    `db:"project_id"`
+	ProjectName string    `db:"project_name"`
+	Title       string    `db:"title"`
+	Amount      float64   `db:"amount"`
+	ExpectedOn  time.Time `db:"expected_on"`
+	ClientID    string    `db:"client_id"`
+	ClientName  string    `db:"client_name"`
+	ClientEmail string    `db:"client_email"`
+}
+
+type paymentReminderChargeRecord struct {
+	ID          string     `db:"id"`
+	ProjectID   string     `db:"project_id"`
+	ProjectName string     `db:"project_name"`
+	Title       string     `db:"title"`
+	Amount      float64    `db:"amount"`
+	DueDay      int        `db:"due_day"`
+	StartsOn    *time.Time `db:"starts_on"`
+	Created     time.Time  `db:"created"`
+	ClientID    string     `db:"client_id"`
+	ClientName  string     `db:"client_name"`
+	ClientEmail string     `db:"client_email"`
+}
+
+type clientPaymentReminderPreferenceRecord struct {
+	ProjectID        string `db:"project_id"`
+	ProjectName      string `db:"project_name"`
+	RemindersEnabled bool   `db:"reminders_enabled"`
+}
+
+// ListPaymentReminderReceivables returns every pending revenue with an
+// expected date and every pending monthly charge, once per client that should
+// be reminded about it.
+func (r *PaymentReminderRepository) ListPaymentReminderReceivables(
+	ctx context.Context,
+) ([]usecase.PaymentReminderReceivable, error) {
+	ctx, span := startSpan(ctx, "PaymentReminderRepository.ListPaymentReminderReceivables")
+	defer span.End()
+
+	var revenueRecords []paymentReminderRevenueRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&revenueRecords,
+		`
+		SELECT DISTINCT
+		  revenue.id,
+		  revenue.project_id,
+		  project.name AS project_name,
+		  revenue.title,
+		  revenue.amount,
+		  revenue.expected_on,
+		  client.id AS client_id,
+		  client.name AS client_name,
+		  client.email AS client_email
+		FROM project_revenues revenue
+		INNER JOIN projects project ON project.id = revenue.project_id
+		INNER JOIN project_clients project_client ON project_client.project_id = project.id
+		INNER JOIN clients client ON client.id = project_client.client_id
+		WHERE revenue.status = 'pendente'
+		  AND revenue.active = TRUE
+		  AND revenue.expected_on IS NOT NULL
+		  AND project.active = TRUE
+		  AND client.active = TRUE
+		  AND TRIM(client.email) <> ''
+		  AND NOT EXISTS (
+		    SELECT 1
+		    FROM client_payment_reminder_opt_outs opt_out
+		    WHERE opt_out.client_id = client.id
+		      AND opt_out.project_id = project.id
+		  )
+		`,
+	); err != nil {
+		return nil, err
+	}
+
+	var chargeRecords []paymentReminderChargeRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&chargeRecords,
+		`
+		SELECT DISTINCT
+		  charge.id,
+		  charge.project_id,
+		  project.name AS project_name,
+		  charge.title,
+		  charge.amount,
+		  charge.due_day,
+		  charge.starts_on,
+		  charge.created,
+		  client.id AS client_id,
+		  client.name AS client_name,
+		  client.email AS client_email
+		FROM project_monthly_charges charge
+		INNER JOIN projects project ON project.id = charge.project_id
+		INNER JOIN project_clients project_client ON project_client.project_id = project.id
+		INNER JOIN clients client ON client.id = project_client.client_id
+		WHERE charge.status = 'pendente'
+		  AND charge.active = TRUE
+		  AND project.active = TRUE
+		  AND client.active = TRUE
+		  AND TRIM(client.email) <> ''
+		  AND NOT EXISTS (
+		    SELECT 1
+		    FROM client_payment_reminder_opt_outs opt_out
+		    WHERE opt_out.client_id = client.id
+		      AND opt_out.project_id = project.id
+		  )
+		`,
+	); err != nil {
+		return nil, err
+	}
+
+	receivables := make([]usecase.PaymentReminderReceivable, 0, len(revenueRecords)+len(chargeRecords))
+	for _, record := range revenueRecords {
+		receivables = append(receivables, usecase.PaymentReminderReceivable{
+			SourceType:  usecase.PaymentReminderSourceRevenue,
+			SourceID:    record.ID,
+			ProjectID:   record.ProjectID,
+			ProjectName: record.ProjectName,
+			Title:       record.Title,
+			Amount:      record.Amount,
+			DueOn:       record.ExpectedOn,
+			ClientID:    record.ClientID,
+			ClientName:  record.ClientName,
+			ClientEmail: record.ClientEmail,
+		})
+	}
+	for _, record := range chargeRecords {
+		receivables = append(receivables, usecase.PaymentReminderReceivable{
+			SourceType:  usecase.PaymentReminderSourceMonthlyCharge,
+			SourceID:    record.ID,
+			ProjectID:   record.ProjectID,
+			ProjectName: record.ProjectName,
+			Title:       record.Title,
+			Amount:      record.Amount,
+			DueOn: usecase.ProjectMonthlyChargeDueDate(usecase.ProjectMonthlyCharge{
+				DueDay:   record.DueDay,
+				StartsOn: record.StartsOn,
+				Created:  record.Created,
+			}),
+			ClientID:    record.ClientID,
+			ClientName:  record.ClientName,
+			ClientEmail: record.ClientEmail,
+		})
+	}
+
+	return receivables, nil
+}
+
+// ReservePaymentReminder records the reminder before it is sent. It reports
+// false when the same reminder was already recorded.
+func (r *PaymentReminderRepository) ReservePaymentReminder(
+	ctx context.Context,
+	input usecase.PaymentReminderRecordInput,
+) (bool, error) {
+	ctx, span := startSpan(ctx, "PaymentReminderRepository.ReservePaymentReminder")
+	defer span.End()
+
+	var reminderID string
+	err := r.db.GetContext(
+		ctx,
+		&reminderID,
+		`
+		INSERT INTO payment_reminders (
+		  source_type,
+		  source_id,
+		  client_id,
+		  due_on,
+		  offset_days,
+		  email
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT ON CONSTRAINT payment_reminders_unique_key DO NOTHING
+		RETURNING id
+		`,
+		input.SourceType,
+		input.SourceID,
+		input.ClientID,
+		input.DueOn,
+		input.OffsetDays,
+		input.Email,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *PaymentReminderRepository) ReleasePaymentReminder(
+	ctx context.Context,
+	input usecase.PaymentReminderRecordInput,
+) error {
+	ctx, span := startSpan(ctx, "PaymentReminderRepository.ReleasePaymentReminder")
+	defer span.End()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`
+		DELETE FROM payment_reminders
+		WHERE source_type = $1
+		  AND source_id = $2
+		  AND client_id = $3
+		  AND due_on = $4
+		  AND offset_days = $5
+		`,
+		input.SourceType,
+		input.SourceID,
+		input.ClientID,
+		input.DueOn,
+		input.OffsetDays,
+	)
+	return err
+}
+
+func (r *PaymentReminderRepository) ListClientPaymentReminderPreferences(
+	ctx context.Context,
+	clientID string,
+) ([]usecase.ClientPaymentReminderPreference, error) {
+	ctx, span := startSpan(ctx, "PaymentReminderRepository.ListClientPaymentReminderPreferences")
+	defer span.End()
+
+	var records []clientPaymentReminderPreferenceRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		`
+		SELECT DISTINCT
+		  project.id AS project_id,
+		  project.name AS project_name,
+		  NOT EXISTS (
+		    SELECT 1
+		    FROM client_payment_reminder_opt_outs opt_out
+		    WHERE opt_out.client_id = project_client.client_id
+		      AND opt_out.project_id = project.id
+		  ) AS reminders_enabled
+		FROM project_clients project_client
+		INNER JOIN projects project ON project.id = project_client.project_id
+		WHERE project_client.client_id::text = $1
+		ORDER BY project_name ASC
+		`,
+		clientID,
+	); err != nil {
+		return nil, err
+	}
+
+	preferences := make([]usecase.ClientPaymentReminderPreference, 0, len(records))
+	for _, record := range records {
+		preferences = append(preferences, usecase.ClientPaymentReminderPreference{
+			ProjectID:        record.ProjectID,
+			ProjectName:      record.ProjectName,
+			RemindersEnabled: record.RemindersEnabled,
+		})
+	}
+
+	return preferences, nil
+}
+
+func (r *PaymentReminderRepository) SetClientPaymentReminderOptOut(
+	ctx context.Context,
+	input usecase.UpdateClientPaymentReminderPreferenceInput,
+) error {
+	ctx, span := startSpan(ctx, "PaymentReminderRepository.SetClientPaymentReminderOptOut")
+	defer span.End()
+
+	var linked bool
+	if err := r.db.GetContext(
+		ctx,
+		&linked,
+		`
+		SELECT EXISTS (
+		  SELECT 1
+		  FROM project_clients
+		  WHERE client_id::text = $1
+		    AND project_id::text = $2
+		)
+		`,
+		input.ClientID,
+		input.ProjectID,
+	); err != nil {
+		return err
+	}
+	if !linked {
+		return usecase.ErrNotFound
+	}
+
+	if input.OptOut {
+		_, err := r.db.ExecContext(
+			ctx,
+			`
+			INSERT INTO client_payment_reminder_opt_outs (client_id, project_id)
+			VALUES ($1, $2)
+			ON CONFLICT (client_id, project_id) DO NOTHING
+			`,
+			input.ClientID,
+			input.ProjectID,
+		)
+		return err
+	}
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`
+		DELETE FROM client_payment_reminder_opt_outs
+		WHERE client_id::text = $1
+		  AND project_id::text = $2
+		`,
+		input.ClientID,
+		input.ProjectID,
+	)
+	return err
+}
@@ -0,0 +1,405 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type PaymentRepository struct {
+	db *sqlx.DB
+}
+
+func NewPaymentRepository(db *sqlx.DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+type paymentInstructionRecord struct {
+	ID                     string     `db:"id"`
+	ProjectID              string     `db:"project_id"`
+	ProjectMonthlyChargeID string     `db:"project_monthly_charge_id"`
+	Provider               string     `db:"provider"`
+	ProviderReference      string     `db:"provider_reference"`
+	Method                 string     `db:"method"`
+	Status                 string     `db:"status"`
+	Amount                 float64    `db:"amount"`
+	DueOn                  time.Time  `db:"due_on"`
+	Description            string     `db:"description"`
+	PixCopyPaste           string     `db:"pix_copy_paste"`
+	BoletoBarcode          string     `db:"boleto_barcode"`
+	BoletoDigitableLine    string     `db:"boleto_digitable_line"`
+	PaidAt                 *time.Time `db:"paid_at"`
+	PaidAmount             float64    `db:"paid_amount"`
+	Created                time.Time  `db:"created"`
+	Updated                time.Time  `db:"updated"`
+}
+
+const paymentInstructionSelectSQL = `
+SELECT
+  id,
+  project_id,
+  project_monthly_charge_id,
+  provider,
+  provider_reference,
+  method,
+  status,
+  amount,
+  due_on,
+  description,
+  pix_copy_paste,
+  boleto_barcode,
+  boleto_digitable_line,
+  paid_at,
+  paid_amount,
+  created,
+  updated
+FROM payment_instructions
+`
+
+func (r *PaymentRepository) ListPaymentInstructions(
+	ctx context.Context,
+	filter usecase.PaymentInstructionListFilter,
+) ([]usecase.PaymentInstruction, error) {
+	ctx, span := startSpan(ctx, "PaymentRepository.ListPaymentInstructions")
+	defer span.End()
+
+	var records []paymentInstructionRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		paymentInstructionSelectSQL+`
+		WHERE ($1 = '' OR project_id::text = $1)
+		  AND ($2 = '' OR project_monthly_charge_id::text = $2)
+		  AND ($3 = '' OR status = $3)
+		ORDER BY due_on ASC, created ASC, id ASC
+		`,
+		filter.ProjectID,
+		filter.MonthlyChargeID,
+		filter.Status,
+	); err != nil {
+		return nil, err
+	}
+
+	instructions := make([]usecase.PaymentInstruction, 0, len(records))
+	for _, record := range records {
+		instructions = append(instructions, mapPaymentInstructionRecord(record))
+	}
+
+	return instructions, nil
+}
+
+func (r *PaymentRepository) GetPaymentInstruction(
+	ctx context.Context,
+	instructionID string,
+) (usecase.PaymentInstruction, error) {
+	ctx, span := startSpan(ctx, "PaymentRepository.GetPaymentInstruction")
+	defer span.End()
+
+	var record paymentInstructionRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		paymentInstructionSelectSQL+"WHERE id::text = $1",
+		instructionID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.PaymentInstruction{}, usecase.ErrNotFound
+		}
+		return usecase.PaymentInstruction{}, err
+	}
+
+	return mapPaymentInstructionRecord(record), nil
+}
+
+func (r *PaymentRepository) FindPendingPaymentInstruction(
+	ctx context.Context,
+	monthlyChargeID string,
+	method string,
+) (usecase.PaymentInstruction, error) {
+	ctx, span := startSpan(ctx, "PaymentRepository.FindPendingPaymentInstruction")
+	defer span.End()
+
+	var record paymentInstructionRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		paymentInstructionSelectSQL+`
+		WHERE project_monthly_charge_id::text = $1
+		  AND method = $2
+		  AND status = 'pendente'
+		`,
+		monthlyChargeID,
+		method,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.PaymentInstruction{}, usecase.ErrNotFound
+		}
+		return usecase.PaymentInstruction{}, err
+	}
+
+	return mapPaymentInstructionRecord(record), nil
+}
+
+func (r *PaymentRepository) CreatePaymentInstruction(
+	ctx context.Context,
+	input usecase.NewPaymentInstruction,
+) (usecase.PaymentInstruction, error) {
+	ctx, span := startSpan(ctx, "PaymentRepository.CreatePaymentInstruction")
+	defer span.End()
+
+	var record paymentInstructionRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		`
+		INSERT INTO payment_instructions (
+		  project_id,
+		  project_monthly_charge_id,
+		  provider,
+		  provider_reference,
+		  method,
+		  status,
+		  amount,
+		  due_on,
+		  description,
+		  pix_copy_paste,
+		  boleto_barcode,
+		  boleto_digitable_line,
+		  created,
+		  updated
+		)
+		VALUES ($1, $2, $3, $4, $5, 'pendente', $6, $7, $8, $9, $10, $11, NOW(), NOW())
+		RETURNING
+		  id,
+		  project_id,
+		  project_monthly_charge_id,
+		  provider,
+		  provider_reference,
+		  method,
+		  status,
+		  amount,
+		  due_on,
+		  description,
+		  pix_copy_paste,
+		  boleto_barcode,
+		  boleto_digitable_line,
+		  paid_at,
+		  paid_amount,
+		  created,
+		  updated
+		`,
+		input.ProjectID,
+		input.ProjectMonthlyChargeID,
+		input.Provider,
+		input.ProviderReference,
+		input.Method,
+		input.Amount,
+		input.DueOn,
+		input.Description,
+		input.PixCopyPaste,
+		input.BoletoBarcode,
+		input.BoletoDigitableLine,
+	); err != nil {
+		return usecase.PaymentInstruction{}, mapPaymentPersistenceError(err)
+	}
+
+	return mapPaymentInstructionRecord(record), nil
+}
+
+func (r *PaymentRepository) ReconcilePaymentWebhook(
+	ctx context.Context,
+	input usecase.ReconcilePaymentWebhookInput,
+) (usecase.PaymentWebhookResult, error) {
+	ctx, span := startSpan(ctx, "PaymentRepository.ReconcilePaymentWebhook")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.PaymentWebhookResult{}, err
+	}
+	defer tx.Rollback()
+
+	result := usecase.PaymentWebhookResult{
+		EventID: input.EventID,
+		Outcome: usecase.PaymentWebhookOutcomeUnmatched,
+	}
+
+	var record paymentInstructionRecord
+	instructionFound := false
+	if input.ProviderReference != "" {
+		err := tx.GetContext(
+			ctx,
+			&record,
+			paymentInstructionSelectSQL+`
+			WHERE provider = $1
+			  AND provider_reference = $2
+			FOR UPDATE
+			`,
+			input.Provider,
+			input.ProviderReference,
+		)
+		switch {
+		case err == nil:
+			instructionFound = true
+		case !errors.Is(err, sql.ErrNoRows):
+			return usecase.PaymentWebhookResult{}, err
+		}
+	}
+
+	if instructionFound {
+		switch {
+		case input.EventType != usecase.PaymentWebhookEventPaymentConfirmed,
+			record.Status != usecase.PaymentInstructionStatusPending:
+			result.Outcome = usecase.PaymentWebhookOutcomeIgnored
+		case input.AmountPaid+0.005 < record.Amount:
+			result.Outcome = usecase.PaymentWebhookOutcomeUnderpaid
+		default:
+			result.Outcome = usecase.PaymentWebhookOutcomeReconciled
+			if err := tx.GetContext(
+				ctx,
+				&record,
+				`
+				UPDATE payment_instructions
+				SET
+				  status = 'pago',
+				  paid_at = $2,
+				  paid_amount = $3,
+				  updated = NOW()
+				WHERE id = $1
+				RETURNING
+				  id,
+				  project_id,
+				  project_monthly_charge_id,
+				  provider,
+				  provider_reference,
+				  method,
+				  status,
+				  amount,
+				  due_on,
+				  description,
+				  pix_copy_paste,
+				  boleto_barcode,
+				  boleto_digitable_line,
+				  paid_at,
+				  paid_amount,
+				  created,
+				  updated
+				`,
+				record.ID,
+				input.PaidAt,
+				input.AmountPaid,
+			); err != nil {
+				return usecase.PaymentWebhookResult{}, err
+			}
+
+			if _, err := tx.ExecContext(
+				ctx,
+				`
+				UPDATE project_monthly_charges
+				SET status = 'pago', received_on = $2::date, updated = NOW()
+				WHERE id = $1
+				  AND status = 'pendente'
+				`,
+				record.ProjectMonthlyChargeID,
+				input.PaidAt,
+			); err != nil {
+				return usecase.PaymentWebhookResult{}, err
+			}
+		}
+
+		instruction := mapPaymentInstructionRecord(record)
+		result.Instruction = &instruction
+	}
+
+	var instructionID *string
+	if instructionFound {
+		instructionID = &record.ID
+	}
+
+	// A replayed event hits the unique (provider, event_id) key and inserts
+	// nothing; rolling back discards any changes made above.
+	var eventRecordID string
+	if err := tx.GetContext(
+		ctx,
+		&eventRecordID,
+		`
+		INSERT INTO payment_webhook_events (
+		  provider,
+		  event_id,
+		  event_type,
+		  payment_instruction_id,
+		  outcome,
+		  amount_paid,
+		  payload,
+		  received
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb, NOW())
+		ON CONFLICT (provider, event_id) DO NOTHING
+		RETURNING id
+		`,
+		input.Provider,
+		input.EventID,
+		input.EventType,
+		instructionID,
+		result.Outcome,
+		input.AmountPaid,
+		string(input.Payload),
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.PaymentWebhookResult{
+				EventID: input.EventID,
+				Outcome: usecase.PaymentWebhookOutcomeDuplicate,
+			}, nil
+		}
+		return usecase.PaymentWebhookResult{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.PaymentWebhookResult{}, err
+	}
+
+	return result, nil
+}
+
+func mapPaymentInstructionRecord(record paymentInstructionRecord) usecase.PaymentInstruction {
+	return usecase.PaymentInstruction{
+		ID:                     record.ID,
+		ProjectID:              record.ProjectID,
+		ProjectMonthlyChargeID: record.ProjectMonthlyChargeID,
+		Provider:               record.Provider,
+		ProviderReference:      record.ProviderReference,
+		Method:                 record.Method,
+		Status:                 record.Status,
+		Amount:                 record.Amount,
+		DueOn:                  record.DueOn,
+		Description:            record.Description,
+		PixCopyPaste:           record.PixCopyPaste,
+		BoletoBarcode:          record.BoletoBarcode,
+		BoletoDigitableLine:    record.BoletoDigitableLine,
+		PaidAt:                 record.PaidAt,
+		PaidAmount:             record.PaidAmount,
+		Created:                record.Created,
+		Updated:                record.Updated,
+	}
+}
+
+func mapPaymentPersistenceError(err error) error {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return usecase.ErrConflict
+		case "23503":
+			return usecase.ErrNotFound
+		case "23514":
+			return usecase.ErrInvalidInput
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,968 @@
+package postgres
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type projectExpenseCategoryRecord struct {
+	ID          string    `db:"id"`
+	Code        string    `db:"code"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Active      bool      `db:"active"`
+	Created     time.Time `db:"created"`
+	Updated     time.Time `db:"updated"`
+}
+
+type projectExpenseRecord struct {
+	ID           string    `db:"id"`
+	ProjectID    string    `db:"project_id"`
+	CategoryID   string    `db:"category_id"`
+	CategoryCode string    `db:"category_code"`
+	CategoryName string    `db:"category_name"`
+	Title        string    `db:"title"`
+	Description  string    `db:"description"`
+	Supplier     string    `db:"supplier"`
+	Amount       float64   `db:"amount"`
+	IncurredOn   time.Time `db:"incurred_on"`
+	Active       bool      `db:"active"`
+	Created      time.Time `db:"created"`
+	Updated      time.Time `db:"updated"`
+}
+
+type projectExpenseReceiptRecord struct {
+	ID               string     `db:"id"`
+	ProjectExpenseID string     `db:"project_expense_id"`
+	FileName         string     `db:"file_name"`
+	FileKey          string     `db:"file_key"`
+	ContentType      string     `db:"content_type"`
+	IssuedOn         *time.Time `db:"issued_on"`
+	Notes            string     `db:"notes"`
+	Created          time.Time  `db:"created"`
+	Updated          time.Time  `db:"updated"`
+}
+
+type userHourlyRateRecord struct {
+	ID         string    `db:"id"`
+	UserID     string    `db:"user_id"`
+	UserName   string    `db:"user_name"`
+	HourlyCost float64   `db:"hourly_cost"`
+	ValidFrom  time.Time `db:"valid_from"`
+	Notes      string    `db:"notes"`
+	Created    time.Time `db:"created"`
+	Updated    time.Time `db:"updated"`
+}
+
+type projectTimeEntryRecord struct {
+	ID              string    `db:"id"`
+	ProjectID       string    `db:"project_id"`
+	ProjectTaskID   string    `db:"project_task_id"`
+	ProjectTaskName string    `db:"project_task_name"`
+	UserID          string    `db:"user_id"`
+	UserName        string    `db:"user_name"`
+	WorkedOn        time.Time `db:"worked_on"`
+	Hours           float64   `db:"hours"`
+	HourlyCost      float64   `db:"hourly_cost"`
+	Description     string    `db:"description"`
+	Created         time.Time `db:"created"`
+	Updated         time.Time `db:"updated"`
+}
+
+type projectFinancialEntryRecord struct {
+	ProjectID   string    `db:"project_id"`
+	ProjectName string    `db:"project_name"`
+	Kind        string    `db:"kind"`
+	OccurredOn  time.Time `db:"occurred_on"`
+	Amount      float64   `db:"amount"`
+	Hours       float64   `db:"hours"`
+}
+
+type projectFinancialChargeRecord struct {
+	projectMonthlyChargeRecord
+	ProjectName string `db:"project_name"`
+}
+
+// userHourlyCostOnWorkedDateSQL prices a time entry with the most recent rate
+// whose valid_from is on or before the day the work happened.
+const userHourlyCostOnWorkedDateSQL = `
+COALESCE((
+  SELECT rate.hourly_cost
+  FROM user_hourly_rates rate
+  WHERE rate.user_id = entry.user_id
+    AND rate.valid_from <= entry.worked_on
+  ORDER BY rate.valid_from DESC
+  LIMIT 1
+), 0)
+`
+
+func (r *ProjectRepository) ListProjectExpenseCategories(
+	ctx context.Context,
+) ([]usecase.ProjectExpenseCategory, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.ListProjectExpenseCategories")
+	defer span.End()
+
+	var records []projectExpenseCategoryRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		`
+		SELECT id, code, name, description, active, created, updated
+		FROM project_expense_categories
+		ORDER BY name ASC, id ASC
+		`,
+	); err != nil {
+		return nil, err
+	}
+
+	categories := make([]usecase.ProjectExpenseCategory, 0, len(records))
+	for _, record := range records {
+		categories = append(categories, mapProjectExpenseCategoryRecord(record))
+	}
+
+	return categories, nil
+}
+
+func (r *ProjectRepository) CreateProjectExpenseCategory(
+	ctx context.Context,
+	input usecase.CreateProjectExpenseCategoryInput,
+) (usecase.ProjectExpenseCategory, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.CreateProjectExpenseCategory")
+	defer span.End()
+
+	var record projectExpenseCategoryRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		`
+		INSERT INTO project_expense_categories (
+		  code,
+		  name,
+		  description,
+		  active,
+		  created,
+		  updated
+		)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING id, code, name, description, active, created, updated
+		`,
+		input.Code,
+		input.Name,
+		input.Description,
+		input.Active,
+	); err != nil {
+		return usecase.ProjectExpenseCategory{}, mapProjectPersistenceError(err)
+	}
+
+	return mapProjectExpenseCategoryRecord(record), nil
+}
+
+func (r *ProjectRepository) ListProjectExpenses(
+	ctx context.Context,
+	projectID string,
+) ([]usecase.ProjectExpense, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.ListProjectExpenses")
+	defer span.End()
+
+	exists, err := r.projectExists(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, usecase.ErrNotFound
+	}
+
+	var records []projectExpenseRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		`
+		SELECT
+		  expense.id,
+		  expense.project_id,
+		  expense.category_id,
+		  category.code AS category_code,
+		  category.name AS category_name,
+		  expense.title,
+		  expense.description,
+		  expense.supplier,
+		  expense.amount,
+		  expense.incurred_on,
+		  expense.active,
+		  expense.created,
+		  expense.updated
+		FROM project_expenses expense
+		INNER JOIN project_expense_categories category ON category.id = expense.category_id
+		WHERE expense.project_id = $1
+		ORDER BY expense.incurred_on DESC, expense.created DESC, expense.id ASC
+		`,
+		projectID,
+	); err != nil {
+		return nil, err
+	}
+
+	return r.withExpenseReceipts(ctx, records)
+}
+
+func (r *ProjectRepository) CreateProjectExpense(
+	ctx context.Context,
+	input usecase.CreateProjectExpenseInput,
+) (usecase.ProjectExpense, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.CreateProjectExpense")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.ProjectExpense{}, err
+	}
+	defer tx.Rollback()
+
+	var expenseID string
+	if err := tx.GetContext(
+		ctx,
+		&expenseID,
+		`
+		INSERT INTO project_expenses (
+		  project_id,
+		  category_id,
+		  title,
+		  description,
+		  supplier,
+		  amount,
+		  incurred_on,
+		  active,
+		  created,
+		  updated
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		RETURNING id
+		`,
+		input.ProjectID,
+		input.CategoryID,
+		input.Title,
+		input.Description,
+		input.Supplier,
+		input.Amount,
+		input.IncurredOn,
+		input.Active,
+	); err != nil {
+		return usecase.ProjectExpense{}, mapProjectPersistenceError(err)
+	}
+
+	if err := insertProjectExpenseReceipts(ctx, tx, expenseID, input.Receipts); err != nil {
+		return usecase.ProjectExpense{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.ProjectExpense{}, err
+	}
+
+	return r.findProjectExpense(ctx, input.ProjectID, expenseID)
+}
+
+func (r *ProjectRepository) UpdateProjectExpense(
+	ctx context.Context,
+	input usecase.UpdateProjectExpenseInput,
+) (usecase.ProjectExpense, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.UpdateProjectExpense")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.ProjectExpense{}, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(
+		ctx,
+		`
+		UPDATE project_expenses
+		SET category_id = $1,
+		    title = $2,
+		    description = $3,
+		    supplier = $4,
+		    amount = $5,
+		    incurred_on = $6,
+		    active = $7,
+		    updated = NOW()
+		WHERE id = $8
+		  AND project_id = $9
+		`,
+		input.CategoryID,
+		input.Title,
+		input.Description,
+		input.Supplier,
+		input.Amount,
+		input.IncurredOn,
+		input.Active,
+		input.ID,
+		input.ProjectID,
+	)
+	if err != nil {
+		return usecase.ProjectExpense{}, mapProjectPersistenceError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return usecase.ProjectExpense{}, err
+	}
+	if rowsAffected == 0 {
+		return usecase.ProjectExpense{}, usecase.ErrNotFound
+	}
+
+	if input.Receipts != nil {
+		if _, err := tx.ExecContext(
+			ctx,
+			"DELETE FROM project_expense_receipts WHERE project_expense_id = $1",
+			input.ID,
+		); err != nil {
+			return usecase.ProjectExpense{}, err
+		}
+		if err := insertProjectExpenseReceipts(ctx, tx, input.ID, *input.Receipts); err != nil {
+			return usecase.ProjectExpense{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.ProjectExpense{}, err
+	}
+
+	return r.findProjectExpense(ctx, input.ProjectID, input.ID)
+}
+
+func (r *ProjectRepository) DeleteProjectExpense(
+	ctx context.Context,
+	projectID string,
+	expenseID string,
+) error {
+	ctx, span := startSpan(ctx, "ProjectRepository.DeleteProjectExpense")
+	defer span.End()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		`
+		DELETE FROM project_expenses
+		WHERE id = $1
+		  AND project_id = $2
+		`,
+		expenseID,
+		projectID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return usecase.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *ProjectRepository) ListUserHourlyRates(
+	ctx context.Context,
+	userID string,
+) ([]usecase.UserHourlyRate, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.ListUserHourlyRates")
+	defer span.End()
+
+	var records []userHourlyRateRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		`
+		SELECT
+		  rate.id,
+		  rate.user_id,
+		  COALESCE(user_record.name, '') AS user_name,
+		  rate.hourly_cost,
+		  rate.valid_from,
+		  rate.notes,
+		  rate.created,
+		  rate.updated
+		FROM user_hourly_rates rate
+		LEFT JOIN users user_record ON user_record.id = rate.user_id
+		WHERE ($1 = '' OR rate.user_id::text = $1)
+		ORDER BY user_name ASC, rate.valid_from DESC, rate.id ASC
+		`,
+		userID,
+	); err != nil {
+		return nil, err
+	}
+
+	rates := make([]usecase.UserHourlyRate, 0, len(records))
+	for _, record := range records {
+		rates = append(rates, mapUserHourlyRateRecord(record))
+	}
+
+	return rates, nil
+}
+
+func (r *ProjectRepository) CreateUserHourlyRate(
+	ctx context.Context,
+	input usecase.CreateUserHourlyRateInput,
+) (usecase.UserHourlyRate, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.CreateUserHourlyRate")
+	defer span.End()
+
+	var record userHourlyRateRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		`
+		WITH inserted AS (
+		  INSERT INTO user_hourly_rates (
+		    user_id,
+		    hourly_cost,
+		    valid_from,
+		    notes,
+		    created,
+		    updated
+		  )
+		  VALUES ($1, $2, $3, $4, NOW(), NOW())
+		  RETURNING id, user_id, hourly_cost, valid_from, notes, created, updated
+		)
+		SELECT
+		  inserted.id,
+		  inserted.user_id,
+		  COALESCE(user_record.name, '') AS user_name,
+		  inserted.hourly_cost,
+		  inserted.valid_from,
+		  inserted.notes,
+		  inserted.created,
+		  inserted.updated
+		FROM inserted
+		LEFT JOIN users user_record ON user_record.id = inserted.user_id
+		`,
+		input.UserID,
+		input.HourlyCost,
+		input.ValidFrom,
+		input.Notes,
+	); err != nil {
+		return usecase.UserHourlyRate{}, mapProjectPersistenceError(err)
+	}
+
+	return mapUserHourlyRateRecord(record), nil
+}
+
+func (r *ProjectRepository) DeleteUserHourlyRate(
+	ctx context.Context,
+	rateID string,
+) error {
+	ctx, span := startSpan(ctx, "ProjectRepository.DeleteUserHourlyRate")
+	defer span.End()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		"DELETE FROM user_hourly_rates WHERE id = $1",
+		rateID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return usecase.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *ProjectRepository) ListProjectTimeEntries(
+	ctx context.Context,
+	projectID string,
+) ([]usecase.ProjectTimeEntry, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.ListProjectTimeEntries")
+	defer span.End()
+
+	exists, err := r.projectExists(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, usecase.ErrNotFound
+	}
+
+	records, err := r.listProjectTimeEntryRecords(ctx, projectID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]usecase.ProjectTimeEntry, 0, len(records))
+	for _, record := range records {
+		entries = append(entries, mapProjectTimeEntryRecord(record))
+	}
+
+	return entries, nil
+}
+
+func (r *ProjectRepository) CreateProjectTimeEntry(
+	ctx context.Context,
+	input usecase.CreateProjectTimeEntryInput,
+) (usecase.ProjectTimeEntry, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.CreateProjectTimeEntry")
+	defer span.End()
+
+	var taskID interface{}
+	if input.ProjectTaskID != "" {
+		var taskExists bool
+		if err := r.db.GetContext(
+			ctx,
+			&taskExists,
+			"SELECT EXISTS (SELECT 1 FROM project_tasks WHERE id = $1 AND project_id = $2)",
+			input.ProjectTaskID,
+			input.ProjectID,
+		); err != nil {
+			return usecase.ProjectTimeEntry{}, mapProjectPersistenceError(err)
+		}
+		if !taskExists {
+			return usecase.ProjectTimeEntry{}, usecase.ErrInvalidInput
+		}
+		taskID = input.ProjectTaskID
+	}
+
+	var entryID string
+	if err := r.db.GetContext(
+		ctx,
+		&entryID,
+		`
+		INSERT INTO project_time_entries (
+		  project_id,
+		  project_task_id,
+		  user_id,
+		  worked_on,
+		  hours,
+		  description,
+		  created,
+		  updated
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING id
+		`,
+		input.ProjectID,
+		taskID,
+		input.UserID,
+		input.WorkedOn,
+		input.Hours,
+		input.Description,
+	); err != nil {
+		return usecase.ProjectTimeEntry{}, mapProjectPersistenceError(err)
+	}
+
+	records, err := r.listProjectTimeEntryRecords(ctx, input.ProjectID, entryID)
+	if err != nil {
+		return usecase.ProjectTimeEntry{}, err
+	}
+	if len(records) == 0 {
+		return usecase.ProjectTimeEntry{}, usecase.ErrNotFound
+	}
+
+	return mapProjectTimeEntryRecord(records[0]), nil
+}
+
+func (r *ProjectRepository) DeleteProjectTimeEntry(
+	ctx context.Context,
+	projectID string,
+	entryID string,
+) error {
+	ctx, span := startSpan(ctx, "ProjectRepository.DeleteProjectTimeEntry")
+	defer span.End()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		`
+		DELETE FROM project_time_entries
+		WHERE id = $1
+		  AND project_id = $2
+		`,
+		entryID,
+		projectID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return usecase.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *ProjectRepository) ListProjectFinancialEntries(
+	ctx context.Context,
+	filter usecase.ProjectFinancialFilter,
+) ([]usecase.ProjectFinancialEntry, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.ListProjectFinancialEntries")
+	defer span.End()
+
+	periodEnd := filter.To.AddDate(0, 1, 0)
+
+	var records []projectFinancialEntryRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		`
+		SELECT
+		  revenue.project_id,
+		  project.name AS project_name,
+		  'revenue' AS kind,
+		  COALESCE(revenue.received_on, revenue.expected_on, revenue.created::date)::timestamptz AS occurred_on,
+		  revenue.amount,
+		  0::numeric AS hours
+		FROM project_revenues revenue
+		INNER JOIN projects project ON project.id = revenue.project_id
+		WHERE revenue.status <> 'cancelado'
+		  AND ($1 = '' OR revenue.project_id::text = $1)
+		  AND COALESCE(revenue.received_on, revenue.expected_on, revenue.created::date) >= $2::date
+		  AND COALESCE(revenue.received_on, revenue.expected_on, revenue.created::date) < $3::date
+
+		UNION ALL
+
+		SELECT
+		  expense.project_id,
+		  project.name AS project_name,
+		  'expense' AS kind,
+		  expense.incurred_on::timestamptz AS occurred_on,
+		  expense.amount,
+		  0::numeric AS hours
+		FROM project_expenses expense
+		INNER JOIN projects project ON project.id = expense.project_id
+		WHERE expense.active = TRUE
+		  AND ($1 = '' OR expense.project_id::text = $1)
+		  AND expense.incurred_on >= $2::date
+		  AND expense.incurred_on < $3::date
+
+		UNION ALL
+
+		SELECT
+		  entry.project_id,
+		  project.name AS project_name,
+		  'labor' AS kind,
+		  entry.worked_on::timestamptz AS occurred_on,
+		  entry.hours * `+userHourlyCostOnWorkedDateSQL+` AS amount,
+		  entry.hours
+		FROM project_time_entries entry
+		INNER JOIN projects project ON project.id = entry.project_id
+		WHERE ($1 = '' OR entry.project_id::text = $1)
+		  AND entry.worked_on >= $2::date
+		  AND entry.worked_on < $3::date
+		`,
+		filter.ProjectID,
+		filter.From,
+		periodEnd,
+	); err != nil {
+		return nil, err
+	}
+
+	entries := make([]usecase.ProjectFinancialEntry, 0, len(records))
+	for _, record := range records {
+		entries = append(entries, usecase.ProjectFinancialEntry{
+			ProjectID:   record.ProjectID,
+			ProjectName: record.ProjectName,
+			Kind:        record.Kind,
+			OccurredOn:  record.OccurredOn.UTC(),
+			Amount:      record.Amount,
+			Hours:       record.Hours,
+		})
+	}
+
+	chargeEntries, err := r.listProjectFinancialChargeEntries(ctx, filter.ProjectID, filter.From, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(entries, chargeEntries...), nil
+}
+
+// listProjectFinancialChargeEntries resolves charge due dates in Go so the
+// report agrees with usecase.ProjectMonthlyChargeDueDate.
+func (r *ProjectRepository) listProjectFinancialChargeEntries(
+	ctx context.Context,
+	projectID string,
+	periodStart time.Time,
+	periodEnd time.Time,
+) ([]usecase.ProjectFinancialEntry, error) {
+	var records []projectFinancialChargeRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		`
+		SELECT
+		  charge.id,
+		  charge.project_id,
+		  project.name AS project_name,
+		  charge.title,
+		  charge.description,
+		  charge.installment,
+		  charge.status,
+		  charge.amount,
+		  charge.due_day,
+		  charge.starts_on,
+		  charge.ends_on,
+		  charge.active,
+		  charge.created,
+		  charge.updated
+		FROM project_monthly_charges charge
+		INNER JOIN projects project ON project.id = charge.project_id
+		WHERE charge.status <> 'cancelada'
+		  AND ($1 = '' OR charge.project_id::text = $1)
+		`,
+		projectID,
+	); err != nil {
+		return nil, err
+	}
+
+	entries := make([]usecase.ProjectFinancialEntry, 0, len(records))
+	for _, record := range records {
+		dueDate := usecase.ProjectMonthlyChargeDueDate(usecase.ProjectMonthlyCharge{
+			DueDay:   record.DueDay,
+			StartsOn: record.StartsOn,
+			Created:  record.Created,
+		})
+		if dueDate.Before(periodStart) || !dueDate.Before(periodEnd) {
+			continue
+		}
+
+		entries = append(entries, usecase.ProjectFinancialEntry{
+			ProjectID:   record.ProjectID,
+			ProjectName: record.ProjectName,
+			Kind:        "monthly_charge",
+			OccurredOn:  dueDate,
+			Amount:      record.Amount,
+		})
+	}
+
+	return entries, nil
+}
+
+func (r *ProjectRepository) findProjectExpense(
+	ctx context.Context,
+	projectID string,
+	expenseID string,
+) (usecase.ProjectExpense, error) {
+	expenses, err := r.ListProjectExpenses(ctx, projectID)
+	if err != nil {
+		return usecase.ProjectExpense{}, err
+	}
+	for _, expense := range expenses {
+		if expense.ID == expenseID {
+			return expense, nil
+		}
+	}
+
+	return usecase.ProjectExpense{}, usecase.ErrNotFound
+}
+
+func (r *ProjectRepository) withExpenseReceipts(
+	ctx context.Context,
+	expenseRecords []projectExpenseRecord,
+) ([]usecase.ProjectExpense, error) {
+	expenses := make([]usecase.ProjectExpense, 0, len(expenseRecords))
+	expenseIDs := make([]string, 0, len(expenseRecords))
+	expenseIndexByID := make(map[string]int, len(expenseRecords))
+
+	for _, record := range expenseRecords {
+		expenseIndexByID[record.ID] = len(expenses)
+		expenseIDs = append(expenseIDs, record.ID)
+		expenses = append(expenses, usecase.ProjectExpense{
+			ID:           record.ID,
+			ProjectID:    record.ProjectID,
+			CategoryID:   record.CategoryID,
+			CategoryCode: record.CategoryCode,
+			CategoryName: record.CategoryName,
+			Title:        record.Title,
+			Description:  record.Description,
+			Supplier:     record.Supplier,
+			Amount:       record.Amount,
+			IncurredOn:   record.IncurredOn,
+			Active:       record.Active,
+			Receipts:     []usecase.ProjectExpenseReceipt{},
+			Created:      record.Created,
+			Updated:      record.Updated,
+		})
+	}
+
+	if len(expenseIDs) == 0 {
+		return expenses, nil
+	}
+
+	var receiptRecords []projectExpenseReceiptRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&receiptRecords,
+		`
+		SELECT
+		  id,
+		  project_expense_id,
+		  file_name,
+		  file_key,
+		  content_type,
+		  issued_on,
+		  notes,
+		  created,
+		  updated
+		FROM project_expense_receipts
+		WHERE project_expense_id = ANY($1::uuid[])
+		ORDER BY created ASC, id ASC
+		`,
+		pq.Array(expenseIDs),
+	); err != nil {
+		return nil, err
+	}
+
+	for _, receiptRecord := range receiptRecords {
+		index, exists := expenseIndexByID[receiptRecord.ProjectExpenseID]
+		if !exists {
+			continue
+		}
+		expenses[index].Receipts = append(expenses[index].Receipts, usecase.ProjectExpenseReceipt{
+			ID:               receiptRecord.ID,
+			ProjectExpenseID: receiptRecord.ProjectExpenseID,
+			FileName:         receiptRecord.FileName,
+			FileKey:          receiptRecord.FileKey,
+			ContentType:      receiptRecord.ContentType,
+			IssuedOn:         receiptRecord.IssuedOn,
+			Notes:            receiptRecord.Notes,
+			Created:          receiptRecord.Created,
+			Updated:          receiptRecord.Updated,
+		})
+	}
+
+	return expenses, nil
+}
+
+func (r *ProjectRepository) listProjectTimeEntryRecords(
+	ctx context.Context,
+	projectID string,
+	entryID string,
+) ([]projectTimeEntryRecord, error) {
+	var records []projectTimeEntryRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		`
+		SELECT
+		  entry.id,
+		  entry.project_id,
+		  COALESCE(entry.project_task_id::text, '') AS project_task_id,
+		  COALESCE(task.name, '') AS project_task_name,
+		  entry.user_id,
+		  COALESCE(user_record.name, '') AS user_name,
+		  entry.worked_on,
+		  entry.hours,
+		  `+userHourlyCostOnWorkedDateSQL+` AS hourly_cost,
+		  entry.description,
+		  entry.created,
+		  entry.updated
+		FROM project_time_entries entry
+		LEFT JOIN project_tasks task ON task.id = entry.project_task_id
+		LEFT JOIN users user_record ON user_record.id = entry.user_id
+		WHERE entry.project_id = $1
+		  AND ($2 = '' OR entry.id::text = $2)
+		ORDER BY entry.worked_on DESC, entry.created DESC, entry.id ASC
+		`,
+		projectID,
+		entryID,
+	); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func insertProjectExpenseReceipts(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	expenseID string,
+	receipts []usecase.CreateProjectExpenseReceiptInput,
+) error {
+	for _, receipt := range receipts {
+		if _, err := tx.ExecContext(
+			ctx,
+			`
+			INSERT INTO project_expense_receipts (
+			  project_expense_id,
+			  file_name,
+			  file_key,
+			  content_type,
+			  issued_on,
+			  notes,
+			  created,
+			  updated
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+			`,
+			expenseID,
+			receipt.FileName,
+			receipt.FileKey,
+			receipt.ContentType,
+			receipt.IssuedOn,
+			receipt.Notes,
+		); err != nil {
+			return mapProjectPersistenceError(err)
+		}
+	}
+
+	return nil
+}
+
+func mapProjectExpenseCategoryRecord(record projectExpenseCategoryRecord) usecase.ProjectExpenseCategory {
+	return usecase.ProjectExpenseCategory{
+		ID:          record.ID,
+		Code:        record.Code,
+		Name:        record.Name,
+		Description: record.Description,
+		Active:      record.Active,
+		Created:     record.Created,
+		Updated:     record.Updated,
+	}
+}
+
+func mapUserHourlyRateRecord(record userHourlyRateRecord) usecase.UserHourlyRate {
+	return usecase.UserHourlyRate{
+		ID:         record.ID,
+		UserID:     record.UserID,
+		UserName:   record.UserName,
+		HourlyCost: record.HourlyCost,
+		ValidFrom:  record.ValidFrom,
+		Notes:      record.Notes,
+		Created:    record.Created,
+		Updated:    record.Updated,
+	}
+}
+
+func mapProjectTimeEntryRecord(record projectTimeEntryRecord) usecase.ProjectTimeEntry {
+	return usecase.ProjectTimeEntry{
+		ID:              record.ID,
+		ProjectID:       record.ProjectID,
+		ProjectTaskID:   record.ProjectTaskID,
+		ProjectTaskName: record.ProjectTaskName,
+		UserID:          record.UserID,
+		UserName:        record.UserName,
+		WorkedOn:        record.WorkedOn,
+		Hours:           record.Hours,
+		HourlyCost:      record.HourlyCost,
+		Cost:            math.Round(record.Hours*record.HourlyCost*100) / 100,
+		Description:     record.Description,
+		Created:         record.Created,
+		Updated:         record.Updated,
+	}
+}
@@ -78,6 +78,7 @@ type projectTypeRecord struct {
 	Code         string    `db:"code"`
 	Name         string    `db:"name"`
 	Description  string    `db:"description"`
+	IssRate      float64   `db:"iss_rate"`
 	Active       bool      `db:"active"`
 	Created      time.Time `db:"created"`
 	Updated      time.Time `db:"updated"`
@@ -136,6 +137,7 @@ type projectMonthlyChargeRecord struct {
 	DueDay      int        `db:"due_day"`
 	StartsOn    *time.Time `db:"starts_on"`
 	EndsOn      *time.Time `db:"ends_on"`
+	ReceivedOn  *time.Time `db:"received_on"`
 	Active      bool       `db:"active"`
 	Created     time.Time  `db:"created"`
 	Updated     time.Time  `db:"updated"`
@@ -169,6 +171,7 @@ type projectTaskRecord struct {
 	Position            int        `db:"position"`
 	Status              string     `db:"status"`
 	Active              bool       `db:"active"`
+	ServiceRequestID    string     `db:"service_request_id"`
 	Created             time.Time  `db:"created"`
 	Updated             time.Time  `db:"updated"`
 }
@@ -226,6 +229,9 @@ func (r *ProjectRepository) ListProjects(
 	ctx context.Context,
 	filter usecase.ProjectListFilter,
 ) ([]usecase.ProjectListItem, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.ListProjects")
+	defer span.End()
+
 	search := strings.TrimSpace(filter.Search)
 
 	query := `
@@ -304,6 +310,9 @@ func (r *ProjectRepository) GetProjectDetail(
 	ctx context.Context,
 	projectID string,
 ) (usecase.ProjectDetail, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.GetProjectDetail")
+	defer span.End()
+
 	project, err := r.getProjectRecordByID(ctx, projectID)
 	if err != nil {
 		return usecase.ProjectDetail{}, err
@@ -367,6 +376,9 @@ func (r *ProjectRepository) CreateProject(
 	ctx context.Context,
 	input usecase.CreateProjectInput,
 ) (usecase.ProjectDetail, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.CreateProject")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return usecase.ProjectDetail{}, err
@@ -434,6 +446,9 @@ func (r *ProjectRepository) UpdateProject(
 	ctx context.Context,
 	input usecase.UpdateProjectInput,
 ) (usecase.ProjectDetail, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.UpdateProject")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return usecase.ProjectDetail{}, err
@@ -503,6 +518,9 @@ func (r *ProjectRepository) UpdateProjectStatus(
 	ctx context.Context,
 	input usecase.UpdateProjectStatusInput,
 ) (usecase.ProjectDetail, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.UpdateProjectStatus")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return usecase.ProjectDetail{}, err
@@ -601,6 +619,9 @@ func (r *ProjectRepository) DeleteProject(
 	ctx context.Context,
 	projectID string,
 ) error {
+	ctx, span := startSpan(ctx, "ProjectRepository.DeleteProject")
+	defer span.End()
+
 	result, err := r.db.ExecContext(
 		ctx,
 		"DELETE FROM projects WHERE id = $1",
@@ -621,6 +642,9 @@ func (r *ProjectRepository) DeleteProject(
 func (r *ProjectRepository) ListProjectCategories(
 	ctx context.Context,
 ) ([]usecase.ProjectCategory, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.ListProjectCategories")
+	defer span.End()
+
 	var records []projectCategoryRecord
 	if err := r.db.SelectContext(
 		ctx,
@@ -654,6 +678,9 @@ func (r *ProjectRepository) ListProjectTypes(
 	ctx context.Context,
 	filter usecase.ProjectTypeListFilter,
 ) ([]usecase.ProjectType, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.ListProjectTypes")
+	defer span.End()
+
 	query := `
 		SELECT
 		  project_type.id,
@@ -663,6 +690,7 @@ func (r *ProjectRepository) ListProjectTypes(
 		  project_type.code,
 		  project_type.name,
 		  project_type.description,
+		  project_type.iss_rate,
 		  project_type.active,
 		  project_type.created,
 		  project_type.updated
@@ -691,6 +719,7 @@ func (r *ProjectRepository) ListProjectTypes(
 			Code:         record.Code,
 			Name:         record.Name,
 			Description:  record.Description,
+			IssRate:      record.IssRate,
 			Active:       record.Active,
 			Created:      record.Created,
 			Updated:      record.Updated,
@@ -704,6 +733,9 @@ func (r *ProjectRepository) CreateProjectType(
 	ctx context.Context,
 	input usecase.CreateProjectTypeInput,
 ) (usecase.ProjectType, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.CreateProjectType")
+	defer span.End()
+
 	var projectType usecase.ProjectType
 	if err := r.db.GetContext(
 		ctx,
@@ -714,11 +746,12 @@ func (r *ProjectRepository) CreateProjectType(
 		  code,
 		  name,
 		  description,
+		  iss_rate,
 		  active,
 		  created,
 		  updated
 		)
-		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
 		RETURNING
 		  id,
 		  category_id,
@@ -735,6 +768,7 @@ func (r *ProjectRepository) CreateProjectType(
 		  code,
 		  name,
 		  description,
+		  iss_rate AS issrate,
 		  active,
 		  created,
 		  updated
@@ -743,6 +777,7 @@ func (r *ProjectRepository) CreateProjectType(
 		input.Code,
 		input.Name,
 		input.Description,
+		input.IssRate,
 		input.Active,
 	); err != nil {
 		return usecase.ProjectType{}, mapProjectPersistenceError(err)
@@ -755,6 +790,9 @@ func (r *ProjectRepository) UpdateProjectType(
 	ctx context.Context,
 	input usecase.UpdateProjectTypeInput,
 ) (usecase.ProjectType, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.UpdateProjectType")
+	defer span.End()
+
 	var projectType usecase.ProjectType
 	if err := r.db.GetContext(
 		ctx,
@@ -765,9 +803,10 @@ func (r *ProjectRepository) UpdateProjectType(
 		    code = $2,
 		    name = $3,
 		    description = $4,
-		    active = COALESCE($5::boolean, active),
+		    iss_rate = COALESCE($5::numeric, iss_rate),
+		    active = COALESCE($6::boolean, active),
 		    updated = NOW()
-		WHERE id = $6
+		WHERE id = $7
 		RETURNING
 		  id,
 		  category_id,
@@ -784,6 +823,7 @@ func (r *ProjectRepository) UpdateProjectType(
 		  code,
 		  name,
 		  description,
+		  iss_rate AS issrate,
 		  active,
 		  created,
 		  updated
@@ -792,6 +832,7 @@ func (r *ProjectRepository) UpdateProjectType(
 		input.Code,
 		input.Name,
 		input.Description,
+		input.IssRate,
 		input.Active,
 		input.ID,
 	); err != nil {
@@ -808,6 +849,9 @@ func (r *ProjectRepository) ListProjectRevenues(
 	ctx context.Context,
 	projectID string,
 ) ([]usecase.ProjectRevenue, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.ListProjectRevenues")
+	defer span.End()
+
 	exists, err := r.projectExists(ctx, projectID)
 	if err != nil {
 		return nil, err
@@ -828,6 +872,9 @@ func (r *ProjectRepository) CreateProjectRevenue(
 	ctx context.Context,
 	input usecase.CreateProjectRevenueInput,
 ) (usecase.ProjectRevenue, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.CreateProjectRevenue")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return usecase.ProjectRevenue{}, err
@@ -916,6 +963,9 @@ func (r *ProjectRepository) UpdateProjectRevenueStatus(
 	ctx context.Context,
 	input usecase.UpdateProjectRevenueStatusInput,
 ) error {
+	ctx, span := startSpan(ctx, "ProjectRepository.UpdateProjectRevenueStatus")
+	defer span.End()
+
 	result, err := r.db.ExecContext(
 		ctx,
 		`
@@ -948,6 +998,9 @@ func (r *ProjectRepository) ListProjectMonthlyCharges(
 	ctx context.Context,
 	projectID string,
 ) ([]usecase.ProjectMonthlyCharge, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.ListProjectMonthlyCharges")
+	defer span.End()
+
 	exists, err := r.projectExists(ctx, projectID)
 	if err != nil {
 		return nil, err
@@ -972,6 +1025,7 @@ func (r *ProjectRepository) ListProjectMonthlyCharges(
 			  due_day,
 			  starts_on,
 		  ends_on,
+		  received_on,
 		  active,
 		  created,
 		  updated
@@ -1001,6 +1055,7 @@ func (r *ProjectRepository) ListProjectMonthlyCharges(
 			  due_day,
 			  starts_on,
 			  ends_on,
+			  NULL::date AS received_on,
 			  active,
 			  created,
 			  updated
@@ -1027,6 +1082,7 @@ func (r *ProjectRepository) ListProjectMonthlyCharges(
 			DueDay:      record.DueDay,
 			StartsOn:    record.StartsOn,
 			EndsOn:      record.EndsOn,
+			ReceivedOn:  record.ReceivedOn,
 			Active:      record.Active,
 			Created:     record.Created,
 			Updated:     record.Updated,
@@ -1040,6 +1096,9 @@ func (r *ProjectRepository) CreateProjectMonthlyCharge(
 	ctx context.Context,
 	input usecase.CreateProjectMonthlyChargeInput,
 ) (usecase.ProjectMonthlyCharge, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.CreateProjectMonthlyCharge")
+	defer span.End()
+
 	var chargeRecord projectMonthlyChargeRecord
 	if err := r.db.GetContext(
 		ctx,
@@ -1071,6 +1130,7 @@ func (r *ProjectRepository) CreateProjectMonthlyCharge(
 			  due_day,
 		  starts_on,
 		  ends_on,
+		  received_on,
 		  active,
 		  created,
 		  updated
@@ -1100,6 +1160,7 @@ func (r *ProjectRepository) CreateProjectMonthlyCharge(
 		DueDay:      chargeRecord.DueDay,
 		StartsOn:    chargeRecord.StartsOn,
 		EndsOn:      chargeRecord.EndsOn,
+		ReceivedOn:  chargeRecord.ReceivedOn,
 		Active:      chargeRecord.Active,
 		Created:     chargeRecord.Created,
 		Updated:     chargeRecord.Updated,
@@ -1110,6 +1171,9 @@ func (r *ProjectRepository) UpdateProjectMonthlyCharge(
 	ctx context.Context,
 	input usecase.UpdateProjectMonthlyChargeInput,
 ) (usecase.ProjectMonthlyCharge, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.UpdateProjectMonthlyCharge")
+	defer span.End()
+
 	var chargeRecord projectMonthlyChargeRecord
 	if err := r.db.GetContext(
 		ctx,
@@ -1140,6 +1204,7 @@ func (r *ProjectRepository) UpdateProjectMonthlyCharge(
 		  due_day,
 		  starts_on,
 		  ends_on,
+		  received_on,
 		  active,
 		  created,
 		  updated
@@ -1180,6 +1245,7 @@ func (r *ProjectRepository) UpdateProjectMonthlyCharge(
 		DueDay:      chargeRecord.DueDay,
 		StartsOn:    chargeRecord.StartsOn,
 		EndsOn:      chargeRecord.EndsOn,
+		ReceivedOn:  chargeRecord.ReceivedOn,
 		Active:      chargeRecord.Active,
 		Created:     chargeRecord.Created,
 		Updated:     chargeRecord.Updated,
@@ -1190,6 +1256,9 @@ func (r *ProjectRepository) UpdateProjectMonthlyChargeStatus(
 	ctx context.Context,
 	input usecase.UpdateProjectMonthlyChargeStatusInput,
 ) (usecase.ProjectMonthlyCharge, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.UpdateProjectMonthlyChargeStatus")
+	defer span.End()
+
 	var chargeRecord projectMonthlyChargeRecord
 	if err := r.db.GetContext(
 		ctx,
@@ -1197,6 +1266,7 @@ func (r *ProjectRepository) UpdateProjectMonthlyChargeStatus(
 		`
 		UPDATE project_monthly_charges
 		SET status = $1,
+		    received_on = CASE WHEN $1 = 'pago' THEN CURRENT_DATE ELSE received_on END,
 		    updated = NOW()
 		WHERE id = $2
 		  AND project_id = $3
@@ -1212,6 +1282,7 @@ func (r *ProjectRepository) UpdateProjectMonthlyChargeStatus(
 		  due_day,
 		  starts_on,
 		  ends_on,
+		  received_on,
 		  active,
 		  created,
 		  updated
@@ -1244,6 +1315,7 @@ func (r *ProjectRepository) UpdateProjectMonthlyChargeStatus(
 		DueDay:      chargeRecord.DueDay,
 		StartsOn:    chargeRecord.StartsOn,
 		EndsOn:      chargeRecord.EndsOn,
+		ReceivedOn:  chargeRecord.ReceivedOn,
 		Active:      chargeRecord.Active,
 		Created:     chargeRecord.Created,
 		Updated:     chargeRecord.Updated,
@@ -1254,6 +1326,9 @@ func (r *ProjectRepository) UpdateProjectMonthlyChargeAmount(
 	ctx context.Context,
 	input usecase.UpdateProjectMonthlyChargeAmountInput,
 ) (usecase.ProjectMonthlyCharge, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.UpdateProjectMonthlyChargeAmount")
+	defer span.End()
+
 	var chargeRecord projectMonthlyChargeRecord
 	if err := r.db.GetContext(
 		ctx,
@@ -1276,6 +1351,7 @@ func (r *ProjectRepository) UpdateProjectMonthlyChargeAmount(
 		  due_day,
 		  starts_on,
 		  ends_on,
+		  received_on,
 		  active,
 		  created,
 		  updated
@@ -1308,6 +1384,7 @@ func (r *ProjectRepository) UpdateProjectMonthlyChargeAmount(
 		DueDay:      chargeRecord.DueDay,
 		StartsOn:    chargeRecord.StartsOn,
 		EndsOn:      chargeRecord.EndsOn,
+		ReceivedOn:  chargeRecord.ReceivedOn,
 		Active:      chargeRecord.Active,
 		Created:     chargeRecord.Created,
 		Updated:     chargeRecord.Updated,
@@ -1319,6 +1396,9 @@ func (r *ProjectRepository) DeleteProjectMonthlyCharge(
 	projectID string,
 	monthlyChargeID string,
 ) error {
+	ctx, span := startSpan(ctx, "ProjectRepository.DeleteProjectMonthlyCharge")
+	defer span.End()
+
 	result, err := r.db.ExecContext(
 		ctx,
 		`
@@ -1330,7 +1410,7 @@ func (r *ProjectRepository) DeleteProjectMonthlyCharge(
 		projectID,
 	)
 	if err != nil {
-		return err
+		return mapProjectPersistenceError(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -1374,6 +1454,9 @@ func (r *ProjectRepository) ListProjectPhases(
 	ctx context.Context,
 	projectID string,
 ) ([]usecase.ProjectPhase, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.ListProjectPhases")
+	defer span.End()
+
 	exists, err := r.projectExists(ctx, projectID)
 	if err != nil {
 		return nil, err
@@ -1394,6 +1477,9 @@ func (r *ProjectRepository) CreateProjectPhase(
 	ctx context.Context,
 	input usecase.CreateProjectPhaseInput,
 ) (usecase.ProjectPhase, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.CreateProjectPhase")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return usecase.ProjectPhase{}, err
@@ -1482,6 +1568,9 @@ func (r *ProjectRepository) UpdateProjectPhase(
 	ctx context.Context,
 	input usecase.UpdateProjectPhaseInput,
 ) (usecase.ProjectPhase, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.UpdateProjectPhase")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return usecase.ProjectPhase{}, err
@@ -1576,6 +1665,9 @@ func (r *ProjectRepository) ListProjectTasks(
 	ctx context.Context,
 	projectID string,
 ) ([]usecase.ProjectTask, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.ListProjectTasks")
+	defer span.End()
+
 	exists, err := r.projectExists(ctx, projectID)
 	if err != nil {
 		return nil, err
@@ -1596,6 +1688,9 @@ func (r *ProjectRepository) CreateProjectTask(
 	ctx context.Context,
 	input usecase.CreateProjectTaskInput,
 ) (usecase.ProjectTask, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.CreateProjectTask")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return usecase.ProjectTask{}, err
@@ -1704,6 +1799,9 @@ func (r *ProjectRepository) UpdateProjectTask(
 	ctx context.Context,
 	input usecase.UpdateProjectTaskInput,
 ) (usecase.ProjectTask, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.UpdateProjectTask")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return usecase.ProjectTask{}, err
@@ -1805,6 +1903,9 @@ func (r *ProjectRepository) ListProjectTaskComments(
 	projectID string,
 	taskID string,
 ) ([]usecase.ProjectTaskComment, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.ListProjectTaskComments")
+	defer span.End()
+
 	var taskExists bool
 	if err := r.db.GetContext(
 		ctx,
@@ -1838,6 +1939,9 @@ func (r *ProjectRepository) CreateProjectTaskComment(
 	ctx context.Context,
 	input usecase.CreateProjectTaskCommentInput,
 ) (usecase.ProjectTaskComment, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.CreateProjectTaskComment")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return usecase.ProjectTaskComment{}, err
@@ -2324,6 +2428,7 @@ func (r *ProjectRepository) listProjectTaskRecords(
 		  task.position,
 		  task.status,
 		  task.active,
+		  COALESCE(task.service_request_id::text, '') AS service_request_id,
 		  task.created,
 		  task.updated
 		FROM project_tasks task
@@ -2356,6 +2461,7 @@ func (r *ProjectRepository) listProjectTaskRecords(
 			  task.position,
 			  task.status,
 			  task.active,
+			  '' AS service_request_id,
 			  task.created,
 			  task.updated
 			FROM project_tasks task
@@ -2396,6 +2502,7 @@ func (r *ProjectRepository) withTaskFiles(
 			Position:            record.Position,
 			Status:              record.Status,
 			Active:              record.Active,
+			ServiceRequestID:    record.ServiceRequestID,
 			Files:               []usecase.ProjectRelatedFile{},
 			Created:             record.Created,
 			Updated:             record.Updated,
@@ -2664,6 +2771,9 @@ func (r *ProjectRepository) RecalculateProjectTimeline(
 	ctx context.Context,
 	projectID string,
 ) (usecase.ProjectDetail, error) {
+	ctx, span := startSpan(ctx, "ProjectRepository.RecalculateProjectTimeline")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return usecase.ProjectDetail{}, err
@@ -3043,6 +3153,12 @@ func mapProjectPersistenceError(err error) error {
 				return usecase.ErrProjectTypeCodeInUse
 			case "project_types_category_name_lower_key":
 				return usecase.ErrProjectTypeNameInUse
+			case "project_expense_categories_code_lower_key":
+				return usecase.ErrProjectExpenseCategoryCodeInUse
+			case "project_expense_categories_name_lower_key":
+				return usecase.ErrProjectExpenseCategoryNameInUse
+			case "user_hourly_rates_user_valid_from_key":
+				return usecase.ErrUserHourlyRateInUse
 			}
 		case "23503":
 			switch pgErr.Constraint {
@@ -3058,6 +3174,16 @@ func mapProjectPersistenceError(err error) error {
 				return usecase.ErrInvalidInput
 			case "project_task_comments_user_id_fkey", "project_task_comments_client_id_fkey":
 				return usecase.ErrInvalidInput
+			case "project_expenses_category_id_fkey":
+				return usecase.ErrProjectExpenseCategoryNotFound
+			case "user_hourly_rates_user_id_fkey", "project_time_entries_user_id_fkey":
+				return usecase.ErrUserNotFound
+			case "project_time_entries_project_task_id_fkey":
+				return usecase.ErrInvalidInput
+			case "invoices_project_id_fkey",
+				"invoices_project_revenue_id_fkey",
+				"invoices_project_monthly_charge_id_fkey":
+				return usecase.ErrProjectHasInvoices
 			default:
 				return usecase.ErrNotFound
 			}
@@ -21,6 +21,9 @@ func NewSecurityRepository(db *sqlx.DB) *SecurityRepository {
 }
 
 func (r *SecurityRepository) ListPermissions(ctx context.Context) ([]usecase.Permission, error) {
+	ctx, span := startSpan(ctx, "SecurityRepository.ListPermissions")
+	defer span.End()
+
 	var permissions []usecase.Permission
 	if err := r.db.SelectContext(
 		ctx,
@@ -41,6 +44,9 @@ func (r *SecurityRepository) CreatePermission(
 	ctx context.Context,
 	input usecase.CreatePermissionInput,
 ) (usecase.Permission, error) {
+	ctx, span := startSpan(ctx, "SecurityRepository.CreatePermission")
+	defer span.End()
+
 	var permission usecase.Permission
 	if err := r.db.GetContext(
 		ctx,
@@ -72,6 +78,9 @@ func (r *SecurityRepository) CreatePermission(
 }
 
 func (r *SecurityRepository) GetPermissionByID(ctx context.Context, id string) (usecase.Permission, error) {
+	ctx, span := startSpan(ctx, "SecurityRepository.GetPermissionByID")
+	defer span.End()
+
 	var permission usecase.Permission
 	if err := r.db.GetContext(
 		ctx,
@@ -97,6 +106,9 @@ func (r *SecurityRepository) UpdatePermission(
 	ctx context.Context,
 	input usecase.UpdatePermissionInput,
 ) (usecase.Permission, error) {
+	ctx, span := startSpan(ctx, "SecurityRepository.UpdatePermission")
+	defer span.End()
+
 	var permission usecase.Permission
 	if err := r.db.GetContext(
 		ctx,
@@ -138,6 +150,9 @@ func (r *SecurityRepository) UpdatePermission(
 }
 
 func (r *SecurityRepository) ListProfiles(ctx context.Context) ([]usecase.Profile, error) {
+	ctx, span := startSpan(ctx, "SecurityRepository.ListProfiles")
+	defer span.End()
+
 	var profiles []usecase.Profile
 	if err := r.db.SelectContext(
 		ctx,
@@ -167,6 +182,9 @@ func (r *SecurityRepository) CreateProfile(
 	ctx context.Context,
 	input usecase.CreateProfileInput,
 ) (usecase.Profile, error) {
+	ctx, span := startSpan(ctx, "SecurityRepository.CreateProfile")
+	defer span.End()
+
 	var profile usecase.Profile
 	if err := r.db.GetContext(
 		ctx,
@@ -192,6 +210,9 @@ func (r *SecurityRepository) CreateProfile(
 }
 
 func (r *SecurityRepository) GetProfileByID(ctx context.Context, id string) (usecase.Profile, error) {
+	ctx, span := startSpan(ctx, "SecurityRepository.GetProfileByID")
+	defer span.End()
+
 	var profile usecase.Profile
 	if err := r.db.GetContext(
 		ctx,
@@ -226,6 +247,9 @@ func (r *SecurityRepository) UpdateProfile(
 	ctx context.Context,
 	input usecase.UpdateProfileInput,
 ) (usecase.Profile, error) {
+	ctx, span := startSpan(ctx, "SecurityRepository.UpdateProfile")
+	defer span.End()
+
 	var profile usecase.Profile
 	if err := r.db.GetContext(
 		ctx,
@@ -270,6 +294,9 @@ func (r *SecurityRepository) ListProfilePermissionIDs(
 	ctx context.Context,
 	profileID string,
 ) ([]string, error) {
+	ctx, span := startSpan(ctx, "SecurityRepository.ListProfilePermissionIDs")
+	defer span.End()
+
 	var permissionIDs []string
 	if err := r.db.SelectContext(
 		ctx,
@@ -293,6 +320,9 @@ func (r *SecurityRepository) ReplaceProfilePermissionIDs(
 	profileID string,
 	permissionIDs []string,
 ) error {
+	ctx, span := startSpan(ctx, "SecurityRepository.ReplaceProfilePermissionIDs")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
@@ -0,0 +1,455 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ServiceRequestSettingsRepository struct {
+	db *sqlx.DB
+}
+
+func NewServiceRequestSettingsRepository(db *sqlx.DB) *ServiceRequestSettingsRepository {
+	return &ServiceRequestSettingsRepository{db: db}
+}
+
+type serviceRequestStatusRecord struct {
+	Code             string         `db:"code"`
+	Name             string         `db:"name"`
+	Description      string         `db:"description"`
+	Closed           bool           `db:"closed"`
+	ReopenWindowDays sql.NullInt64  `db:"reopen_window_days"`
+	Position         int            `db:"position"`
+	System           bool           `db:"system"`
+	Active           bool           `db:"active"`
+	Transitions      pq.StringArray `db:"transitions"`
+	Created          time.Time      `db:"created"`
+	Updated          time.Time      `db:"updated"`
+}
+
+type serviceRequestCategoryRecord struct {
+	ID          string    `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Active      bool      `db:"active"`
+	Created     time.Time `db:"created"`
+	Updated     time.Time `db:"updated"`
+}
+
+const serviceRequestStatusSelectSQL = `
+SELECT
+  status_record.code,
+  status_record.name,
+  status_record.description,
+  status_record.closed,
+  status_record.reopen_window_days,
+  status_record.position,
+  status_record.system,
+  status_record.active,
+  ARRAY(
+    SELECT transition.to_status
+    FROM service_request_status_transitions transition
+    WHERE transition.from_status = status_record.code
+    ORDER BY transition.to_status
+  ) AS transitions,
+  status_record.created,
+  status_record.updated
+FROM service_request_statuses status_record
+`
+
+func (r *ServiceRequestSettingsRepository) ListServiceRequestStatuses(
+	ctx context.Context,
+) ([]usecase.ServiceRequestStatus, error) {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsRepository.ListServiceRequestStatuses")
+	defer span.End()
+
+	var records []serviceRequestStatusRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		serviceRequestStatusSelectSQL+"ORDER BY status_record.position, status_record.code",
+	); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]usecase.ServiceRequestStatus, 0, len(records))
+	for _, record := range records {
+		statuses = append(statuses, mapServiceRequestStatusRecord(record))
+	}
+
+	return statuses, nil
+}
+
+func (r *ServiceRequestSettingsRepository) GetServiceRequestStatus(
+	ctx context.Context,
+	code string,
+) (usecase.ServiceRequestStatus, error) {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsRepository.GetServiceRequestStatus")
+	defer span.End()
+
+	var record serviceRequestStatusRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		serviceRequestStatusSelectSQL+"WHERE status_record.code = $1",
+		code,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.ServiceRequestStatus{}, usecase.ErrServiceRequestStatusNotFound
+		}
+		return usecase.ServiceRequestStatus{}, err
+	}
+
+	return mapServiceRequestStatusRecord(record), nil
+}
+
+func (r *ServiceRequestSettingsRepository) CreateServiceRequestStatus(
+	ctx context.Context,
+	input usecase.CreateServiceRequestStatusInput,
+) (usecase.ServiceRequestStatus, error) {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsRepository.CreateServiceRequestStatus")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.ServiceRequestStatus{}, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`
+		INSERT INTO service_request_statuses (
+		  code,
+		  name,
+		  description,
+		  closed,
+		  reopen_window_days,
+		  position,
+		  system,
+		  active
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, FALSE, $7)
+		`,
+		input.Code,
+		input.Name,
+		input.Description,
+		input.Closed,
+		input.ReopenWindowDays,
+		input.Position,
+		input.Active,
+	); err != nil {
+		return usecase.ServiceRequestStatus{}, mapServiceRequestSettingsPersistenceError(err)
+	}
+
+	if err := replaceServiceRequestStatusTransitions(ctx, tx, input.Code, input.Transitions); err != nil {
+		return usecase.ServiceRequestStatus{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.ServiceRequestStatus{}, err
+	}
+
+	return r.GetServiceRequestStatus(ctx, input.Code)
+}
+
+func (r *ServiceRequestSettingsRepository) UpdateServiceRequestStatus(
+	ctx context.Context,
+	input usecase.UpdateServiceRequestStatusInput,
+) (usecase.ServiceRequestStatus, error) {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsRepository.UpdateServiceRequestStatus")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.ServiceRequestStatus{}, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(
+		ctx,
+		`
+		UPDATE service_request_statuses
+		SET
+		  name = $2,
+		  description = $3,
+		  closed = $4,
+		  reopen_window_days = $5,
+		  position = $6,
+		  active = COALESCE($7, active),
+		  updated = NOW()
+		WHERE code = $1
+		`,
+		input.Code,
+		input.Name,
+		input.Description,
+		input.Closed,
+		input.ReopenWindowDays,
+		input.Position,
+		input.Active,
+	)
+	if err != nil {
+		return usecase.ServiceRequestStatus{}, mapServiceRequestSettingsPersistenceError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return usecase.ServiceRequestStatus{}, err
+	}
+	if affected == 0 {
+		return usecase.ServiceRequestStatus{}, usecase.ErrServiceRequestStatusNotFound
+	}
+
+	if err := replaceServiceRequestStatusTransitions(ctx, tx, input.Code, input.Transitions); err != nil {
+		return usecase.ServiceRequestStatus{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.ServiceRequestStatus{}, err
+	}
+
+	return r.GetServiceRequestStatus(ctx, input.Code)
+}
+
+// DeleteServiceRequestStatus fails while requests are in the status; the
+// transitions from and to it are removed with it.
+func (r *ServiceRequestSettingsRepository) DeleteServiceRequestStatus(ctx context.Context, code string) error {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsRepository.DeleteServiceRequestStatus")
+	defer span.End()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		"DELETE FROM service_request_statuses WHERE code = $1",
+		code,
+	)
+	if err != nil {
+		return mapServiceRequestSettingsPersistenceError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return usecase.ErrServiceRequestStatusNotFound
+	}
+
+	return nil
+}
+
+func (r *ServiceRequestSettingsRepository) ListServiceRequestCategories(
+	ctx context.Context,
+) ([]usecase.ServiceRequestCategory, error) {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsRepository.ListServiceRequestCategories")
+	defer span.End()
+
+	var records []serviceRequestCategoryRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		`
+		SELECT id, name, description, active, created, updated
+		FROM service_request_categories
+		ORDER BY active DESC, LOWER(name), created
+		`,
+	); err != nil {
+		return nil, err
+	}
+
+	categories := make([]usecase.ServiceRequestCategory, 0, len(records))
+	for _, record := range records {
+		categories = append(categories, mapServiceRequestCategoryRecord(record))
+	}
+
+	return categories, nil
+}
+
+func (r *ServiceRequestSettingsRepository) CreateServiceRequestCategory(
+	ctx context.Context,
+	input usecase.CreateServiceRequestCategoryInput,
+) (usecase.ServiceRequestCategory, error) {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsRepository.CreateServiceRequestCategory")
+	defer span.End()
+
+	var record serviceRequestCategoryRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		`
+		INSERT INTO service_request_categories (name, description, active)
+		VALUES ($1, $2, $3)
+		RETURNING id, name, description, active, created, updated
+		`,
+		input.Name,
+		input.Description,
+		input.Active,
+	); err != nil {
+		return usecase.ServiceRequestCategory{}, mapServiceRequestSettingsPersistenceError(err)
+	}
+
+	return mapServiceRequestCategoryRecord(record), nil
+}
+
+func (r *ServiceRequestSettingsRepository) UpdateServiceRequestCategory(
+	ctx context.Context,
+	input usecase.UpdateServiceRequestCategoryInput,
+) (usecase.ServiceRequestCategory, error) {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsRepository.UpdateServiceRequestCategory")
+	defer span.End()
+
+	var record serviceRequestCategoryRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		`
+		UPDATE service_request_categories
+		SET
+		  name = $2,
+		  description = $3,
+		  active = COALESCE($4, active),
+		  updated = NOW()
+		WHERE id::text = $1
+		RETURNING id, name, description, active, created, updated
+		`,
+		input.ID,
+		input.Name,
+		input.Description,
+		input.Active,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.ServiceRequestCategory{}, usecase.ErrServiceRequestCategoryNotFound
+		}
+		return usecase.ServiceRequestCategory{}, mapServiceRequestSettingsPersistenceError(err)
+	}
+
+	return mapServiceRequestCategoryRecord(record), nil
+}
+
+func (r *ServiceRequestSettingsRepository) DeleteServiceRequestCategory(ctx context.Context, categoryID string) error {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsRepository.DeleteServiceRequestCategory")
+	defer span.End()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		"DELETE FROM service_request_categories WHERE id::text = $1",
+		categoryID,
+	)
+	if err != nil {
+		return mapServiceRequestSettingsPersistenceError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return usecase.ErrServiceRequestCategoryNotFound
+	}
+
+	return nil
+}
+
+func replaceServiceRequestStatusTransitions(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	code string,
+	transitions []string,
+) error {
+	if _, err := tx.ExecContext(
+		ctx,
+		"DELETE FROM service_request_status_transitions WHERE from_status = $1",
+		code,
+	); err != nil {
+		return err
+	}
+
+	if len(transitions) == 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`
+		INSERT INTO service_request_status_transitions (from_status, to_status)
+		SELECT $1, target.code
+		FROM UNNEST($2::text[]) AS target(code)
+		`,
+		code,
+		pq.Array(transitions),
+	); err != nil {
+		return mapServiceRequestSettingsPersistenceError(err)
+	}
+
+	return nil
+}
+
+func mapServiceRequestStatusRecord(record serviceRequestStatusRecord) usecase.ServiceRequestStatus {
+	var reopenWindowDays *int
+	if record.ReopenWindowDays.Valid {
+		days := int(record.ReopenWindowDays.Int64)
+		reopenWindowDays = &days
+	}
+
+	transitions := []string(record.Transitions)
+	if transitions == nil {
+		transitions = []string{}
+	}
+
+	return usecase.ServiceRequestStatus{
+		Code:             record.Code,
+		Name:             record.Name,
+		Description:      record.Description,
+		Closed:           record.Closed,
+		ReopenWindowDays: reopenWindowDays,
+		Position:         record.Position,
+		System:           record.System,
+		Active:           record.Active,
+		Transitions:      transitions,
+		Created:          record.Created,
+		Updated:          record.Updated,
+	}
+}
+
+func mapServiceRequestCategoryRecord(record serviceRequestCategoryRecord) usecase.ServiceRequestCategory {
+	return usecase.ServiceRequestCategory{
+		ID:          record.ID,
+		Name:        record.Name,
+		Description: record.Description,
+		Active:      record.Active,
+		Created:     record.Created,
+		Updated:     record.Updated,
+	}
+}
+
+func mapServiceRequestSettingsPersistenceError(err error) error {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			switch pgErr.Constraint {
+			case "service_request_statuses_pkey":
+				return usecase.ErrServiceRequestStatusCodeInUse
+			case "service_request_statuses_name_lower_key":
+				return usecase.ErrServiceRequestStatusNameInUse
+			case "service_request_categories_name_lower_key":
+				return usecase.ErrServiceRequestCategoryNameInUse
+			}
+			return usecase.ErrConflict
+		case "22P02", "23514":
+			return usecase.ErrInvalidInput
+		case "23503":
+			if pgErr.Constraint == "client_service_requests_status_fkey" {
+				return usecase.ErrServiceRequestStatusInUse
+			}
+			return usecase.ErrServiceRequestStatusNotFound
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,482 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type SLARepository struct {
+	db *sqlx.DB
+}
+
+func NewSLARepository(db *sqlx.DB) *SLARepository {
+	return &SLARepository{db: db}
+}
+
+type slaPolicyRecord struct {
+	ID                   string    `db:"id"`
+	Name                 string    `db:"name"`
+	ClientID             string    `db:"client_id"`
+	ClientName           string    `db:"client_name"`
+	ProjectTypeID        string    `db:"project_type_id"`
+	ProjectTypeName      string    `db:"project_type_name"`
+	FirstResponseMinutes int       `db:"first_response_minutes"`
+	ResolutionMinutes    int       `db:"resolution_minutes"`
+	WarningMinutes       int       `db:"warning_minutes"`
+	Active               bool      `db:"active"`
+	Created              time.Time `db:"created"`
+	Updated              time.Time `db:"updated"`
+}
+
+type slaEscalationRecord struct {
+	ServiceRequestID string    `db:"service_request_id"`
+	Title            string    `db:"title"`
+	ProjectID        string    `db:"project_id"`
+	ClientName       string    `db:"client_name"`
+	AssignedUserID   string    `db:"assigned_user_id"`
+	Target           string    `db:"target"`
+	DueAt            time.Time `db:"due_at"`
+}
+
+const slaPolicySelectSQL = `
+SELECT
+  policy.id,
+  policy.name,
+  COALESCE(policy.client_id::text, '') AS client_id,
+  COALESCE(client_record.name, '') AS client_name,
+  COALESCE(policy.project_type_id::text, '') AS project_type_id,
+  COALESCE(project_type.name, '') AS project_type_name,
+  policy.first_response_minutes,
+  policy.resolution_minutes,
+  policy.warning_minutes,
+  policy.active,
+  policy.created,
+  policy.updated
+FROM sla_policies policy
+LEFT JOIN clients client_record ON client_record.id = policy.client_id
+LEFT JOIN project_types project_type ON project_type.id = policy.project_type_id
+`
+
+// slaTargetColumns names the columns of each SLA target, so the same claim
+// queries run for first response and resolution. They run as separate
+// statements because a row can only be updated once per statement.
+type slaTargetColumns struct {
+	target   string
+	dueAt    string
+	metAt    string
+	warnAt   string
+	warnedAt string
+	breached string
+}
+
+var slaTargets = []slaTargetColumns{
+	{
+		target:   usecase.SLATargetFirstResponse,
+		dueAt:    "first_response_due_at",
+		metAt:    "first_responded_at",
+		warnAt:   "first_response_warn_at",
+		warnedAt: "first_response_warned_at",
+		breached: "first_response_breached",
+	},
+	{
+		target:   usecase.SLATargetResolution,
+		dueAt:    "resolution_due_at",
+		metAt:    "resolved_at",
+		warnAt:   "resolution_warn_at",
+		warnedAt: "resolution_warned_at",
+		breached: "resolution_breached",
+	},
+}
+
+func claimSLAEscalationSQL(columns slaTargetColumns, set string, where string) string {
+	return `
+WITH claimed AS (
+  UPDATE client_service_request_slas
+  SET ` + set + `, updated = NOW()
+  WHERE service_request_id IN (
+    SELECT service_request_id
+    FROM client_service_request_slas
+    WHERE ` + columns.metAt + ` IS NULL
+      AND ` + where + `
+    ORDER BY ` + columns.dueAt + `
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+  )
+  RETURNING service_request_id, ` + columns.dueAt + ` AS due_at
+)
+SELECT
+  claimed.service_request_id,
+  request.title,
+  COALESCE(request.project_id::text, '') AS project_id,
+  COALESCE(client_record.name, '') AS client_name,
+  COALESCE(request.assigned_user_id::text, '') AS assigned_user_id,
+  $2::text AS target,
+  claimed.due_at
+FROM claimed
+JOIN client_service_requests request ON request.id = claimed.service_request_id
+LEFT JOIN clients client_record ON client_record.id = request.client_id
+ORDER BY claimed.due_at, claimed.service_request_id
+`
+}
+
+func (r *SLARepository) ListSLAPolicies(ctx context.Context) ([]usecase.SLAPolicy, error) {
+	ctx, span := startSpan(ctx, "SLARepository.ListSLAPolicies")
+	defer span.End()
+
+	var records []slaPolicyRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		slaPolicySelectSQL+`
+		ORDER BY
+		  policy.active DESC,
+		  (policy.client_id IS NULL) ASC,
+		  (policy.project_type_id IS NULL) ASC,
+		  LOWER(policy.name),
+		  policy.created
+		`,
+	); err != nil {
+		return nil, err
+	}
+
+	policies := make([]usecase.SLAPolicy, 0, len(records))
+	for _, record := range records {
+		policies = append(policies, mapSLAPolicyRecord(record))
+	}
+
+	return policies, nil
+}
+
+func (r *SLARepository) GetSLAPolicy(ctx context.Context, policyID string) (usecase.SLAPolicy, error) {
+	ctx, span := startSpan(ctx, "SLARepository.GetSLAPolicy")
+	defer span.End()
+
+	var record slaPolicyRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		slaPolicySelectSQL+"WHERE policy.id::text = $1",
+		policyID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.SLAPolicy{}, usecase.ErrNotFound
+		}
+		return usecase.SLAPolicy{}, err
+	}
+
+	return mapSLAPolicyRecord(record), nil
+}
+
+func (r *SLARepository) CreateSLAPolicy(
+	ctx context.Context,
+	input usecase.CreateSLAPolicyInput,
+) (usecase.SLAPolicy, error) {
+	ctx, span := startSpan(ctx, "SLARepository.CreateSLAPolicy")
+	defer span.End()
+
+	var policyID string
+	if err := r.db.GetContext(
+		ctx,
+		&policyID,
+		`
+		INSERT INTO sla_policies (
+		  name,
+		  client_id,
+		  project_type_id,
+		  first_response_minutes,
+		  resolution_minutes,
+		  warning_minutes,
+		  active
+		)
+		VALUES ($1, NULLIF($2, '')::uuid, NULLIF($3, '')::uuid, $4, $5, $6, $7)
+		RETURNING id
+		`,
+		input.Name,
+		input.ClientID,
+		input.ProjectTypeID,
+		input.FirstResponseMinutes,
+		input.ResolutionMinutes,
+		*input.WarningMinutes,
+		*input.Active,
+	); err != nil {
+		return usecase.SLAPolicy{}, mapSLAPersistenceError(err)
+	}
+
+	return r.GetSLAPolicy(ctx, policyID)
+}
+
+func (r *SLARepository) UpdateSLAPolicy(
+	ctx context.Context,
+	input usecase.UpdateSLAPolicyInput,
+) (usecase.SLAPolicy, error) {
+	ctx, span := startSpan(ctx, "SLARepository.UpdateSLAPolicy")
+	defer span.End()
+
+	var policyID string
+	if err := r.db.GetContext(
+		ctx,
+		&policyID,
+		`
+		UPDATE sla_policies
+		SET
+		  name = $2,
+		  client_id = NULLIF($3, '')::uuid,
+		  project_type_id = NULLIF($4, '')::uuid,
+		  first_response_minutes = $5,
+		  resolution_minutes = $6,
+		  warning_minutes = $7,
+		  active = COALESCE($8, active),
+		  updated = NOW()
+		WHERE id::text = $1
+		RETURNING id
+		`,
+		input.ID,
+		input.Name,
+		input.ClientID,
+		input.ProjectTypeID,
+		input.FirstResponseMinutes,
+		input.ResolutionMinutes,
+		*input.WarningMinutes,
+		input.Active,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.SLAPolicy{}, usecase.ErrNotFound
+		}
+		return usecase.SLAPolicy{}, mapSLAPersistenceError(err)
+	}
+
+	return r.GetSLAPolicy(ctx, policyID)
+}
+
+// DeleteSLAPolicy keeps the deadlines already computed for existing
+// requests; their policy_id is cleared but the policy name is kept.
+func (r *SLARepository) DeleteSLAPolicy(ctx context.Context, policyID string) error {
+	ctx, span := startSpan(ctx, "SLARepository.DeleteSLAPolicy")
+	defer span.End()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		"DELETE FROM sla_policies WHERE id::text = $1",
+		policyID,
+	)
+	if err != nil {
+		return mapSLAPersistenceError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return usecase.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SLARepository) FindServiceRequestSLAPolicy(
+	ctx context.Context,
+	requestID string,
+) (usecase.SLAPolicy, time.Time, error) {
+	ctx, span := startSpan(ctx, "SLARepository.FindServiceRequestSLAPolicy")
+	defer span.End()
+
+	var record struct {
+		slaPolicyRecord
+		RequestCreated time.Time `db:"request_created"`
+	}
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		`
+		SELECT
+		  policy.id,
+		  policy.name,
+		  COALESCE(policy.client_id::text, '') AS client_id,
+		  '' AS client_name,
+		  COALESCE(policy.project_type_id::text, '') AS project_type_id,
+		  '' AS project_type_name,
+		  policy.first_response_minutes,
+		  policy.resolution_minutes,
+		  policy.warning_minutes,
+		  policy.active,
+		  policy.created,
+		  policy.updated,
+		  request.created AS request_created
+		FROM client_service_requests request
+		LEFT JOIN projects project ON project.id = request.project_id
+		JOIN sla_policies policy
+		  ON policy.active = TRUE
+		 AND (policy.client_id IS NULL OR policy.client_id = request.client_id)
+		 AND (policy.project_type_id IS NULL OR policy.project_type_id = project.project_type_id)
+		WHERE request.id::text = $1
+		ORDER BY
+		  (policy.client_id IS NULL) ASC,
+		  (policy.project_type_id IS NULL) ASC,
+		  policy.created DESC
+		LIMIT 1
+		`,
+		requestID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.SLAPolicy{}, time.Time{}, usecase.ErrNotFound
+		}
+		return usecase.SLAPolicy{}, time.Time{}, err
+	}
+
+	return mapSLAPolicyRecord(record.slaPolicyRecord), record.RequestCreated, nil
+}
+
+func (r *SLARepository) CreateServiceRequestSLA(
+	ctx context.Context,
+	input usecase.CreateServiceRequestSLAInput,
+) error {
+	ctx, span := startSpan(ctx, "SLARepository.CreateServiceRequestSLA")
+	defer span.End()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`
+		INSERT INTO client_service_request_slas (
+		  service_request_id,
+		  policy_id,
+		  policy_name,
+		  first_response_due_at,
+		  first_response_warn_at,
+		  resolution_due_at,
+		  resolution_warn_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (service_request_id) DO NOTHING
+		`,
+		input.ServiceRequestID,
+		input.PolicyID,
+		input.PolicyName,
+		input.FirstResponseDueAt,
+		input.FirstResponseWarnAt,
+		input.ResolutionDueAt,
+		input.ResolutionWarnAt,
+	)
+	return mapSLAPersistenceError(err)
+}
+
+// ClaimSLAWarnings only claims targets that are still before their deadline;
+// the ones that already expired are reported by ClaimSLABreaches instead.
+func (r *SLARepository) ClaimSLAWarnings(ctx context.Context, limit int) ([]usecase.SLAEscalation, error) {
+	ctx, span := startSpan(ctx, "SLARepository.ClaimSLAWarnings")
+	defer span.End()
+
+	escalations := []usecase.SLAEscalation{}
+	for _, columns := range slaTargets {
+		claimed, err := r.claimSLAEscalations(
+			ctx,
+			claimSLAEscalationSQL(
+				columns,
+				columns.warnedAt+" = NOW()",
+				columns.warnedAt+" IS NULL AND "+columns.warnAt+" <= NOW() AND "+columns.dueAt+" > NOW()",
+			),
+			columns.target,
+			limit,
+		)
+		if err != nil {
+			return escalations, err
+		}
+		escalations = append(escalations, claimed...)
+	}
+
+	return escalations, nil
+}
+
+func (r *SLARepository) ClaimSLABreaches(ctx context.Context, limit int) ([]usecase.SLAEscalation, error) {
+	ctx, span := startSpan(ctx, "SLARepository.ClaimSLABreaches")
+	defer span.End()
+
+	escalations := []usecase.SLAEscalation{}
+	for _, columns := range slaTargets {
+		claimed, err := r.claimSLAEscalations(
+			ctx,
+			claimSLAEscalationSQL(
+				columns,
+				columns.breached+" = TRUE",
+				columns.breached+" = FALSE AND "+columns.dueAt+" <= NOW()",
+			),
+			columns.target,
+			limit,
+		)
+		if err != nil {
+			return escalations, err
+		}
+		escalations = append(escalations, claimed...)
+	}
+
+	return escalations, nil
+}
+
+func (r *SLARepository) claimSLAEscalations(
+	ctx context.Context,
+	query string,
+	target string,
+	limit int,
+) ([]usecase.SLAEscalation, error) {
+	var records []slaEscalationRecord
+	if err := r.db.SelectContext(ctx, &records, query, limit, target); err != nil {
+		return nil, err
+	}
+
+	escalations := make([]usecase.SLAEscalation, 0, len(records))
+	for _, record := range records {
+		escalations = append(escalations, usecase.SLAEscalation{
+			ServiceRequestID: record.ServiceRequestID,
+			Title:            record.Title,
+			ProjectID:        record.ProjectID,
+			ClientName:       record.ClientName,
+			AssignedUserID:   record.AssignedUserID,
+			Target:           record.Target,
+			DueAt:            record.DueAt,
+		})
+	}
+
+	return escalations, nil
+}
+
+func mapSLAPolicyRecord(record slaPolicyRecord) usecase.SLAPolicy {
+	return usecase.SLAPolicy{
+		ID:                   record.ID,
+		Name:                 record.Name,
+		ClientID:             record.ClientID,
+		ClientName:           record.ClientName,
+		ProjectTypeID:        record.ProjectTypeID,
+		ProjectTypeName:      record.ProjectTypeName,
+		FirstResponseMinutes: record.FirstResponseMinutes,
+		ResolutionMinutes:    record.ResolutionMinutes,
+		WarningMinutes:       record.WarningMinutes,
+		Active:               record.Active,
+		Created:              record.Created,
+		Updated:              record.Updated,
+	}
+}
+
+func mapSLAPersistenceError(err error) error {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			if pgErr.Constraint == "sla_policies_scope_key" {
+				return usecase.ErrSLAPolicyScopeInUse
+			}
+			return usecase.ErrConflict
+		case "22P02", "23514":
+			return usecase.ErrInvalidInput
+		case "23503":
+			return usecase.ErrNotFound
+		}
+	}
+
+	return err
+}
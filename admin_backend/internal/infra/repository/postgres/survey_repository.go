@@ -0,0 +1,426 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type SurveyRepository struct {
+	db *sqlx.DB
+}
+
+func NewSurveyRepository(db *sqlx.DB) *SurveyRepository {
+	return &SurveyRepository{db: db}
+}
+
+type clientSurveyRecord struct {
+	ID                  string        `db:"id"`
+	Kind                string        `db:"kind"`
+	ClientID            string        `db:"client_id"`
+	ClientName          string        `db:"client_name"`
+	ServiceRequestID    string        `db:"service_request_id"`
+	ServiceRequestTitle string        `db:"service_request_title"`
+	ProjectID           string        `db:"project_id"`
+	ProjectName         string        `db:"project_name"`
+	Status              string        `db:"status"`
+	Rating              sql.NullInt64 `db:"rating"`
+	NPS                 sql.NullInt64 `db:"nps"`
+	Comment             string        `db:"comment"`
+	AnsweredAt          *time.Time    `db:"answered_at"`
+	Created             time.Time     `db:"created"`
+	Updated             time.Time     `db:"updated"`
+}
+
+type surveyStatsRecord struct {
+	Responses     int             `db:"responses"`
+	Pending       int             `db:"pending"`
+	AverageRating sql.NullFloat64 `db:"average_rating"`
+	CSAT          sql.NullFloat64 `db:"csat"`
+	NPSResponses  int             `db:"nps_responses"`
+	Promoters     int             `db:"promoters"`
+	Passives      int             `db:"passives"`
+	Detractors    int             `db:"detractors"`
+	NPS           sql.NullFloat64 `db:"nps"`
+}
+
+const clientSurveySelectSQL = `
+SELECT
+  survey.id,
+  survey.kind,
+  survey.client_id,
+  COALESCE(client_record.name, '') AS client_name,
+  COALESCE(survey.service_request_id::text, '') AS service_request_id,
+  COALESCE(request.title, '') AS service_request_title,
+  COALESCE(survey.project_id::text, '') AS project_id,
+  COALESCE(project.name, '') AS project_name,
+  survey.status,
+  survey.rating,
+  survey.nps,
+  survey.comment,
+  survey.answered_at,
+  survey.created,
+  survey.updated
+FROM client_surveys survey
+LEFT JOIN clients client_record ON client_record.id = survey.client_id
+LEFT JOIN client_service_requests request ON request.id = survey.service_request_id
+LEFT JOIN projects project ON project.id = survey.project_id
+`
+
+// scopedSurveysSQL selects the surveys of a report: the answers given in the
+// period and the surveys created in it that are still pending. $1 and $2 are
+// the period, $3 the project and $4 the project manager.
+const scopedSurveysSQL = `
+WITH scoped AS (
+  SELECT survey.*
+  FROM client_surveys survey
+  WHERE (
+      (survey.status = 'respondida' AND survey.answered_at >= $1 AND survey.answered_at < $2)
+      OR (survey.status = 'pendente' AND survey.created >= $1 AND survey.created < $2)
+    )
+    AND ($3 = '' OR survey.project_id::text = $3)
+    AND (
+      $4 = ''
+      OR EXISTS (
+        SELECT 1
+        FROM project_managers manager
+        WHERE manager.project_id = survey.project_id
+          AND manager.user_id::text = $4
+      )
+    )
+)
+`
+
+const surveyStatsColumnsSQL = `
+  COUNT(*) FILTER (WHERE scoped.status = 'respondida')::int AS responses,
+  COUNT(*) FILTER (WHERE scoped.status = 'pendente')::int AS pending,
+  (AVG(scoped.rating) FILTER (WHERE scoped.status = 'respondida'))::float8 AS average_rating,
+  (
+    100.0 * COUNT(*) FILTER (WHERE scoped.rating >= 4)
+    / NULLIF(COUNT(*) FILTER (WHERE scoped.status = 'respondida'), 0)
+  )::float8 AS csat,
+  COUNT(scoped.nps)::int AS nps_responses,
+  COUNT(*) FILTER (WHERE scoped.nps >= 9)::int AS promoters,
+  COUNT(*) FILTER (WHERE scoped.nps BETWEEN 7 AND 8)::int AS passives,
+  COUNT(*) FILTER (WHERE scoped.nps <= 6)::int AS detractors,
+  (
+    100.0 * (COUNT(*) FILTER (WHERE scoped.nps >= 9) - COUNT(*) FILTER (WHERE scoped.nps <= 6))
+    / NULLIF(COUNT(scoped.nps), 0)
+  )::float8 AS nps
+`
+
+func (r *SurveyRepository) ListClientSurveys(
+	ctx context.Context,
+	clientID string,
+	status string,
+) ([]usecase.ClientSurvey, error) {
+	ctx, span := startSpan(ctx, "SurveyRepository.ListClientSurveys")
+	defer span.End()
+
+	var records []clientSurveyRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		clientSurveySelectSQL+`
+		WHERE survey.client_id::text = $1
+		  AND ($2 = '' OR survey.status = $2)
+		ORDER BY
+		  CASE WHEN survey.status = 'pendente' THEN 0 ELSE 1 END,
+		  survey.created DESC,
+		  survey.id DESC
+		`,
+		clientID,
+		status,
+	); err != nil {
+		if isUndefinedRelationOrColumn(err) {
+			return []usecase.ClientSurvey{}, nil
+		}
+		return nil, err
+	}
+
+	return mapClientSurveyRecords(records), nil
+}
+
+func (r *SurveyRepository) AnswerClientSurvey(
+	ctx context.Context,
+	input usecase.AnswerClientSurveyInput,
+) (usecase.ClientSurvey, error) {
+	ctx, span := startSpan(ctx, "SurveyRepository.AnswerClientSurvey")
+	defer span.End()
+
+	var surveyID string
+	if err := r.db.GetContext(
+		ctx,
+		&surveyID,
+		`
+		UPDATE client_surveys
+		SET
+		  status = 'respondida',
+		  rating = $3,
+		  nps = CASE WHEN kind = 'project' THEN $4 ELSE NULL END,
+		  comment = $5,
+		  answered_at = NOW(),
+		  updated = NOW()
+		WHERE id::text = $1
+		  AND client_id::text = $2
+		  AND status = 'pendente'
+		RETURNING id
+		`,
+		input.SurveyID,
+		input.ClientID,
+		input.Rating,
+		input.NPS,
+		input.Comment,
+	); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return usecase.ClientSurvey{}, mapSurveyPersistenceError(err)
+		}
+
+		var status string
+		if statusErr := r.db.GetContext(
+			ctx,
+			&status,
+			"SELECT status FROM client_surveys WHERE id::text = $1 AND client_id::text = $2",
+			input.SurveyID,
+			input.ClientID,
+		); statusErr != nil {
+			if errors.Is(statusErr, sql.ErrNoRows) {
+				return usecase.ClientSurvey{}, usecase.ErrNotFound
+			}
+			return usecase.ClientSurvey{}, statusErr
+		}
+		return usecase.ClientSurvey{}, usecase.ErrConflict
+	}
+
+	var record clientSurveyRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		clientSurveySelectSQL+"WHERE survey.id = $1",
+		surveyID,
+	); err != nil {
+		return usecase.ClientSurvey{}, err
+	}
+
+	return mapClientSurveyRecord(record), nil
+}
+
+func (r *SurveyRepository) ListSurveyResponses(
+	ctx context.Context,
+	filter usecase.SurveyReportFilter,
+) ([]usecase.ClientSurvey, error) {
+	ctx, span := startSpan(ctx, "SurveyRepository.ListSurveyResponses")
+	defer span.End()
+
+	var records []clientSurveyRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		clientSurveySelectSQL+`
+		WHERE survey.status = 'respondida'
+		  AND survey.answered_at >= $1
+		  AND survey.answered_at < $2
+		  AND ($3 = '' OR survey.project_id::text = $3)
+		  AND (
+		    $4 = ''
+		    OR EXISTS (
+		      SELECT 1
+		      FROM project_managers manager
+		      WHERE manager.project_id = survey.project_id
+		        AND manager.user_id::text = $4
+		    )
+		  )
+		ORDER BY survey.answered_at DESC, survey.id DESC
+		`,
+		filter.From,
+		filter.To,
+		filter.ProjectID,
+		filter.ManagerUserID,
+	); err != nil {
+		return nil, err
+	}
+
+	return mapClientSurveyRecords(records), nil
+}
+
+// GetSurveyReport aggregates the scoped surveys overall, per kind, per
+// project and per project manager. A project with several managers counts
+// for each of them.
+func (r *SurveyRepository) GetSurveyReport(
+	ctx context.Context,
+	filter usecase.SurveyReportFilter,
+) (usecase.SurveyReport, error) {
+	ctx, span := startSpan(ctx, "SurveyRepository.GetSurveyReport")
+	defer span.End()
+
+	args := []interface{}{filter.From, filter.To, filter.ProjectID, filter.ManagerUserID}
+
+	var overall surveyStatsRecord
+	if err := r.db.GetContext(
+		ctx,
+		&overall,
+		scopedSurveysSQL+"SELECT"+surveyStatsColumnsSQL+"FROM scoped",
+		args...,
+	); err != nil {
+		return usecase.SurveyReport{}, err
+	}
+
+	var kindRecords []struct {
+		Kind string `db:"kind"`
+		surveyStatsRecord
+	}
+	if err := r.db.SelectContext(
+		ctx,
+		&kindRecords,
+		scopedSurveysSQL+"SELECT scoped.kind,"+surveyStatsColumnsSQL+"FROM scoped GROUP BY scoped.kind",
+		args...,
+	); err != nil {
+		return usecase.SurveyReport{}, err
+	}
+
+	var projectRecords []struct {
+		ProjectID   string `db:"project_id"`
+		ProjectName string `db:"project_name"`
+		surveyStatsRecord
+	}
+	if err := r.db.SelectContext(
+		ctx,
+		&projectRecords,
+		scopedSurveysSQL+`
+		SELECT
+		  project.id AS project_id,
+		  project.name AS project_name,`+surveyStatsColumnsSQL+`
+		FROM scoped
+		INNER JOIN projects project ON project.id = scoped.project_id
+		GROUP BY project.id, project.name
+		ORDER BY LOWER(project.name), project.id
+		`,
+		args...,
+	); err != nil {
+		return usecase.SurveyReport{}, err
+	}
+
+	var managerRecords []struct {
+		UserID   string `db:"user_id"`
+		UserName string `db:"user_name"`
+		surveyStatsRecord
+	}
+	if err := r.db.SelectContext(
+		ctx,
+		&managerRecords,
+		scopedSurveysSQL+`
+		SELECT
+		  user_record.id AS user_id,
+		  user_record.name AS user_name,`+surveyStatsColumnsSQL+`
+		FROM scoped
+		INNER JOIN project_managers manager ON manager.project_id = scoped.project_id
+		INNER JOIN users user_record ON user_record.id = manager.user_id
+		GROUP BY user_record.id, user_record.name
+		ORDER BY LOWER(user_record.name), user_record.id
+		`,
+		args...,
+	); err != nil {
+		return usecase.SurveyReport{}, err
+	}
+
+	report := usecase.SurveyReport{
+		Overall:   mapSurveyStatsRecord(overall),
+		ByKind:    make(map[string]usecase.SurveyStats, len(kindRecords)),
+		ByProject: make([]usecase.ProjectSurveyStats, 0, len(projectRecords)),
+		ByManager: make([]usecase.ManagerSurveyStats, 0, len(managerRecords)),
+	}
+	for _, record := range kindRecords {
+		report.ByKind[record.Kind] = mapSurveyStatsRecord(record.surveyStatsRecord)
+	}
+	for _, record := range projectRecords {
+		report.ByProject = append(report.ByProject, usecase.ProjectSurveyStats{
+			ProjectID:   record.ProjectID,
+			ProjectName: record.ProjectName,
+			SurveyStats: mapSurveyStatsRecord(record.surveyStatsRecord),
+		})
+	}
+	for _, record := range managerRecords {
+		report.ByManager = append(report.ByManager, usecase.ManagerSurveyStats{
+			UserID:      record.UserID,
+			UserName:    record.UserName,
+			SurveyStats: mapSurveyStatsRecord(record.surveyStatsRecord),
+		})
+	}
+
+	return report, nil
+}
+
+func mapClientSurveyRecords(records []clientSurveyRecord) []usecase.ClientSurvey {
+	surveys := make([]usecase.ClientSurvey, 0, len(records))
+	for _, record := range records {
+		surveys = append(surveys, mapClientSurveyRecord(record))
+	}
+	return surveys
+}
+
+func mapClientSurveyRecord(record clientSurveyRecord) usecase.ClientSurvey {
+	return usecase.ClientSurvey{
+		ID:                 record.ID,
+		Kind:               record.Kind,
+		ClientID:           record.ClientID,
+		ClientName:         record.ClientName,
+		ServiceRequestID:   record.ServiceRequestID,
+		ServiceRequestName: record.ServiceRequestTitle,
+		ProjectID:          record.ProjectID,
+		ProjectName:        record.ProjectName,
+		Status:             record.Status,
+		Rating:             nullInt64ToIntPointer(record.Rating),
+		NPS:                nullInt64ToIntPointer(record.NPS),
+		Comment:            record.Comment,
+		AnsweredAt:         record.AnsweredAt,
+		Created:            record.Created,
+		Updated:            record.Updated,
+	}
+}
+
+func mapSurveyStatsRecord(record surveyStatsRecord) usecase.SurveyStats {
+	return usecase.SurveyStats{
+		Responses:     record.Responses,
+		Pending:       record.Pending,
+		AverageRating: nullFloat64ToPointer(record.AverageRating),
+		CSAT:          nullFloat64ToPointer(record.CSAT),
+		NPSResponses:  record.NPSResponses,
+		Promoters:     record.Promoters,
+		Passives:      record.Passives,
+		Detractors:    record.Detractors,
+		NPS:           nullFloat64ToPointer(record.NPS),
+	}
+}
+
+func nullInt64ToIntPointer(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	converted := int(value.Int64)
+	return &converted
+}
+
+func nullFloat64ToPointer(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	converted := value.Float64
+	return &converted
+}
+
+func mapSurveyPersistenceError(err error) error {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "22P02", "23514":
+			return usecase.ErrInvalidInput
+		}
+	}
+
+	return err
+}
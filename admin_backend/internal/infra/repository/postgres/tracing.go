@@ -0,0 +1,14 @@
+package postgres
+
+import (
+	"context"
+
+	"admin_backend/internal/infra/tracing"
+)
+
+// startSpan opens the client span of one repository call.
+func startSpan(ctx context.Context, name string) (context.Context, *tracing.Span) {
+	ctx, span := tracing.Start(ctx, name, tracing.KindClient)
+	span.SetAttribute("db.system", "postgresql")
+	return ctx, span
+}
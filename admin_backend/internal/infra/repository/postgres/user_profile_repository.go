@@ -21,6 +21,9 @@ func (r *UserProfileRepository) ListProfilesByUserID(
 	ctx context.Context,
 	userID string,
 ) ([]usecase.UserProfile, error) {
+	ctx, span := startSpan(ctx, "UserProfileRepository.ListProfilesByUserID")
+	defer span.End()
+
 	var records []struct {
 		ID          string `db:"id"`
 		Name        string `db:"name"`
@@ -60,6 +63,9 @@ func (r *UserProfileRepository) ListProfilesByUserID(
 }
 
 func (r *UserProfileRepository) UserExists(ctx context.Context, userID string) (bool, error) {
+	ctx, span := startSpan(ctx, "UserProfileRepository.UserExists")
+	defer span.End()
+
 	var exists bool
 	if err := r.db.GetContext(
 		ctx,
@@ -77,6 +83,9 @@ func (r *UserProfileRepository) ListProfileIDsByUserID(
 	ctx context.Context,
 	userID string,
 ) ([]string, error) {
+	ctx, span := startSpan(ctx, "UserProfileRepository.ListProfileIDsByUserID")
+	defer span.End()
+
 	var profileIDs []string
 	if err := r.db.SelectContext(
 		ctx,
@@ -100,6 +109,9 @@ func (r *UserProfileRepository) ReplaceUserProfileIDs(
 	userID string,
 	profileIDs []string,
 ) error {
+	ctx, span := startSpan(ctx, "UserProfileRepository.ReplaceUserProfileIDs")
+	defer span.End()
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
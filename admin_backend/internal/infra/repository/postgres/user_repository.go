@@ -0,0 +1,384 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type UserRepository struct {
+	db *sqlx.DB
+}
+
+func NewUserRepository(db *sqlx.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+type userRecord struct {
+	ID         string         `db:"id"`
+	Name       string         `db:"name"`
+	Email      string         `db:"email"`
+	Login      string         `db:"login"`
+	Phone      string         `db:"phone"`
+	Address    string         `db:"address"`
+	Avatar     string         `db:"avatar"`
+	Active     bool           `db:"active"`
+	ProfileIDs pq.StringArray `db:"profile_ids"`
+	Created    time.Time      `db:"created"`
+	Updated    time.Time      `db:"updated"`
+}
+
+const userSelectColumns = `
+	  account.id,
+	  account.name,
+	  account.email,
+	  account.login,
+	  COALESCE(account.phone, '') AS phone,
+	  COALESCE(account.address, '') AS address,
+	  COALESCE(account.avatar, '') AS avatar,
+	  account.ativo AS active,
+	  ARRAY(
+	    SELECT user_profile.profile_id::text
+	    FROM user_profiles user_profile
+	    WHERE user_profile.user_id = account.id
+	    ORDER BY user_profile.created, user_profile.profile_id
+	  ) AS profile_ids,
+	  account.created,
+	  account.updated
+`
+
+func (r *UserRepository) List(ctx context.Context, filter usecase.UserListFilter) ([]usecase.User, error) {
+	ctx, span := startSpan(ctx, "UserRepository.List")
+	defer span.End()
+
+	query := `
+		SELECT` + userSelectColumns + `
+		FROM users account
+		WHERE (
+		  $1 = ''
+		  OR LOWER(account.name) LIKE LOWER('%' || $1 || '%')
+		  OR LOWER(account.email) LIKE LOWER('%' || $1 || '%')
+		  OR LOWER(account.login) LIKE LOWER('%' || $1 || '%')
+		)
+		  AND (
+		    $2 = ''
+		    OR EXISTS (
+		      SELECT 1
+		      FROM user_profiles user_profile
+		      WHERE user_profile.user_id = account.id
+		        AND user_profile.profile_id::text = $2
+		    )
+		  )
+	`
+
+	if filter.OnlyActive {
+		query += " AND account.ativo = TRUE"
+	}
+
+	query += " ORDER BY account.created DESC, account.id DESC"
+
+	var records []userRecord
+	if err := r.db.SelectContext(ctx, &records, query, filter.Search, filter.ProfileID); err != nil {
+		return nil, err
+	}
+
+	users := make([]usecase.User, 0, len(records))
+	for _, record := range records {
+		users = append(users, mapUserRecord(record))
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, userID string) (usecase.User, error) {
+	ctx, span := startSpan(ctx, "UserRepository.GetByID")
+	defer span.End()
+
+	return r.getByID(ctx, r.db, userID)
+}
+
+func (r *UserRepository) Create(ctx context.Context, input usecase.CreateUserInput) (usecase.User, error) {
+	ctx, span := startSpan(ctx, "UserRepository.Create")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.User{}, err
+	}
+	defer tx.Rollback()
+
+	if err := ensureUserIdentityAvailable(ctx, tx, input.Login, input.Email, ""); err != nil {
+		return usecase.User{}, err
+	}
+
+	var userID string
+	if err := tx.GetContext(
+		ctx,
+		&userID,
+		`
+		INSERT INTO users (name, email, login, senha, phone, address, avatar, ativo, created, updated)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), $8, NOW(), NOW())
+		RETURNING id
+		`,
+		input.Name,
+		input.Email,
+		input.Login,
+		input.Password,
+		input.Phone,
+		input.Address,
+		input.Avatar,
+		input.Active,
+	); err != nil {
+		return usecase.User{}, mapUserPersistenceError(err)
+	}
+
+	if err := replaceUserProfiles(ctx, tx, userID, input.ProfileIDs); err != nil {
+		return usecase.User{}, err
+	}
+
+	user, err := r.getByID(ctx, tx, userID)
+	if err != nil {
+		return usecase.User{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.User{}, err
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, input usecase.UpdateUserInput) (usecase.User, error) {
+	ctx, span := startSpan(ctx, "UserRepository.Update")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return usecase.User{}, err
+	}
+	defer tx.Rollback()
+
+	if err := ensureUserIdentityAvailable(ctx, tx, input.Login, input.Email, input.ID); err != nil {
+		return usecase.User{}, err
+	}
+
+	result, err := tx.ExecContext(
+		ctx,
+		`
+		UPDATE users
+		SET name = $1,
+		    email = $2,
+		    login = $3,
+		    senha = COALESCE(NULLIF($4, ''), senha),
+		    phone = NULLIF($5, ''),
+		    address = NULLIF($6, ''),
+		    avatar = NULLIF($7, ''),
+		    ativo = COALESCE($8::boolean, ativo),
+		    updated = NOW()
+		WHERE id = $9
+		`,
+		input.Name,
+		input.Email,
+		input.Login,
+		input.Password,
+		input.Phone,
+		input.Address,
+		input.Avatar,
+		input.Active,
+		input.ID,
+	)
+	if err != nil {
+		return usecase.User{}, mapUserPersistenceError(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return usecase.User{}, err
+	}
+	if affected == 0 {
+		return usecase.User{}, usecase.ErrNotFound
+	}
+
+	if input.ProfileIDs != nil {
+		if err := replaceUserProfiles(ctx, tx, input.ID, *input.ProfileIDs); err != nil {
+			return usecase.User{}, err
+		}
+	}
+
+	user, err := r.getByID(ctx, tx, input.ID)
+	if err != nil {
+		return usecase.User{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return usecase.User{}, err
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) Deactivate(ctx context.Context, userID string) (usecase.User, error) {
+	ctx, span := startSpan(ctx, "UserRepository.Deactivate")
+	defer span.End()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		`
+		UPDATE users
+		SET ativo = FALSE,
+		    updated = NOW()
+		WHERE id = $1
+		`,
+		userID,
+	)
+	if err != nil {
+		return usecase.User{}, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return usecase.User{}, err
+	}
+	if affected == 0 {
+		return usecase.User{}, usecase.ErrNotFound
+	}
+
+	return r.GetByID(ctx, userID)
+}
+
+func (r *UserRepository) getByID(ctx context.Context, queryer sqlx.QueryerContext, userID string) (usecase.User, error) {
+	var record userRecord
+	if err := sqlx.GetContext(
+		ctx,
+		queryer,
+		&record,
+		`SELECT`+userSelectColumns+`
+		FROM users account
+		WHERE account.id = $1
+		LIMIT 1
+		`,
+		userID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.User{}, usecase.ErrNotFound
+		}
+		return usecase.User{}, err
+	}
+
+	return mapUserRecord(record), nil
+}
+
+// ensureUserIdentityAvailable rejects logins and e-mails already taken by
+// another user, ignoring case; the unique indexes only cover the login.
+func ensureUserIdentityAvailable(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	login string,
+	email string,
+	excludeUserID string,
+) error {
+	var taken struct {
+		Login bool `db:"login"`
+		Email bool `db:"email"`
+	}
+	if err := tx.GetContext(
+		ctx,
+		&taken,
+		`
+		SELECT
+		  EXISTS (
+		    SELECT 1 FROM users
+		    WHERE LOWER(login) = LOWER($1) AND ($3 = '' OR id::text <> $3)
+		  ) AS login,
+		  EXISTS (
+		    SELECT 1 FROM users
+		    WHERE LOWER(email) = LOWER($2) AND ($3 = '' OR id::text <> $3)
+		  ) AS email
+		`,
+		login,
+		email,
+		excludeUserID,
+	); err != nil {
+		return err
+	}
+
+	switch {
+	case taken.Login:
+		return usecase.ErrLoginInUse
+	case taken.Email:
+		return usecase.ErrEmailInUse
+	default:
+		return nil
+	}
+}
+
+func replaceUserProfiles(ctx context.Context, tx *sqlx.Tx, userID string, profileIDs []string) error {
+	if _, err := tx.ExecContext(
+		ctx,
+		"DELETE FROM user_profiles WHERE user_id = $1",
+		userID,
+	); err != nil {
+		return err
+	}
+
+	for _, profileID := range profileIDs {
+		if _, err := tx.ExecContext(
+			ctx,
+			`
+			INSERT INTO user_profiles (user_id, profile_id, created)
+			VALUES ($1, $2, NOW())
+			`,
+			userID,
+			profileID,
+		); err != nil {
+			var pgErr *pq.Error
+			if errors.As(err, &pgErr) && (pgErr.Code == "23503" || pgErr.Code == "22P02") {
+				return usecase.ErrProfilesNotFound
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func mapUserRecord(record userRecord) usecase.User {
+	profileIDs := []string(record.ProfileIDs)
+	if profileIDs == nil {
+		profileIDs = []string{}
+	}
+
+	return usecase.User{
+		ID:         record.ID,
+		Name:       record.Name,
+		Email:      record.Email,
+		Login:      record.Login,
+		Phone:      record.Phone,
+		Address:    record.Address,
+		Avatar:     record.Avatar,
+		Active:     record.Active,
+		ProfileIDs: profileIDs,
+		Created:    record.Created,
+		Updated:    record.Updated,
+	}
+}
+
+func mapUserPersistenceError(err error) error {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		switch pgErr.Constraint {
+		case "users_email_key":
+			return usecase.ErrEmailInUse
+		case "users_login_key", "users_login_lower_key":
+			return usecase.ErrLoginInUse
+		}
+		if pgErr.Code == "23505" {
+			return usecase.ErrConflict
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,601 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type WebhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+type webhookSubscriptionRecord struct {
+	ID         string         `db:"id"`
+	Name       string         `db:"name"`
+	URL        string         `db:"url"`
+	Secret     string         `db:"secret"`
+	EventTypes pq.StringArray `db:"event_types"`
+	Active     bool           `db:"active"`
+	CreatedBy  string         `db:"created_by"`
+	Created    time.Time      `db:"created"`
+	Updated    time.Time      `db:"updated"`
+}
+
+type webhookDeliveryRecord struct {
+	ID             string     `db:"id"`
+	EventID        string     `db:"event_id"`
+	EventType      string     `db:"event_type"`
+	SubscriptionID string     `db:"subscription_id"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	NextAttemptAt  *time.Time `db:"next_attempt_at"`
+	LastStatusCode *int       `db:"last_status_code"`
+	LastError      string     `db:"last_error"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+	Payload        []byte     `db:"payload"`
+	Created        time.Time  `db:"created"`
+	Updated        time.Time  `db:"updated"`
+}
+
+type webhookDeliveryAttemptRecord struct {
+	ID           string    `db:"id"`
+	StatusCode   *int      `db:"status_code"`
+	Error        string    `db:"error"`
+	ResponseBody string    `db:"response_body"`
+	DurationMS   int       `db:"duration_ms"`
+	AttemptedAt  time.Time `db:"attempted_at"`
+}
+
+type webhookDispatchRecord struct {
+	DeliveryID     string    `db:"delivery_id"`
+	EventID        string    `db:"event_id"`
+	EventType      string    `db:"event_type"`
+	EventCreated   time.Time `db:"event_created"`
+	Payload        []byte    `db:"payload"`
+	SubscriptionID string    `db:"subscription_id"`
+	URL            string    `db:"url"`
+	Secret         string    `db:"secret"`
+	Attempt        int       `db:"attempt"`
+}
+
+const webhookSubscriptionColumnsSQL = `
+  id,
+  name,
+  url,
+  secret,
+  event_types,
+  active,
+  COALESCE(created_by::text, '') AS created_by,
+  created,
+  updated
+`
+
+const webhookDeliverySelectSQL = `
+SELECT
+  delivery.id,
+  delivery.event_id,
+  event.event_type,
+  delivery.subscription_id,
+  delivery.status,
+  delivery.attempts,
+  CASE WHEN delivery.status = 'pendente' THEN delivery.next_attempt_at END AS next_attempt_at,
+  delivery.last_status_code,
+  delivery.last_error,
+  delivery.delivered_at,
+  event.payload,
+  delivery.created,
+  delivery.updated
+FROM webhook_deliveries delivery
+JOIN webhook_outbox event ON event.id = delivery.event_id
+`
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]usecase.WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "WebhookRepository.ListSubscriptions")
+	defer span.End()
+
+	var records []webhookSubscriptionRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		"SELECT"+webhookSubscriptionColumnsSQL+"FROM webhook_subscriptions ORDER BY LOWER(name), created",
+	); err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]usecase.WebhookSubscription, 0, len(records))
+	for _, record := range records {
+		subscriptions = append(subscriptions, mapWebhookSubscriptionRecord(record))
+	}
+
+	return subscriptions, nil
+}
+
+func (r *WebhookRepository) GetSubscription(
+	ctx context.Context,
+	subscriptionID string,
+) (usecase.WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "WebhookRepository.GetSubscription")
+	defer span.End()
+
+	var record webhookSubscriptionRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		"SELECT"+webhookSubscriptionColumnsSQL+"FROM webhook_subscriptions WHERE id::text = $1",
+		subscriptionID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.WebhookSubscription{}, usecase.ErrNotFound
+		}
+		return usecase.WebhookSubscription{}, err
+	}
+
+	return mapWebhookSubscriptionRecord(record), nil
+}
+
+func (r *WebhookRepository) CreateSubscription(
+	ctx context.Context,
+	input usecase.CreateWebhookSubscriptionInput,
+) (usecase.WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "WebhookRepository.CreateSubscription")
+	defer span.End()
+
+	var record webhookSubscriptionRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		`
+		INSERT INTO webhook_subscriptions (name, url, secret, event_types, active, created_by)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')::uuid)
+		RETURNING`+webhookSubscriptionColumnsSQL,
+		input.Name,
+		input.URL,
+		input.Secret,
+		pq.Array(input.EventTypes),
+		input.Active,
+		input.CreatedBy,
+	); err != nil {
+		return usecase.WebhookSubscription{}, mapWebhookPersistenceError(err)
+	}
+
+	return mapWebhookSubscriptionRecord(record), nil
+}
+
+func (r *WebhookRepository) UpdateSubscription(
+	ctx context.Context,
+	input usecase.UpdateWebhookSubscriptionInput,
+) (usecase.WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "WebhookRepository.UpdateSubscription")
+	defer span.End()
+
+	var record webhookSubscriptionRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		`
+		UPDATE webhook_subscriptions
+		SET
+		  name = $2,
+		  url = $3,
+		  secret = COALESCE(NULLIF($4, ''), secret),
+		  event_types = $5,
+		  active = COALESCE($6, active),
+		  updated = NOW()
+		WHERE id::text = $1
+		RETURNING`+webhookSubscriptionColumnsSQL,
+		input.ID,
+		input.Name,
+		input.URL,
+		input.Secret,
+		pq.Array(input.EventTypes),
+		input.Active,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.WebhookSubscription{}, usecase.ErrNotFound
+		}
+		return usecase.WebhookSubscription{}, mapWebhookPersistenceError(err)
+	}
+
+	return mapWebhookSubscriptionRecord(record), nil
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	ctx, span := startSpan(ctx, "WebhookRepository.DeleteSubscription")
+	defer span.End()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		"DELETE FROM webhook_subscriptions WHERE id::text = $1",
+		subscriptionID,
+	)
+	if err != nil {
+		return mapWebhookPersistenceError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return usecase.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) ListDeliveries(
+	ctx context.Context,
+	filter usecase.WebhookDeliveryFilter,
+) ([]usecase.WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "WebhookRepository.ListDeliveries")
+	defer span.End()
+
+	if _, err := r.GetSubscription(ctx, filter.SubscriptionID); err != nil {
+		return nil, err
+	}
+
+	var records []webhookDeliveryRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		webhookDeliverySelectSQL+`
+		WHERE delivery.subscription_id::text = $1
+		  AND ($2 = '' OR delivery.status = $2)
+		ORDER BY delivery.created DESC
+		LIMIT $3
+		`,
+		filter.SubscriptionID,
+		filter.Status,
+		filter.Limit,
+	); err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]usecase.WebhookDelivery, 0, len(records))
+	for _, record := range records {
+		deliveries = append(deliveries, mapWebhookDeliveryRecord(record))
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) GetDelivery(
+	ctx context.Context,
+	deliveryID string,
+) (usecase.WebhookDeliveryDetail, error) {
+	ctx, span := startSpan(ctx, "WebhookRepository.GetDelivery")
+	defer span.End()
+
+	var record webhookDeliveryRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		webhookDeliverySelectSQL+"WHERE delivery.id::text = $1",
+		deliveryID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.WebhookDeliveryDetail{}, usecase.ErrNotFound
+		}
+		return usecase.WebhookDeliveryDetail{}, err
+	}
+
+	var attemptRecords []webhookDeliveryAttemptRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&attemptRecords,
+		`
+		SELECT id, status_code, error, response_body, duration_ms, attempted_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY attempted_at
+		`,
+		record.ID,
+	); err != nil {
+		return usecase.WebhookDeliveryDetail{}, err
+	}
+
+	attempts := make([]usecase.WebhookDeliveryAttempt, 0, len(attemptRecords))
+	for _, attempt := range attemptRecords {
+		attempts = append(attempts, usecase.WebhookDeliveryAttempt{
+			ID:           attempt.ID,
+			StatusCode:   attempt.StatusCode,
+			Error:        attempt.Error,
+			ResponseBody: attempt.ResponseBody,
+			DurationMS:   attempt.DurationMS,
+			AttemptedAt:  attempt.AttemptedAt,
+		})
+	}
+
+	return usecase.WebhookDeliveryDetail{
+		WebhookDelivery: mapWebhookDeliveryRecord(record),
+		Payload:         record.Payload,
+		Attempts:        attempts,
+	}, nil
+}
+
+func (r *WebhookRepository) RedeliverDelivery(
+	ctx context.Context,
+	deliveryID string,
+) (usecase.WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "WebhookRepository.RedeliverDelivery")
+	defer span.End()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		`
+		UPDATE webhook_deliveries
+		SET
+		  status = 'pendente',
+		  attempts = 0,
+		  next_attempt_at = NOW(),
+		  updated = NOW()
+		WHERE id::text = $1
+		`,
+		deliveryID,
+	)
+	if err != nil {
+		return usecase.WebhookDelivery{}, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return usecase.WebhookDelivery{}, err
+	}
+	if affected == 0 {
+		return usecase.WebhookDelivery{}, usecase.ErrNotFound
+	}
+
+	detail, err := r.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return usecase.WebhookDelivery{}, err
+	}
+
+	return detail.WebhookDelivery, nil
+}
+
+func (r *WebhookRepository) FanOutWebhookEvents(ctx context.Context, limit int) (int, error) {
+	ctx, span := startSpan(ctx, "WebhookRepository.FanOutWebhookEvents")
+	defer span.End()
+
+	var created int
+	if err := r.db.GetContext(
+		ctx,
+		&created,
+		`
+		WITH events AS (
+		  SELECT id, event_type
+		  FROM webhook_outbox
+		  WHERE dispatched_at IS NULL
+		  ORDER BY created
+		  LIMIT $1
+		  FOR UPDATE SKIP LOCKED
+		),
+		inserted AS (
+		  INSERT INTO webhook_deliveries (event_id, subscription_id)
+		  SELECT event.id, subscription.id
+		  FROM events event
+		  JOIN webhook_subscriptions subscription
+		    ON subscription.active = TRUE
+		   AND (
+		     event.event_type = ANY(subscription.event_types)
+		     OR '*' = ANY(subscription.event_types)
+		   )
+		  ON CONFLICT (event_id, subscription_id) DO NOTHING
+		  RETURNING id
+		),
+		dispatched AS (
+		  UPDATE webhook_outbox
+		  SET dispatched_at = NOW()
+		  WHERE id IN (SELECT id FROM events)
+		  RETURNING id
+		)
+		SELECT COUNT(*)::int FROM inserted
+		`,
+		limit,
+	); err != nil {
+		return 0, err
+	}
+
+	return created, nil
+}
+
+// ClaimDueWebhookDeliveries counts the attempt up front and pushes
+// next_attempt_at past the lease, so a dispatcher that dies mid-send leaves
+// the delivery to be picked up again once the lease expires.
+func (r *WebhookRepository) ClaimDueWebhookDeliveries(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+) ([]usecase.WebhookDispatch, error) {
+	ctx, span := startSpan(ctx, "WebhookRepository.ClaimDueWebhookDeliveries")
+	defer span.End()
+
+	var records []webhookDispatchRecord
+	if err := r.db.SelectContext(
+		ctx,
+		&records,
+		`
+		WITH due AS (
+		  SELECT delivery.id
+		  FROM webhook_deliveries delivery
+		  JOIN webhook_subscriptions subscription ON subscription.id = delivery.subscription_id
+		  WHERE delivery.status = 'pendente'
+		    AND delivery.next_attempt_at <= NOW()
+		    AND subscription.active = TRUE
+		  ORDER BY delivery.next_attempt_at
+		  LIMIT $1
+		  FOR UPDATE OF delivery SKIP LOCKED
+		),
+		claimed AS (
+		  UPDATE webhook_deliveries delivery
+		  SET
+		    attempts = delivery.attempts + 1,
+		    next_attempt_at = NOW() + ($2::int * INTERVAL '1 second'),
+		    updated = NOW()
+		  FROM due
+		  WHERE delivery.id = due.id
+		  RETURNING delivery.id, delivery.event_id, delivery.subscription_id, delivery.attempts
+		)
+		SELECT
+		  claimed.id AS delivery_id,
+		  event.id AS event_id,
+		  event.event_type,
+		  event.created AS event_created,
+		  event.payload,
+		  subscription.id AS subscription_id,
+		  subscription.url,
+		  subscription.secret,
+		  claimed.attempts AS attempt
+		FROM claimed
+		JOIN webhook_outbox event ON event.id = claimed.event_id
+		JOIN webhook_subscriptions subscription ON subscription.id = claimed.subscription_id
+		ORDER BY event.created
+		`,
+		limit,
+		int(lease/time.Second),
+	); err != nil {
+		return nil, err
+	}
+
+	dispatches := make([]usecase.WebhookDispatch, 0, len(records))
+	for _, record := range records {
+		dispatches = append(dispatches, usecase.WebhookDispatch{
+			DeliveryID:     record.DeliveryID,
+			EventID:        record.EventID,
+			EventType:      record.EventType,
+			EventCreated:   record.EventCreated,
+			Payload:        record.Payload,
+			SubscriptionID: record.SubscriptionID,
+			URL:            record.URL,
+			Secret:         record.Secret,
+			Attempt:        record.Attempt,
+		})
+	}
+
+	return dispatches, nil
+}
+
+func (r *WebhookRepository) RecordWebhookAttempt(
+	ctx context.Context,
+	input usecase.RecordWebhookAttemptInput,
+) error {
+	ctx, span := startSpan(ctx, "WebhookRepository.RecordWebhookAttempt")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`
+		INSERT INTO webhook_delivery_attempts (delivery_id, status_code, error, response_body, duration_ms)
+		VALUES ($1, NULLIF($2, 0), $3, $4, $5)
+		`,
+		input.DeliveryID,
+		input.StatusCode,
+		input.Error,
+		input.ResponseBody,
+		int(input.Duration/time.Millisecond),
+	); err != nil {
+		return err
+	}
+
+	var nextAttemptAt *time.Time
+	if !input.NextAttemptAt.IsZero() {
+		nextAttemptAt = &input.NextAttemptAt
+	}
+
+	// A redelivery requested while the attempt was in flight resets attempts
+	// to zero; the result of the stale attempt must not overwrite it.
+	if _, err := tx.ExecContext(
+		ctx,
+		`
+		UPDATE webhook_deliveries
+		SET
+		  status = $2,
+		  last_status_code = NULLIF($3, 0),
+		  last_error = $4,
+		  next_attempt_at = COALESCE($5, next_attempt_at),
+		  delivered_at = CASE WHEN $2 = 'entregue' THEN NOW() ELSE delivered_at END,
+		  updated = NOW()
+		WHERE id = $1
+		  AND attempts = $6
+		`,
+		input.DeliveryID,
+		input.Status,
+		input.StatusCode,
+		input.Error,
+		nextAttemptAt,
+		input.Attempts,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func mapWebhookSubscriptionRecord(record webhookSubscriptionRecord) usecase.WebhookSubscription {
+	eventTypes := []string(record.EventTypes)
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	return usecase.WebhookSubscription{
+		ID:         record.ID,
+		Name:       record.Name,
+		URL:        record.URL,
+		Secret:     record.Secret,
+		EventTypes: eventTypes,
+		Active:     record.Active,
+		CreatedBy:  record.CreatedBy,
+		Created:    record.Created,
+		Updated:    record.Updated,
+	}
+}
+
+func mapWebhookDeliveryRecord(record webhookDeliveryRecord) usecase.WebhookDelivery {
+	return usecase.WebhookDelivery{
+		ID:             record.ID,
+		EventID:        record.EventID,
+		EventType:      record.EventType,
+		SubscriptionID: record.SubscriptionID,
+		Status:         record.Status,
+		Attempts:       record.Attempts,
+		NextAttemptAt:  record.NextAttemptAt,
+		LastStatusCode: record.LastStatusCode,
+		LastError:      record.LastError,
+		DeliveredAt:    record.DeliveredAt,
+		Created:        record.Created,
+		Updated:        record.Updated,
+	}
+}
+
+func mapWebhookPersistenceError(err error) error {
+	var pgErr *pq.Error
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "22P02", "23514":
+			return usecase.ErrInvalidInput
+		case "23503":
+			if pgErr.Constraint == "webhook_subscriptions_created_by_fkey" {
+				return usecase.ErrUserNotFound
+			}
+			return usecase.ErrNotFound
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+)
+
+type ZipCodeDirectoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewZipCodeDirectoryRepository(db *sqlx.DB) *ZipCodeDirectoryRepository {
+	return &ZipCodeDirectoryRepository{db: db}
+}
+
+type zipCodeRecord struct {
+	ZipCode      string     `db:"zip_code"`
+	Street       string     `db:"street"`
+	Complement   string     `db:"complement"`
+	Neighborhood string     `db:"neighborhood"`
+	City         string     `db:"city"`
+	State        string     `db:"state"`
+	IBGE         string     `db:"ibge"`
+	GIA          string     `db:"gia"`
+	DDD          string     `db:"ddd"`
+	SIAFI        string     `db:"siafi"`
+	NotFound     bool       `db:"not_found"`
+	Source       string     `db:"source"`
+	FetchedAt    time.Time  `db:"fetched_at"`
+	ExpiresAt    *time.Time `db:"expires_at"`
+}
+
+const zipCodeUpsertSQL = `
+INSERT INTO zip_codes (
+  zip_code,
+  street,
+  complement,
+  neighborhood,
+  city,
+  state,
+  ibge,
+  gia,
+  ddd,
+  siafi,
+  not_found,
+  source,
+  fetched_at,
+  expires_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+ON CONFLICT (zip_code) DO UPDATE SET
+  street = EXCLUDED.street,
+  complement = EXCLUDED.complement,
+  neighborhood = EXCLUDED.neighborhood,
+  city = EXCLUDED.city,
+  state = EXCLUDED.state,
+  ibge = EXCLUDED.ibge,
+  gia = EXCLUDED.gia,
+  ddd = EXCLUDED.ddd,
+  siafi = EXCLUDED.siafi,
+  not_found = EXCLUDED.not_found,
+  source = EXCLUDED.source,
+  fetched_at = EXCLUDED.fetched_at,
+  expires_at = EXCLUDED.expires_at
+`
+
+func (r *ZipCodeDirectoryRepository) GetZipCode(ctx context.Context, zipCode string) (usecase.ZipCodeRecord, error) {
+	ctx, span := startSpan(ctx, "ZipCodeDirectoryRepository.GetZipCode")
+	defer span.End()
+
+	var record zipCodeRecord
+	if err := r.db.GetContext(
+		ctx,
+		&record,
+		`
+		SELECT
+		  zip_code,
+		  street,
+		  complement,
+		  neighborhood,
+		  city,
+		  state,
+		  ibge,
+		  gia,
+		  ddd,
+		  siafi,
+		  not_found,
+		  source,
+		  fetched_at,
+		  expires_at
+		FROM zip_codes
+		WHERE zip_code = $1
+		`,
+		zipCode,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return usecase.ZipCodeRecord{}, usecase.ErrNotFound
+		}
+		return usecase.ZipCodeRecord{}, err
+	}
+
+	return mapZipCodeRecord(record), nil
+}
+
+// SaveZipCode stores a provider answer. Dataset rows are left alone: they
+// never expire, so a provider is only asked when the dataset lacks the zip
+// code.
+func (r *ZipCodeDirectoryRepository) SaveZipCode(ctx context.Context, record usecase.ZipCodeRecord) error {
+	ctx, span := startSpan(ctx, "ZipCodeDirectoryRepository.SaveZipCode")
+	defer span.End()
+
+	_, err := r.db.ExecContext(
+		ctx,
+		zipCodeUpsertSQL+"WHERE zip_codes.source <> $15",
+		append(zipCodeUpsertArgs(record), usecase.ZipCodeSourceDataset)...,
+	)
+	return err
+}
+
+func (r *ZipCodeDirectoryRepository) ImportZipCodes(ctx context.Context, records []usecase.ZipCodeRecord) error {
+	ctx, span := startSpan(ctx, "ZipCodeDirectoryRepository.ImportZipCodes")
+	defer span.End()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statement, err := tx.PreparexContext(ctx, zipCodeUpsertSQL)
+	if err != nil {
+		return err
+	}
+	defer statement.Close()
+
+	for _, record := range records {
+		if _, err := statement.ExecContext(ctx, zipCodeUpsertArgs(record)...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func zipCodeUpsertArgs(record usecase.ZipCodeRecord) []interface{} {
+	return []interface{}{
+		record.Result.ZipCode,
+		record.Result.Street,
+		record.Result.Complement,
+		record.Result.Neighborhood,
+		record.Result.City,
+		record.Result.State,
+		record.Result.IBGE,
+		record.Result.GIA,
+		record.Result.DDD,
+		record.Result.SIAFI,
+		record.NotFound,
+		record.Source,
+		record.FetchedAt,
+		record.ExpiresAt,
+	}
+}
+
+func mapZipCodeRecord(record zipCodeRecord) usecase.ZipCodeRecord {
+	return usecase.ZipCodeRecord{
+		Result: usecase.ZipCodeLookupResult{
+			ZipCode:      record.ZipCode,
+			Street:       record.Street,
+			Complement:   record.Complement,
+			Neighborhood: record.Neighborhood,
+			City:         record.City,
+			State:        record.State,
+			IBGE:         record.IBGE,
+			GIA:          record.GIA,
+			DDD:          record.DDD,
+			SIAFI:        record.SIAFI,
+		},
+		NotFound:  record.NotFound,
+		Source:    record.Source,
+		FetchedAt: record.FetchedAt,
+		ExpiresAt: record.ExpiresAt,
+	}
+}
@@ -0,0 +1,18 @@
+package scheduler
+
+import "time"
+
+type Config struct {
+	PaymentRemindersEnabled  bool
+	PaymentReminderInterval  time.Duration
+	PaymentReminderOffsets   string
+	WebhooksEnabled          bool
+	WebhookDispatchInterval  time.Duration
+	SLAEscalationEnabled     bool
+	SLAEscalationInterval    time.Duration
+	SLABusinessHours         string
+	SLABusinessDays          string
+	SLATimezone              string
+	AddressGeocodingEnabled  bool
+	AddressGeocodingInterval time.Duration
+}
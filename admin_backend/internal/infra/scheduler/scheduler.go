@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"admin_backend/internal/infra/tracing"
+)
+
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+	// Wake, when set, runs the job early each time it receives.
+	Wake <-chan struct{}
+}
+
+type Scheduler struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Start runs every job once right away and then on its interval until
+// Shutdown is called. Runs of the same job never overlap.
+func Start(jobs ...Job) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler := &Scheduler{cancel: cancel}
+
+	for _, job := range jobs {
+		if job.Run == nil || job.Interval <= 0 {
+			continue
+		}
+
+		scheduler.wg.Add(1)
+		go func(job Job) {
+			defer scheduler.wg.Done()
+			runJob(ctx, job)
+
+			ticker := time.NewTicker(job.Interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					runJob(ctx, job)
+				case <-job.Wake:
+					runJob(ctx, job)
+					ticker.Reset(job.Interval)
+				}
+			}
+		}(job)
+	}
+
+	return scheduler
+}
+
+// Shutdown cancels the context of running jobs and waits for them to return,
+// or until ctx is done.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	s.cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("scheduled jobs still running: %w", ctx.Err())
+	}
+}
+
+// runJob traces each run as its own root span, so the use case and
+// repository spans of background work are grouped per run.
+func runJob(ctx context.Context, job Job) {
+	ctx, span := tracing.Start(ctx, "job "+job.Name, tracing.KindInternal)
+	defer span.End()
+
+	if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+		span.RecordError(err)
+		slog.ErrorContext(ctx, "scheduled job failed", "job", job.Name, "error", err)
+	}
+}
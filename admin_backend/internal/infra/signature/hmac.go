@@ -0,0 +1,30 @@
+// Package signature signs webhook payloads, inbound and outbound alike.
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const prefix = "sha256="
+
+// Sign returns "sha256=" followed by the hex HMAC-SHA256 of payload.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(payload)
+	return prefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether value is the signature of payload, ignoring case
+// and surrounding spaces. An empty secret never verifies.
+func Verify(secret string, payload []byte, value string) bool {
+	if secret == "" {
+		return false
+	}
+
+	expected := Sign(secret, payload)
+	received := strings.ToLower(strings.TrimSpace(value))
+	return hmac.Equal([]byte(expected), []byte(received))
+}
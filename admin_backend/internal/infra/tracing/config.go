@@ -0,0 +1,28 @@
+package tracing
+
+import "strings"
+
+// Config follows the OpenTelemetry environment variables. Tracing is off
+// unless enabled; spans are then exported with OTLP/HTTP (JSON) to Endpoint.
+type Config struct {
+	Enabled     bool
+	Endpoint    string
+	Headers     map[string]string
+	SampleRatio float64
+	ServiceName string
+}
+
+// ParseHeaders reads the "key1=value1,key2=value2" list used by
+// OTEL_EXPORTER_OTLP_HEADERS.
+func ParseHeaders(value string) map[string]string {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		key, headerValue, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		headers[key] = strings.TrimSpace(headerValue)
+	}
+	return headers
+}
@@ -0,0 +1,247 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	queueSize      = 2048
+	maxBatchSize   = 512
+	exportInterval = 5 * time.Second
+)
+
+// exporter batches finished spans and posts them as OTLP/HTTP JSON. Spans
+// are dropped when the queue is full rather than slowing requests down.
+type exporter struct {
+	endpoint   string
+	headers    map[string]string
+	service    string
+	httpClient *http.Client
+
+	queue     chan *Span
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newExporter(config Config, service string) *exporter {
+	e := &exporter{
+		endpoint:   config.Endpoint,
+		headers:    config.Headers,
+		service:    service,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		queue:      make(chan *Span, queueSize),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+func (e *exporter) enqueue(span *Span) {
+	select {
+	case e.queue <- span:
+	default:
+	}
+}
+
+func (e *exporter) run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, maxBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := e.export(ctx, batch); err != nil {
+			slog.Warn("trace export failed", "spans", len(batch), "error", err)
+		}
+		cancel()
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case span := <-e.queue:
+			batch = append(batch, span)
+			if len(batch) >= maxBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.stop:
+			for {
+				select {
+				case span := <-e.queue:
+					batch = append(batch, span)
+					if len(batch) >= maxBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *exporter) close(ctx context.Context) error {
+	e.closeOnce.Do(func() { close(e.stop) })
+	select {
+	case <-e.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *exporter) export(ctx context.Context, spans []*Span) error {
+	body, err := json.Marshal(e.payload(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("collector responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func (e *exporter) payload(spans []*Span) otlpRequest {
+	converted := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		span.mu.Lock()
+		item := otlpSpan{
+			TraceID:           hex.EncodeToString(span.context.traceID[:]),
+			SpanID:            hex.EncodeToString(span.context.spanID[:]),
+			Name:              span.name,
+			Kind:              int(span.kind),
+			StartTimeUnixNano: strconv.FormatInt(span.start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.end.UnixNano(), 10),
+			Status:            otlpStatus{Code: span.statusCode, Message: span.statusMessage},
+		}
+		if span.parentID != [8]byte{} {
+			item.ParentSpanID = hex.EncodeToString(span.parentID[:])
+		}
+		for _, attr := range span.attributes {
+			item.Attributes = append(item.Attributes, otlpAttribute{Key: attr.key, Value: toOTLPValue(attr.value)})
+		}
+		span.mu.Unlock()
+
+		converted = append(converted, item)
+	}
+
+	return otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{Attributes: []otlpAttribute{
+				{Key: "service.name", Value: toOTLPValue(e.service)},
+			}},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "admin_backend/internal/infra/tracing"},
+				Spans: converted,
+			}},
+		}},
+	}
+}
+
+func toOTLPValue(value interface{}) otlpValue {
+	switch typed := value.(type) {
+	case string:
+		return otlpValue{StringValue: &typed}
+	case bool:
+		return otlpValue{BoolValue: &typed}
+	case int:
+		formatted := strconv.FormatInt(int64(typed), 10)
+		return otlpValue{IntValue: &formatted}
+	case int64:
+		formatted := strconv.FormatInt(typed, 10)
+		return otlpValue{IntValue: &formatted}
+	case float64:
+		return otlpValue{DoubleValue: &typed}
+	default:
+		formatted := fmt.Sprint(typed)
+		return otlpValue{StringValue: &formatted}
+	}
+}
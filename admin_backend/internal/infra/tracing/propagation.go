@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+const headerTraceParent = "traceparent"
+
+// Extract continues the W3C trace context carried by an incoming request, so
+// spans started from the returned context join the caller's trace.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	parent, ok := parseTraceParent(header.Get(headerTraceParent))
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, parent)
+}
+
+// parseTraceParent reads "00-<trace-id>-<parent-id>-<flags>".
+func parseTraceParent(value string) (spanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return spanContext{}, false
+	}
+	if parts[0] == "00" && len(parts) != 4 {
+		return spanContext{}, false
+	}
+
+	var parsed spanContext
+	if len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return spanContext{}, false
+	}
+	if _, err := hex.Decode(parsed.traceID[:], []byte(parts[1])); err != nil {
+		return spanContext{}, false
+	}
+	if _, err := hex.Decode(parsed.spanID[:], []byte(parts[2])); err != nil {
+		return spanContext{}, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return spanContext{}, false
+	}
+	parsed.sampled = flags[0]&0x01 == 0x01
+
+	return parsed, parsed.valid()
+}
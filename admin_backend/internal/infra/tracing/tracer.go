@@ -0,0 +1,207 @@
+// Package tracing records OpenTelemetry-compatible spans and exports them
+// with OTLP/HTTP. When tracing is disabled every span is nil and all Span
+// methods are no-ops, so call sites never check whether it is on.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	mathrand "math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"admin_backend/internal/usecase"
+)
+
+// SpanKind values match the OTLP enumeration.
+type SpanKind int
+
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+	KindClient   SpanKind = 3
+)
+
+const (
+	statusError = 2
+)
+
+type spanContext struct {
+	traceID [16]byte
+	spanID  [8]byte
+	sampled bool
+}
+
+func (c spanContext) valid() bool {
+	return c.traceID != [16]byte{} && c.spanID != [8]byte{}
+}
+
+type contextKey struct{}
+
+// Tracer creates spans for one service.
+type Tracer struct {
+	sampleRatio float64
+	exporter    *exporter
+}
+
+var defaultTracer atomic.Pointer[Tracer]
+
+// New builds the tracer described by config. A disabled config yields a
+// tracer that records nothing.
+func New(config Config, service string) *Tracer {
+	if !config.Enabled {
+		return &Tracer{}
+	}
+	if config.ServiceName != "" {
+		service = config.ServiceName
+	}
+
+	return &Tracer{
+		sampleRatio: config.SampleRatio,
+		exporter:    newExporter(config, service),
+	}
+}
+
+// SetDefault makes t the tracer used by Start.
+func SetDefault(t *Tracer) {
+	defaultTracer.Store(t)
+}
+
+// Start opens a span under the span in ctx using the default tracer.
+func Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	t := defaultTracer.Load()
+	if t == nil {
+		return ctx, nil
+	}
+	return t.Start(ctx, name, kind)
+}
+
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	if t == nil || t.exporter == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		tracer: t,
+		name:   name,
+		kind:   kind,
+		start:  time.Now(),
+	}
+	if parent, ok := ctx.Value(contextKey{}).(spanContext); ok && parent.valid() {
+		span.context.traceID = parent.traceID
+		span.context.sampled = parent.sampled
+		span.parentID = parent.spanID
+	} else {
+		_, _ = rand.Read(span.context.traceID[:])
+		span.context.sampled = t.sampleRatio >= 1 || mathrand.Float64() < t.sampleRatio
+	}
+	_, _ = rand.Read(span.context.spanID[:])
+
+	return context.WithValue(ctx, contextKey{}, span.context), span
+}
+
+// Close flushes the spans still queued for export.
+func (t *Tracer) Close(ctx context.Context) error {
+	if t == nil || t.exporter == nil {
+		return nil
+	}
+	return t.exporter.close(ctx)
+}
+
+// TraceID returns the hex trace ID of the span in ctx, or "" when the
+// request is not traced.
+func TraceID(ctx context.Context) string {
+	if current, ok := ctx.Value(contextKey{}).(spanContext); ok && current.valid() && current.sampled {
+		return hex.EncodeToString(current.traceID[:])
+	}
+	return ""
+}
+
+// Span is one timed operation of a trace.
+type Span struct {
+	tracer   *Tracer
+	context  spanContext
+	parentID [8]byte
+	name     string
+	kind     SpanKind
+	start    time.Time
+
+	mu            sync.Mutex
+	end           time.Time
+	ended         bool
+	attributes    []attribute
+	statusCode    int
+	statusMessage string
+}
+
+type attribute struct {
+	key   string
+	value interface{}
+}
+
+// SetAttribute records a string, bool, integer or float attribute.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.attributes = append(s.attributes, attribute{key: key, value: value})
+	s.mu.Unlock()
+}
+
+// RecordError marks the span as failed. Cancelled requests are not errors.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	s.mu.Lock()
+	s.statusCode = statusError
+	s.statusMessage = err.Error()
+	s.mu.Unlock()
+}
+
+// SetError marks the span as failed without an error value, e.g. for a 5xx
+// response.
+func (s *Span) SetError(message string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.statusCode = statusError
+	if s.statusMessage == "" {
+		s.statusMessage = message
+	}
+	s.mu.Unlock()
+}
+
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+
+	if s.context.sampled {
+		s.tracer.exporter.enqueue(s)
+	}
+}
+
+// UsecaseTracer reports use case spans through the default tracer.
+type UsecaseTracer struct{}
+
+func (UsecaseTracer) Start(ctx context.Context, name string) (context.Context, usecase.Span) {
+	ctx, span := Start(ctx, name, KindInternal)
+	if span == nil {
+		return ctx, usecase.NoopSpan
+	}
+	return ctx, span
+}
@@ -0,0 +1,109 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"admin_backend/internal/infra/signature"
+	"admin_backend/internal/usecase"
+)
+
+const maxResponseBodyBytes = 2048
+
+// HTTPSender posts deliveries as JSON. X-Webhook-Timestamp holds the Unix
+// time of the attempt and X-Webhook-Signature holds "sha256=" followed by the
+// hex HMAC-SHA256 of "<timestamp>.<raw body>", computed with the subscription
+// secret. Subscribers should recompute it and reject requests whose timestamp
+// is too old, so a captured delivery cannot be replayed.
+type HTTPSender struct {
+	httpClient *http.Client
+	userAgent  string
+}
+
+type envelope struct {
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Created time.Time       `json:"created"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &HTTPSender{
+		httpClient: &http.Client{
+			Timeout: timeout,
+			// Redirects are not followed so a subscriber cannot bounce the
+			// signed payload to another host.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		userAgent: "shalosh-webhooks/1.0",
+	}
+}
+
+func (s *HTTPSender) Send(ctx context.Context, dispatch usecase.WebhookDispatch) usecase.WebhookSendResult {
+	data := json.RawMessage(dispatch.Payload)
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+
+	body, err := json.Marshal(envelope{
+		ID:      dispatch.EventID,
+		Type:    dispatch.EventType,
+		Created: dispatch.EventCreated.UTC(),
+		Data:    data,
+	})
+	if err != nil {
+		return usecase.WebhookSendResult{Err: err}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dispatch.URL, bytes.NewReader(body))
+	if err != nil {
+		return usecase.WebhookSendResult{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.userAgent)
+	req.Header.Set("X-Webhook-Event", dispatch.EventType)
+	req.Header.Set("X-Webhook-Delivery", dispatch.DeliveryID)
+	req.Header.Set("X-Webhook-Attempt", strconv.Itoa(dispatch.Attempt))
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", Sign(dispatch.Secret, timestamp, body))
+
+	startedAt := time.Now()
+	response, err := s.httpClient.Do(req)
+	duration := time.Since(startedAt)
+	if err != nil {
+		return usecase.WebhookSendResult{Duration: duration, Err: err}
+	}
+	defer response.Body.Close()
+
+	excerpt, _ := io.ReadAll(io.LimitReader(response.Body, maxResponseBodyBytes))
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	return usecase.WebhookSendResult{
+		StatusCode: response.StatusCode,
+		// Postgres rejects invalid UTF-8, e.g. a rune cut by the limit.
+		ResponseBody: strings.ToValidUTF8(string(excerpt), ""),
+		Duration:     duration,
+	}
+}
+
+// Sign returns the X-Webhook-Signature value for body sent with timestamp.
+func Sign(secret, timestamp string, body []byte) string {
+	signed := make([]byte, 0, len(timestamp)+1+len(body))
+	signed = append(signed, timestamp...)
+	signed = append(signed, '.')
+	signed = append(signed, body...)
+	return signature.Sign(secret, signed)
+}
@@ -0,0 +1,80 @@
+package zipcode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"admin_backend/internal/usecase"
+)
+
+// DefaultBrasilAPIURL is the CEP v1 API of BrasilAPI.
+const DefaultBrasilAPIURL = "https://brasilapi.com.br/api/cep/v1"
+
+type BrasilAPIClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewBrasilAPIClient(baseURL string, timeout time.Duration) *BrasilAPIClient {
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = DefaultBrasilAPIURL
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &BrasilAPIClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Lookup answers with the street, neighborhood, city and state only; BrasilAPI
+// has no IBGE, GIA, DDD or SIAFI codes.
+func (c *BrasilAPIClient) Lookup(ctx context.Context, zipCode string) (usecase.ZipCodeLookupResult, error) {
+	requestURL := fmt.Sprintf("%s/%s", strings.TrimSuffix(c.baseURL, "/"), zipCode)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return usecase.ZipCodeLookupResult{}, usecase.ErrZipCodeUnavailable
+	}
+	req.Header.Set("Accept", "application/json")
+
+	response, err := c.httpClient.Do(req)
+	if err != nil {
+		return usecase.ZipCodeLookupResult{}, usecase.ErrZipCodeUnavailable
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return usecase.ZipCodeLookupResult{}, usecase.ErrZipCodeNotFound
+	default:
+		return usecase.ZipCodeLookupResult{}, usecase.ErrZipCodeUnavailable
+	}
+
+	var payload struct {
+		CEP          string `json:"cep"`
+		Street       string `json:"street"`
+		Neighborhood string `json:"neighborhood"`
+		City         string `json:"city"`
+		State        string `json:"state"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&payload); err != nil {
+		return usecase.ZipCodeLookupResult{}, usecase.ErrZipCodeUnavailable
+	}
+
+	return usecase.ZipCodeLookupResult{
+		ZipCode:      onlyDigits(payload.CEP),
+		Street:       strings.TrimSpace(payload.Street),
+		Neighborhood: strings.TrimSpace(payload.Neighborhood),
+		City:         strings.TrimSpace(payload.City),
+		State:        strings.TrimSpace(payload.State),
+	}, nil
+}
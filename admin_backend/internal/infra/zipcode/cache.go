@@ -0,0 +1,167 @@
+package zipcode
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"admin_backend/internal/infra/metrics"
+	"admin_backend/internal/usecase"
+)
+
+// CachedLookup answers from an in-memory LRU, then from the zip code
+// directory in Postgres, and only then from the provider chain, storing its
+// answers for ttl, or notFoundTTL when no provider knew the zip code. When
+// every provider is unavailable an expired answer is better than none.
+type CachedLookup struct {
+	chain       *Chain
+	directory   usecase.ZipCodeDirectoryRepository
+	memory      *lru
+	ttl         time.Duration
+	notFoundTTL time.Duration
+	now         func() time.Time
+}
+
+func NewCachedLookup(
+	chain *Chain,
+	directory usecase.ZipCodeDirectoryRepository,
+	size int,
+	ttl time.Duration,
+	notFoundTTL time.Duration,
+) *CachedLookup {
+	return &CachedLookup{
+		chain:       chain,
+		directory:   directory,
+		memory:      newLRU(size),
+		ttl:         ttl,
+		notFoundTTL: notFoundTTL,
+		now:         time.Now,
+	}
+}
+
+func (c *CachedLookup) Lookup(ctx context.Context, zipCode string) (usecase.ZipCodeLookupResult, error) {
+	now := c.now()
+	if record, ok := c.memory.get(zipCode, now); ok {
+		metrics.ZipCodeLookups.Inc("memory")
+		return answer(record)
+	}
+
+	stored, err := c.directory.GetZipCode(ctx, zipCode)
+	hasStored := err == nil
+	switch {
+	case err == nil && !stored.Expired(now):
+		c.remember(stored, now)
+		metrics.ZipCodeLookups.Inc("database")
+		return answer(stored)
+	case err != nil && !errors.Is(err, usecase.ErrNotFound):
+		slog.WarnContext(ctx, "zipcode directory unavailable", "error", err)
+	}
+
+	result, source, err := c.chain.lookup(ctx, zipCode)
+	if err != nil && !errors.Is(err, usecase.ErrZipCodeNotFound) {
+		if hasStored {
+			metrics.ZipCodeLookups.Inc("stale")
+			return answer(stored)
+		}
+		metrics.ZipCodeLookups.Inc("unavailable")
+		return usecase.ZipCodeLookupResult{}, err
+	}
+	metrics.ZipCodeLookups.Inc(source)
+
+	record := usecase.ZipCodeRecord{Result: result, Source: source, FetchedAt: now}
+	expiresAt := now.Add(c.ttl)
+	if err != nil {
+		record.Result = usecase.ZipCodeLookupResult{}
+		record.NotFound = true
+		expiresAt = now.Add(c.notFoundTTL)
+	}
+	record.Result.ZipCode = zipCode
+	record.ExpiresAt = &expiresAt
+
+	if saveErr := c.directory.SaveZipCode(ctx, record); saveErr != nil {
+		slog.WarnContext(ctx, "store zipcode in directory", "zip_code", zipCode, "error", saveErr)
+	}
+	c.remember(record, now)
+	return answer(record)
+}
+
+// remember keeps record in memory until it expires, and dataset rows for
+// ttl so a re-import is eventually picked up.
+func (c *CachedLookup) remember(record usecase.ZipCodeRecord, now time.Time) {
+	expiresAt := now.Add(c.ttl)
+	if record.ExpiresAt != nil && record.ExpiresAt.Before(expiresAt) {
+		expiresAt = *record.ExpiresAt
+	}
+	c.memory.put(record.Result.ZipCode, record, expiresAt)
+}
+
+func answer(record usecase.ZipCodeRecord) (usecase.ZipCodeLookupResult, error) {
+	if record.NotFound {
+		return usecase.ZipCodeLookupResult{}, usecase.ErrZipCodeNotFound
+	}
+	return record.Result, nil
+}
+
+// lru is a fixed-size cache dropping the least recently used zip code.
+type lru struct {
+	size int
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	record    usecase.ZipCodeRecord
+	expiresAt time.Time
+}
+
+func newLRU(size int) *lru {
+	return &lru{size: size, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+func (l *lru) get(key string, now time.Time) (usecase.ZipCodeRecord, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.entries[key]
+	if !ok {
+		return usecase.ZipCodeRecord{}, false
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expiresAt.After(now) {
+		l.order.Remove(element)
+		delete(l.entries, key)
+		return usecase.ZipCodeRecord{}, false
+	}
+	l.order.MoveToFront(element)
+	return entry.record, true
+}
+
+func (l *lru) put(key string, record usecase.ZipCodeRecord, expiresAt time.Time) {
+	if l.size <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.record = record
+		entry.expiresAt = expiresAt
+		l.order.MoveToFront(element)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, record: record, expiresAt: expiresAt})
+	for l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key)
+	}
+}
@@ -0,0 +1,84 @@
+package zipcode
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"admin_backend/internal/usecase"
+)
+
+// Provider is one online CEP service of a Chain.
+type Provider struct {
+	Name   string
+	Lookup usecase.ZipCodeLookup
+}
+
+// Chain asks its providers in order, each behind a circuit breaker, until
+// one knows the zip code. A provider that does not know it is not trusted
+// blindly, as their databases lag behind the Correios one differently.
+type Chain struct {
+	providers []chainProvider
+	now       func() time.Time
+}
+
+type chainProvider struct {
+	Provider
+	breaker *breaker
+}
+
+// NewChain opens the breaker of a provider after threshold consecutive
+// failures and probes it again after cooldown.
+func NewChain(threshold int, cooldown time.Duration, providers ...Provider) *Chain {
+	chain := &Chain{now: time.Now}
+	for _, provider := range providers {
+		chain.providers = append(chain.providers, chainProvider{
+			Provider: provider,
+			breaker:  newBreaker(threshold, cooldown),
+		})
+	}
+	return chain
+}
+
+func (c *Chain) Lookup(ctx context.Context, zipCode string) (usecase.ZipCodeLookupResult, error) {
+	result, _, err := c.lookup(ctx, zipCode)
+	return result, err
+}
+
+// lookup also names the provider that answered. It returns
+// ErrZipCodeNotFound when no provider knows the zip code and at least one
+// said so, and ErrZipCodeUnavailable when none could answer.
+func (c *Chain) lookup(ctx context.Context, zipCode string) (usecase.ZipCodeLookupResult, string, error) {
+	notFoundBy := ""
+	for _, provider := range c.providers {
+		if ctx.Err() != nil {
+			break
+		}
+		if !provider.breaker.allow(c.now()) {
+			continue
+		}
+
+		result, err := provider.Lookup.Lookup(ctx, zipCode)
+		switch {
+		case err == nil:
+			provider.breaker.success()
+			return result, provider.Name, nil
+		case errors.Is(err, usecase.ErrZipCodeNotFound):
+			provider.breaker.success()
+			if notFoundBy == "" {
+				notFoundBy = provider.Name
+			}
+		case ctx.Err() != nil:
+			provider.breaker.release()
+		default:
+			provider.breaker.failure(c.now())
+			slog.WarnContext(ctx, "zipcode provider unavailable", "provider", provider.Name, "error", err)
+		}
+	}
+
+	if notFoundBy != "" {
+		return usecase.ZipCodeLookupResult{}, notFoundBy, usecase.ErrZipCodeNotFound
+	}
+	return usecase.ZipCodeLookupResult{}, "", usecase.ErrZipCodeUnavailable
+}
@@ -0,0 +1,59 @@
+package zipcode
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"admin_backend/internal/usecase"
+)
+
+const (
+	ViaCEPProviderName    = "viacep"
+	BrasilAPIProviderName = "brasilapi"
+	OpenCEPProviderName   = "opencep"
+)
+
+const defaultTimeout = 3 * time.Second
+
+// ProviderNames lists the providers in their default order.
+var ProviderNames = []string{ViaCEPProviderName, BrasilAPIProviderName, OpenCEPProviderName}
+
+type Config struct {
+	// Providers are asked in this order.
+	Providers    []string
+	ViaCEPURL    string
+	BrasilAPIURL string
+	OpenCEPURL   string
+	// Timeout bounds each provider call.
+	Timeout time.Duration
+
+	CacheSize        int
+	CacheTTL         time.Duration
+	NotFoundTTL      time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// New builds the CEP lookup: the providers selected by ZIPCODE_PROVIDERS
+// chained behind circuit breakers, cached in memory and in directory.
+func New(config Config, directory usecase.ZipCodeDirectoryRepository) (*CachedLookup, error) {
+	var providers []Provider
+	for _, name := range config.Providers {
+		provider := Provider{Name: strings.ToLower(strings.TrimSpace(name))}
+		switch provider.Name {
+		case ViaCEPProviderName:
+			provider.Lookup = NewViaCEPClient(config.ViaCEPURL, config.Timeout)
+		case BrasilAPIProviderName:
+			provider.Lookup = NewBrasilAPIClient(config.BrasilAPIURL, config.Timeout)
+		case OpenCEPProviderName:
+			provider.Lookup = NewOpenCEPClient(config.OpenCEPURL, config.Timeout)
+		default:
+			return nil, fmt.Errorf("unsupported zipcode provider %q", name)
+		}
+		providers = append(providers, provider)
+	}
+
+	chain := NewChain(config.BreakerThreshold, config.BreakerCooldown, providers...)
+	return NewCachedLookup(chain, directory, config.CacheSize, config.CacheTTL, config.NotFoundTTL), nil
+}
@@ -0,0 +1,156 @@
+package zipcode
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"admin_backend/internal/usecase"
+)
+
+const importBatchSize = 1000
+
+// datasetColumns maps the header names found in the usual CEP datasets, in
+// Portuguese or English, to the fields they fill.
+var datasetColumns = map[string]string{
+	"cep":          "zip_code",
+	"zipcode":      "zip_code",
+	"zip_code":     "zip_code",
+	"logradouro":   "street",
+	"endereco":     "street",
+	"street":       "street",
+	"complemento":  "complement",
+	"complement":   "complement",
+	"bairro":       "neighborhood",
+	"neighborhood": "neighborhood",
+	"cidade":       "city",
+	"localidade":   "city",
+	"municipio":    "city",
+	"city":         "city",
+	"uf":           "state",
+	"estado":       "state",
+	"state":        "state",
+	"ibge":         "ibge",
+	"codigo_ibge":  "ibge",
+	"ibge_code":    "ibge",
+	"gia":          "gia",
+	"ddd":          "ddd",
+	"siafi":        "siafi",
+}
+
+// ImportSummary counts the rows of an imported dataset.
+type ImportSummary struct {
+	Imported int
+	Skipped  int
+}
+
+// ImportDataset stores every row of a CSV CEP dataset in directory so
+// lookups of those zip codes work without the online providers. The first
+// line is the header; columns are separated by commas or semicolons and
+// only the cep column is required. Rows with an invalid zip code are
+// skipped.
+func ImportDataset(
+	ctx context.Context,
+	directory usecase.ZipCodeDirectoryRepository,
+	reader io.Reader,
+) (ImportSummary, error) {
+	buffered := bufio.NewReader(reader)
+	header, err := buffered.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return ImportSummary{}, err
+	}
+	header = strings.TrimPrefix(header, "\ufeff")
+
+	records := csv.NewReader(io.MultiReader(strings.NewReader(header), buffered))
+	if strings.Count(header, ";") > strings.Count(header, ",") {
+		records.Comma = ';'
+	}
+	records.FieldsPerRecord = -1
+	records.ReuseRecord = true
+
+	names, err := records.Read()
+	if err != nil {
+		return ImportSummary{}, fmt.Errorf("read dataset header: %w", err)
+	}
+	columns := map[string]int{}
+	for index, name := range names {
+		if field, ok := datasetColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = index
+			}
+		}
+	}
+	if _, ok := columns["zip_code"]; !ok {
+		return ImportSummary{}, errors.New("dataset header has no cep column")
+	}
+
+	summary := ImportSummary{}
+	now := time.Now()
+	batch := make([]usecase.ZipCodeRecord, 0, importBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := directory.ImportZipCodes(ctx, batch); err != nil {
+			return err
+		}
+		summary.Imported += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		row, err := records.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return summary, fmt.Errorf("read dataset: %w", err)
+		}
+
+		field := func(name string) string {
+			index, ok := columns[name]
+			if !ok || index >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[index])
+		}
+		zipCode := onlyDigits(field("zip_code"))
+		if len(zipCode) != 8 {
+			summary.Skipped++
+			continue
+		}
+
+		batch = append(batch, usecase.ZipCodeRecord{
+			Result: usecase.ZipCodeLookupResult{
+				ZipCode:      zipCode,
+				Street:       field("street"),
+				Complement:   field("complement"),
+				Neighborhood: field("neighborhood"),
+				City:         field("city"),
+				State:        strings.ToUpper(field("state")),
+				IBGE:         field("ibge"),
+				GIA:          field("gia"),
+				DDD:          field("ddd"),
+				SIAFI:        field("siafi"),
+			},
+			Source:    usecase.ZipCodeSourceDataset,
+			FetchedAt: now,
+		})
+		if len(batch) == importBatchSize {
+			if err := flush(); err != nil {
+				return summary, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return summary, err
+	}
+	return summary, nil
+}
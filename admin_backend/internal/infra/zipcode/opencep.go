@@ -0,0 +1,87 @@
+package zipcode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"admin_backend/internal/usecase"
+)
+
+// DefaultOpenCEPURL is the public OpenCEP API.
+const DefaultOpenCEPURL = "https://opencep.com/v1"
+
+type OpenCEPClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewOpenCEPClient(baseURL string, timeout time.Duration) *OpenCEPClient {
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = DefaultOpenCEPURL
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &OpenCEPClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+func (c *OpenCEPClient) Lookup(ctx context.Context, zipCode string) (usecase.ZipCodeLookupResult, error) {
+	requestURL := fmt.Sprintf("%s/%s", strings.TrimSuffix(c.baseURL, "/"), zipCode)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return usecase.ZipCodeLookupResult{}, usecase.ErrZipCodeUnavailable
+	}
+	req.Header.Set("Accept", "application/json")
+
+	response, err := c.httpClient.Do(req)
+	if err != nil {
+		return usecase.ZipCodeLookupResult{}, usecase.ErrZipCodeUnavailable
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return usecase.ZipCodeLookupResult{}, usecase.ErrZipCodeNotFound
+	default:
+		return usecase.ZipCodeLookupResult{}, usecase.ErrZipCodeUnavailable
+	}
+
+	var payload struct {
+		CEP          string `json:"cep"`
+		Street       string `json:"logradouro"`
+		Complement   string `json:"complemento"`
+		Neighborhood string `json:"bairro"`
+		City         string `json:"localidade"`
+		State        string `json:"uf"`
+		IBGE         string `json:"ibge"`
+		NotFound     bool   `json:"erro"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&payload); err != nil {
+		return usecase.ZipCodeLookupResult{}, usecase.ErrZipCodeUnavailable
+	}
+
+	if payload.NotFound {
+		return usecase.ZipCodeLookupResult{}, usecase.ErrZipCodeNotFound
+	}
+
+	return usecase.ZipCodeLookupResult{
+		ZipCode:      onlyDigits(payload.CEP),
+		Street:       strings.TrimSpace(payload.Street),
+		Complement:   strings.TrimSpace(payload.Complement),
+		Neighborhood: strings.TrimSpace(payload.Neighborhood),
+		City:         strings.TrimSpace(payload.City),
+		State:        strings.TrimSpace(payload.State),
+		IBGE:         strings.TrimSpace(payload.IBGE),
+	}, nil
+}
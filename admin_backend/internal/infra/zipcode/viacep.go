@@ -14,18 +14,24 @@ import (
 
 var nonDigitPattern = regexp.MustCompile(`\D`)
 
+// DefaultViaCEPURL is the public ViaCEP API.
+const DefaultViaCEPURL = "https://viacep.com.br/ws"
+
 type ViaCEPClient struct {
 	baseURL    string
 	httpClient *http.Client
 }
 
-func NewViaCEPClient(timeout time.Duration) *ViaCEPClient {
+func NewViaCEPClient(baseURL string, timeout time.Duration) *ViaCEPClient {
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = DefaultViaCEPURL
+	}
 	if timeout <= 0 {
-		timeout = 8 * time.Second
+		timeout = defaultTimeout
 	}
 
 	return &ViaCEPClient{
-		baseURL: "https://viacep.com.br/ws",
+		baseURL: baseURL,
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
@@ -38,6 +44,7 @@ func (c *ViaCEPClient) Lookup(ctx context.Context, zipCode string) (usecase.ZipC
 	if err != nil {
 		return usecase.ZipCodeLookupResult{}, usecase.ErrZipCodeUnavailable
 	}
+	req.Header.Set("Accept", "application/json")
 
 	response, err := c.httpClient.Do(req)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
@@ -65,6 +66,7 @@ func (h *Handler) HandleAccount(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, usecase.ErrConflict):
 			h.respondError(w, http.StatusConflict, "conflict")
 		default:
+			requestlog.RecordError(w, err)
 			h.respondError(w, http.StatusInternalServerError, "unexpected error")
 		}
 		return
@@ -72,6 +74,7 @@ func (h *Handler) HandleAccount(w http.ResponseWriter, r *http.Request) {
 
 	token, expiresAt, err := h.tokenManager.Generate(user.ID, user.Login, user.Name, time.Now())
 	if err != nil {
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 		return
 	}
@@ -6,6 +6,9 @@ import (
 	"net/http"
 	"time"
 
+	infraauth "admin_backend/internal/infra/auth"
+	"admin_backend/internal/infra/metrics"
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
@@ -25,24 +28,28 @@ func (h *Handler) HandleLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	user, err := h.authService.Authenticate(r.Context(), payload.Login, payload.Password)
-		if err != nil {
-			switch {
-			case errors.Is(err, usecase.ErrInvalidInput):
-				h.respondError(w, http.StatusBadRequest, "login and password are required")
-			case errors.Is(err, usecase.ErrInvalidCredentials):
-				h.respondError(w, http.StatusUnauthorized, "credenciais inválidas")
-			default:
-				h.respondError(w, http.StatusInternalServerError, "unexpected error")
-			}
-			return
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidInput):
+			h.respondError(w, http.StatusBadRequest, "login and password are required")
+		case errors.Is(err, usecase.ErrInvalidCredentials):
+			metrics.LoginAttempts.Inc(infraauth.AudienceAdmin, metrics.LoginFailure)
+			h.respondError(w, http.StatusUnauthorized, "credenciais inválidas")
+		default:
+			requestlog.RecordError(w, err)
+			h.respondError(w, http.StatusInternalServerError, "unexpected error")
+		}
+		return
 	}
 
 	token, expiresAt, err := h.tokenManager.Generate(user.ID, user.Login, user.Name, time.Now())
 	if err != nil {
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 		return
 	}
 
+	metrics.LoginAttempts.Inc(infraauth.AudienceAdmin, metrics.LoginSuccess)
 	h.respondJSON(w, http.StatusOK, map[string]interface{}{
 		"token":     token,
 		"tokenType": "Bearer",
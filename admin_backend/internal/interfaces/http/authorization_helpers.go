@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"admin_backend/internal/infra/auth"
+	"admin_backend/internal/infra/logging"
 )
 
 func (h *UserHandler) isUserAdministrator(ctx context.Context, userID string) (bool, error) {
@@ -33,5 +34,11 @@ func (h *UserHandler) authorizeRequest(r *http.Request) (auth.Claims, error) {
 		return auth.Claims{}, errors.New("missing bearer token")
 	}
 
-	return h.tokenManager.ParseAndValidate(token, time.Now())
+	claims, err := h.tokenManager.ParseAndValidate(token, time.Now())
+	if err != nil {
+		return auth.Claims{}, err
+	}
+
+	logging.SetUserID(r.Context(), claims.Sub)
+	return claims, nil
 }
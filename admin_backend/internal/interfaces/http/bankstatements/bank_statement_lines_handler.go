@@ -0,0 +1,106 @@
+package bankstatements
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) HandleBankStatementLines(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.authorizeWithPermission(w, r, permissionBankStatementsRead); !ok {
+		return
+	}
+
+	options, ok := h.parseMatchOptions(w, r)
+	if !ok {
+		return
+	}
+
+	lines, err := h.bankStatementService.ListPendingBankStatementLines(r.Context(), options)
+	if err != nil {
+		h.handleBankStatementUsecaseError(w, err, "invalid windowDays", "bank statement line not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, lines)
+}
+
+func (h *Handler) HandleBankStatementLineRoutes(w http.ResponseWriter, r *http.Request) {
+	trimmedPath := strings.Trim(strings.TrimPrefix(r.URL.Path, "/bank-statement-lines/"), "/")
+	segments := strings.Split(trimmedPath, "/")
+	if len(segments) != 2 || strings.TrimSpace(segments[0]) == "" {
+		h.respondError(w, http.StatusNotFound, "route not found")
+		return
+	}
+
+	lineID := strings.TrimSpace(segments[0])
+	switch strings.ToLower(strings.TrimSpace(segments[1])) {
+	case "confirm":
+		h.handleBankStatementLineConfirm(w, r, lineID)
+	case "ignore":
+		h.handleBankStatementLineIgnore(w, r, lineID)
+	default:
+		h.respondError(w, http.StatusNotFound, "route not found")
+	}
+}
+
+func (h *Handler) handleBankStatementLineConfirm(w http.ResponseWriter, r *http.Request, lineID string) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, ok := h.authorizeWithPermission(w, r, permissionBankStatementsUpdate)
+	if !ok {
+		return
+	}
+
+	var payload struct {
+		SourceType string `json:"sourceType"`
+		SourceID   string `json:"sourceId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	line, err := h.bankStatementService.ConfirmBankStatementLine(r.Context(), usecase.ConfirmBankStatementLineInput{
+		LineID:      lineID,
+		SourceType:  payload.SourceType,
+		SourceID:    payload.SourceID,
+		ConfirmedBy: claims.Sub,
+	})
+	if err != nil {
+		h.handleBankStatementUsecaseError(w, err, "sourceType must be revenue or monthly_charge and sourceId is required", "bank statement line not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, line)
+}
+
+func (h *Handler) handleBankStatementLineIgnore(w http.ResponseWriter, r *http.Request, lineID string) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, ok := h.authorizeWithPermission(w, r, permissionBankStatementsUpdate)
+	if !ok {
+		return
+	}
+
+	line, err := h.bankStatementService.IgnoreBankStatementLine(r.Context(), lineID, claims.Sub)
+	if err != nil {
+		h.handleBankStatementUsecaseError(w, err, "", "bank statement line not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, line)
+}
@@ -0,0 +1,138 @@
+package bankstatements
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) HandleBankStatements(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := h.authorizeWithPermission(w, r, permissionBankStatementsRead); !ok {
+			return
+		}
+
+		statements, err := h.bankStatementService.ListBankStatements(r.Context())
+		if err != nil {
+			h.handleBankStatementUsecaseError(w, err, "", "bank statement not found")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, statements)
+	case http.MethodPost:
+		claims, ok := h.authorizeWithPermission(w, r, permissionBankStatementsCreate)
+		if !ok {
+			return
+		}
+
+		options, ok := h.parseMatchOptions(w, r)
+		if !ok {
+			return
+		}
+
+		var payload struct {
+			Format   string `json:"format"`
+			FileName string `json:"fileName"`
+			Account  string `json:"account"`
+			Content  string `json:"content"`
+			CSV      struct {
+				Delimiter           string `json:"delimiter"`
+				HasHeader           *bool  `json:"hasHeader"`
+				DateColumn          string `json:"dateColumn"`
+				DateFormat          string `json:"dateFormat"`
+				AmountColumn        string `json:"amountColumn"`
+				DescriptionColumn   string `json:"descriptionColumn"`
+				PayerNameColumn     string `json:"payerNameColumn"`
+				PayerDocumentColumn string `json:"payerDocumentColumn"`
+				IDColumn            string `json:"idColumn"`
+			} `json:"csv"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		detail, err := h.bankStatementService.ImportBankStatement(
+			r.Context(),
+			usecase.ImportBankStatementInput{
+				Format:   payload.Format,
+				FileName: payload.FileName,
+				Account:  payload.Account,
+				Content:  payload.Content,
+				CSV: usecase.CSVStatementLayout{
+					Delimiter:           payload.CSV.Delimiter,
+					HasHeader:           payload.CSV.HasHeader,
+					DateColumn:          payload.CSV.DateColumn,
+					DateFormat:          payload.CSV.DateFormat,
+					AmountColumn:        payload.CSV.AmountColumn,
+					DescriptionColumn:   payload.CSV.DescriptionColumn,
+					PayerNameColumn:     payload.CSV.PayerNameColumn,
+					PayerDocumentColumn: payload.CSV.PayerDocumentColumn,
+					IDColumn:            payload.CSV.IDColumn,
+				},
+				ImportedBy: claims.Sub,
+			},
+			options,
+		)
+		if err != nil {
+			h.handleBankStatementUsecaseError(w, err, "content is required, format must be ofx or csv and csv columns must exist", "bank statement not found")
+			return
+		}
+
+		h.respondJSON(w, http.StatusCreated, detail)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) HandleBankStatementByID(w http.ResponseWriter, r *http.Request) {
+	importID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/bank-statements/"), "/")
+	if importID == "" || strings.Contains(importID, "/") {
+		h.respondError(w, http.StatusNotFound, "bank statement not found")
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.authorizeWithPermission(w, r, permissionBankStatementsRead); !ok {
+		return
+	}
+
+	options, ok := h.parseMatchOptions(w, r)
+	if !ok {
+		return
+	}
+
+	detail, err := h.bankStatementService.GetBankStatement(r.Context(), importID, options)
+	if err != nil {
+		h.handleBankStatementUsecaseError(w, err, "invalid windowDays", "bank statement not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, detail)
+}
+
+func (h *Handler) parseMatchOptions(
+	w http.ResponseWriter,
+	r *http.Request,
+) (usecase.BankStatementMatchOptions, bool) {
+	raw := strings.TrimSpace(r.URL.Query().Get("windowDays"))
+	if raw == "" {
+		return usecase.BankStatementMatchOptions{}, true
+	}
+
+	windowDays, err := strconv.Atoi(raw)
+	if err != nil || windowDays < 1 {
+		h.respondError(w, http.StatusBadRequest, "invalid windowDays")
+		return usecase.BankStatementMatchOptions{}, false
+	}
+
+	return usecase.BankStatementMatchOptions{WindowDays: windowDays}, true
+}
@@ -0,0 +1,39 @@
+package bankstatements
+
+import (
+	"context"
+	"net/http"
+
+	infraauth "admin_backend/internal/infra/auth"
+	"admin_backend/internal/usecase"
+)
+
+type Handler struct {
+	bankStatementService *usecase.BankStatementService
+	authorizeRequest     func(r *http.Request) (infraauth.Claims, error)
+	hasUserPermission    func(ctx context.Context, userID, permissionCode string) (bool, error)
+	respondJSON          func(w http.ResponseWriter, status int, payload interface{})
+	respondError         func(w http.ResponseWriter, status int, message string)
+}
+
+func NewHandler(
+	bankStatementService *usecase.BankStatementService,
+	authorizeRequest func(r *http.Request) (infraauth.Claims, error),
+	hasUserPermission func(ctx context.Context, userID, permissionCode string) (bool, error),
+	respondJSON func(w http.ResponseWriter, status int, payload interface{}),
+	respondError func(w http.ResponseWriter, status int, message string),
+) *Handler {
+	return &Handler{
+		bankStatementService: bankStatementService,
+		authorizeRequest:     authorizeRequest,
+		hasUserPermission:    hasUserPermission,
+		respondJSON:          respondJSON,
+		respondError:         respondError,
+	}
+}
+
+const (
+	permissionBankStatementsRead   = "bank_statements.read"
+	permissionBankStatementsCreate = "bank_statements.create"
+	permissionBankStatementsUpdate = "bank_statements.update"
+)
@@ -0,0 +1,38 @@
+package bankstatements
+
+import (
+	"errors"
+	"net/http"
+
+	"admin_backend/internal/interfaces/http/requestlog"
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) handleBankStatementUsecaseError(
+	w http.ResponseWriter,
+	err error,
+	defaultInvalidInputMessage string,
+	notFoundMessage string,
+) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInput):
+		message := defaultInvalidInputMessage
+		if message == "" {
+			message = "invalid input"
+		}
+		h.respondError(w, http.StatusBadRequest, message)
+	case errors.Is(err, usecase.ErrBankStatementInvalid):
+		h.respondError(w, http.StatusUnprocessableEntity, "bank statement could not be parsed")
+	case errors.Is(err, usecase.ErrNotFound):
+		h.respondError(w, http.StatusNotFound, notFoundMessage)
+	case errors.Is(err, usecase.ErrUserNotFound):
+		h.respondError(w, http.StatusBadRequest, "user not found")
+	case errors.Is(err, usecase.ErrBankStatementLineResolved):
+		h.respondError(w, http.StatusConflict, "bank statement line already resolved")
+	case errors.Is(err, usecase.ErrBankStatementSourceNotPending):
+		h.respondError(w, http.StatusConflict, "matched revenue or charge is not pending")
+	default:
+		requestlog.RecordError(w, err)
+		h.respondError(w, http.StatusInternalServerError, "unexpected error")
+	}
+}
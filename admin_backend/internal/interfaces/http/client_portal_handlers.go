@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+
+	"admin_backend/internal/infra/auth"
+	clientportalhttp "admin_backend/internal/interfaces/http/clientportal"
+	"admin_backend/internal/usecase"
+)
+
+// ClientPortalHandler serves the client portal API (/client-auth/* and
+// /client/*). It is mounted by client_backend, not by the admin API.
+type ClientPortalHandler struct {
+	portalHandler *clientportalhttp.Handler
+}
+
+func NewClientPortalHandler(
+	clientPortalService *usecase.ClientPortalService,
+	projectService *usecase.ProjectService,
+	reminderService *usecase.PaymentReminderService,
+	notificationService *usecase.NotificationService,
+	surveyService *usecase.SurveyService,
+	clientOrganizationService *usecase.ClientOrganizationService,
+	realtimeEvents usecase.RealtimeEventSource,
+	tokenManager *auth.TokenManager,
+) *ClientPortalHandler {
+	return &ClientPortalHandler{
+		portalHandler: clientportalhttp.NewHandler(
+			clientPortalService,
+			projectService,
+			reminderService,
+			notificationService,
+			surveyService,
+			clientOrganizationService,
+			realtimeEvents,
+			tokenManager,
+			normalizeAvatarInput,
+			respondJSON,
+			respondError,
+		),
+	}
+}
+
+func (h *ClientPortalHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/client-auth/login", h.portalHandler.HandleClientLogin)
+	mux.HandleFunc("/client-auth/register", h.portalHandler.HandleClientRegister)
+	mux.HandleFunc("/client-auth/account", h.portalHandler.HandleClientAccount)
+	mux.HandleFunc("/client-auth/invites/accept", h.portalHandler.HandleClientInviteAcceptance)
+	mux.HandleFunc("/client/dashboard", h.portalHandler.HandleClientDashboard)
+	mux.HandleFunc("/client/projects", h.portalHandler.HandleClientProjects)
+	mux.HandleFunc("/client/projects/", h.portalHandler.HandleClientProjectRoutes)
+	mux.HandleFunc("/client/payments", h.portalHandler.HandleClientPayments)
+	mux.HandleFunc("/client/service-requests", h.portalHandler.HandleClientServiceRequests)
+	mux.HandleFunc("/client/service-requests/", h.portalHandler.HandleClientServiceRequests)
+	mux.HandleFunc("/client/events/stream", h.portalHandler.HandleClientEventStream)
+	mux.HandleFunc("/client/notifications", h.portalHandler.HandleClientNotifications)
+	mux.HandleFunc("/client/notifications/", h.portalHandler.HandleClientNotifications)
+	mux.HandleFunc("/client/surveys", h.portalHandler.HandleClientSurveys)
+	mux.HandleFunc("/client/surveys/", h.portalHandler.HandleClientSurveys)
+	mux.HandleFunc("/client/organization", h.portalHandler.HandleClientOrganization)
+	mux.HandleFunc("/client/organization/", h.portalHandler.HandleClientOrganization)
+}
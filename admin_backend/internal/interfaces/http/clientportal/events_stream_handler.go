@@ -0,0 +1,46 @@
+package clientportal
+
+import (
+	"net/http"
+	"time"
+
+	"admin_backend/internal/interfaces/http/sse"
+	"admin_backend/internal/usecase"
+)
+
+const clientProjectsRefreshInterval = time.Minute
+
+// HandleClientEventStream pushes the events of the client's projects,
+// service requests and notifications as Server-Sent Events. The project list
+// is reloaded periodically so links added while connected take effect.
+func (h *Handler) HandleClientEventStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	client, ok := h.authorizeClient(w, sse.WithQueryToken(r))
+	if !ok {
+		return
+	}
+
+	projects, err := h.clientPortalService.ListProjects(r.Context(), client.ID)
+	if err != nil {
+		h.handlePortalUsecaseError(w, err, "")
+		return
+	}
+
+	subscription := usecase.NewClientRealtimeSubscription(client.ID, projects)
+	refreshedAt := time.Now()
+	allows := func(event usecase.RealtimeEvent) bool {
+		if time.Since(refreshedAt) >= clientProjectsRefreshInterval {
+			if projects, err := h.clientPortalService.ListProjects(r.Context(), client.ID); err == nil {
+				subscription = usecase.NewClientRealtimeSubscription(client.ID, projects)
+			}
+			refreshedAt = time.Now()
+		}
+		return subscription.Allows(event)
+	}
+
+	sse.Stream(w, r, h.realtimeEvents, allows)
+}
@@ -4,18 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"sort"
 	"strings"
 	"time"
 
 	infraauth "admin_backend/internal/infra/auth"
+	"admin_backend/internal/infra/logging"
+	"admin_backend/internal/infra/metrics"
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
 type Handler struct {
 	clientPortalService  *usecase.ClientPortalService
 	projectService       *usecase.ProjectService
+	reminderService      *usecase.PaymentReminderService
+	notificationService  *usecase.NotificationService
+	surveyService        *usecase.SurveyService
+	organizationService  *usecase.ClientOrganizationService
+	realtimeEvents       usecase.RealtimeEventSource
 	tokenManager         *infraauth.TokenManager
 	normalizeAvatarInput func(value string) (string, error)
 	respondJSON          func(w http.ResponseWriter, status int, payload interface{})
@@ -25,6 +34,11 @@ type Handler struct {
 func NewHandler(
 	clientPortalService *usecase.ClientPortalService,
 	projectService *usecase.ProjectService,
+	reminderService *usecase.PaymentReminderService,
+	notificationService *usecase.NotificationService,
+	surveyService *usecase.SurveyService,
+	organizationService *usecase.ClientOrganizationService,
+	realtimeEvents usecase.RealtimeEventSource,
 	tokenManager *infraauth.TokenManager,
 	normalizeAvatarInput func(value string) (string, error),
 	respondJSON func(w http.ResponseWriter, status int, payload interface{}),
@@ -33,6 +47,11 @@ func NewHandler(
 	return &Handler{
 		clientPortalService:  clientPortalService,
 		projectService:       projectService,
+		reminderService:      reminderService,
+		notificationService:  notificationService,
+		surveyService:        surveyService,
+		organizationService:  organizationService,
+		realtimeEvents:       realtimeEvents,
 		tokenManager:         tokenManager,
 		normalizeAvatarInput: normalizeAvatarInput,
 		respondJSON:          respondJSON,
@@ -40,6 +59,13 @@ func NewHandler(
 	}
 }
 
+// clientAccountResponse adds the per-project payment reminder preferences to
+// the account payload.
+type clientAccountResponse struct {
+	usecase.ClientPortalAccount
+	PaymentReminders []usecase.ClientPaymentReminderPreference `json:"paymentReminders"`
+}
+
 type relatedFilePayload struct {
 	FileName    string `json:"fileName"`
 	FileKey     string `json:"fileKey"`
@@ -68,6 +94,9 @@ func (h *Handler) HandleClientLogin(w http.ResponseWriter, r *http.Request) {
 		payload.Password,
 	)
 	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidCredentials) {
+			metrics.LoginAttempts.Inc(infraauth.AudienceClient, metrics.LoginFailure)
+		}
 		h.handlePortalUsecaseError(w, err, "login and password are required")
 		return
 	}
@@ -79,10 +108,12 @@ func (h *Handler) HandleClientLogin(w http.ResponseWriter, r *http.Request) {
 		time.Now(),
 	)
 	if err != nil {
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 		return
 	}
 
+	metrics.LoginAttempts.Inc(infraauth.AudienceClient, metrics.LoginSuccess)
 	h.respondJSON(w, http.StatusOK, map[string]interface{}{
 		"token":     token,
 		"tokenType": "Bearer",
@@ -94,6 +125,9 @@ func (h *Handler) HandleClientLogin(w http.ResponseWriter, r *http.Request) {
 			"login":  client.Login,
 			"avatar": client.Avatar,
 			"active": client.Active,
+
+			"organizationId": client.OrganizationID,
+			"role":           client.Role,
 		},
 	})
 }
@@ -144,6 +178,7 @@ func (h *Handler) HandleClientRegister(w http.ResponseWriter, r *http.Request) {
 		time.Now(),
 	)
 	if err != nil {
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 		return
 	}
@@ -169,7 +204,17 @@ func (h *Handler) HandleClientAccount(w http.ResponseWriter, r *http.Request) {
 			h.handlePortalUsecaseError(w, err, "")
 			return
 		}
-		h.respondJSON(w, http.StatusOK, account)
+
+		reminders, err := h.reminderService.ListClientPreferences(r.Context(), client.ID)
+		if err != nil {
+			h.handlePortalUsecaseError(w, err, "")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, clientAccountResponse{
+			ClientPortalAccount: account,
+			PaymentReminders:    reminders,
+		})
 	case http.MethodPatch:
 		var payload struct {
 			Name     string `json:"name"`
@@ -177,6 +222,11 @@ func (h *Handler) HandleClientAccount(w http.ResponseWriter, r *http.Request) {
 			Login    string `json:"login"`
 			Password string `json:"password"`
 			Avatar   string `json:"avatar"`
+
+			PaymentReminders []struct {
+				ProjectID        string `json:"projectId"`
+				RemindersEnabled bool   `json:"remindersEnabled"`
+			} `json:"paymentReminders"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 			h.respondError(w, http.StatusBadRequest, "invalid json")
@@ -205,6 +255,19 @@ func (h *Handler) HandleClientAccount(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		preferences := make([]usecase.ClientPaymentReminderPreference, 0, len(payload.PaymentReminders))
+		for _, preference := range payload.PaymentReminders {
+			preferences = append(preferences, usecase.ClientPaymentReminderPreference{
+				ProjectID:        preference.ProjectID,
+				RemindersEnabled: preference.RemindersEnabled,
+			})
+		}
+		reminders, err := h.reminderService.UpdateClientPreferences(r.Context(), client.ID, preferences)
+		if err != nil {
+			h.handlePortalUsecaseError(w, err, "paymentReminders entries require projectId")
+			return
+		}
+
 		token, expiresAt, err := h.tokenManager.Generate(
 			account.ID,
 			account.Login,
@@ -212,6 +275,7 @@ func (h *Handler) HandleClientAccount(w http.ResponseWriter, r *http.Request) {
 			time.Now(),
 		)
 		if err != nil {
+			requestlog.RecordError(w, err)
 			h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			return
 		}
@@ -220,7 +284,10 @@ func (h *Handler) HandleClientAccount(w http.ResponseWriter, r *http.Request) {
 			"token":     token,
 			"tokenType": "Bearer",
 			"expiresAt": expiresAt.UTC().Format(time.RFC3339),
-			"client":    account,
+			"client": clientAccountResponse{
+				ClientPortalAccount: account,
+				PaymentReminders:    reminders,
+			},
 		})
 	default:
 		w.WriteHeader(http.StatusMethodNotAllowed)
@@ -246,6 +313,7 @@ func (h *Handler) HandleClientDashboard(w http.ResponseWriter, r *http.Request)
 
 	openRequests, err := h.clientPortalService.CountOpenRequests(r.Context(), client.ID)
 	if err != nil {
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 		return
 	}
@@ -259,6 +327,10 @@ func (h *Handler) HandleClientDashboard(w http.ResponseWriter, r *http.Request)
 
 	financialTotals := totals{}
 	for _, project := range projects {
+		if !client.CanViewFinancials() {
+			break
+		}
+
 		detail, detailErr := h.projectService.GetProjectDetail(r.Context(), project.ID)
 		if detailErr != nil {
 			continue
@@ -299,6 +371,9 @@ func (h *Handler) HandleClientDashboard(w http.ResponseWriter, r *http.Request)
 			"email":  client.Email,
 			"login":  client.Login,
 			"avatar": client.Avatar,
+
+			"organizationId": client.OrganizationID,
+			"role":           client.Role,
 		},
 		"summary": map[string]interface{}{
 			"totalProjects":        len(projects),
@@ -378,6 +453,9 @@ func (h *Handler) HandleClientProjectRoutes(w http.ResponseWriter, r *http.Reque
 			h.handleProjectUsecaseError(w, err)
 			return
 		}
+		if !client.CanViewFinancials() {
+			hideProjectFinancials(&project)
+		}
 
 		h.respondJSON(w, http.StatusOK, project)
 		return
@@ -394,6 +472,9 @@ func (h *Handler) HandleClientProjectRoutes(w http.ResponseWriter, r *http.Reque
 			h.handleProjectUsecaseError(w, err)
 			return
 		}
+		if !client.CanViewFinancials() {
+			hideProjectFinancials(&exportPayload.Project)
+		}
 
 		h.respondJSON(w, http.StatusOK, exportPayload)
 		return
@@ -479,6 +560,10 @@ func (h *Handler) HandleClientPayments(w http.ResponseWriter, r *http.Request) {
 	if !ok {
 		return
 	}
+	if !client.CanViewFinancials() {
+		h.respondError(w, http.StatusForbidden, "forbidden")
+		return
+	}
 
 	projects, err := h.clientPortalService.ListProjects(r.Context(), client.ID)
 	if err != nil {
@@ -741,6 +826,50 @@ func (h *Handler) HandleClientServiceRequests(w http.ResponseWriter, r *http.Req
 			return
 		}
 
+		if len(segments) == 2 && strings.EqualFold(strings.TrimSpace(segments[1]), "reopen") {
+			requestID := strings.TrimSpace(segments[0])
+			if requestID == "" {
+				h.respondError(w, http.StatusNotFound, "service request not found")
+				return
+			}
+
+			if r.Method != http.MethodPost {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+
+			var payload struct {
+				Comment string `json:"comment"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+				h.respondError(w, http.StatusBadRequest, "invalid json")
+				return
+			}
+
+			request, err := h.clientPortalService.ReopenServiceRequest(
+				r.Context(),
+				usecase.ReopenClientServiceRequestInput{
+					ClientID:  client.ID,
+					RequestID: requestID,
+					Comment:   payload.Comment,
+				},
+			)
+			if err != nil {
+				switch {
+				case errors.Is(err, usecase.ErrServiceRequestReopenExpired):
+					h.respondError(w, http.StatusConflict, "the reopen window has expired")
+				case errors.Is(err, usecase.ErrConflict):
+					h.respondError(w, http.StatusConflict, "this request cannot be reopened")
+				default:
+					h.handlePortalUsecaseError(w, err, "")
+				}
+				return
+			}
+
+			h.respondJSON(w, http.StatusOK, request)
+			return
+		}
+
 		if len(segments) != 1 {
 			h.respondError(w, http.StatusNotFound, "route not found")
 			return
@@ -867,10 +996,12 @@ func (h *Handler) authorizeClient(
 			h.respondError(w, http.StatusUnauthorized, "unauthorized")
 			return usecase.ClientPortalAuthUser{}, false
 		}
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 		return usecase.ClientPortalAuthUser{}, false
 	}
 
+	logging.SetClientID(r.Context(), client.ID)
 	return client, true
 }
 
@@ -897,6 +1028,7 @@ func (h *Handler) handlePortalUsecaseError(
 	case errors.Is(err, usecase.ErrClientEmailInUse):
 		h.respondError(w, http.StatusConflict, "client email already in use")
 	default:
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 	}
 }
@@ -908,10 +1040,18 @@ func (h *Handler) handleProjectUsecaseError(w http.ResponseWriter, err error) {
 	case errors.Is(err, usecase.ErrNotFound):
 		h.respondError(w, http.StatusNotFound, "project not found")
 	default:
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 	}
 }
 
+// hideProjectFinancials removes the revenues and charges from projects
+// shown to portal users without a financial role.
+func hideProjectFinancials(project *usecase.ProjectDetail) {
+	project.Revenues = []usecase.ProjectRevenue{}
+	project.MonthlyCharges = []usecase.ProjectMonthlyCharge{}
+}
+
 func mapRelatedFilePayloads(files []relatedFilePayload) []usecase.CreateProjectFileInput {
 	mapped := make([]usecase.CreateProjectFileInput, 0, len(files))
 	for _, file := range files {
@@ -0,0 +1,94 @@
+package clientportal
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"admin_backend/internal/interfaces/http/requestlog"
+	"admin_backend/internal/usecase"
+)
+
+// HandleClientNotifications serves the portal notification center:
+// GET /client/notifications, POST /client/notifications/read-all and
+// POST /client/notifications/{id}/read.
+func (h *Handler) HandleClientNotifications(w http.ResponseWriter, r *http.Request) {
+	client, ok := h.authorizeClient(w, r)
+	if !ok {
+		return
+	}
+
+	recipient := usecase.NotificationRecipient{ClientID: client.ID}
+	trimmedPath := strings.Trim(strings.TrimPrefix(r.URL.Path, "/client/notifications"), "/")
+	if trimmedPath == "" {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		filter := usecase.NotificationListFilter{Recipient: recipient}
+		switch strings.ToLower(strings.TrimSpace(r.URL.Query().Get("unread"))) {
+		case "1", "true":
+			filter.UnreadOnly = true
+		}
+		if raw := strings.TrimSpace(r.URL.Query().Get("limit")); raw != "" {
+			limit, err := strconv.Atoi(raw)
+			if err != nil || limit < 1 {
+				h.respondError(w, http.StatusBadRequest, "invalid limit")
+				return
+			}
+			filter.Limit = limit
+		}
+
+		notifications, err := h.notificationService.ListNotifications(r.Context(), filter)
+		if err != nil {
+			h.handleNotificationUsecaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusOK, notifications)
+		return
+	}
+
+	segments := strings.Split(trimmedPath, "/")
+	switch {
+	case len(segments) == 1 && segments[0] == "read-all":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		updated, err := h.notificationService.MarkAllNotificationsRead(r.Context(), recipient)
+		if err != nil {
+			h.handleNotificationUsecaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusOK, map[string]int{"updated": updated})
+	case len(segments) == 2 && segments[1] == "read":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		notification, err := h.notificationService.MarkNotificationRead(r.Context(), recipient, segments[0])
+		if err != nil {
+			h.handleNotificationUsecaseError(w, err)
+			return
+		}
+		h.respondJSON(w, http.StatusOK, notification)
+	default:
+		h.respondError(w, http.StatusNotFound, "route not found")
+	}
+}
+
+func (h *Handler) handleNotificationUsecaseError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInput):
+		h.respondError(w, http.StatusBadRequest, "invalid input")
+	case errors.Is(err, usecase.ErrNotFound):
+		h.respondError(w, http.StatusNotFound, "notification not found")
+	default:
+		requestlog.RecordError(w, err)
+		h.respondError(w, http.StatusInternalServerError, "unexpected error")
+	}
+}
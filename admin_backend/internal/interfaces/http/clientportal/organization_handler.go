@@ -0,0 +1,265 @@
+package clientportal
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"admin_backend/internal/interfaces/http/requestlog"
+	"admin_backend/internal/usecase"
+)
+
+// HandleClientOrganization serves the organization of the signed-in client:
+// GET and PATCH /client/organization, GET /client/organization/members,
+// PATCH /client/organization/members/{id}, GET and POST
+// /client/organization/invites and DELETE /client/organization/invites/{id}.
+// Only owners change anything.
+func (h *Handler) HandleClientOrganization(w http.ResponseWriter, r *http.Request) {
+	client, ok := h.authorizeClient(w, r)
+	if !ok {
+		return
+	}
+
+	trimmedPath := strings.Trim(strings.TrimPrefix(r.URL.Path, "/client/organization"), "/")
+	segments := strings.Split(trimmedPath, "/")
+	switch {
+	case trimmedPath == "":
+		h.handleClientOrganizationRoot(w, r, client)
+	case segments[0] == "members":
+		h.handleClientOrganizationMembers(w, r, client, segments[1:])
+	case segments[0] == "invites":
+		h.handleClientOrganizationInvites(w, r, client, segments[1:])
+	default:
+		h.respondError(w, http.StatusNotFound, "route not found")
+	}
+}
+
+func (h *Handler) handleClientOrganizationRoot(
+	w http.ResponseWriter,
+	r *http.Request,
+	client usecase.ClientPortalAuthUser,
+) {
+	switch r.Method {
+	case http.MethodGet:
+		organization, err := h.organizationService.GetOrganization(r.Context(), client)
+		if err != nil {
+			h.handleOrganizationUsecaseError(w, err, "")
+			return
+		}
+		h.respondJSON(w, http.StatusOK, organization)
+	case http.MethodPatch:
+		var payload struct {
+			Name  string `json:"name"`
+			TaxID string `json:"taxId"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		organization, err := h.organizationService.UpdateOrganization(
+			r.Context(),
+			client,
+			usecase.UpdateClientOrganizationInput{Name: payload.Name, TaxID: payload.TaxID},
+		)
+		if err != nil {
+			h.handleOrganizationUsecaseError(w, err, "name is required and taxId must be a valid CNPJ")
+			return
+		}
+		h.respondJSON(w, http.StatusOK, organization)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) handleClientOrganizationMembers(
+	w http.ResponseWriter,
+	r *http.Request,
+	client usecase.ClientPortalAuthUser,
+	segments []string,
+) {
+	switch len(segments) {
+	case 0:
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		members, err := h.organizationService.ListMembers(r.Context(), client)
+		if err != nil {
+			h.handleOrganizationUsecaseError(w, err, "")
+			return
+		}
+		h.respondJSON(w, http.StatusOK, members)
+	case 1:
+		if r.Method != http.MethodPatch {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var payload struct {
+			Role   string `json:"role"`
+			Active *bool  `json:"active"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		member, err := h.organizationService.UpdateMember(
+			r.Context(),
+			client,
+			usecase.UpdateClientOrganizationMemberInput{
+				ClientID: segments[0],
+				Role:     payload.Role,
+				Active:   payload.Active,
+			},
+		)
+		if err != nil {
+			h.handleOrganizationUsecaseError(w, err, "role must be owner, finance or viewer")
+			return
+		}
+		h.respondJSON(w, http.StatusOK, member)
+	default:
+		h.respondError(w, http.StatusNotFound, "route not found")
+	}
+}
+
+func (h *Handler) handleClientOrganizationInvites(
+	w http.ResponseWriter,
+	r *http.Request,
+	client usecase.ClientPortalAuthUser,
+	segments []string,
+) {
+	switch len(segments) {
+	case 0:
+		switch r.Method {
+		case http.MethodGet:
+			invites, err := h.organizationService.ListInvites(r.Context(), client)
+			if err != nil {
+				h.handleOrganizationUsecaseError(w, err, "")
+				return
+			}
+			h.respondJSON(w, http.StatusOK, invites)
+		case http.MethodPost:
+			var payload struct {
+				Email string `json:"email"`
+				Role  string `json:"role"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				h.respondError(w, http.StatusBadRequest, "invalid json")
+				return
+			}
+
+			invite, err := h.organizationService.InviteMember(
+				r.Context(),
+				client,
+				usecase.InviteClientOrganizationMemberInput{Email: payload.Email, Role: payload.Role},
+			)
+			if err != nil {
+				h.handleOrganizationUsecaseError(w, err, "a valid email is required and role must be owner, finance or viewer")
+				return
+			}
+			h.respondJSON(w, http.StatusCreated, invite)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	case 1:
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err := h.organizationService.RevokeInvite(r.Context(), client, segments[0]); err != nil {
+			h.handleOrganizationUsecaseError(w, err, "")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		h.respondError(w, http.StatusNotFound, "route not found")
+	}
+}
+
+// HandleClientInviteAcceptance serves POST /client-auth/invites/accept: the
+// invited colleague picks a login and password and is signed in.
+func (h *Handler) HandleClientInviteAcceptance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var payload struct {
+		Token    string `json:"token"`
+		Name     string `json:"name"`
+		Login    string `json:"login"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	account, err := h.organizationService.AcceptInvite(r.Context(), usecase.AcceptClientOrganizationInviteInput{
+		Token:    payload.Token,
+		Name:     payload.Name,
+		Login:    payload.Login,
+		Password: payload.Password,
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotFound) {
+			h.respondError(w, http.StatusNotFound, "invite not found or expired")
+			return
+		}
+		h.handlePortalUsecaseError(w, err, "token, login and password are required")
+		return
+	}
+
+	token, expiresAt, err := h.tokenManager.Generate(
+		account.ID,
+		account.Login,
+		account.Name,
+		time.Now(),
+	)
+	if err != nil {
+		requestlog.RecordError(w, err)
+		h.respondError(w, http.StatusInternalServerError, "unexpected error")
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"token":     token,
+		"tokenType": "Bearer",
+		"expiresAt": expiresAt.UTC().Format(time.RFC3339),
+		"client":    account,
+	})
+}
+
+func (h *Handler) handleOrganizationUsecaseError(
+	w http.ResponseWriter,
+	err error,
+	defaultInvalidInputMessage string,
+) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInput):
+		message := defaultInvalidInputMessage
+		if message == "" {
+			message = "invalid input"
+		}
+		h.respondError(w, http.StatusBadRequest, message)
+	case errors.Is(err, usecase.ErrClientRoleForbidden):
+		h.respondError(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, usecase.ErrClientOrganizationOwnerRequired),
+		errors.Is(err, usecase.ErrClientOrganizationTaxIDInUse),
+		errors.Is(err, usecase.ErrClientEmailInUse):
+		h.respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, usecase.ErrConflict):
+		h.respondError(w, http.StatusConflict, "conflict")
+	case errors.Is(err, usecase.ErrNotFound):
+		h.respondError(w, http.StatusNotFound, "member or invite not found")
+	default:
+		requestlog.RecordError(w, err)
+		h.respondError(w, http.StatusInternalServerError, "unexpected error")
+	}
+}
@@ -0,0 +1,89 @@
+package clientportal
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"admin_backend/internal/interfaces/http/requestlog"
+	"admin_backend/internal/usecase"
+)
+
+const surveyAnswerInvalidInputMessage = "rating must be between 1 and 5 and nps, required for projects, between 0 and 10"
+
+// HandleClientSurveys serves GET /client/surveys and
+// POST /client/surveys/{id}/response.
+func (h *Handler) HandleClientSurveys(w http.ResponseWriter, r *http.Request) {
+	client, ok := h.authorizeClient(w, r)
+	if !ok {
+		return
+	}
+
+	trimmedPath := strings.Trim(strings.TrimPrefix(r.URL.Path, "/client/surveys"), "/")
+	if trimmedPath == "" {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		surveys, err := h.surveyService.ListClientSurveys(r.Context(), client.ID, r.URL.Query().Get("status"))
+		if err != nil {
+			h.handleSurveyUsecaseError(w, err, "status must be pendente or respondida")
+			return
+		}
+		h.respondJSON(w, http.StatusOK, surveys)
+		return
+	}
+
+	segments := strings.Split(trimmedPath, "/")
+	if len(segments) != 2 || segments[1] != "response" {
+		h.respondError(w, http.StatusNotFound, "route not found")
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var payload struct {
+		Rating  int    `json:"rating"`
+		NPS     *int   `json:"nps"`
+		Comment string `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	survey, err := h.surveyService.AnswerSurvey(r.Context(), usecase.AnswerClientSurveyInput{
+		ClientID: client.ID,
+		SurveyID: segments[0],
+		Rating:   payload.Rating,
+		NPS:      payload.NPS,
+		Comment:  payload.Comment,
+	})
+	if err != nil {
+		h.handleSurveyUsecaseError(w, err, surveyAnswerInvalidInputMessage)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, survey)
+}
+
+func (h *Handler) handleSurveyUsecaseError(
+	w http.ResponseWriter,
+	err error,
+	defaultInvalidInputMessage string,
+) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInput):
+		h.respondError(w, http.StatusBadRequest, defaultInvalidInputMessage)
+	case errors.Is(err, usecase.ErrConflict):
+		h.respondError(w, http.StatusConflict, "survey already answered")
+	case errors.Is(err, usecase.ErrNotFound):
+		h.respondError(w, http.StatusNotFound, "survey not found")
+	default:
+		requestlog.RecordError(w, err)
+		h.respondError(w, http.StatusInternalServerError, "unexpected error")
+	}
+}
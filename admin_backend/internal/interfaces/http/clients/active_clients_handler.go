@@ -1,6 +1,10 @@
 package clients
 
-import "net/http"
+import (
+	"net/http"
+
+	"admin_backend/internal/interfaces/http/requestlog"
+)
 
 func (h *Handler) HandleActiveClients(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -16,6 +20,7 @@ func (h *Handler) HandleActiveClients(w http.ResponseWriter, r *http.Request) {
 
 	allowed, err := h.hasUserPermission(r.Context(), claims.Sub, permissionClientsRead)
 	if err != nil {
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 		return
 	}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
@@ -22,6 +23,7 @@ func (h *Handler) HandleCEPByZipCode(w http.ResponseWriter, r *http.Request) {
 
 	allowed, err := h.hasUserPermission(r.Context(), claims.Sub, permissionClientsRead)
 	if err != nil {
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 		return
 	}
@@ -41,6 +43,7 @@ func (h *Handler) HandleCEPByZipCode(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, usecase.ErrZipCodeUnavailable):
 			h.respondError(w, http.StatusBadGateway, "zipcode service unavailable")
 		default:
+			requestlog.RecordError(w, err)
 			h.respondError(w, http.StatusInternalServerError, "unexpected error")
 		}
 		return
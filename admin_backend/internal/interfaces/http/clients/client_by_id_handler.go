@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
@@ -31,6 +32,7 @@ func (h *Handler) HandleClientByID(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		allowed, err := h.hasUserPermission(r.Context(), claims.Sub, permissionClientsRead)
 		if err != nil {
+			requestlog.RecordError(w, err)
 			h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			return
 		}
@@ -49,6 +51,7 @@ func (h *Handler) HandleClientByID(w http.ResponseWriter, r *http.Request) {
 	case http.MethodPatch:
 		allowed, err := h.hasUserPermission(r.Context(), claims.Sub, permissionClientsUpdate)
 		if err != nil {
+			requestlog.RecordError(w, err)
 			h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			return
 		}
@@ -63,6 +66,7 @@ func (h *Handler) HandleClientByID(w http.ResponseWriter, r *http.Request) {
 			Login     string            `json:"login"`
 			Password  string            `json:"password"`
 			Avatar    string            `json:"avatar"`
+			TaxID     *string           `json:"taxId"`
 			Active    *bool             `json:"active"`
 			Addresses *[]addressPayload `json:"addresses"`
 			Phones    *[]phonePayload   `json:"phones"`
@@ -90,6 +94,7 @@ func (h *Handler) HandleClientByID(w http.ResponseWriter, r *http.Request) {
 		if password != "" {
 			canUpdatePassword, err := h.hasUserPermission(r.Context(), claims.Sub, permissionClientsPasswordUpdate)
 			if err != nil {
+				requestlog.RecordError(w, err)
 				h.respondError(w, http.StatusInternalServerError, "unexpected error")
 				return
 			}
@@ -102,6 +107,7 @@ func (h *Handler) HandleClientByID(w http.ResponseWriter, r *http.Request) {
 		if payload.Addresses != nil {
 			canUpdateAddresses, err := h.hasUserPermission(r.Context(), claims.Sub, permissionClientAddressesUpdate)
 			if err != nil {
+				requestlog.RecordError(w, err)
 				h.respondError(w, http.StatusInternalServerError, "unexpected error")
 				return
 			}
@@ -114,6 +120,7 @@ func (h *Handler) HandleClientByID(w http.ResponseWriter, r *http.Request) {
 		if payload.Phones != nil {
 			canUpdatePhones, err := h.hasUserPermission(r.Context(), claims.Sub, permissionClientPhonesUpdate)
 			if err != nil {
+				requestlog.RecordError(w, err)
 				h.respondError(w, http.StatusInternalServerError, "unexpected error")
 				return
 			}
@@ -142,6 +149,7 @@ func (h *Handler) HandleClientByID(w http.ResponseWriter, r *http.Request) {
 			Login:     login,
 			Password:  password,
 			Avatar:    avatar,
+			TaxID:     payload.TaxID,
 			Active:    payload.Active,
 			Addresses: addresses,
 			Phones:    phones,
@@ -155,6 +163,7 @@ func (h *Handler) HandleClientByID(w http.ResponseWriter, r *http.Request) {
 	case http.MethodDelete:
 		allowed, err := h.hasUserPermission(r.Context(), claims.Sub, permissionClientsDelete)
 		if err != nil {
+			requestlog.RecordError(w, err)
 			h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			return
 		}
@@ -0,0 +1,115 @@
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"admin_backend/internal/interfaces/http/requestlog"
+	"admin_backend/internal/usecase"
+)
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string                `json:"type"`
+	ID         string                `json:"id"`
+	Geometry   geoJSONPoint          `json:"geometry"`
+	Properties clientLocationPayload `json:"properties"`
+}
+
+// geoJSONPoint holds [longitude, latitude], the GeoJSON axis order.
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type clientLocationPayload struct {
+	ClientID     string `json:"clientId"`
+	ClientName   string `json:"clientName"`
+	AddressID    string `json:"addressId"`
+	Label        string `json:"label"`
+	Street       string `json:"street"`
+	Number       string `json:"number"`
+	Neighborhood string `json:"neighborhood"`
+	City         string `json:"city"`
+	State        string `json:"state"`
+	ZipCode      string `json:"zipCode"`
+}
+
+// HandleClientMap returns the located addresses of active clients as a
+// GeoJSON FeatureCollection. ?bbox=minLon,minLat,maxLon,maxLat limits it to
+// the visible area of the map.
+func (h *Handler) HandleClientMap(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, err := h.authorizeRequest(r)
+	if err != nil {
+		h.respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	allowed, err := h.hasUserPermission(r.Context(), claims.Sub, permissionClientsRead)
+	if err != nil {
+		requestlog.RecordError(w, err)
+		h.respondError(w, http.StatusInternalServerError, "unexpected error")
+		return
+	}
+	if !allowed {
+		h.respondError(w, http.StatusForbidden, "forbidden")
+		return
+	}
+
+	filter := usecase.ClientLocationFilter{}
+	if value := strings.TrimSpace(r.URL.Query().Get("bbox")); value != "" {
+		box, err := usecase.ParseBoundingBox(value)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "bbox must be minLon,minLat,maxLon,maxLat")
+			return
+		}
+		filter.BoundingBox = &box
+	}
+
+	locations, err := h.clientService.ListLocations(r.Context(), filter)
+	if err != nil {
+		h.handleClientUsecaseError(w, err)
+		return
+	}
+
+	collection := geoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]geoJSONFeature, 0, len(locations)),
+	}
+	for _, location := range locations {
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type: "Feature",
+			ID:   location.AddressID,
+			Geometry: geoJSONPoint{
+				Type:        "Point",
+				Coordinates: [2]float64{location.Coordinates.Longitude, location.Coordinates.Latitude},
+			},
+			Properties: clientLocationPayload{
+				ClientID:     location.ClientID,
+				ClientName:   location.ClientName,
+				AddressID:    location.AddressID,
+				Label:        location.Label,
+				Street:       location.Street,
+				Number:       location.Number,
+				Neighborhood: location.Neighborhood,
+				City:         location.City,
+				State:        location.State,
+				ZipCode:      location.ZipCode,
+			},
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(collection)
+}
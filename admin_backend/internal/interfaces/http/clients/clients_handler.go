@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
@@ -19,6 +20,7 @@ func (h *Handler) HandleClients(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		allowed, err := h.hasUserPermission(r.Context(), claims.Sub, permissionClientsRead)
 		if err != nil {
+			requestlog.RecordError(w, err)
 			h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			return
 		}
@@ -37,6 +39,7 @@ func (h *Handler) HandleClients(w http.ResponseWriter, r *http.Request) {
 	case http.MethodPost:
 		allowed, err := h.hasUserPermission(r.Context(), claims.Sub, permissionClientsCreate)
 		if err != nil {
+			requestlog.RecordError(w, err)
 			h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			return
 		}
@@ -51,6 +54,7 @@ func (h *Handler) HandleClients(w http.ResponseWriter, r *http.Request) {
 			Login     string           `json:"login"`
 			Password  string           `json:"password"`
 			Avatar    string           `json:"avatar"`
+			TaxID     string           `json:"taxId"`
 			Active    *bool            `json:"active"`
 			Addresses []addressPayload `json:"addresses"`
 			Phones    []phonePayload   `json:"phones"`
@@ -67,6 +71,7 @@ func (h *Handler) HandleClients(w http.ResponseWriter, r *http.Request) {
 				permissionClientAddressesCreate,
 			)
 			if err != nil {
+				requestlog.RecordError(w, err)
 				h.respondError(w, http.StatusInternalServerError, "unexpected error")
 				return
 			}
@@ -83,6 +88,7 @@ func (h *Handler) HandleClients(w http.ResponseWriter, r *http.Request) {
 				permissionClientPhonesCreate,
 			)
 			if err != nil {
+				requestlog.RecordError(w, err)
 				h.respondError(w, http.StatusInternalServerError, "unexpected error")
 				return
 			}
@@ -118,6 +124,7 @@ func (h *Handler) HandleClients(w http.ResponseWriter, r *http.Request) {
 			Login:     login,
 			Password:  password,
 			Avatar:    avatar,
+			TaxID:     payload.TaxID,
 			Active:    active,
 			Addresses: mapAddressPayloads(payload.Addresses),
 			Phones:    mapPhonePayloads(payload.Phones),
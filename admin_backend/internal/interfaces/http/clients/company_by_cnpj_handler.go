@@ -0,0 +1,53 @@
+package clients
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"admin_backend/internal/interfaces/http/requestlog"
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) HandleCompanyByCNPJ(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, err := h.authorizeRequest(r)
+	if err != nil {
+		h.respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	allowed, err := h.hasUserPermission(r.Context(), claims.Sub, permissionClientsRead)
+	if err != nil {
+		requestlog.RecordError(w, err)
+		h.respondError(w, http.StatusInternalServerError, "unexpected error")
+		return
+	}
+	if !allowed {
+		h.respondError(w, http.StatusForbidden, "forbidden")
+		return
+	}
+
+	trimmedPath := strings.TrimPrefix(r.URL.Path, "/utils/cnpj/")
+	response, err := h.clientService.LookupCompany(r.Context(), trimmedPath)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidInput):
+			h.respondError(w, http.StatusBadRequest, "invalid cnpj")
+		case errors.Is(err, usecase.ErrCompanyNotFound):
+			h.respondError(w, http.StatusNotFound, "company not found")
+		case errors.Is(err, usecase.ErrCompanyLookupUnavailable):
+			h.respondError(w, http.StatusBadGateway, "company lookup service unavailable")
+		default:
+			requestlog.RecordError(w, err)
+			h.respondError(w, http.StatusInternalServerError, "unexpected error")
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
@@ -17,7 +18,12 @@ func (h *Handler) handleClientUsecaseError(w http.ResponseWriter, err error) {
 		h.respondError(w, http.StatusConflict, "client login already in use")
 	case errors.Is(err, usecase.ErrClientEmailInUse):
 		h.respondError(w, http.StatusConflict, "client email already in use")
+	case errors.Is(err, usecase.ErrClientTaxIDInvalid):
+		h.respondError(w, http.StatusBadRequest, "client tax id must be a valid CPF or CNPJ")
+	case errors.Is(err, usecase.ErrClientTaxIDInUse):
+		h.respondError(w, http.StatusConflict, "client tax id already in use")
 	default:
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 	}
 }
@@ -2,20 +2,22 @@ package http
 
 import "net/http"
 
-var allowedCORSOrigins = map[string]struct{}{
-	"http://localhost:3000": {},
-	"http://localhost:3001": {},
-	"http://localhost:3002": {},
-}
+// WithCORS lets the browser call the API from allowedOrigins, matched
+// exactly against the Origin header.
+func WithCORS(allowedOrigins []string, next http.Handler) http.Handler {
+	allowed := make(map[string]struct{}, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		allowed[origin] = struct{}{}
+	}
 
-func WithCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		origin := r.Header.Get("Origin")
-		if _, ok := allowedCORSOrigins[origin]; ok {
+		if _, ok := allowed[origin]; ok {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
 			w.Header().Add("Vary", "Origin")
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
+			w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 			w.Header().Set("Access-Control-Max-Age", "600")
 		}
@@ -25,7 +27,7 @@ func WithCORS(next http.Handler) http.Handler {
 				w.WriteHeader(http.StatusNoContent)
 				return
 			}
-			if _, ok := allowedCORSOrigins[origin]; ok {
+			if _, ok := allowed[origin]; ok {
 				w.WriteHeader(http.StatusNoContent)
 				return
 			}
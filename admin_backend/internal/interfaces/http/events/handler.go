@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"net/http"
+
+	infraauth "admin_backend/internal/infra/auth"
+	"admin_backend/internal/interfaces/http/requestlog"
+	"admin_backend/internal/interfaces/http/sse"
+	"admin_backend/internal/usecase"
+)
+
+const permissionProjectsRead = "projects.read"
+
+type Handler struct {
+	source            usecase.RealtimeEventSource
+	authorizeRequest  func(r *http.Request) (infraauth.Claims, error)
+	hasUserPermission func(ctx context.Context, userID, permissionCode string) (bool, error)
+	respondError      func(w http.ResponseWriter, status int, message string)
+}
+
+func NewHandler(
+	source usecase.RealtimeEventSource,
+	authorizeRequest func(r *http.Request) (infraauth.Claims, error),
+	hasUserPermission func(ctx context.Context, userID, permissionCode string) (bool, error),
+	respondError func(w http.ResponseWriter, status int, message string),
+) *Handler {
+	return &Handler{
+		source:            source,
+		authorizeRequest:  authorizeRequest,
+		hasUserPermission: hasUserPermission,
+		respondError:      respondError,
+	}
+}
+
+// HandleEventStream pushes domain events to the signed in user as
+// Server-Sent Events. Project, task and comment events require the
+// projects.read permission; notifications are only sent to their recipient.
+func (h *Handler) HandleEventStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	claims, err := h.authorizeRequest(sse.WithQueryToken(r))
+	if err != nil {
+		h.respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	canReadProjects, err := h.hasUserPermission(r.Context(), claims.Sub, permissionProjectsRead)
+	if err != nil {
+		requestlog.RecordError(w, err)
+		h.respondError(w, http.StatusInternalServerError, "unexpected error")
+		return
+	}
+
+	subscription := usecase.NewAdminRealtimeSubscription(claims.Sub, canReadProjects)
+	sse.Stream(w, r, h.source, subscription.Allows)
+}
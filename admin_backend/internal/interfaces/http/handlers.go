@@ -5,37 +5,65 @@ import (
 
 	"admin_backend/internal/infra/auth"
 	authhttp "admin_backend/internal/interfaces/http/auth"
-	clientportalhttp "admin_backend/internal/interfaces/http/clientportal"
+	bankstatementshttp "admin_backend/internal/interfaces/http/bankstatements"
 	clientshttp "admin_backend/internal/interfaces/http/clients"
+	eventshttp "admin_backend/internal/interfaces/http/events"
+	inboundmailhttp "admin_backend/internal/interfaces/http/inboundmail"
+	invoiceshttp "admin_backend/internal/interfaces/http/invoices"
+	notificationshttp "admin_backend/internal/interfaces/http/notifications"
+	paymentshttp "admin_backend/internal/interfaces/http/payments"
 	projectshttp "admin_backend/internal/interfaces/http/projects"
 	securityhttp "admin_backend/internal/interfaces/http/security"
 	servicerequestshttp "admin_backend/internal/interfaces/http/servicerequests"
+	servicerequestsettingshttp "admin_backend/internal/interfaces/http/servicerequestsettings"
+	slapolicieshttp "admin_backend/internal/interfaces/http/slapolicies"
+	surveyshttp "admin_backend/internal/interfaces/http/surveys"
 	userprofileshttp "admin_backend/internal/interfaces/http/userprofiles"
 	usershttp "admin_backend/internal/interfaces/http/users"
+	webhookshttp "admin_backend/internal/interfaces/http/webhooks"
 	"admin_backend/internal/usecase"
-	"github.com/jmoiron/sqlx"
 )
 
 type UserHandler struct {
-	service              *usecase.UserService
-	clientService        *usecase.ClientService
-	authService          *usecase.AuthService
-	authorizationService *usecase.AuthorizationService
-	userProfileService   *usecase.UserProfileService
-	securityService      *usecase.SecurityService
-	projectService       *usecase.ProjectService
-	clientPortalService  *usecase.ClientPortalService
-	db                   *sqlx.DB
-	tokenManager         *auth.TokenManager
-
-	authHandler            *authhttp.Handler
-	clientPortalHandler    *clientportalhttp.Handler
-	usersHandler           *usershttp.Handler
-	userProfilesHandler    *userprofileshttp.Handler
-	clientsHandler         *clientshttp.Handler
-	securityHandler        *securityhttp.Handler
-	projectsHandler        *projectshttp.Handler
-	serviceRequestsHandler *servicerequestshttp.Handler
+	service                         *usecase.UserService
+	clientService                   *usecase.ClientService
+	authService                     *usecase.AuthService
+	authorizationService            *usecase.AuthorizationService
+	userProfileService              *usecase.UserProfileService
+	securityService                 *usecase.SecurityService
+	projectService                  *usecase.ProjectService
+	clientPortalService             *usecase.ClientPortalService
+	invoiceService                  *usecase.InvoiceService
+	paymentService                  *usecase.PaymentService
+	bankStatementService            *usecase.BankStatementService
+	reminderService                 *usecase.PaymentReminderService
+	notificationService             *usecase.NotificationService
+	webhookService                  *usecase.WebhookService
+	inboundMailService              *usecase.InboundMailService
+	slaService                      *usecase.SLAService
+	serviceRequestSettingsService   *usecase.ServiceRequestSettingsService
+	serviceRequestConversionService *usecase.ServiceRequestConversionService
+	surveyService                   *usecase.SurveyService
+	realtimeEvents                  usecase.RealtimeEventSource
+	tokenManager                    *auth.TokenManager
+
+	authHandler                   *authhttp.Handler
+	usersHandler                  *usershttp.Handler
+	userProfilesHandler           *userprofileshttp.Handler
+	clientsHandler                *clientshttp.Handler
+	securityHandler               *securityhttp.Handler
+	projectsHandler               *projectshttp.Handler
+	serviceRequestsHandler        *servicerequestshttp.Handler
+	invoicesHandler               *invoiceshttp.Handler
+	paymentsHandler               *paymentshttp.Handler
+	bankStatementsHandler         *bankstatementshttp.Handler
+	notificationsHandler          *notificationshttp.Handler
+	webhooksHandler               *webhookshttp.Handler
+	inboundMailHandler            *inboundmailhttp.Handler
+	slaPoliciesHandler            *slapolicieshttp.Handler
+	serviceRequestSettingsHandler *servicerequestsettingshttp.Handler
+	surveysHandler                *surveyshttp.Handler
+	eventsHandler                 *eventshttp.Handler
 }
 
 func NewUserHandler(
@@ -47,20 +75,42 @@ func NewUserHandler(
 	securityService *usecase.SecurityService,
 	projectService *usecase.ProjectService,
 	clientPortalService *usecase.ClientPortalService,
-	db *sqlx.DB,
+	invoiceService *usecase.InvoiceService,
+	paymentService *usecase.PaymentService,
+	bankStatementService *usecase.BankStatementService,
+	reminderService *usecase.PaymentReminderService,
+	notificationService *usecase.NotificationService,
+	webhookService *usecase.WebhookService,
+	inboundMailService *usecase.InboundMailService,
+	slaService *usecase.SLAService,
+	serviceRequestSettingsService *usecase.ServiceRequestSettingsService,
+	serviceRequestConversionService *usecase.ServiceRequestConversionService,
+	surveyService *usecase.SurveyService,
+	realtimeEvents usecase.RealtimeEventSource,
 	tokenManager *auth.TokenManager,
 ) *UserHandler {
 	handler := &UserHandler{
-		service:              service,
-		clientService:        clientService,
-		authService:          authService,
-		authorizationService: authorizationService,
-		userProfileService:   userProfileService,
-		securityService:      securityService,
-		projectService:       projectService,
-		clientPortalService:  clientPortalService,
-		db:                   db,
-		tokenManager:         tokenManager,
+		service:                         service,
+		clientService:                   clientService,
+		authService:                     authService,
+		authorizationService:            authorizationService,
+		userProfileService:              userProfileService,
+		securityService:                 securityService,
+		projectService:                  projectService,
+		clientPortalService:             clientPortalService,
+		invoiceService:                  invoiceService,
+		paymentService:                  paymentService,
+		bankStatementService:            bankStatementService,
+		reminderService:                 reminderService,
+		notificationService:             notificationService,
+		webhookService:                  webhookService,
+		inboundMailService:              inboundMailService,
+		slaService:                      slaService,
+		serviceRequestSettingsService:   serviceRequestSettingsService,
+		serviceRequestConversionService: serviceRequestConversionService,
+		surveyService:                   surveyService,
+		realtimeEvents:                  realtimeEvents,
+		tokenManager:                    tokenManager,
 	}
 
 	handler.userProfilesHandler = userprofileshttp.NewHandler(
@@ -82,7 +132,6 @@ func NewUserHandler(
 
 	handler.usersHandler = usershttp.NewHandler(
 		handler.service,
-		handler.db,
 		handler.authorizeRequest,
 		normalizeAvatarInput,
 		handler.userProfilesHandler.HandleUserProfiles,
@@ -115,27 +164,95 @@ func NewUserHandler(
 		respondError,
 	)
 
-	handler.clientPortalHandler = clientportalhttp.NewHandler(
+	handler.serviceRequestsHandler = servicerequestshttp.NewHandler(
 		handler.clientPortalService,
-		handler.projectService,
-		handler.tokenManager,
-		normalizeAvatarInput,
+		handler.serviceRequestConversionService,
+		handler.authorizeRequest,
+		handler.hasUserPermission,
 		respondJSON,
 		respondError,
 	)
 
-	handler.serviceRequestsHandler = servicerequestshttp.NewHandler(
-		handler.clientPortalService,
+	handler.invoicesHandler = invoiceshttp.NewHandler(
+		handler.invoiceService,
+		handler.authorizeRequest,
+		handler.hasUserPermission,
+		respondJSON,
+		respondError,
+	)
+
+	handler.paymentsHandler = paymentshttp.NewHandler(
+		handler.paymentService,
+		handler.authorizeRequest,
+		handler.hasUserPermission,
+		respondJSON,
+		respondError,
+	)
+
+	handler.bankStatementsHandler = bankstatementshttp.NewHandler(
+		handler.bankStatementService,
+		handler.authorizeRequest,
+		handler.hasUserPermission,
+		respondJSON,
+		respondError,
+	)
+
+	handler.notificationsHandler = notificationshttp.NewHandler(
+		handler.notificationService,
+		handler.authorizeRequest,
+		respondJSON,
+		respondError,
+	)
+
+	handler.webhooksHandler = webhookshttp.NewHandler(
+		handler.webhookService,
+		handler.authorizeRequest,
+		handler.hasUserPermission,
+		respondJSON,
+		respondError,
+	)
+
+	handler.inboundMailHandler = inboundmailhttp.NewHandler(
+		handler.inboundMailService,
+		respondJSON,
+		respondError,
+	)
+
+	handler.slaPoliciesHandler = slapolicieshttp.NewHandler(
+		handler.slaService,
 		handler.authorizeRequest,
+		handler.hasUserPermission,
 		respondJSON,
 		respondError,
 	)
 
+	handler.serviceRequestSettingsHandler = servicerequestsettingshttp.NewHandler(
+		handler.serviceRequestSettingsService,
+		handler.authorizeRequest,
+		handler.hasUserPermission,
+		respondJSON,
+		respondError,
+	)
+
+	handler.surveysHandler = surveyshttp.NewHandler(
+		handler.surveyService,
+		handler.authorizeRequest,
+		handler.hasUserPermission,
+		respondJSON,
+		respondError,
+	)
+
+	handler.eventsHandler = eventshttp.NewHandler(
+		handler.realtimeEvents,
+		handler.authorizeRequest,
+		handler.hasUserPermission,
+		respondError,
+	)
+
 	return handler
 }
 
 func (h *UserHandler) RegisterRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/health", h.handleHealth)
 	mux.HandleFunc("/auth/login", h.authHandler.HandleLogin)
 	mux.HandleFunc("/auth/account", h.authHandler.HandleAccount)
 	mux.HandleFunc("/auth/me/profiles", h.userProfilesHandler.HandleAuthMyProfiles)
@@ -144,8 +261,10 @@ func (h *UserHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/users/", h.usersHandler.HandleUserByID)
 	mux.HandleFunc("/clients", h.clientsHandler.HandleClients)
 	mux.HandleFunc("/clients/active", h.clientsHandler.HandleActiveClients)
+	mux.HandleFunc("/clients/map", h.clientsHandler.HandleClientMap)
 	mux.HandleFunc("/clients/", h.clientsHandler.HandleClientByID)
 	mux.HandleFunc("/utils/cep/", h.clientsHandler.HandleCEPByZipCode)
+	mux.HandleFunc("/utils/cnpj/", h.clientsHandler.HandleCompanyByCNPJ)
 	mux.HandleFunc("/permissions", h.securityHandler.HandlePermissions)
 	mux.HandleFunc("/permissions/", h.securityHandler.HandlePermissionByID)
 	mux.HandleFunc("/profiles", h.securityHandler.HandleProfiles)
@@ -155,19 +274,35 @@ func (h *UserHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/project-types/", h.projectsHandler.HandleProjectTypeByID)
 	mux.HandleFunc("/projects", h.projectsHandler.HandleProjects)
 	mux.HandleFunc("/projects/", h.projectsHandler.HandleProjectRoutes)
-	mux.HandleFunc("/client-auth/login", h.clientPortalHandler.HandleClientLogin)
-	mux.HandleFunc("/client-auth/register", h.clientPortalHandler.HandleClientRegister)
-	mux.HandleFunc("/client-auth/account", h.clientPortalHandler.HandleClientAccount)
-	mux.HandleFunc("/client/dashboard", h.clientPortalHandler.HandleClientDashboard)
-	mux.HandleFunc("/client/projects", h.clientPortalHandler.HandleClientProjects)
-	mux.HandleFunc("/client/projects/", h.clientPortalHandler.HandleClientProjectRoutes)
-	mux.HandleFunc("/client/payments", h.clientPortalHandler.HandleClientPayments)
-	mux.HandleFunc("/client/service-requests", h.clientPortalHandler.HandleClientServiceRequests)
-	mux.HandleFunc("/client/service-requests/", h.clientPortalHandler.HandleClientServiceRequests)
+	mux.HandleFunc("/project-expense-categories", h.projectsHandler.HandleProjectExpenseCategories)
+	mux.HandleFunc("/user-hourly-rates", h.projectsHandler.HandleUserHourlyRates)
+	mux.HandleFunc("/user-hourly-rates/", h.projectsHandler.HandleUserHourlyRateByID)
+	mux.HandleFunc("/reports/financial", h.projectsHandler.HandleFinancialReport)
+	mux.HandleFunc("/invoice-series", h.invoicesHandler.HandleInvoiceSeries)
+	mux.HandleFunc("/invoices", h.invoicesHandler.HandleInvoices)
+	mux.HandleFunc("/invoices/", h.invoicesHandler.HandleInvoiceRoutes)
+	mux.HandleFunc("/payment-instructions", h.paymentsHandler.HandlePaymentInstructions)
+	mux.HandleFunc("/payment-instructions/", h.paymentsHandler.HandlePaymentInstructionRoutes)
+	mux.HandleFunc("/webhooks/payments", h.paymentsHandler.HandlePaymentWebhook)
+	mux.HandleFunc("/webhook-subscriptions", h.webhooksHandler.HandleWebhookSubscriptions)
+	mux.HandleFunc("/webhook-subscriptions/", h.webhooksHandler.HandleWebhookSubscriptionRoutes)
+	mux.HandleFunc("/webhook-deliveries/", h.webhooksHandler.HandleWebhookDeliveryRoutes)
+	mux.HandleFunc("/inbound-mail", h.inboundMailHandler.HandleInboundMail)
+	mux.HandleFunc("/sla-policies", h.slaPoliciesHandler.HandleSLAPolicies)
+	mux.HandleFunc("/sla-policies/", h.slaPoliciesHandler.HandleSLAPolicyRoutes)
+	mux.HandleFunc("/events/stream", h.eventsHandler.HandleEventStream)
+	mux.HandleFunc("/notifications", h.notificationsHandler.HandleNotifications)
+	mux.HandleFunc("/notifications/", h.notificationsHandler.HandleNotifications)
+	mux.HandleFunc("/bank-statements", h.bankStatementsHandler.HandleBankStatements)
+	mux.HandleFunc("/bank-statements/", h.bankStatementsHandler.HandleBankStatementByID)
+	mux.HandleFunc("/bank-statement-lines", h.bankStatementsHandler.HandleBankStatementLines)
+	mux.HandleFunc("/bank-statement-lines/", h.bankStatementsHandler.HandleBankStatementLineRoutes)
 	mux.HandleFunc("/service-requests", h.serviceRequestsHandler.HandleServiceRequests)
 	mux.HandleFunc("/service-requests/", h.serviceRequestsHandler.HandleServiceRequests)
-}
-
-func (h *UserHandler) handleHealth(w http.ResponseWriter, _ *http.Request) {
-	respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	mux.HandleFunc("/service-request-statuses", h.serviceRequestSettingsHandler.HandleStatuses)
+	mux.HandleFunc("/service-request-statuses/", h.serviceRequestSettingsHandler.HandleStatusRoutes)
+	mux.HandleFunc("/service-request-categories", h.serviceRequestSettingsHandler.HandleCategories)
+	mux.HandleFunc("/service-request-categories/", h.serviceRequestSettingsHandler.HandleCategoryRoutes)
+	mux.HandleFunc("/surveys", h.surveysHandler.HandleSurveys)
+	mux.HandleFunc("/surveys/report", h.surveysHandler.HandleSurveyReport)
 }
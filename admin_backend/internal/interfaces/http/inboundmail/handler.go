@@ -0,0 +1,25 @@
+package inboundmail
+
+import (
+	"net/http"
+
+	"admin_backend/internal/usecase"
+)
+
+type Handler struct {
+	inboundMailService *usecase.InboundMailService
+	respondJSON        func(w http.ResponseWriter, status int, payload interface{})
+	respondError       func(w http.ResponseWriter, status int, message string)
+}
+
+func NewHandler(
+	inboundMailService *usecase.InboundMailService,
+	respondJSON func(w http.ResponseWriter, status int, payload interface{}),
+	respondError func(w http.ResponseWriter, status int, message string),
+) *Handler {
+	return &Handler{
+		inboundMailService: inboundMailService,
+		respondJSON:        respondJSON,
+		respondError:       respondError,
+	}
+}
@@ -0,0 +1,45 @@
+package inboundmail
+
+import (
+	"io"
+	"net/http"
+
+	"admin_backend/internal/usecase"
+)
+
+const (
+	inboundMailSecretHeader = "X-Inbound-Mail-Secret"
+	maxInboundMailBodyBytes = 25 << 20
+)
+
+// HandleInboundMail receives raw RFC 822 messages piped by the MTA, e.g.
+// curl --data-binary @- -H "X-Inbound-Mail-Secret: ..." .../inbound-mail.
+// It is authenticated by the shared secret instead of a bearer token.
+// Rejected messages answer 422 so the MTA bounces them to the sender.
+func (h *Handler) HandleInboundMail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	raw, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxInboundMailBodyBytes))
+	if err != nil {
+		h.respondError(w, http.StatusRequestEntityTooLarge, "message too large")
+		return
+	}
+
+	result, err := h.inboundMailService.IngestMessage(r.Context(), raw, r.Header.Get(inboundMailSecretHeader))
+	if err != nil {
+		h.handleInboundMailUsecaseError(w, err)
+		return
+	}
+
+	switch result.Status {
+	case usecase.InboundMailStatusRequestCreated, usecase.InboundMailStatusCommentCreated:
+		h.respondJSON(w, http.StatusCreated, result)
+	case usecase.InboundMailStatusRejected:
+		h.respondJSON(w, http.StatusUnprocessableEntity, result)
+	default:
+		h.respondJSON(w, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,25 @@
+package inboundmail
+
+import (
+	"errors"
+	"net/http"
+
+	"admin_backend/internal/interfaces/http/requestlog"
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) handleInboundMailUsecaseError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInboundMailDisabled):
+		h.respondError(w, http.StatusServiceUnavailable, "inbound mail is not configured")
+	case errors.Is(err, usecase.ErrUnauthorized):
+		h.respondError(w, http.StatusUnauthorized, "unauthorized")
+	case errors.Is(err, usecase.ErrInboundMailInvalid):
+		h.respondError(w, http.StatusBadRequest, "message could not be parsed")
+	case errors.Is(err, usecase.ErrInvalidInput):
+		h.respondError(w, http.StatusBadRequest, "invalid input")
+	default:
+		requestlog.RecordError(w, err)
+		h.respondError(w, http.StatusInternalServerError, "unexpected error")
+	}
+}
@@ -0,0 +1,39 @@
+package invoices
+
+import (
+	"context"
+	"net/http"
+
+	infraauth "admin_backend/internal/infra/auth"
+	"admin_backend/internal/usecase"
+)
+
+type Handler struct {
+	invoiceService    *usecase.InvoiceService
+	authorizeRequest  func(r *http.Request) (infraauth.Claims, error)
+	hasUserPermission func(ctx context.Context, userID, permissionCode string) (bool, error)
+	respondJSON       func(w http.ResponseWriter, status int, payload interface{})
+	respondError      func(w http.ResponseWriter, status int, message string)
+}
+
+func NewHandler(
+	invoiceService *usecase.InvoiceService,
+	authorizeRequest func(r *http.Request) (infraauth.Claims, error),
+	hasUserPermission func(ctx context.Context, userID, permissionCode string) (bool, error),
+	respondJSON func(w http.ResponseWriter, status int, payload interface{}),
+	respondError func(w http.ResponseWriter, status int, message string),
+) *Handler {
+	return &Handler{
+		invoiceService:    invoiceService,
+		authorizeRequest:  authorizeRequest,
+		hasUserPermission: hasUserPermission,
+		respondJSON:       respondJSON,
+		respondError:      respondError,
+	}
+}
+
+const (
+	permissionInvoicesRead   = "invoices.read"
+	permissionInvoicesCreate = "invoices.create"
+	permissionInvoicesUpdate = "invoices.update"
+)
@@ -0,0 +1,126 @@
+package invoices
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) HandleInvoiceRoutes(w http.ResponseWriter, r *http.Request) {
+	trimmedPath := strings.TrimPrefix(r.URL.Path, "/invoices/")
+	trimmedPath = strings.Trim(trimmedPath, "/")
+	if trimmedPath == "" {
+		h.respondError(w, http.StatusNotFound, "invoice not found")
+		return
+	}
+
+	segments := strings.Split(trimmedPath, "/")
+	invoiceID := strings.TrimSpace(segments[0])
+	if invoiceID == "" {
+		h.respondError(w, http.StatusNotFound, "invoice not found")
+		return
+	}
+
+	if len(segments) == 1 {
+		h.handleInvoiceByID(w, r, invoiceID)
+		return
+	}
+
+	if len(segments) > 2 {
+		h.respondError(w, http.StatusNotFound, "route not found")
+		return
+	}
+
+	switch strings.ToLower(strings.TrimSpace(segments[1])) {
+	case "cancel":
+		h.handleInvoiceCancel(w, r, invoiceID)
+	case "pdf":
+		h.handleInvoicePDF(w, r, invoiceID)
+	default:
+		h.respondError(w, http.StatusNotFound, "route not found")
+	}
+}
+
+func (h *Handler) handleInvoiceByID(w http.ResponseWriter, r *http.Request, invoiceID string) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.authorizeWithPermission(w, r, permissionInvoicesRead); !ok {
+		return
+	}
+
+	invoice, err := h.invoiceService.GetInvoice(r.Context(), invoiceID)
+	if err != nil {
+		h.handleInvoiceUsecaseError(w, err, "")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, invoice)
+}
+
+func (h *Handler) handleInvoiceCancel(w http.ResponseWriter, r *http.Request, invoiceID string) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.authorizeWithPermission(w, r, permissionInvoicesUpdate); !ok {
+		return
+	}
+
+	var payload struct {
+		Reason               string `json:"reason"`
+		CreditNoteSeriesCode string `json:"creditNoteSeriesCode"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	result, err := h.invoiceService.CancelInvoice(r.Context(), usecase.CancelInvoiceInput{
+		ID:                   invoiceID,
+		Reason:               payload.Reason,
+		CreditNoteSeriesCode: payload.CreditNoteSeriesCode,
+	})
+	if err != nil {
+		h.handleInvoiceUsecaseError(w, err, "reason is required")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+func (h *Handler) handleInvoicePDF(w http.ResponseWriter, r *http.Request, invoiceID string) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.authorizeWithPermission(w, r, permissionInvoicesRead); !ok {
+		return
+	}
+
+	invoice, pdfBytes, err := h.invoiceService.ExportInvoicePDF(
+		r.Context(),
+		invoiceID,
+		usecase.ProjectPDFStyle{
+			Theme: r.URL.Query().Get("theme"),
+			Font:  r.URL.Query().Get("font"),
+		},
+	)
+	if err != nil {
+		h.handleInvoiceUsecaseError(w, err, "")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"%s.pdf\"", strings.ToLower(invoice.DisplayNumber)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(pdfBytes)
+}
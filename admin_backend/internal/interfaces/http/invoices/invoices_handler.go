@@ -0,0 +1,138 @@
+package invoices
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) HandleInvoices(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := h.authorizeWithPermission(w, r, permissionInvoicesRead); !ok {
+			return
+		}
+
+		invoices, err := h.invoiceService.ListInvoices(r.Context(), usecase.InvoiceListFilter{
+			ProjectID: r.URL.Query().Get("projectId"),
+			ClientID:  r.URL.Query().Get("clientId"),
+			Kind:      r.URL.Query().Get("kind"),
+			Status:    r.URL.Query().Get("status"),
+		})
+		if err != nil {
+			h.handleInvoiceUsecaseError(w, err, "invalid kind or status filter")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, invoices)
+	case http.MethodPost:
+		if _, ok := h.authorizeWithPermission(w, r, permissionInvoicesCreate); !ok {
+			return
+		}
+
+		var payload struct {
+			ProjectID  string `json:"projectId"`
+			SourceType string `json:"sourceType"`
+			SourceID   string `json:"sourceId"`
+			ClientID   string `json:"clientId"`
+			SeriesCode string `json:"seriesCode"`
+			IssuedOn   string `json:"issuedOn"`
+			DueOn      string `json:"dueOn"`
+			Notes      string `json:"notes"`
+			Items      []struct {
+				Description string  `json:"description"`
+				Quantity    float64 `json:"quantity"`
+				UnitPrice   float64 `json:"unitPrice"`
+			} `json:"items"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		issuedOn, err := parseOptionalDate(payload.IssuedOn)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid issuedOn")
+			return
+		}
+		dueOn, err := parseOptionalDate(payload.DueOn)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid dueOn")
+			return
+		}
+
+		items := make([]usecase.InvoiceItemInput, 0, len(payload.Items))
+		for _, item := range payload.Items {
+			items = append(items, usecase.InvoiceItemInput{
+				Description: item.Description,
+				Quantity:    item.Quantity,
+				UnitPrice:   item.UnitPrice,
+			})
+		}
+
+		invoice, err := h.invoiceService.IssueInvoice(r.Context(), usecase.IssueInvoiceInput{
+			ProjectID:  payload.ProjectID,
+			SourceType: payload.SourceType,
+			SourceID:   payload.SourceID,
+			ClientID:   payload.ClientID,
+			SeriesCode: payload.SeriesCode,
+			IssuedOn:   issuedOn,
+			DueOn:      dueOn,
+			Notes:      payload.Notes,
+			Items:      items,
+		})
+		if err != nil {
+			h.handleInvoiceUsecaseError(
+				w,
+				err,
+				"projectId, sourceType (revenue or monthly_charge) and sourceId are required",
+			)
+			return
+		}
+
+		h.respondJSON(w, http.StatusCreated, invoice)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) HandleInvoiceSeries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.authorizeWithPermission(w, r, permissionInvoicesRead); !ok {
+		return
+	}
+
+	series, err := h.invoiceService.ListInvoiceSeries(r.Context())
+	if err != nil {
+		h.handleInvoiceUsecaseError(w, err, "")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, series)
+}
+
+func parseOptionalDate(value string) (*time.Time, error) {
+	raw := strings.TrimSpace(value)
+	if raw == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", raw)
+	if err == nil {
+		return &parsed, nil
+	}
+
+	rfc3339Date, rfcErr := time.Parse(time.RFC3339, raw)
+	if rfcErr != nil {
+		return nil, err
+	}
+
+	return &rfc3339Date, nil
+}
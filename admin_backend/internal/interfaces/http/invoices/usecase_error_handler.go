@@ -0,0 +1,45 @@
+package invoices
+
+import (
+	"errors"
+	"net/http"
+
+	"admin_backend/internal/interfaces/http/requestlog"
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) handleInvoiceUsecaseError(
+	w http.ResponseWriter,
+	err error,
+	defaultInvalidInputMessage string,
+) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInput):
+		message := defaultInvalidInputMessage
+		if message == "" {
+			message = "invalid input"
+		}
+		h.respondError(w, http.StatusBadRequest, message)
+	case errors.Is(err, usecase.ErrNotFound):
+		h.respondError(w, http.StatusNotFound, "invoice not found")
+	case errors.Is(err, usecase.ErrProjectTypeNotFound):
+		h.respondError(w, http.StatusBadRequest, "project type not found")
+	case errors.Is(err, usecase.ErrInvoiceSeriesNotFound):
+		h.respondError(w, http.StatusBadRequest, "invoice series not found")
+	case errors.Is(err, usecase.ErrInvoiceClientRequired):
+		h.respondError(w, http.StatusBadRequest, "invoice client must be linked to the project")
+	case errors.Is(err, usecase.ErrInvoiceSourceNotBillable):
+		h.respondError(w, http.StatusUnprocessableEntity, "cancelled revenues and charges cannot be invoiced")
+	case errors.Is(err, usecase.ErrInvoiceAmountMismatch):
+		h.respondError(w, http.StatusBadRequest, "invoice items must add up to the billed amount")
+	case errors.Is(err, usecase.ErrInvoiceAlreadyIssued):
+		h.respondError(w, http.StatusConflict, "an invoice was already issued for this source")
+	case errors.Is(err, usecase.ErrInvoiceNotCancellable):
+		h.respondError(w, http.StatusConflict, "only issued invoices can be cancelled")
+	case errors.Is(err, usecase.ErrInvoiceImmutable):
+		h.respondError(w, http.StatusConflict, "invoices are immutable once issued")
+	default:
+		requestlog.RecordError(w, err)
+		h.respondError(w, http.StatusInternalServerError, "unexpected error")
+	}
+}
@@ -0,0 +1,157 @@
+package notifications
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	infraauth "admin_backend/internal/infra/auth"
+	"admin_backend/internal/interfaces/http/requestlog"
+	"admin_backend/internal/usecase"
+)
+
+type Handler struct {
+	notificationService *usecase.NotificationService
+	authorizeRequest    func(r *http.Request) (infraauth.Claims, error)
+	respondJSON         func(w http.ResponseWriter, status int, payload interface{})
+	respondError        func(w http.ResponseWriter, status int, message string)
+}
+
+func NewHandler(
+	notificationService *usecase.NotificationService,
+	authorizeRequest func(r *http.Request) (infraauth.Claims, error),
+	respondJSON func(w http.ResponseWriter, status int, payload interface{}),
+	respondError func(w http.ResponseWriter, status int, message string),
+) *Handler {
+	return &Handler{
+		notificationService: notificationService,
+		authorizeRequest:    authorizeRequest,
+		respondJSON:         respondJSON,
+		respondError:        respondError,
+	}
+}
+
+// HandleNotifications serves the signed in user's notification center:
+// GET /notifications, POST /notifications/read-all and
+// POST /notifications/{id}/read.
+func (h *Handler) HandleNotifications(w http.ResponseWriter, r *http.Request) {
+	claims, err := h.authorizeRequest(r)
+	if err != nil {
+		h.respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	recipient := usecase.NotificationRecipient{UserID: claims.Sub}
+	trimmedPath := strings.Trim(strings.TrimPrefix(r.URL.Path, "/notifications"), "/")
+	if trimmedPath == "" {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		h.listNotifications(w, r, recipient)
+		return
+	}
+
+	segments := strings.Split(trimmedPath, "/")
+	switch {
+	case len(segments) == 1 && segments[0] == "read-all":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		h.markAllNotificationsRead(w, r, recipient)
+	case len(segments) == 2 && segments[1] == "read":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		h.markNotificationRead(w, r, recipient, segments[0])
+	default:
+		h.respondError(w, http.StatusNotFound, "route not found")
+	}
+}
+
+func (h *Handler) listNotifications(
+	w http.ResponseWriter,
+	r *http.Request,
+	recipient usecase.NotificationRecipient,
+) {
+	filter, ok := parseNotificationListFilter(r)
+	if !ok {
+		h.respondError(w, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	filter.Recipient = recipient
+
+	notifications, err := h.notificationService.ListNotifications(r.Context(), filter)
+	if err != nil {
+		h.handleNotificationUsecaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, notifications)
+}
+
+func (h *Handler) markNotificationRead(
+	w http.ResponseWriter,
+	r *http.Request,
+	recipient usecase.NotificationRecipient,
+	notificationID string,
+) {
+	notification, err := h.notificationService.MarkNotificationRead(r.Context(), recipient, notificationID)
+	if err != nil {
+		h.handleNotificationUsecaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, notification)
+}
+
+func (h *Handler) markAllNotificationsRead(
+	w http.ResponseWriter,
+	r *http.Request,
+	recipient usecase.NotificationRecipient,
+) {
+	updated, err := h.notificationService.MarkAllNotificationsRead(r.Context(), recipient)
+	if err != nil {
+		h.handleNotificationUsecaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]int{"updated": updated})
+}
+
+func (h *Handler) handleNotificationUsecaseError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInput):
+		h.respondError(w, http.StatusBadRequest, "invalid input")
+	case errors.Is(err, usecase.ErrNotFound):
+		h.respondError(w, http.StatusNotFound, "notification not found")
+	default:
+		requestlog.RecordError(w, err)
+		h.respondError(w, http.StatusInternalServerError, "unexpected error")
+	}
+}
+
+// parseNotificationListFilter reads the optional unread and limit query
+// parameters.
+func parseNotificationListFilter(r *http.Request) (usecase.NotificationListFilter, bool) {
+	query := r.URL.Query()
+	filter := usecase.NotificationListFilter{}
+
+	switch strings.ToLower(strings.TrimSpace(query.Get("unread"))) {
+	case "1", "true":
+		filter.UnreadOnly = true
+	}
+
+	if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return usecase.NotificationListFilter{}, false
+		}
+		filter.Limit = limit
+	}
+
+	return filter, true
+}
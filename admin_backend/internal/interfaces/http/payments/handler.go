@@ -0,0 +1,38 @@
+package payments
+
+import (
+	"context"
+	"net/http"
+
+	infraauth "admin_backend/internal/infra/auth"
+	"admin_backend/internal/usecase"
+)
+
+type Handler struct {
+	paymentService    *usecase.PaymentService
+	authorizeRequest  func(r *http.Request) (infraauth.Claims, error)
+	hasUserPermission func(ctx context.Context, userID, permissionCode string) (bool, error)
+	respondJSON       func(w http.ResponseWriter, status int, payload interface{})
+	respondError      func(w http.ResponseWriter, status int, message string)
+}
+
+func NewHandler(
+	paymentService *usecase.PaymentService,
+	authorizeRequest func(r *http.Request) (infraauth.Claims, error),
+	hasUserPermission func(ctx context.Context, userID, permissionCode string) (bool, error),
+	respondJSON func(w http.ResponseWriter, status int, payload interface{}),
+	respondError func(w http.ResponseWriter, status int, message string),
+) *Handler {
+	return &Handler{
+		paymentService:    paymentService,
+		authorizeRequest:  authorizeRequest,
+		hasUserPermission: hasUserPermission,
+		respondJSON:       respondJSON,
+		respondError:      respondError,
+	}
+}
+
+const (
+	permissionPaymentsRead   = "payments.read"
+	permissionPaymentsCreate = "payments.create"
+)
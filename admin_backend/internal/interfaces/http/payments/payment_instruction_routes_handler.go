@@ -0,0 +1,103 @@
+package payments
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func (h *Handler) HandlePaymentInstructionRoutes(w http.ResponseWriter, r *http.Request) {
+	trimmedPath := strings.TrimPrefix(r.URL.Path, "/payment-instructions/")
+	trimmedPath = strings.Trim(trimmedPath, "/")
+	if trimmedPath == "" {
+		h.respondError(w, http.StatusNotFound, "payment instruction not found")
+		return
+	}
+
+	segments := strings.Split(trimmedPath, "/")
+	instructionID := strings.TrimSpace(segments[0])
+	if instructionID == "" {
+		h.respondError(w, http.StatusNotFound, "payment instruction not found")
+		return
+	}
+
+	if len(segments) == 1 {
+		h.handlePaymentInstructionByID(w, r, instructionID)
+		return
+	}
+
+	if len(segments) > 2 {
+		h.respondError(w, http.StatusNotFound, "route not found")
+		return
+	}
+
+	switch strings.ToLower(strings.TrimSpace(segments[1])) {
+	case "qrcode":
+		h.handlePaymentInstructionQRCode(w, r, instructionID)
+	case "simulate-payment":
+		h.handlePaymentInstructionSimulation(w, r, instructionID)
+	default:
+		h.respondError(w, http.StatusNotFound, "route not found")
+	}
+}
+
+func (h *Handler) handlePaymentInstructionByID(w http.ResponseWriter, r *http.Request, instructionID string) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.authorizeWithPermission(w, r, permissionPaymentsRead); !ok {
+		return
+	}
+
+	instruction, err := h.paymentService.GetPaymentInstruction(r.Context(), instructionID)
+	if err != nil {
+		h.handlePaymentUsecaseError(w, err, "")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, instruction)
+}
+
+func (h *Handler) handlePaymentInstructionQRCode(w http.ResponseWriter, r *http.Request, instructionID string) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.authorizeWithPermission(w, r, permissionPaymentsRead); !ok {
+		return
+	}
+
+	instruction, pngBytes, err := h.paymentService.GetPaymentInstructionQRCode(r.Context(), instructionID)
+	if err != nil {
+		h.handlePaymentUsecaseError(w, err, "qr codes are only available for pix instructions")
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"pix-%s.png\"", instruction.ID))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(pngBytes)
+}
+
+func (h *Handler) handlePaymentInstructionSimulation(w http.ResponseWriter, r *http.Request, instructionID string) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.authorizeWithPermission(w, r, permissionPaymentsCreate); !ok {
+		return
+	}
+
+	result, err := h.paymentService.SimulatePayment(r.Context(), instructionID)
+	if err != nil {
+		h.handlePaymentUsecaseError(w, err, "")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,57 @@
+package payments
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) HandlePaymentInstructions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := h.authorizeWithPermission(w, r, permissionPaymentsRead); !ok {
+			return
+		}
+
+		instructions, err := h.paymentService.ListPaymentInstructions(r.Context(), usecase.PaymentInstructionListFilter{
+			ProjectID:       r.URL.Query().Get("projectId"),
+			MonthlyChargeID: r.URL.Query().Get("monthlyChargeId"),
+			Status:          r.URL.Query().Get("status"),
+		})
+		if err != nil {
+			h.handlePaymentUsecaseError(w, err, "invalid status filter")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, instructions)
+	case http.MethodPost:
+		if _, ok := h.authorizeWithPermission(w, r, permissionPaymentsCreate); !ok {
+			return
+		}
+
+		var payload struct {
+			ProjectID       string `json:"projectId"`
+			MonthlyChargeID string `json:"monthlyChargeId"`
+			Method          string `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		instructions, err := h.paymentService.CreatePaymentInstructions(r.Context(), usecase.CreatePaymentInstructionsInput{
+			ProjectID:       payload.ProjectID,
+			MonthlyChargeID: payload.MonthlyChargeID,
+			Method:          payload.Method,
+		})
+		if err != nil {
+			h.handlePaymentUsecaseError(w, err, "projectId is required and method must be pix or boleto")
+			return
+		}
+
+		h.respondJSON(w, http.StatusCreated, instructions)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,34 @@
+package payments
+
+import (
+	"io"
+	"net/http"
+)
+
+const (
+	webhookSignatureHeader = "X-Webhook-Signature"
+	maxWebhookBodyBytes    = 1 << 20
+)
+
+// HandlePaymentWebhook is called by the payment provider, not by users, so
+// it is authenticated by the body signature instead of a bearer token.
+func (h *Handler) HandlePaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid webhook body")
+		return
+	}
+
+	result, err := h.paymentService.HandleWebhook(r.Context(), payload, r.Header.Get(webhookSignatureHeader))
+	if err != nil {
+		h.handlePaymentUsecaseError(w, err, "")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
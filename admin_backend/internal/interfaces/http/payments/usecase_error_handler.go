@@ -0,0 +1,39 @@
+package payments
+
+import (
+	"errors"
+	"net/http"
+
+	"admin_backend/internal/interfaces/http/requestlog"
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) handlePaymentUsecaseError(
+	w http.ResponseWriter,
+	err error,
+	defaultInvalidInputMessage string,
+) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInput):
+		message := defaultInvalidInputMessage
+		if message == "" {
+			message = "invalid input"
+		}
+		h.respondError(w, http.StatusBadRequest, message)
+	case errors.Is(err, usecase.ErrNotFound):
+		h.respondError(w, http.StatusNotFound, "payment instruction not found")
+	case errors.Is(err, usecase.ErrConflict):
+		h.respondError(w, http.StatusConflict, "a pending payment instruction already exists for this charge")
+	case errors.Is(err, usecase.ErrPaymentChargeNotOpen):
+		h.respondError(w, http.StatusUnprocessableEntity, "only pending monthly charges accept payments")
+	case errors.Is(err, usecase.ErrPaymentProviderUnavailable):
+		h.respondError(w, http.StatusBadGateway, "payment provider unavailable")
+	case errors.Is(err, usecase.ErrPaymentWebhookInvalid):
+		h.respondError(w, http.StatusUnauthorized, "invalid webhook signature")
+	case errors.Is(err, usecase.ErrPaymentSimulationNotSupported):
+		h.respondError(w, http.StatusNotImplemented, "payment provider does not support simulated payments")
+	default:
+		requestlog.RecordError(w, err)
+		h.respondError(w, http.StatusInternalServerError, "unexpected error")
+	}
+}
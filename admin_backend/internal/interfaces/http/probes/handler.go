@@ -0,0 +1,83 @@
+// Package probes serves the liveness and readiness endpoints.
+package probes
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"admin_backend/internal/infra/health"
+)
+
+type Handler struct {
+	checker *health.Checker
+}
+
+func NewHandler(checker *health.Checker) *Handler {
+	return &Handler{checker: checker}
+}
+
+// RegisterRoutes mounts /livez, /readyz and /health, kept as an alias of
+// /livez for existing monitors.
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/livez", h.HandleLive)
+	mux.HandleFunc("/health", h.HandleLive)
+	mux.HandleFunc("/readyz", h.HandleReady)
+}
+
+// HandleLive answers as long as the process can serve requests; it never
+// looks at dependencies, so a database outage does not get the pod
+// restarted.
+func (h *Handler) HandleLive(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// HandleReady reports 503 while shutting down or while any dependency check
+// fails. Failure details are logged rather than returned, since the probe is
+// not authenticated.
+func (h *Handler) HandleReady(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	results, ready := h.checker.Ready(r.Context())
+
+	checks := make(map[string]string, len(results))
+	for _, result := range results {
+		if result.Err != nil {
+			checks[result.Name] = "failing"
+			slog.WarnContext(r.Context(), "readiness check failed", "check", result.Name, "error", result.Err)
+			continue
+		}
+		checks[result.Name] = "ok"
+	}
+
+	status := "ready"
+	statusCode := http.StatusOK
+	switch {
+	case h.checker.Draining():
+		status = "draining"
+		statusCode = http.StatusServiceUnavailable
+	case !ready:
+		status = "unavailable"
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	respondJSON(w, statusCode, map[string]interface{}{
+		"status": status,
+		"checks": checks,
+	})
+}
+
+func respondJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
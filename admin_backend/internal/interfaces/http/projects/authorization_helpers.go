@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"admin_backend/internal/infra/auth"
+	"admin_backend/internal/interfaces/http/requestlog"
 )
 
 func (h *Handler) authorizeWithPermission(
@@ -19,6 +20,7 @@ func (h *Handler) authorizeWithPermission(
 
 	allowed, err := h.hasUserPermission(r.Context(), claims.Sub, permissionCode)
 	if err != nil {
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 		return auth.Claims{}, false
 	}
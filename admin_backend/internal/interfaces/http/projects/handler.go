@@ -57,4 +57,20 @@ const (
 	permissionProjectTasksRead   = "project_tasks.read"
 	permissionProjectTasksCreate = "project_tasks.create"
 	permissionProjectTasksUpdate = "project_tasks.update"
+
+	permissionProjectExpenseCategoriesRead   = "project_expense_categories.read"
+	permissionProjectExpenseCategoriesCreate = "project_expense_categories.create"
+
+	permissionProjectExpensesRead   = "project_expenses.read"
+	permissionProjectExpensesCreate = "project_expenses.create"
+	permissionProjectExpensesUpdate = "project_expenses.update"
+
+	permissionProjectTimeEntriesRead   = "project_time_entries.read"
+	permissionProjectTimeEntriesCreate = "project_time_entries.create"
+	permissionProjectTimeEntriesUpdate = "project_time_entries.update"
+
+	permissionUserHourlyRatesRead   = "user_hourly_rates.read"
+	permissionUserHourlyRatesUpdate = "user_hourly_rates.update"
+
+	permissionProjectFinancialsRead = "project_financials.read"
 )
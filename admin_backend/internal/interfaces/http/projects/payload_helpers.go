@@ -76,3 +76,35 @@ func mapRevenueReceiptPayloads(
 
 	return receipts, nil
 }
+
+func mapExpenseReceiptPayloads(
+	payloads []revenueReceiptPayload,
+) ([]usecase.CreateProjectExpenseReceiptInput, error) {
+	receipts := make([]usecase.CreateProjectExpenseReceiptInput, 0, len(payloads))
+	for _, payload := range payloads {
+		issuedOn, err := parseOptionalDate(payload.IssuedOn)
+		if err != nil {
+			return nil, err
+		}
+
+		receipts = append(receipts, usecase.CreateProjectExpenseReceiptInput{
+			FileName:    payload.FileName,
+			FileKey:     payload.FileKey,
+			ContentType: payload.ContentType,
+			IssuedOn:    issuedOn,
+			Notes:       payload.Notes,
+		})
+	}
+
+	return receipts, nil
+}
+
+// parseOptionalMonth accepts "2006-01" and returns the zero time when empty.
+func parseOptionalMonth(value string) (time.Time, error) {
+	raw := strings.TrimSpace(value)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse("2006-01", raw)
+}
@@ -0,0 +1,87 @@
+package projects
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) handleProjectExpenseByID(
+	w http.ResponseWriter,
+	r *http.Request,
+	projectID string,
+	expenseID string,
+) {
+	switch r.Method {
+	case http.MethodPatch:
+		if _, ok := h.authorizeWithPermission(w, r, permissionProjectExpensesUpdate); !ok {
+			return
+		}
+
+		var payload struct {
+			expensePayload
+			Receipts *[]revenueReceiptPayload `json:"receipts"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		incurredOn, err := parseOptionalDate(payload.IncurredOn)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid incurredOn")
+			return
+		}
+
+		var receipts *[]usecase.CreateProjectExpenseReceiptInput
+		if payload.Receipts != nil {
+			mappedReceipts, err := mapExpenseReceiptPayloads(*payload.Receipts)
+			if err != nil {
+				h.respondError(w, http.StatusBadRequest, "invalid receipt issuedOn")
+				return
+			}
+			receipts = &mappedReceipts
+		}
+
+		active := true
+		if payload.Active != nil {
+			active = *payload.Active
+		}
+
+		expense, err := h.projectService.UpdateProjectExpense(
+			r.Context(),
+			usecase.UpdateProjectExpenseInput{
+				ID:          expenseID,
+				ProjectID:   projectID,
+				CategoryID:  payload.CategoryID,
+				Title:       payload.Title,
+				Description: payload.Description,
+				Supplier:    payload.Supplier,
+				Amount:      payload.Amount,
+				IncurredOn:  incurredOn,
+				Active:      active,
+				Receipts:    receipts,
+			},
+		)
+		if err != nil {
+			h.handleProjectUsecaseError(w, err, "categoryId, title, amount and incurredOn are required")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, expense)
+	case http.MethodDelete:
+		if _, ok := h.authorizeWithPermission(w, r, permissionProjectExpensesUpdate); !ok {
+			return
+		}
+
+		if err := h.projectService.DeleteProjectExpense(r.Context(), projectID, expenseID); err != nil {
+			h.handleProjectUsecaseError(w, err, "")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,63 @@
+package projects
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) HandleProjectExpenseCategories(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := h.authorizeWithPermission(w, r, permissionProjectExpenseCategoriesRead); !ok {
+			return
+		}
+
+		categories, err := h.projectService.ListProjectExpenseCategories(r.Context())
+		if err != nil {
+			h.handleProjectUsecaseError(w, err, "")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, categories)
+	case http.MethodPost:
+		if _, ok := h.authorizeWithPermission(w, r, permissionProjectExpenseCategoriesCreate); !ok {
+			return
+		}
+
+		var payload struct {
+			Code        string `json:"code"`
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			Active      *bool  `json:"active"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		active := true
+		if payload.Active != nil {
+			active = *payload.Active
+		}
+
+		category, err := h.projectService.CreateProjectExpenseCategory(
+			r.Context(),
+			usecase.CreateProjectExpenseCategoryInput{
+				Code:        payload.Code,
+				Name:        payload.Name,
+				Description: payload.Description,
+				Active:      active,
+			},
+		)
+		if err != nil {
+			h.handleProjectUsecaseError(w, err, "code and name are required")
+			return
+		}
+
+		h.respondJSON(w, http.StatusCreated, category)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,85 @@
+package projects
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"admin_backend/internal/usecase"
+)
+
+type expensePayload struct {
+	CategoryID  string                  `json:"categoryId"`
+	Title       string                  `json:"title"`
+	Description string                  `json:"description"`
+	Supplier    string                  `json:"supplier"`
+	Amount      float64                 `json:"amount"`
+	IncurredOn  string                  `json:"incurredOn"`
+	Active      *bool                   `json:"active"`
+	Receipts    []revenueReceiptPayload `json:"receipts"`
+}
+
+func (h *Handler) handleProjectExpenses(w http.ResponseWriter, r *http.Request, projectID string) {
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := h.authorizeWithPermission(w, r, permissionProjectExpensesRead); !ok {
+			return
+		}
+
+		expenses, err := h.projectService.ListProjectExpenses(r.Context(), projectID)
+		if err != nil {
+			h.handleProjectUsecaseError(w, err, "")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, expenses)
+	case http.MethodPost:
+		if _, ok := h.authorizeWithPermission(w, r, permissionProjectExpensesCreate); !ok {
+			return
+		}
+
+		var payload expensePayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		incurredOn, err := parseOptionalDate(payload.IncurredOn)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid incurredOn")
+			return
+		}
+		receipts, err := mapExpenseReceiptPayloads(payload.Receipts)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid receipt issuedOn")
+			return
+		}
+
+		active := true
+		if payload.Active != nil {
+			active = *payload.Active
+		}
+
+		expense, err := h.projectService.CreateProjectExpense(
+			r.Context(),
+			usecase.CreateProjectExpenseInput{
+				ProjectID:   projectID,
+				CategoryID:  payload.CategoryID,
+				Title:       payload.Title,
+				Description: payload.Description,
+				Supplier:    payload.Supplier,
+				Amount:      payload.Amount,
+				IncurredOn:  incurredOn,
+				Active:      active,
+				Receipts:    receipts,
+			},
+		)
+		if err != nil {
+			h.handleProjectUsecaseError(w, err, "categoryId, title, amount and incurredOn are required")
+			return
+		}
+
+		h.respondJSON(w, http.StatusCreated, expense)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,74 @@
+package projects
+
+import (
+	"net/http"
+
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) handleProjectFinancialSummary(w http.ResponseWriter, r *http.Request, projectID string) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.authorizeWithPermission(w, r, permissionProjectFinancialsRead); !ok {
+		return
+	}
+
+	filter, ok := h.parseFinancialFilter(w, r)
+	if !ok {
+		return
+	}
+	filter.ProjectID = projectID
+
+	summary, err := h.projectService.GetProjectFinancialSummary(r.Context(), filter)
+	if err != nil {
+		h.handleProjectUsecaseError(w, err, "invalid period")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, summary)
+}
+
+func (h *Handler) HandleFinancialReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.authorizeWithPermission(w, r, permissionProjectFinancialsRead); !ok {
+		return
+	}
+
+	filter, ok := h.parseFinancialFilter(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.projectService.GetProjectFinancialReport(r.Context(), filter)
+	if err != nil {
+		h.handleProjectUsecaseError(w, err, "invalid period")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, report)
+}
+
+func (h *Handler) parseFinancialFilter(
+	w http.ResponseWriter,
+	r *http.Request,
+) (usecase.ProjectFinancialFilter, bool) {
+	from, err := parseOptionalMonth(r.URL.Query().Get("from"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid from query param")
+		return usecase.ProjectFinancialFilter{}, false
+	}
+	to, err := parseOptionalMonth(r.URL.Query().Get("to"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid to query param")
+		return usecase.ProjectFinancialFilter{}, false
+	}
+
+	return usecase.ProjectFinancialFilter{From: from, To: to}, true
+}
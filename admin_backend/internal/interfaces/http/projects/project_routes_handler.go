@@ -60,6 +60,10 @@ func (h *Handler) HandleProjectRoutes(w http.ResponseWriter, r *http.Request) {
 			h.handleProjectRevenueByID(w, r, projectID, resourceID)
 		case "monthly-charges":
 			h.handleProjectMonthlyChargeByID(w, r, projectID, resourceID)
+		case "expenses":
+			h.handleProjectExpenseByID(w, r, projectID, resourceID)
+		case "time-entries":
+			h.handleProjectTimeEntryByID(w, r, projectID, resourceID)
 		default:
 			h.respondError(w, http.StatusNotFound, "route not found")
 		}
@@ -88,6 +92,12 @@ func (h *Handler) HandleProjectRoutes(w http.ResponseWriter, r *http.Request) {
 		h.handleProjectPhases(w, r, projectID)
 	case "tasks":
 		h.handleProjectTasks(w, r, projectID)
+	case "expenses":
+		h.handleProjectExpenses(w, r, projectID)
+	case "time-entries":
+		h.handleProjectTimeEntries(w, r, projectID)
+	case "financial-summary":
+		h.handleProjectFinancialSummary(w, r, projectID)
 	default:
 		h.respondError(w, http.StatusNotFound, "route not found")
 	}
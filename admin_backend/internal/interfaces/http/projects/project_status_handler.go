@@ -14,7 +14,8 @@ func (h *Handler) handleProjectStatus(w http.ResponseWriter, r *http.Request, pr
 		return
 	}
 
-	if _, ok := h.authorizeWithPermission(w, r, permissionProjectsUpdate); !ok {
+	claims, ok := h.authorizeWithPermission(w, r, permissionProjectsUpdate)
+	if !ok {
 		return
 	}
 
@@ -33,8 +34,9 @@ func (h *Handler) handleProjectStatus(w http.ResponseWriter, r *http.Request, pr
 	project, err := h.projectService.UpdateProjectStatus(
 		r.Context(),
 		usecase.UpdateProjectStatusInput{
-			ID:     projectID,
-			Status: payload.Status,
+			ID:        projectID,
+			Status:    payload.Status,
+			UpdatedBy: claims.Sub,
 		},
 	)
 	if err != nil {
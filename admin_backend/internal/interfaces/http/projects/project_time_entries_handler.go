@@ -0,0 +1,97 @@
+package projects
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) handleProjectTimeEntries(w http.ResponseWriter, r *http.Request, projectID string) {
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := h.authorizeWithPermission(w, r, permissionProjectTimeEntriesRead); !ok {
+			return
+		}
+
+		entries, err := h.projectService.ListProjectTimeEntries(r.Context(), projectID)
+		if err != nil {
+			h.handleProjectUsecaseError(w, err, "")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, entries)
+	case http.MethodPost:
+		claims, ok := h.authorizeWithPermission(w, r, permissionProjectTimeEntriesCreate)
+		if !ok {
+			return
+		}
+
+		var payload struct {
+			ProjectTaskID string  `json:"projectTaskId"`
+			UserID        string  `json:"userId"`
+			WorkedOn      string  `json:"workedOn"`
+			Hours         float64 `json:"hours"`
+			Description   string  `json:"description"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		workedOn, err := parseOptionalDate(payload.WorkedOn)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid workedOn")
+			return
+		}
+
+		userID := strings.TrimSpace(payload.UserID)
+		if userID == "" {
+			userID = claims.Sub
+		}
+
+		entry, err := h.projectService.CreateProjectTimeEntry(
+			r.Context(),
+			usecase.CreateProjectTimeEntryInput{
+				ProjectID:     projectID,
+				ProjectTaskID: payload.ProjectTaskID,
+				UserID:        userID,
+				WorkedOn:      workedOn,
+				Hours:         payload.Hours,
+				Description:   payload.Description,
+			},
+		)
+		if err != nil {
+			h.handleProjectUsecaseError(w, err, "workedOn and hours between 0 and 24 are required")
+			return
+		}
+
+		h.respondJSON(w, http.StatusCreated, entry)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) handleProjectTimeEntryByID(
+	w http.ResponseWriter,
+	r *http.Request,
+	projectID string,
+	entryID string,
+) {
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.authorizeWithPermission(w, r, permissionProjectTimeEntriesUpdate); !ok {
+		return
+	}
+
+	if err := h.projectService.DeleteProjectTimeEntry(r.Context(), projectID, entryID); err != nil {
+		h.handleProjectUsecaseError(w, err, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -31,11 +31,12 @@ func (h *Handler) HandleProjectTypeByID(w http.ResponseWriter, r *http.Request)
 	}
 
 	var payload struct {
-		CategoryID  string `json:"categoryId"`
-		Code        string `json:"code"`
-		Name        string `json:"name"`
-		Description string `json:"description"`
-		Active      *bool  `json:"active"`
+		CategoryID  string   `json:"categoryId"`
+		Code        string   `json:"code"`
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		IssRate     *float64 `json:"issRate"`
+		Active      *bool    `json:"active"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		h.respondError(w, http.StatusBadRequest, "invalid json")
@@ -50,11 +51,12 @@ func (h *Handler) HandleProjectTypeByID(w http.ResponseWriter, r *http.Request)
 			Code:        payload.Code,
 			Name:        payload.Name,
 			Description: payload.Description,
+			IssRate:     payload.IssRate,
 			Active:      payload.Active,
 		},
 	)
 	if err != nil {
-		h.handleProjectUsecaseError(w, err, "categoryId, code and name are required")
+		h.handleProjectUsecaseError(w, err, "categoryId, code and name are required and issRate must be between 0 and 100")
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
@@ -35,7 +36,20 @@ func (h *Handler) handleProjectUsecaseError(
 		h.respondError(w, http.StatusBadRequest, "one or more clients do not exist")
 	case errors.Is(err, usecase.ErrProjectManagersNotFound):
 		h.respondError(w, http.StatusBadRequest, "one or more managers do not exist")
+	case errors.Is(err, usecase.ErrProjectExpenseCategoryCodeInUse):
+		h.respondError(w, http.StatusConflict, "expense category code already in use")
+	case errors.Is(err, usecase.ErrProjectExpenseCategoryNameInUse):
+		h.respondError(w, http.StatusConflict, "expense category name already in use")
+	case errors.Is(err, usecase.ErrProjectExpenseCategoryNotFound):
+		h.respondError(w, http.StatusBadRequest, "expense category not found")
+	case errors.Is(err, usecase.ErrUserHourlyRateInUse):
+		h.respondError(w, http.StatusConflict, "hourly rate already defined for this date")
+	case errors.Is(err, usecase.ErrProjectHasInvoices):
+		h.respondError(w, http.StatusConflict, "project data is referenced by issued invoices")
+	case errors.Is(err, usecase.ErrUserNotFound):
+		h.respondError(w, http.StatusBadRequest, "user not found")
 	default:
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 	}
 }
@@ -0,0 +1,95 @@
+package projects
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) HandleUserHourlyRates(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := h.authorizeWithPermission(w, r, permissionUserHourlyRatesRead); !ok {
+			return
+		}
+
+		rates, err := h.projectService.ListUserHourlyRates(r.Context(), r.URL.Query().Get("userId"))
+		if err != nil {
+			h.handleProjectUsecaseError(w, err, "")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, rates)
+	case http.MethodPost:
+		if _, ok := h.authorizeWithPermission(w, r, permissionUserHourlyRatesUpdate); !ok {
+			return
+		}
+
+		var payload struct {
+			UserID     string  `json:"userId"`
+			HourlyCost float64 `json:"hourlyCost"`
+			ValidFrom  string  `json:"validFrom"`
+			Notes      string  `json:"notes"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		validFrom, err := parseOptionalDate(payload.ValidFrom)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid validFrom")
+			return
+		}
+
+		rate, err := h.projectService.CreateUserHourlyRate(
+			r.Context(),
+			usecase.CreateUserHourlyRateInput{
+				UserID:     payload.UserID,
+				HourlyCost: payload.HourlyCost,
+				ValidFrom:  validFrom,
+				Notes:      payload.Notes,
+			},
+		)
+		if err != nil {
+			h.handleProjectUsecaseError(w, err, "userId, hourlyCost and validFrom are required")
+			return
+		}
+
+		h.respondJSON(w, http.StatusCreated, rate)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) HandleUserHourlyRateByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	trimmedPath := strings.TrimPrefix(r.URL.Path, "/user-hourly-rates/")
+	if trimmedPath == "" || strings.Contains(trimmedPath, "/") {
+		h.respondError(w, http.StatusNotFound, "hourly rate not found")
+		return
+	}
+
+	rateID := strings.TrimSpace(trimmedPath)
+	if rateID == "" {
+		h.respondError(w, http.StatusNotFound, "hourly rate not found")
+		return
+	}
+
+	if _, ok := h.authorizeWithPermission(w, r, permissionUserHourlyRatesUpdate); !ok {
+		return
+	}
+
+	if err := h.projectService.DeleteUserHourlyRate(r.Context(), rateID); err != nil {
+		h.handleProjectUsecaseError(w, err, "")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,234 @@
+// Package ratelimit applies the per-route rate limit policies and reports
+// them with the RateLimit-* headers of the IETF draft.
+package ratelimit
+
+import (
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	infraauth "admin_backend/internal/infra/auth"
+	"admin_backend/internal/infra/metrics"
+	infraratelimit "admin_backend/internal/infra/ratelimit"
+	"admin_backend/internal/interfaces/http/requestlog"
+)
+
+// Key selects whose bucket a request takes from.
+type Key int
+
+const (
+	// ByIP limits each client address; IPv6 addresses share their /64.
+	ByIP Key = iota
+	// ByUser limits each signed-in admin user, and anonymous requests by IP.
+	ByUser
+	// ByClient limits each signed-in portal client, and anonymous requests
+	// by IP.
+	ByClient
+)
+
+// Policy limits the requests matching Paths and Methods.
+type Policy struct {
+	Name string
+	// Paths match exactly, or as a prefix when they end with "/".
+	Paths []string
+	// Methods limited; empty means every method but OPTIONS, which CORS
+	// preflights use.
+	Methods []string
+	Key     Key
+	Limit   infraratelimit.Limit
+}
+
+func (p Policy) matches(r *http.Request) bool {
+	if len(p.Methods) == 0 {
+		if r.Method == http.MethodOptions {
+			return false
+		}
+	} else if !containsFold(p.Methods, r.Method) {
+		return false
+	}
+
+	for _, path := range p.Paths {
+		if r.URL.Path == path || (strings.HasSuffix(path, "/") && strings.HasPrefix(r.URL.Path, path)) {
+			return true
+		}
+	}
+	return false
+}
+
+type Limiter struct {
+	store          infraratelimit.Store
+	tokens         *infraauth.TokenManager
+	trustedProxies []netip.Prefix
+	policies       []Policy
+}
+
+// New builds a limiter taking from store. tokens identifies signed-in users
+// or clients; trustedProxies are the load balancers whose X-Forwarded-For
+// header is believed. The first policy matching a request applies.
+func New(
+	store infraratelimit.Store,
+	tokens *infraauth.TokenManager,
+	trustedProxies []netip.Prefix,
+	policies ...Policy,
+) *Limiter {
+	return &Limiter{
+		store:          store,
+		tokens:         tokens,
+		trustedProxies: trustedProxies,
+		policies:       policies,
+	}
+}
+
+// Middleware answers 429 once a policy is exhausted. When the store fails,
+// requests are let through rather than taking the API down with it.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		policy, ok := l.policyFor(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		decision, err := l.store.Take(r.Context(), policy.Name+":"+l.key(r, policy.Key), policy.Limit)
+		if err != nil {
+			slog.WarnContext(r.Context(), "rate limit store unavailable; request allowed", "policy", policy.Name, "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Set("RateLimit-Policy", policyHeader(policy.Limit))
+		// The limit is the bucket capacity, so Remaining never exceeds it;
+		// the policy header already tells the refill rate.
+		header.Set("RateLimit-Limit", strconv.Itoa(policy.Limit.Burst))
+		header.Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+		if decision.Allowed {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		metrics.RateLimited.Inc(policy.Name)
+		header.Set("Retry-After", strconv.Itoa(max(ceilSeconds(decision.RetryAfter), 1)))
+		payload := map[string]string{"error": "too many requests"}
+		if requestID := requestlog.RequestID(w); requestID != "" {
+			payload["requestId"] = requestID
+		}
+		header.Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_ = json.NewEncoder(w).Encode(payload)
+	})
+}
+
+func (l *Limiter) policyFor(r *http.Request) (Policy, bool) {
+	for _, policy := range l.policies {
+		if policy.matches(r) {
+			return policy, true
+		}
+	}
+	return Policy{}, false
+}
+
+// key names the bucket of the caller. Only a valid token for this service
+// identifies a user or client, so a forged one is limited by IP.
+func (l *Limiter) key(r *http.Request, key Key) string {
+	if key == ByUser || key == ByClient {
+		if subject, audience, ok := l.subject(r); ok {
+			switch {
+			case key == ByUser && audience == infraauth.AudienceAdmin:
+				return "user:" + subject
+			case key == ByClient && audience == infraauth.AudienceClient:
+				return "client:" + subject
+			}
+		}
+	}
+	return "ip:" + l.clientIP(r)
+}
+
+func (l *Limiter) subject(r *http.Request) (string, string, bool) {
+	if l.tokens == nil {
+		return "", "", false
+	}
+	authHeader := strings.TrimSpace(r.Header.Get("Authorization"))
+	if len(authHeader) < 7 || !strings.EqualFold(authHeader[:7], "Bearer ") {
+		return "", "", false
+	}
+	claims, err := l.tokens.ParseAndValidate(authHeader[7:], time.Now())
+	if err != nil || claims.Sub == "" {
+		return "", "", false
+	}
+	return claims.Sub, claims.Audience, true
+}
+
+// clientIP is the connecting address, or, when that is a trusted proxy, the
+// right-most X-Forwarded-For entry that is not a trusted proxy itself.
+func (l *Limiter) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return host
+	}
+
+	if l.trusted(addr) {
+		forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+		for index := len(forwarded) - 1; index >= 0; index-- {
+			hop, err := netip.ParseAddr(strings.TrimSpace(forwarded[index]))
+			if err != nil {
+				break
+			}
+			addr = hop
+			if !l.trusted(hop) {
+				break
+			}
+		}
+	}
+
+	addr = addr.Unmap()
+	if addr.Is6() {
+		prefix, _ := addr.Prefix(64)
+		return prefix.String()
+	}
+	return addr.String()
+}
+
+func (l *Limiter) trusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range l.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyHeader describes the quota as "10;w=60", adding the burst when it
+// differs from the quota.
+func policyHeader(limit infraratelimit.Limit) string {
+	value := strconv.Itoa(limit.Requests) + ";w=" + strconv.Itoa(ceilSeconds(limit.Per))
+	if limit.Burst != limit.Requests {
+		value += ";burst=" + strconv.Itoa(limit.Burst)
+	}
+	return value
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+// Package requestlog assigns request IDs and, for every request, writes one
+// access log record, updates the HTTP metrics and records the server span.
+package requestlog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"admin_backend/internal/infra/logging"
+	"admin_backend/internal/infra/metrics"
+	"admin_backend/internal/infra/tracing"
+)
+
+const HeaderRequestID = "X-Request-ID"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// Middleware honors a well-formed incoming X-Request-ID or generates one,
+// echoes it on the response and logs method, route, status, latency and the
+// authenticated user or client once the request completes. Requests carrying
+// a W3C traceparent header continue the caller's trace. The route in the
+// log, the span name and the metric labels is the pattern of mux matching
+// the request, or "unmatched"; the raw path is only logged, as path.
+func Middleware(logger *slog.Logger, mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startedAt := time.Now()
+
+		requestID := r.Header.Get(HeaderRequestID)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+		w.Header().Set(HeaderRequestID, requestID)
+
+		route := matchedRoute(mux, r)
+		ctx := tracing.Extract(r.Context(), r.Header)
+		ctx, span := tracing.Start(ctx, r.Method+" "+route, tracing.KindServer)
+		defer span.End()
+		span.SetAttribute("http.request.method", r.Method)
+		span.SetAttribute("http.route", route)
+		span.SetAttribute("request.id", requestID)
+
+		info := &logging.RequestInfo{ID: requestID, Route: route}
+		ctx = logging.WithRequestInfo(ctx, info)
+		recorder := &responseRecorder{ResponseWriter: w, info: info, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		latency := time.Since(startedAt)
+		metrics.HTTPRequests.Inc(r.Method, route, strconv.Itoa(recorder.status))
+		metrics.HTTPRequestDuration.Observe(latency.Seconds(), r.Method, route)
+
+		userID, clientID, err := info.Snapshot()
+		span.SetAttribute("http.response.status_code", recorder.status)
+		if err != nil {
+			span.RecordError(err)
+		} else if recorder.status >= http.StatusInternalServerError {
+			span.SetError(http.StatusText(recorder.status))
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("route", route),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", recorder.status),
+			slog.Int64("latency_ms", latency.Milliseconds()),
+			slog.Int("bytes", recorder.bytes),
+		}
+		if traceID := tracing.TraceID(ctx); traceID != "" {
+			attrs = append(attrs, slog.String("trace_id", traceID))
+		}
+		if userID != "" {
+			attrs = append(attrs, slog.String("user_id", userID))
+		}
+		if clientID != "" {
+			attrs = append(attrs, slog.String("client_id", clientID))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case recorder.status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case recorder.status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+
+		logger.LogAttrs(ctx, level, "http request", attrs...)
+	})
+}
+
+// RecordError attaches the cause of a generic error response to the access
+// log of the request being written by w.
+func RecordError(w http.ResponseWriter, err error) {
+	if recorder, ok := w.(*responseRecorder); ok {
+		recorder.info.RecordError(err)
+	}
+}
+
+// RequestID returns the ID assigned to the response being written by w.
+func RequestID(w http.ResponseWriter) string {
+	return w.Header().Get(HeaderRequestID)
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	info        *logging.RequestInfo
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(body []byte) (int, error) {
+	r.wroteHeader = true
+	written, err := r.ResponseWriter.Write(body)
+	r.bytes += written
+	return written, err
+}
+
+// Flush keeps Server-Sent Event streams working through the recorder.
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func newRequestID() string {
+	buffer := make([]byte, 16)
+	if _, err := rand.Read(buffer); err != nil {
+		return time.Now().UTC().Format("20060102T150405.000000000")
+	}
+	return hex.EncodeToString(buffer)
+}
@@ -0,0 +1,16 @@
+package requestlog
+
+import "net/http"
+
+// routeUnmatched labels requests no route pattern matches, so scanners
+// probing random URLs do not create a metric series or span name per path.
+const routeUnmatched = "unmatched"
+
+// matchedRoute returns the pattern mux serves r with, e.g.
+// "/clients/details/", which keeps identifiers out of labels.
+func matchedRoute(mux *http.ServeMux, r *http.Request) string {
+	if _, pattern := mux.Handler(r); pattern != "" {
+		return pattern
+	}
+	return routeUnmatched
+}
@@ -3,6 +3,8 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+
+	"admin_backend/internal/interfaces/http/requestlog"
 )
 
 func respondJSON(w http.ResponseWriter, status int, payload interface{}) {
@@ -11,6 +13,12 @@ func respondJSON(w http.ResponseWriter, status int, payload interface{}) {
 	_ = json.NewEncoder(w).Encode(payload)
 }
 
+// respondError includes the request ID so support can find the matching
+// access log record.
 func respondError(w http.ResponseWriter, status int, message string) {
-	respondJSON(w, status, map[string]string{"error": message})
+	payload := map[string]string{"error": message}
+	if requestID := requestlog.RequestID(w); requestID != "" {
+		payload["requestId"] = requestID
+	}
+	respondJSON(w, status, payload)
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
@@ -29,6 +30,7 @@ func (h *Handler) HandlePermissionByID(w http.ResponseWriter, r *http.Request) {
 			case errors.Is(err, usecase.ErrNotFound):
 				h.respondError(w, http.StatusNotFound, "permission not found")
 			default:
+				requestlog.RecordError(w, err)
 				h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			}
 			return
@@ -70,6 +72,7 @@ func (h *Handler) HandlePermissionByID(w http.ResponseWriter, r *http.Request) {
 			case errors.Is(err, usecase.ErrPermissionNameInUse):
 				h.respondError(w, http.StatusConflict, "permission name already in use")
 			default:
+				requestlog.RecordError(w, err)
 				h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			}
 			return
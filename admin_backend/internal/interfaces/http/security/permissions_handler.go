@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
@@ -18,6 +19,7 @@ func (h *Handler) HandlePermissions(w http.ResponseWriter, r *http.Request) {
 
 		permissions, err := h.securityService.ListPermissions(r.Context())
 		if err != nil {
+			requestlog.RecordError(w, err)
 			h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			return
 		}
@@ -60,6 +62,7 @@ func (h *Handler) HandlePermissions(w http.ResponseWriter, r *http.Request) {
 			case errors.Is(err, usecase.ErrPermissionNameInUse):
 				h.respondError(w, http.StatusConflict, "permission name already in use")
 			default:
+				requestlog.RecordError(w, err)
 				h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			}
 			return
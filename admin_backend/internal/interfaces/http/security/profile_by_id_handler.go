@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
@@ -45,6 +46,7 @@ func (h *Handler) HandleProfileByID(w http.ResponseWriter, r *http.Request) {
 			case errors.Is(err, usecase.ErrNotFound):
 				h.respondError(w, http.StatusNotFound, "profile not found")
 			default:
+				requestlog.RecordError(w, err)
 				h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			}
 			return
@@ -59,6 +61,7 @@ func (h *Handler) HandleProfileByID(w http.ResponseWriter, r *http.Request) {
 		}
 		isAdministrator, err := h.isUserAdministrator(r.Context(), claims.Sub)
 		if err != nil {
+			requestlog.RecordError(w, err)
 			h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			return
 		}
@@ -92,6 +95,7 @@ func (h *Handler) HandleProfileByID(w http.ResponseWriter, r *http.Request) {
 			case errors.Is(err, usecase.ErrProfileNameInUse):
 				h.respondError(w, http.StatusConflict, "profile name already in use")
 			default:
+				requestlog.RecordError(w, err)
 				h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			}
 			return
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
@@ -18,6 +19,7 @@ func (h *Handler) handleProfilePermissions(w http.ResponseWriter, r *http.Reques
 
 		permissionIDs, err := h.securityService.ListProfilePermissionIDs(r.Context(), profileID)
 		if err != nil {
+			requestlog.RecordError(w, err)
 			h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			return
 		}
@@ -34,6 +36,7 @@ func (h *Handler) handleProfilePermissions(w http.ResponseWriter, r *http.Reques
 		}
 		isAdministrator, err := h.isUserAdministrator(r.Context(), claims.Sub)
 		if err != nil {
+			requestlog.RecordError(w, err)
 			h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			return
 		}
@@ -64,6 +67,7 @@ func (h *Handler) handleProfilePermissions(w http.ResponseWriter, r *http.Reques
 			case errors.Is(err, usecase.ErrInvalidInput):
 				h.respondError(w, http.StatusBadRequest, "invalid input")
 			default:
+				requestlog.RecordError(w, err)
 				h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			}
 			return
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
@@ -18,6 +19,7 @@ func (h *Handler) HandleProfiles(w http.ResponseWriter, r *http.Request) {
 
 		profiles, err := h.securityService.ListProfiles(r.Context())
 		if err != nil {
+			requestlog.RecordError(w, err)
 			h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			return
 		}
@@ -56,6 +58,7 @@ func (h *Handler) HandleProfiles(w http.ResponseWriter, r *http.Request) {
 			case errors.Is(err, usecase.ErrProfileNameInUse):
 				h.respondError(w, http.StatusConflict, "profile name already in use")
 			default:
+				requestlog.RecordError(w, err)
 				h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			}
 			return
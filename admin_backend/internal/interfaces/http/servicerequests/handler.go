@@ -1,36 +1,51 @@
 package servicerequests
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"strings"
 
 	infraauth "admin_backend/internal/infra/auth"
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
 type Handler struct {
 	clientPortalService *usecase.ClientPortalService
+	conversionService   *usecase.ServiceRequestConversionService
 	authorizeRequest    func(r *http.Request) (infraauth.Claims, error)
+	hasUserPermission   func(ctx context.Context, userID, permissionCode string) (bool, error)
 	respondJSON         func(w http.ResponseWriter, status int, payload interface{})
 	respondError        func(w http.ResponseWriter, status int, message string)
 }
 
 func NewHandler(
 	clientPortalService *usecase.ClientPortalService,
+	conversionService *usecase.ServiceRequestConversionService,
 	authorizeRequest func(r *http.Request) (infraauth.Claims, error),
+	hasUserPermission func(ctx context.Context, userID, permissionCode string) (bool, error),
 	respondJSON func(w http.ResponseWriter, status int, payload interface{}),
 	respondError func(w http.ResponseWriter, status int, message string),
 ) *Handler {
 	return &Handler{
 		clientPortalService: clientPortalService,
+		conversionService:   conversionService,
 		authorizeRequest:    authorizeRequest,
+		hasUserPermission:   hasUserPermission,
 		respondJSON:         respondJSON,
 		respondError:        respondError,
 	}
 }
 
+// Converting a request creates project work, so it needs the same
+// permissions as creating it directly.
+const (
+	permissionProjectsCreate     = "projects.create"
+	permissionProjectTasksCreate = "project_tasks.create"
+)
+
 type relatedFilePayload struct {
 	FileName    string `json:"fileName"`
 	FileKey     string `json:"fileKey"`
@@ -48,7 +63,7 @@ func (h *Handler) HandleServiceRequests(w http.ResponseWriter, r *http.Request)
 	trimmedPath := strings.TrimPrefix(r.URL.Path, "/service-requests")
 	trimmedPath = strings.Trim(trimmedPath, "/")
 	if trimmedPath == "" {
-		h.handleServiceRequestCollection(w, r)
+		h.handleServiceRequestCollection(w, r, claims.Sub)
 		return
 	}
 
@@ -60,12 +75,22 @@ func (h *Handler) HandleServiceRequests(w http.ResponseWriter, r *http.Request)
 	}
 
 	if len(segments) == 1 {
-		h.handleServiceRequestByID(w, r, requestID)
+		h.handleServiceRequestByID(w, r, requestID, claims.Sub)
 		return
 	}
 
 	if len(segments) == 2 && strings.EqualFold(strings.TrimSpace(segments[1]), "status") {
-		h.handleServiceRequestStatus(w, r, requestID)
+		h.handleServiceRequestStatus(w, r, requestID, claims.Sub)
+		return
+	}
+
+	if len(segments) == 2 && strings.EqualFold(strings.TrimSpace(segments[1]), "convert") {
+		h.handleServiceRequestConversion(w, r, requestID, claims.Sub)
+		return
+	}
+
+	if len(segments) == 2 && strings.EqualFold(strings.TrimSpace(segments[1]), "history") {
+		h.handleServiceRequestHistory(w, r, requestID)
 		return
 	}
 
@@ -100,15 +125,36 @@ func (h *Handler) HandleServiceRequests(w http.ResponseWriter, r *http.Request)
 	h.respondError(w, http.StatusNotFound, "route not found")
 }
 
-func (h *Handler) handleServiceRequestCollection(w http.ResponseWriter, r *http.Request) {
+// handleServiceRequestCollection accepts assignedUserId=me for the requests
+// assigned to the caller and assignedUserId=none for the unassigned ones.
+func (h *Handler) handleServiceRequestCollection(
+	w http.ResponseWriter,
+	r *http.Request,
+	userID string,
+) {
 	if r.Method != http.MethodGet {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
 
-	requests, err := h.clientPortalService.ListAdminServiceRequests(r.Context())
+	query := r.URL.Query()
+	assignedUserID := strings.TrimSpace(query.Get("assignedUserId"))
+	if strings.EqualFold(assignedUserID, "me") {
+		assignedUserID = userID
+	}
+
+	requests, err := h.clientPortalService.ListAdminServiceRequests(
+		r.Context(),
+		usecase.AdminServiceRequestFilter{
+			Sort:           query.Get("sort"),
+			Status:         query.Get("status"),
+			Priority:       query.Get("priority"),
+			CategoryID:     query.Get("categoryId"),
+			AssignedUserID: assignedUserID,
+		},
+	)
 	if err != nil {
-		h.handleUsecaseError(w, err, "")
+		h.handleUsecaseError(w, err, "invalid filters")
 		return
 	}
 
@@ -119,25 +165,149 @@ func (h *Handler) handleServiceRequestByID(
 	w http.ResponseWriter,
 	r *http.Request,
 	requestID string,
+	userID string,
+) {
+	switch r.Method {
+	case http.MethodGet:
+		request, err := h.clientPortalService.GetAdminServiceRequest(r.Context(), requestID)
+		if err != nil {
+			h.handleUsecaseError(w, err, "")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, request)
+	case http.MethodPatch:
+		var payload struct {
+			AssignedUserID *string `json:"assignedUserId"`
+			Priority       *string `json:"priority"`
+			CategoryID     *string `json:"categoryId"`
+		}
+		if err := decodeJSONBody(r, &payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		request, err := h.clientPortalService.UpdateAdminServiceRequest(
+			r.Context(),
+			usecase.UpdateAdminServiceRequestInput{
+				RequestID:      requestID,
+				AssignedUserID: payload.AssignedUserID,
+				Priority:       payload.Priority,
+				CategoryID:     payload.CategoryID,
+			},
+			userID,
+		)
+		if err != nil {
+			h.handleUsecaseError(w, err, "priority must be baixa, media, alta or urgente")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, request)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// handleServiceRequestConversion serves POST /service-requests/{id}/convert,
+// which creates a task in the request project ("task") or a new project for
+// the client ("project").
+func (h *Handler) handleServiceRequestConversion(
+	w http.ResponseWriter,
+	r *http.Request,
+	requestID string,
+	userID string,
+) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var payload struct {
+		Target            string `json:"target"`
+		ProjectPhaseID    string `json:"projectPhaseId"`
+		ResponsibleUserID string `json:"responsibleUserId"`
+		ProjectName       string `json:"projectName"`
+		ProjectTypeID     string `json:"projectTypeId"`
+	}
+	if err := decodeJSONBody(r, &payload); err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	requiredPermissions := []string{permissionProjectTasksCreate}
+	if strings.EqualFold(strings.TrimSpace(payload.Target), usecase.ServiceRequestConversionProject) {
+		requiredPermissions = append(requiredPermissions, permissionProjectsCreate)
+	}
+	for _, permissionCode := range requiredPermissions {
+		allowed, err := h.hasUserPermission(r.Context(), userID, permissionCode)
+		if err != nil {
+			requestlog.RecordError(w, err)
+			h.respondError(w, http.StatusInternalServerError, "unexpected error")
+			return
+		}
+		if !allowed {
+			h.respondError(w, http.StatusForbidden, "forbidden")
+			return
+		}
+	}
+
+	conversion, err := h.conversionService.ConvertServiceRequest(
+		r.Context(),
+		usecase.ConvertServiceRequestInput{
+			RequestID:         requestID,
+			Target:            payload.Target,
+			ProjectPhaseID:    payload.ProjectPhaseID,
+			ResponsibleUserID: payload.ResponsibleUserID,
+			ProjectName:       payload.ProjectName,
+			ProjectTypeID:     payload.ProjectTypeID,
+			ConvertedByUserID: userID,
+		},
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrServiceRequestAlreadyConverted),
+			errors.Is(err, usecase.ErrServiceRequestWithoutProject):
+			h.respondError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, usecase.ErrProjectNameInUse):
+			h.respondError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, usecase.ErrProjectTypeNotFound),
+			errors.Is(err, usecase.ErrProjectManagersNotFound):
+			h.respondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, usecase.ErrConflict):
+			h.respondError(w, http.StatusConflict, "closed requests cannot be converted")
+		default:
+			h.handleUsecaseError(w, err, "target must be task or project")
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, conversion)
+}
+
+func (h *Handler) handleServiceRequestHistory(
+	w http.ResponseWriter,
+	r *http.Request,
+	requestID string,
 ) {
 	if r.Method != http.MethodGet {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
 
-	request, err := h.clientPortalService.GetAdminServiceRequest(r.Context(), requestID)
+	history, err := h.clientPortalService.ListServiceRequestStatusHistory(r.Context(), requestID)
 	if err != nil {
 		h.handleUsecaseError(w, err, "")
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, request)
+	h.respondJSON(w, http.StatusOK, history)
 }
 
 func (h *Handler) handleServiceRequestStatus(
 	w http.ResponseWriter,
 	r *http.Request,
 	requestID string,
+	userID string,
 ) {
 	if r.Method != http.MethodPatch {
 		w.WriteHeader(http.StatusMethodNotAllowed)
@@ -146,6 +316,7 @@ func (h *Handler) handleServiceRequestStatus(
 
 	var payload struct {
 		Status string `json:"status"`
+		Note   string `json:"note"`
 	}
 	if err := decodeJSONBody(r, &payload); err != nil {
 		h.respondError(w, http.StatusBadRequest, "invalid json")
@@ -155,12 +326,14 @@ func (h *Handler) handleServiceRequestStatus(
 	request, err := h.clientPortalService.UpdateAdminServiceRequestStatus(
 		r.Context(),
 		usecase.UpdateAdminServiceRequestStatusInput{
-			RequestID: requestID,
-			Status:    payload.Status,
+			RequestID:       requestID,
+			Status:          payload.Status,
+			Note:            payload.Note,
+			ChangedByUserID: userID,
 		},
 	)
 	if err != nil {
-		h.handleUsecaseError(w, err, "status must be concluida or cancelada")
+		h.handleUsecaseError(w, err, "invalid status")
 		return
 	}
 
@@ -314,9 +487,18 @@ func (h *Handler) handleUsecaseError(
 		h.respondError(w, http.StatusForbidden, "forbidden")
 	case errors.Is(err, usecase.ErrNotFound):
 		h.respondError(w, http.StatusNotFound, "resource not found")
+	case errors.Is(err, usecase.ErrServiceRequestStatusNotFound):
+		h.respondError(w, http.StatusBadRequest, "status not found")
+	case errors.Is(err, usecase.ErrUserNotFound):
+		h.respondError(w, http.StatusBadRequest, "assigned user not found")
+	case errors.Is(err, usecase.ErrServiceRequestCategoryNotFound):
+		h.respondError(w, http.StatusBadRequest, "category not found")
+	case errors.Is(err, usecase.ErrServiceRequestTransitionNotAllowed):
+		h.respondError(w, http.StatusConflict, "status transition not allowed")
 	case errors.Is(err, usecase.ErrConflict):
 		h.respondError(w, http.StatusConflict, "conflict")
 	default:
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 	}
 }
@@ -0,0 +1,111 @@
+package servicerequestsettings
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"admin_backend/internal/usecase"
+)
+
+type categoryPayload struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Active      *bool  `json:"active"`
+}
+
+// HandleCategories serves GET and POST /service-request-categories.
+func (h *Handler) HandleCategories(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if _, err := h.authorizeRequest(r); err != nil {
+			h.respondError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		categories, err := h.settingsService.ListCategories(r.Context())
+		if err != nil {
+			h.handleSettingsUsecaseError(w, err, "")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, categories)
+	case http.MethodPost:
+		if _, ok := h.authorizeWithPermission(w, r, permissionCategoriesCreate); !ok {
+			return
+		}
+
+		var payload categoryPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		active := true
+		if payload.Active != nil {
+			active = *payload.Active
+		}
+
+		category, err := h.settingsService.CreateCategory(r.Context(), usecase.CreateServiceRequestCategoryInput{
+			Name:        payload.Name,
+			Description: payload.Description,
+			Active:      active,
+		})
+		if err != nil {
+			h.handleSettingsUsecaseError(w, err, "name is required")
+			return
+		}
+
+		h.respondJSON(w, http.StatusCreated, category)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleCategoryRoutes serves PATCH and DELETE /service-request-categories/{id}.
+func (h *Handler) HandleCategoryRoutes(w http.ResponseWriter, r *http.Request) {
+	categoryID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/service-request-categories/"), "/")
+	if categoryID == "" || strings.Contains(categoryID, "/") {
+		h.respondError(w, http.StatusNotFound, "service request category not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPatch:
+		if _, ok := h.authorizeWithPermission(w, r, permissionCategoriesUpdate); !ok {
+			return
+		}
+
+		var payload categoryPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		category, err := h.settingsService.UpdateCategory(r.Context(), usecase.UpdateServiceRequestCategoryInput{
+			ID:          categoryID,
+			Name:        payload.Name,
+			Description: payload.Description,
+			Active:      payload.Active,
+		})
+		if err != nil {
+			h.handleSettingsUsecaseError(w, err, "name is required")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, category)
+	case http.MethodDelete:
+		if _, ok := h.authorizeWithPermission(w, r, permissionCategoriesDelete); !ok {
+			return
+		}
+
+		if err := h.settingsService.DeleteCategory(r.Context(), categoryID); err != nil {
+			h.handleSettingsUsecaseError(w, err, "")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
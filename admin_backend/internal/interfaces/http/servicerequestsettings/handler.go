@@ -0,0 +1,42 @@
+package servicerequestsettings
+
+import (
+	"context"
+	"net/http"
+
+	infraauth "admin_backend/internal/infra/auth"
+	"admin_backend/internal/usecase"
+)
+
+type Handler struct {
+	settingsService   *usecase.ServiceRequestSettingsService
+	authorizeRequest  func(r *http.Request) (infraauth.Claims, error)
+	hasUserPermission func(ctx context.Context, userID, permissionCode string) (bool, error)
+	respondJSON       func(w http.ResponseWriter, status int, payload interface{})
+	respondError      func(w http.ResponseWriter, status int, message string)
+}
+
+func NewHandler(
+	settingsService *usecase.ServiceRequestSettingsService,
+	authorizeRequest func(r *http.Request) (infraauth.Claims, error),
+	hasUserPermission func(ctx context.Context, userID, permissionCode string) (bool, error),
+	respondJSON func(w http.ResponseWriter, status int, payload interface{}),
+	respondError func(w http.ResponseWriter, status int, message string),
+) *Handler {
+	return &Handler{
+		settingsService:   settingsService,
+		authorizeRequest:  authorizeRequest,
+		hasUserPermission: hasUserPermission,
+		respondJSON:       respondJSON,
+		respondError:      respondError,
+	}
+}
+
+const (
+	permissionStatusesCreate   = "service_request_statuses.create"
+	permissionStatusesUpdate   = "service_request_statuses.update"
+	permissionStatusesDelete   = "service_request_statuses.delete"
+	permissionCategoriesCreate = "service_request_categories.create"
+	permissionCategoriesUpdate = "service_request_categories.update"
+	permissionCategoriesDelete = "service_request_categories.delete"
+)
@@ -0,0 +1,141 @@
+package servicerequestsettings
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"admin_backend/internal/usecase"
+)
+
+const statusInvalidInputMessage = "code must start with a letter and use only lowercase letters, digits and underscores, name is required, reopenWindowDays must be positive and transitions must reference other statuses"
+
+type statusPayload struct {
+	Code             string   `json:"code"`
+	Name             string   `json:"name"`
+	Description      string   `json:"description"`
+	Closed           bool     `json:"closed"`
+	ReopenWindowDays *int     `json:"reopenWindowDays"`
+	Position         int      `json:"position"`
+	Active           *bool    `json:"active"`
+	Transitions      []string `json:"transitions"`
+}
+
+// HandleStatuses serves GET and POST /service-request-statuses. Every admin
+// can read the workflow, since it drives the status changes they can make.
+func (h *Handler) HandleStatuses(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if _, err := h.authorizeRequest(r); err != nil {
+			h.respondError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		statuses, err := h.settingsService.ListStatuses(r.Context())
+		if err != nil {
+			h.handleSettingsUsecaseError(w, err, "")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, statuses)
+	case http.MethodPost:
+		if _, ok := h.authorizeWithPermission(w, r, permissionStatusesCreate); !ok {
+			return
+		}
+
+		var payload statusPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		active := true
+		if payload.Active != nil {
+			active = *payload.Active
+		}
+
+		status, err := h.settingsService.CreateStatus(r.Context(), usecase.CreateServiceRequestStatusInput{
+			Code:             payload.Code,
+			Name:             payload.Name,
+			Description:      payload.Description,
+			Closed:           payload.Closed,
+			ReopenWindowDays: payload.ReopenWindowDays,
+			Position:         payload.Position,
+			Active:           active,
+			Transitions:      payload.Transitions,
+		})
+		if err != nil {
+			h.handleSettingsUsecaseError(w, err, statusInvalidInputMessage)
+			return
+		}
+
+		h.respondJSON(w, http.StatusCreated, status)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleStatusRoutes serves GET, PATCH and DELETE /service-request-statuses/{code}.
+func (h *Handler) HandleStatusRoutes(w http.ResponseWriter, r *http.Request) {
+	code := strings.Trim(strings.TrimPrefix(r.URL.Path, "/service-request-statuses/"), "/")
+	if code == "" || strings.Contains(code, "/") {
+		h.respondError(w, http.StatusNotFound, "service request status not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if _, err := h.authorizeRequest(r); err != nil {
+			h.respondError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		status, err := h.settingsService.GetStatus(r.Context(), code)
+		if err != nil {
+			h.handleSettingsUsecaseError(w, err, "")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, status)
+	case http.MethodPatch:
+		if _, ok := h.authorizeWithPermission(w, r, permissionStatusesUpdate); !ok {
+			return
+		}
+
+		var payload statusPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		status, err := h.settingsService.UpdateStatus(r.Context(), usecase.UpdateServiceRequestStatusInput{
+			Code:             code,
+			Name:             payload.Name,
+			Description:      payload.Description,
+			Closed:           payload.Closed,
+			ReopenWindowDays: payload.ReopenWindowDays,
+			Position:         payload.Position,
+			Active:           payload.Active,
+			Transitions:      payload.Transitions,
+		})
+		if err != nil {
+			h.handleSettingsUsecaseError(w, err, statusInvalidInputMessage)
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, status)
+	case http.MethodDelete:
+		if _, ok := h.authorizeWithPermission(w, r, permissionStatusesDelete); !ok {
+			return
+		}
+
+		if err := h.settingsService.DeleteStatus(r.Context(), code); err != nil {
+			h.handleSettingsUsecaseError(w, err, "")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,38 @@
+package servicerequestsettings
+
+import (
+	"errors"
+	"net/http"
+
+	"admin_backend/internal/interfaces/http/requestlog"
+	"admin_backend/internal/usecase"
+)
+
+func (h *Handler) handleSettingsUsecaseError(
+	w http.ResponseWriter,
+	err error,
+	defaultInvalidInputMessage string,
+) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidInput):
+		message := defaultInvalidInputMessage
+		if message == "" {
+			message = "invalid input"
+		}
+		h.respondError(w, http.StatusBadRequest, message)
+	case errors.Is(err, usecase.ErrServiceRequestStatusCodeInUse),
+		errors.Is(err, usecase.ErrServiceRequestStatusNameInUse),
+		errors.Is(err, usecase.ErrServiceRequestStatusInUse),
+		errors.Is(err, usecase.ErrServiceRequestStatusProtected),
+		errors.Is(err, usecase.ErrServiceRequestCategoryNameInUse):
+		h.respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, usecase.ErrServiceRequestStatusNotFound),
+		errors.Is(err, usecase.ErrServiceRequestCategoryNotFound):
+		h.respondError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, usecase.ErrNotFound):
+		h.respondError(w, http.StatusNotFound, "resource not found")
+	default:
+		requestlog.RecordError(w, err)
+		h.respondError(w, http.StatusInternalServerError, "unexpected error")
+	}
+}
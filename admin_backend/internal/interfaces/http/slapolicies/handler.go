@@ -0,0 +1,40 @@
+package slapolicies
+
+import (
+	"context"
+	"net/http"
+
+	infraauth "admin_backend/internal/infra/auth"
+	"admin_backend/internal/usecase"
+)
+
+type Handler struct {
+	slaService        *usecase.SLAService
+	authorizeRequest  func(r *http.Request) (infraauth.Claims, error)
+	hasUserPermission func(ctx context.Context, userID, permissionCode string) (bool, error)
+	respondJSON       func(w http.ResponseWriter, status int, payload interface{})
+	respondError      func(w http.ResponseWriter, status int, message string)
+}
+
+func NewHandler(
+	slaService *usecase.SLAService,
+	authorizeRequest func(r *http.Request) (infraauth.Claims, error),
+	hasUserPermission func(ctx context.Context, userID, permissionCode string) (bool, error),
+	respondJSON func(w http.ResponseWriter, status int, payload interface{}),
+	respondError func(w http.ResponseWriter, status int, message string),
+) *Handler {
+	return &Handler{
+		slaService:        slaService,
+		authorizeRequest:  authorizeRequest,
+		hasUserPermission: hasUserPermission,
+		respondJSON:       respondJSON,
+		respondError:      respondError,
+	}
+}
+
+const (
+	permissionSLAPoliciesRead   = "sla_policies.read"
+	permissionSLAPoliciesCreate = "sla_policies.create"
+	permissionSLAPoliciesUpdate = "sla_policies.update"
+	permissionSLAPoliciesDelete = "sla_policies.delete"
+)
@@ -0,0 +1,131 @@
+package slapolicies
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"admin_backend/internal/usecase"
+)
+
+const policyInvalidInputMessage = "name is required, firstResponseMinutes must be positive, resolutionMinutes must not be lower than firstResponseMinutes and warningMinutes must not be negative"
+
+type slaPolicyPayload struct {
+	Name                 string `json:"name"`
+	ClientID             string `json:"clientId"`
+	ProjectTypeID        string `json:"projectTypeId"`
+	FirstResponseMinutes int    `json:"firstResponseMinutes"`
+	ResolutionMinutes    int    `json:"resolutionMinutes"`
+	WarningMinutes       *int   `json:"warningMinutes"`
+	Active               *bool  `json:"active"`
+}
+
+// HandleSLAPolicies serves GET and POST /sla-policies.
+func (h *Handler) HandleSLAPolicies(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := h.authorizeWithPermission(w, r, permissionSLAPoliciesRead); !ok {
+			return
+		}
+
+		policies, err := h.slaService.ListPolicies(r.Context())
+		if err != nil {
+			h.handleSLAPolicyUsecaseError(w, err, "")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, policies)
+	case http.MethodPost:
+		if _, ok := h.authorizeWithPermission(w, r, permissionSLAPoliciesCreate); !ok {
+			return
+		}
+
+		var payload slaPolicyPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		policy, err := h.slaService.CreatePolicy(r.Context(), usecase.CreateSLAPolicyInput{
+			Name:                 payload.Name,
+			ClientID:             payload.ClientID,
+			ProjectTypeID:        payload.ProjectTypeID,
+			FirstResponseMinutes: payload.FirstResponseMinutes,
+			ResolutionMinutes:    payload.ResolutionMinutes,
+			WarningMinutes:       payload.WarningMinutes,
+			Active:               payload.Active,
+		})
+		if err != nil {
+			h.handleSLAPolicyUsecaseError(w, err, policyInvalidInputMessage)
+			return
+		}
+
+		h.respondJSON(w, http.StatusCreated, policy)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleSLAPolicyRoutes serves GET, PATCH and DELETE /sla-policies/{id}.
+func (h *Handler) HandleSLAPolicyRoutes(w http.ResponseWriter, r *http.Request) {
+	policyID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/sla-policies/"), "/")
+	if policyID == "" || strings.Contains(policyID, "/") {
+		h.respondError(w, http.StatusNotFound, "sla policy not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := h.authorizeWithPermission(w, r, permissionSLAPoliciesRead); !ok {
+			return
+		}
+
+		policy, err := h.slaService.GetPolicy(r.Context(), policyID)
+		if err != nil {
+			h.handleSLAPolicyUsecaseError(w, err, "")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, policy)
+	case http.MethodPatch:
+		if _, ok := h.authorizeWithPermission(w, r, permissionSLAPoliciesUpdate); !ok {
+			return
+		}
+
+		var payload slaPolicyPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		policy, err := h.slaService.UpdatePolicy(r.Context(), usecase.UpdateSLAPolicyInput{
+			ID:                   policyID,
+			Name:                 payload.Name,
+			ClientID:             payload.ClientID,
+			ProjectTypeID:        payload.ProjectTypeID,
+			FirstResponseMinutes: payload.FirstResponseMinutes,
+			ResolutionMinutes:    payload.ResolutionMinutes,
+			WarningMinutes:       payload.WarningMinutes,
+			Active:               payload.Active,
+		})
+		if err != nil {
+			h.handleSLAPolicyUsecaseError(w, err, policyInvalidInputMessage)
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, policy)
+	case http.MethodDelete:
+		if _, ok := h.authorizeWithPermission(w, r, permissionSLAPoliciesDelete); !ok {
+			return
+		}
+
+		if err := h.slaService.DeletePolicy(r.Context(), policyID); err != nil {
+			h.handleSLAPolicyUsecaseError(w, err, "")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,91 @@
+package sse
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"admin_backend/internal/usecase"
+)
+
+const heartbeatInterval = 25 * time.Second
+
+// WithQueryToken copies an access_token query parameter into the
+// Authorization header. Browsers' EventSource cannot send custom headers, so
+// the stream endpoints accept the token in the URL as well.
+func WithQueryToken(r *http.Request) *http.Request {
+	if strings.TrimSpace(r.Header.Get("Authorization")) != "" {
+		return r
+	}
+
+	token := strings.TrimSpace(r.URL.Query().Get("access_token"))
+	if token == "" {
+		return r
+	}
+
+	clone := r.Clone(r.Context())
+	clone.Header.Set("Authorization", "Bearer "+token)
+	return clone
+}
+
+// Stream writes the events accepted by allows as Server-Sent Events until the
+// client disconnects or the source is closed. A comment line is sent
+// periodically so proxies keep the connection open.
+func Stream(
+	w http.ResponseWriter,
+	r *http.Request,
+	source usecase.RealtimeEventSource,
+	allows func(event usecase.RealtimeEvent) bool,
+) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events, cancel := source.Subscribe()
+	defer cancel()
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 5000\nevent: ready\ndata: {}\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, open := <-events:
+			if !open {
+				return
+			}
+			if !allows(event) {
+				continue
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package surveys
+
+import (
+	"context"
+	"net/http"
+
+	infraauth "admin_backend/internal/infra/auth"
+	"admin_backend/internal/usecase"
+)
+
+type Handler struct {
+	surveyService     *usecase.SurveyService
+	authorizeRequest  func(r *http.Request) (infraauth.Claims, error)
+	hasUserPermission func(ctx context.Context, userID, permissionCode string) (bool, error)
+	respondJSON       func(w http.ResponseWriter, status int, payload interface{})
+	respondError      func(w http.ResponseWriter, status int, message string)
+}
+
+func NewHandler(
+	surveyService *usecase.SurveyService,
+	authorizeRequest func(r *http.Request) (infraauth.Claims, error),
+	hasUserPermission func(ctx context.Context, userID, permissionCode string) (bool, error),
+	respondJSON func(w http.ResponseWriter, status int, payload interface{}),
+	respondError func(w http.ResponseWriter, status int, message string),
+) *Handler {
+	return &Handler{
+		surveyService:     surveyService,
+		authorizeRequest:  authorizeRequest,
+		hasUserPermission: hasUserPermission,
+		respondJSON:       respondJSON,
+		respondError:      respondError,
+	}
+}
+
+const permissionClientSurveysRead = "client_surveys.read"
@@ -0,0 +1,113 @@
+package surveys
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"admin_backend/internal/usecase"
+)
+
+// HandleSurveys serves GET /surveys, the answers given in the period.
+func (h *Handler) HandleSurveys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if _, ok := h.authorizeWithPermission(w, r, permissionClientSurveysRead); !ok {
+		return
+	}
+
+	filter, ok := h.parseReportFilter(w, r)
+	if !ok {
+		return
+	}
+
+	responses, err := h.surveyService.ListResponses(r.Context(), filter)
+	if err != nil {
+		h.handleSurveyUsecaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, responses)
+}
+
+// HandleSurveyReport serves GET /surveys/report with the CSAT and NPS
+// aggregated overall, per kind, per project and per project manager.
+func (h *Handler) HandleSurveyReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if _, ok := h.authorizeWithPermission(w, r, permissionClientSurveysRead); !ok {
+		return
+	}
+
+	filter, ok := h.parseReportFilter(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.surveyService.Report(r.Context(), filter)
+	if err != nil {
+		h.handleSurveyUsecaseError(w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, report)
+}
+
+// parseReportFilter reads from, to, projectId and managerUserId. A date-only
+// "to" includes the whole day.
+func (h *Handler) parseReportFilter(
+	w http.ResponseWriter,
+	r *http.Request,
+) (usecase.SurveyReportFilter, bool) {
+	query := r.URL.Query()
+	filter := usecase.SurveyReportFilter{
+		ProjectID:     query.Get("projectId"),
+		ManagerUserID: query.Get("managerUserId"),
+	}
+
+	from, _, err := parseOptionalDate(query.Get("from"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid from")
+		return usecase.SurveyReportFilter{}, false
+	}
+	if from != nil {
+		filter.From = *from
+	}
+
+	to, dateOnly, err := parseOptionalDate(query.Get("to"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "invalid to")
+		return usecase.SurveyReportFilter{}, false
+	}
+	if to != nil {
+		filter.To = *to
+		if dateOnly {
+			filter.To = filter.To.AddDate(0, 0, 1)
+		}
+	}
+
+	return filter, true
+}
+
+func parseOptionalDate(value string) (*time.Time, bool, error) {
+	raw := strings.TrimSpace(value)
+	if raw == "" {
+		return nil, false, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", raw)
+	if err == nil {
+		return &parsed, true, nil
+	}
+
+	rfc3339Date, rfcErr := time.Parse(time.RFC3339, raw)
+	if rfcErr != nil {
+		return nil, false, err
+	}
+
+	return &rfc3339Date, false, nil
+}
@@ -1,6 +1,10 @@
 package userprofiles
 
-import "net/http"
+import (
+	"net/http"
+
+	"admin_backend/internal/interfaces/http/requestlog"
+)
 
 func (h *Handler) HandleAuthMyProfiles(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -16,12 +20,14 @@ func (h *Handler) HandleAuthMyProfiles(w http.ResponseWriter, r *http.Request) {
 
 	profiles, err := h.userProfileService.ListProfilesByUserID(r.Context(), claims.Sub)
 	if err != nil {
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 		return
 	}
 
 	isAdministrator, err := h.isUserAdministrator(r.Context(), claims.Sub)
 	if err != nil {
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 		return
 	}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
@@ -17,6 +18,7 @@ func (h *Handler) HandleUserProfiles(w http.ResponseWriter, r *http.Request, use
 
 	isAdministrator, err := h.isUserAdministrator(r.Context(), claims.Sub)
 	if err != nil {
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 		return
 	}
@@ -35,6 +37,7 @@ func (h *Handler) HandleUserProfiles(w http.ResponseWriter, r *http.Request, use
 			case errors.Is(err, usecase.ErrInvalidInput):
 				h.respondError(w, http.StatusBadRequest, "invalid input")
 			default:
+				requestlog.RecordError(w, err)
 				h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			}
 			return
@@ -67,6 +70,7 @@ func (h *Handler) HandleUserProfiles(w http.ResponseWriter, r *http.Request, use
 			case errors.Is(err, usecase.ErrInvalidInput):
 				h.respondError(w, http.StatusBadRequest, "invalid input")
 			default:
+				requestlog.RecordError(w, err)
 				h.respondError(w, http.StatusInternalServerError, "unexpected error")
 			}
 			return
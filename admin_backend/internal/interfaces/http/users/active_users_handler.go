@@ -2,7 +2,8 @@ package users
 
 import (
 	"net/http"
-	"time"
+
+	"admin_backend/internal/usecase"
 )
 
 func (h *Handler) HandleActiveUsers(w http.ResponseWriter, r *http.Request) {
@@ -16,40 +17,13 @@ func (h *Handler) HandleActiveUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var users []struct {
-		ID      string    `db:"id" json:"id"`
-		Name    string    `db:"name" json:"name"`
-		Email   string    `db:"email" json:"email"`
-		Login   string    `db:"login" json:"login"`
-		Phone   string    `db:"phone" json:"phone"`
-		Address string    `db:"address" json:"address"`
-		Avatar  string    `db:"avatar" json:"avatar"`
-		Active  bool      `db:"active" json:"active"`
-		Created time.Time `db:"created" json:"created"`
-		Updated time.Time `db:"updated" json:"updated"`
-	}
-
-	if err := h.db.SelectContext(
-		r.Context(),
-		&users,
-		`
-		SELECT
-		  id,
-		  name,
-		  email,
-		  login,
-		  COALESCE(phone, '') AS phone,
-		  COALESCE(address, '') AS address,
-		  COALESCE(avatar, '') AS avatar,
-		  ativo AS active,
-		  created,
-		  updated
-		FROM users
-		WHERE ativo = TRUE
-		ORDER BY created DESC, id DESC
-		`,
-	); err != nil {
-		h.respondError(w, http.StatusInternalServerError, "unexpected error")
+	users, err := h.service.List(r.Context(), usecase.UserListFilter{
+		Search:     r.URL.Query().Get("search"),
+		OnlyActive: true,
+		ProfileID:  r.URL.Query().Get("profileId"),
+	})
+	if err != nil {
+		h.handleUsecaseError(w, err)
 		return
 	}
 
@@ -5,12 +5,10 @@ import (
 
 	infraauth "admin_backend/internal/infra/auth"
 	"admin_backend/internal/usecase"
-	"github.com/jmoiron/sqlx"
 )
 
 type Handler struct {
 	service              *usecase.UserService
-	db                   *sqlx.DB
 	authorizeRequest     func(r *http.Request) (infraauth.Claims, error)
 	normalizeAvatarInput func(value string) (string, error)
 	handleUserProfiles   func(w http.ResponseWriter, r *http.Request, userID string)
@@ -20,7 +18,6 @@ type Handler struct {
 
 func NewHandler(
 	service *usecase.UserService,
-	db *sqlx.DB,
 	authorizeRequest func(r *http.Request) (infraauth.Claims, error),
 	normalizeAvatarInput func(value string) (string, error),
 	handleUserProfiles func(w http.ResponseWriter, r *http.Request, userID string),
@@ -29,7 +26,6 @@ func NewHandler(
 ) *Handler {
 	return &Handler{
 		service:              service,
-		db:                   db,
 		authorizeRequest:     authorizeRequest,
 		normalizeAvatarInput: normalizeAvatarInput,
 		handleUserProfiles:   handleUserProfiles,
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"admin_backend/internal/interfaces/http/requestlog"
 	"admin_backend/internal/usecase"
 )
 
@@ -11,9 +12,22 @@ func (h *Handler) handleUsecaseError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, usecase.ErrInvalidInput):
 		h.respondError(w, http.StatusBadRequest, "invalid input")
+	case errors.Is(err, usecase.ErrUserRequiredFields),
+		errors.Is(err, usecase.ErrUserPasswordRequired),
+		errors.Is(err, usecase.ErrUserSelfDeactivation):
+		h.respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, usecase.ErrProfilesNotFound):
+		h.respondError(w, http.StatusBadRequest, "one or more profiles do not exist")
 	case errors.Is(err, usecase.ErrNotFound):
 		h.respondError(w, http.StatusNotFound, "user not found")
+	case errors.Is(err, usecase.ErrLoginInUse):
+		h.respondError(w, http.StatusConflict, "login already in use")
+	case errors.Is(err, usecase.ErrEmailInUse):
+		h.respondError(w, http.StatusConflict, "email already in use")
+	case errors.Is(err, usecase.ErrConflict):
+		h.respondError(w, http.StatusConflict, "conflict")
 	default:
+		requestlog.RecordError(w, err)
 		h.respondError(w, http.StatusInternalServerError, "unexpected error")
 	}
 }
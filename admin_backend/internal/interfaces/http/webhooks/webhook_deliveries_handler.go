@@ -0,0 +1,58 @@
+package webhooks
+
+import (
+	"net/http"
+	"strings"
+)
+
+// HandleWebhookDeliveryRoutes serves GET /webhook-deliveries/{id}, which
+// includes the payload and the attempt log, and
+// POST /webhook-deliveries/{id}/redeliver.
+func (h *Handler) HandleWebhookDeliveryRoutes(w http.ResponseWriter, r *http.Request) {
+	trimmedPath := strings.Trim(strings.TrimPrefix(r.URL.Path, "/webhook-deliveries/"), "/")
+	segments := strings.Split(trimmedPath, "/")
+	if trimmedPath == "" || len(segments) > 2 {
+		h.respondError(w, http.StatusNotFound, "webhook delivery not found")
+		return
+	}
+
+	deliveryID := segments[0]
+	if len(segments) == 2 {
+		if segments[1] != "redeliver" {
+			h.respondError(w, http.StatusNotFound, "route not found")
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if _, ok := h.authorizeWithPermission(w, r, permissionWebhooksUpdate); !ok {
+			return
+		}
+
+		delivery, err := h.webhookService.Redeliver(r.Context(), deliveryID)
+		if err != nil {
+			h.handleWebhookUsecaseError(w, err, "", "webhook delivery not found")
+			return
+		}
+
+		h.respondJSON(w, http.StatusAccepted, delivery)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if _, ok := h.authorizeWithPermission(w, r, permissionWebhooksRead); !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.GetDelivery(r.Context(), deliveryID)
+	if err != nil {
+		h.handleWebhookUsecaseError(w, err, "", "webhook delivery not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, delivery)
+}
@@ -0,0 +1,180 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"admin_backend/internal/usecase"
+)
+
+const subscriptionInvalidInputMessage = "name is required, url must be http or https and eventTypes must list known event types"
+
+type webhookSubscriptionPayload struct {
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"eventTypes"`
+	Active     *bool    `json:"active"`
+}
+
+// HandleWebhookSubscriptions serves GET and POST /webhook-subscriptions.
+func (h *Handler) HandleWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := h.authorizeWithPermission(w, r, permissionWebhooksRead); !ok {
+			return
+		}
+
+		subscriptions, err := h.webhookService.ListSubscriptions(r.Context())
+		if err != nil {
+			h.handleWebhookUsecaseError(w, err, "", "webhook subscription not found")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, map[string]interface{}{
+			"subscriptions": subscriptions,
+			"eventTypes":    usecase.WebhookEventTypes,
+		})
+	case http.MethodPost:
+		claims, ok := h.authorizeWithPermission(w, r, permissionWebhooksCreate)
+		if !ok {
+			return
+		}
+
+		var payload webhookSubscriptionPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		active := true
+		if payload.Active != nil {
+			active = *payload.Active
+		}
+
+		subscription, err := h.webhookService.CreateSubscription(r.Context(), usecase.CreateWebhookSubscriptionInput{
+			Name:       payload.Name,
+			URL:        payload.URL,
+			Secret:     payload.Secret,
+			EventTypes: payload.EventTypes,
+			Active:     active,
+			CreatedBy:  claims.Sub,
+		})
+		if err != nil {
+			h.handleWebhookUsecaseError(w, err, subscriptionInvalidInputMessage, "webhook subscription not found")
+			return
+		}
+
+		h.respondJSON(w, http.StatusCreated, subscription)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleWebhookSubscriptionRoutes serves GET, PATCH and DELETE
+// /webhook-subscriptions/{id} and GET /webhook-subscriptions/{id}/deliveries.
+func (h *Handler) HandleWebhookSubscriptionRoutes(w http.ResponseWriter, r *http.Request) {
+	trimmedPath := strings.Trim(strings.TrimPrefix(r.URL.Path, "/webhook-subscriptions/"), "/")
+	segments := strings.Split(trimmedPath, "/")
+	if trimmedPath == "" || len(segments) > 2 {
+		h.respondError(w, http.StatusNotFound, "webhook subscription not found")
+		return
+	}
+
+	subscriptionID := segments[0]
+	if len(segments) == 2 {
+		if segments[1] != "deliveries" {
+			h.respondError(w, http.StatusNotFound, "route not found")
+			return
+		}
+		h.handleSubscriptionDeliveries(w, r, subscriptionID)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := h.authorizeWithPermission(w, r, permissionWebhooksRead); !ok {
+			return
+		}
+
+		subscription, err := h.webhookService.GetSubscription(r.Context(), subscriptionID)
+		if err != nil {
+			h.handleWebhookUsecaseError(w, err, "", "webhook subscription not found")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, subscription)
+	case http.MethodPatch:
+		if _, ok := h.authorizeWithPermission(w, r, permissionWebhooksUpdate); !ok {
+			return
+		}
+
+		var payload webhookSubscriptionPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			h.respondError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+
+		subscription, err := h.webhookService.UpdateSubscription(r.Context(), usecase.UpdateWebhookSubscriptionInput{
+			ID:         subscriptionID,
+			Name:       payload.Name,
+			URL:        payload.URL,
+			Secret:     payload.Secret,
+			EventTypes: payload.EventTypes,
+			Active:     payload.Active,
+		})
+		if err != nil {
+			h.handleWebhookUsecaseError(w, err, subscriptionInvalidInputMessage, "webhook subscription not found")
+			return
+		}
+
+		h.respondJSON(w, http.StatusOK, subscription)
+	case http.MethodDelete:
+		if _, ok := h.authorizeWithPermission(w, r, permissionWebhooksDelete); !ok {
+			return
+		}
+
+		if err := h.webhookService.DeleteSubscription(r.Context(), subscriptionID); err != nil {
+			h.handleWebhookUsecaseError(w, err, "", "webhook subscription not found")
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) handleSubscriptionDeliveries(w http.ResponseWriter, r *http.Request, subscriptionID string) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.authorizeWithPermission(w, r, permissionWebhooksRead); !ok {
+		return
+	}
+
+	filter := usecase.WebhookDeliveryFilter{
+		SubscriptionID: subscriptionID,
+		Status:         r.URL.Query().Get("status"),
+	}
+	if raw := strings.TrimSpace(r.URL.Query().Get("limit")); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			h.respondError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(r.Context(), filter)
+	if err != nil {
+		h.handleWebhookUsecaseError(w, err, "status must be pendente, entregue or falhou", "webhook subscription not found")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, deliveries)
+}
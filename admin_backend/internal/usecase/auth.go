@@ -44,6 +44,9 @@ type UpdateAccountInput struct {
 }
 
 func (s *AuthService) Authenticate(ctx context.Context, login, password string) (AuthUser, error) {
+	ctx, span := startSpan(ctx, "AuthService.Authenticate")
+	defer span.End()
+
 	normalizedLogin := strings.TrimSpace(login)
 	normalizedPassword := strings.TrimSpace(password)
 	if normalizedLogin == "" || normalizedPassword == "" {
@@ -67,6 +70,9 @@ func (s *AuthService) Authenticate(ctx context.Context, login, password string)
 }
 
 func (s *AuthService) UpdateOwnAccount(ctx context.Context, input UpdateAccountInput) (AuthUser, error) {
+	ctx, span := startSpan(ctx, "AuthService.UpdateOwnAccount")
+	defer span.End()
+
 	normalizedInput := UpdateAccountInput{
 		UserID:   strings.TrimSpace(input.UserID),
 		Name:     strings.TrimSpace(input.Name),
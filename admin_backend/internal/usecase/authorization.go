@@ -19,6 +19,9 @@ func NewAuthorizationService(repo AuthorizationRepository) *AuthorizationService
 }
 
 func (s *AuthorizationService) IsUserAdministrator(ctx context.Context, userID string) (bool, error) {
+	ctx, span := startSpan(ctx, "AuthorizationService.IsUserAdministrator")
+	defer span.End()
+
 	id := strings.TrimSpace(userID)
 	if id == "" {
 		return false, ErrInvalidInput
@@ -32,6 +35,9 @@ func (s *AuthorizationService) HasUserPermission(
 	userID,
 	permissionCode string,
 ) (bool, error) {
+	ctx, span := startSpan(ctx, "AuthorizationService.HasUserPermission")
+	defer span.End()
+
 	id := strings.TrimSpace(userID)
 	code := strings.TrimSpace(permissionCode)
 	if id == "" || code == "" {
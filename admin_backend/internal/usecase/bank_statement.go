@@ -0,0 +1,553 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	BankStatementFormatOFX = "ofx"
+	BankStatementFormatCSV = "csv"
+
+	BankStatementLineStatusPending    = "pendente"
+	BankStatementLineStatusReconciled = "conciliado"
+	BankStatementLineStatusIgnored    = "ignorado"
+
+	BankMatchSourceRevenue       = "revenue"
+	BankMatchSourceMonthlyCharge = "monthly_charge"
+
+	defaultBankMatchWindowDays = 7
+	maxBankMatchWindowDays     = 60
+	maxBankMatchSuggestions    = 5
+)
+
+type BankStatementRepository interface {
+	CreateBankStatementImport(ctx context.Context, input NewBankStatementImport) (BankStatementImport, error)
+	ListBankStatementImports(ctx context.Context) ([]BankStatementImport, error)
+	GetBankStatementImport(ctx context.Context, importID string) (BankStatementImport, error)
+	ListBankStatementLines(ctx context.Context, filter BankStatementLineFilter) ([]BankStatementLine, error)
+	ListBankReconciliationCandidates(ctx context.Context) ([]BankReconciliationCandidate, error)
+	ConfirmBankStatementMatch(ctx context.Context, input BankStatementMatchRecordInput) (BankStatementLine, error)
+	IgnoreBankStatementLine(ctx context.Context, input BankStatementLineResolutionInput) (BankStatementLine, error)
+}
+
+type BankStatementService struct {
+	repo BankStatementRepository
+}
+
+func NewBankStatementService(repo BankStatementRepository) *BankStatementService {
+	return &BankStatementService{repo: repo}
+}
+
+type BankStatementImport struct {
+	ID            string    `json:"id"`
+	Format        string    `json:"format"`
+	FileName      string    `json:"fileName"`
+	Account       string    `json:"account"`
+	ImportedBy    string    `json:"importedBy,omitempty"`
+	TotalLines    int       `json:"totalLines"`
+	ImportedLines int       `json:"importedLines"`
+	PendingLines  int       `json:"pendingLines"`
+	Created       time.Time `json:"created"`
+}
+
+type BankStatementLine struct {
+	ID                string                         `json:"id"`
+	ImportID          string                         `json:"importId"`
+	Account           string                         `json:"account"`
+	FitID             string                         `json:"fitId"`
+	PostedOn          time.Time                      `json:"postedOn"`
+	Amount            float64                        `json:"amount"`
+	Description       string                         `json:"description"`
+	PayerName         string                         `json:"payerName"`
+	PayerDocument     string                         `json:"payerDocument,omitempty"`
+	Status            string                         `json:"status"`
+	MatchedSourceType string                         `json:"matchedSourceType,omitempty"`
+	MatchedSourceID   string                         `json:"matchedSourceId,omitempty"`
+	MatchedProjectID  string                         `json:"matchedProjectId,omitempty"`
+	ResolvedAt        *time.Time                     `json:"resolvedAt,omitempty"`
+	ResolvedBy        string                         `json:"resolvedBy,omitempty"`
+	Suggestions       []BankStatementMatchSuggestion `json:"suggestions,omitempty"`
+	Created           time.Time                      `json:"created"`
+}
+
+// BankStatementMatchSuggestion is a pending revenue or monthly charge that
+// could explain a credit. Reasons lists which signals contributed to Score:
+// amount, date, payer_name and payer_document.
+type BankStatementMatchSuggestion struct {
+	SourceType  string     `json:"sourceType"`
+	SourceID    string     `json:"sourceId"`
+	ProjectID   string     `json:"projectId"`
+	ProjectName string     `json:"projectName"`
+	Title       string     `json:"title"`
+	Amount      float64    `json:"amount"`
+	ExpectedOn  *time.Time `json:"expectedOn,omitempty"`
+	Score       int        `json:"score"`
+	Reasons     []string   `json:"reasons"`
+}
+
+type BankStatementDetail struct {
+	Import BankStatementImport `json:"import"`
+	Lines  []BankStatementLine `json:"lines"`
+}
+
+// BankReconciliationCandidate is an open receivable. KnownPayerDocuments
+// holds the CPF/CNPJ of payers previously reconciled against the project.
+type BankReconciliationCandidate struct {
+	SourceType          string
+	SourceID            string
+	ProjectID           string
+	ProjectName         string
+	Title               string
+	Amount              float64
+	ExpectedOn          *time.Time
+	ClientNames         []string
+	KnownPayerDocuments []string
+}
+
+type BankStatementLineFilter struct {
+	ImportID string
+	Status   string
+}
+
+// CSVStatementLayout tells the CSV parser where each field lives. Columns
+// may be header names (case and accent insensitive) or 1-based positions.
+type CSVStatementLayout struct {
+	Delimiter           string
+	HasHeader           *bool
+	DateColumn          string
+	DateFormat          string
+	AmountColumn        string
+	DescriptionColumn   string
+	PayerNameColumn     string
+	PayerDocumentColumn string
+	IDColumn            string
+}
+
+type ImportBankStatementInput struct {
+	Format     string
+	FileName   string
+	Account    string
+	Content    string
+	CSV        CSVStatementLayout
+	ImportedBy string
+}
+
+type NewBankStatementLine struct {
+	FitID         string
+	PostedOn      time.Time
+	Amount        float64
+	Description   string
+	PayerName     string
+	PayerDocument string
+}
+
+type NewBankStatementImport struct {
+	Format     string
+	FileName   string
+	Account    string
+	ImportedBy string
+	TotalLines int
+	Lines      []NewBankStatementLine
+}
+
+type BankStatementMatchOptions struct {
+	WindowDays int
+}
+
+type ConfirmBankStatementLineInput struct {
+	LineID      string
+	SourceType  string
+	SourceID    string
+	ConfirmedBy string
+}
+
+type BankStatementMatchRecordInput struct {
+	LineID      string
+	SourceType  string
+	SourceID    string
+	ConfirmedBy string
+	ConfirmedAt time.Time
+}
+
+type BankStatementLineResolutionInput struct {
+	LineID     string
+	ResolvedBy string
+	ResolvedAt time.Time
+}
+
+func (s *BankStatementService) ImportBankStatement(
+	ctx context.Context,
+	input ImportBankStatementInput,
+	options BankStatementMatchOptions,
+) (BankStatementDetail, error) {
+	ctx, span := startSpan(ctx, "BankStatementService.ImportBankStatement")
+	defer span.End()
+
+	format := strings.ToLower(strings.TrimSpace(input.Format))
+	if format == "" {
+		format = detectBankStatementFormat(input.FileName, input.Content)
+	}
+	if strings.TrimSpace(input.Content) == "" {
+		return BankStatementDetail{}, ErrInvalidInput
+	}
+
+	var parsed parsedBankStatement
+	var err error
+	switch format {
+	case BankStatementFormatOFX:
+		parsed, err = parseOFXStatement(input.Content)
+	case BankStatementFormatCSV:
+		parsed, err = parseCSVStatement(input.Content, input.CSV)
+	default:
+		return BankStatementDetail{}, ErrInvalidInput
+	}
+	if err != nil {
+		return BankStatementDetail{}, err
+	}
+
+	account := strings.TrimSpace(input.Account)
+	if account == "" {
+		account = parsed.Account
+	}
+
+	credits := make([]NewBankStatementLine, 0, len(parsed.Lines))
+	for _, line := range parsed.Lines {
+		if line.Amount > 0 {
+			credits = append(credits, line)
+		}
+	}
+
+	statementImport, err := s.repo.CreateBankStatementImport(ctx, NewBankStatementImport{
+		Format:     format,
+		FileName:   strings.TrimSpace(input.FileName),
+		Account:    account,
+		ImportedBy: strings.TrimSpace(input.ImportedBy),
+		TotalLines: len(credits),
+		Lines:      credits,
+	})
+	if err != nil {
+		return BankStatementDetail{}, err
+	}
+
+	return s.GetBankStatement(ctx, statementImport.ID, options)
+}
+
+func (s *BankStatementService) ListBankStatements(ctx context.Context) ([]BankStatementImport, error) {
+	ctx, span := startSpan(ctx, "BankStatementService.ListBankStatements")
+	defer span.End()
+
+	return s.repo.ListBankStatementImports(ctx)
+}
+
+// GetBankStatement returns the import with its lines; pending lines carry
+// ranked suggestions computed against the receivables open right now.
+func (s *BankStatementService) GetBankStatement(
+	ctx context.Context,
+	importID string,
+	options BankStatementMatchOptions,
+) (BankStatementDetail, error) {
+	ctx, span := startSpan(ctx, "BankStatementService.GetBankStatement")
+	defer span.End()
+
+	id := strings.TrimSpace(importID)
+	if id == "" {
+		return BankStatementDetail{}, ErrInvalidInput
+	}
+	windowDays, err := normalizeBankMatchWindowDays(options.WindowDays)
+	if err != nil {
+		return BankStatementDetail{}, err
+	}
+
+	statementImport, err := s.repo.GetBankStatementImport(ctx, id)
+	if err != nil {
+		return BankStatementDetail{}, err
+	}
+
+	lines, err := s.repo.ListBankStatementLines(ctx, BankStatementLineFilter{ImportID: id})
+	if err != nil {
+		return BankStatementDetail{}, err
+	}
+
+	if err := s.attachSuggestions(ctx, lines, windowDays); err != nil {
+		return BankStatementDetail{}, err
+	}
+
+	return BankStatementDetail{
+		Import: statementImport,
+		Lines:  lines,
+	}, nil
+}
+
+func (s *BankStatementService) ListPendingBankStatementLines(
+	ctx context.Context,
+	options BankStatementMatchOptions,
+) ([]BankStatementLine, error) {
+	ctx, span := startSpan(ctx, "BankStatementService.ListPendingBankStatementLines")
+	defer span.End()
+
+	windowDays, err := normalizeBankMatchWindowDays(options.WindowDays)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := s.repo.ListBankStatementLines(ctx, BankStatementLineFilter{
+		Status: BankStatementLineStatusPending,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachSuggestions(ctx, lines, windowDays); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// ConfirmBankStatementLine settles the chosen receivable with the statement
+// date: revenues become recebido and monthly charges become pago.
+func (s *BankStatementService) ConfirmBankStatementLine(
+	ctx context.Context,
+	input ConfirmBankStatementLineInput,
+) (BankStatementLine, error) {
+	ctx, span := startSpan(ctx, "BankStatementService.ConfirmBankStatementLine")
+	defer span.End()
+
+	normalizedInput := BankStatementMatchRecordInput{
+		LineID:      strings.TrimSpace(input.LineID),
+		SourceType:  strings.ToLower(strings.TrimSpace(input.SourceType)),
+		SourceID:    strings.TrimSpace(input.SourceID),
+		ConfirmedBy: strings.TrimSpace(input.ConfirmedBy),
+		ConfirmedAt: time.Now().UTC(),
+	}
+	if normalizedInput.LineID == "" || normalizedInput.SourceID == "" {
+		return BankStatementLine{}, ErrInvalidInput
+	}
+	if normalizedInput.SourceType != BankMatchSourceRevenue &&
+		normalizedInput.SourceType != BankMatchSourceMonthlyCharge {
+		return BankStatementLine{}, ErrInvalidInput
+	}
+
+	return s.repo.ConfirmBankStatementMatch(ctx, normalizedInput)
+}
+
+func (s *BankStatementService) IgnoreBankStatementLine(
+	ctx context.Context,
+	lineID string,
+	userID string,
+) (BankStatementLine, error) {
+	ctx, span := startSpan(ctx, "BankStatementService.IgnoreBankStatementLine")
+	defer span.End()
+
+	normalizedInput := BankStatementLineResolutionInput{
+		LineID:     strings.TrimSpace(lineID),
+		ResolvedBy: strings.TrimSpace(userID),
+		ResolvedAt: time.Now().UTC(),
+	}
+	if normalizedInput.LineID == "" {
+		return BankStatementLine{}, ErrInvalidInput
+	}
+
+	return s.repo.IgnoreBankStatementLine(ctx, normalizedInput)
+}
+
+func (s *BankStatementService) attachSuggestions(
+	ctx context.Context,
+	lines []BankStatementLine,
+	windowDays int,
+) error {
+	hasPending := false
+	for _, line := range lines {
+		if line.Status == BankStatementLineStatusPending {
+			hasPending = true
+			break
+		}
+	}
+	if !hasPending {
+		return nil
+	}
+
+	candidates, err := s.repo.ListBankReconciliationCandidates(ctx)
+	if err != nil {
+		return err
+	}
+
+	for index := range lines {
+		if lines[index].Status != BankStatementLineStatusPending {
+			continue
+		}
+		lines[index].Suggestions = suggestBankStatementMatches(lines[index], candidates, windowDays)
+	}
+
+	return nil
+}
+
+func normalizeBankMatchWindowDays(value int) (int, error) {
+	if value == 0 {
+		return defaultBankMatchWindowDays, nil
+	}
+	if value < 0 || value > maxBankMatchWindowDays {
+		return 0, ErrInvalidInput
+	}
+
+	return value, nil
+}
+
+// suggestBankStatementMatches ranks open receivables for a credit. The
+// amount must match to the cent and, when the receivable has a date, fall
+// inside the window; payer name and CPF/CNPJ only raise the score.
+func suggestBankStatementMatches(
+	line BankStatementLine,
+	candidates []BankReconciliationCandidate,
+	windowDays int,
+) []BankStatementMatchSuggestion {
+	type rankedSuggestion struct {
+		suggestion BankStatementMatchSuggestion
+		dayDiff    int
+	}
+
+	payerTokens := bankMatchNameTokens(line.PayerName + " " + line.Description)
+	ranked := make([]rankedSuggestion, 0)
+	for _, candidate := range candidates {
+		if absMoneyDiff(candidate.Amount, line.Amount) > 0.005 {
+			continue
+		}
+
+		score := 50
+		reasons := []string{"amount"}
+		dayDiff := maxBankMatchWindowDays + 1
+		if candidate.ExpectedOn != nil {
+			dayDiff = absDayDiff(*candidate.ExpectedOn, line.PostedOn)
+			if dayDiff > windowDays {
+				continue
+			}
+			score += 20 - 20*dayDiff/(windowDays+1)
+			reasons = append(reasons, "date")
+		}
+
+		bestNameRatio := 0.0
+		for _, clientName := range candidate.ClientNames {
+			if ratio := bankMatchNameRatio(bankMatchNameTokens(clientName), payerTokens); ratio > bestNameRatio {
+				bestNameRatio = ratio
+			}
+		}
+		switch {
+		case bestNameRatio >= 0.99:
+			score += 20
+			reasons = append(reasons, "payer_name")
+		case bestNameRatio >= 0.5:
+			score += 10
+			reasons = append(reasons, "payer_name")
+		}
+
+		if line.PayerDocument != "" {
+			for _, document := range candidate.KnownPayerDocuments {
+				if document == line.PayerDocument {
+					score += 30
+					reasons = append(reasons, "payer_document")
+					break
+				}
+			}
+		}
+
+		ranked = append(ranked, rankedSuggestion{
+			suggestion: BankStatementMatchSuggestion{
+				SourceType:  candidate.SourceType,
+				SourceID:    candidate.SourceID,
+				ProjectID:   candidate.ProjectID,
+				ProjectName: candidate.ProjectName,
+				Title:       candidate.Title,
+				Amount:      candidate.Amount,
+				ExpectedOn:  candidate.ExpectedOn,
+				Score:       score,
+				Reasons:     reasons,
+			},
+			dayDiff: dayDiff,
+		})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].suggestion.Score != ranked[j].suggestion.Score {
+			return ranked[i].suggestion.Score > ranked[j].suggestion.Score
+		}
+		return ranked[i].dayDiff < ranked[j].dayDiff
+	})
+	if len(ranked) > maxBankMatchSuggestions {
+		ranked = ranked[:maxBankMatchSuggestions]
+	}
+
+	suggestions := make([]BankStatementMatchSuggestion, 0, len(ranked))
+	for _, item := range ranked {
+		suggestions = append(suggestions, item.suggestion)
+	}
+
+	return suggestions
+}
+
+var bankMatchIgnoredTokens = map[string]struct{}{
+	"da": {}, "de": {}, "do": {}, "das": {}, "dos": {}, "e": {},
+	"ltda": {}, "me": {}, "epp": {}, "sa": {}, "eireli": {},
+}
+
+func bankMatchNameTokens(value string) []string {
+	folded := strings.ToLower(asciiAccentReplacer.Replace(value))
+	fields := strings.FieldsFunc(folded, func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len(field) < 2 {
+			continue
+		}
+		if _, ignored := bankMatchIgnoredTokens[field]; ignored {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+
+	return tokens
+}
+
+// bankMatchNameRatio is the share of the client's name tokens found among
+// the payer tokens, so "Maria Souza" fully matches "PIX RECEBIDO MARIA SOUZA".
+func bankMatchNameRatio(clientTokens []string, payerTokens []string) float64 {
+	if len(clientTokens) == 0 || len(payerTokens) == 0 {
+		return 0
+	}
+
+	payerSet := make(map[string]struct{}, len(payerTokens))
+	for _, token := range payerTokens {
+		payerSet[token] = struct{}{}
+	}
+
+	matched := 0
+	for _, token := range clientTokens {
+		if _, ok := payerSet[token]; ok {
+			matched++
+		}
+	}
+
+	return float64(matched) / float64(len(clientTokens))
+}
+
+func absMoneyDiff(left, right float64) float64 {
+	diff := roundProjectMoney(left) - roundProjectMoney(right)
+	if diff < 0 {
+		return -diff
+	}
+	return diff
+}
+
+func absDayDiff(left, right time.Time) int {
+	leftDay := time.Date(left.Year(), left.Month(), left.Day(), 0, 0, 0, 0, time.UTC)
+	rightDay := time.Date(right.Year(), right.Month(), right.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(leftDay.Sub(rightDay).Hours() / 24)
+	if days < 0 {
+		return -days
+	}
+	return days
+}
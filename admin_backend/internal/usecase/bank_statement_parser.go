@@ -0,0 +1,477 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type parsedBankStatement struct {
+	Account string
+	Lines   []NewBankStatementLine
+}
+
+var bankStatementDocumentCandidatePattern = regexp.MustCompile(`[0-9][0-9./-]{9,}[0-9]`)
+
+var defaultCSVStatementDateLayouts = []string{"02/01/2006", "2006-01-02", "02-01-2006", "02/01/06"}
+
+var defaultCSVStatementColumns = struct {
+	date          []string
+	amount        []string
+	description   []string
+	payerName     []string
+	payerDocument []string
+	id            []string
+}{
+	date:          []string{"data", "date", "data lancamento", "data do lancamento"},
+	amount:        []string{"valor", "amount", "valor (r$)", "valor r$"},
+	description:   []string{"descricao", "historico", "description", "lancamento", "memo"},
+	payerName:     []string{"pagador", "nome", "favorecido", "remetente", "payer"},
+	payerDocument: []string{"cpf/cnpj", "documento", "cpf", "cnpj", "document"},
+	id:            []string{"id", "identificador", "fitid", "transaction id"},
+}
+
+func detectBankStatementFormat(fileName string, content string) string {
+	switch strings.ToLower(path.Ext(strings.TrimSpace(fileName))) {
+	case ".ofx", ".qfx":
+		return BankStatementFormatOFX
+	case ".csv", ".txt":
+		return BankStatementFormatCSV
+	}
+	if indexFold(content, "<OFX>") >= 0 {
+		return BankStatementFormatOFX
+	}
+
+	return BankStatementFormatCSV
+}
+
+// parseOFXStatement reads both SGML (OFX 1.x, no closing tags) and XML
+// (OFX 2.x) statements by scanning each STMTTRN block for its tags.
+func parseOFXStatement(content string) (parsedBankStatement, error) {
+	if indexFold(content, "<OFX>") < 0 {
+		return parsedBankStatement{}, ErrBankStatementInvalid
+	}
+
+	statement := parsedBankStatement{
+		Account: ofxTagValue(content, "ACCTID"),
+	}
+
+	occurrences := map[string]int{}
+	remaining := content
+	for {
+		start := indexFold(remaining, "<STMTTRN>")
+		if start < 0 {
+			break
+		}
+		remaining = remaining[start+len("<STMTTRN>"):]
+
+		end := len(remaining)
+		for _, terminator := range []string{"</STMTTRN>", "<STMTTRN>", "</BANKTRANLIST>"} {
+			if index := indexFold(remaining, terminator); index >= 0 && index < end {
+				end = index
+			}
+		}
+		block := remaining[:end]
+		remaining = remaining[end:]
+
+		postedRaw := ofxTagValue(block, "DTPOSTED")
+		if len(postedRaw) < 8 {
+			return parsedBankStatement{}, ErrBankStatementInvalid
+		}
+		postedOn, err := time.Parse("20060102", postedRaw[:8])
+		if err != nil {
+			return parsedBankStatement{}, ErrBankStatementInvalid
+		}
+		amount, err := strconv.ParseFloat(strings.ReplaceAll(ofxTagValue(block, "TRNAMT"), ",", "."), 64)
+		if err != nil {
+			return parsedBankStatement{}, ErrBankStatementInvalid
+		}
+
+		name := ofxTagValue(block, "NAME")
+		memo := ofxTagValue(block, "MEMO")
+		description := joinStatementText(name, memo)
+		payerName := name
+		if payerName == "" {
+			payerName = memo
+		}
+
+		line := NewBankStatementLine{
+			FitID:         ofxTagValue(block, "FITID"),
+			PostedOn:      postedOn,
+			Amount:        roundProjectMoney(amount),
+			Description:   description,
+			PayerName:     payerName,
+			PayerDocument: extractStatementTaxID(description),
+		}
+		if line.FitID == "" {
+			line.FitID = derivedStatementLineID(line, occurrences)
+		}
+		statement.Lines = append(statement.Lines, line)
+	}
+
+	return statement, nil
+}
+
+func ofxTagValue(content string, tag string) string {
+	opening := "<" + tag + ">"
+	start := indexFold(content, opening)
+	if start < 0 {
+		return ""
+	}
+
+	value := content[start+len(opening):]
+	if end := strings.IndexAny(value, "<\r\n"); end >= 0 {
+		value = value[:end]
+	}
+
+	return strings.TrimSpace(value)
+}
+
+func parseCSVStatement(content string, layout CSVStatementLayout) (parsedBankStatement, error) {
+	content = strings.TrimPrefix(content, "\ufeff")
+
+	delimiter, err := resolveCSVStatementDelimiter(layout.Delimiter, content)
+	if err != nil {
+		return parsedBankStatement{}, err
+	}
+
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil || len(records) == 0 {
+		return parsedBankStatement{}, ErrBankStatementInvalid
+	}
+
+	hasHeader := true
+	if layout.HasHeader != nil {
+		hasHeader = *layout.HasHeader
+	}
+	var header []string
+	if hasHeader {
+		header = records[0]
+		records = records[1:]
+	}
+
+	dateIndex, err := resolveCSVStatementColumn(layout.DateColumn, header, defaultCSVStatementColumns.date, "1", true)
+	if err != nil {
+		return parsedBankStatement{}, err
+	}
+	amountIndex, err := resolveCSVStatementColumn(layout.AmountColumn, header, defaultCSVStatementColumns.amount, "2", true)
+	if err != nil {
+		return parsedBankStatement{}, err
+	}
+	descriptionIndex, err := resolveCSVStatementColumn(layout.DescriptionColumn, header, defaultCSVStatementColumns.description, "3", false)
+	if err != nil {
+		return parsedBankStatement{}, err
+	}
+	payerNameIndex, err := resolveCSVStatementColumn(layout.PayerNameColumn, header, defaultCSVStatementColumns.payerName, "", false)
+	if err != nil {
+		return parsedBankStatement{}, err
+	}
+	payerDocumentIndex, err := resolveCSVStatementColumn(layout.PayerDocumentColumn, header, defaultCSVStatementColumns.payerDocument, "", false)
+	if err != nil {
+		return parsedBankStatement{}, err
+	}
+	idIndex, err := resolveCSVStatementColumn(layout.IDColumn, header, defaultCSVStatementColumns.id, "", false)
+	if err != nil {
+		return parsedBankStatement{}, err
+	}
+
+	dateLayouts := defaultCSVStatementDateLayouts
+	if customLayout := convertStatementDateFormat(layout.DateFormat); customLayout != "" {
+		dateLayouts = []string{customLayout}
+	}
+
+	statement := parsedBankStatement{}
+	occurrences := map[string]int{}
+	for _, record := range records {
+		dateValue := csvStatementCell(record, dateIndex)
+		amountValue := csvStatementCell(record, amountIndex)
+		description := csvStatementCell(record, descriptionIndex)
+		if dateValue == "" || amountValue == "" {
+			continue
+		}
+		// Balance rows ("SALDO ANTERIOR", "SALDO DO DIA") are not transactions.
+		if strings.HasPrefix(strings.ToLower(asciiAccentReplacer.Replace(description)), "saldo") {
+			continue
+		}
+
+		postedOn, err := parseStatementDate(dateValue, dateLayouts)
+		if err != nil {
+			return parsedBankStatement{}, ErrBankStatementInvalid
+		}
+		amount, err := parseStatementAmount(amountValue)
+		if err != nil {
+			return parsedBankStatement{}, ErrBankStatementInvalid
+		}
+
+		payerName := csvStatementCell(record, payerNameIndex)
+		payerDocument := normalizeTaxID(csvStatementCell(record, payerDocumentIndex))
+		if payerDocument == "" {
+			payerDocument = extractStatementTaxID(joinStatementText(description, payerName))
+		}
+		if payerName == "" {
+			payerName = description
+		}
+
+		line := NewBankStatementLine{
+			FitID:         csvStatementCell(record, idIndex),
+			PostedOn:      postedOn,
+			Amount:        amount,
+			Description:   description,
+			PayerName:     payerName,
+			PayerDocument: payerDocument,
+		}
+		if line.FitID == "" {
+			line.FitID = derivedStatementLineID(line, occurrences)
+		}
+		statement.Lines = append(statement.Lines, line)
+	}
+
+	return statement, nil
+}
+
+func resolveCSVStatementDelimiter(value string, content string) (rune, error) {
+	raw := value
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "":
+		firstLine := content
+		if index := strings.IndexAny(content, "\r\n"); index >= 0 {
+			firstLine = content[:index]
+		}
+		best := ';'
+		bestCount := strings.Count(firstLine, ";")
+		for _, candidate := range []rune{',', '\t', '|'} {
+			if count := strings.Count(firstLine, string(candidate)); count > bestCount {
+				best = candidate
+				bestCount = count
+			}
+		}
+		return best, nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+
+	if utf8.RuneCountInString(raw) != 1 {
+		return 0, ErrInvalidInput
+	}
+	delimiter, _ := utf8.DecodeRuneInString(raw)
+	if delimiter == '"' || delimiter == '\r' || delimiter == '\n' {
+		return 0, ErrInvalidInput
+	}
+
+	return delimiter, nil
+}
+
+// resolveCSVStatementColumn returns the zero-based index for a configured
+// column, falling back to well-known header names (or a fixed position for
+// files without a header). Unknown optional columns resolve to -1.
+func resolveCSVStatementColumn(
+	configured string,
+	header []string,
+	defaultNames []string,
+	defaultPosition string,
+	required bool,
+) (int, error) {
+	value := strings.TrimSpace(configured)
+	if value != "" {
+		if position, err := strconv.Atoi(value); err == nil {
+			if position < 1 {
+				return -1, ErrInvalidInput
+			}
+			return position - 1, nil
+		}
+		if index := findCSVStatementHeader(header, []string{value}); index >= 0 {
+			return index, nil
+		}
+		return -1, ErrInvalidInput
+	}
+
+	if len(header) > 0 {
+		if index := findCSVStatementHeader(header, defaultNames); index >= 0 {
+			return index, nil
+		}
+	} else if defaultPosition != "" {
+		position, _ := strconv.Atoi(defaultPosition)
+		return position - 1, nil
+	}
+
+	if required {
+		return -1, ErrInvalidInput
+	}
+	return -1, nil
+}
+
+func findCSVStatementHeader(header []string, names []string) int {
+	for _, name := range names {
+		wanted := normalizeCSVStatementHeader(name)
+		for index, column := range header {
+			if normalizeCSVStatementHeader(column) == wanted {
+				return index
+			}
+		}
+	}
+
+	return -1
+}
+
+func normalizeCSVStatementHeader(value string) string {
+	folded := strings.ToLower(asciiAccentReplacer.Replace(strings.TrimPrefix(value, "\ufeff")))
+	return strings.Join(strings.Fields(folded), " ")
+}
+
+func csvStatementCell(record []string, index int) string {
+	if index < 0 || index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
+
+// convertStatementDateFormat accepts either a Go layout or the DD/MM/YYYY
+// style notation people copy from their bank's export settings.
+func convertStatementDateFormat(value string) string {
+	format := strings.TrimSpace(value)
+	if format == "" {
+		return ""
+	}
+
+	replacer := strings.NewReplacer("YYYY", "2006", "yyyy", "2006", "YY", "06", "yy", "06", "MM", "01", "DD", "02", "dd", "02")
+	return replacer.Replace(format)
+}
+
+func parseStatementDate(value string, layouts []string) (time.Time, error) {
+	raw := strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, raw); err == nil {
+			return parsed, nil
+		}
+		if len(raw) > len(layout) {
+			if parsed, err := time.Parse(layout, raw[:len(layout)]); err == nil {
+				return parsed, nil
+			}
+		}
+	}
+
+	return time.Time{}, ErrBankStatementInvalid
+}
+
+// parseStatementAmount understands "1.234,56", "1,234.56", "R$ 10,00",
+// "(10,00)" and the C/D suffix some banks use for credits and debits.
+func parseStatementAmount(value string) (float64, error) {
+	raw := strings.ToUpper(strings.TrimSpace(value))
+	raw = strings.ReplaceAll(raw, "R$", "")
+	raw = strings.ReplaceAll(raw, " ", "")
+
+	negative := false
+	switch {
+	case strings.HasSuffix(raw, "D"):
+		negative = true
+		raw = strings.TrimSuffix(raw, "D")
+	case strings.HasSuffix(raw, "C"):
+		raw = strings.TrimSuffix(raw, "C")
+	}
+	if strings.HasPrefix(raw, "(") && strings.HasSuffix(raw, ")") {
+		negative = true
+		raw = strings.Trim(raw, "()")
+	}
+	if strings.HasSuffix(raw, "-") {
+		negative = true
+		raw = strings.TrimSuffix(raw, "-")
+	}
+	if strings.HasPrefix(raw, "-") {
+		negative = !negative
+		raw = strings.TrimPrefix(raw, "-")
+	}
+	raw = strings.TrimPrefix(raw, "+")
+
+	lastComma := strings.LastIndex(raw, ",")
+	lastDot := strings.LastIndex(raw, ".")
+	switch {
+	case lastComma >= 0 && lastDot >= 0:
+		if lastComma > lastDot {
+			raw = strings.ReplaceAll(raw, ".", "")
+			raw = strings.ReplaceAll(raw, ",", ".")
+		} else {
+			raw = strings.ReplaceAll(raw, ",", "")
+		}
+	case lastComma >= 0:
+		if strings.Count(raw, ",") == 1 && len(raw)-lastComma-1 <= 2 {
+			raw = strings.ReplaceAll(raw, ",", ".")
+		} else {
+			raw = strings.ReplaceAll(raw, ",", "")
+		}
+	case strings.Count(raw, ".") > 1:
+		raw = strings.ReplaceAll(raw, ".", "")
+	}
+
+	amount, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, err
+	}
+	if negative {
+		amount = -amount
+	}
+
+	return roundProjectMoney(amount), nil
+}
+
+func extractStatementTaxID(value string) string {
+	for _, candidate := range bankStatementDocumentCandidatePattern.FindAllString(value, -1) {
+		if document := normalizeTaxID(candidate); document != "" {
+			return document
+		}
+	}
+
+	return ""
+}
+
+func joinStatementText(first string, second string) string {
+	first = strings.TrimSpace(first)
+	second = strings.TrimSpace(second)
+	switch {
+	case first == "":
+		return second
+	case second == "" || strings.EqualFold(first, second):
+		return first
+	default:
+		return first + " " + second
+	}
+}
+
+// derivedStatementLineID gives lines without a bank identifier a stable key,
+// so importing the same file twice does not duplicate entries while two
+// identical transactions on the same day are still kept apart.
+func derivedStatementLineID(line NewBankStatementLine, occurrences map[string]int) string {
+	base := fmt.Sprintf(
+		"%s|%.2f|%s|%s",
+		line.PostedOn.Format("2006-01-02"),
+		line.Amount,
+		strings.ToLower(line.Description),
+		line.PayerDocument,
+	)
+	occurrences[base]++
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", base, occurrences[base])))
+	return "gen-" + hex.EncodeToString(sum[:12])
+}
+
+func indexFold(value string, substr string) int {
+	size := len(substr)
+	for i := 0; i+size <= len(value); i++ {
+		if strings.EqualFold(value[i:i+size], substr) {
+			return i
+		}
+	}
+
+	return -1
+}
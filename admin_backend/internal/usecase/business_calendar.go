@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	// The runtime image has no zoneinfo database.
+	_ "time/tzdata"
+)
+
+// maxBusinessCalendarDays bounds the day-by-day walks below; no SLA target
+// spans more than a few weeks.
+const maxBusinessCalendarDays = 3660
+
+// BusinessCalendar counts time only inside the working hours of the working
+// days, in its own time zone.
+type BusinessCalendar struct {
+	location    *time.Location
+	startMinute int
+	endMinute   int
+	weekdays    [7]bool
+}
+
+// ParseBusinessCalendar reads hours such as "09:00-18:00", days as a list
+// or range of weekday numbers with Sunday as 0 (e.g. "1-5" or "1,2,3,4,5,6")
+// and an IANA time zone.
+func ParseBusinessCalendar(hours string, days string, timezone string) (BusinessCalendar, error) {
+	location, err := time.LoadLocation(strings.TrimSpace(timezone))
+	if err != nil {
+		return BusinessCalendar{}, fmt.Errorf("time zone %q: %w", timezone, ErrInvalidInput)
+	}
+
+	start, end, ok := strings.Cut(strings.TrimSpace(hours), "-")
+	if !ok {
+		return BusinessCalendar{}, fmt.Errorf("business hours %q: %w", hours, ErrInvalidInput)
+	}
+	startMinute, startOK := parseClockMinutes(start)
+	endMinute, endOK := parseClockMinutes(end)
+	if !startOK || !endOK || endMinute <= startMinute {
+		return BusinessCalendar{}, fmt.Errorf("business hours %q: %w", hours, ErrInvalidInput)
+	}
+
+	calendar := BusinessCalendar{
+		location:    location,
+		startMinute: startMinute,
+		endMinute:   endMinute,
+	}
+
+	anyDay := false
+	for _, part := range strings.Split(days, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		first, last, isRange := strings.Cut(part, "-")
+		from, fromErr := strconv.Atoi(strings.TrimSpace(first))
+		to := from
+		var toErr error
+		if isRange {
+			to, toErr = strconv.Atoi(strings.TrimSpace(last))
+		}
+		if fromErr != nil || toErr != nil || from < 0 || to > 6 || from > to {
+			return BusinessCalendar{}, fmt.Errorf("business days %q: %w", days, ErrInvalidInput)
+		}
+		for day := from; day <= to; day++ {
+			calendar.weekdays[day] = true
+			anyDay = true
+		}
+	}
+	if !anyDay {
+		return BusinessCalendar{}, fmt.Errorf("business days %q: %w", days, ErrInvalidInput)
+	}
+
+	return calendar, nil
+}
+
+// AddMinutes returns the instant reached after the given amount of business
+// time has passed since from.
+func (c BusinessCalendar) AddMinutes(from time.Time, minutes int) time.Time {
+	current := from.In(c.location)
+	remaining := time.Duration(minutes) * time.Minute
+
+	for day := 0; day < maxBusinessCalendarDays; day++ {
+		dayStart, dayEnd := c.bounds(current)
+		if !c.weekdays[current.Weekday()] || !current.Before(dayEnd) {
+			current = c.nextDayStart(current)
+			continue
+		}
+		if current.Before(dayStart) {
+			current = dayStart
+		}
+
+		available := dayEnd.Sub(current)
+		if remaining <= available {
+			return current.Add(remaining)
+		}
+		remaining -= available
+		current = c.nextDayStart(current)
+	}
+
+	return current
+}
+
+// MinutesBetween counts the business minutes from from to to; the result is
+// negative when to is before from.
+func (c BusinessCalendar) MinutesBetween(from time.Time, to time.Time) int {
+	if to.Before(from) {
+		return -c.MinutesBetween(to, from)
+	}
+
+	total := time.Duration(0)
+	current := from.In(c.location)
+	for day := 0; day < maxBusinessCalendarDays && current.Before(to); day++ {
+		dayStart, dayEnd := c.bounds(current)
+		if c.weekdays[current.Weekday()] {
+			start := current
+			if start.Before(dayStart) {
+				start = dayStart
+			}
+			end := dayEnd
+			if to.Before(end) {
+				end = to
+			}
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+		current = c.nextDayStart(current)
+	}
+
+	return int(total / time.Minute)
+}
+
+func (c BusinessCalendar) Location() *time.Location {
+	return c.location
+}
+
+func (c BusinessCalendar) bounds(value time.Time) (time.Time, time.Time) {
+	year, month, day := value.Date()
+	start := time.Date(year, month, day, c.startMinute/60, c.startMinute%60, 0, 0, c.location)
+	end := time.Date(year, month, day, c.endMinute/60, c.endMinute%60, 0, 0, c.location)
+	return start, end
+}
+
+func (c BusinessCalendar) nextDayStart(value time.Time) time.Time {
+	year, month, day := value.Date()
+	return time.Date(year, month, day+1, c.startMinute/60, c.startMinute%60, 0, 0, c.location)
+}
+
+func parseClockMinutes(value string) (int, bool) {
+	hours, minutes, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		return 0, false
+	}
+	hour, hourErr := strconv.Atoi(hours)
+	minute, minuteErr := strconv.Atoi(minutes)
+	if hourErr != nil || minuteErr != nil || hour < 0 || hour > 24 || minute < 0 || minute > 59 {
+		return 0, false
+	}
+	total := hour*60 + minute
+	if total > 24*60 {
+		return 0, false
+	}
+	return total, true
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import "context"
+
+type BusinessMetricsRepository interface {
+	CountOpenServiceRequests(ctx context.Context) ([]ServiceRequestStatusCount, error)
+	SummarizeOverdueReceivables(ctx context.Context) ([]OverdueReceivableSummary, error)
+}
+
+// BusinessMetricsService reads the operational figures exposed as gauges on
+// /metrics.
+type BusinessMetricsService struct {
+	repo BusinessMetricsRepository
+}
+
+func NewBusinessMetricsService(repo BusinessMetricsRepository) *BusinessMetricsService {
+	return &BusinessMetricsService{repo: repo}
+}
+
+// ServiceRequestStatusCount is the number of service requests in a status
+// not flagged as closed.
+type ServiceRequestStatusCount struct {
+	Status string
+	Count  int
+}
+
+// OverdueReceivableSummary totals the pending receivables of one source
+// (PaymentReminderSourceRevenue or PaymentReminderSourceMonthlyCharge) whose
+// due date has passed.
+type OverdueReceivableSummary struct {
+	Source string
+	Count  int
+	Amount float64
+}
+
+func (s *BusinessMetricsService) OpenServiceRequests(ctx context.Context) ([]ServiceRequestStatusCount, error) {
+	ctx, span := startSpan(ctx, "BusinessMetricsService.OpenServiceRequests")
+	defer span.End()
+
+	return s.repo.CountOpenServiceRequests(ctx)
+}
+
+func (s *BusinessMetricsService) OverdueReceivables(ctx context.Context) ([]OverdueReceivableSummary, error) {
+	ctx, span := startSpan(ctx, "BusinessMetricsService.OverdueReceivables")
+	defer span.End()
+
+	return s.repo.SummarizeOverdueReceivables(ctx)
+}
@@ -25,21 +25,39 @@ type ClientRepository interface {
 	Create(ctx context.Context, input CreateClientInput) (ClientDetail, error)
 	Update(ctx context.Context, input UpdateClientInput) (ClientDetail, error)
 	Deactivate(ctx context.Context, clientID string) (ClientDetail, error)
+	ListClientLocations(ctx context.Context, filter ClientLocationFilter) ([]ClientLocation, error)
 }
 
 type ZipCodeLookup interface {
 	Lookup(ctx context.Context, zipCode string) (ZipCodeLookupResult, error)
 }
 
+// CompanyLookup fetches the public registration of a company by its CNPJ
+// (14 digits, already validated).
+type CompanyLookup interface {
+	LookupCompany(ctx context.Context, cnpj string) (CompanyLookupResult, error)
+}
+
 type ClientService struct {
-	repo          ClientRepository
-	zipCodeLookup ZipCodeLookup
+	repo             ClientRepository
+	zipCodeLookup    ZipCodeLookup
+	companyLookup    CompanyLookup
+	addressGeocoding *AddressGeocodingService
 }
 
-func NewClientService(repo ClientRepository, zipCodeLookup ZipCodeLookup) *ClientService {
+// NewClientService requests addressGeocoding, which may be nil, after
+// every save that leaves addresses without coordinates.
+func NewClientService(
+	repo ClientRepository,
+	zipCodeLookup ZipCodeLookup,
+	companyLookup CompanyLookup,
+	addressGeocoding *AddressGeocodingService,
+) *ClientService {
 	return &ClientService{
-		repo:          repo,
-		zipCodeLookup: zipCodeLookup,
+		repo:             repo,
+		zipCodeLookup:    zipCodeLookup,
+		companyLookup:    companyLookup,
+		addressGeocoding: addressGeocoding,
 	}
 }
 
@@ -49,6 +67,8 @@ type ClientListItem struct {
 	Email          string    `json:"email"`
 	Login          string    `json:"login"`
 	Avatar         string    `json:"avatar"`
+	TaxID          string    `json:"taxId"`
+	TaxIDType      string    `json:"taxIdType"`
 	Phone          string    `json:"phone"`
 	Address        string    `json:"address"`
 	Active         bool      `json:"active"`
@@ -59,25 +79,28 @@ type ClientListItem struct {
 }
 
 type ClientAddress struct {
-	ID           string    `json:"id"`
-	ClientID     string    `json:"clientId"`
-	Label        string    `json:"label"`
-	Country      string    `json:"country"`
-	ZipCode      string    `json:"zipCode"`
-	StreetType   string    `json:"streetType"`
-	StreetName   string    `json:"streetName"`
-	Street       string    `json:"street"`
-	Number       string    `json:"number"`
-	Neighborhood string    `json:"neighborhood"`
-	City         string    `json:"city"`
-	State        string    `json:"state"`
-	Complement   string    `json:"complement"`
-	Latitude     *float64  `json:"latitude,omitempty"`
-	Longitude    *float64  `json:"longitude,omitempty"`
-	Position     int       `json:"position"`
-	Active       bool      `json:"active"`
-	Created      time.Time `json:"created"`
-	Updated      time.Time `json:"updated"`
+	ID           string   `json:"id"`
+	ClientID     string   `json:"clientId"`
+	Label        string   `json:"label"`
+	Country      string   `json:"country"`
+	ZipCode      string   `json:"zipCode"`
+	StreetType   string   `json:"streetType"`
+	StreetName   string   `json:"streetName"`
+	Street       string   `json:"street"`
+	Number       string   `json:"number"`
+	Neighborhood string   `json:"neighborhood"`
+	City         string   `json:"city"`
+	State        string   `json:"state"`
+	Complement   string   `json:"complement"`
+	Latitude     *float64 `json:"latitude,omitempty"`
+	Longitude    *float64 `json:"longitude,omitempty"`
+	// GeocodeStatus tells whether the coordinates are still pending, were
+	// located or entered by hand, or could not be found.
+	GeocodeStatus string    `json:"geocodeStatus"`
+	Position      int       `json:"position"`
+	Active        bool      `json:"active"`
+	Created       time.Time `json:"created"`
+	Updated       time.Time `json:"updated"`
 }
 
 type ClientPhone struct {
@@ -98,6 +121,8 @@ type ClientDetail struct {
 	Email     string          `json:"email"`
 	Login     string          `json:"login"`
 	Avatar    string          `json:"avatar"`
+	TaxID     string          `json:"taxId"`
+	TaxIDType string          `json:"taxIdType"`
 	Active    bool            `json:"active"`
 	Created   time.Time       `json:"created"`
 	Updated   time.Time       `json:"updated"`
@@ -117,10 +142,26 @@ type ClientAddressInput struct {
 	City         string
 	State        string
 	Complement   string
-	Latitude     *float64
-	Longitude    *float64
-	Position     *int
-	Active       *bool
+	// Latitude and Longitude are set together or not at all. When unset,
+	// the coordinates of the saved address with the same GeocodeQuery are
+	// kept, or the address is geocoded in the background.
+	Latitude  *float64
+	Longitude *float64
+	Position  *int
+	Active    *bool
+}
+
+// GeocodeQuery is what decides where the address is.
+func (a ClientAddressInput) GeocodeQuery() GeocodeQuery {
+	return GeocodeQuery{
+		Country:      a.Country,
+		ZipCode:      a.ZipCode,
+		Street:       a.Street,
+		Number:       a.Number,
+		Neighborhood: a.Neighborhood,
+		City:         a.City,
+		State:        a.State,
+	}
 }
 
 type ClientPhoneInput struct {
@@ -131,17 +172,22 @@ type ClientPhoneInput struct {
 	Active      *bool
 }
 
+// CreateClientInput.TaxID is a CPF or CNPJ, with or without punctuation, and
+// may be empty.
 type CreateClientInput struct {
 	Name      string
 	Email     string
 	Login     string
 	Password  string
 	Avatar    string
+	TaxID     string
 	Active    bool
 	Addresses []ClientAddressInput
 	Phones    []ClientPhoneInput
 }
 
+// UpdateClientInput keeps the current tax id when TaxID is nil; an empty
+// value clears it.
 type UpdateClientInput struct {
 	ID        string
 	Name      string
@@ -149,6 +195,7 @@ type UpdateClientInput struct {
 	Login     string
 	Password  string
 	Avatar    string
+	TaxID     *string
 	Active    *bool
 	Addresses *[]ClientAddressInput
 	Phones    *[]ClientPhoneInput
@@ -167,11 +214,43 @@ type ZipCodeLookupResult struct {
 	SIAFI        string `json:"siafi"`
 }
 
+// CompanyLookupResult is the public registration of a company. Name is the
+// suggested client name: the trade name, or the legal name when the company
+// has none.
+type CompanyLookupResult struct {
+	TaxID     string         `json:"taxId"`
+	Name      string         `json:"name"`
+	LegalName string         `json:"legalName"`
+	TradeName string         `json:"tradeName"`
+	Status    string         `json:"status"`
+	Email     string         `json:"email"`
+	Phone     string         `json:"phone"`
+	Address   CompanyAddress `json:"address"`
+}
+
+type CompanyAddress struct {
+	ZipCode      string `json:"zipCode"`
+	StreetType   string `json:"streetType"`
+	StreetName   string `json:"streetName"`
+	Street       string `json:"street"`
+	Number       string `json:"number"`
+	Neighborhood string `json:"neighborhood"`
+	City         string `json:"city"`
+	State        string `json:"state"`
+	Complement   string `json:"complement"`
+}
+
 func (s *ClientService) List(ctx context.Context, onlyActive bool) ([]ClientListItem, error) {
+	ctx, span := startSpan(ctx, "ClientService.List")
+	defer span.End()
+
 	return s.repo.List(ctx, onlyActive)
 }
 
 func (s *ClientService) GetDetail(ctx context.Context, clientID string) (ClientDetail, error) {
+	ctx, span := startSpan(ctx, "ClientService.GetDetail")
+	defer span.End()
+
 	id := strings.TrimSpace(clientID)
 	if id == "" {
 		return ClientDetail{}, ErrInvalidInput
@@ -181,24 +260,63 @@ func (s *ClientService) GetDetail(ctx context.Context, clientID string) (ClientD
 }
 
 func (s *ClientService) Create(ctx context.Context, input CreateClientInput) (ClientDetail, error) {
+	ctx, span := startSpan(ctx, "ClientService.Create")
+	defer span.End()
+
 	normalizedInput, err := normalizeCreateClientInput(input)
 	if err != nil {
 		return ClientDetail{}, err
 	}
 
-	return s.repo.Create(ctx, normalizedInput)
+	client, err := s.repo.Create(ctx, normalizedInput)
+	if err != nil {
+		return ClientDetail{}, err
+	}
+
+	s.requestGeocoding(client)
+	return client, nil
 }
 
 func (s *ClientService) Update(ctx context.Context, input UpdateClientInput) (ClientDetail, error) {
+	ctx, span := startSpan(ctx, "ClientService.Update")
+	defer span.End()
+
 	normalizedInput, err := normalizeUpdateClientInput(input)
 	if err != nil {
 		return ClientDetail{}, err
 	}
 
-	return s.repo.Update(ctx, normalizedInput)
+	client, err := s.repo.Update(ctx, normalizedInput)
+	if err != nil {
+		return ClientDetail{}, err
+	}
+
+	s.requestGeocoding(client)
+	return client, nil
+}
+
+// ListLocations returns the located active addresses of active clients,
+// within filter.BoundingBox when set.
+func (s *ClientService) ListLocations(ctx context.Context, filter ClientLocationFilter) ([]ClientLocation, error) {
+	ctx, span := startSpan(ctx, "ClientService.ListLocations")
+	defer span.End()
+
+	return s.repo.ListClientLocations(ctx, filter)
+}
+
+func (s *ClientService) requestGeocoding(client ClientDetail) {
+	for _, address := range client.Addresses {
+		if address.GeocodeStatus == GeocodeStatusPending {
+			s.addressGeocoding.Request()
+			return
+		}
+	}
 }
 
 func (s *ClientService) Deactivate(ctx context.Context, clientID string) (ClientDetail, error) {
+	ctx, span := startSpan(ctx, "ClientService.Deactivate")
+	defer span.End()
+
 	id := strings.TrimSpace(clientID)
 	if id == "" {
 		return ClientDetail{}, ErrInvalidInput
@@ -208,6 +326,9 @@ func (s *ClientService) Deactivate(ctx context.Context, clientID string) (Client
 }
 
 func (s *ClientService) LookupZipCode(ctx context.Context, zipCode string) (ZipCodeLookupResult, error) {
+	ctx, span := startSpan(ctx, "ClientService.LookupZipCode")
+	defer span.End()
+
 	if s.zipCodeLookup == nil {
 		return ZipCodeLookupResult{}, ErrZipCodeUnavailable
 	}
@@ -220,6 +341,64 @@ func (s *ClientService) LookupZipCode(ctx context.Context, zipCode string) (ZipC
 	return s.zipCodeLookup.Lookup(ctx, normalizedZipCode)
 }
 
+// LookupCompany fills in the company name and address of a CNPJ so the
+// client form can be prefilled.
+func (s *ClientService) LookupCompany(ctx context.Context, cnpj string) (CompanyLookupResult, error) {
+	ctx, span := startSpan(ctx, "ClientService.LookupCompany")
+	defer span.End()
+
+	if s.companyLookup == nil {
+		return CompanyLookupResult{}, ErrCompanyLookupUnavailable
+	}
+
+	normalizedCNPJ := normalizeTaxID(cnpj)
+	if taxIDType(normalizedCNPJ) != TaxIDTypeCNPJ {
+		return CompanyLookupResult{}, ErrInvalidInput
+	}
+
+	result, err := s.companyLookup.LookupCompany(ctx, normalizedCNPJ)
+	if err != nil {
+		return CompanyLookupResult{}, err
+	}
+
+	result.TaxID = normalizedCNPJ
+	if strings.TrimSpace(result.Name) == "" {
+		result.Name = result.TradeName
+		if strings.TrimSpace(result.Name) == "" {
+			result.Name = result.LegalName
+		}
+	}
+	result.Address.ZipCode = nonDigitPattern.ReplaceAllString(result.Address.ZipCode, "")
+	result.Address.State = strings.ToUpper(strings.TrimSpace(result.Address.State))
+	if result.Address.StreetName == "" {
+		streetType, streetName := splitStreet(result.Address.Street)
+		if result.Address.StreetType == "" {
+			result.Address.StreetType = streetType
+		}
+		result.Address.StreetName = streetName
+	}
+	result.Address.StreetType = normalizeStreetType(result.Address.StreetType)
+	if _, ok := allowedStreetTypes[result.Address.StreetType]; !ok {
+		result.Address.StreetType = "outro"
+	}
+
+	return result, nil
+}
+
+// normalizeClientTaxID validates the check digits of a CPF or CNPJ and
+// returns its digits; an empty value is allowed.
+func normalizeClientTaxID(value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+
+	normalized := normalizeTaxID(value)
+	if normalized == "" {
+		return "", ErrClientTaxIDInvalid
+	}
+	return normalized, nil
+}
+
 func normalizeCreateClientInput(input CreateClientInput) (CreateClientInput, error) {
 	normalizedAddresses, err := normalizeAddressInputs(input.Addresses)
 	if err != nil {
@@ -228,12 +407,18 @@ func normalizeCreateClientInput(input CreateClientInput) (CreateClientInput, err
 
 	normalizedPhones := normalizePhoneInputs(input.Phones)
 
+	taxID, err := normalizeClientTaxID(input.TaxID)
+	if err != nil {
+		return CreateClientInput{}, err
+	}
+
 	normalizedInput := CreateClientInput{
 		Name:      strings.TrimSpace(input.Name),
 		Email:     strings.TrimSpace(input.Email),
 		Login:     strings.ToLower(strings.TrimSpace(input.Login)),
 		Password:  strings.TrimSpace(input.Password),
 		Avatar:    strings.TrimSpace(input.Avatar),
+		TaxID:     taxID,
 		Active:    input.Active,
 		Addresses: normalizedAddresses,
 		Phones:    normalizedPhones,
@@ -265,6 +450,15 @@ func normalizeUpdateClientInput(input UpdateClientInput) (UpdateClientInput, err
 		normalizedPhones = &phones
 	}
 
+	var normalizedTaxID *string
+	if input.TaxID != nil {
+		taxID, err := normalizeClientTaxID(*input.TaxID)
+		if err != nil {
+			return UpdateClientInput{}, err
+		}
+		normalizedTaxID = &taxID
+	}
+
 	normalizedInput := UpdateClientInput{
 		ID:        strings.TrimSpace(input.ID),
 		Name:      strings.TrimSpace(input.Name),
@@ -272,6 +466,7 @@ func normalizeUpdateClientInput(input UpdateClientInput) (UpdateClientInput, err
 		Login:     strings.ToLower(strings.TrimSpace(input.Login)),
 		Password:  strings.TrimSpace(input.Password),
 		Avatar:    strings.TrimSpace(input.Avatar),
+		TaxID:     normalizedTaxID,
 		Active:    input.Active,
 		Addresses: normalizedAddresses,
 		Phones:    normalizedPhones,
@@ -330,6 +525,13 @@ func normalizeAddressInputs(addresses []ClientAddressInput) ([]ClientAddressInpu
 		if normalizedAddress.ZipCode != "" && len(normalizedAddress.ZipCode) != 8 {
 			return nil, ErrInvalidInput
 		}
+		if (normalizedAddress.Latitude == nil) != (normalizedAddress.Longitude == nil) {
+			return nil, ErrInvalidInput
+		}
+		if normalizedAddress.Latitude != nil &&
+			!validCoordinates(*normalizedAddress.Latitude, *normalizedAddress.Longitude) {
+			return nil, ErrInvalidInput
+		}
 		if _, ok := allowedStreetTypes[normalizedAddress.StreetType]; !ok {
 			return nil, ErrInvalidInput
 		}
@@ -0,0 +1,244 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Geocode statuses of a client address.
+const (
+	// GeocodeStatusPending addresses wait for the geocoding job.
+	GeocodeStatusPending = "pending"
+	// GeocodeStatusLocated addresses were placed by the geocoder.
+	GeocodeStatusLocated = "located"
+	// GeocodeStatusNotFound addresses are unknown to the geocoder; they are
+	// retried only when the address changes.
+	GeocodeStatusNotFound = "not_found"
+	// GeocodeStatusFailed addresses could not be geocoded after
+	// addressGeocodeMaxAttempts attempts while the geocoder was unavailable.
+	GeocodeStatusFailed = "failed"
+	// GeocodeStatusManual addresses had their coordinates entered by hand.
+	GeocodeStatusManual = "manual"
+)
+
+const (
+	addressGeocodeBatchSize   = 20
+	addressGeocodeMaxAttempts = 6
+	addressGeocodeRetryDelay  = 5 * time.Minute
+	// addressGeocodeLease keeps claimed addresses from other replicas while
+	// one geocodes them.
+	addressGeocodeLease = 2 * time.Minute
+)
+
+// GeocodeQuery is the part of an address that decides where it is. Two
+// addresses with the same query share their coordinates.
+type GeocodeQuery struct {
+	Country      string
+	ZipCode      string
+	Street       string
+	Number       string
+	Neighborhood string
+	City         string
+	State        string
+}
+
+// Geocoder places an address on the map. It returns ErrGeocodeNotFound when
+// the address is unknown and ErrGeocoderUnavailable when the service could
+// not answer.
+type Geocoder interface {
+	Geocode(ctx context.Context, query GeocodeQuery) (Coordinates, error)
+}
+
+type Coordinates struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// PendingAddressGeocode is a client address waiting for coordinates.
+type PendingAddressGeocode struct {
+	AddressID string
+	Attempts  int
+	Query     GeocodeQuery
+}
+
+// AddressGeocodeUpdate records one geocoding attempt. Coordinates are only
+// set when Status is GeocodeStatusLocated; NextAttemptAt only when it is
+// GeocodeStatusPending.
+type AddressGeocodeUpdate struct {
+	AddressID     string
+	Status        string
+	Attempts      int
+	Coordinates   *Coordinates
+	NextAttemptAt time.Time
+}
+
+type AddressGeocodingRepository interface {
+	// ClaimPendingAddressGeocodes returns up to limit pending addresses due
+	// for an attempt and postpones their next attempt by lease.
+	ClaimPendingAddressGeocodes(ctx context.Context, limit int, lease time.Duration) ([]PendingAddressGeocode, error)
+	// RecordAddressGeocode is ignored when the address was replaced or
+	// edited by hand meanwhile.
+	RecordAddressGeocode(ctx context.Context, update AddressGeocodeUpdate) error
+}
+
+type AddressGeocodeResult struct {
+	Located  int `json:"located"`
+	NotFound int `json:"notFound"`
+	Retrying int `json:"retrying"`
+	Failed   int `json:"failed"`
+}
+
+// AddressGeocodingService fills in the coordinates of client addresses in
+// the background, so saving a client never waits for the geocoder.
+type AddressGeocodingService struct {
+	repo     AddressGeocodingRepository
+	geocoder Geocoder
+	clock    Clock
+	wake     chan struct{}
+}
+
+func NewAddressGeocodingService(
+	repo AddressGeocodingRepository,
+	geocoder Geocoder,
+	clock Clock,
+) *AddressGeocodingService {
+	return &AddressGeocodingService{
+		repo:     repo,
+		geocoder: geocoder,
+		clock:    clock,
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Request asks for pending addresses to be geocoded without waiting for the
+// next scheduled run.
+func (s *AddressGeocodingService) Request() {
+	if s == nil {
+		return
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Requested receives after Request was called.
+func (s *AddressGeocodingService) Requested() <-chan struct{} {
+	return s.wake
+}
+
+// GeocodePendingAddresses geocodes the addresses due for an attempt. It
+// stops at the first unavailable answer; the other claimed addresses are
+// tried again once their lease ends.
+func (s *AddressGeocodingService) GeocodePendingAddresses(ctx context.Context) (AddressGeocodeResult, error) {
+	ctx, span := startSpan(ctx, "AddressGeocodingService.GeocodePendingAddresses")
+	defer span.End()
+
+	result := AddressGeocodeResult{}
+
+	pending, err := s.repo.ClaimPendingAddressGeocodes(ctx, addressGeocodeBatchSize, addressGeocodeLease)
+	if err != nil {
+		return result, err
+	}
+
+	for _, address := range pending {
+		coordinates, err := s.geocoder.Geocode(ctx, address.Query)
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		update := AddressGeocodeUpdate{AddressID: address.AddressID, Attempts: address.Attempts + 1}
+		switch {
+		case err == nil:
+			update.Status = GeocodeStatusLocated
+			update.Coordinates = &coordinates
+			result.Located++
+		case errors.Is(err, ErrGeocodeNotFound):
+			update.Status = GeocodeStatusNotFound
+			result.NotFound++
+		case update.Attempts >= addressGeocodeMaxAttempts:
+			update.Status = GeocodeStatusFailed
+			result.Failed++
+		default:
+			update.Status = GeocodeStatusPending
+			update.NextAttemptAt = s.clock.Now().Add(addressGeocodeRetryDelay * time.Duration(update.Attempts))
+			result.Retrying++
+		}
+
+		if err := s.repo.RecordAddressGeocode(ctx, update); err != nil {
+			return result, err
+		}
+		if update.Status == GeocodeStatusPending || update.Status == GeocodeStatusFailed {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// BoundingBox limits the client map to an area.
+type BoundingBox struct {
+	MinLongitude float64
+	MinLatitude  float64
+	MaxLongitude float64
+	MaxLatitude  float64
+}
+
+// ParseBoundingBox reads "minLon,minLat,maxLon,maxLat", the GeoJSON bbox
+// order. Boxes crossing the antimeridian are not supported.
+func ParseBoundingBox(value string) (BoundingBox, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return BoundingBox{}, ErrInvalidInput
+	}
+
+	numbers := make([]float64, 0, len(parts))
+	for _, part := range parts {
+		number, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return BoundingBox{}, ErrInvalidInput
+		}
+		numbers = append(numbers, number)
+	}
+
+	box := BoundingBox{
+		MinLongitude: numbers[0],
+		MinLatitude:  numbers[1],
+		MaxLongitude: numbers[2],
+		MaxLatitude:  numbers[3],
+	}
+	if !validCoordinates(box.MinLatitude, box.MinLongitude) ||
+		!validCoordinates(box.MaxLatitude, box.MaxLongitude) ||
+		box.MinLatitude > box.MaxLatitude ||
+		box.MinLongitude > box.MaxLongitude {
+		return BoundingBox{}, ErrInvalidInput
+	}
+	return box, nil
+}
+
+// ClientLocation is a located active address of an active client.
+type ClientLocation struct {
+	AddressID    string
+	ClientID     string
+	ClientName   string
+	Label        string
+	Street       string
+	Number       string
+	Neighborhood string
+	City         string
+	State        string
+	ZipCode      string
+	Coordinates  Coordinates
+}
+
+type ClientLocationFilter struct {
+	BoundingBox *BoundingBox
+}
+
+func validCoordinates(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
@@ -0,0 +1,424 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	ClientOrganizationRoleOwner   = "owner"
+	ClientOrganizationRoleFinance = "finance"
+	ClientOrganizationRoleViewer  = "viewer"
+
+	clientOrganizationInviteTTL = 7 * 24 * time.Hour
+)
+
+type ClientOrganizationRepository interface {
+	GetClientOrganization(ctx context.Context, organizationID string) (ClientOrganization, error)
+	// UpdateClientOrganization returns ErrClientOrganizationTaxIDInUse when
+	// another organization already has the CNPJ.
+	UpdateClientOrganization(ctx context.Context, input UpdateClientOrganizationInput) (ClientOrganization, error)
+	ListClientOrganizationMembers(ctx context.Context, organizationID string) ([]ClientOrganizationMember, error)
+	// UpdateClientOrganizationMember returns
+	// ErrClientOrganizationOwnerRequired when the change would leave the
+	// organization without an active owner.
+	UpdateClientOrganizationMember(
+		ctx context.Context,
+		input UpdateClientOrganizationMemberInput,
+	) (ClientOrganizationMember, error)
+
+	ListClientOrganizationInvites(ctx context.Context, organizationID string) ([]ClientOrganizationInvite, error)
+	// CreateClientOrganizationInvite replaces the pending invite sent to the
+	// same e-mail and returns ErrClientEmailInUse when a client already
+	// uses it.
+	CreateClientOrganizationInvite(
+		ctx context.Context,
+		input CreateClientOrganizationInviteInput,
+	) (ClientOrganizationInvite, error)
+	RevokeClientOrganizationInvite(ctx context.Context, organizationID, inviteID string) error
+	// AcceptClientOrganizationInvite returns ErrNotFound when the token is
+	// unknown, expired, revoked or already used.
+	AcceptClientOrganizationInvite(
+		ctx context.Context,
+		input AcceptClientOrganizationInviteInput,
+	) (ClientPortalAccount, error)
+}
+
+// ClientOrganizationConfig holds the client portal address used in invite
+// links.
+type ClientOrganizationConfig struct {
+	PortalURL string
+}
+
+type ClientOrganization struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	TaxID   string    `json:"taxId"`
+	Active  bool      `json:"active"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+type UpdateClientOrganizationInput struct {
+	OrganizationID string
+	Name           string
+	TaxID          string
+}
+
+// ClientOrganizationMember is a portal user (a row of clients) of the
+// organization.
+type ClientOrganizationMember struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Email   string    `json:"email"`
+	Login   string    `json:"login"`
+	Avatar  string    `json:"avatar"`
+	Role    string    `json:"role"`
+	Active  bool      `json:"active"`
+	Created time.Time `json:"created"`
+}
+
+type UpdateClientOrganizationMemberInput struct {
+	OrganizationID string
+	ClientID       string
+	Role           string
+	Active         *bool
+}
+
+type ClientOrganizationInvite struct {
+	ID                string    `json:"id"`
+	OrganizationID    string    `json:"organizationId"`
+	Email             string    `json:"email"`
+	Role              string    `json:"role"`
+	InvitedByClientID string    `json:"invitedByClientId,omitempty"`
+	InvitedByName     string    `json:"invitedByName,omitempty"`
+	ExpiresAt         time.Time `json:"expiresAt"`
+	Created           time.Time `json:"created"`
+}
+
+type InviteClientOrganizationMemberInput struct {
+	Email string
+	Role  string
+}
+
+type CreateClientOrganizationInviteInput struct {
+	OrganizationID    string
+	Email             string
+	Role              string
+	TokenHash         string
+	InvitedByClientID string
+	ExpiresAt         time.Time
+}
+
+type AcceptClientOrganizationInviteInput struct {
+	Token     string
+	TokenHash string
+	Name      string
+	Login     string
+	Password  string
+}
+
+type ClientOrganizationService struct {
+	repo   ClientOrganizationRepository
+	mailer Mailer
+	ids    IDGenerator
+	clock  Clock
+	config ClientOrganizationConfig
+}
+
+func NewClientOrganizationService(
+	repo ClientOrganizationRepository,
+	mailer Mailer,
+	ids IDGenerator,
+	clock Clock,
+	config ClientOrganizationConfig,
+) *ClientOrganizationService {
+	return &ClientOrganizationService{
+		repo:   repo,
+		mailer: mailer,
+		ids:    ids,
+		clock:  clock,
+		config: ClientOrganizationConfig{
+			PortalURL: strings.TrimRight(strings.TrimSpace(config.PortalURL), "/"),
+		},
+	}
+}
+
+// CanViewFinancials reports whether the portal user may see revenues,
+// charges and payments.
+func (u ClientPortalAuthUser) CanViewFinancials() bool {
+	return u.Role == ClientOrganizationRoleOwner || u.Role == ClientOrganizationRoleFinance
+}
+
+func (s *ClientOrganizationService) GetOrganization(
+	ctx context.Context,
+	actor ClientPortalAuthUser,
+) (ClientOrganization, error) {
+	ctx, span := startSpan(ctx, "ClientOrganizationService.GetOrganization")
+	defer span.End()
+
+	return s.repo.GetClientOrganization(ctx, actor.OrganizationID)
+}
+
+// UpdateOrganization lets owners rename the organization and set its CNPJ.
+func (s *ClientOrganizationService) UpdateOrganization(
+	ctx context.Context,
+	actor ClientPortalAuthUser,
+	input UpdateClientOrganizationInput,
+) (ClientOrganization, error) {
+	ctx, span := startSpan(ctx, "ClientOrganizationService.UpdateOrganization")
+	defer span.End()
+
+	if actor.Role != ClientOrganizationRoleOwner {
+		return ClientOrganization{}, ErrClientRoleForbidden
+	}
+
+	normalizedInput := UpdateClientOrganizationInput{
+		OrganizationID: actor.OrganizationID,
+		Name:           strings.TrimSpace(input.Name),
+		TaxID:          strings.TrimSpace(input.TaxID),
+	}
+	if normalizedInput.Name == "" {
+		return ClientOrganization{}, ErrInvalidInput
+	}
+	if normalizedInput.TaxID != "" {
+		normalizedInput.TaxID = normalizeTaxID(normalizedInput.TaxID)
+		if len(normalizedInput.TaxID) != 14 {
+			return ClientOrganization{}, ErrInvalidInput
+		}
+	}
+
+	return s.repo.UpdateClientOrganization(ctx, normalizedInput)
+}
+
+func (s *ClientOrganizationService) ListMembers(
+	ctx context.Context,
+	actor ClientPortalAuthUser,
+) ([]ClientOrganizationMember, error) {
+	ctx, span := startSpan(ctx, "ClientOrganizationService.ListMembers")
+	defer span.End()
+
+	return s.repo.ListClientOrganizationMembers(ctx, actor.OrganizationID)
+}
+
+// UpdateMember lets owners change the role of a colleague or deactivate
+// their portal access.
+func (s *ClientOrganizationService) UpdateMember(
+	ctx context.Context,
+	actor ClientPortalAuthUser,
+	input UpdateClientOrganizationMemberInput,
+) (ClientOrganizationMember, error) {
+	ctx, span := startSpan(ctx, "ClientOrganizationService.UpdateMember")
+	defer span.End()
+
+	if actor.Role != ClientOrganizationRoleOwner {
+		return ClientOrganizationMember{}, ErrClientRoleForbidden
+	}
+
+	normalizedInput := UpdateClientOrganizationMemberInput{
+		OrganizationID: actor.OrganizationID,
+		ClientID:       strings.TrimSpace(input.ClientID),
+		Role:           strings.ToLower(strings.TrimSpace(input.Role)),
+		Active:         input.Active,
+	}
+	if normalizedInput.ClientID == "" || !isValidClientOrganizationRole(normalizedInput.Role) {
+		return ClientOrganizationMember{}, ErrInvalidInput
+	}
+
+	return s.repo.UpdateClientOrganizationMember(ctx, normalizedInput)
+}
+
+func (s *ClientOrganizationService) ListInvites(
+	ctx context.Context,
+	actor ClientPortalAuthUser,
+) ([]ClientOrganizationInvite, error) {
+	ctx, span := startSpan(ctx, "ClientOrganizationService.ListInvites")
+	defer span.End()
+
+	if actor.Role != ClientOrganizationRoleOwner {
+		return nil, ErrClientRoleForbidden
+	}
+
+	return s.repo.ListClientOrganizationInvites(ctx, actor.OrganizationID)
+}
+
+// InviteMember e-mails a one-time link that lets the colleague create their
+// portal login in the organization. Sending again replaces the previous
+// invite. A failed delivery is logged and the invite kept, so the owner can
+// send it again.
+func (s *ClientOrganizationService) InviteMember(
+	ctx context.Context,
+	actor ClientPortalAuthUser,
+	input InviteClientOrganizationMemberInput,
+) (ClientOrganizationInvite, error) {
+	ctx, span := startSpan(ctx, "ClientOrganizationService.InviteMember")
+	defer span.End()
+
+	if actor.Role != ClientOrganizationRoleOwner {
+		return ClientOrganizationInvite{}, ErrClientRoleForbidden
+	}
+
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	role := strings.ToLower(strings.TrimSpace(input.Role))
+	if role == "" {
+		role = ClientOrganizationRoleViewer
+	}
+	if !strings.Contains(email, "@") || !isValidClientOrganizationRole(role) {
+		return ClientOrganizationInvite{}, ErrInvalidInput
+	}
+
+	token := s.ids.NewID()
+	invite, err := s.repo.CreateClientOrganizationInvite(ctx, CreateClientOrganizationInviteInput{
+		OrganizationID:    actor.OrganizationID,
+		Email:             email,
+		Role:              role,
+		TokenHash:         hashClientOrganizationInviteToken(token),
+		InvitedByClientID: actor.ID,
+		ExpiresAt:         s.clock.Now().Add(clientOrganizationInviteTTL),
+	})
+	if err != nil {
+		return ClientOrganizationInvite{}, err
+	}
+
+	if s.mailer != nil {
+		organization, err := s.repo.GetClientOrganization(ctx, actor.OrganizationID)
+		if err != nil {
+			return ClientOrganizationInvite{}, err
+		}
+
+		message, err := s.buildInviteMessage(invite, organization, actor, token)
+		if err != nil {
+			return ClientOrganizationInvite{}, err
+		}
+		if err := s.mailer.Send(ctx, message); err != nil {
+			slog.ErrorContext(ctx, "client organization invite could not be sent", "invite_id", invite.ID, "error", err)
+		}
+	}
+
+	return invite, nil
+}
+
+func (s *ClientOrganizationService) RevokeInvite(
+	ctx context.Context,
+	actor ClientPortalAuthUser,
+	inviteID string,
+) error {
+	ctx, span := startSpan(ctx, "ClientOrganizationService.RevokeInvite")
+	defer span.End()
+
+	if actor.Role != ClientOrganizationRoleOwner {
+		return ErrClientRoleForbidden
+	}
+
+	normalizedID := strings.TrimSpace(inviteID)
+	if normalizedID == "" {
+		return ErrInvalidInput
+	}
+
+	return s.repo.RevokeClientOrganizationInvite(ctx, actor.OrganizationID, normalizedID)
+}
+
+// AcceptInvite creates the portal login of an invited colleague with the
+// e-mail and role of the invite.
+func (s *ClientOrganizationService) AcceptInvite(
+	ctx context.Context,
+	input AcceptClientOrganizationInviteInput,
+) (ClientPortalAccount, error) {
+	ctx, span := startSpan(ctx, "ClientOrganizationService.AcceptInvite")
+	defer span.End()
+
+	normalizedInput := AcceptClientOrganizationInviteInput{
+		Token:    strings.TrimSpace(input.Token),
+		Name:     strings.TrimSpace(input.Name),
+		Login:    strings.ToLower(strings.TrimSpace(input.Login)),
+		Password: strings.TrimSpace(input.Password),
+	}
+	if normalizedInput.Token == "" || normalizedInput.Login == "" || normalizedInput.Password == "" {
+		return ClientPortalAccount{}, ErrInvalidInput
+	}
+	if normalizedInput.Name == "" {
+		normalizedInput.Name = normalizedInput.Login
+	}
+	normalizedInput.TokenHash = hashClientOrganizationInviteToken(normalizedInput.Token)
+
+	return s.repo.AcceptClientOrganizationInvite(ctx, normalizedInput)
+}
+
+var clientOrganizationInviteTemplate = template.Must(template.New("client_invite").Parse(
+	`Olá!
+
+{{.InvitedBy}} convidou você para acessar a área do cliente da {{.OrganizationName}} na Shalosh com o perfil "{{.RoleLabel}}".
+
+Para criar seu acesso, abra o link abaixo até {{.ExpiresOn}}:
+
+{{.Link}}
+
+Se você não esperava este convite, desconsidere esta mensagem.
+
+Atenciosamente,
+Equipe Shalosh
+`))
+
+func (s *ClientOrganizationService) buildInviteMessage(
+	invite ClientOrganizationInvite,
+	organization ClientOrganization,
+	actor ClientPortalAuthUser,
+	token string,
+) (EmailMessage, error) {
+	data := struct {
+		InvitedBy        string
+		OrganizationName string
+		RoleLabel        string
+		ExpiresOn        string
+		Link             string
+	}{
+		InvitedBy:        strings.TrimSpace(actor.Name),
+		OrganizationName: strings.TrimSpace(organization.Name),
+		RoleLabel:        clientOrganizationRoleLabel(invite.Role),
+		ExpiresOn:        invite.ExpiresAt.Format("02/01/2006"),
+		Link:             s.config.PortalURL + "/convite?token=" + url.QueryEscape(token),
+	}
+
+	var body strings.Builder
+	if err := clientOrganizationInviteTemplate.Execute(&body, data); err != nil {
+		return EmailMessage{}, err
+	}
+
+	return EmailMessage{
+		To:      invite.Email,
+		Subject: fmt.Sprintf("Convite para a área do cliente - %s", data.OrganizationName),
+		Body:    body.String(),
+	}, nil
+}
+
+func clientOrganizationRoleLabel(role string) string {
+	switch role {
+	case ClientOrganizationRoleOwner:
+		return "Responsável"
+	case ClientOrganizationRoleFinance:
+		return "Financeiro"
+	default:
+		return "Visualização"
+	}
+}
+
+func isValidClientOrganizationRole(role string) bool {
+	switch role {
+	case ClientOrganizationRoleOwner, ClientOrganizationRoleFinance, ClientOrganizationRoleViewer:
+		return true
+	default:
+		return false
+	}
+}
+
+func hashClientOrganizationInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -26,12 +26,22 @@ type ClientPortalRepository interface {
 		requestID string,
 	) (ClientServiceRequest, error)
 
-	ListAdminServiceRequests(ctx context.Context) ([]AdminServiceRequest, error)
+	ReopenClientServiceRequest(
+		ctx context.Context,
+		input ReopenClientServiceRequestInput,
+	) (ClientServiceRequest, error)
+
+	ListAdminServiceRequests(ctx context.Context, filter AdminServiceRequestFilter) ([]AdminServiceRequest, error)
 	GetAdminServiceRequest(ctx context.Context, requestID string) (AdminServiceRequest, error)
+	UpdateAdminServiceRequest(ctx context.Context, input UpdateAdminServiceRequestInput) (AdminServiceRequest, error)
+	// UpdateAdminServiceRequestStatus returns
+	// ErrServiceRequestTransitionNotAllowed when the workflow has no
+	// transition from the current status to the requested one.
 	UpdateAdminServiceRequestStatus(
 		ctx context.Context,
 		input UpdateAdminServiceRequestStatusInput,
 	) (AdminServiceRequest, error)
+	ListServiceRequestStatusHistory(ctx context.Context, requestID string) ([]ServiceRequestStatusChange, error)
 	ListServiceRequestComments(ctx context.Context, requestID string) ([]ServiceRequestComment, error)
 	CreateServiceRequestComment(
 		ctx context.Context,
@@ -52,11 +62,17 @@ type ClientPortalRepository interface {
 }
 
 type ClientPortalService struct {
-	repo ClientPortalRepository
+	repo     ClientPortalRepository
+	notifier Notifier
+	sla      SLATracker
 }
 
-func NewClientPortalService(repo ClientPortalRepository) *ClientPortalService {
-	return &ClientPortalService{repo: repo}
+func NewClientPortalService(
+	repo ClientPortalRepository,
+	notifier Notifier,
+	sla SLATracker,
+) *ClientPortalService {
+	return &ClientPortalService{repo: repo, notifier: notifier, sla: sla}
 }
 
 type ClientPortalAuthUser struct {
@@ -67,17 +83,24 @@ type ClientPortalAuthUser struct {
 	Password string `json:"-"`
 	Avatar   string `json:"avatar"`
 	Active   bool   `json:"active"`
+	// OrganizationID and Role identify the client organization the portal
+	// user belongs to and what they can do in it.
+	OrganizationID string `json:"organizationId"`
+	Role           string `json:"role"`
 }
 
 type ClientPortalAccount struct {
-	ID      string    `json:"id"`
-	Name    string    `json:"name"`
-	Email   string    `json:"email"`
-	Login   string    `json:"login"`
-	Avatar  string    `json:"avatar"`
-	Active  bool      `json:"active"`
-	Created time.Time `json:"created"`
-	Updated time.Time `json:"updated"`
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Email            string    `json:"email"`
+	Login            string    `json:"login"`
+	Avatar           string    `json:"avatar"`
+	Active           bool      `json:"active"`
+	OrganizationID   string    `json:"organizationId"`
+	OrganizationName string    `json:"organizationName"`
+	Role             string    `json:"role"`
+	Created          time.Time `json:"created"`
+	Updated          time.Time `json:"updated"`
 }
 
 type CreateClientPortalAccountInput struct {
@@ -120,34 +143,49 @@ type ClientServiceRequestFile struct {
 }
 
 type ClientServiceRequest struct {
-	ID          string                     `json:"id"`
-	ClientID    string                     `json:"clientId"`
-	ProjectID   string                     `json:"projectId,omitempty"`
-	ProjectName string                     `json:"projectName,omitempty"`
-	Title       string                     `json:"title"`
-	Description string                     `json:"description"`
-	Status      string                     `json:"status"`
-	Files       []ClientServiceRequestFile `json:"files"`
-	Created     time.Time                  `json:"created"`
-	Updated     time.Time                  `json:"updated"`
+	ID          string `json:"id"`
+	ClientID    string `json:"clientId"`
+	ProjectID   string `json:"projectId,omitempty"`
+	ProjectName string `json:"projectName,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Status      string `json:"status"`
+	StatusName  string `json:"statusName"`
+	// ReopenableUntil is set while the client can still reopen the request.
+	ReopenableUntil *time.Time                 `json:"reopenableUntil,omitempty"`
+	Files           []ClientServiceRequestFile `json:"files"`
+	Created         time.Time                  `json:"created"`
+	Updated         time.Time                  `json:"updated"`
 }
 
 type AdminServiceRequest struct {
-	ID           string                     `json:"id"`
-	ClientID     string                     `json:"clientId"`
-	ClientName   string                     `json:"clientName"`
-	ClientEmail  string                     `json:"clientEmail"`
-	ClientLogin  string                     `json:"clientLogin"`
-	ProjectID    string                     `json:"projectId,omitempty"`
-	ProjectName  string                     `json:"projectName,omitempty"`
-	Title        string                     `json:"title"`
-	Description  string                     `json:"description"`
-	Status       string                     `json:"status"`
-	Files        []ClientServiceRequestFile `json:"files"`
-	Comments     int                        `json:"comments"`
-	OpenComments int                        `json:"openComments"`
-	Created      time.Time                  `json:"created"`
-	Updated      time.Time                  `json:"updated"`
+	ID                string                     `json:"id"`
+	ClientID          string                     `json:"clientId"`
+	ClientName        string                     `json:"clientName"`
+	ClientEmail       string                     `json:"clientEmail"`
+	ClientLogin       string                     `json:"clientLogin"`
+	ProjectID         string                     `json:"projectId,omitempty"`
+	ProjectName       string                     `json:"projectName,omitempty"`
+	Title             string                     `json:"title"`
+	Description       string                     `json:"description"`
+	Status            string                     `json:"status"`
+	StatusName        string                     `json:"statusName"`
+	StatusClosed      bool                       `json:"statusClosed"`
+	Priority          string                     `json:"priority"`
+	CategoryID        string                     `json:"categoryId,omitempty"`
+	CategoryName      string                     `json:"categoryName,omitempty"`
+	AssignedUserID    string                     `json:"assignedUserId,omitempty"`
+	AssignedUserName  string                     `json:"assignedUserName,omitempty"`
+	ProjectTaskID     string                     `json:"projectTaskId,omitempty"`
+	ProjectTaskName   string                     `json:"projectTaskName,omitempty"`
+	ProjectTaskStatus string                     `json:"projectTaskStatus,omitempty"`
+	Files             []ClientServiceRequestFile `json:"files"`
+	Comments          int                        `json:"comments"`
+	OpenComments      int                        `json:"openComments"`
+	SLA               *ServiceRequestSLA         `json:"sla,omitempty"`
+	SLATracking       *ServiceRequestSLATracking `json:"-"`
+	Created           time.Time                  `json:"created"`
+	Updated           time.Time                  `json:"updated"`
 }
 
 type ServiceRequestCommentFile struct {
@@ -191,8 +229,49 @@ type CreateClientServiceRequestInput struct {
 }
 
 type UpdateAdminServiceRequestStatusInput struct {
+	RequestID       string
+	Status          string
+	Note            string
+	ChangedByUserID string
+}
+
+// AdminServiceRequestFilter narrows the admin list. AssignedUserID accepts
+// ServiceRequestUnassigned; Sort accepts "" or ServiceRequestSortSLA.
+type AdminServiceRequestFilter struct {
+	Sort           string
+	Status         string
+	Priority       string
+	CategoryID     string
+	AssignedUserID string
+}
+
+// UpdateAdminServiceRequestInput changes only the fields that are set; an
+// empty AssignedUserID or CategoryID clears them.
+type UpdateAdminServiceRequestInput struct {
+	RequestID      string
+	AssignedUserID *string
+	Priority       *string
+	CategoryID     *string
+}
+
+type ReopenClientServiceRequestInput struct {
+	ClientID  string
 	RequestID string
-	Status    string
+	Comment   string
+}
+
+type ServiceRequestStatusChange struct {
+	ID                  string    `json:"id"`
+	FromStatus          string    `json:"fromStatus,omitempty"`
+	FromStatusName      string    `json:"fromStatusName,omitempty"`
+	ToStatus            string    `json:"toStatus"`
+	ToStatusName        string    `json:"toStatusName"`
+	ChangedByUserID     string    `json:"changedByUserId,omitempty"`
+	ChangedByUserName   string    `json:"changedByUserName,omitempty"`
+	ChangedByClientID   string    `json:"changedByClientId,omitempty"`
+	ChangedByClientName string    `json:"changedByClientName,omitempty"`
+	Note                string    `json:"note"`
+	Created             time.Time `json:"created"`
 }
 
 type CreateServiceRequestCommentInput struct {
@@ -233,6 +312,9 @@ func (s *ClientPortalService) Authenticate(
 	login string,
 	password string,
 ) (ClientPortalAuthUser, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.Authenticate")
+	defer span.End()
+
 	normalizedLogin := strings.TrimSpace(login)
 	normalizedPassword := strings.TrimSpace(password)
 	if normalizedLogin == "" || normalizedPassword == "" {
@@ -260,6 +342,9 @@ func (s *ClientPortalService) ValidateSession(
 	clientID string,
 	login string,
 ) (ClientPortalAuthUser, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.ValidateSession")
+	defer span.End()
+
 	normalizedID := strings.TrimSpace(clientID)
 	normalizedLogin := strings.TrimSpace(login)
 	if normalizedID == "" || normalizedLogin == "" {
@@ -286,6 +371,9 @@ func (s *ClientPortalService) Register(
 	ctx context.Context,
 	input CreateClientPortalAccountInput,
 ) (ClientPortalAccount, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.Register")
+	defer span.End()
+
 	normalizedLogin := strings.ToLower(strings.TrimSpace(input.Login))
 	normalizedPassword := strings.TrimSpace(input.Password)
 	normalizedName := strings.TrimSpace(input.Name)
@@ -315,6 +403,9 @@ func (s *ClientPortalService) GetAccount(
 	ctx context.Context,
 	clientID string,
 ) (ClientPortalAccount, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.GetAccount")
+	defer span.End()
+
 	normalizedID := strings.TrimSpace(clientID)
 	if normalizedID == "" {
 		return ClientPortalAccount{}, ErrInvalidInput
@@ -327,6 +418,9 @@ func (s *ClientPortalService) UpdateAccount(
 	ctx context.Context,
 	input UpdateClientPortalAccountInput,
 ) (ClientPortalAccount, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.UpdateAccount")
+	defer span.End()
+
 	normalizedInput := UpdateClientPortalAccountInput{
 		ClientID: strings.TrimSpace(input.ClientID),
 		Name:     strings.TrimSpace(input.Name),
@@ -350,6 +444,9 @@ func (s *ClientPortalService) ListProjects(
 	ctx context.Context,
 	clientID string,
 ) ([]ClientPortalProject, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.ListProjects")
+	defer span.End()
+
 	normalizedID := strings.TrimSpace(clientID)
 	if normalizedID == "" {
 		return nil, ErrInvalidInput
@@ -363,6 +460,9 @@ func (s *ClientPortalService) HasProjectAccess(
 	clientID string,
 	projectID string,
 ) (bool, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.HasProjectAccess")
+	defer span.End()
+
 	normalizedClientID := strings.TrimSpace(clientID)
 	normalizedProjectID := strings.TrimSpace(projectID)
 	if normalizedClientID == "" || normalizedProjectID == "" {
@@ -376,6 +476,9 @@ func (s *ClientPortalService) CountOpenRequests(
 	ctx context.Context,
 	clientID string,
 ) (int, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.CountOpenRequests")
+	defer span.End()
+
 	normalizedID := strings.TrimSpace(clientID)
 	if normalizedID == "" {
 		return 0, ErrInvalidInput
@@ -388,6 +491,9 @@ func (s *ClientPortalService) ListServiceRequests(
 	ctx context.Context,
 	clientID string,
 ) ([]ClientServiceRequest, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.ListServiceRequests")
+	defer span.End()
+
 	normalizedID := strings.TrimSpace(clientID)
 	if normalizedID == "" {
 		return nil, ErrInvalidInput
@@ -400,6 +506,9 @@ func (s *ClientPortalService) CreateServiceRequest(
 	ctx context.Context,
 	input CreateClientServiceRequestInput,
 ) (ClientServiceRequest, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.CreateServiceRequest")
+	defer span.End()
+
 	normalizedInput := CreateClientServiceRequestInput{
 		ClientID:    strings.TrimSpace(input.ClientID),
 		ProjectID:   strings.TrimSpace(input.ProjectID),
@@ -442,7 +551,29 @@ func (s *ClientPortalService) CreateServiceRequest(
 		}
 	}
 
-	return s.repo.CreateClientServiceRequest(ctx, normalizedInput)
+	request, err := s.repo.CreateClientServiceRequest(ctx, normalizedInput)
+	if err != nil {
+		return ClientServiceRequest{}, err
+	}
+
+	if s.sla != nil {
+		s.sla.TrackServiceRequest(ctx, request.ID)
+	}
+
+	s.notify(ctx, NotificationEvent{
+		Type:             NotificationTypeServiceRequestCreated,
+		Title:            "Nova solicitação: " + request.Title,
+		Message:          request.Description,
+		ProjectID:        request.ProjectID,
+		ServiceRequestID: request.ID,
+		ActorClientID:    request.ClientID,
+		Audience: NotificationAudience{
+			ProjectManagersOf: request.ProjectID,
+			AllUsersWhenEmpty: true,
+		},
+	})
+
+	return request, nil
 }
 
 func (s *ClientPortalService) CancelServiceRequest(
@@ -450,6 +581,9 @@ func (s *ClientPortalService) CancelServiceRequest(
 	clientID string,
 	requestID string,
 ) (ClientServiceRequest, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.CancelServiceRequest")
+	defer span.End()
+
 	normalizedClientID := strings.TrimSpace(clientID)
 	normalizedRequestID := strings.TrimSpace(requestID)
 	if normalizedClientID == "" || normalizedRequestID == "" {
@@ -459,48 +593,226 @@ func (s *ClientPortalService) CancelServiceRequest(
 	return s.repo.CancelClientServiceRequest(ctx, normalizedClientID, normalizedRequestID)
 }
 
+// ReopenServiceRequest moves a closed request back to "reaberta" when its
+// status allows reopening and the reopen window has not passed. The optional
+// comment explains why and is added to the request.
+func (s *ClientPortalService) ReopenServiceRequest(
+	ctx context.Context,
+	input ReopenClientServiceRequestInput,
+) (ClientServiceRequest, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.ReopenServiceRequest")
+	defer span.End()
+
+	normalizedInput := ReopenClientServiceRequestInput{
+		ClientID:  strings.TrimSpace(input.ClientID),
+		RequestID: strings.TrimSpace(input.RequestID),
+		Comment:   strings.TrimSpace(input.Comment),
+	}
+	if normalizedInput.ClientID == "" || normalizedInput.RequestID == "" {
+		return ClientServiceRequest{}, ErrInvalidInput
+	}
+
+	request, err := s.repo.ReopenClientServiceRequest(ctx, normalizedInput)
+	if err != nil {
+		return ClientServiceRequest{}, err
+	}
+
+	message := normalizedInput.Comment
+	if message == "" {
+		message = "O cliente reabriu a solicitação."
+	}
+	s.notify(ctx, NotificationEvent{
+		Type:             NotificationTypeServiceRequestStatus,
+		Title:            "Solicitação reaberta: " + request.Title,
+		Message:          message,
+		ProjectID:        request.ProjectID,
+		ServiceRequestID: request.ID,
+		ActorClientID:    request.ClientID,
+		Audience: NotificationAudience{
+			ProjectManagersOf: request.ProjectID,
+			UserIDs:           s.assigneeOf(ctx, request.ID),
+			AllUsersWhenEmpty: true,
+		},
+	})
+
+	return request, nil
+}
+
+// ListAdminServiceRequests keeps the repository order unless the filter sorts
+// by "sla", which puts the requests closest to missing a deadline first.
 func (s *ClientPortalService) ListAdminServiceRequests(
 	ctx context.Context,
+	filter AdminServiceRequestFilter,
 ) ([]AdminServiceRequest, error) {
-	return s.repo.ListAdminServiceRequests(ctx)
+	ctx, span := startSpan(ctx, "ClientPortalService.ListAdminServiceRequests")
+	defer span.End()
+
+	normalizedFilter := AdminServiceRequestFilter{
+		Sort:           strings.ToLower(strings.TrimSpace(filter.Sort)),
+		Status:         strings.ToLower(strings.TrimSpace(filter.Status)),
+		Priority:       strings.ToLower(strings.TrimSpace(filter.Priority)),
+		CategoryID:     strings.TrimSpace(filter.CategoryID),
+		AssignedUserID: strings.TrimSpace(filter.AssignedUserID),
+	}
+	if normalizedFilter.Sort != "" && normalizedFilter.Sort != ServiceRequestSortSLA {
+		return nil, ErrInvalidInput
+	}
+	if normalizedFilter.Priority != "" && normalizeServiceRequestPriority(normalizedFilter.Priority) == "" {
+		return nil, ErrInvalidInput
+	}
+
+	requests, err := s.repo.ListAdminServiceRequests(ctx, normalizedFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	for index := range requests {
+		requests[index] = s.withSLA(requests[index])
+	}
+	if normalizedFilter.Sort == ServiceRequestSortSLA {
+		sortServiceRequestsBySLA(requests)
+	}
+
+	return requests, nil
 }
 
 func (s *ClientPortalService) GetAdminServiceRequest(
 	ctx context.Context,
 	requestID string,
 ) (AdminServiceRequest, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.GetAdminServiceRequest")
+	defer span.End()
+
 	normalizedRequestID := strings.TrimSpace(requestID)
 	if normalizedRequestID == "" {
 		return AdminServiceRequest{}, ErrInvalidInput
 	}
 
-	return s.repo.GetAdminServiceRequest(ctx, normalizedRequestID)
+	request, err := s.repo.GetAdminServiceRequest(ctx, normalizedRequestID)
+	if err != nil {
+		return AdminServiceRequest{}, err
+	}
+
+	return s.withSLA(request), nil
 }
 
 func (s *ClientPortalService) UpdateAdminServiceRequestStatus(
 	ctx context.Context,
 	input UpdateAdminServiceRequestStatusInput,
 ) (AdminServiceRequest, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.UpdateAdminServiceRequestStatus")
+	defer span.End()
+
 	normalizedInput := UpdateAdminServiceRequestStatusInput{
-		RequestID: strings.TrimSpace(input.RequestID),
-		Status:    normalizeServiceRequestStatus(strings.TrimSpace(input.Status)),
+		RequestID:       strings.TrimSpace(input.RequestID),
+		Status:          normalizeServiceRequestStatus(input.Status),
+		Note:            strings.TrimSpace(input.Note),
+		ChangedByUserID: strings.TrimSpace(input.ChangedByUserID),
 	}
 
 	if normalizedInput.RequestID == "" || normalizedInput.Status == "" {
 		return AdminServiceRequest{}, ErrInvalidInput
 	}
 
-	if normalizedInput.Status != "concluida" && normalizedInput.Status != "cancelada" {
+	request, err := s.repo.UpdateAdminServiceRequestStatus(ctx, normalizedInput)
+	if err != nil {
+		return AdminServiceRequest{}, err
+	}
+
+	s.notify(ctx, NotificationEvent{
+		Type:             NotificationTypeServiceRequestStatus,
+		Title:            "Solicitação " + formatServiceRequestStatusLabel(request.Status, request.StatusName) + ": " + request.Title,
+		Message:          normalizedInput.Note,
+		ProjectID:        request.ProjectID,
+		ServiceRequestID: request.ID,
+		Audience: NotificationAudience{
+			ClientIDs: []string{request.ClientID},
+		},
+	})
+
+	return s.withSLA(request), nil
+}
+
+// UpdateAdminServiceRequest changes the assignee, priority and category of a
+// request. Nil fields are kept; an empty assignee or category clears it. The
+// new assignee is notified unless they assigned the request to themselves.
+func (s *ClientPortalService) UpdateAdminServiceRequest(
+	ctx context.Context,
+	input UpdateAdminServiceRequestInput,
+	actorUserID string,
+) (AdminServiceRequest, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.UpdateAdminServiceRequest")
+	defer span.End()
+
+	normalizedInput := UpdateAdminServiceRequestInput{
+		RequestID:      strings.TrimSpace(input.RequestID),
+		AssignedUserID: trimOptionalString(input.AssignedUserID),
+		Priority:       trimOptionalString(input.Priority),
+		CategoryID:     trimOptionalString(input.CategoryID),
+	}
+	if normalizedInput.RequestID == "" {
 		return AdminServiceRequest{}, ErrInvalidInput
 	}
+	if normalizedInput.Priority != nil {
+		priority := normalizeServiceRequestPriority(*normalizedInput.Priority)
+		if priority == "" {
+			return AdminServiceRequest{}, ErrInvalidInput
+		}
+		normalizedInput.Priority = &priority
+	}
 
-	return s.repo.UpdateAdminServiceRequestStatus(ctx, normalizedInput)
+	current, err := s.repo.GetAdminServiceRequest(ctx, normalizedInput.RequestID)
+	if err != nil {
+		return AdminServiceRequest{}, err
+	}
+
+	request, err := s.repo.UpdateAdminServiceRequest(ctx, normalizedInput)
+	if err != nil {
+		return AdminServiceRequest{}, err
+	}
+
+	actor := strings.TrimSpace(actorUserID)
+	if request.AssignedUserID != "" &&
+		request.AssignedUserID != current.AssignedUserID &&
+		request.AssignedUserID != actor {
+		s.notify(ctx, NotificationEvent{
+			Type:             NotificationTypeServiceRequestAssigned,
+			Title:            "Solicitação atribuída a você: " + request.Title,
+			Message:          request.ClientName + " · " + request.ProjectName,
+			ProjectID:        request.ProjectID,
+			ServiceRequestID: request.ID,
+			ActorUserID:      actor,
+			Audience: NotificationAudience{
+				UserIDs: []string{request.AssignedUserID},
+			},
+		})
+	}
+
+	return s.withSLA(request), nil
+}
+
+func (s *ClientPortalService) ListServiceRequestStatusHistory(
+	ctx context.Context,
+	requestID string,
+) ([]ServiceRequestStatusChange, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.ListServiceRequestStatusHistory")
+	defer span.End()
+
+	normalizedRequestID := strings.TrimSpace(requestID)
+	if normalizedRequestID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	return s.repo.ListServiceRequestStatusHistory(ctx, normalizedRequestID)
 }
 
 func (s *ClientPortalService) ListServiceRequestComments(
 	ctx context.Context,
 	requestID string,
 ) ([]ServiceRequestComment, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.ListServiceRequestComments")
+	defer span.End()
+
 	normalizedRequestID := strings.TrimSpace(requestID)
 	if normalizedRequestID == "" {
 		return nil, ErrInvalidInput
@@ -513,6 +825,9 @@ func (s *ClientPortalService) CreateServiceRequestComment(
 	ctx context.Context,
 	input CreateServiceRequestCommentInput,
 ) (ServiceRequestComment, error) {
+	ctx, span := startSpan(ctx, "ClientPortalService.CreateServiceRequestComment")
+	defer span.End()
+
 	normalizedInput := CreateServiceRequestCommentInput{
 		ServiceRequestID: strings.TrimSpace(input.ServiceRequestID),
 		ParentCommentID:  strings.TrimSpace(input.ParentCommentID),
@@ -543,13 +858,77 @@ func (s *ClientPortalService) CreateServiceRequestComment(
 		normalizedInput.Files = append(normalizedInput.Files, normalizedFile)
 	}
 
-	return s.repo.CreateServiceRequestComment(ctx, normalizedInput)
+	comment, err := s.repo.CreateServiceRequestComment(ctx, normalizedInput)
+	if err != nil {
+		return ServiceRequestComment{}, err
+	}
+
+	s.notifyServiceRequestComment(ctx, comment)
+
+	return comment, nil
+}
+
+// notifyServiceRequestComment tells the requesting client and the linked
+// project's managers (or every user, for requests without a project) about
+// a new comment; the author is never notified.
+func (s *ClientPortalService) notifyServiceRequestComment(
+	ctx context.Context,
+	comment ServiceRequestComment,
+) {
+	if s.notifier == nil {
+		return
+	}
+
+	request, err := s.repo.GetAdminServiceRequest(ctx, comment.ServiceRequestID)
+	if err != nil {
+		return
+	}
+
+	s.notify(ctx, NotificationEvent{
+		Type:             NotificationTypeServiceRequestComment,
+		Title:            comment.AuthorName + " comentou na solicitação " + request.Title,
+		Message:          comment.Comment,
+		ProjectID:        request.ProjectID,
+		ServiceRequestID: request.ID,
+		ActorUserID:      comment.UserID,
+		ActorClientID:    comment.ClientID,
+		Audience: NotificationAudience{
+			ProjectManagersOf: request.ProjectID,
+			ClientIDs:         []string{request.ClientID},
+			AllUsersWhenEmpty: true,
+		},
+	})
+}
+
+func (s *ClientPortalService) notify(ctx context.Context, event NotificationEvent) {
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, event)
+	}
+}
+
+// assigneeOf returns the assignee of a request as a notification audience.
+func (s *ClientPortalService) assigneeOf(ctx context.Context, requestID string) []string {
+	request, err := s.repo.GetAdminServiceRequest(ctx, requestID)
+	if err != nil || request.AssignedUserID == "" {
+		return nil
+	}
+	return []string{request.AssignedUserID}
+}
+
+func (s *ClientPortalService) withSLA(request AdminServiceRequest) AdminServiceRequest {
+	if s.sla != nil {
+		request.SLA = s.sla.DescribeServiceRequestSLA(request.SLATracking)
+	}
+	return request
 }
 
 func (s *ClientPortalService) DeleteServiceRequestCommentFile(
 	ctx context.Context,
 	input DeleteServiceRequestCommentFileInput,
 ) error {
+	ctx, span := startSpan(ctx, "ClientPortalService.DeleteServiceRequestCommentFile")
+	defer span.End()
+
 	normalizedInput := DeleteServiceRequestCommentFileInput{
 		ServiceRequestID: strings.TrimSpace(input.ServiceRequestID),
 		CommentID:        strings.TrimSpace(input.CommentID),
@@ -573,6 +952,9 @@ func (s *ClientPortalService) DeleteServiceRequestComment(
 	ctx context.Context,
 	input DeleteServiceRequestCommentInput,
 ) error {
+	ctx, span := startSpan(ctx, "ClientPortalService.DeleteServiceRequestComment")
+	defer span.End()
+
 	normalizedInput := DeleteServiceRequestCommentInput{
 		ServiceRequestID: strings.TrimSpace(input.ServiceRequestID),
 		CommentID:        strings.TrimSpace(input.CommentID),
@@ -594,6 +976,9 @@ func (s *ClientPortalService) UpdateServiceRequestComment(
 	ctx context.Context,
 	input UpdateServiceRequestCommentInput,
 ) error {
+	ctx, span := startSpan(ctx, "ClientPortalService.UpdateServiceRequestComment")
+	defer span.End()
+
 	normalizedInput := UpdateServiceRequestCommentInput{
 		ServiceRequestID: strings.TrimSpace(input.ServiceRequestID),
 		CommentID:        strings.TrimSpace(input.CommentID),
@@ -626,12 +1011,12 @@ func (s *ClientPortalService) UpdateServiceRequestComment(
 	return s.repo.UpdateServiceRequestComment(ctx, normalizedInput)
 }
 
+// normalizeServiceRequestStatus only checks the code format; whether the
+// status exists and the transition is allowed is checked by the repository.
 func normalizeServiceRequestStatus(value string) string {
 	normalized := strings.ToLower(strings.TrimSpace(value))
-	switch normalized {
-	case "aberta", "em_andamento", "concluida", "cancelada":
-		return normalized
-	default:
+	if !serviceRequestStatusCodePattern.MatchString(normalized) {
 		return ""
 	}
+	return normalized
 }
@@ -12,9 +12,20 @@ var (
 	ErrLoginInUse         = errors.New("login already in use")
 	ErrEmailInUse         = errors.New("email already in use")
 
+	ErrUserRequiredFields   = errors.New("name, email and login are required")
+	ErrUserPasswordRequired = errors.New("password is required")
+	ErrUserSelfDeactivation = errors.New("users cannot deactivate themselves")
+
 	ErrClientLoginInUse = errors.New("client login already in use")
 	ErrClientEmailInUse = errors.New("client email already in use")
 
+	ErrClientTaxIDInvalid = errors.New("client tax id must be a valid CPF or CNPJ")
+	ErrClientTaxIDInUse   = errors.New("client tax id already in use")
+
+	ErrClientRoleForbidden             = errors.New("client role does not allow this action")
+	ErrClientOrganizationOwnerRequired = errors.New("organization must keep an active owner")
+	ErrClientOrganizationTaxIDInUse    = errors.New("organization tax id already in use")
+
 	ErrPermissionCodeInUse = errors.New("permission code already in use")
 	ErrPermissionNameInUse = errors.New("permission name already in use")
 	ErrProfileNameInUse    = errors.New("profile name already in use")
@@ -29,6 +40,53 @@ var (
 	ErrProjectClientsNotFound  = errors.New("one or more project clients do not exist")
 	ErrProjectManagersNotFound = errors.New("one or more project managers do not exist")
 
+	ErrProjectExpenseCategoryCodeInUse = errors.New("project expense category code already in use")
+	ErrProjectExpenseCategoryNameInUse = errors.New("project expense category name already in use")
+	ErrProjectExpenseCategoryNotFound  = errors.New("project expense category not found")
+	ErrUserHourlyRateInUse             = errors.New("user hourly rate already defined for this date")
+	ErrUserNotFound                    = errors.New("user not found")
+
+	ErrInvoiceSeriesNotFound    = errors.New("invoice series not found")
+	ErrInvoiceClientRequired    = errors.New("invoice client must be linked to the project")
+	ErrInvoiceSourceNotBillable = errors.New("invoice source is cancelled")
+	ErrInvoiceAmountMismatch    = errors.New("invoice items do not match the billed amount")
+	ErrInvoiceAlreadyIssued     = errors.New("invoice already issued for this source")
+	ErrInvoiceNotCancellable    = errors.New("invoice cannot be cancelled")
+	ErrInvoiceImmutable         = errors.New("invoices are immutable once issued")
+	ErrProjectHasInvoices       = errors.New("project data is referenced by issued invoices")
+
+	ErrPaymentChargeNotOpen          = errors.New("only pending monthly charges accept payments")
+	ErrPaymentProviderUnavailable    = errors.New("payment provider unavailable")
+	ErrPaymentWebhookInvalid         = errors.New("payment webhook signature or payload is invalid")
+	ErrPaymentSimulationNotSupported = errors.New("payment provider does not support simulated payments")
+
+	ErrBankStatementInvalid          = errors.New("bank statement could not be parsed")
+	ErrBankStatementLineResolved     = errors.New("bank statement line already resolved")
+	ErrBankStatementSourceNotPending = errors.New("matched revenue or charge is not pending")
+
+	ErrInboundMailDisabled = errors.New("inbound mail is not configured")
+	ErrInboundMailInvalid  = errors.New("inbound message could not be parsed")
+
+	ErrSLAPolicyScopeInUse = errors.New("an active SLA policy already exists for this client and project type")
+
+	ErrServiceRequestTransitionNotAllowed = errors.New("service request status transition not allowed")
+	ErrServiceRequestReopenExpired        = errors.New("service request can no longer be reopened")
+	ErrServiceRequestStatusNotFound       = errors.New("service request status not found")
+	ErrServiceRequestStatusCodeInUse      = errors.New("service request status code already in use")
+	ErrServiceRequestStatusNameInUse      = errors.New("service request status name already in use")
+	ErrServiceRequestStatusInUse          = errors.New("service request status is used by service requests")
+	ErrServiceRequestStatusProtected      = errors.New("system service request statuses cannot be removed, deactivated or change their closed flag")
+	ErrServiceRequestCategoryNotFound     = errors.New("service request category not found")
+	ErrServiceRequestCategoryNameInUse    = errors.New("service request category name already in use")
+	ErrServiceRequestAlreadyConverted     = errors.New("service request already has a project task")
+	ErrServiceRequestWithoutProject       = errors.New("service request is not linked to a project")
+
 	ErrZipCodeUnavailable = errors.New("zipcode service unavailable")
 	ErrZipCodeNotFound    = errors.New("zipcode not found")
+
+	ErrCompanyLookupUnavailable = errors.New("company lookup service unavailable")
+	ErrCompanyNotFound          = errors.New("company not found")
+
+	ErrGeocoderUnavailable = errors.New("geocoding service unavailable")
+	ErrGeocodeNotFound     = errors.New("address location not found")
 )
@@ -0,0 +1,399 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	InboundMailStatusRequestCreated = "solicitacao_criada"
+	InboundMailStatusCommentCreated = "comentario_criado"
+	InboundMailStatusIgnored        = "ignorado"
+	InboundMailStatusRejected       = "rejeitado"
+	// InboundMailStatusDuplicate is only returned to the caller: the message
+	// id was already processed, e.g. because the MTA retried the delivery.
+	InboundMailStatusDuplicate = "duplicado"
+
+	inboundMailReplyTokenPrefix    = "sr"
+	inboundMailReplySignatureBytes = 8
+	maxInboundMailSubjectLength    = 200
+)
+
+// AttachmentStorage keeps uploaded files in object storage; the key is what
+// attachment rows store.
+type AttachmentStorage interface {
+	PutObject(ctx context.Context, key string, contentType string, content []byte) error
+}
+
+type InboundMailRepository interface {
+	FindClientByEmail(ctx context.Context, email string) (InboundMailClient, error)
+	// ReserveInboundEmail stores the message id and returns false when it
+	// was already received.
+	ReserveInboundEmail(ctx context.Context, input ReserveInboundEmailInput) (string, bool, error)
+	CompleteInboundEmail(ctx context.Context, input CompleteInboundEmailInput) error
+	ReleaseInboundEmail(ctx context.Context, inboundEmailID string) error
+}
+
+// InboundMailConfig holds the address clients reply to and the secret the
+// MTA sends with every message.
+type InboundMailConfig struct {
+	Address string
+	Secret  string
+}
+
+type InboundMailClient struct {
+	ID    string
+	Name  string
+	Email string
+}
+
+type ReserveInboundEmailInput struct {
+	MessageID   string
+	FromAddress string
+	Subject     string
+}
+
+type CompleteInboundEmailInput struct {
+	ID               string
+	Status           string
+	ClientID         string
+	ServiceRequestID string
+	CommentID        string
+	Error            string
+}
+
+type InboundMailResult struct {
+	Status           string `json:"status"`
+	ServiceRequestID string `json:"serviceRequestId,omitempty"`
+	CommentID        string `json:"commentId,omitempty"`
+	Reason           string `json:"reason,omitempty"`
+}
+
+type InboundMailService struct {
+	repo     InboundMailRepository
+	requests *ClientPortalService
+	mailer   Mailer
+	storage  AttachmentStorage
+	config   InboundMailConfig
+}
+
+func NewInboundMailService(
+	repo InboundMailRepository,
+	requests *ClientPortalService,
+	mailer Mailer,
+	storage AttachmentStorage,
+	config InboundMailConfig,
+) *InboundMailService {
+	return &InboundMailService{
+		repo:     repo,
+		requests: requests,
+		mailer:   mailer,
+		storage:  storage,
+		config: InboundMailConfig{
+			Address: strings.ToLower(strings.TrimSpace(config.Address)),
+			Secret:  strings.TrimSpace(config.Secret),
+		},
+	}
+}
+
+// IngestMessage turns a raw RFC 822 message into a service request or, when
+// it was sent to a tokenized reply address, into a comment on the request
+// the token points to. Senders are matched to clients by e-mail address.
+// Rejected and ignored messages are logged and reported in the result; only
+// unexpected failures return an error, so the MTA retries them.
+func (s *InboundMailService) IngestMessage(
+	ctx context.Context,
+	raw []byte,
+	secret string,
+) (InboundMailResult, error) {
+	ctx, span := startSpan(ctx, "InboundMailService.IngestMessage")
+	defer span.End()
+
+	if s.config.Secret == "" {
+		return InboundMailResult{}, ErrInboundMailDisabled
+	}
+	if !hmac.Equal([]byte(strings.TrimSpace(secret)), []byte(s.config.Secret)) {
+		return InboundMailResult{}, ErrUnauthorized
+	}
+
+	message, err := parseInboundMail(raw)
+	if err != nil {
+		return InboundMailResult{}, err
+	}
+
+	inboundEmailID, reserved, err := s.repo.ReserveInboundEmail(ctx, ReserveInboundEmailInput{
+		MessageID:   message.MessageID,
+		FromAddress: message.From,
+		Subject:     truncateInboundMailText(message.Subject, maxInboundMailSubjectLength),
+	})
+	if err != nil {
+		return InboundMailResult{}, err
+	}
+	if !reserved {
+		return InboundMailResult{Status: InboundMailStatusDuplicate}, nil
+	}
+
+	result, clientID, err := s.processMessage(ctx, inboundEmailID, message)
+	if err != nil {
+		if releaseErr := s.repo.ReleaseInboundEmail(ctx, inboundEmailID); releaseErr != nil {
+			return InboundMailResult{}, releaseErr
+		}
+		return InboundMailResult{}, err
+	}
+
+	if err := s.repo.CompleteInboundEmail(ctx, CompleteInboundEmailInput{
+		ID:               inboundEmailID,
+		Status:           result.Status,
+		ClientID:         clientID,
+		ServiceRequestID: result.ServiceRequestID,
+		CommentID:        result.CommentID,
+		Error:            result.Reason,
+	}); err != nil {
+		return InboundMailResult{}, err
+	}
+
+	return result, nil
+}
+
+func (s *InboundMailService) processMessage(
+	ctx context.Context,
+	inboundEmailID string,
+	message parsedInboundMail,
+) (InboundMailResult, string, error) {
+	if message.AutoGenerated {
+		return inboundMailOutcome(InboundMailStatusIgnored, "automatic reply"), "", nil
+	}
+
+	client, err := s.repo.FindClientByEmail(ctx, message.From)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return inboundMailOutcome(InboundMailStatusRejected, "sender is not an active client"), "", nil
+		}
+		return InboundMailResult{}, "", err
+	}
+
+	requestID, hasToken, validToken := s.findReplyToken(message.Recipients)
+	if hasToken && !validToken {
+		return inboundMailOutcome(InboundMailStatusRejected, "invalid reply address"), client.ID, nil
+	}
+	if hasToken {
+		result, err := s.addReply(ctx, inboundEmailID, client, requestID, message)
+		return result, client.ID, err
+	}
+
+	result, err := s.createRequest(ctx, inboundEmailID, client, message)
+	return result, client.ID, err
+}
+
+func (s *InboundMailService) createRequest(
+	ctx context.Context,
+	inboundEmailID string,
+	client InboundMailClient,
+	message parsedInboundMail,
+) (InboundMailResult, error) {
+	description := message.Text
+	if description == "" && len(message.Attachments) == 0 {
+		return inboundMailOutcome(InboundMailStatusIgnored, "empty message"), nil
+	}
+	if description == "" {
+		description = "Solicitação enviada por e-mail com anexos."
+	}
+
+	files, err := s.uploadAttachments(ctx, inboundEmailID, message.Attachments)
+	if err != nil {
+		return InboundMailResult{}, err
+	}
+
+	request, err := s.requests.CreateServiceRequest(ctx, CreateClientServiceRequestInput{
+		ClientID:    client.ID,
+		Title:       truncateInboundMailText(cleanInboundMailSubject(message.Subject), maxInboundMailSubjectLength),
+		Description: description,
+		Files:       files,
+	})
+	if err != nil {
+		return InboundMailResult{}, err
+	}
+
+	s.sendAcknowledgement(ctx, client, request)
+
+	return InboundMailResult{
+		Status:           InboundMailStatusRequestCreated,
+		ServiceRequestID: request.ID,
+	}, nil
+}
+
+func (s *InboundMailService) addReply(
+	ctx context.Context,
+	inboundEmailID string,
+	client InboundMailClient,
+	requestID string,
+	message parsedInboundMail,
+) (InboundMailResult, error) {
+	request, err := s.requests.GetAdminServiceRequest(ctx, requestID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return inboundMailOutcome(InboundMailStatusRejected, "service request not found"), nil
+		}
+		return InboundMailResult{}, err
+	}
+	// A forwarded reply address must not let someone else comment.
+	if request.ClientID != client.ID {
+		return inboundMailOutcome(InboundMailStatusRejected, "sender does not own the service request"), nil
+	}
+
+	comment := stripQuotedReply(message.Text)
+	if comment == "" && len(message.Attachments) == 0 {
+		return inboundMailOutcome(InboundMailStatusIgnored, "empty reply"), nil
+	}
+	if comment == "" {
+		comment = "Anexos enviados por e-mail."
+	}
+
+	files, err := s.uploadAttachments(ctx, inboundEmailID, message.Attachments)
+	if err != nil {
+		return InboundMailResult{}, err
+	}
+
+	created, err := s.requests.CreateServiceRequestComment(ctx, CreateServiceRequestCommentInput{
+		ServiceRequestID: request.ID,
+		AuthorClientID:   client.ID,
+		Comment:          comment,
+		Files:            files,
+	})
+	if err != nil {
+		return InboundMailResult{}, err
+	}
+
+	return InboundMailResult{
+		Status:           InboundMailStatusCommentCreated,
+		ServiceRequestID: request.ID,
+		CommentID:        created.ID,
+	}, nil
+}
+
+// uploadAttachments stores the files of a message under
+// inbound-mail/<inbound email id>/. A failed upload fails the message, so the
+// MTA delivers it again instead of the files being lost.
+func (s *InboundMailService) uploadAttachments(
+	ctx context.Context,
+	inboundEmailID string,
+	attachments []inboundMailAttachment,
+) ([]CreateClientServiceRequestFileInput, error) {
+	files := make([]CreateClientServiceRequestFileInput, 0, len(attachments))
+	for index, attachment := range attachments {
+		key := fmt.Sprintf("inbound-mail/%s/%d-%s", inboundEmailID, index+1, attachment.FileName)
+		if err := s.storage.PutObject(ctx, key, attachment.ContentType, attachment.Content); err != nil {
+			return nil, fmt.Errorf("upload inbound mail attachment: %w", err)
+		}
+		files = append(files, CreateClientServiceRequestFileInput{
+			FileName:    attachment.FileName,
+			FileKey:     key,
+			ContentType: attachment.ContentType,
+			Notes:       "Recebido por e-mail",
+		})
+	}
+	return files, nil
+}
+
+// sendAcknowledgement confirms the new request to the client. Its Reply-To
+// carries the request token, so answering it adds a comment. Failures are
+// only logged: the request has already been created.
+func (s *InboundMailService) sendAcknowledgement(
+	ctx context.Context,
+	client InboundMailClient,
+	request ClientServiceRequest,
+) {
+	if s.mailer == nil {
+		return
+	}
+
+	body := fmt.Sprintf(
+		"Olá, %s.\n\n"+
+			"Recebemos sua solicitação \"%s\" e ela já está com a nossa equipe.\n\n"+
+			"Para acrescentar informações, basta responder a este e-mail: a resposta "+
+			"será registrada como comentário na solicitação. Você também pode "+
+			"acompanhá-la pelo portal do cliente.\n\n"+
+			"Equipe Shalosh",
+		client.Name,
+		request.Title,
+	)
+
+	if err := s.mailer.Send(ctx, EmailMessage{
+		To:      client.Email,
+		ToName:  client.Name,
+		ReplyTo: s.ReplyAddress(request.ID),
+		Subject: "Recebemos sua solicitação: " + request.Title,
+		Body:    body,
+	}); err != nil {
+		slog.ErrorContext(ctx, "inbound mail acknowledgement failed", "service_request_id", request.ID, "error", err)
+	}
+}
+
+// ReplyAddress returns the address whose replies are threaded onto the
+// service request, e.g. solicitacoes+sr.<id>.<signature>@example.com. It is
+// empty when inbound mail is not configured.
+func (s *InboundMailService) ReplyAddress(serviceRequestID string) string {
+	at := strings.LastIndex(s.config.Address, "@")
+	if s.config.Secret == "" || at <= 0 {
+		return ""
+	}
+
+	token := inboundMailReplyTokenPrefix + "." + serviceRequestID + "." + s.signReplyToken(serviceRequestID)
+	return s.config.Address[:at] + "+" + token + s.config.Address[at:]
+}
+
+// findReplyToken looks for a recipient of the form local+token@domain. It
+// reports whether a token was present and whether its signature is valid.
+func (s *InboundMailService) findReplyToken(recipients []string) (string, bool, bool) {
+	hasToken := false
+	for _, recipient := range recipients {
+		at := strings.LastIndex(recipient, "@")
+		if at <= 0 {
+			continue
+		}
+		plus := strings.Index(recipient[:at], "+")
+		if plus < 0 {
+			continue
+		}
+
+		parts := strings.Split(recipient[plus+1:at], ".")
+		if len(parts) != 3 || parts[0] != inboundMailReplyTokenPrefix {
+			continue
+		}
+		hasToken = true
+
+		expected := s.signReplyToken(parts[1])
+		if hmac.Equal([]byte(parts[2]), []byte(expected)) {
+			return parts[1], true, true
+		}
+	}
+
+	return "", hasToken, false
+}
+
+func (s *InboundMailService) signReplyToken(serviceRequestID string) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Secret))
+	_, _ = mac.Write([]byte(inboundMailReplyTokenPrefix + ":" + strings.ToLower(serviceRequestID)))
+	return hex.EncodeToString(mac.Sum(nil)[:inboundMailReplySignatureBytes])
+}
+
+func inboundMailOutcome(status string, reason string) InboundMailResult {
+	return InboundMailResult{Status: status, Reason: reason}
+}
+
+func truncateInboundMailText(value string, limit int) string {
+	value = strings.TrimSpace(value)
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+
+	runes := []rune(value)
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxInboundMailParts           = 64
+	maxInboundMailDepth           = 5
+	maxInboundMailAttachments     = 10
+	maxInboundMailAttachmentBytes = 5 << 20
+)
+
+type parsedInboundMail struct {
+	MessageID     string
+	From          string
+	FromName      string
+	Subject       string
+	Recipients    []string
+	Text          string
+	Attachments   []inboundMailAttachment
+	AutoGenerated bool
+}
+
+// inboundMailAttachment is a file of a message before it is uploaded.
+type inboundMailAttachment struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+var (
+	inboundMailWordDecoder = &mime.WordDecoder{CharsetReader: inboundMailCharsetReader}
+	inboundMailAddresses   = &mail.AddressParser{WordDecoder: inboundMailWordDecoder}
+
+	inboundMailBreakTagPattern  = regexp.MustCompile(`(?i)<(br|/p|/div|/li|/tr|/h[1-6])[^>]*>`)
+	inboundMailHiddenTagPattern = regexp.MustCompile(`(?is)<(style|script|head)[^>]*>.*?</(style|script|head)>`)
+	inboundMailTagPattern       = regexp.MustCompile(`(?s)<[^>]*>`)
+	inboundMailBlankLines       = regexp.MustCompile(`\n{3,}`)
+	inboundMailSubjectPrefix    = regexp.MustCompile(`(?i)^\s*((re|res|fw|fwd|enc|tr)\s*:\s*)+`)
+	inboundMailQuoteHeader      = regexp.MustCompile(`(?i)^(on|em)\s.+(wrote|escreveu)\s*:\s*$`)
+	inboundMailQuoteSeparator   = regexp.MustCompile(`(?i)^(-{2,}\s*(original message|mensagem original)\s*-{2,}|_{5,})\s*$`)
+)
+
+// windows1252Specials maps the 0x80-0x9F range, where windows-1252 differs
+// from ISO-8859-1. Undefined positions fall back to the Latin-1 code point.
+var windows1252Specials = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡',
+	0x88: 'ˆ', 0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž',
+	0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—',
+	0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›', 0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+// parseInboundMail reads a raw RFC 822 message. The first text/plain part is
+// used as the body, falling back to the first text/html part stripped of its
+// markup; every other part with a file name becomes an inboundMailAttachment
+// holding its decoded bytes, which the service uploads to object storage
+// before saving only the returned key.
+func parseInboundMail(raw []byte) (parsedInboundMail, error) {
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return parsedInboundMail{}, ErrInboundMailInvalid
+	}
+
+	from, err := inboundMailAddresses.Parse(message.Header.Get("From"))
+	if err != nil {
+		return parsedInboundMail{}, ErrInboundMailInvalid
+	}
+
+	subject, err := inboundMailWordDecoder.DecodeHeader(message.Header.Get("Subject"))
+	if err != nil {
+		subject = message.Header.Get("Subject")
+	}
+
+	parsed := parsedInboundMail{
+		MessageID:     strings.Trim(strings.TrimSpace(message.Header.Get("Message-Id")), "<>"),
+		From:          strings.ToLower(strings.TrimSpace(from.Address)),
+		FromName:      strings.TrimSpace(from.Name),
+		Subject:       strings.TrimSpace(subject),
+		AutoGenerated: isAutoGeneratedMail(message.Header),
+	}
+	if parsed.MessageID == "" {
+		sum := sha256.Sum256(raw)
+		parsed.MessageID = "sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	for _, key := range []string{"To", "Cc", "Delivered-To", "X-Original-To"} {
+		for _, value := range message.Header[key] {
+			addresses, err := inboundMailAddresses.ParseList(value)
+			if err != nil {
+				continue
+			}
+			for _, address := range addresses {
+				parsed.Recipients = append(parsed.Recipients, strings.ToLower(address.Address))
+			}
+		}
+	}
+
+	collector := inboundMailCollector{}
+	if err := collector.walk(textproto.MIMEHeader(message.Header), message.Body, 0); err != nil {
+		return parsedInboundMail{}, ErrInboundMailInvalid
+	}
+
+	parsed.Text = collector.plain
+	if strings.TrimSpace(parsed.Text) == "" {
+		parsed.Text = htmlToText(collector.html)
+	}
+	parsed.Text = normalizeInboundMailText(parsed.Text)
+	parsed.Attachments = collector.attachments
+
+	return parsed, nil
+}
+
+type inboundMailCollector struct {
+	plain       string
+	html        string
+	attachments []inboundMailAttachment
+	parts       int
+}
+
+func (c *inboundMailCollector) walk(header textproto.MIMEHeader, body io.Reader, depth int) error {
+	c.parts++
+	if c.parts > maxInboundMailParts || depth > maxInboundMailDepth {
+		return nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := c.walk(part.Header, part, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(io.LimitReader(decodeTransferEncoding(header, body), maxInboundMailAttachmentBytes+1))
+	if err != nil {
+		return err
+	}
+
+	fileName := inboundMailFileName(header, params)
+	disposition, _, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	isBodyText := (mediaType == "text/plain" || mediaType == "text/html") &&
+		disposition != "attachment" && fileName == ""
+
+	switch {
+	case isBodyText && mediaType == "text/plain":
+		if c.plain == "" {
+			c.plain = decodeInboundMailCharset(content, params["charset"])
+		}
+	case isBodyText:
+		if c.html == "" {
+			c.html = decodeInboundMailCharset(content, params["charset"])
+		}
+	default:
+		if len(content) == 0 ||
+			len(content) > maxInboundMailAttachmentBytes ||
+			len(c.attachments) >= maxInboundMailAttachments {
+			return nil
+		}
+		if fileName == "" {
+			if mediaType == "message/rfc822" {
+				fileName = "mensagem.eml"
+			} else {
+				fileName = fmt.Sprintf("anexo-%d", len(c.attachments)+1)
+			}
+		}
+		c.attachments = append(c.attachments, inboundMailAttachment{
+			FileName:    fileName,
+			ContentType: mediaType,
+			Content:     content,
+		})
+	}
+
+	return nil
+}
+
+func decodeTransferEncoding(header textproto.MIMEHeader, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(header.Get("Content-Transfer-Encoding"))) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+func inboundMailFileName(header textproto.MIMEHeader, contentTypeParams map[string]string) string {
+	name := ""
+	if _, params, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil {
+		name = params["filename"]
+	}
+	if name == "" {
+		name = contentTypeParams["name"]
+	}
+	if decoded, err := inboundMailWordDecoder.DecodeHeader(name); err == nil {
+		name = decoded
+	}
+
+	name = strings.TrimSpace(strings.NewReplacer("/", "_", "\\", "_").Replace(name))
+	return name
+}
+
+func isAutoGeneratedMail(header mail.Header) bool {
+	autoSubmitted := strings.ToLower(strings.TrimSpace(header.Get("Auto-Submitted")))
+	if autoSubmitted != "" && autoSubmitted != "no" {
+		return true
+	}
+	switch strings.ToLower(strings.TrimSpace(header.Get("Precedence"))) {
+	case "bulk", "junk", "list", "auto_reply":
+		return true
+	}
+	return header.Get("X-Autoreply") != "" || header.Get("X-Autorespond") != ""
+}
+
+func inboundMailCharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	content, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+	return strings.NewReader(decodeInboundMailCharset(content, charset)), nil
+}
+
+// decodeInboundMailCharset converts the charsets Brazilian mail clients
+// actually send; anything else is read as UTF-8 with invalid bytes replaced.
+func decodeInboundMailCharset(content []byte, charset string) string {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "iso-8859-1", "iso8859-1", "latin1", "iso-8859-15", "windows-1252", "cp1252":
+		var builder strings.Builder
+		builder.Grow(len(content))
+		for _, b := range content {
+			if special, ok := windows1252Specials[b]; ok {
+				builder.WriteRune(special)
+				continue
+			}
+			builder.WriteRune(rune(b))
+		}
+		return builder.String()
+	default:
+		if utf8.Valid(content) {
+			return string(content)
+		}
+		return strings.ToValidUTF8(string(content), "�")
+	}
+}
+
+func htmlToText(value string) string {
+	value = inboundMailHiddenTagPattern.ReplaceAllString(value, "")
+	value = inboundMailBreakTagPattern.ReplaceAllString(value, "\n")
+	value = inboundMailTagPattern.ReplaceAllString(value, "")
+	return html.UnescapeString(value)
+}
+
+func normalizeInboundMailText(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	value = strings.ReplaceAll(value, "\u00a0", " ")
+
+	lines := strings.Split(value, "\n")
+	for index, line := range lines {
+		lines[index] = strings.TrimRight(line, " \t")
+	}
+
+	value = strings.Join(lines, "\n")
+	value = inboundMailBlankLines.ReplaceAllString(value, "\n\n")
+	return strings.TrimSpace(value)
+}
+
+// stripQuotedReply keeps what the client wrote above the quoted message and
+// the signature separator, so replies do not repeat the whole thread.
+func stripQuotedReply(value string) string {
+	lines := strings.Split(value, "\n")
+	kept := make([]string, 0, len(lines))
+	for index, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, ">") ||
+			line == "-- " ||
+			inboundMailQuoteHeader.MatchString(trimmed) ||
+			inboundMailQuoteSeparator.MatchString(trimmed) {
+			break
+		}
+		// Gmail wraps long "On ... wrote:" lines in two.
+		if index+1 < len(lines) &&
+			inboundMailQuoteHeader.MatchString(trimmed+" "+strings.TrimSpace(lines[index+1])) {
+			break
+		}
+		kept = append(kept, line)
+	}
+
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+func cleanInboundMailSubject(value string) string {
+	return strings.TrimSpace(inboundMailSubjectPrefix.ReplaceAllString(value, ""))
+}
@@ -0,0 +1,596 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	InvoiceKindInvoice    = "invoice"
+	InvoiceKindCreditNote = "credit_note"
+
+	InvoiceStatusIssued    = "emitida"
+	InvoiceStatusCancelled = "cancelada"
+
+	InvoiceSourceRevenue       = "revenue"
+	InvoiceSourceMonthlyCharge = "monthly_charge"
+
+	defaultInvoiceSeriesCode    = "NFS"
+	defaultCreditNoteSeriesCode = "NC"
+)
+
+type InvoiceRepository interface {
+	ListInvoiceSeries(ctx context.Context) ([]InvoiceSeries, error)
+	ListInvoices(ctx context.Context, filter InvoiceListFilter) ([]Invoice, error)
+	GetInvoice(ctx context.Context, invoiceID string) (Invoice, error)
+	GetProjectTypeIssRate(ctx context.Context, projectTypeID string) (float64, error)
+	IssueInvoice(ctx context.Context, input NewInvoice) (Invoice, error)
+	CancelInvoice(ctx context.Context, input CancelInvoiceRecordInput) (Invoice, error)
+}
+
+type InvoiceService struct {
+	repo     InvoiceRepository
+	projects ProjectRepository
+	clients  ClientRepository
+}
+
+func NewInvoiceService(
+	repo InvoiceRepository,
+	projects ProjectRepository,
+	clients ClientRepository,
+) *InvoiceService {
+	return &InvoiceService{
+		repo:     repo,
+		projects: projects,
+		clients:  clients,
+	}
+}
+
+type InvoiceSeries struct {
+	ID         string    `json:"id"`
+	Code       string    `json:"code"`
+	Name       string    `json:"name"`
+	Kind       string    `json:"kind"`
+	NextNumber int       `json:"nextNumber"`
+	Active     bool      `json:"active"`
+	Created    time.Time `json:"created"`
+	Updated    time.Time `json:"updated"`
+}
+
+type InvoiceItem struct {
+	ID          string  `json:"id"`
+	Position    int     `json:"position"`
+	Description string  `json:"description"`
+	Quantity    float64 `json:"quantity"`
+	UnitPrice   float64 `json:"unitPrice"`
+	Amount      float64 `json:"amount"`
+}
+
+type Invoice struct {
+	ID                     string        `json:"id"`
+	SeriesID               string        `json:"seriesId"`
+	SeriesCode             string        `json:"seriesCode"`
+	Number                 int           `json:"number"`
+	DisplayNumber          string        `json:"displayNumber"`
+	Kind                   string        `json:"kind"`
+	Status                 string        `json:"status"`
+	ProjectID              string        `json:"projectId"`
+	ClientID               string        `json:"clientId"`
+	ProjectRevenueID       string        `json:"projectRevenueId,omitempty"`
+	ProjectMonthlyChargeID string        `json:"projectMonthlyChargeId,omitempty"`
+	OriginalInvoiceID      string        `json:"originalInvoiceId,omitempty"`
+	CreditNoteID           string        `json:"creditNoteId,omitempty"`
+	ClientName             string        `json:"clientName"`
+	ClientEmail            string        `json:"clientEmail"`
+	BillingAddress         string        `json:"billingAddress"`
+	BillingZipCode         string        `json:"billingZipCode"`
+	BillingCity            string        `json:"billingCity"`
+	BillingState           string        `json:"billingState"`
+	ProjectName            string        `json:"projectName"`
+	ProjectTypeName        string        `json:"projectTypeName"`
+	IssuedOn               time.Time     `json:"issuedOn"`
+	DueOn                  *time.Time    `json:"dueOn,omitempty"`
+	ServicesAmount         float64       `json:"servicesAmount"`
+	IssRate                float64       `json:"issRate"`
+	IssAmount              float64       `json:"issAmount"`
+	TotalAmount            float64       `json:"totalAmount"`
+	Notes                  string        `json:"notes"`
+	CancelledAt            *time.Time    `json:"cancelledAt,omitempty"`
+	CancellationReason     string        `json:"cancellationReason,omitempty"`
+	Items                  []InvoiceItem `json:"items"`
+	Created                time.Time     `json:"created"`
+	Updated                time.Time     `json:"updated"`
+}
+
+type InvoiceListFilter struct {
+	ProjectID string
+	ClientID  string
+	Kind      string
+	Status    string
+}
+
+type InvoiceItemInput struct {
+	Description string
+	Quantity    float64
+	UnitPrice   float64
+}
+
+type IssueInvoiceInput struct {
+	ProjectID  string
+	SourceType string
+	SourceID   string
+	ClientID   string
+	SeriesCode string
+	IssuedOn   *time.Time
+	DueOn      *time.Time
+	Notes      string
+	Items      []InvoiceItemInput
+}
+
+type CancelInvoiceInput struct {
+	ID                   string
+	Reason               string
+	CreditNoteSeriesCode string
+}
+
+type CancelInvoiceResult struct {
+	Invoice    Invoice `json:"invoice"`
+	CreditNote Invoice `json:"creditNote"`
+}
+
+// NewInvoice is a fully priced invoice ready to be numbered and persisted.
+// Client and project data are copied so the document never changes after
+// issue, even if the client or project is edited later.
+type NewInvoice struct {
+	SeriesCode             string
+	ProjectID              string
+	ClientID               string
+	ProjectRevenueID       string
+	ProjectMonthlyChargeID string
+	ClientName             string
+	ClientEmail            string
+	BillingAddress         string
+	BillingZipCode         string
+	BillingCity            string
+	BillingState           string
+	ProjectName            string
+	ProjectTypeName        string
+	IssuedOn               time.Time
+	DueOn                  *time.Time
+	ServicesAmount         float64
+	IssRate                float64
+	IssAmount              float64
+	TotalAmount            float64
+	Notes                  string
+	Items                  []InvoiceItem
+}
+
+type CancelInvoiceRecordInput struct {
+	ID                   string
+	Reason               string
+	CreditNoteSeriesCode string
+	CancelledAt          time.Time
+}
+
+func (s *InvoiceService) ListInvoiceSeries(ctx context.Context) ([]InvoiceSeries, error) {
+	ctx, span := startSpan(ctx, "InvoiceService.ListInvoiceSeries")
+	defer span.End()
+
+	return s.repo.ListInvoiceSeries(ctx)
+}
+
+func (s *InvoiceService) ListInvoices(
+	ctx context.Context,
+	filter InvoiceListFilter,
+) ([]Invoice, error) {
+	ctx, span := startSpan(ctx, "InvoiceService.ListInvoices")
+	defer span.End()
+
+	normalizedFilter := InvoiceListFilter{
+		ProjectID: strings.TrimSpace(filter.ProjectID),
+		ClientID:  strings.TrimSpace(filter.ClientID),
+		Kind:      strings.ToLower(strings.TrimSpace(filter.Kind)),
+		Status:    strings.ToLower(strings.TrimSpace(filter.Status)),
+	}
+	if normalizedFilter.Kind != "" &&
+		normalizedFilter.Kind != InvoiceKindInvoice &&
+		normalizedFilter.Kind != InvoiceKindCreditNote {
+		return nil, ErrInvalidInput
+	}
+	if normalizedFilter.Status != "" &&
+		normalizedFilter.Status != InvoiceStatusIssued &&
+		normalizedFilter.Status != InvoiceStatusCancelled {
+		return nil, ErrInvalidInput
+	}
+
+	return s.repo.ListInvoices(ctx, normalizedFilter)
+}
+
+func (s *InvoiceService) GetInvoice(ctx context.Context, invoiceID string) (Invoice, error) {
+	ctx, span := startSpan(ctx, "InvoiceService.GetInvoice")
+	defer span.End()
+
+	id := strings.TrimSpace(invoiceID)
+	if id == "" {
+		return Invoice{}, ErrInvalidInput
+	}
+
+	return s.repo.GetInvoice(ctx, id)
+}
+
+func (s *InvoiceService) IssueInvoice(
+	ctx context.Context,
+	input IssueInvoiceInput,
+) (Invoice, error) {
+	ctx, span := startSpan(ctx, "InvoiceService.IssueInvoice")
+	defer span.End()
+
+	normalizedInput, err := normalizeIssueInvoiceInput(input)
+	if err != nil {
+		return Invoice{}, err
+	}
+
+	project, err := s.projects.GetProjectDetail(ctx, normalizedInput.ProjectID)
+	if err != nil {
+		return Invoice{}, err
+	}
+
+	newInvoice := NewInvoice{
+		SeriesCode:      normalizedInput.SeriesCode,
+		ProjectID:       project.ID,
+		ProjectName:     project.Name,
+		ProjectTypeName: project.ProjectTypeName,
+		IssuedOn:        *normalizedInput.IssuedOn,
+		DueOn:           normalizedInput.DueOn,
+		Notes:           normalizedInput.Notes,
+	}
+
+	var sourceAmount float64
+	var sourceDescription string
+	switch normalizedInput.SourceType {
+	case InvoiceSourceRevenue:
+		revenue, found := findProjectRevenue(project.Revenues, normalizedInput.SourceID)
+		if !found {
+			return Invoice{}, ErrNotFound
+		}
+		if revenue.Status == "cancelado" {
+			return Invoice{}, ErrInvoiceSourceNotBillable
+		}
+		newInvoice.ProjectRevenueID = revenue.ID
+		sourceAmount = revenue.Amount
+		sourceDescription = revenue.Title
+		if newInvoice.DueOn == nil {
+			newInvoice.DueOn = revenue.ExpectedOn
+		}
+	case InvoiceSourceMonthlyCharge:
+		charge, found := findProjectMonthlyCharge(project.MonthlyCharges, normalizedInput.SourceID)
+		if !found {
+			return Invoice{}, ErrNotFound
+		}
+		if charge.Status == "cancelada" {
+			return Invoice{}, ErrInvoiceSourceNotBillable
+		}
+		newInvoice.ProjectMonthlyChargeID = charge.ID
+		sourceAmount = charge.Amount
+		sourceDescription = strings.TrimSpace(fmt.Sprintf("%s %s", charge.Title, charge.Installment))
+		if newInvoice.DueOn == nil {
+			dueOn := ProjectMonthlyChargeDueDate(charge)
+			newInvoice.DueOn = &dueOn
+		}
+	}
+
+	clientID, err := resolveInvoiceClientID(project.Clients, normalizedInput.ClientID)
+	if err != nil {
+		return Invoice{}, err
+	}
+	client, err := s.clients.GetDetail(ctx, clientID)
+	if err != nil {
+		return Invoice{}, err
+	}
+	applyInvoiceBillingData(&newInvoice, client)
+
+	items := buildInvoiceItems(normalizedInput.Items, sourceDescription, sourceAmount)
+	servicesAmount := 0.0
+	for _, item := range items {
+		servicesAmount += item.Amount
+	}
+	servicesAmount = roundProjectMoney(servicesAmount)
+	if math.Abs(servicesAmount-roundProjectMoney(sourceAmount)) > 0.005 {
+		return Invoice{}, ErrInvoiceAmountMismatch
+	}
+
+	issRate, err := s.repo.GetProjectTypeIssRate(ctx, project.ProjectTypeID)
+	if err != nil {
+		return Invoice{}, err
+	}
+
+	newInvoice.Items = items
+	newInvoice.ServicesAmount = servicesAmount
+	newInvoice.IssRate = issRate
+	newInvoice.IssAmount = roundProjectMoney(servicesAmount * issRate / 100)
+	newInvoice.TotalAmount = servicesAmount
+
+	return s.repo.IssueInvoice(ctx, newInvoice)
+}
+
+func (s *InvoiceService) CancelInvoice(
+	ctx context.Context,
+	input CancelInvoiceInput,
+) (CancelInvoiceResult, error) {
+	ctx, span := startSpan(ctx, "InvoiceService.CancelInvoice")
+	defer span.End()
+
+	normalizedInput := CancelInvoiceRecordInput{
+		ID:                   strings.TrimSpace(input.ID),
+		Reason:               strings.TrimSpace(input.Reason),
+		CreditNoteSeriesCode: strings.ToUpper(strings.TrimSpace(input.CreditNoteSeriesCode)),
+		CancelledAt:          time.Now().UTC(),
+	}
+	if normalizedInput.ID == "" || normalizedInput.Reason == "" {
+		return CancelInvoiceResult{}, ErrInvalidInput
+	}
+	if normalizedInput.CreditNoteSeriesCode == "" {
+		normalizedInput.CreditNoteSeriesCode = defaultCreditNoteSeriesCode
+	}
+
+	creditNote, err := s.repo.CancelInvoice(ctx, normalizedInput)
+	if err != nil {
+		return CancelInvoiceResult{}, err
+	}
+
+	invoice, err := s.repo.GetInvoice(ctx, normalizedInput.ID)
+	if err != nil {
+		return CancelInvoiceResult{}, err
+	}
+
+	return CancelInvoiceResult{Invoice: invoice, CreditNote: creditNote}, nil
+}
+
+func (s *InvoiceService) ExportInvoicePDF(
+	ctx context.Context,
+	invoiceID string,
+	style ProjectPDFStyle,
+) (Invoice, []byte, error) {
+	ctx, span := startSpan(ctx, "InvoiceService.ExportInvoicePDF")
+	defer span.End()
+
+	invoice, err := s.GetInvoice(ctx, invoiceID)
+	if err != nil {
+		return Invoice{}, nil, err
+	}
+
+	resolvedStyle := resolveProjectPDFStyle(style)
+	lines := buildInvoicePDFLines(invoice)
+	return invoice, renderSimpleProjectPDF(lines, resolvedStyle), nil
+}
+
+// FormatInvoiceNumber renders the printed number of an invoice, zero padded
+// inside its series (e.g. NFS-000042).
+func FormatInvoiceNumber(seriesCode string, number int) string {
+	return fmt.Sprintf("%s-%06d", strings.ToUpper(seriesCode), number)
+}
+
+func normalizeIssueInvoiceInput(input IssueInvoiceInput) (IssueInvoiceInput, error) {
+	normalizedInput := IssueInvoiceInput{
+		ProjectID:  strings.TrimSpace(input.ProjectID),
+		SourceType: strings.ToLower(strings.TrimSpace(input.SourceType)),
+		SourceID:   strings.TrimSpace(input.SourceID),
+		ClientID:   strings.TrimSpace(input.ClientID),
+		SeriesCode: strings.ToUpper(strings.TrimSpace(input.SeriesCode)),
+		IssuedOn:   input.IssuedOn,
+		DueOn:      input.DueOn,
+		Notes:      strings.TrimSpace(input.Notes),
+		Items:      make([]InvoiceItemInput, 0, len(input.Items)),
+	}
+
+	switch normalizedInput.SourceType {
+	case "receita", "revenues":
+		normalizedInput.SourceType = InvoiceSourceRevenue
+	case "cobranca", "monthly-charge", "monthly_charges":
+		normalizedInput.SourceType = InvoiceSourceMonthlyCharge
+	}
+
+	if normalizedInput.ProjectID == "" || normalizedInput.SourceID == "" {
+		return IssueInvoiceInput{}, ErrInvalidInput
+	}
+	if normalizedInput.SourceType != InvoiceSourceRevenue &&
+		normalizedInput.SourceType != InvoiceSourceMonthlyCharge {
+		return IssueInvoiceInput{}, ErrInvalidInput
+	}
+	if normalizedInput.SeriesCode == "" {
+		normalizedInput.SeriesCode = defaultInvoiceSeriesCode
+	}
+	if normalizedInput.IssuedOn == nil {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		normalizedInput.IssuedOn = &today
+	}
+
+	for _, item := range input.Items {
+		description := strings.TrimSpace(item.Description)
+		if description == "" || item.Quantity <= 0 || item.UnitPrice < 0 {
+			return IssueInvoiceInput{}, ErrInvalidInput
+		}
+		normalizedInput.Items = append(normalizedInput.Items, InvoiceItemInput{
+			Description: description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+		})
+	}
+
+	return normalizedInput, nil
+}
+
+func findProjectRevenue(revenues []ProjectRevenue, revenueID string) (ProjectRevenue, bool) {
+	for _, revenue := range revenues {
+		if revenue.ID == revenueID {
+			return revenue, true
+		}
+	}
+
+	return ProjectRevenue{}, false
+}
+
+func findProjectMonthlyCharge(
+	charges []ProjectMonthlyCharge,
+	chargeID string,
+) (ProjectMonthlyCharge, bool) {
+	for _, charge := range charges {
+		if charge.ID == chargeID {
+			return charge, true
+		}
+	}
+
+	return ProjectMonthlyCharge{}, false
+}
+
+// resolveInvoiceClientID bills the requested client when it belongs to the
+// project, or the first linked client otherwise.
+func resolveInvoiceClientID(clients []ProjectClient, requestedClientID string) (string, error) {
+	if len(clients) == 0 {
+		return "", ErrInvoiceClientRequired
+	}
+	if requestedClientID == "" {
+		return clients[0].ClientID, nil
+	}
+
+	for _, client := range clients {
+		if client.ClientID == requestedClientID {
+			return client.ClientID, nil
+		}
+	}
+
+	return "", ErrInvoiceClientRequired
+}
+
+func applyInvoiceBillingData(invoice *NewInvoice, client ClientDetail) {
+	invoice.ClientID = client.ID
+	invoice.ClientName = client.Name
+	invoice.ClientEmail = client.Email
+
+	addresses := make([]ClientAddress, 0, len(client.Addresses))
+	for _, address := range client.Addresses {
+		if address.Active {
+			addresses = append(addresses, address)
+		}
+	}
+	if len(addresses) == 0 {
+		return
+	}
+	sort.SliceStable(addresses, func(i, j int) bool {
+		return addresses[i].Position < addresses[j].Position
+	})
+
+	address := addresses[0]
+	invoice.BillingAddress = formatInvoiceBillingAddress(address)
+	invoice.BillingZipCode = address.ZipCode
+	invoice.BillingCity = address.City
+	invoice.BillingState = address.State
+}
+
+func formatInvoiceBillingAddress(address ClientAddress) string {
+	street := strings.TrimSpace(address.Street)
+	if street == "" {
+		street = strings.TrimSpace(strings.Join([]string{address.StreetType, address.StreetName}, " "))
+	}
+
+	parts := make([]string, 0, 4)
+	for _, part := range []string{street, address.Number, address.Complement, address.Neighborhood} {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			parts = append(parts, trimmed)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func buildInvoiceItems(
+	inputs []InvoiceItemInput,
+	sourceDescription string,
+	sourceAmount float64,
+) []InvoiceItem {
+	if len(inputs) == 0 {
+		inputs = []InvoiceItemInput{{
+			Description: fallbackProjectText(sourceDescription),
+			Quantity:    1,
+			UnitPrice:   sourceAmount,
+		}}
+	}
+
+	items := make([]InvoiceItem, 0, len(inputs))
+	for index, input := range inputs {
+		items = append(items, InvoiceItem{
+			Position:    index + 1,
+			Description: input.Description,
+			Quantity:    input.Quantity,
+			UnitPrice:   roundProjectMoney(input.UnitPrice),
+			Amount:      roundProjectMoney(input.Quantity * input.UnitPrice),
+		})
+	}
+
+	return items
+}
+
+func buildInvoicePDFLines(invoice Invoice) []string {
+	title := "NOTA FISCAL DE SERVICO"
+	if invoice.Kind == InvoiceKindCreditNote {
+		title = "NOTA DE CREDITO"
+	}
+
+	lines := []string{
+		title,
+		fmt.Sprintf("Gerado em: %s", time.Now().Local().Format("02/01/2006 15:04:05")),
+		"",
+		fmt.Sprintf("Numero: %s", invoice.DisplayNumber),
+		fmt.Sprintf("Emissao: %s", formatProjectDate(&invoice.IssuedOn)),
+		fmt.Sprintf("Vencimento: %s", formatProjectDate(invoice.DueOn)),
+		fmt.Sprintf("Situacao: %s", formatInvoiceStatus(invoice.Status)),
+	}
+	if invoice.Status == InvoiceStatusCancelled {
+		lines = append(lines, fmt.Sprintf("Motivo do cancelamento: %s", fallbackProjectText(invoice.CancellationReason)))
+	}
+
+	lines = append(lines,
+		"",
+		"TOMADOR DO SERVICO",
+		fmt.Sprintf("Nome: %s", fallbackProjectText(invoice.ClientName)),
+		fmt.Sprintf("E-mail: %s", fallbackProjectText(invoice.ClientEmail)),
+		fmt.Sprintf("Endereco: %s", fallbackProjectText(invoice.BillingAddress)),
+		fmt.Sprintf("CEP: %s | Cidade: %s | UF: %s", fallbackProjectText(invoice.BillingZipCode), fallbackProjectText(invoice.BillingCity), fallbackProjectText(invoice.BillingState)),
+		"",
+		"DISCRIMINACAO DOS SERVICOS",
+		fmt.Sprintf("Projeto: %s (%s)", fallbackProjectText(invoice.ProjectName), fallbackProjectText(invoice.ProjectTypeName)),
+	)
+	for _, item := range invoice.Items {
+		lines = append(lines, fmt.Sprintf("%d. %s | qtd: %.2f | unitario: %.2f | total: %.2f", item.Position, item.Description, item.Quantity, item.UnitPrice, item.Amount))
+	}
+
+	lines = append(lines,
+		"",
+		"VALORES",
+		fmt.Sprintf("Valor dos servicos: %.2f", invoice.ServicesAmount),
+		fmt.Sprintf("Aliquota ISS: %.2f%%", invoice.IssRate),
+		fmt.Sprintf("Valor do ISS: %.2f", invoice.IssAmount),
+		fmt.Sprintf("Valor total da nota: %.2f", invoice.TotalAmount),
+	)
+
+	if invoice.Notes != "" {
+		lines = append(lines, "", "OBSERVACOES", invoice.Notes)
+	}
+
+	return wrapProjectPDFLines(lines, projectPDFWrapWidth)
+}
+
+func formatInvoiceStatus(value string) string {
+	switch value {
+	case InvoiceStatusIssued:
+		return "Emitida"
+	case InvoiceStatusCancelled:
+		return "Cancelada"
+	default:
+		return fallbackProjectText(value)
+	}
+}
@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	NotificationTypeServiceRequestCreated    = "service_request.created"
+	NotificationTypeServiceRequestComment    = "service_request.comment"
+	NotificationTypeServiceRequestStatus     = "service_request.status"
+	NotificationTypeProjectTaskComment       = "project_task.comment"
+	NotificationTypeProjectStatus            = "project.status"
+	NotificationTypeServiceRequestSLAWarning = "service_request.sla_warning"
+	NotificationTypeServiceRequestSLABreach  = "service_request.sla_breach"
+	NotificationTypeServiceRequestAssigned   = "service_request.assigned"
+
+	defaultNotificationListLimit = 50
+	maxNotificationListLimit     = 200
+	notificationExcerptLength    = 160
+)
+
+type NotificationRepository interface {
+	CreateNotifications(ctx context.Context, event NotificationEvent) (int, error)
+	ListNotifications(ctx context.Context, filter NotificationListFilter) ([]Notification, error)
+	CountUnreadNotifications(ctx context.Context, recipient NotificationRecipient) (int, error)
+	MarkNotificationRead(ctx context.Context, recipient NotificationRecipient, notificationID string) (Notification, error)
+	MarkAllNotificationsRead(ctx context.Context, recipient NotificationRecipient) (int, error)
+}
+
+// Notifier is implemented by NotificationService and used by the services
+// that emit events. Notifying is best effort: the action that triggered the
+// event has already been stored when Notify is called.
+type Notifier interface {
+	Notify(ctx context.Context, event NotificationEvent)
+}
+
+// NotificationRecipient identifies either an admin user or a portal client.
+type NotificationRecipient struct {
+	UserID   string
+	ClientID string
+}
+
+// NotificationAudience describes who should receive an event. The repository
+// expands it into one notification per user or client, skipping the actor.
+type NotificationAudience struct {
+	ProjectManagersOf string
+	ProjectClientsOf  string
+	TaskResponsibleOf string
+	UserIDs           []string
+	ClientIDs         []string
+	// AllUsersWhenEmpty notifies every active user when the other user
+	// groups resolve to nobody, e.g. a service request without a project.
+	AllUsersWhenEmpty bool
+}
+
+type NotificationEvent struct {
+	Type             string
+	Title            string
+	Message          string
+	ProjectID        string
+	ServiceRequestID string
+	ProjectTaskID    string
+	ActorUserID      string
+	ActorClientID    string
+	Audience         NotificationAudience
+}
+
+type Notification struct {
+	ID               string     `json:"id"`
+	Type             string     `json:"type"`
+	Title            string     `json:"title"`
+	Message          string     `json:"message"`
+	ProjectID        string     `json:"projectId,omitempty"`
+	ServiceRequestID string     `json:"serviceRequestId,omitempty"`
+	ProjectTaskID    string     `json:"projectTaskId,omitempty"`
+	Read             bool       `json:"read"`
+	ReadAt           *time.Time `json:"readAt,omitempty"`
+	Created          time.Time  `json:"created"`
+}
+
+type NotificationList struct {
+	Notifications []Notification `json:"notifications"`
+	UnreadCount   int            `json:"unreadCount"`
+}
+
+type NotificationListFilter struct {
+	Recipient  NotificationRecipient
+	UnreadOnly bool
+	Limit      int
+}
+
+type NotificationService struct {
+	repo NotificationRepository
+}
+
+func NewNotificationService(repo NotificationRepository) *NotificationService {
+	return &NotificationService{repo: repo}
+}
+
+func (s *NotificationService) Notify(ctx context.Context, event NotificationEvent) {
+	ctx, span := startSpan(ctx, "NotificationService.Notify")
+	defer span.End()
+
+	if s == nil || s.repo == nil {
+		return
+	}
+
+	event.Title = strings.TrimSpace(event.Title)
+	event.Message = notificationExcerpt(event.Message)
+	if event.Type == "" || event.Title == "" {
+		return
+	}
+
+	if _, err := s.repo.CreateNotifications(ctx, event); err != nil {
+		slog.ErrorContext(ctx, "notification failed", "type", event.Type, "error", err)
+	}
+}
+
+func (s *NotificationService) ListNotifications(
+	ctx context.Context,
+	filter NotificationListFilter,
+) (NotificationList, error) {
+	ctx, span := startSpan(ctx, "NotificationService.ListNotifications")
+	defer span.End()
+
+	recipient, err := normalizeNotificationRecipient(filter.Recipient)
+	if err != nil {
+		return NotificationList{}, err
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultNotificationListLimit
+	}
+	if limit > maxNotificationListLimit {
+		limit = maxNotificationListLimit
+	}
+
+	notifications, err := s.repo.ListNotifications(ctx, NotificationListFilter{
+		Recipient:  recipient,
+		UnreadOnly: filter.UnreadOnly,
+		Limit:      limit,
+	})
+	if err != nil {
+		return NotificationList{}, err
+	}
+
+	unreadCount, err := s.repo.CountUnreadNotifications(ctx, recipient)
+	if err != nil {
+		return NotificationList{}, err
+	}
+
+	return NotificationList{
+		Notifications: notifications,
+		UnreadCount:   unreadCount,
+	}, nil
+}
+
+func (s *NotificationService) MarkNotificationRead(
+	ctx context.Context,
+	recipient NotificationRecipient,
+	notificationID string,
+) (Notification, error) {
+	ctx, span := startSpan(ctx, "NotificationService.MarkNotificationRead")
+	defer span.End()
+
+	normalizedRecipient, err := normalizeNotificationRecipient(recipient)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	id := strings.TrimSpace(notificationID)
+	if id == "" {
+		return Notification{}, ErrInvalidInput
+	}
+
+	return s.repo.MarkNotificationRead(ctx, normalizedRecipient, id)
+}
+
+func (s *NotificationService) MarkAllNotificationsRead(
+	ctx context.Context,
+	recipient NotificationRecipient,
+) (int, error) {
+	ctx, span := startSpan(ctx, "NotificationService.MarkAllNotificationsRead")
+	defer span.End()
+
+	normalizedRecipient, err := normalizeNotificationRecipient(recipient)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.repo.MarkAllNotificationsRead(ctx, normalizedRecipient)
+}
+
+func normalizeNotificationRecipient(recipient NotificationRecipient) (NotificationRecipient, error) {
+	normalized := NotificationRecipient{
+		UserID:   strings.TrimSpace(recipient.UserID),
+		ClientID: strings.TrimSpace(recipient.ClientID),
+	}
+	if (normalized.UserID == "") == (normalized.ClientID == "") {
+		return NotificationRecipient{}, ErrInvalidInput
+	}
+
+	return normalized, nil
+}
+
+func notificationExcerpt(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if utf8.RuneCountInString(value) <= notificationExcerptLength {
+		return value
+	}
+
+	runes := []rune(value)
+	return strings.TrimSpace(string(runes[:notificationExcerptLength-1])) + "…"
+}
+
+// formatServiceRequestStatusLabel is addressed to the client; statuses
+// created by the admins fall back to their configured name.
+func formatServiceRequestStatusLabel(status string, name string) string {
+	switch status {
+	case ServiceRequestStatusCompleted:
+		return "concluída"
+	case ServiceRequestStatusCancelled:
+		return "cancelada"
+	case ServiceRequestStatusInProgress:
+		return "em andamento"
+	case ServiceRequestStatusAwaitingClient:
+		return "aguardando sua resposta"
+	case ServiceRequestStatusReopened:
+		return "reaberta"
+	case ServiceRequestStatusOpen:
+		return "aberta"
+	}
+	if strings.TrimSpace(name) != "" {
+		return strings.ToLower(strings.TrimSpace(name))
+	}
+	return status
+}
+
+func formatProjectStatusLabel(status string) string {
+	switch status {
+	case "andamento":
+		return "Em andamento"
+	case "concluido":
+		return "Concluído"
+	case "cancelado":
+		return "Cancelado"
+	default:
+		return "Planejamento"
+	}
+}
@@ -0,0 +1,417 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	PaymentMethodPix    = "pix"
+	PaymentMethodBoleto = "boleto"
+
+	PaymentInstructionStatusPending = "pendente"
+	PaymentInstructionStatusPaid    = "pago"
+
+	PaymentWebhookEventPaymentConfirmed = "payment.confirmed"
+
+	PaymentWebhookOutcomeReconciled = "reconciled"
+	PaymentWebhookOutcomeDuplicate  = "duplicate"
+	PaymentWebhookOutcomeUnmatched  = "unmatched"
+	PaymentWebhookOutcomeUnderpaid  = "underpaid"
+	PaymentWebhookOutcomeIgnored    = "ignored"
+)
+
+// PaymentProvider is the port to a PIX/boleto gateway. Implementations
+// create the payable artifacts for a charge and authenticate the webhooks
+// the gateway sends back when money arrives.
+type PaymentProvider interface {
+	Name() string
+	CreatePaymentInstruction(
+		ctx context.Context,
+		request PaymentInstructionRequest,
+	) (PaymentProviderInstruction, error)
+	ParseWebhook(payload []byte, signature string) (PaymentWebhookEvent, error)
+}
+
+// PaymentSimulator is implemented by providers that can fabricate a signed
+// payment webhook, which lets local environments exercise reconciliation.
+type PaymentSimulator interface {
+	SimulatePayment(instruction PaymentInstruction) (payload []byte, signature string, err error)
+}
+
+type QRCodeRenderer interface {
+	RenderPNG(content string) ([]byte, error)
+}
+
+type PaymentRepository interface {
+	ListPaymentInstructions(
+		ctx context.Context,
+		filter PaymentInstructionListFilter,
+	) ([]PaymentInstruction, error)
+	GetPaymentInstruction(ctx context.Context, instructionID string) (PaymentInstruction, error)
+	FindPendingPaymentInstruction(
+		ctx context.Context,
+		monthlyChargeID string,
+		method string,
+	) (PaymentInstruction, error)
+	CreatePaymentInstruction(ctx context.Context, input NewPaymentInstruction) (PaymentInstruction, error)
+	ReconcilePaymentWebhook(
+		ctx context.Context,
+		input ReconcilePaymentWebhookInput,
+	) (PaymentWebhookResult, error)
+}
+
+type PaymentService struct {
+	repo     PaymentRepository
+	projects ProjectRepository
+	provider PaymentProvider
+	qrCodes  QRCodeRenderer
+}
+
+func NewPaymentService(
+	repo PaymentRepository,
+	projects ProjectRepository,
+	provider PaymentProvider,
+	qrCodes QRCodeRenderer,
+) *PaymentService {
+	return &PaymentService{
+		repo:     repo,
+		projects: projects,
+		provider: provider,
+		qrCodes:  qrCodes,
+	}
+}
+
+type PaymentInstruction struct {
+	ID                     string     `json:"id"`
+	ProjectID              string     `json:"projectId"`
+	ProjectMonthlyChargeID string     `json:"projectMonthlyChargeId"`
+	Provider               string     `json:"provider"`
+	ProviderReference      string     `json:"providerReference"`
+	Method                 string     `json:"method"`
+	Status                 string     `json:"status"`
+	Amount                 float64    `json:"amount"`
+	DueOn                  time.Time  `json:"dueOn"`
+	Description            string     `json:"description"`
+	PixCopyPaste           string     `json:"pixCopyPaste,omitempty"`
+	BoletoBarcode          string     `json:"boletoBarcode,omitempty"`
+	BoletoDigitableLine    string     `json:"boletoDigitableLine,omitempty"`
+	PaidAt                 *time.Time `json:"paidAt,omitempty"`
+	PaidAmount             float64    `json:"paidAmount"`
+	Created                time.Time  `json:"created"`
+	Updated                time.Time  `json:"updated"`
+}
+
+type PaymentInstructionListFilter struct {
+	ProjectID       string
+	MonthlyChargeID string
+	Status          string
+}
+
+type CreatePaymentInstructionsInput struct {
+	ProjectID       string
+	MonthlyChargeID string
+	Method          string
+}
+
+type PaymentInstructionRequest struct {
+	Method      string
+	Amount      float64
+	DueOn       time.Time
+	Description string
+}
+
+type PaymentProviderInstruction struct {
+	ProviderReference   string
+	PixCopyPaste        string
+	BoletoBarcode       string
+	BoletoDigitableLine string
+}
+
+type NewPaymentInstruction struct {
+	ProjectID              string
+	ProjectMonthlyChargeID string
+	Provider               string
+	ProviderReference      string
+	Method                 string
+	Amount                 float64
+	DueOn                  time.Time
+	Description            string
+	PixCopyPaste           string
+	BoletoBarcode          string
+	BoletoDigitableLine    string
+}
+
+type PaymentWebhookEvent struct {
+	EventID           string
+	Type              string
+	ProviderReference string
+	AmountPaid        float64
+	PaidAt            time.Time
+}
+
+// ReconcilePaymentWebhookInput carries an authenticated provider event. The
+// repository records it once per (provider, event id) and, for confirmed
+// payments that cover the instruction amount, marks both the instruction and
+// its monthly charge as paid in the same transaction.
+type ReconcilePaymentWebhookInput struct {
+	Provider          string
+	EventID           string
+	EventType         string
+	ProviderReference string
+	AmountPaid        float64
+	PaidAt            time.Time
+	Payload           []byte
+}
+
+type PaymentWebhookResult struct {
+	EventID     string              `json:"eventId"`
+	Outcome     string              `json:"outcome"`
+	Instruction *PaymentInstruction `json:"instruction,omitempty"`
+}
+
+func (s *PaymentService) ListPaymentInstructions(
+	ctx context.Context,
+	filter PaymentInstructionListFilter,
+) ([]PaymentInstruction, error) {
+	ctx, span := startSpan(ctx, "PaymentService.ListPaymentInstructions")
+	defer span.End()
+
+	normalizedFilter := PaymentInstructionListFilter{
+		ProjectID:       strings.TrimSpace(filter.ProjectID),
+		MonthlyChargeID: strings.TrimSpace(filter.MonthlyChargeID),
+		Status:          strings.ToLower(strings.TrimSpace(filter.Status)),
+	}
+	if normalizedFilter.Status != "" &&
+		normalizedFilter.Status != PaymentInstructionStatusPending &&
+		normalizedFilter.Status != PaymentInstructionStatusPaid {
+		return nil, ErrInvalidInput
+	}
+
+	return s.repo.ListPaymentInstructions(ctx, normalizedFilter)
+}
+
+func (s *PaymentService) GetPaymentInstruction(
+	ctx context.Context,
+	instructionID string,
+) (PaymentInstruction, error) {
+	ctx, span := startSpan(ctx, "PaymentService.GetPaymentInstruction")
+	defer span.End()
+
+	id := strings.TrimSpace(instructionID)
+	if id == "" {
+		return PaymentInstruction{}, ErrInvalidInput
+	}
+
+	return s.repo.GetPaymentInstruction(ctx, id)
+}
+
+// CreatePaymentInstructions returns a payable instruction for one pending
+// monthly charge, or for every pending charge of the project when no charge
+// is given. A pending instruction with the same method is reused so clients
+// never receive two different codes for the same charge.
+func (s *PaymentService) CreatePaymentInstructions(
+	ctx context.Context,
+	input CreatePaymentInstructionsInput,
+) ([]PaymentInstruction, error) {
+	ctx, span := startSpan(ctx, "PaymentService.CreatePaymentInstructions")
+	defer span.End()
+
+	normalizedInput, err := normalizeCreatePaymentInstructionsInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.projects.GetProjectDetail(ctx, normalizedInput.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	charges := make([]ProjectMonthlyCharge, 0, len(project.MonthlyCharges))
+	if normalizedInput.MonthlyChargeID != "" {
+		charge, found := findProjectMonthlyCharge(project.MonthlyCharges, normalizedInput.MonthlyChargeID)
+		if !found {
+			return nil, ErrNotFound
+		}
+		if !isPaymentChargeOpen(charge) {
+			return nil, ErrPaymentChargeNotOpen
+		}
+		charges = append(charges, charge)
+	} else {
+		for _, charge := range project.MonthlyCharges {
+			if isPaymentChargeOpen(charge) {
+				charges = append(charges, charge)
+			}
+		}
+	}
+
+	today := startOfPaymentDay(time.Now().UTC())
+	instructions := make([]PaymentInstruction, 0, len(charges))
+	for _, charge := range charges {
+		existing, err := s.repo.FindPendingPaymentInstruction(ctx, charge.ID, normalizedInput.Method)
+		if err == nil {
+			instructions = append(instructions, existing)
+			continue
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+
+		dueOn := ProjectMonthlyChargeDueDate(charge)
+		if dueOn.Before(today) {
+			dueOn = today
+		}
+		description := strings.TrimSpace(fmt.Sprintf("%s %s", charge.Title, charge.Installment))
+		if project.Name != "" {
+			description = fmt.Sprintf("%s - %s", project.Name, description)
+		}
+
+		providerInstruction, err := s.provider.CreatePaymentInstruction(ctx, PaymentInstructionRequest{
+			Method:      normalizedInput.Method,
+			Amount:      roundProjectMoney(charge.Amount),
+			DueOn:       dueOn,
+			Description: description,
+		})
+		if err != nil {
+			if errors.Is(err, ErrInvalidInput) {
+				return nil, err
+			}
+			return nil, ErrPaymentProviderUnavailable
+		}
+
+		instruction, err := s.repo.CreatePaymentInstruction(ctx, NewPaymentInstruction{
+			ProjectID:              project.ID,
+			ProjectMonthlyChargeID: charge.ID,
+			Provider:               s.provider.Name(),
+			ProviderReference:      providerInstruction.ProviderReference,
+			Method:                 normalizedInput.Method,
+			Amount:                 roundProjectMoney(charge.Amount),
+			DueOn:                  dueOn,
+			Description:            description,
+			PixCopyPaste:           providerInstruction.PixCopyPaste,
+			BoletoBarcode:          providerInstruction.BoletoBarcode,
+			BoletoDigitableLine:    providerInstruction.BoletoDigitableLine,
+		})
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, instruction)
+	}
+
+	return instructions, nil
+}
+
+func (s *PaymentService) GetPaymentInstructionQRCode(
+	ctx context.Context,
+	instructionID string,
+) (PaymentInstruction, []byte, error) {
+	ctx, span := startSpan(ctx, "PaymentService.GetPaymentInstructionQRCode")
+	defer span.End()
+
+	instruction, err := s.GetPaymentInstruction(ctx, instructionID)
+	if err != nil {
+		return PaymentInstruction{}, nil, err
+	}
+	if instruction.Method != PaymentMethodPix || instruction.PixCopyPaste == "" {
+		return PaymentInstruction{}, nil, ErrInvalidInput
+	}
+
+	pngBytes, err := s.qrCodes.RenderPNG(instruction.PixCopyPaste)
+	if err != nil {
+		return PaymentInstruction{}, nil, err
+	}
+
+	return instruction, pngBytes, nil
+}
+
+func (s *PaymentService) HandleWebhook(
+	ctx context.Context,
+	payload []byte,
+	signature string,
+) (PaymentWebhookResult, error) {
+	ctx, span := startSpan(ctx, "PaymentService.HandleWebhook")
+	defer span.End()
+
+	event, err := s.provider.ParseWebhook(payload, signature)
+	if err != nil {
+		return PaymentWebhookResult{}, ErrPaymentWebhookInvalid
+	}
+
+	eventID := strings.TrimSpace(event.EventID)
+	if eventID == "" {
+		return PaymentWebhookResult{}, ErrPaymentWebhookInvalid
+	}
+	paidAt := event.PaidAt.UTC()
+	if paidAt.IsZero() {
+		paidAt = time.Now().UTC()
+	}
+
+	return s.repo.ReconcilePaymentWebhook(ctx, ReconcilePaymentWebhookInput{
+		Provider:          s.provider.Name(),
+		EventID:           eventID,
+		EventType:         strings.TrimSpace(event.Type),
+		ProviderReference: strings.TrimSpace(event.ProviderReference),
+		AmountPaid:        roundProjectMoney(event.AmountPaid),
+		PaidAt:            paidAt,
+		Payload:           payload,
+	})
+}
+
+// SimulatePayment asks the provider for a signed webhook that pays the
+// instruction in full and feeds it through the regular webhook path.
+func (s *PaymentService) SimulatePayment(
+	ctx context.Context,
+	instructionID string,
+) (PaymentWebhookResult, error) {
+	ctx, span := startSpan(ctx, "PaymentService.SimulatePayment")
+	defer span.End()
+
+	simulator, ok := s.provider.(PaymentSimulator)
+	if !ok {
+		return PaymentWebhookResult{}, ErrPaymentSimulationNotSupported
+	}
+
+	instruction, err := s.GetPaymentInstruction(ctx, instructionID)
+	if err != nil {
+		return PaymentWebhookResult{}, err
+	}
+
+	payload, signature, err := simulator.SimulatePayment(instruction)
+	if err != nil {
+		return PaymentWebhookResult{}, err
+	}
+
+	return s.HandleWebhook(ctx, payload, signature)
+}
+
+func normalizeCreatePaymentInstructionsInput(
+	input CreatePaymentInstructionsInput,
+) (CreatePaymentInstructionsInput, error) {
+	normalizedInput := CreatePaymentInstructionsInput{
+		ProjectID:       strings.TrimSpace(input.ProjectID),
+		MonthlyChargeID: strings.TrimSpace(input.MonthlyChargeID),
+		Method:          strings.ToLower(strings.TrimSpace(input.Method)),
+	}
+	if normalizedInput.Method == "" {
+		normalizedInput.Method = PaymentMethodPix
+	}
+	if normalizedInput.ProjectID == "" {
+		return CreatePaymentInstructionsInput{}, ErrInvalidInput
+	}
+	if normalizedInput.Method != PaymentMethodPix && normalizedInput.Method != PaymentMethodBoleto {
+		return CreatePaymentInstructionsInput{}, ErrInvalidInput
+	}
+
+	return normalizedInput, nil
+}
+
+func isPaymentChargeOpen(charge ProjectMonthlyCharge) bool {
+	return charge.Active && charge.Status == "pendente" && charge.Amount > 0
+}
+
+func startOfPaymentDay(value time.Time) time.Time {
+	return time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,372 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	PaymentReminderSourceRevenue       = "revenue"
+	PaymentReminderSourceMonthlyCharge = "monthly_charge"
+)
+
+// DefaultPaymentReminderOffsets are the days relative to the due date on
+// which clients are reminded: five days before, on the day and three and ten
+// days after it.
+var DefaultPaymentReminderOffsets = []int{-5, 0, 3, 10}
+
+type Mailer interface {
+	Send(ctx context.Context, message EmailMessage) error
+}
+
+type EmailMessage struct {
+	To      string
+	ToName  string
+	ReplyTo string
+	Subject string
+	Body    string
+}
+
+type PaymentReminderRepository interface {
+	ListPaymentReminderReceivables(ctx context.Context) ([]PaymentReminderReceivable, error)
+	ReservePaymentReminder(ctx context.Context, input PaymentReminderRecordInput) (bool, error)
+	ReleasePaymentReminder(ctx context.Context, input PaymentReminderRecordInput) error
+	ListClientPaymentReminderPreferences(ctx context.Context, clientID string) ([]ClientPaymentReminderPreference, error)
+	SetClientPaymentReminderOptOut(ctx context.Context, input UpdateClientPaymentReminderPreferenceInput) error
+}
+
+// PaymentReminderReceivable is one pending revenue or monthly charge paired
+// with one of the project's clients. Clients that opted out of reminders for
+// the project are never returned.
+type PaymentReminderReceivable struct {
+	SourceType  string
+	SourceID    string
+	ProjectID   string
+	ProjectName string
+	Title       string
+	Amount      float64
+	DueOn       time.Time
+	ClientID    string
+	ClientName  string
+	ClientEmail string
+}
+
+type PaymentReminderRecordInput struct {
+	SourceType string
+	SourceID   string
+	ClientID   string
+	DueOn      time.Time
+	OffsetDays int
+	Email      string
+}
+
+type PaymentReminderRunResult struct {
+	Evaluated int `json:"evaluated"`
+	Sent      int `json:"sent"`
+	Skipped   int `json:"skipped"`
+	Failed    int `json:"failed"`
+}
+
+type ClientPaymentReminderPreference struct {
+	ProjectID        string `json:"projectId"`
+	ProjectName      string `json:"projectName"`
+	RemindersEnabled bool   `json:"remindersEnabled"`
+}
+
+type UpdateClientPaymentReminderPreferenceInput struct {
+	ClientID  string
+	ProjectID string
+	OptOut    bool
+}
+
+type PaymentReminderService struct {
+	repo    PaymentReminderRepository
+	mailer  Mailer
+	clock   Clock
+	offsets []int
+}
+
+func NewPaymentReminderService(
+	repo PaymentReminderRepository,
+	mailer Mailer,
+	clock Clock,
+	offsets []int,
+) *PaymentReminderService {
+	if len(offsets) == 0 {
+		offsets = DefaultPaymentReminderOffsets
+	}
+
+	return &PaymentReminderService{
+		repo:    repo,
+		mailer:  mailer,
+		clock:   clock,
+		offsets: offsets,
+	}
+}
+
+// ParsePaymentReminderOffsets reads a comma separated list of day offsets
+// such as "-5,0,3,10". Duplicates are dropped and the result is sorted.
+func ParsePaymentReminderOffsets(value string) ([]int, error) {
+	seen := map[int]struct{}{}
+	offsets := make([]int, 0)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		offset, err := strconv.Atoi(part)
+		if err != nil || offset < -60 || offset > 120 {
+			return nil, ErrInvalidInput
+		}
+		if _, exists := seen[offset]; exists {
+			continue
+		}
+		seen[offset] = struct{}{}
+		offsets = append(offsets, offset)
+	}
+
+	if len(offsets) == 0 {
+		return nil, ErrInvalidInput
+	}
+
+	sort.Ints(offsets)
+	return offsets, nil
+}
+
+// SendPaymentReminders emails every client whose receivable is exactly one of
+// the configured offsets away from its due date today. Each reminder is
+// reserved before it is sent, so running the job several times a day (or on
+// several instances) sends it once; failed deliveries release the
+// reservation and are retried on the next run.
+func (s *PaymentReminderService) SendPaymentReminders(ctx context.Context) (PaymentReminderRunResult, error) {
+	ctx, span := startSpan(ctx, "PaymentReminderService.SendPaymentReminders")
+	defer span.End()
+
+	receivables, err := s.repo.ListPaymentReminderReceivables(ctx)
+	if err != nil {
+		return PaymentReminderRunResult{}, err
+	}
+
+	now := s.clock.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	result := PaymentReminderRunResult{}
+	var firstErr error
+	for _, receivable := range receivables {
+		offset := paymentReminderOffsetDays(receivable.DueOn, today)
+		if !s.isReminderOffset(offset) {
+			continue
+		}
+		result.Evaluated++
+
+		record := PaymentReminderRecordInput{
+			SourceType: receivable.SourceType,
+			SourceID:   receivable.SourceID,
+			ClientID:   receivable.ClientID,
+			DueOn:      receivable.DueOn,
+			OffsetDays: offset,
+			Email:      receivable.ClientEmail,
+		}
+		reserved, err := s.repo.ReservePaymentReminder(ctx, record)
+		if err != nil {
+			return result, err
+		}
+		if !reserved {
+			result.Skipped++
+			continue
+		}
+
+		message, err := buildPaymentReminderMessage(receivable, offset)
+		if err == nil {
+			err = s.mailer.Send(ctx, message)
+		}
+		if err != nil {
+			result.Failed++
+			if firstErr == nil {
+				firstErr = fmt.Errorf("send reminder for %s %s: %w", receivable.SourceType, receivable.SourceID, err)
+			}
+			if releaseErr := s.repo.ReleasePaymentReminder(ctx, record); releaseErr != nil {
+				return result, releaseErr
+			}
+			continue
+		}
+
+		result.Sent++
+	}
+
+	return result, firstErr
+}
+
+func (s *PaymentReminderService) ListClientPreferences(
+	ctx context.Context,
+	clientID string,
+) ([]ClientPaymentReminderPreference, error) {
+	ctx, span := startSpan(ctx, "PaymentReminderService.ListClientPreferences")
+	defer span.End()
+
+	normalizedID := strings.TrimSpace(clientID)
+	if normalizedID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	return s.repo.ListClientPaymentReminderPreferences(ctx, normalizedID)
+}
+
+// UpdateClientPreferences applies the opt-outs sent by the client portal and
+// returns the resulting preferences. Projects the client does not belong to
+// are rejected with ErrNotFound.
+func (s *PaymentReminderService) UpdateClientPreferences(
+	ctx context.Context,
+	clientID string,
+	preferences []ClientPaymentReminderPreference,
+) ([]ClientPaymentReminderPreference, error) {
+	ctx, span := startSpan(ctx, "PaymentReminderService.UpdateClientPreferences")
+	defer span.End()
+
+	normalizedID := strings.TrimSpace(clientID)
+	if normalizedID == "" {
+		return nil, ErrInvalidInput
+	}
+
+	for _, preference := range preferences {
+		projectID := strings.TrimSpace(preference.ProjectID)
+		if projectID == "" {
+			return nil, ErrInvalidInput
+		}
+
+		if err := s.repo.SetClientPaymentReminderOptOut(ctx, UpdateClientPaymentReminderPreferenceInput{
+			ClientID:  normalizedID,
+			ProjectID: projectID,
+			OptOut:    !preference.RemindersEnabled,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.ListClientPaymentReminderPreferences(ctx, normalizedID)
+}
+
+func (s *PaymentReminderService) isReminderOffset(offset int) bool {
+	for _, configured := range s.offsets {
+		if configured == offset {
+			return true
+		}
+	}
+	return false
+}
+
+// paymentReminderOffsetDays is negative before the due date and positive
+// once the receivable is overdue.
+func paymentReminderOffsetDays(dueOn time.Time, today time.Time) int {
+	due := time.Date(dueOn.Year(), dueOn.Month(), dueOn.Day(), 0, 0, 0, 0, time.UTC)
+	return int(today.Sub(due).Hours() / 24)
+}
+
+type paymentReminderTemplateData struct {
+	ClientName  string
+	ProjectName string
+	Title       string
+	Amount      string
+	DueOn       string
+	Days        int
+}
+
+var (
+	paymentReminderUpcomingTemplate = template.Must(template.New("upcoming").Parse(
+		`Olá, {{.ClientName}}!
+
+Este é um lembrete de que a cobrança "{{.Title}}" do projeto {{.ProjectName}}, no valor de {{.Amount}}, vence em {{.Days}} {{if eq .Days 1}}dia{{else}}dias{{end}} ({{.DueOn}}).
+
+Se o pagamento já foi realizado, por favor desconsidere esta mensagem.
+
+Atenciosamente,
+Equipe Shalosh
+`))
+	paymentReminderDueTodayTemplate = template.Must(template.New("due_today").Parse(
+		`Olá, {{.ClientName}}!
+
+A cobrança "{{.Title}}" do projeto {{.ProjectName}}, no valor de {{.Amount}}, vence hoje ({{.DueOn}}).
+
+Se o pagamento já foi realizado, por favor desconsidere esta mensagem.
+
+Atenciosamente,
+Equipe Shalosh
+`))
+	paymentReminderOverdueTemplate = template.Must(template.New("overdue").Parse(
+		`Olá, {{.ClientName}}!
+
+Não identificamos o pagamento da cobrança "{{.Title}}" do projeto {{.ProjectName}}, no valor de {{.Amount}}, vencida em {{.DueOn}} (há {{.Days}} {{if eq .Days 1}}dia{{else}}dias{{end}}).
+
+Caso precise de uma nova via ou queira negociar, responda este e-mail. Se o pagamento já foi realizado, por favor desconsidere esta mensagem.
+
+Atenciosamente,
+Equipe Shalosh
+`))
+)
+
+func buildPaymentReminderMessage(receivable PaymentReminderReceivable, offset int) (EmailMessage, error) {
+	data := paymentReminderTemplateData{
+		ClientName:  strings.TrimSpace(receivable.ClientName),
+		ProjectName: strings.TrimSpace(receivable.ProjectName),
+		Title:       strings.TrimSpace(receivable.Title),
+		Amount:      formatBRLAmount(receivable.Amount),
+		DueOn:       receivable.DueOn.Format("02/01/2006"),
+	}
+
+	tmpl := paymentReminderDueTodayTemplate
+	subject := fmt.Sprintf("Sua cobrança vence hoje - %s", data.ProjectName)
+	switch {
+	case offset < 0:
+		tmpl = paymentReminderUpcomingTemplate
+		data.Days = -offset
+		subject = fmt.Sprintf("Lembrete de vencimento - %s", data.ProjectName)
+	case offset > 0:
+		tmpl = paymentReminderOverdueTemplate
+		data.Days = offset
+		subject = fmt.Sprintf("Cobrança em atraso - %s", data.ProjectName)
+	}
+
+	var body strings.Builder
+	if err := tmpl.Execute(&body, data); err != nil {
+		return EmailMessage{}, err
+	}
+
+	return EmailMessage{
+		To:      receivable.ClientEmail,
+		ToName:  data.ClientName,
+		Subject: subject,
+		Body:    body.String(),
+	}, nil
+}
+
+// formatBRLAmount renders a value the way Brazilian invoices do, e.g.
+// "R$ 1.234,56".
+func formatBRLAmount(value float64) string {
+	cents := int64(roundProjectMoney(value)*100 + 0.5)
+	if value < 0 {
+		cents = int64(roundProjectMoney(value)*100 - 0.5)
+	}
+
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	integer := strconv.FormatInt(cents/100, 10)
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return fmt.Sprintf("%sR$ %s,%02d", sign, grouped.String(), cents%100)
+}
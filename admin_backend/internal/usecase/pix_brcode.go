@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	pixGUI                  = "br.gov.bcb.pix"
+	pixMaxMerchantNameLen   = 25
+	pixMaxMerchantCityLen   = 15
+	pixMaxTxIDLen           = 25
+	pixMaxDescriptionLength = 72
+)
+
+// PixBRCodeInput describes a static PIX charge. Amount is optional in the
+// BACEN spec; zero leaves it for the payer to fill in.
+type PixBRCodeInput struct {
+	Key          string
+	MerchantName string
+	MerchantCity string
+	Amount       float64
+	TxID         string
+	Description  string
+}
+
+// BuildPixBRCode renders the EMV "copia e cola" payload for a static PIX
+// BR Code, including the trailing CRC16 checksum.
+func BuildPixBRCode(input PixBRCodeInput) (string, error) {
+	key := strings.TrimSpace(input.Key)
+	merchantName := truncatePixField(sanitizePixText(input.MerchantName), pixMaxMerchantNameLen)
+	merchantCity := truncatePixField(sanitizePixText(input.MerchantCity), pixMaxMerchantCityLen)
+	if key == "" || merchantName == "" || merchantCity == "" || input.Amount < 0 {
+		return "", ErrInvalidInput
+	}
+
+	txID := sanitizePixTxID(input.TxID)
+	if txID == "" {
+		txID = "***"
+	}
+
+	accountInfo := pixField("00", pixGUI) + pixField("01", key)
+	if description := truncatePixField(sanitizePixText(input.Description), pixMaxDescriptionLength); description != "" {
+		accountInfo += pixField("02", description)
+	}
+
+	var payload strings.Builder
+	payload.WriteString(pixField("00", "01"))
+	payload.WriteString(pixField("26", accountInfo))
+	payload.WriteString(pixField("52", "0000"))
+	payload.WriteString(pixField("53", "986"))
+	if amount := roundProjectMoney(input.Amount); amount > 0 {
+		payload.WriteString(pixField("54", fmt.Sprintf("%.2f", amount)))
+	}
+	payload.WriteString(pixField("58", "BR"))
+	payload.WriteString(pixField("59", merchantName))
+	payload.WriteString(pixField("60", merchantCity))
+	payload.WriteString(pixField("62", pixField("05", txID)))
+	payload.WriteString("6304")
+
+	checksum := pixCRC16(payload.String())
+	payload.WriteString(fmt.Sprintf("%04X", checksum))
+
+	return payload.String(), nil
+}
+
+func pixField(id string, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// pixCRC16 implements CRC-16/CCITT-FALSE (polynomial 0x1021, initial value
+// 0xFFFF) as required by the BR Code manual.
+func pixCRC16(payload string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(payload); i++ {
+		crc ^= uint16(payload[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
+
+var asciiAccentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+)
+
+// sanitizePixText keeps BR Code text fields in printable ASCII so that field
+// lengths match byte counts and every bank app can read them.
+func sanitizePixText(value string) string {
+	replaced := asciiAccentReplacer.Replace(strings.TrimSpace(value))
+
+	var builder strings.Builder
+	for _, r := range replaced {
+		if r >= 0x20 && r <= 0x7E {
+			builder.WriteRune(r)
+		}
+	}
+
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+func sanitizePixTxID(value string) string {
+	var builder strings.Builder
+	for _, r := range strings.TrimSpace(value) {
+		if (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			builder.WriteRune(r)
+		}
+	}
+
+	return truncatePixField(builder.String(), pixMaxTxIDLen)
+}
+
+func truncatePixField(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+
+	return strings.TrimSpace(value[:maxLength])
+}
@@ -90,14 +90,38 @@ type ProjectRepository interface {
 		ctx context.Context,
 		input CreateProjectTaskCommentInput,
 	) (ProjectTaskComment, error)
+
+	ListProjectExpenseCategories(ctx context.Context) ([]ProjectExpenseCategory, error)
+	CreateProjectExpenseCategory(
+		ctx context.Context,
+		input CreateProjectExpenseCategoryInput,
+	) (ProjectExpenseCategory, error)
+	ListProjectExpenses(ctx context.Context, projectID string) ([]ProjectExpense, error)
+	CreateProjectExpense(ctx context.Context, input CreateProjectExpenseInput) (ProjectExpense, error)
+	UpdateProjectExpense(ctx context.Context, input UpdateProjectExpenseInput) (ProjectExpense, error)
+	DeleteProjectExpense(ctx context.Context, projectID, expenseID string) error
+
+	ListUserHourlyRates(ctx context.Context, userID string) ([]UserHourlyRate, error)
+	CreateUserHourlyRate(ctx context.Context, input CreateUserHourlyRateInput) (UserHourlyRate, error)
+	DeleteUserHourlyRate(ctx context.Context, rateID string) error
+
+	ListProjectTimeEntries(ctx context.Context, projectID string) ([]ProjectTimeEntry, error)
+	CreateProjectTimeEntry(ctx context.Context, input CreateProjectTimeEntryInput) (ProjectTimeEntry, error)
+	DeleteProjectTimeEntry(ctx context.Context, projectID, entryID string) error
+
+	ListProjectFinancialEntries(
+		ctx context.Context,
+		filter ProjectFinancialFilter,
+	) ([]ProjectFinancialEntry, error)
 }
 
 type ProjectService struct {
-	repo ProjectRepository
+	repo     ProjectRepository
+	notifier Notifier
 }
 
-func NewProjectService(repo ProjectRepository) *ProjectService {
-	return &ProjectService{repo: repo}
+func NewProjectService(repo ProjectRepository, notifier Notifier) *ProjectService {
+	return &ProjectService{repo: repo, notifier: notifier}
 }
 
 type ProjectListFilter struct {
@@ -128,6 +152,7 @@ type ProjectType struct {
 	Code         string    `json:"code"`
 	Name         string    `json:"name"`
 	Description  string    `json:"description"`
+	IssRate      float64   `json:"issRate"`
 	Active       bool      `json:"active"`
 	Created      time.Time `json:"created"`
 	Updated      time.Time `json:"updated"`
@@ -197,6 +222,7 @@ type ProjectMonthlyCharge struct {
 	DueDay      int        `json:"dueDay"`
 	StartsOn    *time.Time `json:"startsOn,omitempty"`
 	EndsOn      *time.Time `json:"endsOn,omitempty"`
+	ReceivedOn  *time.Time `json:"receivedOn,omitempty"`
 	Active      bool       `json:"active"`
 	Created     time.Time  `json:"created"`
 	Updated     time.Time  `json:"updated"`
@@ -231,6 +257,7 @@ type ProjectTask struct {
 	Position            int                  `json:"position"`
 	Status              string               `json:"status"`
 	Active              bool                 `json:"active"`
+	ServiceRequestID    string               `json:"serviceRequestId,omitempty"`
 	Files               []ProjectRelatedFile `json:"files"`
 	Created             time.Time            `json:"created"`
 	Updated             time.Time            `json:"updated"`
@@ -325,8 +352,9 @@ type UpdateProjectInput struct {
 }
 
 type UpdateProjectStatusInput struct {
-	ID     string
-	Status string
+	ID        string
+	Status    string
+	UpdatedBy string
 }
 
 type CreateProjectTypeInput struct {
@@ -334,6 +362,7 @@ type CreateProjectTypeInput struct {
 	Code        string
 	Name        string
 	Description string
+	IssRate     float64
 	Active      bool
 }
 
@@ -343,6 +372,7 @@ type UpdateProjectTypeInput struct {
 	Code        string
 	Name        string
 	Description string
+	IssRate     *float64
 	Active      *bool
 }
 
@@ -487,6 +517,9 @@ func (s *ProjectService) ListProjects(
 	ctx context.Context,
 	filter ProjectListFilter,
 ) ([]ProjectListItem, error) {
+	ctx, span := startSpan(ctx, "ProjectService.ListProjects")
+	defer span.End()
+
 	normalizedFilter := ProjectListFilter{
 		Search:     strings.TrimSpace(filter.Search),
 		OnlyActive: filter.OnlyActive,
@@ -499,6 +532,9 @@ func (s *ProjectService) GetProjectDetail(
 	ctx context.Context,
 	projectID string,
 ) (ProjectDetail, error) {
+	ctx, span := startSpan(ctx, "ProjectService.GetProjectDetail")
+	defer span.End()
+
 	id := strings.TrimSpace(projectID)
 	if id == "" {
 		return ProjectDetail{}, ErrInvalidInput
@@ -511,6 +547,9 @@ func (s *ProjectService) CreateProject(
 	ctx context.Context,
 	input CreateProjectInput,
 ) (ProjectDetail, error) {
+	ctx, span := startSpan(ctx, "ProjectService.CreateProject")
+	defer span.End()
+
 	normalizedInput, err := normalizeCreateProjectInput(input)
 	if err != nil {
 		return ProjectDetail{}, err
@@ -523,6 +562,9 @@ func (s *ProjectService) UpdateProject(
 	ctx context.Context,
 	input UpdateProjectInput,
 ) (ProjectDetail, error) {
+	ctx, span := startSpan(ctx, "ProjectService.UpdateProject")
+	defer span.End()
+
 	normalizedInput, err := normalizeUpdateProjectInput(input)
 	if err != nil {
 		return ProjectDetail{}, err
@@ -535,18 +577,42 @@ func (s *ProjectService) UpdateProjectStatus(
 	ctx context.Context,
 	input UpdateProjectStatusInput,
 ) (ProjectDetail, error) {
+	ctx, span := startSpan(ctx, "ProjectService.UpdateProjectStatus")
+	defer span.End()
+
 	normalizedInput, err := normalizeUpdateProjectStatusInput(input)
 	if err != nil {
 		return ProjectDetail{}, err
 	}
 
-	return s.repo.UpdateProjectStatus(ctx, normalizedInput)
+	project, err := s.repo.UpdateProjectStatus(ctx, normalizedInput)
+	if err != nil {
+		return ProjectDetail{}, err
+	}
+
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, NotificationEvent{
+			Type:        NotificationTypeProjectStatus,
+			Title:       "Projeto " + project.Name + ": " + formatProjectStatusLabel(project.Status),
+			ProjectID:   project.ID,
+			ActorUserID: normalizedInput.UpdatedBy,
+			Audience: NotificationAudience{
+				ProjectManagersOf: project.ID,
+				ProjectClientsOf:  project.ID,
+			},
+		})
+	}
+
+	return project, nil
 }
 
 func (s *ProjectService) DeleteProject(
 	ctx context.Context,
 	projectID string,
 ) error {
+	ctx, span := startSpan(ctx, "ProjectService.DeleteProject")
+	defer span.End()
+
 	id := strings.TrimSpace(projectID)
 	if id == "" {
 		return ErrInvalidInput
@@ -558,6 +624,9 @@ func (s *ProjectService) DeleteProject(
 func (s *ProjectService) ListProjectCategories(
 	ctx context.Context,
 ) ([]ProjectCategory, error) {
+	ctx, span := startSpan(ctx, "ProjectService.ListProjectCategories")
+	defer span.End()
+
 	return s.repo.ListProjectCategories(ctx)
 }
 
@@ -565,6 +634,9 @@ func (s *ProjectService) ListProjectTypes(
 	ctx context.Context,
 	filter ProjectTypeListFilter,
 ) ([]ProjectType, error) {
+	ctx, span := startSpan(ctx, "ProjectService.ListProjectTypes")
+	defer span.End()
+
 	normalizedFilter := ProjectTypeListFilter{
 		CategoryID: strings.TrimSpace(filter.CategoryID),
 		OnlyActive: filter.OnlyActive,
@@ -577,16 +649,21 @@ func (s *ProjectService) CreateProjectType(
 	ctx context.Context,
 	input CreateProjectTypeInput,
 ) (ProjectType, error) {
+	ctx, span := startSpan(ctx, "ProjectService.CreateProjectType")
+	defer span.End()
+
 	normalizedInput := CreateProjectTypeInput{
 		CategoryID:  strings.TrimSpace(input.CategoryID),
 		Code:        strings.ToLower(strings.TrimSpace(input.Code)),
 		Name:        strings.TrimSpace(input.Name),
 		Description: strings.TrimSpace(input.Description),
+		IssRate:     input.IssRate,
 		Active:      input.Active,
 	}
 	if normalizedInput.CategoryID == "" ||
 		normalizedInput.Code == "" ||
-		normalizedInput.Name == "" {
+		normalizedInput.Name == "" ||
+		!isValidIssRate(normalizedInput.IssRate) {
 		return ProjectType{}, ErrInvalidInput
 	}
 
@@ -597,12 +674,16 @@ func (s *ProjectService) UpdateProjectType(
 	ctx context.Context,
 	input UpdateProjectTypeInput,
 ) (ProjectType, error) {
+	ctx, span := startSpan(ctx, "ProjectService.UpdateProjectType")
+	defer span.End()
+
 	normalizedInput := UpdateProjectTypeInput{
 		ID:          strings.TrimSpace(input.ID),
 		CategoryID:  strings.TrimSpace(input.CategoryID),
 		Code:        strings.ToLower(strings.TrimSpace(input.Code)),
 		Name:        strings.TrimSpace(input.Name),
 		Description: strings.TrimSpace(input.Description),
+		IssRate:     input.IssRate,
 		Active:      input.Active,
 	}
 	if normalizedInput.ID == "" ||
@@ -611,14 +692,25 @@ func (s *ProjectService) UpdateProjectType(
 		normalizedInput.Name == "" {
 		return ProjectType{}, ErrInvalidInput
 	}
+	if normalizedInput.IssRate != nil && !isValidIssRate(*normalizedInput.IssRate) {
+		return ProjectType{}, ErrInvalidInput
+	}
 
 	return s.repo.UpdateProjectType(ctx, normalizedInput)
 }
 
+// isValidIssRate accepts municipal ISS rates expressed as a percentage.
+func isValidIssRate(rate float64) bool {
+	return rate >= 0 && rate <= 100
+}
+
 func (s *ProjectService) ListProjectRevenues(
 	ctx context.Context,
 	projectID string,
 ) ([]ProjectRevenue, error) {
+	ctx, span := startSpan(ctx, "ProjectService.ListProjectRevenues")
+	defer span.End()
+
 	id := strings.TrimSpace(projectID)
 	if id == "" {
 		return nil, ErrInvalidInput
@@ -631,6 +723,9 @@ func (s *ProjectService) CreateProjectRevenue(
 	ctx context.Context,
 	input CreateProjectRevenueInput,
 ) (ProjectRevenue, error) {
+	ctx, span := startSpan(ctx, "ProjectService.CreateProjectRevenue")
+	defer span.End()
+
 	normalizedInput, err := normalizeCreateProjectRevenueInput(input)
 	if err != nil {
 		return ProjectRevenue{}, err
@@ -643,6 +738,9 @@ func (s *ProjectService) UpdateProjectRevenueStatus(
 	ctx context.Context,
 	input UpdateProjectRevenueStatusInput,
 ) error {
+	ctx, span := startSpan(ctx, "ProjectService.UpdateProjectRevenueStatus")
+	defer span.End()
+
 	normalizedInput, err := normalizeUpdateProjectRevenueStatusInput(input)
 	if err != nil {
 		return err
@@ -655,6 +753,9 @@ func (s *ProjectService) ListProjectMonthlyCharges(
 	ctx context.Context,
 	projectID string,
 ) ([]ProjectMonthlyCharge, error) {
+	ctx, span := startSpan(ctx, "ProjectService.ListProjectMonthlyCharges")
+	defer span.End()
+
 	id := strings.TrimSpace(projectID)
 	if id == "" {
 		return nil, ErrInvalidInput
@@ -667,6 +768,9 @@ func (s *ProjectService) CreateProjectMonthlyCharge(
 	ctx context.Context,
 	input CreateProjectMonthlyChargeInput,
 ) (ProjectMonthlyCharge, error) {
+	ctx, span := startSpan(ctx, "ProjectService.CreateProjectMonthlyCharge")
+	defer span.End()
+
 	normalizedInput, err := normalizeCreateProjectMonthlyChargeInput(input)
 	if err != nil {
 		return ProjectMonthlyCharge{}, err
@@ -679,6 +783,9 @@ func (s *ProjectService) UpdateProjectMonthlyCharge(
 	ctx context.Context,
 	input UpdateProjectMonthlyChargeInput,
 ) (ProjectMonthlyCharge, error) {
+	ctx, span := startSpan(ctx, "ProjectService.UpdateProjectMonthlyCharge")
+	defer span.End()
+
 	normalizedInput, err := normalizeUpdateProjectMonthlyChargeInput(input)
 	if err != nil {
 		return ProjectMonthlyCharge{}, err
@@ -691,6 +798,9 @@ func (s *ProjectService) UpdateProjectMonthlyChargeStatus(
 	ctx context.Context,
 	input UpdateProjectMonthlyChargeStatusInput,
 ) (ProjectMonthlyCharge, error) {
+	ctx, span := startSpan(ctx, "ProjectService.UpdateProjectMonthlyChargeStatus")
+	defer span.End()
+
 	normalizedInput, err := normalizeUpdateProjectMonthlyChargeStatusInput(input)
 	if err != nil {
 		return ProjectMonthlyCharge{}, err
@@ -703,6 +813,9 @@ func (s *ProjectService) UpdateProjectMonthlyChargeAmount(
 	ctx context.Context,
 	input UpdateProjectMonthlyChargeAmountInput,
 ) (ProjectMonthlyCharge, error) {
+	ctx, span := startSpan(ctx, "ProjectService.UpdateProjectMonthlyChargeAmount")
+	defer span.End()
+
 	normalizedInput, err := normalizeUpdateProjectMonthlyChargeAmountInput(input)
 	if err != nil {
 		return ProjectMonthlyCharge{}, err
@@ -716,6 +829,9 @@ func (s *ProjectService) DeleteProjectMonthlyCharge(
 	projectID string,
 	monthlyChargeID string,
 ) error {
+	ctx, span := startSpan(ctx, "ProjectService.DeleteProjectMonthlyCharge")
+	defer span.End()
+
 	normalizedProjectID := strings.TrimSpace(projectID)
 	normalizedMonthlyChargeID := strings.TrimSpace(monthlyChargeID)
 	if normalizedProjectID == "" || normalizedMonthlyChargeID == "" {
@@ -729,6 +845,9 @@ func (s *ProjectService) ListProjectPhases(
 	ctx context.Context,
 	projectID string,
 ) ([]ProjectPhase, error) {
+	ctx, span := startSpan(ctx, "ProjectService.ListProjectPhases")
+	defer span.End()
+
 	id := strings.TrimSpace(projectID)
 	if id == "" {
 		return nil, ErrInvalidInput
@@ -741,6 +860,9 @@ func (s *ProjectService) CreateProjectPhase(
 	ctx context.Context,
 	input CreateProjectPhaseInput,
 ) (ProjectPhase, error) {
+	ctx, span := startSpan(ctx, "ProjectService.CreateProjectPhase")
+	defer span.End()
+
 	normalizedInput, err := normalizeCreateProjectPhaseInput(input)
 	if err != nil {
 		return ProjectPhase{}, err
@@ -753,6 +875,9 @@ func (s *ProjectService) UpdateProjectPhase(
 	ctx context.Context,
 	input UpdateProjectPhaseInput,
 ) (ProjectPhase, error) {
+	ctx, span := startSpan(ctx, "ProjectService.UpdateProjectPhase")
+	defer span.End()
+
 	normalizedInput, err := normalizeUpdateProjectPhaseInput(input)
 	if err != nil {
 		return ProjectPhase{}, err
@@ -765,6 +890,9 @@ func (s *ProjectService) ListProjectTasks(
 	ctx context.Context,
 	projectID string,
 ) ([]ProjectTask, error) {
+	ctx, span := startSpan(ctx, "ProjectService.ListProjectTasks")
+	defer span.End()
+
 	id := strings.TrimSpace(projectID)
 	if id == "" {
 		return nil, ErrInvalidInput
@@ -777,6 +905,9 @@ func (s *ProjectService) CreateProjectTask(
 	ctx context.Context,
 	input CreateProjectTaskInput,
 ) (ProjectTask, error) {
+	ctx, span := startSpan(ctx, "ProjectService.CreateProjectTask")
+	defer span.End()
+
 	normalizedInput, err := normalizeCreateProjectTaskInput(input)
 	if err != nil {
 		return ProjectTask{}, err
@@ -789,6 +920,9 @@ func (s *ProjectService) UpdateProjectTask(
 	ctx context.Context,
 	input UpdateProjectTaskInput,
 ) (ProjectTask, error) {
+	ctx, span := startSpan(ctx, "ProjectService.UpdateProjectTask")
+	defer span.End()
+
 	normalizedInput, err := normalizeUpdateProjectTaskInput(input)
 	if err != nil {
 		return ProjectTask{}, err
@@ -802,6 +936,9 @@ func (s *ProjectService) ListProjectTaskComments(
 	projectID string,
 	taskID string,
 ) ([]ProjectTaskComment, error) {
+	ctx, span := startSpan(ctx, "ProjectService.ListProjectTaskComments")
+	defer span.End()
+
 	normalizedProjectID := strings.TrimSpace(projectID)
 	normalizedTaskID := strings.TrimSpace(taskID)
 	if normalizedProjectID == "" || normalizedTaskID == "" {
@@ -815,18 +952,68 @@ func (s *ProjectService) CreateProjectTaskComment(
 	ctx context.Context,
 	input CreateProjectTaskCommentInput,
 ) (ProjectTaskComment, error) {
+	ctx, span := startSpan(ctx, "ProjectService.CreateProjectTaskComment")
+	defer span.End()
+
 	normalizedInput, err := normalizeCreateProjectTaskCommentInput(input)
 	if err != nil {
 		return ProjectTaskComment{}, err
 	}
 
-	return s.repo.CreateProjectTaskComment(ctx, normalizedInput)
+	comment, err := s.repo.CreateProjectTaskComment(ctx, normalizedInput)
+	if err != nil {
+		return ProjectTaskComment{}, err
+	}
+
+	s.notifyProjectTaskComment(ctx, normalizedInput.ProjectID, comment)
+
+	return comment, nil
+}
+
+// notifyProjectTaskComment tells the project's managers and clients and the
+// task responsible about a new comment; the author is never notified.
+func (s *ProjectService) notifyProjectTaskComment(
+	ctx context.Context,
+	projectID string,
+	comment ProjectTaskComment,
+) {
+	if s.notifier == nil {
+		return
+	}
+
+	taskName := "tarefa"
+	if tasks, err := s.repo.ListProjectTasks(ctx, projectID); err == nil {
+		for _, task := range tasks {
+			if task.ID == comment.ProjectTaskID {
+				taskName = task.Name
+				break
+			}
+		}
+	}
+
+	s.notifier.Notify(ctx, NotificationEvent{
+		Type:          NotificationTypeProjectTaskComment,
+		Title:         comment.AuthorName + " comentou em " + taskName,
+		Message:       comment.Comment,
+		ProjectID:     projectID,
+		ProjectTaskID: comment.ProjectTaskID,
+		ActorUserID:   comment.UserID,
+		ActorClientID: comment.ClientID,
+		Audience: NotificationAudience{
+			ProjectManagersOf: projectID,
+			ProjectClientsOf:  projectID,
+			TaskResponsibleOf: comment.ProjectTaskID,
+		},
+	})
 }
 
 func (s *ProjectService) RecalculateProjectTimeline(
 	ctx context.Context,
 	projectID string,
 ) (ProjectDetail, error) {
+	ctx, span := startSpan(ctx, "ProjectService.RecalculateProjectTimeline")
+	defer span.End()
+
 	id := strings.TrimSpace(projectID)
 	if id == "" {
 		return ProjectDetail{}, ErrInvalidInput
@@ -904,8 +1091,9 @@ func normalizeUpdateProjectStatusInput(
 	input UpdateProjectStatusInput,
 ) (UpdateProjectStatusInput, error) {
 	normalizedInput := UpdateProjectStatusInput{
-		ID:     strings.TrimSpace(input.ID),
-		Status: normalizeProjectStatus(input.Status),
+		ID:        strings.TrimSpace(input.ID),
+		Status:    normalizeProjectStatus(input.Status),
+		UpdatedBy: strings.TrimSpace(input.UpdatedBy),
 	}
 	if normalizedInput.ID == "" || normalizedInput.Status == "" {
 		return UpdateProjectStatusInput{}, ErrInvalidInput
@@ -51,6 +51,9 @@ func (s *ProjectService) ExportProject(
 	ctx context.Context,
 	projectID string,
 ) (ProjectExport, error) {
+	ctx, span := startSpan(ctx, "ProjectService.ExportProject")
+	defer span.End()
+
 	project, err := s.GetProjectDetail(ctx, projectID)
 	if err != nil {
 		return ProjectExport{}, err
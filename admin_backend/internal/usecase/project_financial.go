@@ -0,0 +1,666 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	projectFinancialEntryRevenue       = "revenue"
+	projectFinancialEntryMonthlyCharge = "monthly_charge"
+	projectFinancialEntryExpense       = "expense"
+	projectFinancialEntryLabor         = "labor"
+
+	projectFinancialMonthLayout      = "2006-01"
+	projectFinancialDefaultMonthSpan = 12
+)
+
+type ProjectExpenseCategory struct {
+	ID          string    `json:"id"`
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Active      bool      `json:"active"`
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+}
+
+type ProjectExpenseReceipt struct {
+	ID               string     `json:"id"`
+	ProjectExpenseID string     `json:"projectExpenseId"`
+	FileName         string     `json:"fileName"`
+	FileKey          string     `json:"fileKey"`
+	ContentType      string     `json:"contentType"`
+	IssuedOn         *time.Time `json:"issuedOn,omitempty"`
+	Notes            string     `json:"notes"`
+	Created          time.Time  `json:"created"`
+	Updated          time.Time  `json:"updated"`
+}
+
+type ProjectExpense struct {
+	ID           string                  `json:"id"`
+	ProjectID    string                  `json:"projectId"`
+	CategoryID   string                  `json:"categoryId"`
+	CategoryCode string                  `json:"categoryCode"`
+	CategoryName string                  `json:"categoryName"`
+	Title        string                  `json:"title"`
+	Description  string                  `json:"description"`
+	Supplier     string                  `json:"supplier"`
+	Amount       float64                 `json:"amount"`
+	IncurredOn   time.Time               `json:"incurredOn"`
+	Active       bool                    `json:"active"`
+	Receipts     []ProjectExpenseReceipt `json:"receipts"`
+	Created      time.Time               `json:"created"`
+	Updated      time.Time               `json:"updated"`
+}
+
+type UserHourlyRate struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"userId"`
+	UserName   string    `json:"userName"`
+	HourlyCost float64   `json:"hourlyCost"`
+	ValidFrom  time.Time `json:"validFrom"`
+	Notes      string    `json:"notes"`
+	Created    time.Time `json:"created"`
+	Updated    time.Time `json:"updated"`
+}
+
+type ProjectTimeEntry struct {
+	ID              string    `json:"id"`
+	ProjectID       string    `json:"projectId"`
+	ProjectTaskID   string    `json:"projectTaskId,omitempty"`
+	ProjectTaskName string    `json:"projectTaskName,omitempty"`
+	UserID          string    `json:"userId"`
+	UserName        string    `json:"userName"`
+	WorkedOn        time.Time `json:"workedOn"`
+	Hours           float64   `json:"hours"`
+	HourlyCost      float64   `json:"hourlyCost"`
+	Cost            float64   `json:"cost"`
+	Description     string    `json:"description"`
+	Created         time.Time `json:"created"`
+	Updated         time.Time `json:"updated"`
+}
+
+// ProjectFinancialEntry is a single dated money movement used to build
+// profitability reports. Labor entries carry the hours worked and their cost
+// already priced with the rate in force on the day the work happened.
+type ProjectFinancialEntry struct {
+	ProjectID   string
+	ProjectName string
+	Kind        string
+	OccurredOn  time.Time
+	Amount      float64
+	Hours       float64
+}
+
+type ProjectFinancialFilter struct {
+	ProjectID string
+	From      time.Time
+	To        time.Time
+}
+
+type ProjectFinancialMonth struct {
+	Month         string  `json:"month"`
+	Revenue       float64 `json:"revenue"`
+	ExpenseCost   float64 `json:"expenseCost"`
+	LaborCost     float64 `json:"laborCost"`
+	LaborHours    float64 `json:"laborHours"`
+	TotalCost     float64 `json:"totalCost"`
+	Margin        float64 `json:"margin"`
+	MarginPercent float64 `json:"marginPercent"`
+}
+
+type ProjectFinancialSummary struct {
+	ProjectID   string                  `json:"projectId,omitempty"`
+	ProjectName string                  `json:"projectName,omitempty"`
+	From        string                  `json:"from"`
+	To          string                  `json:"to"`
+	Totals      ProjectFinancialMonth   `json:"totals"`
+	Months      []ProjectFinancialMonth `json:"months"`
+}
+
+type ProjectFinancialReport struct {
+	From     string                    `json:"from"`
+	To       string                    `json:"to"`
+	Totals   ProjectFinancialMonth     `json:"totals"`
+	Months   []ProjectFinancialMonth   `json:"months"`
+	Projects []ProjectFinancialSummary `json:"projects"`
+}
+
+type CreateProjectExpenseCategoryInput struct {
+	Code        string
+	Name        string
+	Description string
+	Active      bool
+}
+
+type CreateProjectExpenseReceiptInput struct {
+	FileName    string
+	FileKey     string
+	ContentType string
+	IssuedOn    *time.Time
+	Notes       string
+}
+
+type CreateProjectExpenseInput struct {
+	ProjectID   string
+	CategoryID  string
+	Title       string
+	Description string
+	Supplier    string
+	Amount      float64
+	IncurredOn  *time.Time
+	Active      bool
+	Receipts    []CreateProjectExpenseReceiptInput
+}
+
+type UpdateProjectExpenseInput struct {
+	ID          string
+	ProjectID   string
+	CategoryID  string
+	Title       string
+	Description string
+	Supplier    string
+	Amount      float64
+	IncurredOn  *time.Time
+	Active      bool
+	Receipts    *[]CreateProjectExpenseReceiptInput
+}
+
+type CreateUserHourlyRateInput struct {
+	UserID     string
+	HourlyCost float64
+	ValidFrom  *time.Time
+	Notes      string
+}
+
+type CreateProjectTimeEntryInput struct {
+	ProjectID     string
+	ProjectTaskID string
+	UserID        string
+	WorkedOn      *time.Time
+	Hours         float64
+	Description   string
+}
+
+func (s *ProjectService) ListProjectExpenseCategories(
+	ctx context.Context,
+) ([]ProjectExpenseCategory, error) {
+	ctx, span := startSpan(ctx, "ProjectService.ListProjectExpenseCategories")
+	defer span.End()
+
+	return s.repo.ListProjectExpenseCategories(ctx)
+}
+
+func (s *ProjectService) CreateProjectExpenseCategory(
+	ctx context.Context,
+	input CreateProjectExpenseCategoryInput,
+) (ProjectExpenseCategory, error) {
+	ctx, span := startSpan(ctx, "ProjectService.CreateProjectExpenseCategory")
+	defer span.End()
+
+	normalizedInput := CreateProjectExpenseCategoryInput{
+		Code:        strings.ToLower(strings.TrimSpace(input.Code)),
+		Name:        strings.TrimSpace(input.Name),
+		Description: strings.TrimSpace(input.Description),
+		Active:      input.Active,
+	}
+	if normalizedInput.Code == "" || normalizedInput.Name == "" {
+		return ProjectExpenseCategory{}, ErrInvalidInput
+	}
+
+	return s.repo.CreateProjectExpenseCategory(ctx, normalizedInput)
+}
+
+func (s *ProjectService) ListProjectExpenses(
+	ctx context.Context,
+	projectID string,
+) ([]ProjectExpense, error) {
+	ctx, span := startSpan(ctx, "ProjectService.ListProjectExpenses")
+	defer span.End()
+
+	id := strings.TrimSpace(projectID)
+	if id == "" {
+		return nil, ErrInvalidInput
+	}
+
+	return s.repo.ListProjectExpenses(ctx, id)
+}
+
+func (s *ProjectService) CreateProjectExpense(
+	ctx context.Context,
+	input CreateProjectExpenseInput,
+) (ProjectExpense, error) {
+	ctx, span := startSpan(ctx, "ProjectService.CreateProjectExpense")
+	defer span.End()
+
+	normalizedInput := CreateProjectExpenseInput{
+		ProjectID:   strings.TrimSpace(input.ProjectID),
+		CategoryID:  strings.TrimSpace(input.CategoryID),
+		Title:       strings.TrimSpace(input.Title),
+		Description: strings.TrimSpace(input.Description),
+		Supplier:    strings.TrimSpace(input.Supplier),
+		Amount:      input.Amount,
+		IncurredOn:  input.IncurredOn,
+		Active:      input.Active,
+		Receipts:    normalizeProjectExpenseReceiptInputs(input.Receipts),
+	}
+	if normalizedInput.ProjectID == "" ||
+		normalizedInput.CategoryID == "" ||
+		normalizedInput.Title == "" ||
+		normalizedInput.IncurredOn == nil {
+		return ProjectExpense{}, ErrInvalidInput
+	}
+	if normalizedInput.Amount < 0 {
+		return ProjectExpense{}, ErrInvalidInput
+	}
+
+	return s.repo.CreateProjectExpense(ctx, normalizedInput)
+}
+
+func (s *ProjectService) UpdateProjectExpense(
+	ctx context.Context,
+	input UpdateProjectExpenseInput,
+) (ProjectExpense, error) {
+	ctx, span := startSpan(ctx, "ProjectService.UpdateProjectExpense")
+	defer span.End()
+
+	normalizedInput := UpdateProjectExpenseInput{
+		ID:          strings.TrimSpace(input.ID),
+		ProjectID:   strings.TrimSpace(input.ProjectID),
+		CategoryID:  strings.TrimSpace(input.CategoryID),
+		Title:       strings.TrimSpace(input.Title),
+		Description: strings.TrimSpace(input.Description),
+		Supplier:    strings.TrimSpace(input.Supplier),
+		Amount:      input.Amount,
+		IncurredOn:  input.IncurredOn,
+		Active:      input.Active,
+	}
+	if input.Receipts != nil {
+		receipts := normalizeProjectExpenseReceiptInputs(*input.Receipts)
+		normalizedInput.Receipts = &receipts
+	}
+	if normalizedInput.ID == "" ||
+		normalizedInput.ProjectID == "" ||
+		normalizedInput.CategoryID == "" ||
+		normalizedInput.Title == "" ||
+		normalizedInput.IncurredOn == nil {
+		return ProjectExpense{}, ErrInvalidInput
+	}
+	if normalizedInput.Amount < 0 {
+		return ProjectExpense{}, ErrInvalidInput
+	}
+
+	return s.repo.UpdateProjectExpense(ctx, normalizedInput)
+}
+
+func (s *ProjectService) DeleteProjectExpense(
+	ctx context.Context,
+	projectID string,
+	expenseID string,
+) error {
+	ctx, span := startSpan(ctx, "ProjectService.DeleteProjectExpense")
+	defer span.End()
+
+	normalizedProjectID := strings.TrimSpace(projectID)
+	normalizedExpenseID := strings.TrimSpace(expenseID)
+	if normalizedProjectID == "" || normalizedExpenseID == "" {
+		return ErrInvalidInput
+	}
+
+	return s.repo.DeleteProjectExpense(ctx, normalizedProjectID, normalizedExpenseID)
+}
+
+func (s *ProjectService) ListUserHourlyRates(
+	ctx context.Context,
+	userID string,
+) ([]UserHourlyRate, error) {
+	ctx, span := startSpan(ctx, "ProjectService.ListUserHourlyRates")
+	defer span.End()
+
+	return s.repo.ListUserHourlyRates(ctx, strings.TrimSpace(userID))
+}
+
+func (s *ProjectService) CreateUserHourlyRate(
+	ctx context.Context,
+	input CreateUserHourlyRateInput,
+) (UserHourlyRate, error) {
+	ctx, span := startSpan(ctx, "ProjectService.CreateUserHourlyRate")
+	defer span.End()
+
+	normalizedInput := CreateUserHourlyRateInput{
+		UserID:     strings.TrimSpace(input.UserID),
+		HourlyCost: input.HourlyCost,
+		ValidFrom:  input.ValidFrom,
+		Notes:      strings.TrimSpace(input.Notes),
+	}
+	if normalizedInput.UserID == "" || normalizedInput.ValidFrom == nil {
+		return UserHourlyRate{}, ErrInvalidInput
+	}
+	if normalizedInput.HourlyCost < 0 {
+		return UserHourlyRate{}, ErrInvalidInput
+	}
+
+	return s.repo.CreateUserHourlyRate(ctx, normalizedInput)
+}
+
+func (s *ProjectService) DeleteUserHourlyRate(
+	ctx context.Context,
+	rateID string,
+) error {
+	ctx, span := startSpan(ctx, "ProjectService.DeleteUserHourlyRate")
+	defer span.End()
+
+	id := strings.TrimSpace(rateID)
+	if id == "" {
+		return ErrInvalidInput
+	}
+
+	return s.repo.DeleteUserHourlyRate(ctx, id)
+}
+
+func (s *ProjectService) ListProjectTimeEntries(
+	ctx context.Context,
+	projectID string,
+) ([]ProjectTimeEntry, error) {
+	ctx, span := startSpan(ctx, "ProjectService.ListProjectTimeEntries")
+	defer span.End()
+
+	id := strings.TrimSpace(projectID)
+	if id == "" {
+		return nil, ErrInvalidInput
+	}
+
+	return s.repo.ListProjectTimeEntries(ctx, id)
+}
+
+func (s *ProjectService) CreateProjectTimeEntry(
+	ctx context.Context,
+	input CreateProjectTimeEntryInput,
+) (ProjectTimeEntry, error) {
+	ctx, span := startSpan(ctx, "ProjectService.CreateProjectTimeEntry")
+	defer span.End()
+
+	normalizedInput := CreateProjectTimeEntryInput{
+		ProjectID:     strings.TrimSpace(input.ProjectID),
+		ProjectTaskID: strings.TrimSpace(input.ProjectTaskID),
+		UserID:        strings.TrimSpace(input.UserID),
+		WorkedOn:      input.WorkedOn,
+		Hours:         input.Hours,
+		Description:   strings.TrimSpace(input.Description),
+	}
+	if normalizedInput.ProjectID == "" ||
+		normalizedInput.UserID == "" ||
+		normalizedInput.WorkedOn == nil {
+		return ProjectTimeEntry{}, ErrInvalidInput
+	}
+	if normalizedInput.Hours <= 0 || normalizedInput.Hours > 24 {
+		return ProjectTimeEntry{}, ErrInvalidInput
+	}
+
+	return s.repo.CreateProjectTimeEntry(ctx, normalizedInput)
+}
+
+func (s *ProjectService) DeleteProjectTimeEntry(
+	ctx context.Context,
+	projectID string,
+	entryID string,
+) error {
+	ctx, span := startSpan(ctx, "ProjectService.DeleteProjectTimeEntry")
+	defer span.End()
+
+	normalizedProjectID := strings.TrimSpace(projectID)
+	normalizedEntryID := strings.TrimSpace(entryID)
+	if normalizedProjectID == "" || normalizedEntryID == "" {
+		return ErrInvalidInput
+	}
+
+	return s.repo.DeleteProjectTimeEntry(ctx, normalizedProjectID, normalizedEntryID)
+}
+
+func (s *ProjectService) GetProjectFinancialSummary(
+	ctx context.Context,
+	filter ProjectFinancialFilter,
+) (ProjectFinancialSummary, error) {
+	ctx, span := startSpan(ctx, "ProjectService.GetProjectFinancialSummary")
+	defer span.End()
+
+	normalizedFilter, err := normalizeProjectFinancialFilter(filter, time.Now())
+	if err != nil {
+		return ProjectFinancialSummary{}, err
+	}
+	if normalizedFilter.ProjectID == "" {
+		return ProjectFinancialSummary{}, ErrInvalidInput
+	}
+
+	project, err := s.repo.GetProjectDetail(ctx, normalizedFilter.ProjectID)
+	if err != nil {
+		return ProjectFinancialSummary{}, err
+	}
+
+	entries, err := s.repo.ListProjectFinancialEntries(ctx, normalizedFilter)
+	if err != nil {
+		return ProjectFinancialSummary{}, err
+	}
+
+	summary := buildProjectFinancialSummary(entries, normalizedFilter)
+	summary.ProjectID = project.ID
+	summary.ProjectName = project.Name
+	return summary, nil
+}
+
+func (s *ProjectService) GetProjectFinancialReport(
+	ctx context.Context,
+	filter ProjectFinancialFilter,
+) (ProjectFinancialReport, error) {
+	ctx, span := startSpan(ctx, "ProjectService.GetProjectFinancialReport")
+	defer span.End()
+
+	normalizedFilter, err := normalizeProjectFinancialFilter(filter, time.Now())
+	if err != nil {
+		return ProjectFinancialReport{}, err
+	}
+
+	entries, err := s.repo.ListProjectFinancialEntries(ctx, normalizedFilter)
+	if err != nil {
+		return ProjectFinancialReport{}, err
+	}
+
+	return buildProjectFinancialReport(entries, normalizedFilter), nil
+}
+
+// normalizeProjectFinancialFilter snaps the period to whole calendar months.
+// Without bounds the report covers the trailing twelve months up to now.
+func normalizeProjectFinancialFilter(
+	filter ProjectFinancialFilter,
+	now time.Time,
+) (ProjectFinancialFilter, error) {
+	normalizedFilter := ProjectFinancialFilter{
+		ProjectID: strings.TrimSpace(filter.ProjectID),
+		From:      filter.From,
+		To:        filter.To,
+	}
+
+	if normalizedFilter.To.IsZero() {
+		normalizedFilter.To = now
+	}
+	normalizedFilter.To = startOfProjectFinancialMonth(normalizedFilter.To)
+	if normalizedFilter.From.IsZero() {
+		normalizedFilter.From = normalizedFilter.To.AddDate(0, -(projectFinancialDefaultMonthSpan - 1), 0)
+	}
+	normalizedFilter.From = startOfProjectFinancialMonth(normalizedFilter.From)
+
+	if normalizedFilter.To.Before(normalizedFilter.From) {
+		return ProjectFinancialFilter{}, ErrInvalidInput
+	}
+	if normalizedFilter.From.AddDate(5, 0, 0).Before(normalizedFilter.To) {
+		return ProjectFinancialFilter{}, ErrInvalidInput
+	}
+
+	return normalizedFilter, nil
+}
+
+func buildProjectFinancialSummary(
+	entries []ProjectFinancialEntry,
+	filter ProjectFinancialFilter,
+) ProjectFinancialSummary {
+	months := aggregateProjectFinancialMonths(entries, filter)
+	return ProjectFinancialSummary{
+		From:   filter.From.Format(projectFinancialMonthLayout),
+		To:     filter.To.Format(projectFinancialMonthLayout),
+		Totals: sumProjectFinancialMonths(months),
+		Months: months,
+	}
+}
+
+func buildProjectFinancialReport(
+	entries []ProjectFinancialEntry,
+	filter ProjectFinancialFilter,
+) ProjectFinancialReport {
+	entriesByProjectID := make(map[string][]ProjectFinancialEntry)
+	projectNameByID := make(map[string]string)
+	for _, entry := range entries {
+		entriesByProjectID[entry.ProjectID] = append(entriesByProjectID[entry.ProjectID], entry)
+		projectNameByID[entry.ProjectID] = entry.ProjectName
+	}
+
+	projects := make([]ProjectFinancialSummary, 0, len(entriesByProjectID))
+	for projectID, projectEntries := range entriesByProjectID {
+		summary := buildProjectFinancialSummary(projectEntries, filter)
+		summary.ProjectID = projectID
+		summary.ProjectName = projectNameByID[projectID]
+		projects = append(projects, summary)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].Totals.Margin == projects[j].Totals.Margin {
+			return projects[i].ProjectName < projects[j].ProjectName
+		}
+		return projects[i].Totals.Margin > projects[j].Totals.Margin
+	})
+
+	months := aggregateProjectFinancialMonths(entries, filter)
+	return ProjectFinancialReport{
+		From:     filter.From.Format(projectFinancialMonthLayout),
+		To:       filter.To.Format(projectFinancialMonthLayout),
+		Totals:   sumProjectFinancialMonths(months),
+		Months:   months,
+		Projects: projects,
+	}
+}
+
+func aggregateProjectFinancialMonths(
+	entries []ProjectFinancialEntry,
+	filter ProjectFinancialFilter,
+) []ProjectFinancialMonth {
+	months := make([]ProjectFinancialMonth, 0)
+	indexByMonth := make(map[string]int)
+	for month := filter.From; !month.After(filter.To); month = month.AddDate(0, 1, 0) {
+		key := month.Format(projectFinancialMonthLayout)
+		indexByMonth[key] = len(months)
+		months = append(months, ProjectFinancialMonth{Month: key})
+	}
+
+	for _, entry := range entries {
+		index, ok := indexByMonth[entry.OccurredOn.Format(projectFinancialMonthLayout)]
+		if !ok {
+			continue
+		}
+
+		switch entry.Kind {
+		case projectFinancialEntryRevenue, projectFinancialEntryMonthlyCharge:
+			months[index].Revenue += entry.Amount
+		case projectFinancialEntryExpense:
+			months[index].ExpenseCost += entry.Amount
+		case projectFinancialEntryLabor:
+			months[index].LaborCost += entry.Amount
+			months[index].LaborHours += entry.Hours
+		}
+	}
+
+	for index := range months {
+		months[index] = finalizeProjectFinancialMonth(months[index])
+	}
+
+	return months
+}
+
+func sumProjectFinancialMonths(months []ProjectFinancialMonth) ProjectFinancialMonth {
+	totals := ProjectFinancialMonth{}
+	for _, month := range months {
+		totals.Revenue += month.Revenue
+		totals.ExpenseCost += month.ExpenseCost
+		totals.LaborCost += month.LaborCost
+		totals.LaborHours += month.LaborHours
+	}
+
+	return finalizeProjectFinancialMonth(totals)
+}
+
+func finalizeProjectFinancialMonth(month ProjectFinancialMonth) ProjectFinancialMonth {
+	month.Revenue = roundProjectMoney(month.Revenue)
+	month.ExpenseCost = roundProjectMoney(month.ExpenseCost)
+	month.LaborCost = roundProjectMoney(month.LaborCost)
+	month.LaborHours = roundProjectMoney(month.LaborHours)
+	month.TotalCost = roundProjectMoney(month.ExpenseCost + month.LaborCost)
+	month.Margin = roundProjectMoney(month.Revenue - month.TotalCost)
+	month.MarginPercent = 0
+	if month.Revenue > 0 {
+		month.MarginPercent = roundProjectMoney(month.Margin / month.Revenue * 100)
+	}
+
+	return month
+}
+
+func startOfProjectFinancialMonth(value time.Time) time.Time {
+	return time.Date(value.Year(), value.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func roundProjectMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func normalizeProjectExpenseReceiptInputs(
+	receipts []CreateProjectExpenseReceiptInput,
+) []CreateProjectExpenseReceiptInput {
+	normalized := make([]CreateProjectExpenseReceiptInput, 0, len(receipts))
+	for _, receipt := range receipts {
+		normalizedReceipt := CreateProjectExpenseReceiptInput{
+			FileName:    strings.TrimSpace(receipt.FileName),
+			FileKey:     strings.TrimSpace(receipt.FileKey),
+			ContentType: strings.TrimSpace(receipt.ContentType),
+			IssuedOn:    receipt.IssuedOn,
+			Notes:       strings.TrimSpace(receipt.Notes),
+		}
+		if normalizedReceipt.FileName == "" && normalizedReceipt.FileKey == "" {
+			continue
+		}
+		normalized = append(normalized, normalizedReceipt)
+	}
+
+	return normalized
+}
+
+// ProjectMonthlyChargeDueDate resolves the calendar due date of a charge
+// installment: the DueDay of the month the installment starts in, clamped to
+// the last day of that month. Installments without StartsOn fall back to the
+// month they were created.
+func ProjectMonthlyChargeDueDate(charge ProjectMonthlyCharge) time.Time {
+	reference := charge.Created
+	if charge.StartsOn != nil {
+		reference = *charge.StartsOn
+	}
+
+	lastDay := time.Date(reference.Year(), reference.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	day := charge.DueDay
+	if day < 1 {
+		day = 1
+	}
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return time.Date(reference.Year(), reference.Month(), day, 0, 0, 0, 0, time.UTC)
+}
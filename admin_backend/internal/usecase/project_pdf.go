@@ -42,6 +42,9 @@ func (s *ProjectService) ExportProjectPDF(
 	projectID string,
 	style ProjectPDFStyle,
 ) ([]byte, error) {
+	ctx, span := startSpan(ctx, "ProjectService.ExportProjectPDF")
+	defer span.End()
+
 	exportPayload, err := s.ExportProject(ctx, projectID)
 	if err != nil {
 		return nil, err
@@ -402,6 +405,8 @@ func isProjectPDFSectionHeading(line string) bool {
 	switch strings.ToUpper(strings.TrimSpace(line)) {
 	case "RESUMO", "CLIENTES", "RECEITAS", "COBRANCAS MENSAIS", "PLANEJAMENTO (FASES, SUB-FASES E TAREFAS)":
 		return true
+	case "TOMADOR DO SERVICO", "DISCRIMINACAO DOS SERVICOS", "VALORES", "OBSERVACOES":
+		return true
 	default:
 		return false
 	}
@@ -0,0 +1,90 @@
+package usecase
+
+import "strings"
+
+const (
+	// RealtimeEventResync is sent when events may have been missed, e.g.
+	// after the database connection was re-established. Frontends should
+	// reload what they display.
+	RealtimeEventResync = "stream.resync"
+)
+
+// RealtimeEvent is a change published by the database triggers. It only
+// carries identifiers; clients fetch the changed resources themselves.
+type RealtimeEvent struct {
+	Type              string `json:"type"`
+	ID                string `json:"id,omitempty"`
+	ProjectID         string `json:"projectId,omitempty"`
+	ProjectTaskID     string `json:"projectTaskId,omitempty"`
+	ServiceRequestID  string `json:"serviceRequestId,omitempty"`
+	ClientID          string `json:"clientId,omitempty"`
+	RecipientUserID   string `json:"recipientUserId,omitempty"`
+	RecipientClientID string `json:"recipientClientId,omitempty"`
+}
+
+// RealtimeEventSource fans events out to subscribers. The returned function
+// cancels the subscription and must be called once the stream ends.
+type RealtimeEventSource interface {
+	Subscribe() (<-chan RealtimeEvent, func())
+}
+
+// RealtimeSubscription decides which events a connected session may see.
+type RealtimeSubscription struct {
+	userID          string
+	clientID        string
+	canReadProjects bool
+	projectIDs      map[string]struct{}
+}
+
+// NewAdminRealtimeSubscription sees service request events, its own
+// notifications and, when canReadProjects is set, project, task and task
+// comment events.
+func NewAdminRealtimeSubscription(userID string, canReadProjects bool) RealtimeSubscription {
+	return RealtimeSubscription{
+		userID:          strings.TrimSpace(userID),
+		canReadProjects: canReadProjects,
+	}
+}
+
+// NewClientRealtimeSubscription sees its own service requests and
+// notifications and the events of the projects it is linked to.
+func NewClientRealtimeSubscription(clientID string, projects []ClientPortalProject) RealtimeSubscription {
+	subscription := RealtimeSubscription{
+		clientID:   strings.TrimSpace(clientID),
+		projectIDs: make(map[string]struct{}, len(projects)),
+	}
+	for _, project := range projects {
+		subscription.projectIDs[project.ID] = struct{}{}
+	}
+	return subscription
+}
+
+func (s RealtimeSubscription) Allows(event RealtimeEvent) bool {
+	kind := event.Type
+	if index := strings.LastIndex(kind, "."); index >= 0 {
+		kind = kind[:index]
+	}
+
+	switch kind {
+	case "stream":
+		return true
+	case "notification":
+		if s.clientID != "" {
+			return event.RecipientClientID == s.clientID
+		}
+		return s.userID != "" && event.RecipientUserID == s.userID
+	case "service_request", "service_request_comment":
+		if s.clientID != "" {
+			return event.ClientID == s.clientID
+		}
+		return s.userID != ""
+	case "project", "project_task", "project_task_comment":
+		if s.clientID != "" {
+			_, linked := s.projectIDs[event.ProjectID]
+			return linked
+		}
+		return s.canReadProjects
+	default:
+		return false
+	}
+}
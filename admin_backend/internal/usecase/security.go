@@ -78,10 +78,16 @@ type UpdateProfileInput struct {
 }
 
 func (s *SecurityService) ListPermissions(ctx context.Context) ([]Permission, error) {
+	ctx, span := startSpan(ctx, "SecurityService.ListPermissions")
+	defer span.End()
+
 	return s.repo.ListPermissions(ctx)
 }
 
 func (s *SecurityService) CreatePermission(ctx context.Context, input CreatePermissionInput) (Permission, error) {
+	ctx, span := startSpan(ctx, "SecurityService.CreatePermission")
+	defer span.End()
+
 	normalizedInput := CreatePermissionInput{
 		Code:        strings.ToLower(strings.TrimSpace(input.Code)),
 		Name:        strings.TrimSpace(input.Name),
@@ -96,6 +102,9 @@ func (s *SecurityService) CreatePermission(ctx context.Context, input CreatePerm
 }
 
 func (s *SecurityService) GetPermissionByID(ctx context.Context, id string) (Permission, error) {
+	ctx, span := startSpan(ctx, "SecurityService.GetPermissionByID")
+	defer span.End()
+
 	permissionID := strings.TrimSpace(id)
 	if permissionID == "" {
 		return Permission{}, ErrInvalidInput
@@ -105,6 +114,9 @@ func (s *SecurityService) GetPermissionByID(ctx context.Context, id string) (Per
 }
 
 func (s *SecurityService) UpdatePermission(ctx context.Context, input UpdatePermissionInput) (Permission, error) {
+	ctx, span := startSpan(ctx, "SecurityService.UpdatePermission")
+	defer span.End()
+
 	normalizedInput := UpdatePermissionInput{
 		ID:          strings.TrimSpace(input.ID),
 		Code:        strings.ToLower(strings.TrimSpace(input.Code)),
@@ -120,10 +132,16 @@ func (s *SecurityService) UpdatePermission(ctx context.Context, input UpdatePerm
 }
 
 func (s *SecurityService) ListProfiles(ctx context.Context) ([]Profile, error) {
+	ctx, span := startSpan(ctx, "SecurityService.ListProfiles")
+	defer span.End()
+
 	return s.repo.ListProfiles(ctx)
 }
 
 func (s *SecurityService) CreateProfile(ctx context.Context, input CreateProfileInput) (Profile, error) {
+	ctx, span := startSpan(ctx, "SecurityService.CreateProfile")
+	defer span.End()
+
 	normalizedInput := CreateProfileInput{
 		Name:        strings.TrimSpace(input.Name),
 		Description: strings.TrimSpace(input.Description),
@@ -137,6 +155,9 @@ func (s *SecurityService) CreateProfile(ctx context.Context, input CreateProfile
 }
 
 func (s *SecurityService) GetProfileByID(ctx context.Context, id string) (Profile, error) {
+	ctx, span := startSpan(ctx, "SecurityService.GetProfileByID")
+	defer span.End()
+
 	profileID := strings.TrimSpace(id)
 	if profileID == "" {
 		return Profile{}, ErrInvalidInput
@@ -146,6 +167,9 @@ func (s *SecurityService) GetProfileByID(ctx context.Context, id string) (Profil
 }
 
 func (s *SecurityService) UpdateProfile(ctx context.Context, input UpdateProfileInput) (Profile, error) {
+	ctx, span := startSpan(ctx, "SecurityService.UpdateProfile")
+	defer span.End()
+
 	normalizedInput := UpdateProfileInput{
 		ID:          strings.TrimSpace(input.ID),
 		Name:        strings.TrimSpace(input.Name),
@@ -163,6 +187,9 @@ func (s *SecurityService) ListProfilePermissionIDs(
 	ctx context.Context,
 	profileID string,
 ) ([]string, error) {
+	ctx, span := startSpan(ctx, "SecurityService.ListProfilePermissionIDs")
+	defer span.End()
+
 	id := strings.TrimSpace(profileID)
 	if id == "" {
 		return nil, ErrInvalidInput
@@ -176,6 +203,9 @@ func (s *SecurityService) SaveProfilePermissionIDs(
 	profileID string,
 	permissionIDs []string,
 ) ([]string, error) {
+	ctx, span := startSpan(ctx, "SecurityService.SaveProfilePermissionIDs")
+	defer span.End()
+
 	id := strings.TrimSpace(profileID)
 	if id == "" {
 		return nil, ErrInvalidInput
@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	ServiceRequestConversionTask    = "task"
+	ServiceRequestConversionProject = "project"
+)
+
+type ServiceRequestConversionRepository interface {
+	GetAdminServiceRequest(ctx context.Context, requestID string) (AdminServiceRequest, error)
+	LinkServiceRequestTask(ctx context.Context, input LinkServiceRequestTaskInput) (AdminServiceRequest, error)
+}
+
+// ServiceRequestProjectRepository is the part of ProjectRepository used to
+// create the work for a converted request.
+type ServiceRequestProjectRepository interface {
+	CreateProject(ctx context.Context, input CreateProjectInput) (ProjectDetail, error)
+	CreateProjectTask(ctx context.Context, input CreateProjectTaskInput) (ProjectTask, error)
+}
+
+// ConvertServiceRequestInput creates a task in the request project, or a new
+// project for the client with a first task when Target is "project".
+// ProjectName defaults to the request title and ResponsibleUserID to the
+// request assignee.
+type ConvertServiceRequestInput struct {
+	RequestID         string
+	Target            string
+	ProjectPhaseID    string
+	ResponsibleUserID string
+	ProjectName       string
+	ProjectTypeID     string
+	ConvertedByUserID string
+}
+
+// LinkServiceRequestTaskInput links the task to the request, moves the
+// request into the project when it had none and starts work on it.
+type LinkServiceRequestTaskInput struct {
+	RequestID      string
+	TaskID         string
+	ProjectID      string
+	LinkedByUserID string
+}
+
+type ServiceRequestConversion struct {
+	Request AdminServiceRequest `json:"request"`
+	Task    ProjectTask         `json:"task"`
+	Project *ProjectDetail      `json:"project,omitempty"`
+}
+
+type ServiceRequestConversionService struct {
+	requests ServiceRequestConversionRepository
+	projects ServiceRequestProjectRepository
+	notifier Notifier
+	sla      SLATracker
+}
+
+func NewServiceRequestConversionService(
+	requests ServiceRequestConversionRepository,
+	projects ServiceRequestProjectRepository,
+	notifier Notifier,
+	sla SLATracker,
+) *ServiceRequestConversionService {
+	return &ServiceRequestConversionService{
+		requests: requests,
+		projects: projects,
+		notifier: notifier,
+		sla:      sla,
+	}
+}
+
+// ConvertServiceRequest carries the title, description and files of the
+// request over to a new project task. A request is converted at most once;
+// completing the task later completes the request.
+func (s *ServiceRequestConversionService) ConvertServiceRequest(
+	ctx context.Context,
+	input ConvertServiceRequestInput,
+) (ServiceRequestConversion, error) {
+	ctx, span := startSpan(ctx, "ServiceRequestConversionService.ConvertServiceRequest")
+	defer span.End()
+
+	normalizedInput := ConvertServiceRequestInput{
+		RequestID:         strings.TrimSpace(input.RequestID),
+		Target:            strings.ToLower(strings.TrimSpace(input.Target)),
+		ProjectPhaseID:    strings.TrimSpace(input.ProjectPhaseID),
+		ResponsibleUserID: strings.TrimSpace(input.ResponsibleUserID),
+		ProjectName:       strings.TrimSpace(input.ProjectName),
+		ProjectTypeID:     strings.TrimSpace(input.ProjectTypeID),
+		ConvertedByUserID: strings.TrimSpace(input.ConvertedByUserID),
+	}
+	if normalizedInput.RequestID == "" {
+		return ServiceRequestConversion{}, ErrInvalidInput
+	}
+	if normalizedInput.Target == "" {
+		normalizedInput.Target = ServiceRequestConversionTask
+	}
+	if normalizedInput.Target != ServiceRequestConversionTask &&
+		normalizedInput.Target != ServiceRequestConversionProject {
+		return ServiceRequestConversion{}, ErrInvalidInput
+	}
+
+	request, err := s.requests.GetAdminServiceRequest(ctx, normalizedInput.RequestID)
+	if err != nil {
+		return ServiceRequestConversion{}, err
+	}
+	if request.ProjectTaskID != "" {
+		return ServiceRequestConversion{}, ErrServiceRequestAlreadyConverted
+	}
+	if request.StatusClosed {
+		return ServiceRequestConversion{}, ErrConflict
+	}
+
+	conversion := ServiceRequestConversion{}
+	projectID := request.ProjectID
+	switch normalizedInput.Target {
+	case ServiceRequestConversionTask:
+		if projectID == "" {
+			return ServiceRequestConversion{}, ErrServiceRequestWithoutProject
+		}
+	case ServiceRequestConversionProject:
+		projectName := normalizedInput.ProjectName
+		if projectName == "" {
+			projectName = request.Title
+		}
+
+		projectInput, err := normalizeCreateProjectInput(CreateProjectInput{
+			Name:           projectName,
+			Objective:      request.Description,
+			ProjectTypeID:  normalizedInput.ProjectTypeID,
+			Active:         true,
+			ClientIDs:      []string{request.ClientID},
+			ManagerUserIDs: []string{normalizedInput.ConvertedByUserID},
+		})
+		if err != nil {
+			return ServiceRequestConversion{}, err
+		}
+
+		project, err := s.projects.CreateProject(ctx, projectInput)
+		if err != nil {
+			return ServiceRequestConversion{}, err
+		}
+		projectID = project.ID
+		conversion.Project = &project
+		// A new project has no phases yet.
+		normalizedInput.ProjectPhaseID = ""
+	}
+
+	responsibleUserID := normalizedInput.ResponsibleUserID
+	if responsibleUserID == "" {
+		responsibleUserID = request.AssignedUserID
+	}
+
+	taskInput, err := normalizeCreateProjectTaskInput(CreateProjectTaskInput{
+		ProjectID:         projectID,
+		ProjectPhaseID:    normalizedInput.ProjectPhaseID,
+		ResponsibleUserID: responsibleUserID,
+		Name:              request.Title,
+		Description:       request.Description,
+		Active:            true,
+		Files:             mapServiceRequestFilesToProjectFiles(request.Files),
+	})
+	if err != nil {
+		return ServiceRequestConversion{}, err
+	}
+
+	task, err := s.projects.CreateProjectTask(ctx, taskInput)
+	if err != nil {
+		return ServiceRequestConversion{}, err
+	}
+
+	linkedRequest, err := s.requests.LinkServiceRequestTask(ctx, LinkServiceRequestTaskInput{
+		RequestID:      request.ID,
+		TaskID:         task.ID,
+		ProjectID:      projectID,
+		LinkedByUserID: normalizedInput.ConvertedByUserID,
+	})
+	if err != nil {
+		return ServiceRequestConversion{}, err
+	}
+	task.ServiceRequestID = linkedRequest.ID
+
+	if linkedRequest.Status != request.Status && s.notifier != nil {
+		s.notifier.Notify(ctx, NotificationEvent{
+			Type:             NotificationTypeServiceRequestStatus,
+			Title:            "Solicitação " + formatServiceRequestStatusLabel(linkedRequest.Status, linkedRequest.StatusName) + ": " + linkedRequest.Title,
+			ProjectID:        linkedRequest.ProjectID,
+			ServiceRequestID: linkedRequest.ID,
+			ActorUserID:      normalizedInput.ConvertedByUserID,
+			Audience: NotificationAudience{
+				ClientIDs: []string{linkedRequest.ClientID},
+			},
+		})
+	}
+
+	if s.sla != nil {
+		linkedRequest.SLA = s.sla.DescribeServiceRequestSLA(linkedRequest.SLATracking)
+	}
+
+	conversion.Request = linkedRequest
+	conversion.Task = task
+	return conversion, nil
+}
+
+func mapServiceRequestFilesToProjectFiles(files []ClientServiceRequestFile) []CreateProjectFileInput {
+	mapped := make([]CreateProjectFileInput, 0, len(files))
+	for _, file := range files {
+		mapped = append(mapped, CreateProjectFileInput{
+			FileName:    file.FileName,
+			FileKey:     file.FileKey,
+			ContentType: file.ContentType,
+			Notes:       file.Notes,
+		})
+	}
+	return mapped
+}
@@ -0,0 +1,347 @@
+package usecase
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// System statuses are referenced by the application: requests are created
+// "aberta", clients reopen into "reaberta" and cancel into "cancelada".
+const (
+	ServiceRequestStatusOpen           = "aberta"
+	ServiceRequestStatusInProgress     = "em_andamento"
+	ServiceRequestStatusAwaitingClient = "aguardando_cliente"
+	ServiceRequestStatusReopened       = "reaberta"
+	ServiceRequestStatusCompleted      = "concluida"
+	ServiceRequestStatusCancelled      = "cancelada"
+
+	ServiceRequestPriorityLow    = "baixa"
+	ServiceRequestPriorityMedium = "media"
+	ServiceRequestPriorityHigh   = "alta"
+	ServiceRequestPriorityUrgent = "urgente"
+
+	// ServiceRequestUnassigned filters the requests without an assignee.
+	ServiceRequestUnassigned = "none"
+)
+
+var ServiceRequestPriorities = []string{
+	ServiceRequestPriorityLow,
+	ServiceRequestPriorityMedium,
+	ServiceRequestPriorityHigh,
+	ServiceRequestPriorityUrgent,
+}
+
+var serviceRequestStatusCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type ServiceRequestSettingsRepository interface {
+	ListServiceRequestStatuses(ctx context.Context) ([]ServiceRequestStatus, error)
+	GetServiceRequestStatus(ctx context.Context, code string) (ServiceRequestStatus, error)
+	CreateServiceRequestStatus(ctx context.Context, input CreateServiceRequestStatusInput) (ServiceRequestStatus, error)
+	UpdateServiceRequestStatus(ctx context.Context, input UpdateServiceRequestStatusInput) (ServiceRequestStatus, error)
+	DeleteServiceRequestStatus(ctx context.Context, code string) error
+
+	ListServiceRequestCategories(ctx context.Context) ([]ServiceRequestCategory, error)
+	CreateServiceRequestCategory(
+		ctx context.Context,
+		input CreateServiceRequestCategoryInput,
+	) (ServiceRequestCategory, error)
+	UpdateServiceRequestCategory(
+		ctx context.Context,
+		input UpdateServiceRequestCategoryInput,
+	) (ServiceRequestCategory, error)
+	DeleteServiceRequestCategory(ctx context.Context, categoryID string) error
+}
+
+// ServiceRequestStatus is a state of the configurable workflow. Transitions
+// lists the statuses a request in this status can move to.
+type ServiceRequestStatus struct {
+	Code             string    `json:"code"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	Closed           bool      `json:"closed"`
+	ReopenWindowDays *int      `json:"reopenWindowDays,omitempty"`
+	Position         int       `json:"position"`
+	System           bool      `json:"system"`
+	Active           bool      `json:"active"`
+	Transitions      []string  `json:"transitions"`
+	Created          time.Time `json:"created"`
+	Updated          time.Time `json:"updated"`
+}
+
+type CreateServiceRequestStatusInput struct {
+	Code             string
+	Name             string
+	Description      string
+	Closed           bool
+	ReopenWindowDays *int
+	Position         int
+	Active           bool
+	Transitions      []string
+}
+
+// UpdateServiceRequestStatusInput replaces the status settings and its
+// outgoing transitions; the code cannot be changed.
+type UpdateServiceRequestStatusInput struct {
+	Code             string
+	Name             string
+	Description      string
+	Closed           bool
+	ReopenWindowDays *int
+	Position         int
+	Active           *bool
+	Transitions      []string
+}
+
+type ServiceRequestCategory struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Active      bool      `json:"active"`
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+}
+
+type CreateServiceRequestCategoryInput struct {
+	Name        string
+	Description string
+	Active      bool
+}
+
+type UpdateServiceRequestCategoryInput struct {
+	ID          string
+	Name        string
+	Description string
+	Active      *bool
+}
+
+type ServiceRequestSettingsService struct {
+	repo ServiceRequestSettingsRepository
+}
+
+func NewServiceRequestSettingsService(repo ServiceRequestSettingsRepository) *ServiceRequestSettingsService {
+	return &ServiceRequestSettingsService{repo: repo}
+}
+
+func (s *ServiceRequestSettingsService) ListStatuses(ctx context.Context) ([]ServiceRequestStatus, error) {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsService.ListStatuses")
+	defer span.End()
+
+	return s.repo.ListServiceRequestStatuses(ctx)
+}
+
+func (s *ServiceRequestSettingsService) GetStatus(ctx context.Context, code string) (ServiceRequestStatus, error) {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsService.GetStatus")
+	defer span.End()
+
+	normalizedCode := strings.ToLower(strings.TrimSpace(code))
+	if normalizedCode == "" {
+		return ServiceRequestStatus{}, ErrInvalidInput
+	}
+
+	return s.repo.GetServiceRequestStatus(ctx, normalizedCode)
+}
+
+func (s *ServiceRequestSettingsService) CreateStatus(
+	ctx context.Context,
+	input CreateServiceRequestStatusInput,
+) (ServiceRequestStatus, error) {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsService.CreateStatus")
+	defer span.End()
+
+	transitions, err := normalizeServiceRequestTransitions(input.Code, input.Transitions)
+	if err != nil {
+		return ServiceRequestStatus{}, err
+	}
+
+	normalizedInput := CreateServiceRequestStatusInput{
+		Code:             strings.ToLower(strings.TrimSpace(input.Code)),
+		Name:             strings.TrimSpace(input.Name),
+		Description:      strings.TrimSpace(input.Description),
+		Closed:           input.Closed,
+		ReopenWindowDays: input.ReopenWindowDays,
+		Position:         input.Position,
+		Active:           input.Active,
+		Transitions:      transitions,
+	}
+
+	if !serviceRequestStatusCodePattern.MatchString(normalizedInput.Code) ||
+		normalizedInput.Name == "" ||
+		!isValidReopenWindow(normalizedInput.ReopenWindowDays) {
+		return ServiceRequestStatus{}, ErrInvalidInput
+	}
+
+	return s.repo.CreateServiceRequestStatus(ctx, normalizedInput)
+}
+
+// UpdateStatus lets the admins rename and reorder system statuses and change
+// their transitions, but not their closed flag, which drives the SLA and
+// the client actions.
+func (s *ServiceRequestSettingsService) UpdateStatus(
+	ctx context.Context,
+	input UpdateServiceRequestStatusInput,
+) (ServiceRequestStatus, error) {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsService.UpdateStatus")
+	defer span.End()
+
+	transitions, err := normalizeServiceRequestTransitions(input.Code, input.Transitions)
+	if err != nil {
+		return ServiceRequestStatus{}, err
+	}
+
+	normalizedInput := UpdateServiceRequestStatusInput{
+		Code:             strings.ToLower(strings.TrimSpace(input.Code)),
+		Name:             strings.TrimSpace(input.Name),
+		Description:      strings.TrimSpace(input.Description),
+		Closed:           input.Closed,
+		ReopenWindowDays: input.ReopenWindowDays,
+		Position:         input.Position,
+		Active:           input.Active,
+		Transitions:      transitions,
+	}
+
+	if normalizedInput.Code == "" ||
+		normalizedInput.Name == "" ||
+		!isValidReopenWindow(normalizedInput.ReopenWindowDays) {
+		return ServiceRequestStatus{}, ErrInvalidInput
+	}
+
+	current, err := s.repo.GetServiceRequestStatus(ctx, normalizedInput.Code)
+	if err != nil {
+		return ServiceRequestStatus{}, err
+	}
+	if current.System {
+		if normalizedInput.Closed != current.Closed ||
+			(normalizedInput.Active != nil && !*normalizedInput.Active) {
+			return ServiceRequestStatus{}, ErrServiceRequestStatusProtected
+		}
+	}
+
+	return s.repo.UpdateServiceRequestStatus(ctx, normalizedInput)
+}
+
+func (s *ServiceRequestSettingsService) DeleteStatus(ctx context.Context, code string) error {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsService.DeleteStatus")
+	defer span.End()
+
+	normalizedCode := strings.ToLower(strings.TrimSpace(code))
+	if normalizedCode == "" {
+		return ErrInvalidInput
+	}
+
+	current, err := s.repo.GetServiceRequestStatus(ctx, normalizedCode)
+	if err != nil {
+		return err
+	}
+	if current.System {
+		return ErrServiceRequestStatusProtected
+	}
+
+	return s.repo.DeleteServiceRequestStatus(ctx, normalizedCode)
+}
+
+func (s *ServiceRequestSettingsService) ListCategories(ctx context.Context) ([]ServiceRequestCategory, error) {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsService.ListCategories")
+	defer span.End()
+
+	return s.repo.ListServiceRequestCategories(ctx)
+}
+
+func (s *ServiceRequestSettingsService) CreateCategory(
+	ctx context.Context,
+	input CreateServiceRequestCategoryInput,
+) (ServiceRequestCategory, error) {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsService.CreateCategory")
+	defer span.End()
+
+	normalizedInput := CreateServiceRequestCategoryInput{
+		Name:        strings.TrimSpace(input.Name),
+		Description: strings.TrimSpace(input.Description),
+		Active:      input.Active,
+	}
+	if normalizedInput.Name == "" {
+		return ServiceRequestCategory{}, ErrInvalidInput
+	}
+
+	return s.repo.CreateServiceRequestCategory(ctx, normalizedInput)
+}
+
+func (s *ServiceRequestSettingsService) UpdateCategory(
+	ctx context.Context,
+	input UpdateServiceRequestCategoryInput,
+) (ServiceRequestCategory, error) {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsService.UpdateCategory")
+	defer span.End()
+
+	normalizedInput := UpdateServiceRequestCategoryInput{
+		ID:          strings.TrimSpace(input.ID),
+		Name:        strings.TrimSpace(input.Name),
+		Description: strings.TrimSpace(input.Description),
+		Active:      input.Active,
+	}
+	if normalizedInput.ID == "" || normalizedInput.Name == "" {
+		return ServiceRequestCategory{}, ErrInvalidInput
+	}
+
+	return s.repo.UpdateServiceRequestCategory(ctx, normalizedInput)
+}
+
+// DeleteCategory clears the category of the requests that used it.
+func (s *ServiceRequestSettingsService) DeleteCategory(ctx context.Context, categoryID string) error {
+	ctx, span := startSpan(ctx, "ServiceRequestSettingsService.DeleteCategory")
+	defer span.End()
+
+	id := strings.TrimSpace(categoryID)
+	if id == "" {
+		return ErrInvalidInput
+	}
+
+	return s.repo.DeleteServiceRequestCategory(ctx, id)
+}
+
+func normalizeServiceRequestTransitions(code string, values []string) ([]string, error) {
+	normalizedCode := strings.ToLower(strings.TrimSpace(code))
+	seen := map[string]struct{}{}
+	transitions := make([]string, 0, len(values))
+	for _, value := range values {
+		target := strings.ToLower(strings.TrimSpace(value))
+		if target == "" {
+			continue
+		}
+		if target == normalizedCode || !serviceRequestStatusCodePattern.MatchString(target) {
+			return nil, ErrInvalidInput
+		}
+		if _, exists := seen[target]; exists {
+			continue
+		}
+		seen[target] = struct{}{}
+		transitions = append(transitions, target)
+	}
+
+	sort.Strings(transitions)
+	return transitions, nil
+}
+
+func isValidReopenWindow(days *int) bool {
+	return days == nil || *days > 0
+}
+
+func normalizeServiceRequestPriority(value string) string {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	for _, priority := range ServiceRequestPriorities {
+		if normalized == priority {
+			return normalized
+		}
+	}
+	return ""
+}
+
+func trimOptionalString(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	return &trimmed
+}
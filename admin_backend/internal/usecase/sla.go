@@ -0,0 +1,517 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	SLAStatusOnTrack = "no_prazo"
+	SLAStatusAtRisk  = "em_risco"
+	SLAStatusBreach  = "violado"
+	SLAStatusMet     = "cumprido"
+	SLAStatusMetLate = "cumprido_com_atraso"
+
+	SLATargetFirstResponse = "primeira_resposta"
+	SLATargetResolution    = "resolucao"
+
+	ServiceRequestSortSLA = "sla"
+
+	defaultSLAWarningMinutes = 60
+	slaEscalationBatchSize   = 100
+)
+
+type SLARepository interface {
+	ListSLAPolicies(ctx context.Context) ([]SLAPolicy, error)
+	GetSLAPolicy(ctx context.Context, policyID string) (SLAPolicy, error)
+	CreateSLAPolicy(ctx context.Context, input CreateSLAPolicyInput) (SLAPolicy, error)
+	UpdateSLAPolicy(ctx context.Context, input UpdateSLAPolicyInput) (SLAPolicy, error)
+	DeleteSLAPolicy(ctx context.Context, policyID string) error
+
+	// FindServiceRequestSLAPolicy returns the most specific active policy for
+	// the request and when the request was created, or ErrNotFound when no
+	// policy applies.
+	FindServiceRequestSLAPolicy(ctx context.Context, requestID string) (SLAPolicy, time.Time, error)
+	CreateServiceRequestSLA(ctx context.Context, input CreateServiceRequestSLAInput) error
+	// ClaimSLAWarnings and ClaimSLABreaches mark the pending targets whose
+	// warning time or deadline has passed and return them, so every target
+	// is escalated once.
+	ClaimSLAWarnings(ctx context.Context, limit int) ([]SLAEscalation, error)
+	ClaimSLABreaches(ctx context.Context, limit int) ([]SLAEscalation, error)
+}
+
+// SLATracker is implemented by SLAService and used by the service request
+// use cases to start the clock and describe the deadlines.
+type SLATracker interface {
+	TrackServiceRequest(ctx context.Context, requestID string)
+	DescribeServiceRequestSLA(tracking *ServiceRequestSLATracking) *ServiceRequestSLA
+}
+
+// SLAPolicy sets the targets, in business minutes, for the service requests
+// of a client, of the projects of a project type or, with neither, of every
+// request. When several policies match, the client one wins.
+type SLAPolicy struct {
+	ID                   string    `json:"id"`
+	Name                 string    `json:"name"`
+	ClientID             string    `json:"clientId,omitempty"`
+	ClientName           string    `json:"clientName,omitempty"`
+	ProjectTypeID        string    `json:"projectTypeId,omitempty"`
+	ProjectTypeName      string    `json:"projectTypeName,omitempty"`
+	FirstResponseMinutes int       `json:"firstResponseMinutes"`
+	ResolutionMinutes    int       `json:"resolutionMinutes"`
+	WarningMinutes       int       `json:"warningMinutes"`
+	Active               bool      `json:"active"`
+	Created              time.Time `json:"created"`
+	Updated              time.Time `json:"updated"`
+}
+
+type CreateSLAPolicyInput struct {
+	Name                 string
+	ClientID             string
+	ProjectTypeID        string
+	FirstResponseMinutes int
+	ResolutionMinutes    int
+	WarningMinutes       *int
+	Active               *bool
+}
+
+type UpdateSLAPolicyInput struct {
+	ID                   string
+	Name                 string
+	ClientID             string
+	ProjectTypeID        string
+	FirstResponseMinutes int
+	ResolutionMinutes    int
+	WarningMinutes       *int
+	Active               *bool
+}
+
+type CreateServiceRequestSLAInput struct {
+	ServiceRequestID    string
+	PolicyID            string
+	PolicyName          string
+	FirstResponseDueAt  time.Time
+	FirstResponseWarnAt time.Time
+	ResolutionDueAt     time.Time
+	ResolutionWarnAt    time.Time
+}
+
+// ServiceRequestSLATracking holds the stored deadlines of a request; it is
+// filled by the repository and turned into ServiceRequestSLA by the service.
+type ServiceRequestSLATracking struct {
+	PolicyID              string
+	PolicyName            string
+	FirstResponseDueAt    time.Time
+	FirstResponseWarnAt   time.Time
+	FirstRespondedAt      *time.Time
+	FirstResponseBreached bool
+	ResolutionDueAt       time.Time
+	ResolutionWarnAt      time.Time
+	ResolvedAt            *time.Time
+	ResolutionBreached    bool
+}
+
+type ServiceRequestSLA struct {
+	PolicyID      string                  `json:"policyId,omitempty"`
+	PolicyName    string                  `json:"policyName"`
+	FirstResponse ServiceRequestSLATarget `json:"firstResponse"`
+	Resolution    ServiceRequestSLATarget `json:"resolution"`
+}
+
+// ServiceRequestSLATarget describes one deadline. RemainingMinutes counts
+// business minutes and is only set while the target is pending; it is
+// negative once the deadline has passed.
+type ServiceRequestSLATarget struct {
+	Status           string     `json:"status"`
+	DueAt            time.Time  `json:"dueAt"`
+	MetAt            *time.Time `json:"metAt,omitempty"`
+	Breached         bool       `json:"breached"`
+	RemainingMinutes *int       `json:"remainingMinutes,omitempty"`
+}
+
+func (t ServiceRequestSLATarget) pending() bool {
+	return t.MetAt == nil
+}
+
+type SLAEscalation struct {
+	ServiceRequestID string
+	Title            string
+	ProjectID        string
+	ClientName       string
+	AssignedUserID   string
+	Target           string
+	DueAt            time.Time
+}
+
+type SLAEscalationResult struct {
+	Warned   int `json:"warned"`
+	Breached int `json:"breached"`
+}
+
+type SLAService struct {
+	repo     SLARepository
+	notifier Notifier
+	clock    Clock
+	calendar BusinessCalendar
+}
+
+func NewSLAService(
+	repo SLARepository,
+	notifier Notifier,
+	clock Clock,
+	calendar BusinessCalendar,
+) *SLAService {
+	return &SLAService{
+		repo:     repo,
+		notifier: notifier,
+		clock:    clock,
+		calendar: calendar,
+	}
+}
+
+func (s *SLAService) ListPolicies(ctx context.Context) ([]SLAPolicy, error) {
+	ctx, span := startSpan(ctx, "SLAService.ListPolicies")
+	defer span.End()
+
+	return s.repo.ListSLAPolicies(ctx)
+}
+
+func (s *SLAService) GetPolicy(ctx context.Context, policyID string) (SLAPolicy, error) {
+	ctx, span := startSpan(ctx, "SLAService.GetPolicy")
+	defer span.End()
+
+	id := strings.TrimSpace(policyID)
+	if id == "" {
+		return SLAPolicy{}, ErrInvalidInput
+	}
+
+	return s.repo.GetSLAPolicy(ctx, id)
+}
+
+func (s *SLAService) CreatePolicy(ctx context.Context, input CreateSLAPolicyInput) (SLAPolicy, error) {
+	ctx, span := startSpan(ctx, "SLAService.CreatePolicy")
+	defer span.End()
+
+	normalizedInput, err := normalizeCreateSLAPolicyInput(input)
+	if err != nil {
+		return SLAPolicy{}, err
+	}
+
+	return s.repo.CreateSLAPolicy(ctx, normalizedInput)
+}
+
+// UpdatePolicy only affects requests created afterwards: the deadlines of
+// existing requests are fixed when they are created.
+func (s *SLAService) UpdatePolicy(ctx context.Context, input UpdateSLAPolicyInput) (SLAPolicy, error) {
+	ctx, span := startSpan(ctx, "SLAService.UpdatePolicy")
+	defer span.End()
+
+	normalizedInput, err := normalizeUpdateSLAPolicyInput(input)
+	if err != nil {
+		return SLAPolicy{}, err
+	}
+
+	return s.repo.UpdateSLAPolicy(ctx, normalizedInput)
+}
+
+func (s *SLAService) DeletePolicy(ctx context.Context, policyID string) error {
+	ctx, span := startSpan(ctx, "SLAService.DeletePolicy")
+	defer span.End()
+
+	id := strings.TrimSpace(policyID)
+	if id == "" {
+		return ErrInvalidInput
+	}
+
+	return s.repo.DeleteSLAPolicy(ctx, id)
+}
+
+// TrackServiceRequest computes the deadlines of a new request from the policy
+// that applies to it. Like notifications it is best effort: the request has
+// already been stored, so failures are only logged.
+func (s *SLAService) TrackServiceRequest(ctx context.Context, requestID string) {
+	ctx, span := startSpan(ctx, "SLAService.TrackServiceRequest")
+	defer span.End()
+
+	policy, created, err := s.repo.FindServiceRequestSLAPolicy(ctx, requestID)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			slog.ErrorContext(ctx, "sla policy lookup failed", "service_request_id", requestID, "error", err)
+		}
+		return
+	}
+
+	input := CreateServiceRequestSLAInput{
+		ServiceRequestID:    requestID,
+		PolicyID:            policy.ID,
+		PolicyName:          policy.Name,
+		FirstResponseDueAt:  s.calendar.AddMinutes(created, policy.FirstResponseMinutes),
+		FirstResponseWarnAt: s.calendar.AddMinutes(created, warningOffset(policy.FirstResponseMinutes, policy.WarningMinutes)),
+		ResolutionDueAt:     s.calendar.AddMinutes(created, policy.ResolutionMinutes),
+		ResolutionWarnAt:    s.calendar.AddMinutes(created, warningOffset(policy.ResolutionMinutes, policy.WarningMinutes)),
+	}
+	if err := s.repo.CreateServiceRequestSLA(ctx, input); err != nil {
+		slog.ErrorContext(ctx, "sla tracking failed", "service_request_id", requestID, "error", err)
+	}
+}
+
+// DescribeServiceRequestSLA returns nil for requests created while no policy
+// applied to them.
+func (s *SLAService) DescribeServiceRequestSLA(tracking *ServiceRequestSLATracking) *ServiceRequestSLA {
+	if tracking == nil {
+		return nil
+	}
+
+	now := s.clock.Now()
+	return &ServiceRequestSLA{
+		PolicyID:   tracking.PolicyID,
+		PolicyName: tracking.PolicyName,
+		FirstResponse: s.describeTarget(
+			now,
+			tracking.FirstResponseDueAt,
+			tracking.FirstResponseWarnAt,
+			tracking.FirstRespondedAt,
+			tracking.FirstResponseBreached,
+		),
+		Resolution: s.describeTarget(
+			now,
+			tracking.ResolutionDueAt,
+			tracking.ResolutionWarnAt,
+			tracking.ResolvedAt,
+			tracking.ResolutionBreached,
+		),
+	}
+}
+
+func (s *SLAService) describeTarget(
+	now time.Time,
+	dueAt time.Time,
+	warnAt time.Time,
+	metAt *time.Time,
+	breached bool,
+) ServiceRequestSLATarget {
+	target := ServiceRequestSLATarget{
+		DueAt:    dueAt,
+		MetAt:    metAt,
+		Breached: breached,
+	}
+
+	if metAt != nil {
+		target.Status = SLAStatusMet
+		if breached || metAt.After(dueAt) {
+			target.Breached = true
+			target.Status = SLAStatusMetLate
+		}
+		return target
+	}
+
+	remaining := s.calendar.MinutesBetween(now, dueAt)
+	target.RemainingMinutes = &remaining
+	switch {
+	case now.After(dueAt):
+		target.Status = SLAStatusBreach
+		target.Breached = true
+	case !now.Before(warnAt):
+		target.Status = SLAStatusAtRisk
+	default:
+		target.Status = SLAStatusOnTrack
+	}
+
+	return target
+}
+
+// EscalateServiceRequests notifies the project managers, or every user when
+// the request has no project, about deadlines that are about to expire and
+// about the ones that expired since the last run.
+func (s *SLAService) EscalateServiceRequests(ctx context.Context) (SLAEscalationResult, error) {
+	ctx, span := startSpan(ctx, "SLAService.EscalateServiceRequests")
+	defer span.End()
+
+	result := SLAEscalationResult{}
+
+	warnings, err := s.repo.ClaimSLAWarnings(ctx, slaEscalationBatchSize)
+	if err != nil {
+		return result, err
+	}
+	for _, escalation := range warnings {
+		s.notify(ctx, NotificationEvent{
+			Type:  NotificationTypeServiceRequestSLAWarning,
+			Title: "SLA próximo do vencimento: " + escalation.Title,
+			Message: fmt.Sprintf(
+				"O prazo de %s da solicitação de %s vence em %s.",
+				formatSLATargetLabel(escalation.Target),
+				escalation.ClientName,
+				s.formatDeadline(escalation.DueAt),
+			),
+			ProjectID:        escalation.ProjectID,
+			ServiceRequestID: escalation.ServiceRequestID,
+			Audience: NotificationAudience{
+				ProjectManagersOf: escalation.ProjectID,
+				UserIDs:           escalationAssignee(escalation),
+				AllUsersWhenEmpty: true,
+			},
+		})
+	}
+	result.Warned = len(warnings)
+
+	breaches, err := s.repo.ClaimSLABreaches(ctx, slaEscalationBatchSize)
+	if err != nil {
+		return result, err
+	}
+	for _, escalation := range breaches {
+		s.notify(ctx, NotificationEvent{
+			Type:  NotificationTypeServiceRequestSLABreach,
+			Title: "SLA violado: " + escalation.Title,
+			Message: fmt.Sprintf(
+				"O prazo de %s da solicitação de %s venceu em %s.",
+				formatSLATargetLabel(escalation.Target),
+				escalation.ClientName,
+				s.formatDeadline(escalation.DueAt),
+			),
+			ProjectID:        escalation.ProjectID,
+			ServiceRequestID: escalation.ServiceRequestID,
+			Audience: NotificationAudience{
+				ProjectManagersOf: escalation.ProjectID,
+				UserIDs:           escalationAssignee(escalation),
+				AllUsersWhenEmpty: true,
+			},
+		})
+	}
+	result.Breached = len(breaches)
+
+	return result, nil
+}
+
+func (s *SLAService) notify(ctx context.Context, event NotificationEvent) {
+	if s.notifier != nil {
+		s.notifier.Notify(ctx, event)
+	}
+}
+
+func (s *SLAService) formatDeadline(value time.Time) string {
+	return value.In(s.calendar.Location()).Format("02/01/2006 15:04")
+}
+
+// sortServiceRequestsBySLA puts the requests with the nearest pending
+// deadline first, overdue ones included; requests without a pending target
+// keep their order at the end.
+func sortServiceRequestsBySLA(requests []AdminServiceRequest) {
+	sort.SliceStable(requests, func(i, j int) bool {
+		left, leftOK := nextSLADeadline(requests[i].SLA)
+		right, rightOK := nextSLADeadline(requests[j].SLA)
+		if leftOK != rightOK {
+			return leftOK
+		}
+		return leftOK && left.Before(right)
+	})
+}
+
+func nextSLADeadline(sla *ServiceRequestSLA) (time.Time, bool) {
+	if sla == nil {
+		return time.Time{}, false
+	}
+
+	var deadline time.Time
+	found := false
+	for _, target := range []ServiceRequestSLATarget{sla.FirstResponse, sla.Resolution} {
+		if !target.pending() {
+			continue
+		}
+		if !found || target.DueAt.Before(deadline) {
+			deadline = target.DueAt
+			found = true
+		}
+	}
+
+	return deadline, found
+}
+
+// warningOffset is how far into the target the warning is raised.
+func warningOffset(targetMinutes int, warningMinutes int) int {
+	if warningMinutes >= targetMinutes {
+		return 0
+	}
+	return targetMinutes - warningMinutes
+}
+
+func formatSLATargetLabel(target string) string {
+	if target == SLATargetFirstResponse {
+		return "primeira resposta"
+	}
+	return "resolução"
+}
+
+func normalizeCreateSLAPolicyInput(input CreateSLAPolicyInput) (CreateSLAPolicyInput, error) {
+	normalizedInput := CreateSLAPolicyInput{
+		Name:                 strings.TrimSpace(input.Name),
+		ClientID:             strings.TrimSpace(input.ClientID),
+		ProjectTypeID:        strings.TrimSpace(input.ProjectTypeID),
+		FirstResponseMinutes: input.FirstResponseMinutes,
+		ResolutionMinutes:    input.ResolutionMinutes,
+		WarningMinutes:       input.WarningMinutes,
+		Active:               input.Active,
+	}
+	if normalizedInput.WarningMinutes == nil {
+		warningMinutes := defaultSLAWarningMinutes
+		normalizedInput.WarningMinutes = &warningMinutes
+	}
+	if normalizedInput.Active == nil {
+		active := true
+		normalizedInput.Active = &active
+	}
+
+	if normalizedInput.Name == "" || !validSLATargets(
+		normalizedInput.FirstResponseMinutes,
+		normalizedInput.ResolutionMinutes,
+		*normalizedInput.WarningMinutes,
+	) {
+		return CreateSLAPolicyInput{}, ErrInvalidInput
+	}
+
+	return normalizedInput, nil
+}
+
+func normalizeUpdateSLAPolicyInput(input UpdateSLAPolicyInput) (UpdateSLAPolicyInput, error) {
+	normalizedInput := UpdateSLAPolicyInput{
+		ID:                   strings.TrimSpace(input.ID),
+		Name:                 strings.TrimSpace(input.Name),
+		ClientID:             strings.TrimSpace(input.ClientID),
+		ProjectTypeID:        strings.TrimSpace(input.ProjectTypeID),
+		FirstResponseMinutes: input.FirstResponseMinutes,
+		ResolutionMinutes:    input.ResolutionMinutes,
+		WarningMinutes:       input.WarningMinutes,
+		Active:               input.Active,
+	}
+	if normalizedInput.WarningMinutes == nil {
+		warningMinutes := defaultSLAWarningMinutes
+		normalizedInput.WarningMinutes = &warningMinutes
+	}
+
+	if normalizedInput.ID == "" || normalizedInput.Name == "" || !validSLATargets(
+		normalizedInput.FirstResponseMinutes,
+		normalizedInput.ResolutionMinutes,
+		*normalizedInput.WarningMinutes,
+	) {
+		return UpdateSLAPolicyInput{}, ErrInvalidInput
+	}
+
+	return normalizedInput, nil
+}
+
+func validSLATargets(firstResponseMinutes int, resolutionMinutes int, warningMinutes int) bool {
+	return firstResponseMinutes > 0 &&
+		resolutionMinutes >= firstResponseMinutes &&
+		warningMinutes >= 0
+}
+
+func escalationAssignee(escalation SLAEscalation) []string {
+	if escalation.AssignedUserID == "" {
+		return nil
+	}
+	return []string{escalation.AssignedUserID}
+}
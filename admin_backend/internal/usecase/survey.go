@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+const (
+	SurveyKindServiceRequest = "service_request"
+	SurveyKindProject        = "project"
+
+	SurveyStatusPending  = "pendente"
+	SurveyStatusAnswered = "respondida"
+
+	NotificationTypeSurveyRequested = "survey.requested"
+
+	defaultSurveyReportPeriod = 90 * 24 * time.Hour
+)
+
+// Surveys are created by the database when a service request is completed
+// or a project is concluded; the application only lists, answers and
+// reports on them.
+type SurveyRepository interface {
+	ListClientSurveys(ctx context.Context, clientID string, status string) ([]ClientSurvey, error)
+	AnswerClientSurvey(ctx context.Context, input AnswerClientSurveyInput) (ClientSurvey, error)
+	ListSurveyResponses(ctx context.Context, filter SurveyReportFilter) ([]ClientSurvey, error)
+	GetSurveyReport(ctx context.Context, filter SurveyReportFilter) (SurveyReport, error)
+}
+
+type ClientSurvey struct {
+	ID                 string     `json:"id"`
+	Kind               string     `json:"kind"`
+	ClientID           string     `json:"clientId"`
+	ClientName         string     `json:"clientName,omitempty"`
+	ServiceRequestID   string     `json:"serviceRequestId,omitempty"`
+	ServiceRequestName string     `json:"serviceRequestTitle,omitempty"`
+	ProjectID          string     `json:"projectId,omitempty"`
+	ProjectName        string     `json:"projectName,omitempty"`
+	Status             string     `json:"status"`
+	Rating             *int       `json:"rating,omitempty"`
+	NPS                *int       `json:"nps,omitempty"`
+	Comment            string     `json:"comment"`
+	AnsweredAt         *time.Time `json:"answeredAt,omitempty"`
+	Created            time.Time  `json:"created"`
+	Updated            time.Time  `json:"updated"`
+}
+
+type AnswerClientSurveyInput struct {
+	ClientID string
+	SurveyID string
+	Rating   int
+	NPS      *int
+	Comment  string
+}
+
+// SurveyReportFilter selects the answers given between From (inclusive) and
+// To (exclusive), optionally for one project or one project manager.
+type SurveyReportFilter struct {
+	From          time.Time
+	To            time.Time
+	ProjectID     string
+	ManagerUserID string
+}
+
+// SurveyStats aggregates the answers of a group. CSAT is the share of 4 and
+// 5 ratings; NPS is the share of promoters (9-10) minus the share of
+// detractors (0-6), both as percentages. They are nil without answers.
+type SurveyStats struct {
+	Responses     int      `json:"responses"`
+	Pending       int      `json:"pending"`
+	AverageRating *float64 `json:"averageRating"`
+	CSAT          *float64 `json:"csat"`
+	NPSResponses  int      `json:"npsResponses"`
+	Promoters     int      `json:"promoters"`
+	Passives      int      `json:"passives"`
+	Detractors    int      `json:"detractors"`
+	NPS           *float64 `json:"nps"`
+}
+
+type ProjectSurveyStats struct {
+	ProjectID   string `json:"projectId"`
+	ProjectName string `json:"projectName"`
+	SurveyStats
+}
+
+type ManagerSurveyStats struct {
+	UserID   string `json:"userId"`
+	UserName string `json:"userName"`
+	SurveyStats
+}
+
+type SurveyReport struct {
+	From      time.Time              `json:"from"`
+	To        time.Time              `json:"to"`
+	Overall   SurveyStats            `json:"overall"`
+	ByKind    map[string]SurveyStats `json:"byKind"`
+	ByProject []ProjectSurveyStats   `json:"byProject"`
+	ByManager []ManagerSurveyStats   `json:"byManager"`
+}
+
+type SurveyService struct {
+	repo  SurveyRepository
+	clock Clock
+}
+
+func NewSurveyService(repo SurveyRepository, clock Clock) *SurveyService {
+	return &SurveyService{repo: repo, clock: clock}
+}
+
+func (s *SurveyService) ListClientSurveys(
+	ctx context.Context,
+	clientID string,
+	status string,
+) ([]ClientSurvey, error) {
+	ctx, span := startSpan(ctx, "SurveyService.ListClientSurveys")
+	defer span.End()
+
+	normalizedClientID := strings.TrimSpace(clientID)
+	normalizedStatus := strings.ToLower(strings.TrimSpace(status))
+	if normalizedClientID == "" {
+		return nil, ErrInvalidInput
+	}
+	if normalizedStatus != "" &&
+		normalizedStatus != SurveyStatusPending &&
+		normalizedStatus != SurveyStatusAnswered {
+		return nil, ErrInvalidInput
+	}
+
+	return s.repo.ListClientSurveys(ctx, normalizedClientID, normalizedStatus)
+}
+
+// AnswerSurvey records the client's answer once; project surveys also
+// require the NPS score.
+func (s *SurveyService) AnswerSurvey(
+	ctx context.Context,
+	input AnswerClientSurveyInput,
+) (ClientSurvey, error) {
+	ctx, span := startSpan(ctx, "SurveyService.AnswerSurvey")
+	defer span.End()
+
+	normalizedInput := AnswerClientSurveyInput{
+		ClientID: strings.TrimSpace(input.ClientID),
+		SurveyID: strings.TrimSpace(input.SurveyID),
+		Rating:   input.Rating,
+		NPS:      input.NPS,
+		Comment:  strings.TrimSpace(input.Comment),
+	}
+	if normalizedInput.ClientID == "" || normalizedInput.SurveyID == "" {
+		return ClientSurvey{}, ErrInvalidInput
+	}
+	if normalizedInput.Rating < 1 || normalizedInput.Rating > 5 {
+		return ClientSurvey{}, ErrInvalidInput
+	}
+	if normalizedInput.NPS != nil && (*normalizedInput.NPS < 0 || *normalizedInput.NPS > 10) {
+		return ClientSurvey{}, ErrInvalidInput
+	}
+
+	return s.repo.AnswerClientSurvey(ctx, normalizedInput)
+}
+
+func (s *SurveyService) ListResponses(
+	ctx context.Context,
+	filter SurveyReportFilter,
+) ([]ClientSurvey, error) {
+	ctx, span := startSpan(ctx, "SurveyService.ListResponses")
+	defer span.End()
+
+	normalizedFilter, err := s.normalizeReportFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListSurveyResponses(ctx, normalizedFilter)
+}
+
+// Report defaults to the last 90 days.
+func (s *SurveyService) Report(ctx context.Context, filter SurveyReportFilter) (SurveyReport, error) {
+	ctx, span := startSpan(ctx, "SurveyService.Report")
+	defer span.End()
+
+	normalizedFilter, err := s.normalizeReportFilter(filter)
+	if err != nil {
+		return SurveyReport{}, err
+	}
+
+	report, err := s.repo.GetSurveyReport(ctx, normalizedFilter)
+	if err != nil {
+		return SurveyReport{}, err
+	}
+	report.From = normalizedFilter.From
+	report.To = normalizedFilter.To
+
+	return report, nil
+}
+
+func (s *SurveyService) normalizeReportFilter(filter SurveyReportFilter) (SurveyReportFilter, error) {
+	normalizedFilter := SurveyReportFilter{
+		From:          filter.From,
+		To:            filter.To,
+		ProjectID:     strings.TrimSpace(filter.ProjectID),
+		ManagerUserID: strings.TrimSpace(filter.ManagerUserID),
+	}
+	if normalizedFilter.To.IsZero() {
+		normalizedFilter.To = s.clock.Now()
+	}
+	if normalizedFilter.From.IsZero() {
+		normalizedFilter.From = normalizedFilter.To.Add(-defaultSurveyReportPeriod)
+	}
+	if !normalizedFilter.From.Before(normalizedFilter.To) {
+		return SurveyReportFilter{}, ErrInvalidInput
+	}
+
+	return normalizedFilter, nil
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import "strings"
+
+const (
+	TaxIDTypeCPF  = "cpf"
+	TaxIDTypeCNPJ = "cnpj"
+)
+
+// normalizeTaxID strips punctuation from a CPF or CNPJ and returns the
+// digits only when they form a valid document.
+func normalizeTaxID(value string) string {
+	digits := onlyDigits(value)
+	switch len(digits) {
+	case 11:
+		if isValidCPF(digits) {
+			return digits
+		}
+	case 14:
+		if isValidCNPJ(digits) {
+			return digits
+		}
+	}
+
+	return ""
+}
+
+// taxIDType names the kind of a normalized tax id: "cpf", "cnpj" or empty.
+func taxIDType(digits string) string {
+	switch len(digits) {
+	case 11:
+		return TaxIDTypeCPF
+	case 14:
+		return TaxIDTypeCNPJ
+	default:
+		return ""
+	}
+}
+
+func isValidCPF(digits string) bool {
+	if len(digits) != 11 || strings.Count(digits, digits[:1]) == len(digits) {
+		return false
+	}
+
+	for _, size := range []int{9, 10} {
+		sum := 0
+		for i := 0; i < size; i++ {
+			sum += int(digits[i]-'0') * (size + 1 - i)
+		}
+		check := sum * 10 % 11
+		if check == 10 {
+			check = 0
+		}
+		if check != int(digits[size]-'0') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isValidCNPJ(digits string) bool {
+	if len(digits) != 14 || strings.Count(digits, digits[:1]) == len(digits) {
+		return false
+	}
+
+	weights := []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	for _, size := range []int{12, 13} {
+		sum := 0
+		offset := len(weights) - size
+		for i := 0; i < size; i++ {
+			sum += int(digits[i]-'0') * weights[offset+i]
+		}
+		check := sum % 11
+		if check < 2 {
+			check = 0
+		} else {
+			check = 11 - check
+		}
+		if check != int(digits[size]-'0') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func onlyDigits(value string) string {
+	var builder strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
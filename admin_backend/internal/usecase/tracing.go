@@ -0,0 +1,41 @@
+package usecase
+
+import "context"
+
+// Span covers one use case call in a request trace.
+type Span interface {
+	End()
+}
+
+// Tracer opens the spans use cases report. The default records nothing; the
+// application installs the real one with SetTracer at startup.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// NoopSpan is the span returned while tracing is disabled.
+var NoopSpan Span = noopSpan{}
+
+var tracer Tracer = noopTracer{}
+
+// SetTracer must be called before the services handle any request.
+func SetTracer(t Tracer) {
+	if t == nil {
+		t = noopTracer{}
+	}
+	tracer = t
+}
+
+func startSpan(ctx context.Context, name string) (context.Context, Span) {
+	return tracer.Start(ctx, name)
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, _ string) (context.Context, Span) {
+	return ctx, NoopSpan
+}
+
+type noopSpan struct{}
+
+func (noopSpan) End() {}
@@ -4,14 +4,14 @@ import (
 	"context"
 	"strings"
 	"time"
-
-	"admin_backend/internal/domain"
 )
 
 type UserRepository interface {
-	Create(ctx context.Context, user domain.User) error
-	GetByID(ctx context.Context, id string) (domain.User, error)
-	List(ctx context.Context) ([]domain.User, error)
+	List(ctx context.Context, filter UserListFilter) ([]User, error)
+	GetByID(ctx context.Context, userID string) (User, error)
+	Create(ctx context.Context, input CreateUserInput) (User, error)
+	Update(ctx context.Context, input UpdateUserInput) (User, error)
+	Deactivate(ctx context.Context, userID string) (User, error)
 }
 
 type IDGenerator interface {
@@ -22,56 +22,154 @@ type Clock interface {
 	Now() time.Time
 }
 
-// UserService implements user-related use cases.
+// UserService implements the lifecycle of admin users.
 type UserService struct {
-	repo  UserRepository
-	ids   IDGenerator
-	clock Clock
+	repo UserRepository
 }
 
-func NewUserService(repo UserRepository, ids IDGenerator, clock Clock) *UserService {
-	return &UserService{
-		repo:  repo,
-		ids:   ids,
-		clock: clock,
-	}
+func NewUserService(repo UserRepository) *UserService {
+	return &UserService{repo: repo}
 }
 
-type RegisterUserInput struct {
-	Name  string
-	Email string
+type User struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	Login      string    `json:"login"`
+	Phone      string    `json:"phone"`
+	Address    string    `json:"address"`
+	Avatar     string    `json:"avatar"`
+	Active     bool      `json:"active"`
+	ProfileIDs []string  `json:"profileIds"`
+	Created    time.Time `json:"created"`
+	Updated    time.Time `json:"updated"`
 }
 
-func (s *UserService) Register(ctx context.Context, input RegisterUserInput) (domain.User, error) {
-	name := strings.TrimSpace(input.Name)
-	email := strings.TrimSpace(input.Email)
-	if name == "" || email == "" {
-		return domain.User{}, ErrInvalidInput
-	}
+// UserListFilter narrows the user list. Search matches name, e-mail and
+// login; ProfileID keeps only users holding that profile.
+type UserListFilter struct {
+	Search     string
+	OnlyActive bool
+	ProfileID  string
+}
 
-	user := domain.User{
-		ID:        s.ids.NewID(),
-		Name:      name,
-		Email:     email,
-		CreatedAt: s.clock.Now(),
-	}
+type CreateUserInput struct {
+	Name       string
+	Email      string
+	Login      string
+	Password   string
+	Phone      string
+	Address    string
+	Avatar     string
+	Active     bool
+	ProfileIDs []string
+}
 
-	if err := s.repo.Create(ctx, user); err != nil {
-		return domain.User{}, err
-	}
+// UpdateUserInput keeps the current password when Password is empty, and the
+// current status and profiles when Active or ProfileIDs are nil.
+type UpdateUserInput struct {
+	ID         string
+	Name       string
+	Email      string
+	Login      string
+	Password   string
+	Phone      string
+	Address    string
+	Avatar     string
+	Active     *bool
+	ProfileIDs *[]string
+}
 
-	return user, nil
+func (s *UserService) List(ctx context.Context, filter UserListFilter) ([]User, error) {
+	ctx, span := startSpan(ctx, "UserService.List")
+	defer span.End()
+
+	return s.repo.List(ctx, UserListFilter{
+		Search:     strings.TrimSpace(filter.Search),
+		OnlyActive: filter.OnlyActive,
+		ProfileID:  strings.TrimSpace(filter.ProfileID),
+	})
 }
 
-func (s *UserService) Get(ctx context.Context, id string) (domain.User, error) {
-	id = strings.TrimSpace(id)
+func (s *UserService) Get(ctx context.Context, userID string) (User, error) {
+	ctx, span := startSpan(ctx, "UserService.Get")
+	defer span.End()
+
+	id := strings.TrimSpace(userID)
 	if id == "" {
-		return domain.User{}, ErrInvalidInput
+		return User{}, ErrInvalidInput
 	}
 
 	return s.repo.GetByID(ctx, id)
 }
 
-func (s *UserService) List(ctx context.Context) ([]domain.User, error) {
-	return s.repo.List(ctx)
+func (s *UserService) Create(ctx context.Context, input CreateUserInput) (User, error) {
+	ctx, span := startSpan(ctx, "UserService.Create")
+	defer span.End()
+
+	normalizedInput := CreateUserInput{
+		Name:       strings.TrimSpace(input.Name),
+		Email:      strings.TrimSpace(input.Email),
+		Login:      strings.ToLower(strings.TrimSpace(input.Login)),
+		Password:   strings.TrimSpace(input.Password),
+		Phone:      strings.TrimSpace(input.Phone),
+		Address:    strings.TrimSpace(input.Address),
+		Avatar:     strings.TrimSpace(input.Avatar),
+		Active:     input.Active,
+		ProfileIDs: uniqueTrimmedIDs(input.ProfileIDs),
+	}
+	if normalizedInput.Name == "" || normalizedInput.Email == "" || normalizedInput.Login == "" {
+		return User{}, ErrUserRequiredFields
+	}
+	if normalizedInput.Password == "" {
+		return User{}, ErrUserPasswordRequired
+	}
+
+	return s.repo.Create(ctx, normalizedInput)
+}
+
+func (s *UserService) Update(ctx context.Context, input UpdateUserInput) (User, error) {
+	ctx, span := startSpan(ctx, "UserService.Update")
+	defer span.End()
+
+	normalizedInput := UpdateUserInput{
+		ID:       strings.TrimSpace(input.ID),
+		Name:     strings.TrimSpace(input.Name),
+		Email:    strings.TrimSpace(input.Email),
+		Login:    strings.ToLower(strings.TrimSpace(input.Login)),
+		Password: strings.TrimSpace(input.Password),
+		Phone:    strings.TrimSpace(input.Phone),
+		Address:  strings.TrimSpace(input.Address),
+		Avatar:   strings.TrimSpace(input.Avatar),
+		Active:   input.Active,
+	}
+	if input.ProfileIDs != nil {
+		profileIDs := uniqueTrimmedIDs(*input.ProfileIDs)
+		normalizedInput.ProfileIDs = &profileIDs
+	}
+	if normalizedInput.ID == "" {
+		return User{}, ErrInvalidInput
+	}
+	if normalizedInput.Name == "" || normalizedInput.Email == "" || normalizedInput.Login == "" {
+		return User{}, ErrUserRequiredFields
+	}
+
+	return s.repo.Update(ctx, normalizedInput)
+}
+
+// Deactivate disables a user's access. actorUserID is the signed-in admin,
+// who may not lock themselves out.
+func (s *UserService) Deactivate(ctx context.Context, userID, actorUserID string) (User, error) {
+	ctx, span := startSpan(ctx, "UserService.Deactivate")
+	defer span.End()
+
+	id := strings.TrimSpace(userID)
+	if id == "" {
+		return User{}, ErrInvalidInput
+	}
+	if id == strings.TrimSpace(actorUserID) {
+		return User{}, ErrUserSelfDeactivation
+	}
+
+	return s.repo.Deactivate(ctx, id)
 }
@@ -28,6 +28,9 @@ type UserProfile struct {
 }
 
 func (s *UserProfileService) ListProfilesByUserID(ctx context.Context, userID string) ([]UserProfile, error) {
+	ctx, span := startSpan(ctx, "UserProfileService.ListProfilesByUserID")
+	defer span.End()
+
 	id := strings.TrimSpace(userID)
 	if id == "" {
 		return nil, ErrInvalidInput
@@ -37,6 +40,9 @@ func (s *UserProfileService) ListProfilesByUserID(ctx context.Context, userID st
 }
 
 func (s *UserProfileService) GetProfileIDsByUserID(ctx context.Context, userID string) ([]string, error) {
+	ctx, span := startSpan(ctx, "UserProfileService.GetProfileIDsByUserID")
+	defer span.End()
+
 	id := strings.TrimSpace(userID)
 	if id == "" {
 		return nil, ErrInvalidInput
@@ -58,6 +64,9 @@ func (s *UserProfileService) SaveProfileIDsByUserID(
 	userID string,
 	profileIDs []string,
 ) ([]string, error) {
+	ctx, span := startSpan(ctx, "UserProfileService.SaveProfileIDsByUserID")
+	defer span.End()
+
 	id := strings.TrimSpace(userID)
 	if id == "" {
 		return nil, ErrInvalidInput
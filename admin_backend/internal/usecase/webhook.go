@@ -0,0 +1,495 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	WebhookEventServiceRequestCreated        = "service_request.created"
+	WebhookEventServiceRequestStatusChanged  = "service_request.status_changed"
+	WebhookEventServiceRequestCommentCreated = "service_request.comment_created"
+	WebhookEventProjectCreated               = "project.created"
+	WebhookEventProjectStatusChanged         = "project.status_changed"
+	WebhookEventMonthlyChargePaid            = "monthly_charge.paid"
+	WebhookEventRevenueReceived              = "revenue.received"
+	// WebhookEventAll subscribes to every event type, including the ones
+	// added after the subscription was created.
+	WebhookEventAll = "*"
+
+	WebhookDeliveryStatusPending   = "pendente"
+	WebhookDeliveryStatusDelivered = "entregue"
+	WebhookDeliveryStatusFailed    = "falhou"
+
+	webhookMaxAttempts          = 8
+	webhookBaseRetryDelay       = 30 * time.Second
+	webhookMaxRetryDelay        = 6 * time.Hour
+	webhookDeliveryLease        = 2 * time.Minute
+	webhookDispatchBatchSize    = 50
+	defaultWebhookDeliveryLimit = 50
+	maxWebhookDeliveryLimit     = 200
+	webhookSecretPrefix         = "whsec_"
+)
+
+// WebhookEventTypes lists the event types emitted by the database triggers.
+var WebhookEventTypes = []string{
+	WebhookEventServiceRequestCreated,
+	WebhookEventServiceRequestStatusChanged,
+	WebhookEventServiceRequestCommentCreated,
+	WebhookEventProjectCreated,
+	WebhookEventProjectStatusChanged,
+	WebhookEventMonthlyChargePaid,
+	WebhookEventRevenueReceived,
+}
+
+type WebhookRepository interface {
+	ListSubscriptions(ctx context.Context) ([]WebhookSubscription, error)
+	GetSubscription(ctx context.Context, subscriptionID string) (WebhookSubscription, error)
+	CreateSubscription(ctx context.Context, input CreateWebhookSubscriptionInput) (WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, input UpdateWebhookSubscriptionInput) (WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID string) error
+	ListDeliveries(ctx context.Context, filter WebhookDeliveryFilter) ([]WebhookDelivery, error)
+	GetDelivery(ctx context.Context, deliveryID string) (WebhookDeliveryDetail, error)
+	RedeliverDelivery(ctx context.Context, deliveryID string) (WebhookDelivery, error)
+	// FanOutWebhookEvents creates one delivery per matching active
+	// subscription for the outbox events not yet dispatched.
+	FanOutWebhookEvents(ctx context.Context, limit int) (int, error)
+	// ClaimDueWebhookDeliveries leases pending deliveries whose next attempt
+	// is due, so concurrent dispatchers never send the same delivery twice.
+	ClaimDueWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]WebhookDispatch, error)
+	RecordWebhookAttempt(ctx context.Context, input RecordWebhookAttemptInput) error
+}
+
+// WebhookSender posts one signed delivery to the subscriber.
+type WebhookSender interface {
+	Send(ctx context.Context, dispatch WebhookDispatch) WebhookSendResult
+}
+
+type WebhookSubscription struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret,omitempty"`
+	EventTypes []string  `json:"eventTypes"`
+	Active     bool      `json:"active"`
+	CreatedBy  string    `json:"createdBy,omitempty"`
+	Created    time.Time `json:"created"`
+	Updated    time.Time `json:"updated"`
+}
+
+type CreateWebhookSubscriptionInput struct {
+	Name       string
+	URL        string
+	Secret     string
+	EventTypes []string
+	Active     bool
+	CreatedBy  string
+}
+
+// UpdateWebhookSubscriptionInput keeps the current secret when Secret is
+// empty.
+type UpdateWebhookSubscriptionInput struct {
+	ID         string
+	Name       string
+	URL        string
+	Secret     string
+	EventTypes []string
+	Active     *bool
+}
+
+type WebhookDelivery struct {
+	ID             string     `json:"id"`
+	EventID        string     `json:"eventId"`
+	EventType      string     `json:"eventType"`
+	SubscriptionID string     `json:"subscriptionId"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt,omitempty"`
+	LastStatusCode *int       `json:"lastStatusCode,omitempty"`
+	LastError      string     `json:"lastError,omitempty"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty"`
+	Created        time.Time  `json:"created"`
+	Updated        time.Time  `json:"updated"`
+}
+
+type WebhookDeliveryAttempt struct {
+	ID           string    `json:"id"`
+	StatusCode   *int      `json:"statusCode,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	ResponseBody string    `json:"responseBody,omitempty"`
+	DurationMS   int       `json:"durationMs"`
+	AttemptedAt  time.Time `json:"attemptedAt"`
+}
+
+type WebhookDeliveryDetail struct {
+	WebhookDelivery
+	Payload  json.RawMessage          `json:"payload"`
+	Attempts []WebhookDeliveryAttempt `json:"attemptLog"`
+}
+
+type WebhookDeliveryFilter struct {
+	SubscriptionID string
+	Status         string
+	Limit          int
+}
+
+// WebhookDispatch is a claimed delivery with everything the sender needs.
+type WebhookDispatch struct {
+	DeliveryID     string
+	EventID        string
+	EventType      string
+	EventCreated   time.Time
+	Payload        []byte
+	SubscriptionID string
+	URL            string
+	Secret         string
+	Attempt        int
+}
+
+type WebhookSendResult struct {
+	StatusCode   int
+	ResponseBody string
+	Duration     time.Duration
+	Err          error
+}
+
+// Delivered reports whether the subscriber acknowledged the delivery with a
+// 2xx status.
+func (r WebhookSendResult) Delivered() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+type RecordWebhookAttemptInput struct {
+	DeliveryID    string
+	Status        string
+	Attempts      int
+	StatusCode    int
+	Error         string
+	ResponseBody  string
+	Duration      time.Duration
+	NextAttemptAt time.Time
+}
+
+type WebhookDispatchResult struct {
+	Enqueued  int `json:"enqueued"`
+	Delivered int `json:"delivered"`
+	Retrying  int `json:"retrying"`
+	Failed    int `json:"failed"`
+}
+
+type WebhookService struct {
+	repo   WebhookRepository
+	sender WebhookSender
+	ids    IDGenerator
+	clock  Clock
+}
+
+func NewWebhookService(
+	repo WebhookRepository,
+	sender WebhookSender,
+	ids IDGenerator,
+	clock Clock,
+) *WebhookService {
+	return &WebhookService{
+		repo:   repo,
+		sender: sender,
+		ids:    ids,
+		clock:  clock,
+	}
+}
+
+// ListSubscriptions omits the secrets; they are only returned when a single
+// subscription is read, created or updated.
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "WebhookService.ListSubscriptions")
+	defer span.End()
+
+	subscriptions, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for index := range subscriptions {
+		subscriptions[index].Secret = ""
+	}
+
+	return subscriptions, nil
+}
+
+func (s *WebhookService) GetSubscription(ctx context.Context, subscriptionID string) (WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "WebhookService.GetSubscription")
+	defer span.End()
+
+	id := strings.TrimSpace(subscriptionID)
+	if id == "" {
+		return WebhookSubscription{}, ErrInvalidInput
+	}
+
+	return s.repo.GetSubscription(ctx, id)
+}
+
+func (s *WebhookService) CreateSubscription(
+	ctx context.Context,
+	input CreateWebhookSubscriptionInput,
+) (WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "WebhookService.CreateSubscription")
+	defer span.End()
+
+	normalizedInput, err := normalizeCreateWebhookSubscriptionInput(input)
+	if err != nil {
+		return WebhookSubscription{}, err
+	}
+
+	if normalizedInput.Secret == "" {
+		normalizedInput.Secret = webhookSecretPrefix + s.ids.NewID()
+	}
+
+	return s.repo.CreateSubscription(ctx, normalizedInput)
+}
+
+func (s *WebhookService) UpdateSubscription(
+	ctx context.Context,
+	input UpdateWebhookSubscriptionInput,
+) (WebhookSubscription, error) {
+	ctx, span := startSpan(ctx, "WebhookService.UpdateSubscription")
+	defer span.End()
+
+	normalizedInput, err := normalizeUpdateWebhookSubscriptionInput(input)
+	if err != nil {
+		return WebhookSubscription{}, err
+	}
+
+	return s.repo.UpdateSubscription(ctx, normalizedInput)
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	ctx, span := startSpan(ctx, "WebhookService.DeleteSubscription")
+	defer span.End()
+
+	id := strings.TrimSpace(subscriptionID)
+	if id == "" {
+		return ErrInvalidInput
+	}
+
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+func (s *WebhookService) ListDeliveries(
+	ctx context.Context,
+	filter WebhookDeliveryFilter,
+) ([]WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "WebhookService.ListDeliveries")
+	defer span.End()
+
+	normalizedFilter := WebhookDeliveryFilter{
+		SubscriptionID: strings.TrimSpace(filter.SubscriptionID),
+		Status:         strings.ToLower(strings.TrimSpace(filter.Status)),
+		Limit:          filter.Limit,
+	}
+	if normalizedFilter.SubscriptionID == "" {
+		return nil, ErrInvalidInput
+	}
+	switch normalizedFilter.Status {
+	case "", WebhookDeliveryStatusPending, WebhookDeliveryStatusDelivered, WebhookDeliveryStatusFailed:
+	default:
+		return nil, ErrInvalidInput
+	}
+	if normalizedFilter.Limit <= 0 {
+		normalizedFilter.Limit = defaultWebhookDeliveryLimit
+	}
+	if normalizedFilter.Limit > maxWebhookDeliveryLimit {
+		normalizedFilter.Limit = maxWebhookDeliveryLimit
+	}
+
+	return s.repo.ListDeliveries(ctx, normalizedFilter)
+}
+
+func (s *WebhookService) GetDelivery(ctx context.Context, deliveryID string) (WebhookDeliveryDetail, error) {
+	ctx, span := startSpan(ctx, "WebhookService.GetDelivery")
+	defer span.End()
+
+	id := strings.TrimSpace(deliveryID)
+	if id == "" {
+		return WebhookDeliveryDetail{}, ErrInvalidInput
+	}
+
+	return s.repo.GetDelivery(ctx, id)
+}
+
+// Redeliver schedules the delivery to be sent again on the next dispatch,
+// whatever its current status, with a fresh attempt budget.
+func (s *WebhookService) Redeliver(ctx context.Context, deliveryID string) (WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "WebhookService.Redeliver")
+	defer span.End()
+
+	id := strings.TrimSpace(deliveryID)
+	if id == "" {
+		return WebhookDelivery{}, ErrInvalidInput
+	}
+
+	return s.repo.RedeliverDelivery(ctx, id)
+}
+
+// DispatchWebhooks moves new outbox events into per-subscription deliveries
+// and sends the deliveries that are due. Failed attempts are retried with an
+// exponential backoff until webhookMaxAttempts is reached.
+func (s *WebhookService) DispatchWebhooks(ctx context.Context) (WebhookDispatchResult, error) {
+	ctx, span := startSpan(ctx, "WebhookService.DispatchWebhooks")
+	defer span.End()
+
+	result := WebhookDispatchResult{}
+
+	enqueued, err := s.repo.FanOutWebhookEvents(ctx, webhookDispatchBatchSize)
+	if err != nil {
+		return result, err
+	}
+	result.Enqueued = enqueued
+
+	dispatches, err := s.repo.ClaimDueWebhookDeliveries(ctx, webhookDispatchBatchSize, webhookDeliveryLease)
+	if err != nil {
+		return result, err
+	}
+
+	for _, dispatch := range dispatches {
+		sendResult := s.sender.Send(ctx, dispatch)
+
+		attempt := RecordWebhookAttemptInput{
+			DeliveryID:   dispatch.DeliveryID,
+			Attempts:     dispatch.Attempt,
+			StatusCode:   sendResult.StatusCode,
+			ResponseBody: sendResult.ResponseBody,
+			Duration:     sendResult.Duration,
+		}
+		switch {
+		case sendResult.Delivered():
+			attempt.Status = WebhookDeliveryStatusDelivered
+			result.Delivered++
+		case dispatch.Attempt >= webhookMaxAttempts:
+			attempt.Status = WebhookDeliveryStatusFailed
+			result.Failed++
+		default:
+			attempt.Status = WebhookDeliveryStatusPending
+			attempt.NextAttemptAt = s.clock.Now().Add(webhookRetryDelay(dispatch.Attempt))
+			result.Retrying++
+		}
+		if sendResult.Err != nil {
+			attempt.Error = sendResult.Err.Error()
+		} else if !sendResult.Delivered() {
+			attempt.Error = fmt.Sprintf("unexpected status %d", sendResult.StatusCode)
+		}
+
+		if err := s.repo.RecordWebhookAttempt(ctx, attempt); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// webhookRetryDelay doubles the delay after every failed attempt: 30s, 1m,
+// 2m, 4m... capped at webhookMaxRetryDelay.
+func webhookRetryDelay(attempt int) time.Duration {
+	delay := webhookBaseRetryDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= webhookMaxRetryDelay {
+			return webhookMaxRetryDelay
+		}
+	}
+	return delay
+}
+
+func normalizeCreateWebhookSubscriptionInput(
+	input CreateWebhookSubscriptionInput,
+) (CreateWebhookSubscriptionInput, error) {
+	eventTypes, err := normalizeWebhookEventTypes(input.EventTypes)
+	if err != nil {
+		return CreateWebhookSubscriptionInput{}, err
+	}
+
+	normalizedInput := CreateWebhookSubscriptionInput{
+		Name:       strings.TrimSpace(input.Name),
+		URL:        strings.TrimSpace(input.URL),
+		Secret:     strings.TrimSpace(input.Secret),
+		EventTypes: eventTypes,
+		Active:     input.Active,
+		CreatedBy:  strings.TrimSpace(input.CreatedBy),
+	}
+
+	if normalizedInput.Name == "" || !isValidWebhookURL(normalizedInput.URL) {
+		return CreateWebhookSubscriptionInput{}, ErrInvalidInput
+	}
+
+	return normalizedInput, nil
+}
+
+func normalizeUpdateWebhookSubscriptionInput(
+	input UpdateWebhookSubscriptionInput,
+) (UpdateWebhookSubscriptionInput, error) {
+	eventTypes, err := normalizeWebhookEventTypes(input.EventTypes)
+	if err != nil {
+		return UpdateWebhookSubscriptionInput{}, err
+	}
+
+	normalizedInput := UpdateWebhookSubscriptionInput{
+		ID:         strings.TrimSpace(input.ID),
+		Name:       strings.TrimSpace(input.Name),
+		URL:        strings.TrimSpace(input.URL),
+		Secret:     strings.TrimSpace(input.Secret),
+		EventTypes: eventTypes,
+		Active:     input.Active,
+	}
+
+	if normalizedInput.ID == "" ||
+		normalizedInput.Name == "" ||
+		!isValidWebhookURL(normalizedInput.URL) {
+		return UpdateWebhookSubscriptionInput{}, ErrInvalidInput
+	}
+
+	return normalizedInput, nil
+}
+
+func normalizeWebhookEventTypes(values []string) ([]string, error) {
+	known := make(map[string]struct{}, len(WebhookEventTypes)+1)
+	known[WebhookEventAll] = struct{}{}
+	for _, eventType := range WebhookEventTypes {
+		known[eventType] = struct{}{}
+	}
+
+	seen := map[string]struct{}{}
+	eventTypes := make([]string, 0, len(values))
+	for _, value := range values {
+		eventType := strings.ToLower(strings.TrimSpace(value))
+		if eventType == "" {
+			continue
+		}
+		if _, ok := known[eventType]; !ok {
+			return nil, ErrInvalidInput
+		}
+		if _, exists := seen[eventType]; exists {
+			continue
+		}
+		seen[eventType] = struct{}{}
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	if len(eventTypes) == 0 {
+		return nil, ErrInvalidInput
+	}
+
+	sort.Strings(eventTypes)
+	return eventTypes, nil
+}
+
+func isValidWebhookURL(value string) bool {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"time"
+)
+
+// ZipCodeSourceDataset marks zip codes imported from a local dataset; they
+// never expire and are only replaced by a later import.
+const ZipCodeSourceDataset = "dataset"
+
+// ZipCodeRecord is a zip code kept in the local directory, either as the
+// answer of an online provider or as a row of an imported dataset.
+type ZipCodeRecord struct {
+	Result ZipCodeLookupResult
+	// NotFound remembers that the providers did not know the zip code.
+	NotFound bool
+	// Source names the provider that answered, or ZipCodeSourceDataset.
+	Source    string
+	FetchedAt time.Time
+	// ExpiresAt is nil for dataset rows.
+	ExpiresAt *time.Time
+}
+
+// Expired reports whether the record should be refreshed from a provider.
+func (r ZipCodeRecord) Expired(now time.Time) bool {
+	return r.ExpiresAt != nil && !r.ExpiresAt.After(now)
+}
+
+// ZipCodeDirectoryRepository stores the zip codes answered by the providers
+// and those imported for offline use.
+type ZipCodeDirectoryRepository interface {
+	// GetZipCode returns ErrNotFound when the zip code was never stored,
+	// and expired records otherwise.
+	GetZipCode(ctx context.Context, zipCode string) (ZipCodeRecord, error)
+	SaveZipCode(ctx context.Context, record ZipCodeRecord) error
+	// ImportZipCodes stores dataset rows, replacing any cached answer.
+	ImportZipCodes(ctx context.Context, records []ZipCodeRecord) error
+}
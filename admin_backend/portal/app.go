@@ -0,0 +1,197 @@
+package portal
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"admin_backend/internal/infra/auth"
+	"admin_backend/internal/infra/clock"
+	"admin_backend/internal/infra/config"
+	"admin_backend/internal/infra/db"
+	"admin_backend/internal/infra/health"
+	"admin_backend/internal/infra/id"
+	"admin_backend/internal/infra/localstack"
+	"admin_backend/internal/infra/mailer"
+	"admin_backend/internal/infra/metrics"
+	infraratelimit "admin_backend/internal/infra/ratelimit"
+	"admin_backend/internal/infra/realtime"
+	"admin_backend/internal/infra/repository/postgres"
+	"admin_backend/internal/infra/tracing"
+	apphttp "admin_backend/internal/interfaces/http"
+	"admin_backend/internal/interfaces/http/probes"
+	"admin_backend/internal/interfaces/http/ratelimit"
+	"admin_backend/internal/interfaces/http/requestlog"
+	"admin_backend/internal/usecase"
+	"github.com/jmoiron/sqlx"
+)
+
+type app struct {
+	handler    http.Handler
+	db         *sqlx.DB
+	realtime   *realtime.Hub
+	tracer     *tracing.Tracer
+	health     *health.Checker
+	rateLimits infraratelimit.Store
+}
+
+// newApp wires the portal services against the schema owned and migrated by
+// admin_backend; this service never runs migrations at boot. ctx only bounds
+// startup, e.g. the database connection retries.
+func newApp(ctx context.Context, cfg config.Config, logger *slog.Logger) (*app, error) {
+	schedulerConfig := cfg.Scheduler
+	reminderOffsets, err := usecase.ParsePaymentReminderOffsets(schedulerConfig.PaymentReminderOffsets)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PAYMENT_REMINDER_OFFSETS: %w", err)
+	}
+	businessCalendar, err := usecase.ParseBusinessCalendar(
+		schedulerConfig.SLABusinessHours,
+		schedulerConfig.SLABusinessDays,
+		schedulerConfig.SLATimezone,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SLA_BUSINESS_HOURS/SLA_BUSINESS_DAYS/SLA_TIMEZONE: %w", err)
+	}
+
+	emailSender, err := mailer.New(cfg.Mail)
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := db.Connect(ctx, cfg.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	localstackClient, err := localstack.New(cfg.Localstack)
+	if err != nil {
+		_ = database.Close()
+		return nil, err
+	}
+
+	realtimeHub, err := realtime.NewHub(cfg.DB.DSN())
+	if err != nil {
+		_ = database.Close()
+		return nil, err
+	}
+
+	tracer := tracing.New(cfg.Tracing, serviceName)
+	tracing.SetDefault(tracer)
+	usecase.SetTracer(tracing.UsecaseTracer{})
+	metrics.RegisterDBStats(metrics.Default, database.DB)
+
+	ids := id.New()
+	clockProvider := clock.New()
+	tokenManager := auth.NewTokenManager(cfg.Auth).WithAudience(auth.AudienceClient)
+
+	notificationService := usecase.NewNotificationService(postgres.NewNotificationRepository(database))
+	slaService := usecase.NewSLAService(
+		postgres.NewSLARepository(database),
+		notificationService,
+		clockProvider,
+		businessCalendar,
+	)
+	clientPortalService := usecase.NewClientPortalService(
+		postgres.NewClientPortalRepository(database),
+		notificationService,
+		slaService,
+	)
+	projectService := usecase.NewProjectService(postgres.NewProjectRepository(database), notificationService)
+	reminderService := usecase.NewPaymentReminderService(
+		postgres.NewPaymentReminderRepository(database),
+		emailSender,
+		clockProvider,
+		reminderOffsets,
+	)
+	surveyService := usecase.NewSurveyService(postgres.NewSurveyRepository(database), clockProvider)
+	clientOrganizationService := usecase.NewClientOrganizationService(
+		postgres.NewClientOrganizationRepository(database),
+		emailSender,
+		ids,
+		clockProvider,
+		usecase.ClientOrganizationConfig{PortalURL: cfg.Mail.PortalURL},
+	)
+
+	healthChecker := health.NewChecker(
+		health.Database(database),
+		health.Migrations(database, db.Migrations),
+		health.Check{Name: "objectStorage", Run: localstackClient.Ping},
+	)
+
+	rateLimitStore, err := infraratelimit.New(cfg.RateLimit, database)
+	if err != nil {
+		_ = realtimeHub.Close()
+		_ = database.Close()
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	apphttp.NewClientPortalHandler(
+		clientPortalService,
+		projectService,
+		reminderService,
+		notificationService,
+		surveyService,
+		clientOrganizationService,
+		realtimeHub,
+		tokenManager,
+	).RegisterRoutes(mux)
+	probes.NewHandler(healthChecker).RegisterRoutes(mux)
+	mux.Handle("/metrics", metrics.Default.Handler())
+
+	// Limits sit inside CORS so browsers can read the 429 responses.
+	var routes http.Handler = mux
+	if cfg.RateLimit.Enabled {
+		routes = ratelimit.New(
+			rateLimitStore,
+			tokenManager,
+			cfg.RateLimit.TrustedProxies,
+			ratelimit.Policy{
+				Name:    "client-login",
+				Paths:   []string{"/client-auth/login"},
+				Methods: []string{http.MethodPost},
+				Key:     ratelimit.ByIP,
+				Limit:   cfg.RateLimit.Login,
+			},
+			ratelimit.Policy{
+				Name:    "client-register",
+				Paths:   []string{"/client-auth/register", "/client-auth/invites/accept"},
+				Methods: []string{http.MethodPost},
+				Key:     ratelimit.ByIP,
+				Limit:   cfg.RateLimit.Register,
+			},
+			ratelimit.Policy{
+				Name:    "portal-writes",
+				Paths:   []string{"/client/"},
+				Methods: []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+				Key:     ratelimit.ByClient,
+				Limit:   cfg.RateLimit.PortalWrites,
+			},
+		).Middleware(mux)
+	}
+
+	return &app{
+		handler:    requestlog.Middleware(logger, mux, apphttp.WithCORS(cfg.CORSOrigins, routes)),
+		db:         database,
+		realtime:   realtimeHub,
+		tracer:     tracer,
+		health:     healthChecker,
+		rateLimits: rateLimitStore,
+	}, nil
+}
+
+// Drain marks the portal not ready and ends the open event streams.
+func (a *app) Drain() {
+	a.health.SetDraining()
+	_ = a.realtime.Close()
+}
+
+// Shutdown stops the realtime listener, flushes queued trace spans and
+// releases the rate limit store and the database once requests have drained.
+func (a *app) Shutdown(ctx context.Context) error {
+	_ = a.realtime.Close()
+	_ = a.tracer.Close(ctx)
+	_ = a.rateLimits.Close()
+	return a.db.Close()
+}
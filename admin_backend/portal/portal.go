@@ -0,0 +1,58 @@
+// Package portal runs the client portal API as the client_backend service on
+// top of the shared Postgres schema. It lives outside internal/ so
+// client_backend can import it; admin_backend keeps owning the schema, its
+// migrations and every infrastructure package, none of which are exported.
+package portal
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"admin_backend/internal/dbmigrate"
+	"admin_backend/internal/infra/config"
+	"admin_backend/internal/infra/httpserver"
+	"admin_backend/internal/infra/logging"
+)
+
+const serviceName = "client_backend"
+
+// Service is the client_backend process.
+type Service struct {
+	cfg    config.Config
+	logger *slog.Logger
+}
+
+// New loads the client_backend configuration and makes its JSON logger the
+// process default, so a configuration error is logged like any other.
+func New() (*Service, error) {
+	cfg, err := config.Load(serviceName)
+	logger := logging.New(cfg.Log, serviceName)
+	slog.SetDefault(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{cfg: cfg, logger: logger}, nil
+}
+
+// Migrate runs the migrate subcommand; args are the words after "migrate".
+func (s *Service) Migrate(ctx context.Context, args []string, stdout io.Writer) error {
+	return dbmigrate.Run(ctx, serviceName, s.cfg.DB, args, stdout)
+}
+
+// Run serves the portal on PORT until ctx is done, then shuts down
+// gracefully exactly as admin_backend does.
+func (s *Service) Run(ctx context.Context) error {
+	s.cfg.LogSummary(ctx, s.logger)
+
+	application, err := newApp(ctx, s.cfg, s.logger)
+	if err != nil {
+		return err
+	}
+
+	server := httpserver.New(":"+s.cfg.Port, application.handler, s.cfg.Server, s.logger)
+
+	s.logger.Info("client_backend listening", "port", s.cfg.Port)
+	return httpserver.Run(ctx, s.logger, server, s.cfg.Server, application)
+}
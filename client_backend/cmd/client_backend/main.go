@@ -1,36 +1,37 @@
 package main
 
 import (
-	"log"
-	"net/http"
+	"context"
+	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 
-	"client_backend/internal/app"
+	"admin_backend/portal"
 )
 
 func main() {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-
-	application, err := app.New()
+	service, err := portal.New()
 	if err != nil {
-		log.Fatalf("startup error: %v", err)
+		slog.Error("startup error", "error", err)
+		os.Exit(1)
 	}
-	defer func() {
-		if err := application.Close(); err != nil {
-			log.Printf("shutdown error: %v", err)
-		}
-	}()
 
-	server := &http.Server{
-		Addr:    ":" + port,
-		Handler: application.Handler,
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := service.Migrate(ctx, os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "migrate:", err)
+			stop()
+			os.Exit(1)
+		}
+		return
 	}
 
-	log.Printf("client_backend listening on :%s", port)
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("server error: %v", err)
+	if err := service.Run(ctx); err != nil {
+		slog.Error("server error", "error", err)
+		os.Exit(1)
 	}
 }
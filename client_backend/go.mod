@@ -2,7 +2,12 @@ module client_backend
 
 go 1.21
 
+require admin_backend v0.0.0-00010101000000-000000000000
+
 require (
-	github.com/jmoiron/sqlx v1.4.0
-	github.com/lib/pq v1.10.9
+	github.com/jmoiron/sqlx v1.4.0 // indirect
+	github.com/lib/pq v1.10.9 // indirect
 )
+
+// The portal API is shared code published by admin_backend/portal.
+replace admin_backend => ../admin_backend
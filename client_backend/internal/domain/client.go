@@ -1,10 +0,0 @@
-package domain
-
-import "time"
-
-type Client struct {
-	ID        string
-	Name      string
-	Email     string
-	CreatedAt time.Time
-}
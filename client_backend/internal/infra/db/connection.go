@@ -1,34 +0,0 @@
-package db
-
-import (
-	"context"
-	"time"
-
-	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
-)
-
-func Connect(ctx context.Context, cfg Config) (*sqlx.DB, error) {
-	db, err := sqlx.Open("postgres", cfg.DSN())
-	if err != nil {
-		return nil, err
-	}
-
-	var lastErr error
-	for attempt := 0; attempt < 10; attempt++ {
-		pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
-		lastErr = db.PingContext(pingCtx)
-		cancel()
-		if lastErr == nil {
-			return db, nil
-		}
-		time.Sleep(1 * time.Second)
-	}
-
-	if lastErr != nil {
-		_ = db.Close()
-		return nil, lastErr
-	}
-
-	return db, nil
-}
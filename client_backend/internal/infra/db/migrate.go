@@ -1,108 +0,0 @@
-package db
-
-import (
-	"context"
-	"fmt"
-	"io/fs"
-	"sort"
-	"strings"
-
-	"github.com/jmoiron/sqlx"
-)
-
-func Migrate(ctx context.Context, db *sqlx.DB, migrations fs.FS) error {
-	if _, err := db.ExecContext(ctx, `
-		CREATE EXTENSION IF NOT EXISTS pgcrypto;
-		CREATE TABLE IF NOT EXISTS schema_migrations (
-			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-			version TEXT NOT NULL UNIQUE,
-			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
-		)
-	`); err != nil {
-		return fmt.Errorf("create schema_migrations: %w", err)
-	}
-
-	applied, err := loadApplied(ctx, db)
-	if err != nil {
-		return err
-	}
-
-	entries, err := fs.ReadDir(migrations, "migrations")
-	if err != nil {
-		return fmt.Errorf("read migrations dir: %w", err)
-	}
-
-	files := make([]string, 0, len(entries))
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		name := entry.Name()
-		if !strings.HasSuffix(name, ".sql") {
-			continue
-		}
-		files = append(files, name)
-	}
-
-	sort.Strings(files)
-
-	for _, name := range files {
-		if applied[name] {
-			continue
-		}
-		contents, err := fs.ReadFile(migrations, "migrations/"+name)
-		if err != nil {
-			return fmt.Errorf("read migration %s: %w", name, err)
-		}
-		if err := runMigration(ctx, db, name, string(contents)); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func loadApplied(ctx context.Context, db *sqlx.DB) (map[string]bool, error) {
-	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
-	if err != nil {
-		return nil, fmt.Errorf("list schema_migrations: %w", err)
-	}
-	defer rows.Close()
-
-	applied := make(map[string]bool)
-	for rows.Next() {
-		var version string
-		if err := rows.Scan(&version); err != nil {
-			return nil, fmt.Errorf("scan schema_migrations: %w", err)
-		}
-		applied[version] = true
-	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate schema_migrations: %w", err)
-	}
-
-	return applied, nil
-}
-
-func runMigration(ctx context.Context, db *sqlx.DB, name, sqlText string) error {
-	tx, err := db.BeginTxx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("start migration %s: %w", name, err)
-	}
-
-	if _, err := tx.ExecContext(ctx, sqlText); err != nil {
-		_ = tx.Rollback()
-		return fmt.Errorf("apply migration %s: %w", name, err)
-	}
-
-	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", name); err != nil {
-		_ = tx.Rollback()
-		return fmt.Errorf("record migration %s: %w", name, err)
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit migration %s: %w", name, err)
-	}
-
-	return nil
-}
@@ -1,6 +0,0 @@
-package db
-
-import "embed"
-
-//go:embed migrations/*.sql
-var Migrations embed.FS
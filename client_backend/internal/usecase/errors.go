@@ -1,8 +0,0 @@
-package usecase
-
-import "errors"
-
-var (
-	ErrNotFound     = errors.New("not found")
-	ErrInvalidInput = errors.New("invalid input")
-)
@@ -1,2 +1,2 @@
-export const adminBackendUrl =
-  process.env.NEXT_PUBLIC_ADMIN_BACKEND_URL || "http://localhost:8080";
+export const clientBackendUrl =
+  process.env.NEXT_PUBLIC_CLIENT_BACKEND_URL || "http://localhost:8081";
@@ -1,4 +1,4 @@
-import { adminBackendUrl } from "@/config/api";
+import { clientBackendUrl } from "@/config/api";
 import { readClientTokenFromCookie } from "@/lib/client-auth";
 
 export class ClientApiError extends Error {
@@ -35,7 +35,7 @@ export async function fetchClientApi<TResponse>(
     headers.set("Authorization", `Bearer ${token}`);
   }
 
-  const response = await fetch(`${adminBackendUrl}${path}`, {
+  const response = await fetch(`${clientBackendUrl}${path}`, {
     ...init,
     headers,
   });